@@ -4,6 +4,19 @@ import (
 	"github.com/bluenviron/gortsplib/v4/pkg/ringbuffer"
 )
 
+// asyncTask is an entry of the queue of asyncProcessor that can be used
+// in place of a closure, in order to avoid allocations.
+type asyncTask interface {
+	run() error
+}
+
+func runAsyncEntry(entry interface{}) error {
+	if task, ok := entry.(asyncTask); ok {
+		return task.run()
+	}
+	return entry.(func() error)()
+}
+
 // this is an asynchronous queue processor
 // that allows to detach the routine that is reading a stream
 // from the routine that is writing a stream.
@@ -46,7 +59,7 @@ func (w *asyncProcessor) runInner() error {
 			return nil
 		}
 
-		err := tmp.(func() error)()
+		err := runAsyncEntry(tmp)
 		if err != nil {
 			return err
 		}
@@ -56,3 +69,9 @@ func (w *asyncProcessor) runInner() error {
 func (w *asyncProcessor) push(cb func() error) bool {
 	return w.buffer.Push(cb)
 }
+
+// pushMultiple pushes multiple entries atomically.
+// Each entry is either a func() error or an asyncTask.
+func (w *asyncProcessor) pushMultiple(entries []interface{}) bool {
+	return w.buffer.PushMultiple(entries)
+}
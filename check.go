@@ -0,0 +1,341 @@
+package gortsplib
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/internal/happyeyeballs"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
+)
+
+// CheckStep is a step of Check.
+type CheckStep string
+
+// steps.
+const (
+	// resolution of the host name.
+	CheckStepDNS CheckStep = "dns"
+	// TCP connection.
+	CheckStepTCP CheckStep = "tcp"
+	// TLS handshake, in case of RTSPS URLs.
+	CheckStepTLS CheckStep = "tls"
+	// authentication.
+	CheckStepAuth CheckStep = "auth"
+	// DESCRIBE request.
+	CheckStepDescribe CheckStep = "describe"
+	// SETUP and PLAY requests with a transport.
+	CheckStepSetup CheckStep = "setup"
+	// reception of the first RTP packet with a transport.
+	CheckStepFirstPacket CheckStep = "first_packet"
+)
+
+// CheckFinding is the result of a step of Check.
+type CheckFinding struct {
+	// step.
+	Step CheckStep
+	// transport, filled in case of CheckStepSetup and CheckStepFirstPacket.
+	Transport *Transport
+	// whether the step succeeded.
+	OK bool
+	// time spent by the step.
+	// In case of CheckStepFirstPacket, it's the time elapsed between
+	// the PLAY response and the first RTP packet.
+	Duration time.Duration
+	// error that caused the step to fail.
+	Error string
+}
+
+// CheckReport is the report produced by Check.
+type CheckReport struct {
+	// IPs of the host.
+	Addresses []net.IP
+	// whether the server requested authentication.
+	AuthRequired bool
+	// codecs of the stream, in the order of the stream description.
+	Codecs []string
+	// results of steps, in order of execution.
+	// Steps that follow a failed step are not executed,
+	// except for steps of different transports.
+	Findings []CheckFinding
+}
+
+// OK returns whether the stream can be read with at least one transport.
+func (r *CheckReport) OK() bool {
+	for _, f := range r.Findings {
+		if f.Step == CheckStepFirstPacket && f.OK {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CheckReport) add(step CheckStep, transport *Transport, duration time.Duration, err error) bool {
+	f := CheckFinding{
+		Step:      step,
+		Transport: transport,
+		OK:        err == nil,
+		Duration:  duration,
+	}
+	if err != nil {
+		f.Error = err.Error()
+	}
+
+	r.Findings = append(r.Findings, f)
+	return err == nil
+}
+
+// CheckOptions are the options of Check.
+type CheckOptions struct {
+	// transports that are tested.
+	// It defaults to UDP, UDP-multicast and TCP.
+	Transports []Transport
+	// a TLS configuration, used with RTSPS URLs.
+	TLSConfig *tls.Config
+	// timeout of each step.
+	// It defaults to 10 seconds.
+	Timeout time.Duration
+	// function used to resolve host names.
+	// It defaults to net.DefaultResolver.LookupIPAddr.
+	LookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+func (o *CheckOptions) fillDefaults() {
+	if o.Transports == nil {
+		o.Transports = []Transport{TransportUDP, TransportUDPMulticast, TransportTCP}
+	}
+	if o.Timeout == 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.LookupIPAddr == nil {
+		o.LookupIPAddr = net.DefaultResolver.LookupIPAddr
+	}
+}
+
+func (o *CheckOptions) newClient(transport *Transport) *Client {
+	return &Client{
+		TransportOptions: ClientTransportOptions{
+			Transport: transport,
+		},
+		TimeoutOptions: TimeoutOptions{
+			Read:  o.Timeout,
+			Write: o.Timeout,
+		},
+		SecurityOptions: SecurityOptions{
+			TLSConfig: o.TLSConfig,
+		},
+		LookupIPAddr: o.LookupIPAddr,
+	}
+}
+
+// Check runs a short diagnostic session against a RTSP URL
+// and returns findings about each step (DNS, TCP, TLS, authentication, DESCRIBE,
+// SETUP and first packet of each transport).
+// It is meant to be used by monitoring systems.
+// Failures of steps are reported inside the report,
+// while the error is returned only when the URL is invalid or ctx is canceled.
+func Check(ctx context.Context, url string, opts CheckOptions) (*CheckReport, error) {
+	u, err := base.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.fillDefaults()
+
+	r := &CheckReport{}
+
+	if !checkConn(ctx, r, u, &opts) {
+		return r, ctx.Err()
+	}
+
+	desc, ok := checkDescribe(ctx, r, u, &opts)
+	if !ok {
+		return r, ctx.Err()
+	}
+
+	for _, medi := range desc.Medias {
+		for _, forma := range medi.Formats {
+			r.Codecs = append(r.Codecs, forma.Codec())
+		}
+	}
+
+	for _, transport := range opts.Transports {
+		checkTransport(ctx, r, u, &opts, transport)
+
+		if ctx.Err() != nil {
+			return r, ctx.Err()
+		}
+	}
+
+	return r, nil
+}
+
+func checkConn(ctx context.Context, r *CheckReport, u *base.URL, opts *CheckOptions) bool {
+	stepCtx, stepCtxCancel := context.WithTimeout(ctx, opts.Timeout)
+	defer stepCtxCancel()
+
+	host := u.Hostname()
+	start := time.Now()
+
+	if ip := net.ParseIP(host); ip != nil {
+		r.Addresses = []net.IP{ip}
+	} else {
+		addrs, err := opts.LookupIPAddr(stepCtx, host)
+		if !r.add(CheckStepDNS, nil, time.Since(start), err) {
+			return false
+		}
+
+		for _, addr := range addrs {
+			r.Addresses = append(r.Addresses, addr.IP)
+		}
+	}
+
+	d := &happyeyeballs.Dialer{
+		LookupIPAddr: opts.LookupIPAddr,
+		DialContext:  (&net.Dialer{}).DialContext,
+		AttemptDelay: happyeyeballs.DefaultAttemptDelay,
+	}
+
+	start = time.Now()
+	nconn, err := d.Dial(stepCtx, "tcp", canonicalAddr(u))
+	if !r.add(CheckStepTCP, nil, time.Since(start), err) {
+		return false
+	}
+	defer nconn.Close()
+
+	if u.Scheme == "rtsps" {
+		tlsConfig := &tls.Config{}
+		if opts.TLSConfig != nil {
+			tlsConfig = opts.TLSConfig.Clone()
+		}
+		tlsConfig.ServerName = host
+
+		start = time.Now()
+		err = tls.Client(nconn, tlsConfig).HandshakeContext(stepCtx)
+		if !r.add(CheckStepTLS, nil, time.Since(start), err) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func checkDescribe(
+	ctx context.Context,
+	r *CheckReport,
+	u *base.URL,
+	opts *CheckOptions,
+) (*description.Session, bool) {
+	c := opts.newClient(nil)
+	c.OnResponse = func(res *base.Response) {
+		if res.StatusCode == base.StatusUnauthorized {
+			r.AuthRequired = true
+		}
+	}
+
+	err := c.Start(u.Scheme, u.Host)
+	if err != nil {
+		r.add(CheckStepDescribe, nil, 0, err)
+		return nil, false
+	}
+	defer c.Close()
+
+	stop := context.AfterFunc(ctx, c.Close)
+	defer stop()
+
+	start := time.Now()
+	desc, _, err := c.Describe(u)
+	duration := time.Since(start)
+
+	var authErr error
+	var errStatus liberrors.ErrClientBadStatusCode
+	if errors.As(err, &errStatus) && errStatus.Code == base.StatusUnauthorized {
+		authErr = err
+	}
+
+	if !r.add(CheckStepAuth, nil, duration, authErr) {
+		return nil, false
+	}
+
+	if !r.add(CheckStepDescribe, nil, duration, err) {
+		return nil, false
+	}
+
+	return desc, true
+}
+
+func checkTransport(
+	ctx context.Context,
+	r *CheckReport,
+	u *base.URL,
+	opts *CheckOptions,
+	transport Transport,
+) {
+	c := opts.newClient(&transport)
+
+	err := c.Start(u.Scheme, u.Host)
+	if err != nil {
+		r.add(CheckStepSetup, &transport, 0, err)
+		return
+	}
+	defer c.Close()
+
+	stop := context.AfterFunc(ctx, c.Close)
+	defer stop()
+
+	start := time.Now()
+
+	err = func() error {
+		desc, _, err2 := c.Describe(u)
+		if err2 != nil {
+			return err2
+		}
+
+		return c.SetupAll(desc.BaseURL, desc.Medias)
+	}()
+	if err != nil {
+		r.add(CheckStepSetup, &transport, time.Since(start), err)
+		return
+	}
+
+	firstPacket := make(chan struct{})
+	var firstPacketOnce sync.Once
+
+	c.OnPacketRTPAny(func(_ *description.Media, _ format.Format, _ *rtp.Packet) {
+		firstPacketOnce.Do(func() {
+			close(firstPacket)
+		})
+	})
+
+	_, err = c.Play(nil)
+	if !r.add(CheckStepSetup, &transport, time.Since(start), err) {
+		return
+	}
+
+	start = time.Now()
+
+	select {
+	case <-firstPacket:
+		r.add(CheckStepFirstPacket, &transport, time.Since(start), nil)
+
+	case <-time.After(opts.Timeout):
+		var timeoutErr error
+		if transport == TransportTCP {
+			timeoutErr = liberrors.ErrClientTCPTimeout{}
+		} else {
+			timeoutErr = liberrors.ErrClientUDPTimeout{}
+		}
+		r.add(CheckStepFirstPacket, &transport, time.Since(start), timeoutErr)
+
+	case <-c.done:
+		r.add(CheckStepFirstPacket, &transport, time.Since(start), c.closeError)
+	}
+}
@@ -9,8 +9,9 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
-	"log"
+	"io"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -20,6 +21,7 @@ import (
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 
+	"github.com/bluenviron/gortsplib/v4/internal/happyeyeballs"
 	"github.com/bluenviron/gortsplib/v4/internal/rtcpreceiver"
 	"github.com/bluenviron/gortsplib/v4/internal/rtcpsender"
 	"github.com/bluenviron/gortsplib/v4/pkg/auth"
@@ -68,16 +70,18 @@ func isAnyPort(p int) bool {
 
 func findBaseURL(sd *sdp.SessionDescription, res *base.Response, u *base.URL) (*base.URL, error) {
 	// use global control attribute
-	if control, ok := sd.Attribute("control"); ok && control != "*" {
-		ret, err := base.ParseURL(control)
-		if err != nil {
-			return nil, fmt.Errorf("invalid control attribute: '%v'", control)
-		}
+	if sd != nil {
+		if control, ok := sd.Attribute("control"); ok && control != "*" {
+			ret, err := base.ParseURL(control)
+			if err != nil {
+				return nil, fmt.Errorf("invalid control attribute: '%v'", control)
+			}
 
-		// add credentials
-		ret.User = u.User
+			// add credentials
+			ret.User = u.User
 
-		return ret, nil
+			return ret, nil
+		}
 	}
 
 	// use Content-Base
@@ -120,20 +124,6 @@ func prepareForAnnounce(desc *description.Session) {
 	}
 }
 
-func supportsGetParameter(header base.Header) bool {
-	pub, ok := header["Public"]
-	if !ok || len(pub) != 1 {
-		return false
-	}
-
-	for _, m := range strings.Split(pub[0], ",") {
-		if base.Method(strings.Trim(m, " ")) == base.GetParameter {
-			return true
-		}
-	}
-	return false
-}
-
 type clientState int
 
 const (
@@ -165,6 +155,11 @@ type optionsReq struct {
 	res chan clientRes
 }
 
+type capabilitiesReq struct {
+	url *base.URL
+	res chan clientRes
+}
+
 type describeReq struct {
 	url *base.URL
 	res chan clientRes
@@ -184,6 +179,12 @@ type setupReq struct {
 	res      chan clientRes
 }
 
+type setupAllReq struct {
+	baseURL *base.URL
+	medias  []*description.Media
+	res     chan clientRes
+}
+
 type playReq struct {
 	ra  *headers.Range
 	res chan clientRes
@@ -197,15 +198,28 @@ type pauseReq struct {
 	res chan clientRes
 }
 
+// ClientRequestBody is the body of a request sent by the client.
+type ClientRequestBody struct {
+	// value of the Content-Type header.
+	ContentType string
+	// body content.
+	Content []byte
+}
+
 type clientRes struct {
-	sd  *description.Session // describe only
-	res *base.Response
-	err error
+	sd     *description.Session // describe only
+	caps   *ClientCapabilities  // capabilities only
+	params map[string]string    // get parameters only
+	res    *base.Response
+	err    error
 }
 
 // ClientOnRequestFunc is the prototype of Client.OnRequest.
 type ClientOnRequestFunc func(*base.Request)
 
+// ClientOnUnauthorizedFunc is the prototype of Client.OnUnauthorized.
+type ClientOnUnauthorizedFunc func(*base.Response) bool
+
 // ClientOnResponseFunc is the prototype of Client.OnResponse.
 type ClientOnResponseFunc func(*base.Response)
 
@@ -218,12 +232,36 @@ type ClientOnPacketLostFunc func(err error)
 // ClientOnDecodeErrorFunc is the prototype of Client.OnDecodeError.
 type ClientOnDecodeErrorFunc func(err error)
 
+// ClientOnMulticastSilenceFunc is the prototype of Client.OnMulticastSilence.
+type ClientOnMulticastSilenceFunc func(err error)
+
+// ClientOnRedirectFunc is the prototype of Client.OnRedirect.
+type ClientOnRedirectFunc func(u *base.URL)
+
+// ClientOnEndOfStreamFunc is the prototype of Client.OnEndOfStream.
+type ClientOnEndOfStreamFunc func(err error)
+
+// ClientOnDescribeProgressFunc is the prototype of Client.OnDescribeProgress.
+type ClientOnDescribeProgressFunc func(elapsed time.Duration)
+
+// ClientOnStatsFunc is the prototype of Client.OnStats.
+type ClientOnStatsFunc func(*ClientStats)
+
+// ClientOnViolationFunc is the prototype of Client.OnViolation.
+type ClientOnViolationFunc func(*Violation)
+
+// ClientOnDescriptionWarningFunc is the prototype of Client.OnDescriptionWarning.
+type ClientOnDescriptionWarningFunc func(description.Warning)
+
 // OnPacketRTPFunc is the prototype of the callback passed to OnPacketRTP().
 type OnPacketRTPFunc func(*rtp.Packet)
 
 // OnPacketRTPAnyFunc is the prototype of the callback passed to OnPacketRTP(Any).
 type OnPacketRTPAnyFunc func(*description.Media, format.Format, *rtp.Packet)
 
+// OnFrameFunc is the prototype of the callback passed to OnFrame().
+type OnFrameFunc func(*Frame)
+
 // OnPacketRTCPFunc is the prototype of the callback passed to OnPacketRTCP().
 type OnPacketRTCPFunc func(rtcp.Packet)
 
@@ -235,28 +273,52 @@ type Client struct {
 	//
 	// RTSP parameters (all optional)
 	//
+	// transport settings.
+	TransportOptions ClientTransportOptions
+	// timeout settings.
+	TimeoutOptions TimeoutOptions
+	// security settings.
+	SecurityOptions SecurityOptions
+	// socket settings.
+	SocketOptions SocketOptions
+	// strict mode settings.
+	StrictOptions StrictOptions
+	// redirect settings.
+	RedirectOptions ClientRedirectOptions
+	// DESCRIBE settings.
+	DescribeOptions ClientDescribeOptions
+	// settings about reading streams.
+	PlayOptions ClientPlayOptions
+	// settings about publishing streams.
+	RecordOptions ClientRecordOptions
+	// media activity settings.
+	MediaActivityOptions MediaActivityOptions
+	// logging, metrics and statistics settings.
+	ObservabilityOptions ObservabilityOptions
+	// memory management settings.
+	MemoryOptions MemoryOptions
 	// timeout of read operations.
-	// It defaults to 10 seconds.
+	// Deprecated: use TimeoutOptions.Read.
 	ReadTimeout time.Duration
 	// timeout of write operations.
-	// It defaults to 10 seconds.
+	// Deprecated: use TimeoutOptions.Write.
 	WriteTimeout time.Duration
 	// a TLS configuration to connect to TLS (RTSPS) servers.
-	// It defaults to nil.
+	// Deprecated: use SecurityOptions.TLSConfig.
 	TLSConfig *tls.Config
 	// enable communication with servers which don't provide UDP server ports
 	// or use different server ports than the announced ones.
-	// This can be a security issue.
-	// It defaults to false.
+	// Deprecated: use TransportOptions.AnyPortEnable.
 	AnyPortEnable bool
 	// transport protocol (UDP, Multicast or TCP).
-	// If nil, it is chosen automatically (first UDP, then, if it fails, TCP).
-	// It defaults to nil.
+	// Deprecated: use TransportOptions.Transport.
 	Transport *Transport
 	// If the client is reading with UDP, it must receive
 	// at least a packet within this timeout, otherwise it switches to TCP.
-	// It defaults to 3 seconds.
+	// Deprecated: use TransportOptions.InitialUDPReadTimeout.
 	InitialUDPReadTimeout time.Duration
+	// the following settings predate option groups and are not deprecated,
+	// therefore they are kept here in order not to break existing code.
 	// Size of the queue of outgoing packets.
 	// It defaults to 256.
 	WriteQueueSize int
@@ -282,29 +344,68 @@ type Client struct {
 	// system functions (all optional)
 	//
 	// function used to initialize the TCP client.
-	// It defaults to (&net.Dialer{}).DialContext.
+	// It defaults to a function that, when the host resolves to multiple addresses,
+	// connects to them in a staggered fashion and uses the first connection that succeeds (RFC 8305).
 	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
 	// function used to initialize UDP listeners.
 	// It defaults to net.ListenPacket.
 	ListenPacket func(network, address string) (net.PacketConn, error)
+	// function used to resolve host names of servers and of sources of media.
+	// It allows to use a private DNS, mDNS or a static map.
+	// It is used by the default DialContext.
+	// It defaults to net.DefaultResolver.LookupIPAddr.
+	LookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
 
 	//
 	// callbacks (all optional)
 	//
-	// called when sending a request to the server.
+	// called before sending a request to the server, before credentials are added.
+	// It can be used to edit the request, for instance to change the User-Agent
+	// or to insert fresh tokens into the URL or headers.
+	OnPrepareRequest ClientOnRequestFunc
+	// called when sending a request to the server, after credentials are added.
+	// It is called with every outgoing request, including keepalives,
+	// and can be used to log requests or to edit them, for instance to add vendor headers.
 	OnRequest ClientOnRequestFunc
-	// called when receiving a response from the server.
+	// called when receiving a response from the server, after parsing and before processing.
+	// It is called with every incoming response, including the ones to keepalives,
+	// and can be used to log responses or to edit them, for instance to fix headers of buggy devices.
 	OnResponse ClientOnResponseFunc
 	// called when receiving a request from the server.
 	OnServerRequest ClientOnRequestFunc
 	// called when sending a response to the server.
 	OnServerResponse ClientOnResponseFunc
 	// called when the transport protocol changes.
+	// TransportOptions.OnTransportSwitch provides the previous and the new transport too.
 	OnTransportSwitch ClientOnTransportSwitchFunc
 	// called when the client detects lost packets.
 	OnPacketLost ClientOnPacketLostFunc
 	// called when a non-fatal decode error occurs.
 	OnDecodeError ClientOnDecodeErrorFunc
+	// called when multicast groups are left because the stream went silent.
+	OnMulticastSilence ClientOnMulticastSilenceFunc
+	// called when the server sends a REDIRECT request.
+	OnRedirect ClientOnRedirectFunc
+	// called when the stream ends, because a RTCP BYE has been received on all medias,
+	// or because packets stopped after the end time of the Range header of the PLAY response.
+	// The client is not closed, and it doesn't return timeout errors afterwards.
+	OnEndOfStream ClientOnEndOfStreamFunc
+	// called periodically while waiting for the response to a DESCRIBE request,
+	// every DescribeOptions.KeepalivePeriod.
+	OnDescribeProgress ClientOnDescribeProgressFunc
+	// called when the server replies with 401 Unauthorized and
+	// available credentials can't be used, for instance because they expired.
+	// If it returns true, the request is sent again, after passing it to OnPrepareRequest
+	// and reading credentials from its URL.
+	OnUnauthorized ClientOnUnauthorizedFunc
+	// called periodically, every ObservabilityOptions.StatsPeriod, with client statistics,
+	// in order to monitor rates without polling Stats().
+	// It defaults to nil (disabled).
+	OnStats ClientOnStatsFunc
+	// called when StrictOptions.Enabled is true and the server violates RFC 2326.
+	OnViolation ClientOnViolationFunc
+	// called when DescribeOptions.Lenient is true and the stream description contains a problem.
+	OnDescriptionWarning ClientOnDescriptionWarningFunc
 
 	//
 	// private
@@ -323,10 +424,16 @@ type Client struct {
 	conn                 *conn.Conn
 	session              string
 	sender               *auth.Sender
+	refreshingAuth       bool
 	cseq                 int
+	strictServerCSeq     strictCSeqChecker
 	optionsSent          bool
 	useGetParameter      bool
+	capabilities         *ClientCapabilities
 	lastDescribeURL      *base.URL
+	lastDescribeDesc     *description.Session
+	pendingSetups        []*clientSetup
+	lastAnnounceDesc     *description.Session
 	baseURL              *base.URL
 	effectiveTransport   *Transport
 	backChannelSetupped  bool
@@ -334,11 +441,18 @@ type Client struct {
 	setuppedMedias       map[*description.Media]*clientMedia
 	tcpCallbackByChannel map[int]readFunc
 	lastRange            *headers.Range
+	playSpeed            ClientPlaySpeed
+	playStartTime        time.Time
+	playDuration         *time.Duration
+	endOfStreamReached   bool
+	packetDump           atomic.Pointer[packetDump]
 	checkTimeoutTimer    *time.Timer
 	checkTimeoutInitial  bool
 	tcpLastFrameTime     *int64
 	keepalivePeriod      time.Duration
 	keepaliveTimer       *time.Timer
+	clockSyncTimer       *time.Timer
+	statsTimer           *time.Timer
 	closeError           error
 	writer               *asyncProcessor
 	writerMutex          sync.RWMutex
@@ -351,13 +465,18 @@ type Client struct {
 	bytesSent            *uint64
 
 	// in
-	chOptions  chan optionsReq
-	chDescribe chan describeReq
-	chAnnounce chan announceReq
-	chSetup    chan setupReq
-	chPlay     chan playReq
-	chRecord   chan recordReq
-	chPause    chan pauseReq
+	chOptions       chan optionsReq
+	chCapabilities  chan capabilitiesReq
+	chDescribe      chan describeReq
+	chAnnounce      chan announceReq
+	chSetup         chan setupReq
+	chSetupAll      chan setupAllReq
+	chPlay          chan playReq
+	chRecord        chan recordReq
+	chPause         chan pauseReq
+	chGetParameters chan getParametersReq
+	chSetParameters chan setParametersReq
+	chEndOfStream   chan struct{}
 
 	// out
 	done chan struct{}
@@ -365,39 +484,101 @@ type Client struct {
 
 // Start initializes the connection to a server.
 func (c *Client) Start(scheme string, host string) error {
+	err := c.Validate()
+	if err != nil {
+		return err
+	}
+
+	c.TransportOptions, c.TimeoutOptions, c.SecurityOptions = c.mergeDeprecatedFields()
+
 	// RTSP parameters
-	if c.ReadTimeout == 0 {
-		c.ReadTimeout = 10 * time.Second
+	if c.TimeoutOptions.Read == 0 {
+		c.TimeoutOptions.Read = 10 * time.Second
+	}
+	if c.TimeoutOptions.Write == 0 {
+		c.TimeoutOptions.Write = 10 * time.Second
+	}
+	if c.TransportOptions.InitialUDPReadTimeout == 0 {
+		c.TransportOptions.InitialUDPReadTimeout = 3 * time.Second
+	}
+	if c.TransportOptions.FallbackProbePackets == 0 {
+		c.TransportOptions.FallbackProbePackets = 1
+	}
+	if c.RedirectOptions.MaxRedirects == 0 {
+		c.RedirectOptions.MaxRedirects = 10
+	}
+	if c.DescribeOptions.Timeout == 0 {
+		c.DescribeOptions.Timeout = c.TimeoutOptions.Read
+	}
+	if c.MediaActivityOptions.MediaActivity == nil {
+		c.MediaActivityOptions.MediaActivity = defaultMediaActivity
 	}
-	if c.WriteTimeout == 0 {
-		c.WriteTimeout = 10 * time.Second
+	if c.PlayOptions.MulticastSilenceTimeout == 0 {
+		c.PlayOptions.MulticastSilenceTimeout = c.TimeoutOptions.Read
 	}
-	if c.InitialUDPReadTimeout == 0 {
-		c.InitialUDPReadTimeout = 3 * time.Second
+	if c.MediaActivityOptions.SparseReadTimeout == 0 {
+		c.MediaActivityOptions.SparseReadTimeout = 60 * time.Second
 	}
 	if c.WriteQueueSize == 0 {
 		c.WriteQueueSize = 256
-	} else if (c.WriteQueueSize & (c.WriteQueueSize - 1)) != 0 {
-		return fmt.Errorf("WriteQueueSize must be a power of two")
+	}
+	if c.SocketOptions.UDPReadBufferSize == 0 {
+		c.SocketOptions.UDPReadBufferSize = udpKernelReadBufferSize
+	}
+	if c.SocketOptions.UDPMaxPayloadSize == 0 {
+		c.SocketOptions.UDPMaxPayloadSize = udpMaxReadPayloadSize
 	}
 	if c.MaxPacketSize == 0 {
 		c.MaxPacketSize = udpMaxPayloadSize
-	} else if c.MaxPacketSize > udpMaxPayloadSize {
-		return fmt.Errorf("MaxPacketSize must be less than %d", udpMaxPayloadSize)
 	}
 	if c.UserAgent == "" {
 		c.UserAgent = "gortsplib"
 	}
+	if c.ObservabilityOptions.Logger == nil {
+		c.ObservabilityOptions.Logger = stdLogger{}
+	}
+	if c.ObservabilityOptions.Metrics == nil {
+		c.ObservabilityOptions.Metrics = nilMetrics{}
+	}
+	if c.ObservabilityOptions.StatsPeriod == 0 {
+		c.ObservabilityOptions.StatsPeriod = 1 * time.Second
+	}
+	if c.PlayOptions.ClockSync != nil {
+		// copy settings, in order not to edit the struct of the caller,
+		// that may be shared between clients.
+		clockSync := *c.PlayOptions.ClockSync
+		if clockSync.Period == 0 {
+			clockSync.Period = 10 * time.Second
+		}
+		if clockSync.Decode == nil {
+			clockSync.Decode = func(v string) (time.Time, error) {
+				return time.Parse(time.RFC3339Nano, v)
+			}
+		}
+		c.PlayOptions.ClockSync = &clockSync
+	}
 
 	// system functions
+	if c.LookupIPAddr == nil {
+		c.LookupIPAddr = net.DefaultResolver.LookupIPAddr
+	}
 	if c.DialContext == nil {
-		c.DialContext = (&net.Dialer{}).DialContext
+		d := &happyeyeballs.Dialer{
+			LookupIPAddr: c.LookupIPAddr,
+			DialContext:  (&net.Dialer{}).DialContext,
+			AttemptDelay: happyeyeballs.DefaultAttemptDelay,
+		}
+		c.DialContext = d.Dial
 	}
 	if c.ListenPacket == nil {
 		c.ListenPacket = net.ListenPacket
 	}
 
 	// callbacks
+	if c.OnPrepareRequest == nil {
+		c.OnPrepareRequest = func(*base.Request) {
+		}
+	}
 	if c.OnRequest == nil {
 		c.OnRequest = func(*base.Request) {
 		}
@@ -416,17 +597,55 @@ func (c *Client) Start(scheme string, host string) error {
 	}
 	if c.OnTransportSwitch == nil {
 		c.OnTransportSwitch = func(err error) {
-			log.Println(err.Error())
+			c.ObservabilityOptions.Logger.Info(err.Error())
+		}
+	}
+	if c.TransportOptions.OnTransportSwitch == nil {
+		c.TransportOptions.OnTransportSwitch = func(_ Transport, _ Transport, reason error) {
+			c.OnTransportSwitch(reason)
 		}
 	}
 	if c.OnPacketLost == nil {
 		c.OnPacketLost = func(err error) {
-			log.Println(err.Error())
+			c.ObservabilityOptions.Logger.Warn(err.Error())
 		}
 	}
 	if c.OnDecodeError == nil {
 		c.OnDecodeError = func(err error) {
-			log.Println(err.Error())
+			c.ObservabilityOptions.Logger.Warn(err.Error())
+		}
+	}
+	if c.OnMulticastSilence == nil {
+		c.OnMulticastSilence = func(err error) {
+			c.ObservabilityOptions.Logger.Warn(err.Error())
+		}
+	}
+	if c.OnRedirect == nil {
+		c.OnRedirect = func(*base.URL) {
+		}
+	}
+	if c.OnEndOfStream == nil {
+		c.OnEndOfStream = func(err error) {
+			c.ObservabilityOptions.Logger.Info(err.Error())
+		}
+	}
+	if c.OnDescribeProgress == nil {
+		c.OnDescribeProgress = func(time.Duration) {
+		}
+	}
+	if c.OnUnauthorized == nil {
+		c.OnUnauthorized = func(*base.Response) bool {
+			return false
+		}
+	}
+	if c.OnViolation == nil {
+		c.OnViolation = func(v *Violation) {
+			c.ObservabilityOptions.Logger.Warn(v.String())
+		}
+	}
+	if c.OnDescriptionWarning == nil {
+		c.OnDescriptionWarning = func(w description.Warning) {
+			c.ObservabilityOptions.Logger.Warn("invalid stream description: " + w.String())
 		}
 	}
 
@@ -456,6 +675,13 @@ func (c *Client) Start(scheme string, host string) error {
 	c.checkTimeoutTimer = emptyTimer()
 	c.keepalivePeriod = 30 * time.Second
 	c.keepaliveTimer = emptyTimer()
+	c.clockSyncTimer = emptyTimer()
+
+	if c.OnStats != nil {
+		c.statsTimer = time.NewTimer(c.ObservabilityOptions.StatsPeriod)
+	} else {
+		c.statsTimer = emptyTimer()
+	}
 
 	if c.BytesReceived != nil {
 		c.bytesReceived = c.BytesReceived
@@ -469,12 +695,17 @@ func (c *Client) Start(scheme string, host string) error {
 	}
 
 	c.chOptions = make(chan optionsReq)
+	c.chCapabilities = make(chan capabilitiesReq)
 	c.chDescribe = make(chan describeReq)
 	c.chAnnounce = make(chan announceReq)
 	c.chSetup = make(chan setupReq)
+	c.chSetupAll = make(chan setupAllReq)
 	c.chPlay = make(chan playReq)
 	c.chRecord = make(chan recordReq)
 	c.chPause = make(chan pauseReq)
+	c.chGetParameters = make(chan getParametersReq)
+	c.chSetParameters = make(chan setParametersReq)
+	c.chEndOfStream = make(chan struct{}, 1)
 	c.done = make(chan struct{})
 
 	go c.run()
@@ -577,6 +808,14 @@ func (c *Client) runInner() error {
 				return err
 			}
 
+		case req := <-c.chCapabilities:
+			caps, err := c.doCapabilities(req.url)
+			req.res <- clientRes{caps: caps, err: err}
+
+			if c.mustClose {
+				return err
+			}
+
 		case req := <-c.chDescribe:
 			sd, res, err := c.doDescribe(req.url)
 			req.res <- clientRes{sd: sd, res: res, err: err}
@@ -601,6 +840,14 @@ func (c *Client) runInner() error {
 				return err
 			}
 
+		case req := <-c.chSetupAll:
+			err := c.doSetupAll(req.baseURL, req.medias)
+			req.res <- clientRes{err: err}
+
+			if c.mustClose {
+				return err
+			}
+
 		case req := <-c.chPlay:
 			res, err := c.doPlay(req.ra)
 			req.res <- clientRes{res: res, err: err}
@@ -625,6 +872,25 @@ func (c *Client) runInner() error {
 				return err
 			}
 
+		case req := <-c.chGetParameters:
+			params, res, err := c.doGetParameters(req.names)
+			req.res <- clientRes{params: params, res: res, err: err}
+
+			if c.mustClose {
+				return err
+			}
+
+		case req := <-c.chSetParameters:
+			res, err := c.doSetParameters(req.params)
+			req.res <- clientRes{res: res, err: err}
+
+			if c.mustClose {
+				return err
+			}
+
+		case <-c.chEndOfStream:
+			c.doEndOfStream(false)
+
 		case <-c.checkTimeoutTimer.C:
 			err := c.doCheckTimeout()
 			if err != nil {
@@ -633,12 +899,23 @@ func (c *Client) runInner() error {
 			c.checkTimeoutTimer = time.NewTimer(c.checkTimeoutPeriod)
 
 		case <-c.keepaliveTimer.C:
-			err := c.doKeepAlive()
+			err := c.doKeepAlive(true)
 			if err != nil {
 				return err
 			}
 			c.keepaliveTimer = time.NewTimer(c.keepalivePeriod)
 
+		case <-c.clockSyncTimer.C:
+			err := c.doClockSync()
+			if err != nil {
+				return err
+			}
+			c.clockSyncTimer = time.NewTimer(c.PlayOptions.ClockSync.Period)
+
+		case <-c.statsTimer.C:
+			c.OnStats(c.StatsSnapshot())
+			c.statsTimer = time.NewTimer(c.ObservabilityOptions.StatsPeriod)
+
 		case <-chWriterError:
 			return c.writer.stopError
 
@@ -648,7 +925,18 @@ func (c *Client) runInner() error {
 
 		case res := <-chReaderResponse:
 			c.OnResponse(res)
-			// these are responses to keepalives, ignore them.
+
+			// these are responses to keepalives, ignore them,
+			// unless credentials have expired.
+			if res.StatusCode == base.StatusUnauthorized && c.OnUnauthorized(res) {
+				c.sender = nil
+				c.refreshingAuth = true
+				err := c.doKeepAlive(false)
+				c.refreshingAuth = false
+				if err != nil {
+					return err
+				}
+			}
 
 		case req := <-chReaderRequest:
 			err := c.handleServerRequest(req)
@@ -662,15 +950,58 @@ func (c *Client) runInner() error {
 	}
 }
 
-func (c *Client) waitResponse(requestCseqStr string) (*base.Response, error) {
-	t := time.NewTimer(c.ReadTimeout)
+func (c *Client) waitResponse(req *base.Request, requestCseqStr string) (*base.Response, error) {
+	ress, err := c.waitResponses(req, []string{requestCseqStr})
+	if err != nil {
+		return nil, err
+	}
+	return ress[0], nil
+}
+
+// waitResponses waits for the responses to pipelined requests.
+// Responses are matched to requests by CSeq, in any order.
+// Responses without CSeq are assigned to the oldest request without a response.
+func (c *Client) waitResponses(req *base.Request, requestCseqStrs []string) ([]*base.Response, error) {
+	ress := make([]*base.Response, len(requestCseqStrs))
+	remaining := len(requestCseqStrs)
+
+	timeout := c.TimeoutOptions.Read
+	var keepalivePeriod time.Duration
+
+	if req.Method == base.Describe {
+		timeout = c.DescribeOptions.Timeout
+		keepalivePeriod = c.DescribeOptions.KeepalivePeriod
+	}
+
+	t := time.NewTimer(timeout)
 	defer t.Stop()
 
+	var keepaliveTicker <-chan time.Time
+	if keepalivePeriod != 0 {
+		tk := time.NewTicker(keepalivePeriod)
+		defer tk.Stop()
+		keepaliveTicker = tk.C
+	}
+
+	start := time.Now()
+
 	for {
 		select {
 		case <-t.C:
 			return nil, liberrors.ErrClientRequestTimedOut{}
 
+		case <-keepaliveTicker:
+			c.OnDescribeProgress(time.Since(start))
+
+			// responses to interim requests have a different CSeq and are discarded.
+			_, err := c.do(&base.Request{
+				Method: base.Options,
+				URL:    req.URL,
+			}, true)
+			if err != nil {
+				return nil, err
+			}
+
 		case err := <-c.reader.chError:
 			c.reader = nil
 			return nil, err
@@ -678,11 +1009,20 @@ func (c *Client) waitResponse(requestCseqStr string) (*base.Response, error) {
 		case res := <-c.reader.chResponse:
 			c.OnResponse(res)
 
-			// accept response if CSeq equals request CSeq, or if CSeq is not present
-			if cseq, ok := res.Header["CSeq"]; !ok || len(cseq) != 1 || strings.TrimSpace(cseq[0]) == requestCseqStr {
-				return res, nil
+			i := responseIndex(res, ress, requestCseqStrs)
+			if i < 0 {
+				continue
+			}
+
+			ress[i] = res
+			remaining--
+
+			if remaining == 0 {
+				return ress, nil
 			}
 
+			t.Reset(timeout)
+
 		case req := <-c.reader.chRequest:
 			err := c.handleServerRequest(req)
 			if err != nil {
@@ -698,7 +1038,31 @@ func (c *Client) waitResponse(requestCseqStr string) (*base.Response, error) {
 func (c *Client) handleServerRequest(req *base.Request) error {
 	c.OnServerRequest(req)
 
-	if req.Method != base.Options {
+	if c.StrictOptions.Enabled {
+		err := c.reportViolations(strictCheckServerRequest(&c.strictServerCSeq, req))
+		if err != nil {
+			return err
+		}
+	}
+
+	var redirectURL *base.URL
+
+	switch {
+	case req.Method == base.Options:
+
+	case req.Method == base.SetParameter && c.PlayOptions.ClockSync != nil:
+		if c.state == clientStatePlay {
+			c.processClockSyncParameters(req.Body, c.timeNow())
+		}
+
+	case req.Method == base.Redirect:
+		var err error
+		redirectURL, err = redirectLocation(req)
+		if err != nil {
+			return err
+		}
+
+	default:
 		return liberrors.ErrClientUnhandledMethod{Method: req.Method}
 	}
 
@@ -717,8 +1081,159 @@ func (c *Client) handleServerRequest(req *base.Request) error {
 
 	c.OnServerResponse(res)
 
-	c.nconn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
-	return c.conn.WriteResponse(res)
+	c.nconn.SetWriteDeadline(time.Now().Add(c.TimeoutOptions.Write))
+	err := c.conn.WriteResponse(res)
+	if err != nil {
+		return err
+	}
+
+	if redirectURL != nil {
+		return c.doRedirect(redirectURL)
+	}
+
+	return nil
+}
+
+// responseIndex returns the index of the request a response refers to,
+// or -1 if the response must be discarded.
+func responseIndex(res *base.Response, ress []*base.Response, requestCseqStrs []string) int {
+	// accept response if CSeq equals request CSeq, or if CSeq is not present
+	if cseq, ok := res.Header["CSeq"]; ok && len(cseq) == 1 {
+		for i, cseqStr := range requestCseqStrs {
+			if ress[i] == nil && strings.TrimSpace(cseq[0]) == cseqStr {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for i, r := range ress {
+		if r == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// reportViolations reports violations detected in strict mode.
+// It returns an error if FailOnViolation is true and there's at least a violation.
+func (c *Client) reportViolations(vs []*Violation) error {
+	for _, v := range vs {
+		c.OnViolation(v)
+	}
+
+	if len(vs) != 0 && c.StrictOptions.FailOnViolation {
+		return liberrors.ErrClientProtocolViolation{Description: vs[0].Description}
+	}
+
+	return nil
+}
+
+func redirectLocation(req *base.Request) (*base.URL, error) {
+	if len(req.Header["Location"]) != 1 {
+		return nil, liberrors.ErrClientRedirectLocationInvalid{}
+	}
+
+	ru, err := base.ParseURL(req.Header["Location"][0])
+	if err != nil {
+		return nil, liberrors.ErrClientRedirectLocationInvalid{}
+	}
+
+	return ru, nil
+}
+
+// formatsAreCompatible checks whether two formats can be exchanged
+// without affecting callbacks and decoders.
+func formatsAreCompatible(a format.Format, b format.Format) bool {
+	return a.PayloadType() == b.PayloadType() &&
+		a.Codec() == b.Codec() &&
+		a.ClockRate() == b.ClockRate()
+}
+
+// mediasAreCompatible checks whether a media of a redirect location
+// can replace a media that is already setupped.
+func mediasAreCompatible(a *description.Media, b *description.Media) bool {
+	if a.Type != b.Type || a.IsBackChannel != b.IsBackChannel || len(a.Formats) != len(b.Formats) {
+		return false
+	}
+
+	for i, forma := range a.Formats {
+		if !formatsAreCompatible(forma, b.Formats[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sameServer checks whether two URLs have the same scheme, host and port.
+func sameServer(a *base.URL, b *base.URL) bool {
+	return a.Scheme == b.Scheme && canonicalAddr(a) == canonicalAddr(b)
+}
+
+func (c *Client) doRedirect(ru *base.URL) error {
+	// reuse credentials only if the location points to the same server,
+	// in order not to send them to third parties.
+	if ru.User == nil && c.lastDescribeURL != nil &&
+		(sameServer(ru, c.lastDescribeURL) || c.RedirectOptions.ForwardCredentials) {
+		ru.User = c.lastDescribeURL.User
+	}
+
+	c.OnRedirect(ru)
+
+	if !c.RedirectOptions.Reconnect || c.state != clientStatePlay {
+		return liberrors.ErrClientRedirected{URL: ru}
+	}
+
+	prevMedias := c.setuppedMedias
+	prevDesc := c.lastDescribeDesc
+
+	c.reset()
+
+	c.connURL = &base.URL{
+		Scheme: ru.Scheme,
+		Host:   ru.Host,
+	}
+
+	desc, _, err := c.doDescribe(ru)
+	if err != nil {
+		return err
+	}
+
+	if prevDesc == nil || len(prevDesc.Medias) != len(desc.Medias) {
+		return liberrors.ErrClientRedirectIncompatibleMedias{}
+	}
+
+	// medias are matched by position, and are set up with controls of the new location.
+	// They are stored with the previous media, that is the one known by the user.
+	for i, prevMedi := range prevDesc.Medias {
+		cm, ok := prevMedias[prevMedi]
+		if !ok {
+			continue
+		}
+
+		newMedi := desc.Medias[i]
+		if !mediasAreCompatible(prevMedi, newMedi) {
+			return liberrors.ErrClientRedirectIncompatibleMedias{}
+		}
+
+		_, err = c.doSetup(desc.BaseURL, newMedi, 0, 0)
+		if err != nil {
+			return err
+		}
+
+		ncm := c.setuppedMedias[newMedi]
+		delete(c.setuppedMedias, newMedi)
+		c.setuppedMedias[prevMedi] = ncm
+
+		ncm.onPacketRTCP = cm.onPacketRTCP
+		for j, tr := range cm.formats {
+			ncm.formats[j].onPacketRTP = tr.onPacketRTP
+		}
+	}
+
+	_, err = c.doPlay(c.lastRange)
+	return err
 }
 
 func (c *Client) doClose() {
@@ -747,15 +1262,22 @@ func (c *Client) doClose() {
 		c.reader = nil
 		c.nconn = nil
 		c.conn = nil
+		c.ObservabilityOptions.Metrics.AddConns(-1)
 	} else if c.nconn != nil {
 		c.nconn.Close()
 		c.nconn = nil
 		c.conn = nil
+		c.ObservabilityOptions.Metrics.AddConns(-1)
 	}
 
 	for _, cm := range c.setuppedMedias {
 		cm.close()
 	}
+
+	if c.session != "" {
+		c.ObservabilityOptions.Metrics.AddSessions(-1)
+		c.session = ""
+	}
 }
 
 func (c *Client) reset() {
@@ -765,8 +1287,10 @@ func (c *Client) reset() {
 	c.session = ""
 	c.sender = nil
 	c.cseq = 0
+	c.strictServerCSeq = strictCSeqChecker{}
 	c.optionsSent = false
 	c.useGetParameter = false
+	c.capabilities = nil
 	c.baseURL = nil
 	c.effectiveTransport = nil
 	c.backChannelSetupped = false
@@ -791,16 +1315,39 @@ func (c *Client) checkState(allowed map[clientState]struct{}) error {
 }
 
 func (c *Client) trySwitchingProtocol() error {
-	c.OnTransportSwitch(liberrors.ErrClientSwitchToTCP{})
-
-	prevConnURL := c.connURL
+	from := *c.effectiveTransport
 	prevBaseURL := c.baseURL
 	prevMedias := c.setuppedMedias
 
+	if from == TransportUDP && c.TransportOptions.FallbackMulticast {
+		c.TransportOptions.OnTransportSwitch(from, TransportUDPMulticast, liberrors.ErrClientSwitchToMulticast{})
+
+		err := c.switchPlayingTransport(TransportUDPMulticast, prevBaseURL, prevMedias)
+		if err == nil {
+			return nil
+		}
+
+		// the server doesn't support multicast
+		c.TransportOptions.OnTransportSwitch(TransportUDPMulticast, TransportTCP,
+			liberrors.ErrClientSwitchFromMulticastToTCP{Err: err})
+		return c.switchPlayingTransport(TransportTCP, prevBaseURL, prevMedias)
+	}
+
+	c.TransportOptions.OnTransportSwitch(from, TransportTCP, liberrors.ErrClientSwitchToTCP{})
+	return c.switchPlayingTransport(TransportTCP, prevBaseURL, prevMedias)
+}
+
+// switchPlayingTransport restarts the session with the given transport.
+func (c *Client) switchPlayingTransport(
+	to Transport,
+	prevBaseURL *base.URL,
+	prevMedias map[*description.Media]*clientMedia,
+) error {
+	prevConnURL := c.connURL
+
 	c.reset()
 
-	v := TransportTCP
-	c.effectiveTransport = &v
+	c.effectiveTransport = &to
 	c.connURL = prevConnURL
 
 	// some Hikvision cameras require a describe before a setup
@@ -830,7 +1377,7 @@ func (c *Client) trySwitchingProtocol() error {
 }
 
 func (c *Client) trySwitchingProtocol2(medi *description.Media, baseURL *base.URL) (*base.Response, error) {
-	c.OnTransportSwitch(liberrors.ErrClientSwitchToTCP2{})
+	c.TransportOptions.OnTransportSwitch(TransportUDP, TransportTCP, liberrors.ErrClientSwitchToTCP2{})
 
 	prevConnURL := c.connURL
 
@@ -849,10 +1396,97 @@ func (c *Client) trySwitchingProtocol2(medi *description.Media, baseURL *base.UR
 	return c.doSetup(baseURL, medi, 0, 0)
 }
 
+// setuppedMediasInOrder returns setupped medias in the order of the session description.
+func (c *Client) setuppedMediasInOrder() []*description.Media {
+	desc := c.lastDescribeDesc
+	if c.state == clientStatePreRecord {
+		desc = c.lastAnnounceDesc
+	}
+
+	ret := make([]*description.Media, 0, len(c.setuppedMedias))
+
+	if desc != nil {
+		for _, medi := range desc.Medias {
+			if _, ok := c.setuppedMedias[medi]; ok {
+				ret = append(ret, medi)
+			}
+		}
+	}
+
+	// medias that are not part of the description are sorted by control attribute
+	if len(ret) != len(c.setuppedMedias) {
+		inDesc := make(map[*description.Media]struct{}, len(ret))
+		for _, medi := range ret {
+			inDesc[medi] = struct{}{}
+		}
+
+		start := len(ret)
+		for medi := range c.setuppedMedias {
+			if _, ok := inDesc[medi]; !ok {
+				ret = append(ret, medi)
+			}
+		}
+
+		sort.Slice(ret[start:], func(i, j int) bool {
+			return ret[start+i].Control < ret[start+j].Control
+		})
+	}
+
+	return ret
+}
+
+// switchSessionToTCP sets up again already-setupped medias with TCP,
+// in the order of the session description, then sets up medi with TCP,
+// without leaving the current connection and session.
+// Blocksize is not negotiated by the client, therefore there's nothing to carry over.
+// If a SETUP request is rejected, the session can't be restored
+// and the client is reset.
+func (c *Client) switchSessionToTCP(baseURL *base.URL, medi *description.Media) (*base.Response, error) {
+	v := TransportTCP
+	c.effectiveTransport = &v
+
+	prevOrder := c.setuppedMediasInOrder()
+	prevMedias := c.setuppedMedias
+	c.setuppedMedias = nil
+
+	for _, cm := range prevMedias {
+		cm.close()
+	}
+
+	for _, prevMedi := range prevOrder {
+		cm := prevMedias[prevMedi]
+
+		_, err := c.doSetup(baseURL, prevMedi, 0, 0)
+		if err != nil {
+			c.reset()
+			return nil, liberrors.ErrClientSwitchSessionToTCP{Err: err}
+		}
+
+		c.setuppedMedias[prevMedi].onPacketRTCP = cm.onPacketRTCP
+		for j, tr := range cm.formats {
+			c.setuppedMedias[prevMedi].formats[j].onPacketRTP = tr.onPacketRTP
+		}
+	}
+
+	return c.doSetup(baseURL, medi, 0, 0)
+}
+
 func (c *Client) startTransportRoutines() {
 	c.timeDecoder = rtptime.NewGlobalDecoder2()
 
 	for _, cm := range c.setuppedMedias {
+		if cm.activity == MediaActivitySparse {
+			for _, cf := range cm.formats {
+				c.timeDecoder.SetSparse(cf.format)
+			}
+
+			// give sparse medias a whole timeout period before considering them silent
+			if cm.udpRTPListener != nil {
+				now := c.timeNow().Unix()
+				atomic.StoreInt64(cm.udpRTPListener.lastPacketTime, now)
+				atomic.StoreInt64(cm.udpRTCPListener.lastPacketTime, now)
+			}
+		}
 		cm.start()
 	}
 
@@ -864,13 +1498,24 @@ func (c *Client) startTransportRoutines() {
 	if c.state == clientStatePlay && c.stdChannelSetupped {
 		c.keepaliveTimer = time.NewTimer(c.keepalivePeriod)
 
+		// synchronize the clock as soon as possible, then periodically.
+		if c.PlayOptions.ClockSync != nil {
+			c.clockSyncTimer = time.NewTimer(0)
+		}
+
 		switch *c.effectiveTransport {
 		case TransportUDP:
-			c.checkTimeoutTimer = time.NewTimer(c.InitialUDPReadTimeout)
+			c.checkTimeoutTimer = time.NewTimer(c.TransportOptions.InitialUDPReadTimeout)
 			c.checkTimeoutInitial = true
 
 		case TransportUDPMulticast:
-			c.checkTimeoutTimer = time.NewTimer(c.checkTimeoutPeriod)
+			// multicast has been chosen automatically by the fallback
+			if c.TransportOptions.Transport == nil {
+				c.checkTimeoutTimer = time.NewTimer(c.TransportOptions.InitialUDPReadTimeout)
+				c.checkTimeoutInitial = true
+			} else {
+				c.checkTimeoutTimer = time.NewTimer(c.checkTimeoutPeriod)
+			}
 
 		default: // TCP
 			c.checkTimeoutTimer = time.NewTimer(c.checkTimeoutPeriod)
@@ -891,6 +1536,7 @@ func (c *Client) stopTransportRoutines() {
 
 	c.checkTimeoutTimer = emptyTimer()
 	c.keepaliveTimer = emptyTimer()
+	c.clockSyncTimer = emptyTimer()
 
 	for _, cm := range c.setuppedMedias {
 		cm.stop()
@@ -941,11 +1587,13 @@ func (c *Client) connOpen() error {
 		return liberrors.ErrClientUnsupportedScheme{Scheme: c.connURL.Scheme}
 	}
 
-	if c.connURL.Scheme == "rtsps" && c.Transport != nil && *c.Transport != TransportTCP {
+	if c.connURL.Scheme == "rtsps" && c.TransportOptions.Transport != nil &&
+		*c.TransportOptions.Transport != TransportTCP &&
+		(*c.TransportOptions.Transport != TransportUDP || c.SecurityOptions.UnencryptedMedia == nil) {
 		return liberrors.ErrClientRTSPSTCP{}
 	}
 
-	dialCtx, dialCtxCancel := context.WithTimeout(c.ctx, c.ReadTimeout)
+	dialCtx, dialCtxCancel := context.WithTimeout(c.ctx, c.TimeoutOptions.Read)
 	defer dialCtxCancel()
 
 	nconn, err := c.DialContext(dialCtx, "tcp", canonicalAddr(c.connURL))
@@ -953,8 +1601,14 @@ func (c *Client) connOpen() error {
 		return err
 	}
 
+	err = setTCPOptions(nconn, c.SocketOptions.DisableTCPNoDelay, c.SocketOptions.TCPKeepAlivePeriod)
+	if err != nil {
+		nconn.Close()
+		return err
+	}
+
 	if c.connURL.Scheme == "rtsps" {
-		tlsConfig := c.TLSConfig
+		tlsConfig := c.SecurityOptions.TLSConfig
 		if tlsConfig == nil {
 			tlsConfig = &tls.Config{}
 		}
@@ -964,8 +1618,12 @@ func (c *Client) connOpen() error {
 	}
 
 	c.nconn = nconn
+	c.ObservabilityOptions.Metrics.AddConns(1)
 	bc := bytecounter.New(c.nconn, c.bytesReceived, c.bytesSent)
 	c.conn = conn.NewConn(bc)
+	if c.MemoryOptions.PoolPackets {
+		c.conn.SetPayloadAllocator(getPacketBuffer)
+	}
 	c.reader = &clientReader{
 		c: c,
 	}
@@ -996,13 +1654,17 @@ func (c *Client) do(req *base.Request, skipResponse bool) (*base.Response, error
 
 	req.Header["User-Agent"] = base.HeaderValue{c.UserAgent}
 
+	// do not edit the URL in place, since it may be shared with the client state
+	req.URL = req.URL.Clone()
+	c.OnPrepareRequest(req)
+
 	if c.sender != nil {
 		c.sender.AddAuthorization(req)
 	}
 
 	c.OnRequest(req)
 
-	c.nconn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+	c.nconn.SetWriteDeadline(time.Now().Add(c.TimeoutOptions.Write))
 	err := c.conn.WriteRequest(req)
 	if err != nil {
 		return nil, err
@@ -1012,18 +1674,63 @@ func (c *Client) do(req *base.Request, skipResponse bool) (*base.Response, error
 		return nil, nil
 	}
 
-	res, err := c.waitResponse(cseqStr)
+	res, err := c.waitResponse(req, cseqStr)
 	if err != nil {
 		c.mustClose = true
 		return nil, err
 	}
 
+	err = c.processResponse(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == base.StatusUnauthorized {
+		// send request again with authentication
+		if req.URL.User != nil && c.sender == nil {
+			pass, _ := req.URL.User.Password()
+			user := req.URL.User.Username()
+
+			sender, err := auth.NewSender(res.Header["WWW-Authenticate"], user, pass)
+			if err != nil {
+				return nil, liberrors.ErrClientAuthSetup{Err: err}
+			}
+			c.sender = sender
+
+			return c.do(req, skipResponse)
+		}
+
+		// send request again with refreshed credentials
+		if !c.refreshingAuth && c.OnUnauthorized(res) {
+			c.sender = nil
+			c.refreshingAuth = true
+			res, err = c.do(req, skipResponse)
+			c.refreshingAuth = false
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// processResponse checks a response and extracts the session from it.
+func (c *Client) processResponse(req *base.Request, res *base.Response) error {
+	if c.StrictOptions.Enabled {
+		err := c.reportViolations(strictCheckResponse(c.session, req, res))
+		if err != nil {
+			return err
+		}
+	}
+
 	// get session from response
 	if v, ok := res.Header["Session"]; ok {
 		var sx headers.Session
 		err := sx.Unmarshal(v)
 		if err != nil {
-			return nil, liberrors.ErrClientSessionHeaderInvalid{Err: err}
+			return liberrors.ErrClientSessionHeaderInvalid{Err: err}
+		}
+		if c.session == "" {
+			c.ObservabilityOptions.Metrics.AddSessions(1)
 		}
 		c.session = sx.Session
 
@@ -1032,48 +1739,68 @@ func (c *Client) do(req *base.Request, skipResponse bool) (*base.Response, error
 		}
 	}
 
-	// send request again with authentication
-	if res.StatusCode == base.StatusUnauthorized && req.URL.User != nil && c.sender == nil {
-		pass, _ := req.URL.User.Password()
-		user := req.URL.User.Username()
+	return nil
+}
 
-		sender, err := auth.NewSender(res.Header["WWW-Authenticate"], user, pass)
-		if err != nil {
-			return nil, liberrors.ErrClientAuthSetup{Err: err}
+func (c *Client) hasContinuousMedias() bool {
+	for _, cm := range c.setuppedMedias {
+		if cm.activity == MediaActivityContinuous {
+			return true
 		}
-		c.sender = sender
+	}
+	return false
+}
 
-		return c.do(req, skipResponse)
+// medias that are checked for timeouts and their timeout.
+// sparse medias are checked only when there are no continuous medias.
+func (c *Client) timeoutMedias() ([]*clientMedia, time.Duration) {
+	activity, timeout := MediaActivityContinuous, c.TimeoutOptions.Read
+	if !c.hasContinuousMedias() {
+		activity, timeout = MediaActivitySparse, c.MediaActivityOptions.SparseReadTimeout
 	}
 
-	return res, nil
+	var medias []*clientMedia
+	for _, cm := range c.setuppedMedias {
+		if cm.activity == activity {
+			medias = append(medias, cm)
+		}
+	}
+	return medias, timeout
 }
 
-func (c *Client) atLeastOneUDPPacketHasBeenReceived() bool {
+func (c *Client) enoughUDPPacketsHaveBeenReceived() bool {
+	var n uint64
 	for _, ct := range c.setuppedMedias {
-		lft := atomic.LoadInt64(ct.udpRTPListener.lastPacketTime)
-		if lft != 0 {
-			return true
+		if ct.activity != MediaActivityContinuous {
+			continue
 		}
 
-		lft = atomic.LoadInt64(ct.udpRTCPListener.lastPacketTime)
-		if lft != 0 {
-			return true
-		}
+		n += atomic.LoadUint64(ct.udpRTPListener.packetsReceived)
+		n += atomic.LoadUint64(ct.udpRTCPListener.packetsReceived)
 	}
-	return false
+	return n >= uint64(c.TransportOptions.FallbackProbePackets)
 }
 
 func (c *Client) isInUDPTimeout() bool {
+	medias, timeout := c.timeoutMedias()
+	return c.noUDPPacketsSince(medias, timeout)
+}
+
+func (c *Client) isMulticastSilent() bool {
+	medias, _ := c.timeoutMedias()
+	return c.noUDPPacketsSince(medias, c.PlayOptions.MulticastSilenceTimeout)
+}
+
+func (c *Client) noUDPPacketsSince(medias []*clientMedia, timeout time.Duration) bool {
 	now := c.timeNow()
-	for _, ct := range c.setuppedMedias {
+	for _, ct := range medias {
 		lft := time.Unix(atomic.LoadInt64(ct.udpRTPListener.lastPacketTime), 0)
-		if now.Sub(lft) < c.ReadTimeout {
+		if now.Sub(lft) < timeout {
 			return false
 		}
 
 		lft = time.Unix(atomic.LoadInt64(ct.udpRTCPListener.lastPacketTime), 0)
-		if now.Sub(lft) < c.ReadTimeout {
+		if now.Sub(lft) < timeout {
 			return false
 		}
 	}
@@ -1083,21 +1810,41 @@ func (c *Client) isInUDPTimeout() bool {
 func (c *Client) isInTCPTimeout() bool {
 	now := c.timeNow()
 	lft := time.Unix(atomic.LoadInt64(c.tcpLastFrameTime), 0)
-	return now.Sub(lft) >= c.ReadTimeout
+	_, timeout := c.timeoutMedias()
+	return now.Sub(lft) >= timeout
 }
 
 func (c *Client) doCheckTimeout() error {
+	// the server stopped sending packets on purpose
+	if c.endOfStreamReached {
+		return nil
+	}
+
+	err := c.checkTimeout()
+	if err != nil && c.isAfterPlayEnd() {
+		c.doEndOfStream(true)
+		return nil
+	}
+	return err
+}
+
+func (c *Client) checkTimeout() error {
 	if *c.effectiveTransport == TransportUDP ||
 		*c.effectiveTransport == TransportUDPMulticast {
-		if c.checkTimeoutInitial && !c.backChannelSetupped && c.Transport == nil {
+		if c.checkTimeoutInitial && !c.backChannelSetupped && c.TransportOptions.Transport == nil {
 			c.checkTimeoutInitial = false
 
-			if !c.atLeastOneUDPPacketHasBeenReceived() {
+			// sparse medias may legitimately be silent during the initial period.
+			if c.hasContinuousMedias() && !c.enoughUDPPacketsHaveBeenReceived() {
 				err := c.trySwitchingProtocol()
 				if err != nil {
 					return err
 				}
 			}
+		} else if *c.effectiveTransport == TransportUDPMulticast {
+			if c.isMulticastSilent() {
+				return c.doMulticastSilence()
+			}
 		} else if c.isInUDPTimeout() {
 			return liberrors.ErrClientUDPTimeout{}
 		}
@@ -1108,8 +1855,59 @@ func (c *Client) doCheckTimeout() error {
 	return nil
 }
 
-func (c *Client) doKeepAlive() error {
-	// some cameras do not reply to keepalives, do not wait for responses.
+func (c *Client) endOfStream() {
+	select {
+	case c.chEndOfStream <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Client) doEndOfStream(endOfRange bool) {
+	if c.state != clientStatePlay || c.endOfStreamReached {
+		return
+	}
+
+	c.endOfStreamReached = true
+	c.OnEndOfStream(liberrors.ErrClientEndOfStream{EndOfRange: endOfRange})
+}
+
+// isAfterPlayEnd checks whether the end time of the Range header
+// of the PLAY response has been reached.
+func (c *Client) isAfterPlayEnd() bool {
+	if c.playDuration == nil {
+		return false
+	}
+
+	elapsed := time.Duration(float64(c.timeNow().Sub(c.playStartTime)) * c.playSpeed.Speed)
+	return elapsed >= *c.playDuration
+}
+
+func (c *Client) doMulticastSilence() error {
+	c.OnMulticastSilence(liberrors.ErrClientMulticastSilence{Rejoin: c.PlayOptions.MulticastRejoin})
+
+	if !c.PlayOptions.MulticastRejoin {
+		// groups are left when listeners are closed
+		return liberrors.ErrClientUDPTimeout{}
+	}
+
+	for _, cm := range c.setuppedMedias {
+		err := cm.udpRTPListener.rejoin()
+		if err != nil {
+			return err
+		}
+
+		err = cm.udpRTCPListener.rejoin()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// some cameras do not reply to keepalives,
+// therefore responses are waited for only when credentials are being refreshed.
+func (c *Client) doKeepAlive(skipResponse bool) error {
 	_, err := c.do(&base.Request{
 		Method: func() base.Method {
 			// the VLC integrated rtsp server requires GET_PARAMETER
@@ -1120,7 +1918,7 @@ func (c *Client) doKeepAlive() error {
 		}(),
 		// use the stream base URL, otherwise some cameras do not reply
 		URL: c.baseURL,
-	}, true)
+	}, skipResponse)
 	return err
 }
 
@@ -1151,17 +1949,48 @@ func (c *Client) doOptions(u *base.URL) (*base.Response, error) {
 		// since this method is not implemented by every RTSP server,
 		// return an error only if status code is not 404
 		if res.StatusCode == base.StatusNotFound {
+			c.capabilities = newClientCapabilities(res.Header)
 			return res, nil
 		}
 		return nil, liberrors.ErrClientBadStatusCode{Code: res.StatusCode, Message: res.StatusMessage}
 	}
 
 	c.optionsSent = true
-	c.useGetParameter = supportsGetParameter(res.Header)
+	c.capabilities = newClientCapabilities(res.Header)
+	c.useGetParameter = c.capabilities.Supports(base.GetParameter)
 
 	return res, nil
 }
 
+func (c *Client) doCapabilities(u *base.URL) (*ClientCapabilities, error) {
+	// capabilities are cached until the connection is closed.
+	if c.nconn != nil && c.capabilities != nil {
+		return c.capabilities, nil
+	}
+
+	_, err := c.doOptions(u)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.capabilities, nil
+}
+
+// Capabilities returns the capabilities of the server.
+// They are obtained with an OPTIONS request and cached
+// until the connection is closed.
+func (c *Client) Capabilities(u *base.URL) (*ClientCapabilities, error) {
+	cres := make(chan clientRes)
+	select {
+	case c.chCapabilities <- capabilitiesReq{url: u, res: cres}:
+		res := <-cres
+		return res.caps, res.err
+
+	case <-c.done:
+		return nil, c.closeError
+	}
+}
+
 // Options sends an OPTIONS request.
 func (c *Client) Options(u *base.URL) (*base.Response, error) {
 	cres := make(chan clientRes)
@@ -1176,6 +2005,15 @@ func (c *Client) Options(u *base.URL) (*base.Response, error) {
 }
 
 func (c *Client) doDescribe(u *base.URL) (*description.Session, *base.Response, error) {
+	return c.doDescribeRedirects(u, nil)
+}
+
+// doDescribeRedirects sends a DESCRIBE request and follows 3xx responses.
+// visited contains the URLs that redirected to u, without credentials.
+func (c *Client) doDescribeRedirects(
+	u *base.URL,
+	visited []string,
+) (*description.Session, *base.Response, error) {
 	err := c.checkState(map[clientState]struct{}{
 		clientStateInitial:   {},
 		clientStatePrePlay:   {},
@@ -1198,10 +2036,18 @@ func (c *Client) doDescribe(u *base.URL) (*description.Session, *base.Response,
 		header["Require"] = base.HeaderValue{"www.onvif.org/ver20/backchannel"}
 	}
 
+	var body []byte
+
+	if c.DescribeOptions.Body != nil {
+		header["Content-Type"] = base.HeaderValue{c.DescribeOptions.Body.ContentType}
+		body = c.DescribeOptions.Body.Content
+	}
+
 	res, err := c.do(&base.Request{
 		Method: base.Describe,
 		URL:    u,
 		Header: header,
+		Body:   body,
 	}, false)
 	if err != nil {
 		return nil, nil, err
@@ -1220,7 +2066,25 @@ func (c *Client) doDescribe(u *base.URL) (*description.Session, *base.Response,
 				return nil, nil, err
 			}
 
-			if u.User != nil {
+			visited = append(visited, u.CloneWithoutCredentials().String())
+
+			if len(visited) > c.RedirectOptions.MaxRedirects {
+				return nil, nil, liberrors.ErrClientTooManyRedirects{Max: c.RedirectOptions.MaxRedirects}
+			}
+
+			for _, vu := range visited {
+				if vu == ru.CloneWithoutCredentials().String() {
+					return nil, nil, liberrors.ErrClientRedirectLoop{URL: ru}
+				}
+			}
+
+			same := sameServer(u, ru)
+
+			if !same && c.RedirectOptions.SameHostOnly {
+				return nil, nil, liberrors.ErrClientRedirected{URL: ru}
+			}
+
+			if ru.User == nil && (same || c.RedirectOptions.ForwardCredentials) {
 				ru.User = u.User
 			}
 
@@ -1229,45 +2093,66 @@ func (c *Client) doDescribe(u *base.URL) (*description.Session, *base.Response,
 				Host:   ru.Host,
 			}
 
-			return c.doDescribe(ru)
+			return c.doDescribeRedirects(ru, visited)
 		}
 
 		return nil, res, liberrors.ErrClientBadStatusCode{Code: res.StatusCode, Message: res.StatusMessage}
 	}
 
-	ct, ok := res.Header["Content-Type"]
-	if !ok || len(ct) != 1 {
+	ct, ok := res.Header.ContentType()
+	if !ok {
 		return nil, nil, liberrors.ErrClientContentTypeMissing{}
 	}
 
-	// strip encoding information from Content-Type header
-	ct = base.HeaderValue{strings.Split(ct[0], ";")[0]}
+	var ssd *sdp.SessionDescription
+	var desc *description.Session
 
-	if ct[0] != "application/sdp" {
-		return nil, nil, liberrors.ErrClientContentTypeUnsupported{CT: ct}
-	}
+	if ct == "application/sdp" {
+		ssd = &sdp.SessionDescription{}
+		err = ssd.Unmarshal(res.Body)
+		if err != nil {
+			return nil, nil, liberrors.ErrClientSDPInvalid{Err: err}
+		}
 
-	var ssd sdp.SessionDescription
-	err = ssd.Unmarshal(res.Body)
-	if err != nil {
-		return nil, nil, liberrors.ErrClientSDPInvalid{Err: err}
-	}
+		desc = &description.Session{}
 
-	var desc description.Session
-	err = desc.Unmarshal(&ssd)
-	if err != nil {
-		return nil, nil, liberrors.ErrClientSDPInvalid{Err: err}
+		if c.DescribeOptions.Lenient {
+			var warnings []description.Warning
+			warnings, err = desc.UnmarshalLenient(ssd)
+			if err != nil {
+				return nil, nil, liberrors.ErrClientSDPInvalid{Err: err}
+			}
+
+			for _, w := range warnings {
+				c.OnDescriptionWarning(w)
+			}
+		} else {
+			err = desc.Unmarshal(ssd)
+			if err != nil {
+				return nil, nil, liberrors.ErrClientSDPInvalid{Err: err}
+			}
+		}
+	} else {
+		if c.DescribeOptions.Decode == nil {
+			return nil, nil, liberrors.ErrClientContentTypeUnsupported{CT: base.HeaderValue{ct}}
+		}
+
+		desc, err = c.DescribeOptions.Decode(ct, res.Body)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	baseURL, err := findBaseURL(&ssd, res, u)
+	baseURL, err := findBaseURL(ssd, res, u)
 	if err != nil {
 		return nil, nil, err
 	}
 	desc.BaseURL = baseURL
 
 	c.lastDescribeURL = u
+	c.lastDescribeDesc = desc
 
-	return &desc, res, nil
+	return desc, res, nil
 }
 
 // Describe sends a DESCRIBE request.
@@ -1291,61 +2176,185 @@ func (c *Client) doAnnounce(u *base.URL, desc *description.Session) (*base.Respo
 		return nil, err
 	}
 
-	err = c.connOpen()
-	if err != nil {
-		return nil, err
+	err = c.connOpen()
+	if err != nil {
+		return nil, err
+	}
+
+	prepareForAnnounce(desc)
+
+	if c.RecordOptions.SenderIdentity != nil && c.RecordOptions.SenderIdentity.LabelMedias {
+		for i, medi := range desc.Medias {
+			if medi.Label == "" {
+				medi.Label = c.RecordOptions.SenderIdentity.CNAME + "-" + strconv.FormatInt(int64(i), 10)
+			}
+		}
+	}
+
+	byts, err := desc.Marshal(false)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(&base.Request{
+		Method: base.Announce,
+		URL:    u,
+		Header: base.Header{
+			"Content-Type": base.HeaderValue{"application/sdp"},
+		},
+		Body: byts,
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != base.StatusOK {
+		return nil, liberrors.ErrClientBadStatusCode{
+			Code: res.StatusCode, Message: res.StatusMessage,
+		}
+	}
+
+	c.baseURL = u.Clone()
+	c.state = clientStatePreRecord
+	c.lastAnnounceDesc = desc
+
+	return res, nil
+}
+
+// Announce sends an ANNOUNCE request.
+func (c *Client) Announce(u *base.URL, desc *description.Session) (*base.Response, error) {
+	cres := make(chan clientRes)
+	select {
+	case c.chAnnounce <- announceReq{url: u, desc: desc, res: cres}:
+		res := <-cres
+		return res.res, res.err
+
+	case <-c.done:
+		return nil, c.closeError
+	}
+}
+
+// clientSetup is a SETUP request that has been prepared but not processed yet.
+type clientSetup struct {
+	baseURL          *base.URL
+	medi             *description.Media
+	cm               *clientMedia
+	desiredTransport Transport
+	tcpChannel       int
+	req              *base.Request
+}
+
+func (c *Client) doSetup(
+	baseURL *base.URL,
+	medi *description.Media,
+	rtpPort int,
+	rtcpPort int,
+) (*base.Response, error) {
+	s, err := c.prepareSetup(baseURL, medi, rtpPort, rtcpPort)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(s.req, false)
+	if err != nil {
+		s.cm.close()
+		return nil, err
+	}
+
+	return c.finishSetup(s, res)
+}
+
+// doSetupAll setups all the given medias by pipelining SETUP requests.
+// The first request is sent alone, in order to create the session
+// and to negotiate transport and authentication,
+// while the remaining ones are sent back-to-back without waiting for responses.
+func (c *Client) doSetupAll(baseURL *base.URL, medias []*description.Media) error {
+	if len(medias) == 0 {
+		return nil
+	}
+
+	_, err := c.doSetup(baseURL, medias[0], 0, 0)
+	if err != nil {
+		return err
+	}
+
+	medias = medias[1:]
+	if len(medias) == 0 {
+		return nil
+	}
+
+	c.pendingSetups = nil
+	defer func() {
+		c.pendingSetups = nil
+	}()
+
+	for _, medi := range medias {
+		var s *clientSetup
+		s, err = c.prepareSetup(baseURL, medi, 0, 0)
+		if err != nil {
+			break
+		}
+		c.pendingSetups = append(c.pendingSetups, s)
 	}
 
-	prepareForAnnounce(desc)
+	cseqs := make([]string, 0, len(c.pendingSetups))
 
-	byts, err := desc.Marshal(false)
-	if err != nil {
-		return nil, err
+	if err == nil {
+		for _, s := range c.pendingSetups {
+			_, err = c.do(s.req, true)
+			if err != nil {
+				break
+			}
+			cseqs = append(cseqs, s.req.Header["CSeq"][0])
+		}
 	}
 
-	res, err := c.do(&base.Request{
-		Method: base.Announce,
-		URL:    u,
-		Header: base.Header{
-			"Content-Type": base.HeaderValue{"application/sdp"},
-		},
-		Body: byts,
-	}, false)
-	if err != nil {
-		return nil, err
+	var ress []*base.Response
+
+	if err == nil {
+		ress, err = c.waitResponses(c.pendingSetups[0].req, cseqs)
+		if err != nil {
+			c.mustClose = true
+		}
 	}
 
-	if res.StatusCode != base.StatusOK {
-		return nil, liberrors.ErrClientBadStatusCode{
-			Code: res.StatusCode, Message: res.StatusMessage,
+	if err != nil {
+		for _, s := range c.pendingSetups {
+			s.cm.close()
 		}
+		return err
 	}
 
-	c.baseURL = u.Clone()
-	c.state = clientStatePreRecord
+	for _, res := range ress {
+		s := c.pendingSetups[0]
+		c.pendingSetups = c.pendingSetups[1:]
 
-	return res, nil
-}
+		if err == nil {
+			err = c.processResponse(s.req, res)
+		}
 
-// Announce sends an ANNOUNCE request.
-func (c *Client) Announce(u *base.URL, desc *description.Session) (*base.Response, error) {
-	cres := make(chan clientRes)
-	select {
-	case c.chAnnounce <- announceReq{url: u, desc: desc, res: cres}:
-		res := <-cres
-		return res.res, res.err
+		if err == nil && res.StatusCode != base.StatusOK {
+			err = liberrors.ErrClientBadStatusCode{Code: res.StatusCode, Message: res.StatusMessage}
+		}
 
-	case <-c.done:
-		return nil, c.closeError
+		if err != nil {
+			s.cm.close()
+			continue
+		}
+
+		_, err = c.finishSetup(s, res)
 	}
+
+	return err
 }
 
-func (c *Client) doSetup(
+// prepareSetup allocates resources of a media and builds its SETUP request.
+func (c *Client) prepareSetup(
 	baseURL *base.URL,
 	medi *description.Media,
 	rtpPort int,
 	rtcpPort int,
-) (*base.Response, error) {
+) (*clientSetup, error) {
 	err := c.checkState(map[clientState]struct{}{
 		clientStateInitial:   {},
 		clientStatePrePlay:   {},
@@ -1376,17 +2385,18 @@ func (c *Client) doSetup(
 	}
 
 	cm := &clientMedia{
-		c:     c,
-		media: medi,
+		c:        c,
+		media:    medi,
+		activity: c.MediaActivityOptions.MediaActivity(medi),
 	}
 	cm.initialize()
 
 	if c.effectiveTransport == nil {
-		if c.connURL.Scheme == "rtsps" { // always use TCP if encrypted
+		if c.connURL.Scheme == "rtsps" && c.TransportOptions.Transport == nil { // use TCP if encrypted
 			v := TransportTCP
 			c.effectiveTransport = &v
-		} else if c.Transport != nil { // take transport from config
-			c.effectiveTransport = c.Transport
+		} else if c.TransportOptions.Transport != nil { // take transport from config
+			c.effectiveTransport = c.TransportOptions.Transport
 		}
 	}
 
@@ -1397,6 +2407,13 @@ func (c *Client) doSetup(
 		desiredTransport = TransportUDP
 	}
 
+	if c.connURL.Scheme == "rtsps" && desiredTransport == TransportUDP &&
+		!c.SecurityOptions.UnencryptedMedia(medi) {
+		return nil, liberrors.ErrClientMediaMustBeEncrypted{}
+	}
+
+	tcpChannel := -1
+
 	switch desiredTransport {
 	case TransportUDP:
 		if (rtpPort == 0 && rtcpPort != 0) ||
@@ -1434,6 +2451,7 @@ func (c *Client) doSetup(
 		th.Protocol = headers.TransportProtocolTCP
 		ch := c.findFreeChannelPair()
 		th.InterleavedIDs = &[2]int{ch, ch + 1}
+		tcpChannel = ch
 	}
 
 	mediaURL, err := medi.URL(baseURL)
@@ -1450,26 +2468,38 @@ func (c *Client) doSetup(
 		header["Require"] = base.HeaderValue{"www.onvif.org/ver20/backchannel"}
 	}
 
-	res, err := c.do(&base.Request{
-		Method: base.Setup,
-		URL:    mediaURL,
-		Header: header,
-	}, false)
-	if err != nil {
-		cm.close()
-		return nil, err
-	}
+	return &clientSetup{
+		baseURL:          baseURL,
+		medi:             medi,
+		cm:               cm,
+		desiredTransport: desiredTransport,
+		tcpChannel:       tcpChannel,
+		req: &base.Request{
+			Method: base.Setup,
+			URL:    mediaURL,
+			Header: header,
+		},
+	}, nil
+}
+
+// finishSetup processes the response to a SETUP request.
+func (c *Client) finishSetup(s *clientSetup, res *base.Response) (*base.Response, error) {
+	baseURL := s.baseURL
+	medi := s.medi
+	cm := s.cm
+	desiredTransport := s.desiredTransport
+
+	var err error
 
 	if res.StatusCode != base.StatusOK {
 		cm.close()
 
 		// switch transport automatically
 		if res.StatusCode == base.StatusUnsupportedTransport &&
-			c.effectiveTransport == nil {
-			c.OnTransportSwitch(liberrors.ErrClientSwitchToTCP2{})
-			v := TransportTCP
-			c.effectiveTransport = &v
-			return c.doSetup(baseURL, medi, 0, 0)
+			desiredTransport == TransportUDP &&
+			c.TransportOptions.Transport == nil {
+			c.TransportOptions.OnTransportSwitch(TransportUDP, TransportTCP, liberrors.ErrClientSwitchToTCP2{})
+			return c.switchSessionToTCP(baseURL, medi)
 		}
 
 		return nil, liberrors.ErrClientBadStatusCode{Code: res.StatusCode, Message: res.StatusMessage}
@@ -1489,7 +2519,7 @@ func (c *Client) doSetup(
 
 			// switch transport automatically
 			if c.effectiveTransport == nil &&
-				c.Transport == nil {
+				c.TransportOptions.Transport == nil {
 				c.baseURL = baseURL
 				return c.trySwitchingProtocol2(medi, baseURL)
 			}
@@ -1507,20 +2537,27 @@ func (c *Client) doSetup(
 
 		serverPortsValid := thRes.ServerPorts != nil && !isAnyPort(thRes.ServerPorts[0]) && !isAnyPort(thRes.ServerPorts[1])
 
-		if (c.state == clientStatePreRecord || !c.AnyPortEnable) && !serverPortsValid {
+		if (c.state == clientStatePreRecord || !c.TransportOptions.AnyPortEnable) && !serverPortsValid {
 			cm.close()
+
+			// server is probably behind a NAT, switch transport automatically
+			if c.TransportOptions.Transport == nil {
+				c.TransportOptions.OnTransportSwitch(TransportUDP, TransportTCP, liberrors.ErrClientSwitchToTCP3{})
+				return c.switchSessionToTCP(baseURL, medi)
+			}
+
 			return nil, liberrors.ErrClientServerPortsNotProvided{}
 		}
 
 		var readIP net.IP
-		if thRes.Source != nil {
-			readIP = *thRes.Source
-		} else {
-			readIP = c.nconn.RemoteAddr().(*net.TCPAddr).IP
+		readIP, err = c.sourceIP(&thRes)
+		if err != nil {
+			cm.close()
+			return nil, err
 		}
 
 		if serverPortsValid {
-			if !c.AnyPortEnable {
+			if !c.TransportOptions.AnyPortEnable {
 				cm.udpRTPListener.readPort = thRes.ServerPorts[0]
 			}
 			cm.udpRTPListener.writeAddr = &net.UDPAddr{
@@ -1532,7 +2569,7 @@ func (c *Client) doSetup(
 		cm.udpRTPListener.readIP = readIP
 
 		if serverPortsValid {
-			if !c.AnyPortEnable {
+			if !c.TransportOptions.AnyPortEnable {
 				cm.udpRTCPListener.readPort = thRes.ServerPorts[1]
 			}
 			cm.udpRTCPListener.writeAddr = &net.UDPAddr{
@@ -1552,22 +2589,30 @@ func (c *Client) doSetup(
 			return nil, liberrors.ErrClientTransportHeaderNoPorts{}
 		}
 
-		if thRes.Destination == nil {
+		var destination net.IP
+		switch {
+		case thRes.Destination != nil:
+			destination = *thRes.Destination
+
+		// fallback to the media-level connection address of the SDP
+		case medi.Connection != nil && medi.Connection.IsMulticast():
+			destination = net.ParseIP(medi.Connection.Address)
+
+		default:
 			return nil, liberrors.ErrClientTransportHeaderNoDestination{}
 		}
 
 		var readIP net.IP
-		if thRes.Source != nil {
-			readIP = *thRes.Source
-		} else {
-			readIP = c.nconn.RemoteAddr().(*net.TCPAddr).IP
+		readIP, err = c.sourceIP(&thRes)
+		if err != nil {
+			return nil, err
 		}
 
 		err = cm.createUDPListeners(
 			true,
 			readIP,
-			net.JoinHostPort(thRes.Destination.String(), strconv.FormatInt(int64(thRes.Ports[0]), 10)),
-			net.JoinHostPort(thRes.Destination.String(), strconv.FormatInt(int64(thRes.Ports[1]), 10)),
+			net.JoinHostPort(destination.String(), strconv.FormatInt(int64(thRes.Ports[0]), 10)),
+			net.JoinHostPort(destination.String(), strconv.FormatInt(int64(thRes.Ports[1]), 10)),
 		)
 		if err != nil {
 			return nil, err
@@ -1576,14 +2621,14 @@ func (c *Client) doSetup(
 		cm.udpRTPListener.readIP = readIP
 		cm.udpRTPListener.readPort = thRes.Ports[0]
 		cm.udpRTPListener.writeAddr = &net.UDPAddr{
-			IP:   *thRes.Destination,
+			IP:   destination,
 			Port: thRes.Ports[0],
 		}
 
 		cm.udpRTCPListener.readIP = readIP
 		cm.udpRTCPListener.readPort = thRes.Ports[1]
 		cm.udpRTCPListener.writeAddr = &net.UDPAddr{
-			IP:   *thRes.Destination,
+			IP:   destination,
 			Port: thRes.Ports[1],
 		}
 
@@ -1617,6 +2662,7 @@ func (c *Client) doSetup(
 		c.setuppedMedias = make(map[*description.Media]*clientMedia)
 	}
 
+	cm.transport = desiredTransport
 	c.setuppedMedias[medi] = cm
 
 	c.baseURL = baseURL
@@ -1637,13 +2683,43 @@ func (c *Client) doSetup(
 
 func (c *Client) isChannelPairInUse(channel int) bool {
 	for _, cm := range c.setuppedMedias {
-		if (cm.tcpChannel+1) == channel || cm.tcpChannel == channel || cm.tcpChannel == (channel+1) {
+		if channelPairsOverlap(cm.tcpChannel, channel) {
+			return true
+		}
+	}
+	for _, s := range c.pendingSetups {
+		if s.tcpChannel >= 0 && channelPairsOverlap(s.tcpChannel, channel) {
 			return true
 		}
 	}
 	return false
 }
 
+func channelPairsOverlap(a int, b int) bool {
+	return (a+1) == b || a == b || a == (b+1)
+}
+
+// sourceIP returns the IP of the sender of packets.
+func (c *Client) sourceIP(th *headers.Transport) (net.IP, error) {
+	if th.Source != nil {
+		return *th.Source, nil
+	}
+
+	if th.SourceHost != "" {
+		ctx, ctxCancel := context.WithTimeout(c.ctx, c.TimeoutOptions.Read)
+		defer ctxCancel()
+
+		ip, err := lookupIP(ctx, c.LookupIPAddr, th.SourceHost)
+		if err != nil {
+			return nil, liberrors.ErrClientTransportHeaderInvalidSource{Err: err}
+		}
+
+		return ip, nil
+	}
+
+	return c.nconn.RemoteAddr().(*net.TCPAddr).IP, nil
+}
+
 func (c *Client) findFreeChannelPair() int {
 	for i := 0; ; i += 2 { // prefer even channels
 		if !c.isChannelPairInUse(i) {
@@ -1680,13 +2756,29 @@ func (c *Client) Setup(
 
 // SetupAll setups all the given medias.
 func (c *Client) SetupAll(baseURL *base.URL, medias []*description.Media) error {
-	for _, m := range medias {
-		_, err := c.Setup(baseURL, m, 0, 0)
-		if err != nil {
-			return err
+	if !c.TransportOptions.PipelineSetup {
+		for _, m := range medias {
+			_, err := c.Setup(baseURL, m, 0, 0)
+			if err != nil {
+				return err
+			}
 		}
+		return nil
+	}
+
+	cres := make(chan clientRes)
+	select {
+	case c.chSetupAll <- setupAllReq{
+		baseURL: baseURL,
+		medias:  medias,
+		res:     cres,
+	}:
+		res := <-cres
+		return res.err
+
+	case <-c.done:
+		return c.closeError
 	}
-	return nil
 }
 
 func (c *Client) doPlay(ra *headers.Range) (*base.Response, error) {
@@ -1697,6 +2789,12 @@ func (c *Client) doPlay(ra *headers.Range) (*base.Response, error) {
 		return nil, err
 	}
 
+	// discard end-of-stream signals of previous PLAY requests
+	select {
+	case <-c.chEndOfStream:
+	default:
+	}
+
 	c.state = clientStatePlay
 	c.startTransportRoutines()
 	c.createWriter()
@@ -1718,6 +2816,10 @@ func (c *Client) doPlay(ra *headers.Range) (*base.Response, error) {
 		header["Require"] = base.HeaderValue{"www.onvif.org/ver20/backchannel"}
 	}
 
+	if c.PlayOptions.Speed > 0 {
+		header["Speed"] = base.HeaderValue{strconv.FormatFloat(c.PlayOptions.Speed, 'f', -1, 64)}
+	}
+
 	res, err := c.do(&base.Request{
 		Method: base.Play,
 		URL:    c.baseURL,
@@ -1753,6 +2855,14 @@ func (c *Client) doPlay(ra *headers.Range) (*base.Response, error) {
 		}
 	}
 
+	// the stream is received faster or slower than real time,
+	// take it into account when computing timestamps.
+	c.playSpeed = newClientPlaySpeed(res)
+	c.timeDecoder.SetSpeed(c.playSpeed.Speed)
+	c.playStartTime = c.timeNow()
+	c.playDuration = playDuration(res)
+	c.endOfStreamReached = false
+
 	c.startWriter()
 
 	c.lastRange = ra
@@ -1760,6 +2870,24 @@ func (c *Client) doPlay(ra *headers.Range) (*base.Response, error) {
 	return res, nil
 }
 
+// playDuration returns the duration of the played range,
+// when the server provides its end time.
+func playDuration(res *base.Response) *time.Duration {
+	var ra headers.Range
+	err := ra.Unmarshal(res.Header["Range"])
+	if err != nil {
+		return nil
+	}
+
+	npt, ok := ra.Value.(*headers.RangeNPT)
+	if !ok || npt.End == nil || *npt.End < npt.Start {
+		return nil
+	}
+
+	d := *npt.End - npt.Start
+	return &d
+}
+
 // Play sends a PLAY request.
 // This can be called only after Setup().
 func (c *Client) Play(ra *headers.Range) (*base.Response, error) {
@@ -1919,6 +3047,65 @@ func (c *Client) OnPacketRTP(medi *description.Media, forma format.Format, cb On
 	ct.onPacketRTP = cb
 }
 
+// OnFrame sets a callback that is called when a frame is decoded from the RTP packets of a format.
+// Packets are decoded by the library and decoding results are counted in format statistics.
+// It replaces any callback set with OnPacketRTP() for the same format.
+func (c *Client) OnFrame(medi *description.Media, forma format.Format, cb OnFrameFunc) error {
+	decode, err := newFrameDecoder(forma)
+	if err != nil {
+		return err
+	}
+
+	c.onFrame(medi, forma, decode, cb)
+	return nil
+}
+
+func (c *Client) onFrame(medi *description.Media, forma format.Format, decode frameDecoder, cb OnFrameFunc) {
+	cm := c.setuppedMedias[medi]
+	ct := cm.formats[forma.PayloadType()]
+
+	ct.onPacketRTP = func(pkt *rtp.Packet) {
+		// decoders keep references to payloads of previous packets
+		cm.packets.retain(pkt)
+
+		payload, err := decode(pkt)
+		if err != nil {
+			// errors that tell that more packets are needed are not decode errors.
+			if isErrMorePacketsNeeded(err) {
+				atomic.AddUint64(ct.rtpPacketsDecoded, 1)
+			} else {
+				atomic.AddUint64(ct.rtpPacketsDecodeError, 1)
+				c.OnDecodeError(err)
+			}
+			return
+		}
+
+		atomic.AddUint64(ct.rtpPacketsDecoded, 1)
+
+		pts, ok := ct.decodePTS(pkt)
+		if !ok {
+			return
+		}
+
+		ntp, _ := c.PacketNTP(medi, pkt)
+
+		cb(&Frame{
+			Media:   medi,
+			Format:  forma,
+			PTS:     pts,
+			NTP:     ntp,
+			Payload: payload,
+		})
+	}
+}
+
+// RetainPacketRTP prevents a RTP packet from being reused after the OnPacketRTP callback returns.
+// It is needed only when MemoryOptions.PoolPackets is true, and must be called inside the callback.
+func (c *Client) RetainPacketRTP(medi *description.Media, pkt *rtp.Packet) {
+	cm := c.setuppedMedias[medi]
+	cm.packets.retain(pkt)
+}
+
 // OnPacketRTCP sets a callback that is called when a RTCP packet is read.
 func (c *Client) OnPacketRTCP(medi *description.Media, cb OnPacketRTCPFunc) {
 	cm := c.setuppedMedias[medi]
@@ -1926,6 +3113,11 @@ func (c *Client) OnPacketRTCP(medi *description.Media, cb OnPacketRTCPFunc) {
 }
 
 // WritePacketRTP writes a RTP packet to the server.
+//
+// WritePacketRTP, WritePacketsRTP and WritePacketRTCP can be called concurrently
+// from multiple goroutines. Writes to the same media are serialized,
+// therefore packets are sent in the same order in which calls are made,
+// while writes to different medias don't block each other.
 func (c *Client) WritePacketRTP(medi *description.Media, pkt *rtp.Packet) error {
 	return c.WritePacketRTPWithNTP(medi, pkt, c.timeNow())
 }
@@ -1933,12 +3125,51 @@ func (c *Client) WritePacketRTP(medi *description.Media, pkt *rtp.Packet) error
 // WritePacketRTPWithNTP writes a RTP packet to the server.
 // ntp is the absolute time of the packet, and is sent with periodic RTCP sender reports.
 func (c *Client) WritePacketRTPWithNTP(medi *description.Media, pkt *rtp.Packet, ntp time.Time) error {
-	byts := make([]byte, c.MaxPacketSize)
-	n, err := pkt.MarshalTo(byts)
-	if err != nil {
-		return err
+	return c.WritePacketsRTPWithNTP(medi, []*rtp.Packet{pkt}, ntp)
+}
+
+// WritePacketsRTP writes the RTP packets of an access unit to the server.
+// Packets are enqueued atomically: either all packets are sent or none of them.
+// Packets must belong to the same format.
+func (c *Client) WritePacketsRTP(medi *description.Media, pkts []*rtp.Packet) error {
+	return c.WritePacketsRTPWithNTP(medi, pkts, c.timeNow())
+}
+
+// WritePacketsRTPWithNTP writes the RTP packets of an access unit to the server.
+// Packets are enqueued atomically: either all packets are sent or none of them.
+// Packets must belong to the same format.
+// ntp is the absolute time of the access unit, and is sent with periodic RTCP sender reports.
+func (c *Client) WritePacketsRTPWithNTP(medi *description.Media, pkts []*rtp.Packet, ntp time.Time) error {
+	if len(pkts) == 0 {
+		return nil
+	}
+
+	exts := newMediaExtensions(medi)
+	now := c.timeNow()
+
+	byts := make([][]byte, len(pkts))
+	for i, pkt := range pkts {
+		if exts.enabled() {
+			var err error
+			pkt, err = exts.apply(pkt, now, ntp)
+			if err != nil {
+				return err
+			}
+		}
+
+		var buf []byte
+		if c.MemoryOptions.PoolPackets {
+			buf = getPacketBuffer(c.MaxPacketSize)
+		} else {
+			buf = make([]byte, c.MaxPacketSize)
+		}
+
+		n, err := pkt.MarshalTo(buf)
+		if err != nil {
+			return err
+		}
+		byts[i] = buf[:n]
 	}
-	byts = byts[:n]
 
 	select {
 	case <-c.done:
@@ -1954,14 +3185,35 @@ func (c *Client) WritePacketRTPWithNTP(medi *description.Media, pkt *rtp.Packet,
 	}
 
 	cm := c.setuppedMedias[medi]
-	cf := cm.formats[pkt.PayloadType]
+	cf := cm.formats[pkts[0].PayloadType]
 
-	cf.rtcpSender.ProcessPacketRTP(pkt, ntp, cf.format.PTSEqualsDTS(pkt))
+	// packets must reach the queue in the same order in which they are processed by the RTCP sender.
+	cm.writeMutex.Lock()
+	defer cm.writeMutex.Unlock()
 
-	ok := c.writer.push(func() error {
-		return cf.writePacketRTPInQueue(byts)
-	})
+	for _, pkt := range pkts {
+		cf.rtcpSender.ProcessPacketRTP(pkt, ntp, cf.format.PTSEqualsDTS(pkt))
+	}
+
+	// access units usually fit into this array, that is allocated on the stack.
+	var entriesBuf [16]interface{}
+	entries := entriesBuf[:0]
+
+	for _, b := range byts {
+		p := clientPacketPool.Get().(*clientPacket)
+		p.cf = cf
+		p.byts = b
+		p.pool = c.MemoryOptions.PoolPackets
+		entries = append(entries, p)
+	}
+
+	ok := c.writer.pushMultiple(entries)
 	if !ok {
+		for _, entry := range entries {
+			entry.(*clientPacket).put()
+		}
+
+		c.ObservabilityOptions.Metrics.AddPacketsDropped(uint64(len(pkts)))
 		return liberrors.ErrClientWriteQueueFull{}
 	}
 
@@ -1990,16 +3242,103 @@ func (c *Client) WritePacketRTCP(medi *description.Media, pkt rtcp.Packet) error
 
 	cm := c.setuppedMedias[medi]
 
+	cm.writeMutex.Lock()
+	defer cm.writeMutex.Unlock()
+
 	ok := c.writer.push(func() error {
 		return cm.writePacketRTCPInQueue(byts)
 	})
 	if !ok {
+		c.ObservabilityOptions.Metrics.AddPacketsDropped(1)
 		return liberrors.ErrClientWriteQueueFull{}
 	}
 
 	return nil
 }
 
+// StartPacketDump starts writing received and sent RTP and RTCP packets into w,
+// in the pcapng format, in order to debug interoperability issues.
+// Packets are written as UDP datagrams, that can be decoded by Wireshark as RTP or RTCP.
+// With the TCP transport, ports are set to the interleaved channels.
+// It can be called at any time, including while reading or publishing.
+func (c *Client) StartPacketDump(w io.Writer) error {
+	if c.packetDump.Load() != nil {
+		return fmt.Errorf("packet dump already started")
+	}
+
+	d, err := newPacketDump(w)
+	if err != nil {
+		return err
+	}
+
+	if !c.packetDump.CompareAndSwap(nil, d) {
+		return fmt.Errorf("packet dump already started")
+	}
+
+	return nil
+}
+
+// StopPacketDump stops writing packets into the writer passed to StartPacketDump().
+// It returns the first error that occurred while writing packets.
+func (c *Client) StopPacketDump() error {
+	d := c.packetDump.Swap(nil)
+	if d == nil {
+		return fmt.Errorf("packet dump not started")
+	}
+
+	return d.close()
+}
+
+// MediaTransports returns transport parameters of setupped medias.
+// Transport may change after setup when it is chosen automatically.
+func (c *Client) MediaTransports() map[*description.Media]MediaTransportInfo {
+	stats := c.Stats()
+	ret := make(map[*description.Media]MediaTransportInfo, len(c.setuppedMedias))
+
+	for medi, cm := range c.setuppedMedias {
+		info := MediaTransportInfo{
+			Transport: cm.transport,
+		}
+
+		switch cm.transport {
+		case TransportUDP, TransportUDPMulticast:
+			info.LocalRTPPort = cm.udpRTPListener.port()
+			info.LocalRTCPPort = cm.udpRTCPListener.port()
+			info.ReadBufferSize = cm.udpRTPListener.readBufferSize
+			info.WriteBufferSize = cm.udpRTPListener.writeBufferSize
+
+			// write addresses are not available when server ports are not provided
+			if cm.udpRTPListener.writeAddr != nil {
+				info.RemoteRTPPort = cm.udpRTPListener.writeAddr.Port
+				info.RemoteRTCPPort = cm.udpRTCPListener.writeAddr.Port
+
+				if cm.transport == TransportUDPMulticast {
+					info.MulticastGroup = cm.udpRTPListener.writeAddr.IP
+				}
+			}
+
+		default: // TCP
+			info.InterleavedIDs = &[2]int{cm.tcpChannel, cm.tcpChannel + 1}
+		}
+
+		info.fillSSRCs(stats.Session.Medias[medi])
+		ret[medi] = info
+	}
+
+	return ret
+}
+
+// DescribeURL returns the URL used by the last DESCRIBE request,
+// after following redirects.
+func (c *Client) DescribeURL() *base.URL {
+	return c.lastDescribeURL
+}
+
+// PlaySpeed returns the speed negotiated with the server during the last PLAY request.
+func (c *Client) PlaySpeed() ClientPlaySpeed {
+	return c.playSpeed
+}
+
 // PacketPTS returns the PTS of an incoming RTP packet.
 // It is computed by decoding the packet timestamp and sychronizing it with other tracks.
 //
@@ -2008,7 +3347,7 @@ func (c *Client) PacketPTS(medi *description.Media, pkt *rtp.Packet) (time.Durat
 	cm := c.setuppedMedias[medi]
 	ct := cm.formats[pkt.PayloadType]
 
-	v, ok := c.timeDecoder.Decode(ct.format, pkt)
+	v, ok := ct.decodePTS(pkt)
 	if !ok {
 		return 0, false
 	}
@@ -2021,19 +3360,90 @@ func (c *Client) PacketPTS(medi *description.Media, pkt *rtp.Packet) (time.Durat
 func (c *Client) PacketPTS2(medi *description.Media, pkt *rtp.Packet) (int64, bool) {
 	cm := c.setuppedMedias[medi]
 	ct := cm.formats[pkt.PayloadType]
-	return c.timeDecoder.Decode(ct.format, pkt)
+	return ct.decodePTS(pkt)
+}
+
+// PacketDTS returns the DTS of an incoming RTP packet.
+// With H264 and H265, DTS is estimated from the picture order count, in order to support B-frames;
+// this must be called with every packet of the format, in order,
+// and DTS is returned with the last packet of each access unit only.
+// With other formats, DTS is equal to PTS.
+func (c *Client) PacketDTS(medi *description.Media, pkt *rtp.Packet) (int64, bool) {
+	cm := c.setuppedMedias[medi]
+	ct := cm.formats[pkt.PayloadType]
+
+	if ct.dtsEstimator == nil {
+		return 0, false
+	}
+
+	pts, ok := ct.decodePTS(pkt)
+	if !ok {
+		return 0, false
+	}
+
+	return ct.dtsEstimator.Estimate(pkt, pts)
+}
+
+// AccessUnitDTS returns the DTS of an access unit that has been decoded from incoming RTP packets,
+// where pkt is the last packet of the access unit.
+// It allows to avoid decoding packets twice when the caller already decodes them.
+// With H264 and H265, this must be called with every access unit of the format, in order,
+// and must not be used together with PacketDTS.
+// With other formats, DTS is equal to PTS.
+func (c *Client) AccessUnitDTS(medi *description.Media, pkt *rtp.Packet, au [][]byte) (int64, bool) {
+	cm := c.setuppedMedias[medi]
+	ct := cm.formats[pkt.PayloadType]
+
+	if ct.dtsEstimator == nil {
+		return 0, false
+	}
+
+	pts, ok := ct.decodePTS(pkt)
+	if !ok {
+		return 0, false
+	}
+
+	return ct.dtsEstimator.EstimateAccessUnit(au, pts)
 }
 
 // PacketNTP returns the NTP timestamp of an incoming RTP packet.
-// The NTP timestamp is computed from RTCP sender reports.
+// The NTP timestamp is read from the ONVIF replay extension, if present,
+// otherwise it is computed from RTCP sender reports.
 func (c *Client) PacketNTP(medi *description.Media, pkt *rtp.Packet) (time.Time, bool) {
+	if ntp, ok := packetNTPFromExtension(pkt); ok {
+		return ntp, true
+	}
+
 	cm := c.setuppedMedias[medi]
 	ct := cm.formats[pkt.PayloadType]
 	return ct.rtcpReceiver.PacketNTP(pkt.Timestamp)
 }
 
+// PacketAbsSendTime returns the send time of an incoming RTP packet,
+// read from the abs-send-time extension.
+// The extension must be declared in the media with an extmap attribute.
+func (c *Client) PacketAbsSendTime(medi *description.Media, pkt *rtp.Packet) (time.Time, bool) {
+	return packetAbsSendTime(medi, pkt, c.timeNow())
+}
+
+// PacketTransmissionOffset returns the transmission time offset of an incoming RTP packet,
+// in clock rate units, read from the toffset extension.
+// The extension must be declared in the media with an extmap attribute.
+func (c *Client) PacketTransmissionOffset(medi *description.Media, pkt *rtp.Packet) (int32, bool) {
+	return packetTransmissionOffset(medi, pkt)
+}
+
 // Stats returns client statistics.
+// It is equivalent to StatsSnapshot().
 func (c *Client) Stats() *ClientStats {
+	return c.StatsSnapshot()
+}
+
+// StatsSnapshot returns a snapshot of client statistics.
+// Counters are updated atomically by packet paths, that never wait for readers,
+// therefore it can be called periodically without slowing down packet processing.
+// See StatsSession for the consistency model.
+func (c *Client) StatsSnapshot() *ClientStats {
 	return &ClientStats{
 		Conn: StatsConn{
 			BytesReceived: atomic.LoadUint64(c.bytesReceived),
@@ -2131,14 +3541,27 @@ func (c *Client) Stats() *ClientStats {
 			Medias: func() map[*description.Media]StatsSessionMedia { //nolint:dupl
 				ret := make(map[*description.Media]StatsSessionMedia, len(c.setuppedMedias))
 
+				now := c.timeNow()
+
 				for med, sm := range c.setuppedMedias {
+					received := sm.rates.received.Rates(now)
+					sent := sm.rates.sent.Rates(now)
+
 					ret[med] = StatsSessionMedia{
-						BytesReceived:       atomic.LoadUint64(sm.bytesReceived),
-						BytesSent:           atomic.LoadUint64(sm.bytesSent),
-						RTPPacketsInError:   atomic.LoadUint64(sm.rtpPacketsInError),
-						RTCPPacketsReceived: atomic.LoadUint64(sm.rtcpPacketsReceived),
-						RTCPPacketsSent:     atomic.LoadUint64(sm.rtcpPacketsSent),
-						RTCPPacketsInError:  atomic.LoadUint64(sm.rtcpPacketsInError),
+						BytesReceived:         atomic.LoadUint64(sm.bytesReceived),
+						BytesSent:             atomic.LoadUint64(sm.bytesSent),
+						RTPPacketsInError:     atomic.LoadUint64(sm.rtpPacketsInError),
+						RTCPPacketsReceived:   atomic.LoadUint64(sm.rtcpPacketsReceived),
+						RTCPPacketsSent:       atomic.LoadUint64(sm.rtcpPacketsSent),
+						RTCPPacketsInError:    atomic.LoadUint64(sm.rtcpPacketsInError),
+						RTCPOnRTPPort:         atomic.LoadInt32(sm.rtcpOnRTPPort) == 1,
+						UDPPacketsTruncated:   atomic.LoadUint64(sm.udpPacketsTruncated),
+						RTPBitrateReceived:    received.Bitrate,
+						RTPBitrateSent:        sent.Bitrate,
+						RTPPacketRateReceived: received.PacketRate,
+						RTPPacketRateSent:     sent.PacketRate,
+						FrameRateReceived:     received.FrameRate,
+						FrameRateSent:         sent.FrameRate,
 						Formats: func() map[format.Format]StatsSessionFormat {
 							ret := make(map[format.Format]StatsSessionFormat, len(sm.formats))
 
@@ -2156,10 +3579,17 @@ func (c *Client) Stats() *ClientStats {
 									return nil
 								}()
 
+								remoteCNAME, remoteName := fo.remoteSDES.stats(med, recvStats)
+
 								ret[fo.format] = StatsSessionFormat{ //nolint:dupl
-									RTPPacketsReceived: atomic.LoadUint64(fo.rtpPacketsReceived),
-									RTPPacketsSent:     atomic.LoadUint64(fo.rtpPacketsSent),
-									RTPPacketsLost:     atomic.LoadUint64(fo.rtpPacketsLost),
+									RTPPacketsReceived:     atomic.LoadUint64(fo.rtpPacketsReceived),
+									RTPPacketsSent:         atomic.LoadUint64(fo.rtpPacketsSent),
+									RTPPacketsLost:         atomic.LoadUint64(fo.rtpPacketsLost),
+									RTPPacketsInError:      atomic.LoadUint64(fo.rtpPacketsInError),
+									RTPPacketsDecoded:      atomic.LoadUint64(fo.rtpPacketsDecoded),
+									RTPPacketsDecodeErrors: atomic.LoadUint64(fo.rtpPacketsDecodeError),
+									AccessUnitsReceived:    atomic.LoadUint64(fo.accessUnitsReceived),
+									AccessUnitsCorrupted:   atomic.LoadUint64(fo.accessUnitsCorrupted),
 									LocalSSRC: func() uint32 {
 										if fo.rtcpReceiver != nil {
 											return *fo.rtcpReceiver.LocalSSRC
@@ -2175,6 +3605,8 @@ func (c *Client) Stats() *ClientStats {
 										}
 										return 0
 									}(),
+									RemoteCNAME: remoteCNAME,
+									RemoteName:  remoteName,
 									RTPPacketsLastSequenceNumber: func() uint16 {
 										if recvStats != nil {
 											return recvStats.LastSequenceNumber
@@ -0,0 +1,94 @@
+package gortsplib
+
+import (
+	"strings"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+)
+
+// ClientServerVendor is the vendor of a server, detected from the Server header.
+type ClientServerVendor string
+
+// vendors.
+const (
+	ClientServerVendorUnknown   ClientServerVendor = ""
+	ClientServerVendorGortsplib ClientServerVendor = "gortsplib"
+	ClientServerVendorGStreamer ClientServerVendor = "gstreamer"
+	ClientServerVendorLive555   ClientServerVendor = "live555"
+	ClientServerVendorWowza     ClientServerVendor = "wowza"
+	ClientServerVendorDarwin    ClientServerVendor = "darwin"
+	ClientServerVendorHikvision ClientServerVendor = "hikvision"
+	ClientServerVendorDahua     ClientServerVendor = "dahua"
+	ClientServerVendorAxis      ClientServerVendor = "axis"
+	ClientServerVendorHappytime ClientServerVendor = "happytime"
+)
+
+// substrings of the Server header, in lower case, that identify a vendor.
+var clientServerVendorSignatures = []struct {
+	signature string
+	vendor    ClientServerVendor
+}{
+	{"gortsplib", ClientServerVendorGortsplib},
+	{"mediamtx", ClientServerVendorGortsplib},
+	{"gstreamer", ClientServerVendorGStreamer},
+	{"live555", ClientServerVendorLive555},
+	{"wowza", ClientServerVendorWowza},
+	{"darwin", ClientServerVendorDarwin},
+	{"hikvision", ClientServerVendorHikvision},
+	{"dahua", ClientServerVendorDahua},
+	{"axis", ClientServerVendorAxis},
+	{"happytime", ClientServerVendorHappytime},
+}
+
+func detectServerVendor(server string) ClientServerVendor {
+	server = strings.ToLower(server)
+
+	for _, s := range clientServerVendorSignatures {
+		if strings.Contains(server, s.signature) {
+			return s.vendor
+		}
+	}
+
+	return ClientServerVendorUnknown
+}
+
+// ClientCapabilities contains the capabilities of a server,
+// obtained from the response to an OPTIONS request.
+type ClientCapabilities struct {
+	// methods listed in the Public header.
+	Methods []base.Method
+	// value of the Server header.
+	Server string
+	// vendor detected from the Server header.
+	Vendor ClientServerVendor
+}
+
+func newClientCapabilities(header base.Header) *ClientCapabilities {
+	ret := &ClientCapabilities{}
+
+	if pub, ok := header["Public"]; ok && len(pub) == 1 {
+		for _, m := range strings.Split(pub[0], ",") {
+			m = strings.Trim(m, " ")
+			if m != "" {
+				ret.Methods = append(ret.Methods, base.Method(m))
+			}
+		}
+	}
+
+	if server, ok := header["Server"]; ok && len(server) == 1 {
+		ret.Server = server[0]
+		ret.Vendor = detectServerVendor(ret.Server)
+	}
+
+	return ret
+}
+
+// Supports returns whether a method is listed in the Public header.
+func (c *ClientCapabilities) Supports(m base.Method) bool {
+	for _, m2 := range c.Methods {
+		if m2 == m {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package gortsplib
+
+import (
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+)
+
+// ClientClockSync allows to use a RTSP parameter as clock source,
+// in order to compute NTP timestamps of incoming packets when RTCP sender reports are absent.
+// Some devices (i.e. Axis and ONVIF cameras) expose the absolute time of the stream
+// with GET_PARAMETER responses or SET_PARAMETER requests.
+type ClientClockSync struct {
+	// name of the parameter that contains the absolute time of the stream.
+	Parameter string
+	// period of GET_PARAMETER requests.
+	// It defaults to 10 seconds.
+	Period time.Duration
+	// function that decodes the parameter value.
+	// It defaults to a RFC3339 decoder.
+	Decode func(string) (time.Time, error)
+}
+
+func (c *Client) processClockSyncParameters(byts []byte, system time.Time) {
+	var v string
+	found := false
+
+	for _, p := range unmarshalParameters(byts) {
+		if p.Name == c.PlayOptions.ClockSync.Parameter && p.Value != "" {
+			v = p.Value
+			found = true
+		}
+	}
+
+	if !found {
+		return
+	}
+
+	ntp, err := c.PlayOptions.ClockSync.Decode(v)
+	if err != nil {
+		c.OnDecodeError(err)
+		return
+	}
+
+	for _, cm := range c.setuppedMedias {
+		for _, cf := range cm.formats {
+			if cf.rtcpReceiver != nil {
+				cf.rtcpReceiver.ProcessClockSync(ntp, system)
+			}
+		}
+	}
+}
+
+func (c *Client) doClockSync() error {
+	res, err := c.do(&base.Request{
+		Method: base.GetParameter,
+		URL:    c.baseURL,
+		Header: base.Header{
+			"Content-Type": base.HeaderValue{"text/parameters"},
+		},
+		Body: []byte(c.PlayOptions.ClockSync.Parameter + "\r\n"),
+	}, false)
+	if err != nil {
+		return err
+	}
+
+	// devices that do not support the parameter are not considered faulty.
+	if res.StatusCode == base.StatusOK {
+		c.processClockSyncParameters(res.Body, c.timeNow())
+	}
+
+	return nil
+}
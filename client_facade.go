@@ -0,0 +1,269 @@
+package gortsplib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
+)
+
+const (
+	facadeRetryPause = 2 * time.Second
+)
+
+type publishFormat struct {
+	encode      frameEncoder
+	randomStart uint32
+}
+
+// FacadeErrorFunc is the prototype of the error callback of Publish() and Subscribe().
+type FacadeErrorFunc func(err error)
+
+func defaultFacadeOnError(err error) {
+	stdLogger{}.Warn(err.Error())
+}
+
+// Publish connects to a server and publishes the frames provided by source.
+// Frames are packetized with the encoder of their format.
+// In case of connection errors, onError is called, the connection is re-established automatically,
+// and frames read while disconnected are discarded.
+// If onError is nil, errors are written to the standard logger.
+// It returns when source returns an error or when ctx is canceled.
+// ctx is checked between frames, therefore source should return
+// frames periodically or an error when ctx is canceled.
+func Publish(
+	ctx context.Context,
+	url string,
+	desc *description.Session,
+	source FrameSource,
+	onError FacadeErrorFunc,
+) error {
+	if onError == nil {
+		onError = defaultFacadeOnError
+	}
+
+	formats := make(map[format.Format]*publishFormat)
+
+	for _, medi := range desc.Medias {
+		for _, forma := range medi.Formats {
+			encode, err := newFrameEncoder(forma)
+			if err != nil {
+				return err
+			}
+
+			randomStart, err := randUint32()
+			if err != nil {
+				return err
+			}
+
+			formats[forma] = &publishFormat{
+				encode:      encode,
+				randomStart: randomStart,
+			}
+		}
+	}
+
+	var c *Client
+	var lastAttempt time.Time
+
+	defer func() {
+		if c != nil {
+			c.Close()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		frame, err := source.ReadFrame()
+		if err != nil {
+			return err
+		}
+
+		pf, ok := formats[frame.Format]
+		if !ok {
+			return fmt.Errorf("frame format not found in the stream description")
+		}
+
+		pkts, err := pf.encode(frame.Payload)
+		if err != nil {
+			return err
+		}
+
+		if c == nil {
+			if time.Since(lastAttempt) < facadeRetryPause {
+				continue
+			}
+			lastAttempt = time.Now()
+
+			c = &Client{}
+			err = c.StartRecording(url, desc)
+			if err != nil {
+				c = nil
+				onError(err)
+				continue
+			}
+		}
+
+		for _, pkt := range pkts {
+			pkt.Timestamp = pf.randomStart + uint32(frame.PTS)
+
+			if frame.NTP.IsZero() {
+				err = c.WritePacketRTP(frame.Media, pkt)
+			} else {
+				err = c.WritePacketRTPWithNTP(frame.Media, pkt, frame.NTP)
+			}
+
+			if err != nil {
+				var eqf liberrors.ErrClientWriteQueueFull
+				if errors.As(err, &eqf) {
+					continue
+				}
+
+				onError(err)
+				c.Close()
+				c = nil
+				break
+			}
+		}
+	}
+}
+
+type errSubscribeSink struct {
+	err error
+}
+
+func (e errSubscribeSink) Error() string {
+	return e.err.Error()
+}
+
+// Subscribe connects to a server, reads all the media streams with a supported format,
+// and passes depacketized frames to sink.
+// Incomplete frames are discarded.
+// In case of connection errors, onError is called and the connection is re-established automatically.
+// If onError is nil, errors are written to the standard logger.
+// It returns when sink returns an error or when ctx is canceled.
+func Subscribe(ctx context.Context, url string, sink FrameSink, onError FacadeErrorFunc) error {
+	if onError == nil {
+		onError = defaultFacadeOnError
+	}
+
+	u, err := base.ParseURL(url)
+	if err != nil {
+		return err
+	}
+
+	for {
+		err = subscribeOnce(ctx, u, sink)
+
+		var es errSubscribeSink
+		if errors.As(err, &es) {
+			return es.err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		onError(err)
+
+		select {
+		case <-time.After(facadeRetryPause):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func subscribeOnce(ctx context.Context, u *base.URL, sink FrameSink) error {
+	c := Client{}
+
+	err := c.Start(u.Scheme, u.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// interrupt pending requests and reading when ctx is canceled.
+	stop := context.AfterFunc(ctx, c.Close)
+	defer stop()
+
+	desc, _, err := c.Describe(u)
+	if err != nil {
+		return err
+	}
+
+	chSinkErr := make(chan error, 1)
+	var sinkFailed int32
+	setupped := false
+
+	for _, medi := range desc.Medias {
+		decoders := make(map[format.Format]frameDecoder)
+
+		for _, forma := range medi.Formats {
+			decode, err := newFrameDecoder(forma)
+			if err == nil {
+				decoders[forma] = decode
+			}
+		}
+
+		if len(decoders) == 0 {
+			continue
+		}
+
+		_, err = c.Setup(desc.BaseURL, medi, 0, 0)
+		if err != nil {
+			return err
+		}
+		setupped = true
+
+		for forma, decode := range decoders {
+			cmedi := medi
+			cforma := forma
+			cdecode := decode
+
+			c.onFrame(cmedi, cforma, cdecode, func(fr *Frame) {
+				if atomic.LoadInt32(&sinkFailed) != 0 {
+					return
+				}
+
+				err := sink.WriteFrame(fr)
+				if err != nil && atomic.CompareAndSwapInt32(&sinkFailed, 0, 1) {
+					chSinkErr <- err
+				}
+			})
+		}
+	}
+
+	if !setupped {
+		return fmt.Errorf("no supported formats found")
+	}
+
+	_, err = c.Play(nil)
+	if err != nil {
+		return err
+	}
+
+	chWait := make(chan error, 1)
+	go func() {
+		chWait <- c.Wait()
+	}()
+
+	select {
+	case err := <-chWait:
+		return err
+
+	case err := <-chSinkErr:
+		return errSubscribeSink{err}
+	}
+}
@@ -0,0 +1,212 @@
+package gortsplib
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+type testFrameSource struct {
+	desc      *description.Session
+	terminate chan struct{}
+	pts       int64
+}
+
+func (s *testFrameSource) ReadFrame() (*Frame, error) {
+	select {
+	case <-time.After(10 * time.Millisecond):
+	case <-s.terminate:
+		return nil, fmt.Errorf("terminated")
+	}
+
+	s.pts += 3000
+
+	return &Frame{
+		Media:   s.desc.Medias[0],
+		Format:  s.desc.Medias[0].Formats[0],
+		PTS:     s.pts,
+		Payload: [][]byte{{0x05, 0x01, 0x02, 0x03, 0x04}},
+	}, nil
+}
+
+type testFrameSink struct {
+	chFrame chan *Frame
+}
+
+func (s *testFrameSink) WriteFrame(frame *Frame) error {
+	s.chFrame <- frame
+	return fmt.Errorf("terminated")
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	var mutex sync.Mutex
+	var stream *ServerStream
+	recording := make(chan struct{})
+
+	var s *Server
+	s = &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(ctx *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				stream = NewServerStream(s, ctx.Description)
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				if stream == nil {
+					return &base.Response{
+						StatusCode: base.StatusNotFound,
+					}, nil, nil
+				}
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				if ctx.Session.State() == ServerSessionStatePreRecord {
+					return &base.Response{
+						StatusCode: base.StatusOK,
+					}, nil, nil
+				}
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				ctx.Session.OnPacketRTPAny(func(medi *description.Media, _ format.Format, pkt *rtp.Packet) {
+					err := stream.WritePacketRTP(medi, pkt)
+					require.NoError(t, err)
+				})
+
+				close(recording)
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	defer func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if stream != nil {
+			stream.Close()
+		}
+	}()
+
+	desc := &description.Session{
+		Medias: []*description.Media{{
+			Type: description.MediaTypeVideo,
+			Formats: []format.Format{&format.H264{
+				PayloadTyp:        96,
+				PacketizationMode: 1,
+			}},
+		}},
+	}
+
+	source := &testFrameSource{
+		desc:      desc,
+		terminate: make(chan struct{}),
+	}
+
+	publishDone := make(chan error)
+	go func() {
+		publishDone <- Publish(context.Background(), "rtsp://localhost:8554/teststream", desc, source, nil)
+	}()
+
+	defer func() {
+		close(source.terminate)
+		err := <-publishDone
+		require.EqualError(t, err, "terminated")
+	}()
+
+	<-recording
+
+	sink := &testFrameSink{
+		chFrame: make(chan *Frame, 1),
+	}
+
+	err = Subscribe(context.Background(), "rtsp://localhost:8554/teststream", sink, nil)
+	require.EqualError(t, err, "terminated")
+
+	frame := <-sink.chFrame
+	require.Equal(t, [][]byte{{0x05, 0x01, 0x02, 0x03, 0x04}}, frame.Payload)
+	require.IsType(t, &format.H264{}, frame.Format)
+	require.Equal(t, description.MediaTypeVideo, frame.Media.Type)
+}
+
+func TestSubscribeContext(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	var errs []error
+
+	err := Subscribe(ctx, "rtsp://localhost:8554/teststream", &testFrameSink{}, func(err error) {
+		errs = append(errs, err)
+		ctxCancel()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, errs, 1)
+}
+
+func TestPublishContext(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	desc := &description.Session{
+		Medias: []*description.Media{{
+			Type: description.MediaTypeVideo,
+			Formats: []format.Format{&format.H264{
+				PayloadTyp:        96,
+				PacketizationMode: 1,
+			}},
+		}},
+	}
+
+	source := &testFrameSource{
+		desc:      desc,
+		terminate: make(chan struct{}),
+	}
+
+	var errs []error
+
+	err := Publish(ctx, "rtsp://localhost:8554/teststream", desc, source, func(err error) {
+		errs = append(errs, err)
+		ctxCancel()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, errs, 1)
+}
@@ -1,16 +1,19 @@
 package gortsplib
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 
+	"github.com/bluenviron/gortsplib/v4/internal/dtsestimator"
 	"github.com/bluenviron/gortsplib/v4/internal/rtcpreceiver"
 	"github.com/bluenviron/gortsplib/v4/internal/rtcpsender"
 	"github.com/bluenviron/gortsplib/v4/internal/rtplossdetector"
 	"github.com/bluenviron/gortsplib/v4/internal/rtpreorderer"
+	"github.com/bluenviron/gortsplib/v4/pkg/corruptiondetector"
 	"github.com/bluenviron/gortsplib/v4/pkg/format"
 	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
 )
@@ -23,17 +26,75 @@ type clientFormat struct {
 	udpReorderer          *rtpreorderer.Reorderer       // play
 	tcpLossDetector       *rtplossdetector.LossDetector // play
 	rtcpReceiver          *rtcpreceiver.RTCPReceiver    // play
+	dtsEstimator          *dtsestimator.Estimator       // play
+	ptsPacket             *rtp.Packet                   // play
+	ptsSequenceNumber     uint16                        // play
+	pts                   int64                         // play
+	ptsOK                 bool                          // play
 	rtcpSender            *rtcpsender.RTCPSender        // record or back channel
 	writePacketRTPInQueue func([]byte) error
 	rtpPacketsReceived    *uint64
 	rtpPacketsSent        *uint64
 	rtpPacketsLost        *uint64
+	rtpPacketsInError     *uint64
+	rtpPacketsDecoded     *uint64
+	rtpPacketsDecodeError *uint64
+	accessUnitsReceived   *uint64
+	accessUnitsCorrupted  *uint64
+	corruptionDetector    *corruptiondetector.Detector // play
+	remoteSDES            sourceDescription
+}
+
+// clientPacket is a RTP packet in the write queue of a client.
+// It is taken from a pool, in order to avoid allocating a closure for each packet.
+type clientPacket struct {
+	cf   *clientFormat
+	byts []byte
+	pool bool
+}
+
+var clientPacketPool = sync.Pool{
+	New: func() interface{} {
+		return &clientPacket{}
+	},
+}
+
+func (p *clientPacket) put() {
+	*p = clientPacket{}
+	clientPacketPool.Put(p)
+}
+
+func (p *clientPacket) run() error {
+	cf, byts, pool := p.cf, p.byts, p.pool
+	p.put()
+
+	if pool {
+		defer putPacketBuffer(byts)
+	}
+	return cf.writePacketRTPInQueue(byts)
 }
 
 func (cf *clientFormat) initialize() {
 	cf.rtpPacketsReceived = new(uint64)
 	cf.rtpPacketsSent = new(uint64)
 	cf.rtpPacketsLost = new(uint64)
+	cf.rtpPacketsInError = new(uint64)
+	cf.rtpPacketsDecoded = new(uint64)
+	cf.rtpPacketsDecodeError = new(uint64)
+	cf.accessUnitsReceived = new(uint64)
+	cf.accessUnitsCorrupted = new(uint64)
+}
+
+// decodePTS decodes the PTS of an incoming RTP packet.
+// The result of the last packet is cached, in order to decode packets once
+// when both PacketPTS2 and PacketDTS are called.
+func (cf *clientFormat) decodePTS(pkt *rtp.Packet) (int64, bool) {
+	if pkt != cf.ptsPacket || pkt.SequenceNumber != cf.ptsSequenceNumber {
+		cf.pts, cf.ptsOK = cf.cm.c.timeDecoder.Decode(cf.format, pkt)
+		cf.ptsPacket = pkt
+		cf.ptsSequenceNumber = pkt.SequenceNumber
+	}
+	return cf.pts, cf.ptsOK
 }
 
 func (cf *clientFormat) start() {
@@ -54,10 +115,16 @@ func (cf *clientFormat) start() {
 				}
 			},
 		}
+		if cf.cm.c.RecordOptions.SenderIdentity != nil {
+			cf.rtcpSender.CNAME = cf.cm.c.RecordOptions.SenderIdentity.CNAME
+			cf.rtcpSender.Name = cf.cm.c.RecordOptions.SenderIdentity.Name
+		}
 		cf.rtcpSender.Initialize()
 	} else {
 		if cf.cm.udpRTPListener != nil {
-			cf.udpReorderer = &rtpreorderer.Reorderer{}
+			cf.udpReorderer = &rtpreorderer.Reorderer{
+				OnDiscard: cf.cm.packets.release,
+			}
 			cf.udpReorderer.Initialize()
 		} else {
 			cf.tcpLossDetector = &rtplossdetector.LossDetector{}
@@ -77,6 +144,39 @@ func (cf *clientFormat) start() {
 		if err != nil {
 			panic(err)
 		}
+
+		cf.dtsEstimator = &dtsestimator.Estimator{
+			Format:       cf.format,
+			CopyPayloads: cf.cm.c.MemoryOptions.PoolPackets,
+		}
+		err = cf.dtsEstimator.Initialize()
+		if err != nil {
+			cf.dtsEstimator = nil
+		}
+
+		if cf.cm.c.PlayOptions.DetectCorruption {
+			cf.corruptionDetector = &corruptiondetector.Detector{
+				Format: cf.format,
+				OnAccessUnit: func(_ uint32, corrupted bool) {
+					atomic.AddUint64(cf.accessUnitsReceived, 1)
+					if corrupted {
+						atomic.AddUint64(cf.accessUnitsCorrupted, 1)
+					}
+				},
+				OnGOP: func(st corruptiondetector.GOPStats) {
+					if st.CorruptedAccessUnits != 0 {
+						cf.cm.c.OnDecodeError(liberrors.ErrClientCorruptedGOP{
+							AccessUnits:          st.AccessUnits,
+							CorruptedAccessUnits: st.CorruptedAccessUnits,
+						})
+					}
+				},
+			}
+			err = cf.corruptionDetector.Initialize()
+			if err != nil {
+				cf.corruptionDetector = nil
+			}
+		}
 	}
 }
 
@@ -118,19 +218,28 @@ func (cf *clientFormat) readPacketRTPTCP(pkt *rtp.Packet) {
 }
 
 func (cf *clientFormat) handlePacketRTP(pkt *rtp.Packet, now time.Time) {
+	defer cf.cm.packets.release(pkt)
+
 	err := cf.rtcpReceiver.ProcessPacketRTP(pkt, now, cf.format.PTSEqualsDTS(pkt))
 	if err != nil {
+		atomic.AddUint64(cf.rtpPacketsInError, 1)
 		cf.cm.onPacketRTPDecodeError(err)
 		return
 	}
 
 	atomic.AddUint64(cf.rtpPacketsReceived, 1)
+	cf.cm.rates.addReceived(now, pkt)
+
+	if cf.corruptionDetector != nil {
+		cf.corruptionDetector.Process(pkt)
+	}
 
 	cf.onPacketRTP(pkt)
 }
 
 func (cf *clientFormat) onPacketRTPLost(lost uint) {
 	atomic.AddUint64(cf.rtpPacketsLost, uint64(lost))
+	cf.cm.c.ObservabilityOptions.Metrics.AddErrors(MetricsErrorTypeRTPPacketsLost, uint64(lost))
 	cf.cm.c.OnPacketLost(liberrors.ErrClientRTPPacketsLost{Lost: lost})
 }
 
@@ -140,21 +249,29 @@ func (cf *clientFormat) writePacketRTPInQueueUDP(payload []byte) error {
 		return err
 	}
 
+	cf.cm.dumpSent(false, payload)
+
 	atomic.AddUint64(cf.cm.bytesSent, uint64(len(payload)))
+	cf.cm.c.ObservabilityOptions.Metrics.AddBytesSent(uint64(len(payload)))
 	atomic.AddUint64(cf.rtpPacketsSent, 1)
+	cf.cm.rates.addSent(cf.cm.c.timeNow(), payload)
 	return nil
 }
 
 func (cf *clientFormat) writePacketRTPInQueueTCP(payload []byte) error {
 	cf.cm.c.tcpFrame.Channel = cf.cm.tcpChannel
 	cf.cm.c.tcpFrame.Payload = payload
-	cf.cm.c.nconn.SetWriteDeadline(time.Now().Add(cf.cm.c.WriteTimeout))
+	cf.cm.c.nconn.SetWriteDeadline(time.Now().Add(cf.cm.c.TimeoutOptions.Write))
 	err := cf.cm.c.conn.WriteInterleavedFrame(cf.cm.c.tcpFrame, cf.cm.c.tcpBuffer)
 	if err != nil {
 		return err
 	}
 
+	cf.cm.dumpSent(false, payload)
+
 	atomic.AddUint64(cf.cm.bytesSent, uint64(len(payload)))
+	cf.cm.c.ObservabilityOptions.Metrics.AddBytesSent(uint64(len(payload)))
 	atomic.AddUint64(cf.rtpPacketsSent, 1)
+	cf.cm.rates.addSent(cf.cm.c.timeNow(), payload)
 	return nil
 }
@@ -2,6 +2,7 @@ package gortsplib
 
 import (
 	"net"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -13,8 +14,10 @@ import (
 )
 
 type clientMedia struct {
-	c     *Client
-	media *description.Media
+	c         *Client
+	media     *description.Media
+	activity  MediaActivity
+	transport Transport
 
 	onPacketRTCP           OnPacketRTCPFunc
 	formats                map[uint8]*clientFormat
@@ -28,6 +31,15 @@ type clientMedia struct {
 	rtcpPacketsReceived    *uint64
 	rtcpPacketsSent        *uint64
 	rtcpPacketsInError     *uint64
+	udpPacketsTruncated    *uint64
+	goodbyeReceived        *int32
+	rtcpOnRTPPort          *int32
+	dumpAddrs              *packetDumpAddrs
+	rates                  *mediaRates
+	packets                receivedPackets // play only
+
+	// serializes writes of concurrent producers.
+	writeMutex sync.Mutex
 }
 
 func (cm *clientMedia) initialize() {
@@ -38,6 +50,17 @@ func (cm *clientMedia) initialize() {
 	cm.rtcpPacketsReceived = new(uint64)
 	cm.rtcpPacketsSent = new(uint64)
 	cm.rtcpPacketsInError = new(uint64)
+	cm.udpPacketsTruncated = new(uint64)
+	cm.goodbyeReceived = new(int32)
+	cm.rtcpOnRTPPort = new(int32)
+
+	cm.rates = &mediaRates{media: cm.media}
+	cm.rates.initialize()
+
+	cm.packets = receivedPackets{
+		pool: cm.c.MemoryOptions.PoolPackets,
+	}
+	cm.packets.initialize()
 
 	cm.formats = make(map[uint8]*clientFormat)
 
@@ -99,15 +122,20 @@ func (cm *clientMedia) createUDPListeners(
 }
 
 func (cm *clientMedia) start() {
+	atomic.StoreInt32(cm.goodbyeReceived, 0)
+
 	if cm.udpRTPListener != nil {
 		cm.writePacketRTCPInQueue = cm.writePacketRTCPInQueueUDP
+		cm.dumpAddrs = cm.packetDumpAddrsUDP()
+		cm.udpRTPListener.truncatedFunc = cm.onPacketRTPTruncated
+		cm.udpRTCPListener.truncatedFunc = cm.onPacketRTCPTruncated
 
 		if cm.c.state == clientStateRecord || cm.media.IsBackChannel {
-			cm.udpRTPListener.readFunc = cm.readPacketRTPUDPRecord
-			cm.udpRTCPListener.readFunc = cm.readPacketRTCPUDPRecord
+			cm.udpRTPListener.readFunc = cm.dumpReadFunc(false, cm.readPacketRTPUDPRecord)
+			cm.udpRTCPListener.readFunc = cm.dumpReadFunc(true, cm.readPacketRTCPUDPRecord)
 		} else {
-			cm.udpRTPListener.readFunc = cm.readPacketRTPUDPPlay
-			cm.udpRTCPListener.readFunc = cm.readPacketRTCPUDPPlay
+			cm.udpRTPListener.readFunc = cm.dumpReadFunc(false, cm.readPacketRTPUDPPlay)
+			cm.udpRTCPListener.readFunc = cm.dumpReadFunc(true, cm.readPacketRTCPUDPPlay)
 		}
 	} else {
 		cm.writePacketRTCPInQueue = cm.writePacketRTCPInQueueTCP
@@ -116,12 +144,14 @@ func (cm *clientMedia) start() {
 			cm.c.tcpCallbackByChannel = make(map[int]readFunc)
 		}
 
+		cm.dumpAddrs = newPacketDumpAddrsTCP(cm.c.nconn.LocalAddr(), cm.c.nconn.RemoteAddr(), cm.tcpChannel)
+
 		if cm.c.state == clientStateRecord || cm.media.IsBackChannel {
-			cm.c.tcpCallbackByChannel[cm.tcpChannel] = cm.readPacketRTPTCPRecord
-			cm.c.tcpCallbackByChannel[cm.tcpChannel+1] = cm.readPacketRTCPTCPRecord
+			cm.c.tcpCallbackByChannel[cm.tcpChannel] = cm.dumpReadFunc(false, cm.readPacketRTPTCPRecord)
+			cm.c.tcpCallbackByChannel[cm.tcpChannel+1] = cm.dumpReadFunc(true, cm.readPacketRTCPTCPRecord)
 		} else {
-			cm.c.tcpCallbackByChannel[cm.tcpChannel] = cm.readPacketRTPTCPPlay
-			cm.c.tcpCallbackByChannel[cm.tcpChannel+1] = cm.readPacketRTCPTCPPlay
+			cm.c.tcpCallbackByChannel[cm.tcpChannel] = cm.dumpReadFunc(false, cm.readPacketRTPTCPPlay)
+			cm.c.tcpCallbackByChannel[cm.tcpChannel+1] = cm.dumpReadFunc(true, cm.readPacketRTCPTCPPlay)
 		}
 	}
 
@@ -156,13 +186,49 @@ func (cm *clientMedia) findFormatWithSSRC(ssrc uint32) *clientFormat {
 	return nil
 }
 
+func (cm *clientMedia) packetDumpAddrsUDP() *packetDumpAddrs {
+	localIP := addrIP(cm.c.nconn.LocalAddr())
+
+	remoteAddr := func(l *clientUDPListener) *net.UDPAddr {
+		if l.writeAddr != nil {
+			return l.writeAddr
+		}
+		return &net.UDPAddr{IP: l.readIP, Port: l.readPort}
+	}
+
+	return &packetDumpAddrs{
+		rtpLocal:   &net.UDPAddr{IP: localIP, Port: cm.udpRTPListener.port()},
+		rtpRemote:  remoteAddr(cm.udpRTPListener),
+		rtcpLocal:  &net.UDPAddr{IP: localIP, Port: cm.udpRTCPListener.port()},
+		rtcpRemote: remoteAddr(cm.udpRTCPListener),
+	}
+}
+
+func (cm *clientMedia) dumpReadFunc(isRTCP bool, cb readFunc) readFunc {
+	return func(payload []byte) bool {
+		if d := cm.c.packetDump.Load(); d != nil {
+			d.write(cm.c.timeNow(), true, cm.dumpAddrs, isRTCP, payload)
+		}
+		return cb(payload)
+	}
+}
+
+func (cm *clientMedia) dumpSent(isRTCP bool, payload []byte) {
+	if d := cm.c.packetDump.Load(); d != nil {
+		d.write(cm.c.timeNow(), false, cm.dumpAddrs, isRTCP, payload)
+	}
+}
+
 func (cm *clientMedia) writePacketRTCPInQueueUDP(payload []byte) error {
 	err := cm.udpRTCPListener.write(payload)
 	if err != nil {
 		return err
 	}
 
+	cm.dumpSent(true, payload)
+
 	atomic.AddUint64(cm.bytesSent, uint64(len(payload)))
+	cm.c.ObservabilityOptions.Metrics.AddBytesSent(uint64(len(payload)))
 	atomic.AddUint64(cm.rtcpPacketsSent, 1)
 	return nil
 }
@@ -170,25 +236,28 @@ func (cm *clientMedia) writePacketRTCPInQueueUDP(payload []byte) error {
 func (cm *clientMedia) writePacketRTCPInQueueTCP(payload []byte) error {
 	cm.c.tcpFrame.Channel = cm.tcpChannel + 1
 	cm.c.tcpFrame.Payload = payload
-	cm.c.nconn.SetWriteDeadline(time.Now().Add(cm.c.WriteTimeout))
+	cm.c.nconn.SetWriteDeadline(time.Now().Add(cm.c.TimeoutOptions.Write))
 	err := cm.c.conn.WriteInterleavedFrame(cm.c.tcpFrame, cm.c.tcpBuffer)
 	if err != nil {
 		return err
 	}
 
+	cm.dumpSent(true, payload)
+
 	atomic.AddUint64(cm.bytesSent, uint64(len(payload)))
+	cm.c.ObservabilityOptions.Metrics.AddBytesSent(uint64(len(payload)))
 	atomic.AddUint64(cm.rtcpPacketsSent, 1)
 	return nil
 }
 
 func (cm *clientMedia) readPacketRTPTCPPlay(payload []byte) bool {
 	atomic.AddUint64(cm.bytesReceived, uint64(len(payload)))
+	cm.c.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
 
 	now := cm.c.timeNow()
 	atomic.StoreInt64(cm.c.tcpLastFrameTime, now.Unix())
 
-	pkt := &rtp.Packet{}
-	err := pkt.Unmarshal(payload)
+	pkt, err := cm.packets.decode(payload)
 	if err != nil {
 		cm.onPacketRTPDecodeError(err)
 		return false
@@ -196,6 +265,7 @@ func (cm *clientMedia) readPacketRTPTCPPlay(payload []byte) bool {
 
 	forma, ok := cm.formats[pkt.PayloadType]
 	if !ok {
+		cm.packets.discard(pkt)
 		cm.onPacketRTPDecodeError(liberrors.ErrClientRTPPacketUnknownPayloadType{PayloadType: pkt.PayloadType})
 		return false
 	}
@@ -207,6 +277,7 @@ func (cm *clientMedia) readPacketRTPTCPPlay(payload []byte) bool {
 
 func (cm *clientMedia) readPacketRTCPTCPPlay(payload []byte) bool {
 	atomic.AddUint64(cm.bytesReceived, uint64(len(payload)))
+	cm.c.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
 
 	now := cm.c.timeNow()
 	atomic.StoreInt64(cm.c.tcpLastFrameTime, now.Unix())
@@ -225,11 +296,23 @@ func (cm *clientMedia) readPacketRTCPTCPPlay(payload []byte) bool {
 	atomic.AddUint64(cm.rtcpPacketsReceived, uint64(len(packets)))
 
 	for _, pkt := range packets {
-		if sr, ok := pkt.(*rtcp.SenderReport); ok {
-			format := cm.findFormatWithSSRC(sr.SSRC)
+		switch tpkt := pkt.(type) {
+		case *rtcp.SenderReport:
+			format := cm.findFormatWithSSRC(tpkt.SSRC)
 			if format != nil {
-				format.rtcpReceiver.ProcessSenderReport(sr, now)
+				format.rtcpReceiver.ProcessSenderReport(tpkt, now)
+			}
+
+		case *rtcp.SourceDescription:
+			for i, chunk := range tpkt.Chunks {
+				format := cm.findFormatWithSSRC(chunk.Source)
+				if format != nil {
+					format.remoteSDES.process(&tpkt.Chunks[i])
+				}
 			}
+
+		case *rtcp.Goodbye:
+			cm.onGoodbye()
 		}
 
 		cm.onPacketRTCP(pkt)
@@ -244,6 +327,7 @@ func (cm *clientMedia) readPacketRTPTCPRecord(_ []byte) bool {
 
 func (cm *clientMedia) readPacketRTCPTCPRecord(payload []byte) bool {
 	atomic.AddUint64(cm.bytesReceived, uint64(len(payload)))
+	cm.c.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
 
 	if len(payload) > udpMaxPayloadSize {
 		cm.onPacketRTCPDecodeError(liberrors.ErrClientRTCPPacketTooBig{L: len(payload), Max: udpMaxPayloadSize})
@@ -266,15 +350,16 @@ func (cm *clientMedia) readPacketRTCPTCPRecord(payload []byte) bool {
 }
 
 func (cm *clientMedia) readPacketRTPUDPPlay(payload []byte) bool {
-	atomic.AddUint64(cm.bytesReceived, uint64(len(payload)))
-
-	if len(payload) == (udpMaxPayloadSize + 1) {
-		cm.onPacketRTPDecodeError(liberrors.ErrClientRTPPacketTooBigUDP{})
-		return false
+	// some servers send RTCP packets to the RTP port.
+	if isRTCPPacket(payload) {
+		atomic.StoreInt32(cm.rtcpOnRTPPort, 1)
+		return cm.readPacketRTCPUDPPlay(payload)
 	}
 
-	pkt := &rtp.Packet{}
-	err := pkt.Unmarshal(payload)
+	atomic.AddUint64(cm.bytesReceived, uint64(len(payload)))
+	cm.c.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
+
+	pkt, err := cm.packets.decode(payload)
 	if err != nil {
 		cm.onPacketRTPDecodeError(err)
 		return false
@@ -282,6 +367,7 @@ func (cm *clientMedia) readPacketRTPUDPPlay(payload []byte) bool {
 
 	forma, ok := cm.formats[pkt.PayloadType]
 	if !ok {
+		cm.packets.discard(pkt)
 		cm.onPacketRTPDecodeError(liberrors.ErrClientRTPPacketUnknownPayloadType{PayloadType: pkt.PayloadType})
 		return false
 	}
@@ -293,11 +379,7 @@ func (cm *clientMedia) readPacketRTPUDPPlay(payload []byte) bool {
 
 func (cm *clientMedia) readPacketRTCPUDPPlay(payload []byte) bool {
 	atomic.AddUint64(cm.bytesReceived, uint64(len(payload)))
-
-	if len(payload) == (udpMaxPayloadSize + 1) {
-		cm.onPacketRTCPDecodeError(liberrors.ErrClientRTCPPacketTooBigUDP{})
-		return false
-	}
+	cm.c.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
 
 	packets, err := rtcp.Unmarshal(payload)
 	if err != nil {
@@ -310,11 +392,23 @@ func (cm *clientMedia) readPacketRTCPUDPPlay(payload []byte) bool {
 	atomic.AddUint64(cm.rtcpPacketsReceived, uint64(len(packets)))
 
 	for _, pkt := range packets {
-		if sr, ok := pkt.(*rtcp.SenderReport); ok {
-			format := cm.findFormatWithSSRC(sr.SSRC)
+		switch tpkt := pkt.(type) {
+		case *rtcp.SenderReport:
+			format := cm.findFormatWithSSRC(tpkt.SSRC)
 			if format != nil {
-				format.rtcpReceiver.ProcessSenderReport(sr, now)
+				format.rtcpReceiver.ProcessSenderReport(tpkt, now)
 			}
+
+		case *rtcp.SourceDescription:
+			for i, chunk := range tpkt.Chunks {
+				format := cm.findFormatWithSSRC(chunk.Source)
+				if format != nil {
+					format.remoteSDES.process(&tpkt.Chunks[i])
+				}
+			}
+
+		case *rtcp.Goodbye:
+			cm.onGoodbye()
 		}
 
 		cm.onPacketRTCP(pkt)
@@ -323,17 +417,19 @@ func (cm *clientMedia) readPacketRTCPUDPPlay(payload []byte) bool {
 	return true
 }
 
-func (cm *clientMedia) readPacketRTPUDPRecord(_ []byte) bool {
+func (cm *clientMedia) readPacketRTPUDPRecord(payload []byte) bool {
+	// some servers send RTCP packets to the RTP port.
+	if isRTCPPacket(payload) {
+		atomic.StoreInt32(cm.rtcpOnRTPPort, 1)
+		return cm.readPacketRTCPUDPRecord(payload)
+	}
+
 	return false
 }
 
 func (cm *clientMedia) readPacketRTCPUDPRecord(payload []byte) bool {
 	atomic.AddUint64(cm.bytesReceived, uint64(len(payload)))
-
-	if len(payload) == (udpMaxPayloadSize + 1) {
-		cm.onPacketRTCPDecodeError(liberrors.ErrClientRTCPPacketTooBigUDP{})
-		return false
-	}
+	cm.c.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
 
 	packets, err := rtcp.Unmarshal(payload)
 	if err != nil {
@@ -350,12 +446,46 @@ func (cm *clientMedia) readPacketRTCPUDPRecord(payload []byte) bool {
 	return true
 }
 
+// isRTCPPacket checks whether a packet received on a RTP port is a RTCP packet.
+// RTCP packet types (192-223) don't overlap with RTP payload types
+// followed by the marker bit, as long as payload types 64-95 are not used.
+// https://datatracker.ietf.org/doc/html/rfc5761#section-4
+func isRTCPPacket(payload []byte) bool {
+	return len(payload) >= 2 && (payload[0]>>6) == 2 && payload[1] >= 192 && payload[1] <= 223
+}
+
+// onGoodbye is called when a RTCP BYE is received.
+// When all medias have received it, the stream is over.
+func (cm *clientMedia) onGoodbye() {
+	atomic.StoreInt32(cm.goodbyeReceived, 1)
+
+	for _, cm2 := range cm.c.setuppedMedias {
+		if !cm2.media.IsBackChannel && atomic.LoadInt32(cm2.goodbyeReceived) == 0 {
+			return
+		}
+	}
+
+	cm.c.endOfStream()
+}
+
 func (cm *clientMedia) onPacketRTPDecodeError(err error) {
 	atomic.AddUint64(cm.rtpPacketsInError, 1)
+	cm.c.ObservabilityOptions.Metrics.AddErrors(MetricsErrorTypeRTPDecode, 1)
 	cm.c.OnDecodeError(err)
 }
 
 func (cm *clientMedia) onPacketRTCPDecodeError(err error) {
 	atomic.AddUint64(cm.rtcpPacketsInError, 1)
+	cm.c.ObservabilityOptions.Metrics.AddErrors(MetricsErrorTypeRTCPDecode, 1)
 	cm.c.OnDecodeError(err)
 }
+
+func (cm *clientMedia) onPacketRTPTruncated() {
+	atomic.AddUint64(cm.udpPacketsTruncated, 1)
+	cm.onPacketRTPDecodeError(liberrors.ErrClientRTPPacketTooBigUDP{})
+}
+
+func (cm *clientMedia) onPacketRTCPTruncated() {
+	atomic.AddUint64(cm.udpPacketsTruncated, 1)
+	cm.onPacketRTCPDecodeError(liberrors.ErrClientRTCPPacketTooBigUDP{})
+}
@@ -0,0 +1,324 @@
+package gortsplib
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+)
+
+// ClientTransportSwitchFunc is the prototype of ClientTransportOptions.OnTransportSwitch.
+type ClientTransportSwitchFunc func(from Transport, to Transport, reason error)
+
+// ClientTransportOptions groups transport settings of a Client.
+type ClientTransportOptions struct {
+	// transport protocol (UDP, Multicast or TCP).
+	// If nil, it is chosen automatically (first UDP, then, if it fails, TCP).
+	Transport *Transport
+	// enable communication with servers which don't provide UDP server ports
+	// or use different server ports than the announced ones.
+	AnyPortEnable bool
+	// If the client is reading with UDP, it must receive
+	// at least FallbackProbePackets packets within this timeout,
+	// otherwise it switches to another transport.
+	// It defaults to 3 seconds.
+	InitialUDPReadTimeout time.Duration
+	// number of packets that must be received within InitialUDPReadTimeout
+	// in order to consider UDP working.
+	// It defaults to 1.
+	FallbackProbePackets int
+	// when UDP doesn't work, try UDP-multicast before switching to TCP.
+	// It defaults to false.
+	FallbackMulticast bool
+	// called when the transport is chosen automatically and it changes,
+	// with the previous transport, the new one and the reason of the switch.
+	// It defaults to calling Client.OnTransportSwitch with the reason.
+	OnTransportSwitch ClientTransportSwitchFunc
+	// send SETUP requests of SetupAll() back-to-back, without waiting for responses,
+	// after the first one, that creates the session.
+	// It reduces setup latency on links with high latency and streams with many medias.
+	// It defaults to false.
+	PipelineSetup bool
+}
+
+// ClientRedirectOptions groups settings about redirects,
+// that are 3xx responses to DESCRIBE requests and REDIRECT requests sent by the server.
+type ClientRedirectOptions struct {
+	// maximum number of redirects that are followed by a single Describe().
+	// It defaults to 10.
+	MaxRedirects int
+	// do not follow redirects to a different scheme, host or port.
+	// It defaults to false.
+	SameHostOnly bool
+	// send credentials to redirect locations with a different scheme, host or port.
+	// It defaults to false, that is, credentials are reused only when
+	// the location points to the same server.
+	ForwardCredentials bool
+	// when the server sends a REDIRECT request while the client is playing,
+	// connect to the new location instead of closing the client.
+	// Medias of the new location must be compatible with the setupped ones.
+	// Credentials are reused only if the new location has the same scheme, host and port,
+	// or if ForwardCredentials is true.
+	// It defaults to false.
+	Reconnect bool
+}
+
+// ClientDescribeOptions groups settings about DESCRIBE requests.
+type ClientDescribeOptions struct {
+	// timeout of DESCRIBE requests.
+	// Some devices take a long time to generate the session description;
+	// this allows to wait for them without raising the read timeout.
+	// It defaults to TimeoutOptions.Read.
+	Timeout time.Duration
+	// period of OPTIONS requests sent while waiting for the response to a DESCRIBE request,
+	// in order to keep slow devices from closing the connection.
+	// It defaults to 0 (disabled).
+	KeepalivePeriod time.Duration
+	// body of DESCRIBE requests, required by some servers.
+	// It defaults to nil.
+	Body *ClientRequestBody
+	// function that decodes DESCRIBE responses whose Content-Type is not application/sdp
+	// into a stream description.
+	// It defaults to nil, that is, these responses are rejected.
+	Decode func(contentType string, body []byte) (*description.Session, error)
+	// decode SDP stream descriptions in lenient mode, that is, report problems that
+	// don't prevent the stream from being read (invalid fmtp values, duplicate payload types,
+	// missing rtpmap attributes) through OnDescriptionWarning instead of failing DESCRIBE.
+	// It defaults to false.
+	Lenient bool
+}
+
+// ClientPlayOptions groups settings about reading streams.
+type ClientPlayOptions struct {
+	// speed at which the server is asked to send the stream, relative to real time,
+	// with the Speed header of PLAY requests.
+	// It allows to download recordings faster than real time, if supported by the server.
+	// It defaults to 0 (header not sent).
+	Speed float64
+	// when reading with UDP-multicast, period without packets
+	// after which the stream is considered silent and multicast groups are left.
+	// It defaults to TimeoutOptions.Read.
+	MulticastSilenceTimeout time.Duration
+	// when the UDP-multicast stream is silent, leave and join again
+	// multicast groups instead of closing the client.
+	// It defaults to false.
+	MulticastRejoin bool
+	// use a RTSP parameter as clock source when RTCP sender reports are absent.
+	// It defaults to nil.
+	ClockSync *ClientClockSync
+	// check H264 and H265 access units for missing packets and incomplete fragmented NALUs.
+	// Groups of pictures that contain corrupted access units are reported with OnDecodeError,
+	// and access units are counted in format statistics.
+	// It defaults to false.
+	DetectCorruption bool
+}
+
+// ClientRecordOptions groups settings about publishing streams.
+type ClientRecordOptions struct {
+	// identity of the client, written into RTCP SDES packets sent together
+	// with RTCP sender reports.
+	// It defaults to nil (SDES packets are not sent).
+	SenderIdentity *SenderIdentity
+}
+
+// ClientOption is a functional option of NewClient.
+type ClientOption func(*Client)
+
+// WithClientTransport sets transport settings.
+func WithClientTransport(o ClientTransportOptions) ClientOption {
+	return func(c *Client) {
+		c.TransportOptions = o
+	}
+}
+
+// WithClientTimeouts sets timeout settings.
+func WithClientTimeouts(o TimeoutOptions) ClientOption {
+	return func(c *Client) {
+		c.TimeoutOptions = o
+	}
+}
+
+// WithClientSecurity sets security settings.
+func WithClientSecurity(o SecurityOptions) ClientOption {
+	return func(c *Client) {
+		c.SecurityOptions = o
+	}
+}
+
+// WithClientSocket sets socket settings.
+func WithClientSocket(o SocketOptions) ClientOption {
+	return func(c *Client) {
+		c.SocketOptions = o
+	}
+}
+
+// WithClientStrict sets strict mode settings.
+func WithClientStrict(o StrictOptions) ClientOption {
+	return func(c *Client) {
+		c.StrictOptions = o
+	}
+}
+
+// WithClientRedirect sets redirect settings.
+func WithClientRedirect(o ClientRedirectOptions) ClientOption {
+	return func(c *Client) {
+		c.RedirectOptions = o
+	}
+}
+
+// WithClientDescribe sets DESCRIBE settings.
+func WithClientDescribe(o ClientDescribeOptions) ClientOption {
+	return func(c *Client) {
+		c.DescribeOptions = o
+	}
+}
+
+// WithClientPlay sets settings about reading streams.
+func WithClientPlay(o ClientPlayOptions) ClientOption {
+	return func(c *Client) {
+		c.PlayOptions = o
+	}
+}
+
+// WithClientRecord sets settings about publishing streams.
+func WithClientRecord(o ClientRecordOptions) ClientOption {
+	return func(c *Client) {
+		c.RecordOptions = o
+	}
+}
+
+// WithClientMediaActivity sets media activity settings.
+func WithClientMediaActivity(o MediaActivityOptions) ClientOption {
+	return func(c *Client) {
+		c.MediaActivityOptions = o
+	}
+}
+
+// WithClientObservability sets logging, metrics and statistics settings.
+func WithClientObservability(o ObservabilityOptions) ClientOption {
+	return func(c *Client) {
+		c.ObservabilityOptions = o
+	}
+}
+
+// WithClientMemory sets memory management settings.
+func WithClientMemory(o MemoryOptions) ClientOption {
+	return func(c *Client) {
+		c.MemoryOptions = o
+	}
+}
+
+// NewClient allocates a Client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// mergeDeprecatedFields returns transport, timeout and security settings,
+// filled with values of deprecated fields when the corresponding group field is not set.
+// The client is left untouched.
+func (c *Client) mergeDeprecatedFields() (ClientTransportOptions, TimeoutOptions, SecurityOptions) {
+	transportOptions := c.TransportOptions
+	if transportOptions.Transport == nil {
+		transportOptions.Transport = c.Transport
+	}
+	if c.AnyPortEnable {
+		transportOptions.AnyPortEnable = true
+	}
+	if transportOptions.InitialUDPReadTimeout == 0 {
+		transportOptions.InitialUDPReadTimeout = c.InitialUDPReadTimeout
+	}
+
+	timeoutOptions := c.TimeoutOptions
+	if timeoutOptions.Read == 0 {
+		timeoutOptions.Read = c.ReadTimeout
+	}
+	if timeoutOptions.Write == 0 {
+		timeoutOptions.Write = c.WriteTimeout
+	}
+
+	securityOptions := c.SecurityOptions
+	if securityOptions.TLSConfig == nil {
+		securityOptions.TLSConfig = c.TLSConfig
+	}
+
+	return transportOptions, timeoutOptions, securityOptions
+}
+
+// Validate checks the configuration of the client.
+// Deprecated fields are checked together with their groups, without being moved.
+// It is called automatically by Start().
+func (c *Client) Validate() error {
+	transportOptions, timeoutOptions, _ := c.mergeDeprecatedFields()
+
+	if timeoutOptions.Read < 0 {
+		return fmt.Errorf("ReadTimeout must not be negative")
+	}
+
+	if timeoutOptions.Write < 0 {
+		return fmt.Errorf("WriteTimeout must not be negative")
+	}
+
+	if transportOptions.InitialUDPReadTimeout < 0 {
+		return fmt.Errorf("InitialUDPReadTimeout must not be negative")
+	}
+
+	if transportOptions.FallbackProbePackets < 0 {
+		return fmt.Errorf("FallbackProbePackets must not be negative")
+	}
+
+	if c.RedirectOptions.MaxRedirects < 0 {
+		return fmt.Errorf("MaxRedirects must not be negative")
+	}
+
+	if c.DescribeOptions.Timeout < 0 {
+		return fmt.Errorf("DescribeTimeout must not be negative")
+	}
+
+	if c.DescribeOptions.KeepalivePeriod < 0 {
+		return fmt.Errorf("DescribeKeepalivePeriod must not be negative")
+	}
+
+	if c.MediaActivityOptions.SparseReadTimeout < 0 {
+		return fmt.Errorf("SparseReadTimeout must not be negative")
+	}
+
+	if c.WriteQueueSize < 0 || (c.WriteQueueSize&(c.WriteQueueSize-1)) != 0 {
+		return fmt.Errorf("WriteQueueSize must be a power of two")
+	}
+
+	if c.SocketOptions.UDPMaxPayloadSize < 0 || c.SocketOptions.UDPMaxPayloadSize > udpMaxReadPayloadSize {
+		return fmt.Errorf("UDPMaxPayloadSize must be less than %d", udpMaxReadPayloadSize)
+	}
+
+	if c.MaxPacketSize < 0 || c.MaxPacketSize > udpMaxPayloadSize {
+		return fmt.Errorf("MaxPacketSize must be less than %d", udpMaxPayloadSize)
+	}
+
+	if c.PlayOptions.MulticastSilenceTimeout < 0 {
+		return fmt.Errorf("MulticastSilenceTimeout must not be negative")
+	}
+
+	if c.PlayOptions.Speed < 0 {
+		return fmt.Errorf("Speed must not be negative")
+	}
+
+	if c.SocketOptions.DSCP < 0 || c.SocketOptions.DSCP > 63 {
+		return fmt.Errorf("DSCP must be between 0 and 63")
+	}
+
+	if c.PlayOptions.ClockSync != nil && c.PlayOptions.ClockSync.Parameter == "" {
+		return fmt.Errorf("ClockSync.Parameter is empty")
+	}
+
+	if c.RecordOptions.SenderIdentity != nil && c.RecordOptions.SenderIdentity.CNAME == "" {
+		return fmt.Errorf("SenderIdentity.CNAME is empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package gortsplib
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
+)
+
+type getParametersReq struct {
+	names []string
+	res   chan clientRes
+}
+
+type setParametersReq struct {
+	params map[string]string
+	res    chan clientRes
+}
+
+func (c *Client) checkStateInSession() error {
+	return c.checkState(map[clientState]struct{}{
+		clientStatePrePlay:   {},
+		clientStatePlay:      {},
+		clientStatePreRecord: {},
+		clientStateRecord:    {},
+	})
+}
+
+func (c *Client) doGetParameters(names []string) (map[string]string, *base.Response, error) {
+	err := c.checkStateInSession()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := c.do(&base.Request{
+		Method: base.GetParameter,
+		URL:    c.baseURL,
+		Header: base.Header{
+			"Content-Type": base.HeaderValue{"text/parameters"},
+		},
+		Body: []byte(strings.Join(names, "\r\n") + "\r\n"),
+	}, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.StatusCode != base.StatusOK {
+		return nil, res, liberrors.ErrClientBadStatusCode{Code: res.StatusCode, Message: res.StatusMessage}
+	}
+
+	params := make(map[string]string)
+	for _, p := range unmarshalParameters(res.Body) {
+		params[p.Name] = p.Value
+	}
+
+	return params, res, nil
+}
+
+// GetParameters sends a GET_PARAMETER request that asks the values of the given parameters,
+// and returns the values contained in the response.
+// This can be called only after Setup().
+func (c *Client) GetParameters(names []string) (map[string]string, *base.Response, error) {
+	cres := make(chan clientRes)
+	select {
+	case c.chGetParameters <- getParametersReq{names: names, res: cres}:
+		res := <-cres
+		return res.params, res.res, res.err
+
+	case <-c.done:
+		return nil, nil, c.closeError
+	}
+}
+
+func (c *Client) doSetParameters(params map[string]string) (*base.Response, error) {
+	err := c.checkStateInSession()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Parameter, 0, len(params))
+	for name, value := range params {
+		list = append(list, Parameter{Name: name, Value: value})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	res, err := c.do(&base.Request{
+		Method: base.SetParameter,
+		URL:    c.baseURL,
+		Header: base.Header{
+			"Content-Type": base.HeaderValue{"text/parameters"},
+		},
+		Body: marshalParameters(list),
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != base.StatusOK {
+		return res, liberrors.ErrClientBadStatusCode{Code: res.StatusCode, Message: res.StatusMessage}
+	}
+
+	return res, nil
+}
+
+// SetParameters sends a SET_PARAMETER request that sets the given parameters.
+// This can be called only after Setup().
+func (c *Client) SetParameters(params map[string]string) (*base.Response, error) {
+	cres := make(chan clientRes)
+	select {
+	case c.chSetParameters <- setParametersReq{params: params, res: cres}:
+		res := <-cres
+		return res.res, res.err
+
+	case <-c.done:
+		return nil, c.closeError
+	}
+}
@@ -0,0 +1,36 @@
+package gortsplib
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+)
+
+// ClientPlaySpeed contains the speed negotiated with the server during PLAY.
+type ClientPlaySpeed struct {
+	// speed at which the server sends the stream, relative to real time.
+	Speed float64
+	// whether the server changes the sending rate dynamically (x-Dynamic-Rate header).
+	DynamicRate bool
+}
+
+func newClientPlaySpeed(res *base.Response) ClientPlaySpeed {
+	// servers that don't support the Speed header send the stream in real time.
+	ret := ClientPlaySpeed{
+		Speed: 1,
+	}
+
+	if v, ok := res.Header["Speed"]; ok && len(v) == 1 {
+		tmp, err := strconv.ParseFloat(strings.TrimSpace(v[0]), 64)
+		if err == nil && tmp > 0 {
+			ret.Speed = tmp
+		}
+	}
+
+	if v, ok := res.Header["X-Dynamic-Rate"]; ok && len(v) == 1 {
+		ret.DynamicRate = strings.TrimSpace(v[0]) == "1"
+	}
+
+	return ret
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/bluenviron/gortsplib/v4/pkg/description"
 	"github.com/bluenviron/gortsplib/v4/pkg/format"
 	"github.com/bluenviron/gortsplib/v4/pkg/headers"
+	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
 	"github.com/bluenviron/gortsplib/v4/pkg/sdp"
 )
 
@@ -932,6 +933,134 @@ func TestClientRecordAutomaticProtocol(t *testing.T) {
 	<-recv
 }
 
+func TestClientRecordWritePacketsRTP(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	pkts := make([]*rtp.Packet, 3)
+	for i := range pkts {
+		pkts[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: uint16(i),
+				Marker:         i == 2,
+				CSRC:           []uint32{},
+			},
+			Payload: []byte{1, 2, 3, 4},
+		}
+	}
+
+	recv := make(chan struct{})
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err2 := l.Accept()
+		require.NoError(t, err2)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		req, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Public": base.HeaderValue{strings.Join([]string{
+					string(base.Announce),
+					string(base.Setup),
+					string(base.Record),
+				}, ", ")},
+			},
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Announce, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Setup, req.Method)
+
+		th := headers.Transport{
+			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+			Protocol:       headers.TransportProtocolTCP,
+			InterleavedIDs: &[2]int{0, 1},
+		}
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Transport": th.Marshal(),
+			},
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Record, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+
+		for _, pkt := range pkts[1:] {
+			f, err3 := conn.ReadInterleavedFrame()
+			require.NoError(t, err3)
+			require.Equal(t, 0, f.Channel)
+
+			var dec rtp.Packet
+			err3 = dec.Unmarshal(f.Payload)
+			require.NoError(t, err3)
+			require.Equal(t, pkt, &dec)
+		}
+
+		close(recv)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Teardown, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+	}()
+
+	c := Client{
+		Transport:      transportPtr(TransportTCP),
+		WriteQueueSize: 2,
+	}
+
+	medi := testH264Media
+	medias := []*description.Media{medi}
+
+	err = record(&c, "rtsp://localhost:8554/teststream", medias, nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	// access unit is bigger than the queue
+	err = c.WritePacketsRTP(medi, pkts)
+	require.Equal(t, liberrors.ErrClientWriteQueueFull{}, err)
+
+	err = c.WritePacketsRTP(medi, pkts[1:])
+	require.NoError(t, err)
+
+	<-recv
+}
+
 func TestClientRecordDecodeErrors(t *testing.T) {
 	for _, ca := range []struct {
 		proto string
@@ -1407,3 +1536,114 @@ func TestClientRecordIgnoreTCPRTPPackets(t *testing.T) {
 
 	<-rtcpReceived
 }
+
+func TestClientRecordConcurrentWrites(t *testing.T) {
+	const (
+		producers = 4
+		packets   = 100
+	)
+
+	metaFormat := &format.Generic{
+		PayloadTyp: 97,
+		RTPMa:      "private/90000",
+	}
+	err := metaFormat.Init()
+	require.NoError(t, err)
+
+	medias := []*description.Media{
+		testH264Media,
+		{
+			Type:    description.MediaTypeApplication,
+			Formats: []format.Format{metaFormat},
+		},
+	}
+
+	var mutex sync.Mutex
+	lastCounters := make(map[int][producers]int)
+	received := 0
+	done := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				ctx.Session.OnPacketRTPAny(func(_ *description.Media, forma format.Format, pkt *rtp.Packet) {
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					// packets of each producer must be received in order
+					mediaID := int(forma.PayloadType())
+					counters := lastCounters[mediaID]
+					producer := int(pkt.Payload[0])
+					counter := int(pkt.Payload[1])
+					require.Equal(t, counters[producer], counter)
+					counters[producer]++
+					lastCounters[mediaID] = counters
+
+					received++
+					if received == len(medias)*producers*packets {
+						close(done)
+					}
+				})
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	c := Client{
+		TransportOptions: ClientTransportOptions{
+			Transport: transportPtr(TransportTCP),
+		},
+		WriteQueueSize: 2048,
+	}
+
+	desc := &description.Session{Medias: medias}
+
+	err = c.StartRecording("rtsp://localhost:8554/teststream", desc)
+	require.NoError(t, err)
+	defer c.Close()
+
+	var wg sync.WaitGroup
+
+	for _, medi := range desc.Medias {
+		for p := 0; p < producers; p++ {
+			wg.Add(1)
+
+			go func(medi *description.Media, p int) {
+				defer wg.Done()
+
+				for i := 0; i < packets; i++ {
+					err2 := c.WritePacketRTP(medi, &rtp.Packet{
+						Header: rtp.Header{
+							Version:     2,
+							PayloadType: medi.Formats[0].PayloadType(),
+							SSRC:        0x38F27A2F,
+						},
+						Payload: []byte{byte(p), byte(i)},
+					})
+					require.NoError(t, err2)
+				}
+			}(medi, p)
+		}
+	}
+
+	wg.Wait()
+	<-done
+}
@@ -1,10 +1,13 @@
 package gortsplib
 
 import (
+	"context"
 	"crypto/tls"
+	"fmt"
 	"net"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -131,6 +134,119 @@ func TestClientClose(t *testing.T) {
 	require.EqualError(t, err, "terminated")
 }
 
+func TestClientValidate(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		c    *Client
+		err  string
+	}{
+		{
+			"write queue size",
+			&Client{WriteQueueSize: 100},
+			"WriteQueueSize must be a power of two",
+		},
+		{
+			"max packet size",
+			&Client{MaxPacketSize: 2000},
+			"MaxPacketSize must be less than 1472",
+		},
+		{
+			"clock sync without parameter",
+			NewClient(WithClientPlay(ClientPlayOptions{ClockSync: &ClientClockSync{}})),
+			"ClockSync.Parameter is empty",
+		},
+		{
+			"sender identity without cname",
+			NewClient(WithClientRecord(ClientRecordOptions{SenderIdentity: &SenderIdentity{Name: "test"}})),
+			"SenderIdentity.CNAME is empty",
+		},
+		{
+			"negative timeout",
+			NewClient(WithClientTimeouts(TimeoutOptions{Write: -1})),
+			"WriteTimeout must not be negative",
+		},
+		{
+			"negative initial udp read timeout",
+			NewClient(WithClientTransport(ClientTransportOptions{InitialUDPReadTimeout: -1})),
+			"InitialUDPReadTimeout must not be negative",
+		},
+		{
+			"negative fallback probe packets",
+			NewClient(WithClientTransport(ClientTransportOptions{FallbackProbePackets: -1})),
+			"FallbackProbePackets must not be negative",
+		},
+		{
+			"negative max redirects",
+			NewClient(WithClientRedirect(ClientRedirectOptions{MaxRedirects: -1})),
+			"MaxRedirects must not be negative",
+		},
+		{
+			"negative describe timeout",
+			NewClient(WithClientDescribe(ClientDescribeOptions{Timeout: -1})),
+			"DescribeTimeout must not be negative",
+		},
+		{
+			"negative speed",
+			NewClient(WithClientPlay(ClientPlayOptions{Speed: -1})),
+			"Speed must not be negative",
+		},
+		{
+			"negative multicast silence timeout",
+			NewClient(WithClientPlay(ClientPlayOptions{MulticastSilenceTimeout: -1})),
+			"MulticastSilenceTimeout must not be negative",
+		},
+		{
+			"negative sparse read timeout",
+			NewClient(WithClientMediaActivity(MediaActivityOptions{SparseReadTimeout: -1})),
+			"SparseReadTimeout must not be negative",
+		},
+		{
+			"udp max payload size",
+			NewClient(WithClientSocket(SocketOptions{UDPMaxPayloadSize: 70000})),
+			"UDPMaxPayloadSize must be less than 65535",
+		},
+		{
+			"invalid dscp",
+			NewClient(WithClientSocket(SocketOptions{DSCP: -1})),
+			"DSCP must be between 0 and 63",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			require.EqualError(t, ca.c.Validate(), ca.err)
+			require.EqualError(t, ca.c.Start("rtsp", "localhost:8554"), ca.err)
+		})
+	}
+}
+
+func TestClientDeprecatedFields(t *testing.T) {
+	c := Client{
+		ReadTimeout:   -1,
+		AnyPortEnable: true,
+		TimeoutOptions: TimeoutOptions{
+			Write: 6 * time.Second,
+		},
+	}
+
+	// deprecated fields are validated, but not moved
+	require.EqualError(t, c.Validate(), "ReadTimeout must not be negative")
+	require.Equal(t, ClientTransportOptions{}, c.TransportOptions)
+	require.Equal(t, TimeoutOptions{Write: 6 * time.Second}, c.TimeoutOptions)
+
+	c.ReadTimeout = 5 * time.Second
+	require.NoError(t, c.Validate())
+	require.Equal(t, TimeoutOptions{Write: 6 * time.Second}, c.TimeoutOptions)
+
+	err := c.Start("rtsp", "localhost:8554")
+	require.NoError(t, err)
+	defer c.Close()
+
+	require.Equal(t, TimeoutOptions{
+		Read:  5 * time.Second,
+		Write: 6 * time.Second,
+	}, c.TimeoutOptions)
+	require.Equal(t, true, c.TransportOptions.AnyPortEnable)
+}
+
 func TestClientCloseDuringRequest(t *testing.T) {
 	l, err := net.Listen("tcp", "localhost:8554")
 	require.NoError(t, err)
@@ -239,6 +355,50 @@ func TestClientSession(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClientLookupIPAddr(t *testing.T) {
+	lookupIPAddr := func(_ context.Context, host string) ([]net.IPAddr, error) {
+		if host == "mycamera.local" {
+			return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+		}
+		return nil, fmt.Errorf("host not found")
+	}
+
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		RTSPAddress:  "mycamera.local:8554",
+		LookupIPAddr: lookupIPAddr,
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	u, err := base.ParseURL("rtsp://mycamera.local:8554/teststream")
+	require.NoError(t, err)
+
+	c := Client{
+		LookupIPAddr: lookupIPAddr,
+	}
+
+	err = c.Start(u.Scheme, u.Host)
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, _, err = c.Describe(u)
+	require.NoError(t, err)
+}
+
 func TestClientAuth(t *testing.T) {
 	l, err := net.Listen("tcp", "localhost:8554")
 	require.NoError(t, err)
@@ -315,6 +475,143 @@ func TestClientAuth(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClientOnRequestOnResponse(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err2 := l.Accept()
+		require.NoError(t, err2)
+		conn := conn.NewConn(nconn)
+		defer nconn.Close()
+
+		req, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+		require.Equal(t, base.HeaderValue{"myvalue"}, req.Header["X-Vendor"])
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"CSeq": req.Header["CSeq"],
+			},
+		})
+		require.NoError(t, err2)
+	}()
+
+	u, err := base.ParseURL("rtsp://localhost:8554/stream")
+	require.NoError(t, err)
+
+	var requests []base.Method
+	var responses []base.StatusCode
+
+	c := Client{
+		OnRequest: func(req *base.Request) {
+			requests = append(requests, req.Method)
+			req.Header["X-Vendor"] = base.HeaderValue{"myvalue"}
+		},
+		OnResponse: func(res *base.Response) {
+			responses = append(responses, res.StatusCode)
+			res.Header["Public"] = base.HeaderValue{"DESCRIBE"}
+		},
+	}
+
+	err = c.Start(u.Scheme, u.Host)
+	require.NoError(t, err)
+	defer c.Close()
+
+	res, err := c.Options(u)
+	require.NoError(t, err)
+	require.Equal(t, base.HeaderValue{"DESCRIBE"}, res.Header["Public"])
+	require.Equal(t, []base.Method{base.Options}, requests)
+	require.Equal(t, []base.StatusCode{base.StatusOK}, responses)
+}
+
+func TestClientAuthRefresh(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err2 := l.Accept()
+		require.NoError(t, err2)
+		conn := conn.NewConn(nconn)
+		defer nconn.Close()
+
+		req, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+		require.Equal(t, "token=1", req.URL.RawQuery)
+		require.Equal(t, base.HeaderValue{"myagent"}, req.Header["User-Agent"])
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+		require.Equal(t, "token=1", req.URL.RawQuery)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusUnauthorized,
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+		require.Equal(t, "token=2", req.URL.RawQuery)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+	}()
+
+	u, err := base.ParseURL("rtsp://localhost:8554/stream")
+	require.NoError(t, err)
+
+	token := 1
+	unauthorizedCount := 0
+
+	c := Client{
+		OnPrepareRequest: func(req *base.Request) {
+			req.URL.RawQuery = fmt.Sprintf("token=%d", token)
+			req.Header["User-Agent"] = base.HeaderValue{"myagent"}
+		},
+		OnUnauthorized: func(res *base.Response) bool {
+			require.Equal(t, base.StatusUnauthorized, res.StatusCode)
+			unauthorizedCount++
+			token++
+			return true
+		},
+	}
+
+	err = c.Start(u.Scheme, u.Host)
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.Options(u)
+	require.NoError(t, err)
+
+	res, err := c.Options(u)
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+	require.Equal(t, 1, unauthorizedCount)
+	require.Equal(t, "", u.RawQuery)
+}
+
 func TestClientCSeq(t *testing.T) {
 	for _, ca := range []string{
 		"different cseq",
@@ -392,6 +689,74 @@ func TestClientCSeq(t *testing.T) {
 	}
 }
 
+func TestClientStrictMode(t *testing.T) {
+	for _, ca := range []string{"report", "fail"} {
+		t.Run(ca, func(t *testing.T) {
+			l, err := net.Listen("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer l.Close()
+
+			serverDone := make(chan struct{})
+			defer func() { <-serverDone }()
+			go func() {
+				defer close(serverDone)
+
+				nconn, err2 := l.Accept()
+				require.NoError(t, err2)
+				defer nconn.Close()
+				conn := conn.NewConn(nconn)
+
+				req, err2 := conn.ReadRequest()
+				require.NoError(t, err2)
+				require.Equal(t, base.Options, req.Method)
+
+				err2 = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Public": base.HeaderValue{strings.Join([]string{
+							string(base.Describe),
+						}, ", ")},
+					},
+				})
+				require.NoError(t, err2)
+			}()
+
+			u, err := base.ParseURL("rtsp://localhost:8554/teststream")
+			require.NoError(t, err)
+
+			var violations []*Violation
+
+			c := Client{
+				StrictOptions: StrictOptions{
+					Enabled:         true,
+					FailOnViolation: ca == "fail",
+				},
+				OnViolation: func(v *Violation) {
+					violations = append(violations, v)
+				},
+			}
+
+			err = c.Start(u.Scheme, u.Host)
+			require.NoError(t, err)
+			defer c.Close()
+
+			_, err = c.Options(u)
+
+			if ca == "fail" {
+				require.EqualError(t, err, "protocol violation: CSeq header is missing")
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, violations, 1)
+			require.Equal(t, ViolationTypeHeaderMissing, violations[0].Type)
+			require.Equal(t, "12.17", violations[0].Section)
+			require.Equal(t, base.Options, violations[0].Request.Method)
+			require.NotNil(t, violations[0].Response)
+		})
+	}
+}
+
 func TestClientDescribeCharset(t *testing.T) {
 	l, err := net.Listen("tcp", "localhost:8554")
 	require.NoError(t, err)
@@ -452,6 +817,250 @@ func TestClientDescribeCharset(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClientDescribeLenient(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err2 := l.Accept()
+		require.NoError(t, err2)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		req, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Public": base.HeaderValue{strings.Join([]string{
+					string(base.Describe),
+				}, ", ")},
+			},
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Describe, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Content-Type": base.HeaderValue{"application/sdp"},
+				"Content-Base": base.HeaderValue{"rtsp://localhost:8554/teststream/"},
+			},
+			Body: []byte("v=0\r\n" +
+				"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+				"s=Stream\r\n" +
+				"c=IN IP4 0.0.0.0\r\n" +
+				"t=0 0\r\n" +
+				"m=video 0 RTP/AVP 96\r\n" +
+				"a=control:trackID=0\r\n" +
+				"m=audio 0 RTP/AVP 0\r\n" +
+				"a=control:trackID=1\r\n"),
+		})
+		require.NoError(t, err2)
+	}()
+
+	u, err := base.ParseURL("rtsp://localhost:8554/teststream")
+	require.NoError(t, err)
+
+	var warnings []string
+
+	c := Client{
+		DescribeOptions: ClientDescribeOptions{Lenient: true},
+		OnDescriptionWarning: func(w description.Warning) {
+			warnings = append(warnings, w.String())
+		},
+	}
+
+	err = c.Start(u.Scheme, u.Host)
+	require.NoError(t, err)
+	defer c.Close()
+
+	desc, _, err := c.Describe(u)
+	require.NoError(t, err)
+	require.Len(t, desc.Medias, 1)
+	require.Equal(t, description.MediaTypeAudio, desc.Medias[0].Type)
+	require.Equal(t, []string{
+		"media 1: payload type 96 has no rtpmap",
+		"media 1: media skipped: no formats found",
+	}, warnings)
+}
+
+func TestClientDescribeSlowDevice(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err2 := l.Accept()
+		require.NoError(t, err2)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		req, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"CSeq": req.Header["CSeq"],
+				"Public": base.HeaderValue{strings.Join([]string{
+					string(base.Describe),
+				}, ", ")},
+			},
+		})
+		require.NoError(t, err2)
+
+		describeReq, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Describe, describeReq.Method)
+
+		// wait for interim requests, for more than ReadTimeout.
+		for i := 0; i < 3; i++ {
+			req, err2 = conn.ReadRequest()
+			require.NoError(t, err2)
+			require.Equal(t, base.Options, req.Method)
+			require.Equal(t, mustParseURL("rtsp://localhost:8554/teststream"), req.URL)
+
+			err2 = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+				Header: base.Header{
+					"CSeq": req.Header["CSeq"],
+				},
+			})
+			require.NoError(t, err2)
+		}
+
+		medias := []*description.Media{testH264Media}
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"CSeq":         describeReq.Header["CSeq"],
+				"Content-Type": base.HeaderValue{"application/sdp"},
+				"Content-Base": base.HeaderValue{"rtsp://localhost:8554/teststream/"},
+			},
+			Body: mediasToSDP(medias),
+		})
+		require.NoError(t, err2)
+	}()
+
+	u, err := base.ParseURL("rtsp://localhost:8554/teststream")
+	require.NoError(t, err)
+
+	progressCount := 0
+
+	c := Client{
+		ReadTimeout: 500 * time.Millisecond,
+		DescribeOptions: ClientDescribeOptions{
+			Timeout:         5 * time.Second,
+			KeepalivePeriod: 200 * time.Millisecond,
+		},
+		OnDescribeProgress: func(elapsed time.Duration) {
+			progressCount++
+			require.Greater(t, elapsed, time.Duration(0))
+		},
+	}
+
+	err = c.Start(u.Scheme, u.Host)
+	require.NoError(t, err)
+	defer c.Close()
+
+	desc, _, err := c.Describe(u)
+	require.NoError(t, err)
+	require.Len(t, desc.Medias, 1)
+	require.Equal(t, 3, progressCount)
+}
+
+func TestClientDescribeCustomBody(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err2 := l.Accept()
+		require.NoError(t, err2)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		req, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Public": base.HeaderValue{strings.Join([]string{
+					string(base.Describe),
+				}, ", ")},
+			},
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Describe, req.Method)
+		require.Equal(t, base.HeaderValue{"text/xml"}, req.Header["Content-Type"])
+		require.Equal(t, []byte("<describe/>"), req.Body)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Content-Type": base.HeaderValue{"text/xml; charset=utf-8"},
+				"Content-Base": base.HeaderValue{"rtsp://localhost:8554/teststream/"},
+			},
+			Body: []byte("<stream/>"),
+		})
+		require.NoError(t, err2)
+	}()
+
+	u, err := base.ParseURL("rtsp://localhost:8554/teststream")
+	require.NoError(t, err)
+
+	c := Client{
+		DescribeOptions: ClientDescribeOptions{
+			Body: &ClientRequestBody{
+				ContentType: "text/xml",
+				Content:     []byte("<describe/>"),
+			},
+			Decode: func(contentType string, body []byte) (*description.Session, error) {
+				require.Equal(t, "text/xml", contentType)
+				require.Equal(t, []byte("<stream/>"), body)
+				return &description.Session{
+					Medias: []*description.Media{testH264Media},
+				}, nil
+			},
+		},
+	}
+
+	err = c.Start(u.Scheme, u.Host)
+	require.NoError(t, err)
+	defer c.Close()
+
+	desc, _, err := c.Describe(u)
+	require.NoError(t, err)
+	require.Equal(t, mustParseURL("rtsp://localhost:8554/teststream/"), desc.BaseURL)
+	require.Equal(t, []*description.Media{testH264Media}, desc.Medias)
+}
+
 func TestClientReplyToServerRequest(t *testing.T) {
 	for _, ca := range []string{"after response", "before response"} {
 		t.Run(ca, func(t *testing.T) {
@@ -542,6 +1151,91 @@ func TestClientReplyToServerRequest(t *testing.T) {
 	}
 }
 
+func TestClientGetSetParameters(t *testing.T) {
+	var stream *ServerStream
+	values := make(map[string]string)
+
+	s := &Server{
+		Handler: &testServerParametersHandler{
+			testServerHandler: testServerHandler{
+				onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+					return &base.Response{
+						StatusCode: base.StatusOK,
+					}, stream, nil
+				},
+				onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+					return &base.Response{
+						StatusCode: base.StatusOK,
+					}, stream, nil
+				},
+			},
+			onSetParameters: func(ctx *ServerHandlerOnSetParametersCtx) (*base.Response, error) {
+				require.NotNil(t, ctx.Session)
+				require.Equal(t, []byte("pan: 10\r\nzoom: 2\r\n"), ctx.Request.Body)
+				for _, p := range ctx.Parameters {
+					values[p.Name] = p.Value
+				}
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onGetParameters: func(ctx *ServerHandlerOnGetParametersCtx) (*base.Response, []Parameter, error) {
+				require.NotNil(t, ctx.Session)
+				var ret []Parameter
+				for _, p := range ctx.Parameters {
+					if v, ok := values[p.Name]; ok {
+						ret = append(ret, Parameter{Name: p.Name, Value: v})
+					}
+				}
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, ret, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	u, err := base.ParseURL("rtsp://localhost:8554/teststream")
+	require.NoError(t, err)
+
+	c := Client{
+		Transport: transportPtr(TransportTCP),
+	}
+
+	err = c.Start(u.Scheme, u.Host)
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, _, err = c.GetParameters([]string{"zoom"})
+	require.Error(t, err)
+
+	desc, _, err := c.Describe(u)
+	require.NoError(t, err)
+
+	err = c.SetupAll(desc.BaseURL, desc.Medias)
+	require.NoError(t, err)
+
+	_, err = c.SetParameters(map[string]string{
+		"zoom": "2",
+		"pan":  "10",
+	})
+	require.NoError(t, err)
+
+	params, _, err := c.GetParameters([]string{"zoom", "pan", "tilt"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"zoom": "2",
+		"pan":  "10",
+	}, params)
+}
+
 func TestClientRelativeContentBase(t *testing.T) {
 	l, err := net.Listen("tcp", "localhost:8554")
 	require.NoError(t, err)
@@ -603,3 +1297,69 @@ func TestClientRelativeContentBase(t *testing.T) {
 
 	require.Equal(t, "rtsp://localhost:8554/relative-content-base", desc.BaseURL.String())
 }
+
+func TestClientCapabilities(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+
+	go func() {
+		defer close(serverDone)
+
+		nconn, err2 := l.Accept()
+		require.NoError(t, err2)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		req, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"CSeq": req.Header["CSeq"],
+				"Public": base.HeaderValue{strings.Join([]string{
+					string(base.Describe),
+					string(base.Setup),
+					string(base.Play),
+					string(base.GetParameter),
+				}, ", ")},
+				"Server": base.HeaderValue{"Hikvision-Webs"},
+			},
+		})
+		require.NoError(t, err2)
+
+		// capabilities are cached, therefore no other request is sent
+		_, err2 = conn.ReadRequest()
+		require.Error(t, err2)
+	}()
+
+	c := Client{}
+
+	err = c.Start("rtsp", "localhost:8554")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		var caps *ClientCapabilities
+		caps, err = c.Capabilities(mustParseURL("rtsp://localhost:8554/teststream"))
+		require.NoError(t, err)
+		require.Equal(t, &ClientCapabilities{
+			Methods: []base.Method{
+				base.Describe,
+				base.Setup,
+				base.Play,
+				base.GetParameter,
+			},
+			Server: "Hikvision-Webs",
+			Vendor: ClientServerVendorHikvision,
+		}, caps)
+		require.True(t, caps.Supports(base.Play))
+		require.False(t, caps.Supports(base.Record))
+	}
+
+	c.Close()
+}
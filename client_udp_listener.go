@@ -8,6 +8,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/net/ipv4"
+
 	"github.com/bluenviron/gortsplib/v4/pkg/multicast"
 )
 
@@ -74,19 +76,41 @@ type clientUDPListener struct {
 	multicastSourceIP net.IP
 	address           string
 
-	pc        packetConn
-	readFunc  readFunc
-	readIP    net.IP
-	readPort  int
-	writeAddr *net.UDPAddr
+	pc            packetConn
+	batch         *udpBatchConn
+	readFunc      readFunc
+	truncatedFunc func()
+	readIP        net.IP
+	readPort      int
+	writeAddr     *net.UDPAddr
+
+	readBufferSize  int
+	writeBufferSize int
+	payloadSize     int
 
-	running        bool
-	lastPacketTime *int64
+	running         bool
+	lastPacketTime  *int64
+	packetsReceived *uint64
 
 	done chan struct{}
 }
 
 func (u *clientUDPListener) initialize() error {
+	err := u.createConn()
+	if err != nil {
+		return err
+	}
+
+	u.payloadSize = min(udpMaxPayloadSize, u.c.SocketOptions.UDPMaxPayloadSize)
+	u.lastPacketTime = int64Ptr(0)
+	u.packetsReceived = new(uint64)
+	return nil
+}
+
+// createConn creates the socket.
+// It is separated from initialize() in order to allow to recreate the socket
+// without resetting statistics.
+func (u *clientUDPListener) createConn() error {
 	if u.multicastEnable {
 		intf, err := multicast.InterfaceForSource(u.multicastSourceIP)
 		if err != nil {
@@ -105,13 +129,29 @@ func (u *clientUDPListener) initialize() error {
 		u.pc = tmp.(*net.UDPConn)
 	}
 
-	err := u.pc.SetReadBuffer(udpKernelReadBufferSize)
+	readBufferSize := u.c.SocketOptions.UDPReadBufferSize
+
+	// when receiving faster than real time, enlarge the buffer proportionally.
+	if u.c.PlayOptions.Speed > 1 {
+		readBufferSize = int(float64(readBufferSize) * u.c.PlayOptions.Speed)
+	}
+
+	var err error
+	u.readBufferSize, u.writeBufferSize, err = setBufferSizes(u.pc, readBufferSize, u.c.SocketOptions.UDPWriteBufferSize)
 	if err != nil {
 		u.pc.Close()
 		return err
 	}
 
-	u.lastPacketTime = int64Ptr(0)
+	if u.c.SocketOptions.DSCP != 0 {
+		err = setDSCP(u.pc, u.c.SocketOptions.DSCP)
+		if err != nil {
+			u.pc.Close()
+			return err
+		}
+	}
+
+	u.batch = newUDPBatchConn(u.pc)
 	return nil
 }
 
@@ -122,6 +162,30 @@ func (u *clientUDPListener) close() {
 	u.pc.Close()
 }
 
+// rejoin leaves and joins again the multicast group.
+func (u *clientUDPListener) rejoin() error {
+	running := u.running
+	if running {
+		u.stop()
+	}
+
+	u.pc.Close()
+
+	err := u.createConn()
+	if err != nil {
+		return err
+	}
+
+	// give the group a whole timeout period before considering it silent again
+	atomic.StoreInt64(u.lastPacketTime, u.c.timeNow().Unix())
+
+	if running {
+		u.start()
+	}
+
+	return nil
+}
+
 func (u *clientUDPListener) port() int {
 	return u.pc.LocalAddr().(*net.UDPAddr).Port
 }
@@ -142,13 +206,41 @@ func (u *clientUDPListener) stop() {
 func (u *clientUDPListener) run() {
 	defer close(u.done)
 
-	var buf []byte
+	if u.batch != nil {
+		u.runBatch()
+	} else {
+		u.runSingle()
+	}
+}
+
+// newBuffer allocates a buffer that is one byte bigger than the payload size,
+// in order to detect truncated packets.
+func (u *clientUDPListener) newBuffer() []byte {
+	if u.c.MemoryOptions.PoolPackets {
+		return getPacketBuffer(u.payloadSize + 1)
+	}
+	return make([]byte, u.payloadSize+1)
+}
+
+// nextBuffer returns the buffer to use for the next read.
+func (u *clientUDPListener) nextBuffer(buf []byte, retained bool) []byte {
+	if retained {
+		return u.newBuffer()
+	}
 
-	createNewBuffer := func() {
-		buf = make([]byte, udpMaxPayloadSize+1)
+	// the payload size has been enlarged
+	if len(buf) != (u.payloadSize + 1) {
+		if u.c.MemoryOptions.PoolPackets {
+			putPacketBuffer(buf)
+		}
+		return u.newBuffer()
 	}
 
-	createNewBuffer()
+	return buf
+}
+
+func (u *clientUDPListener) runSingle() {
+	buf := u.newBuffer()
 
 	for {
 		n, addr, err := u.pc.ReadFrom(buf)
@@ -156,33 +248,67 @@ func (u *clientUDPListener) run() {
 			return
 		}
 
-		uaddr := addr.(*net.UDPAddr)
+		// the packet filled the whole buffer, therefore it may have been truncated.
+		retained := u.processPacket(buf[:n], n == len(buf), addr.(*net.UDPAddr))
+		buf = u.nextBuffer(buf, retained)
+	}
+}
 
-		if !u.readIP.Equal(uaddr.IP) {
-			continue
+func (u *clientUDPListener) runBatch() {
+	ms := make([]ipv4.Message, udpReadBatchSize)
+	for i := range ms {
+		ms[i].Buffers = [][]byte{u.newBuffer()}
+	}
+
+	for {
+		n, err := u.batch.read(ms)
+		if err != nil {
+			return
 		}
 
-		// in case of anyPortEnable, store the port of the first packet we receive.
-		// this reduces security issues
-		if u.c.AnyPortEnable && u.readPort == 0 {
-			u.readPort = uaddr.Port
-		} else if u.readPort != uaddr.Port {
-			continue
+		for i := 0; i < n; i++ {
+			buf := ms[i].Buffers[0]
+
+			retained := u.processPacket(buf[:ms[i].N], ms[i].N == len(buf), ms[i].Addr.(*net.UDPAddr))
+			ms[i].Buffers[0] = u.nextBuffer(buf, retained)
 		}
+	}
+}
 
-		now := u.c.timeNow()
-		atomic.StoreInt64(u.lastPacketTime, now.Unix())
+// processPacket processes a packet and returns whether its buffer has been retained.
+func (u *clientUDPListener) processPacket(buf []byte, truncated bool, addr *net.UDPAddr) bool {
+	if !u.readIP.Equal(addr.IP) {
+		return false
+	}
 
-		if u.readFunc(buf[:n]) {
-			createNewBuffer()
+	// in case of anyPortEnable, store the port of the first packet we receive.
+	// this reduces security issues
+	if u.c.TransportOptions.AnyPortEnable && u.readPort == 0 {
+		u.readPort = addr.Port
+	} else if u.readPort != addr.Port {
+		return false
+	}
+
+	now := u.c.timeNow()
+	atomic.StoreInt64(u.lastPacketTime, now.Unix())
+	atomic.AddUint64(u.packetsReceived, 1)
+
+	// discard truncated packets and enlarge buffers, in order to read next packets entirely.
+	if truncated {
+		if len(buf) > u.payloadSize {
+			u.payloadSize = min(u.payloadSize*2, u.c.SocketOptions.UDPMaxPayloadSize)
 		}
+		u.truncatedFunc()
+		return false
 	}
+
+	return u.readFunc(buf)
 }
 
 func (u *clientUDPListener) write(payload []byte) error {
 	// no mutex is needed here since Write() has an internal lock.
 	// https://github.com/golang/go/issues/27203#issuecomment-534386117
-	u.pc.SetWriteDeadline(time.Now().Add(u.c.WriteTimeout))
+	u.pc.SetWriteDeadline(time.Now().Add(u.c.TimeoutOptions.Write))
 	_, err := u.pc.WriteTo(payload, u.writeAddr)
 	return err
 }
@@ -0,0 +1,37 @@
+package gortsplib
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientUDPListenerRejoinKeepsStats(t *testing.T) {
+	c := &Client{
+		ListenPacket: net.ListenPacket,
+		SocketOptions: SocketOptions{
+			UDPMaxPayloadSize: 1472,
+		},
+		timeNow: time.Now,
+	}
+
+	u := &clientUDPListener{
+		c:       c,
+		address: "localhost:0",
+	}
+	err := u.initialize()
+	require.NoError(t, err)
+	defer u.close()
+
+	atomic.StoreUint64(u.packetsReceived, 5)
+	packetsReceived := u.packetsReceived
+
+	err = u.rejoin()
+	require.NoError(t, err)
+
+	require.Same(t, packetsReceived, u.packetsReceived)
+	require.Equal(t, uint64(5), atomic.LoadUint64(u.packetsReceived))
+}
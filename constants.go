@@ -1,9 +1,19 @@
 package gortsplib
 
+import (
+	"time"
+)
+
 const (
 	// same size as GStreamer's rtspsrc
 	udpKernelReadBufferSize = 0x80000
 
 	// 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header)
 	udpMaxPayloadSize = 1472
+
+	// maximum size of incoming UDP payloads, that can be read by enlarging buffers
+	udpMaxReadPayloadSize = 65535
+
+	// window of bitrates, packet rates and frame rates
+	mediaRatesWindow = 5 * time.Second
 )
@@ -19,12 +19,16 @@ import (
 func main() {
 	// Client allows to set additional client options
 	c := &gortsplib.Client{
-		// transport protocol (UDP, Multicast or TCP). If nil, it is chosen automatically
-		Transport: nil,
-		// timeout of read operations
-		ReadTimeout: 10 * time.Second,
-		// timeout of write operations
-		WriteTimeout: 10 * time.Second,
+		TransportOptions: gortsplib.ClientTransportOptions{
+			// transport protocol (UDP, Multicast or TCP). If nil, it is chosen automatically
+			Transport: nil,
+		},
+		TimeoutOptions: gortsplib.TimeoutOptions{
+			// timeout of read operations
+			Read: 10 * time.Second,
+			// timeout of write operations
+			Write: 10 * time.Second,
+		},
 	}
 
 	// parse URL
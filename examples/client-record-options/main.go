@@ -50,12 +50,16 @@ func main() {
 
 	// Client allows to set additional client options
 	c := &gortsplib.Client{
-		// transport protocol (UDP or TCP). If nil, it is chosen automatically
-		Transport: nil,
-		// timeout of read operations
-		ReadTimeout: 10 * time.Second,
-		// timeout of write operations
-		WriteTimeout: 10 * time.Second,
+		TransportOptions: gortsplib.ClientTransportOptions{
+			// transport protocol (UDP or TCP). If nil, it is chosen automatically
+			Transport: nil,
+		},
+		TimeoutOptions: gortsplib.TimeoutOptions{
+			// timeout of read operations
+			Read: 10 * time.Second,
+			// timeout of write operations
+			Write: 10 * time.Second,
+		},
 	}
 
 	// connect to the server and start recording
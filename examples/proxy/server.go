@@ -18,13 +18,15 @@ type server struct {
 func (s *server) initialize() {
 	// configure the server
 	s.s = &gortsplib.Server{
-		Handler:           s,
-		RTSPAddress:       ":8554",
-		UDPRTPAddress:     ":8000",
-		UDPRTCPAddress:    ":8001",
-		MulticastIPRange:  "224.1.0.0/16",
-		MulticastRTPPort:  8002,
-		MulticastRTCPPort: 8003,
+		Handler:     s,
+		RTSPAddress: ":8554",
+		TransportOptions: gortsplib.ServerTransportOptions{
+			UDPRTPAddress:     ":8000",
+			UDPRTCPAddress:    ":8001",
+			MulticastIPRange:  "224.1.0.0/16",
+			MulticastRTPPort:  8002,
+			MulticastRTCPPort: 8003,
+		},
 	}
 }
 
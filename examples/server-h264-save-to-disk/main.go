@@ -145,13 +145,15 @@ func main() {
 	// configure the server
 	h := &serverHandler{}
 	h.s = &gortsplib.Server{
-		Handler:           h,
-		RTSPAddress:       ":8554",
-		UDPRTPAddress:     ":8000",
-		UDPRTCPAddress:    ":8001",
-		MulticastIPRange:  "224.1.0.0/16",
-		MulticastRTPPort:  8002,
-		MulticastRTCPPort: 8003,
+		Handler:     h,
+		RTSPAddress: ":8554",
+		TransportOptions: gortsplib.ServerTransportOptions{
+			UDPRTPAddress:     ":8000",
+			UDPRTCPAddress:    ":8001",
+			MulticastIPRange:  "224.1.0.0/16",
+			MulticastRTPPort:  8002,
+			MulticastRTCPPort: 8003,
+		},
 	}
 
 	// start server and wait until a fatal error
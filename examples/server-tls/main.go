@@ -149,8 +149,10 @@ func main() {
 	// configure the server
 	h := &serverHandler{}
 	h.s = &gortsplib.Server{
-		Handler:     h,
-		TLSConfig:   &tls.Config{Certificates: []tls.Certificate{cert}},
+		Handler: h,
+		SecurityOptions: gortsplib.SecurityOptions{
+			TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		},
 		RTSPAddress: ":8322",
 	}
 
@@ -0,0 +1,579 @@
+package gortsplib
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpac3"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpamr"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpav1"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpevc"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpfragmentedaudio"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph263"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph265"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpj2k"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpjpegxs"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmjpeg"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmpeg1audio"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmpeg1video"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmpeg4audio"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmpeg4video"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtprawvideo"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtptheora"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpttml"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpvp8"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpvp9"
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Frame is a media frame, that is the content of one or more RTP packets
+// once they have been depacketized.
+type Frame struct {
+	// Media the frame belongs to.
+	Media *description.Media
+
+	// Format of the frame.
+	Format format.Format
+
+	// Presentation timestamp, expressed in clock rate units of the format.
+	PTS int64
+
+	// Absolute time of the frame (optional).
+	// When subscribing, it is filled only after a RTCP sender report has been received.
+	NTP time.Time
+
+	// Frame content. Its layout depends on the format:
+	// - H264, H265, EVC: NALUs of an access unit.
+	// - AV1: OBUs of a temporal unit.
+	// - Theora: Theora packets.
+	// - MPEG-TS: MPEG-TS packets.
+	// - MPEG-4 Audio: access units.
+	// - MPEG-1 Audio, AC-3: frames.
+	// - AMR, AMR-WB: frames in the storage format.
+	// - G729: speech and SID frames.
+	// - iLBC: frames.
+	// - other formats: a single element containing the frame or the samples.
+	Payload [][]byte
+}
+
+// FrameSource provides frames to Publish().
+type FrameSource interface {
+	// ReadFrame returns the next frame.
+	// It must block until a frame is available.
+	// Returning an error stops Publish().
+	ReadFrame() (*Frame, error)
+}
+
+// FrameSink receives frames from Subscribe().
+type FrameSink interface {
+	// WriteFrame is called when a frame is received.
+	// Returning an error stops Subscribe().
+	WriteFrame(*Frame) error
+}
+
+type frameDecoder func(*rtp.Packet) ([][]byte, error)
+
+// isErrMorePacketsNeeded checks whether a decoder error tells
+// that more packets are needed to complete a frame.
+func isErrMorePacketsNeeded(err error) bool {
+	return errors.Is(err, rtph264.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtph265.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpevc.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpav1.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpvp8.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpvp9.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpmjpeg.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpmpeg1video.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpmpeg4video.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpmpeg4audio.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpmpeg1audio.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpac3.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpj2k.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpjpegxs.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtprawvideo.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpamr.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpfragmentedaudio.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtptheora.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtpttml.ErrMorePacketsNeeded) ||
+		errors.Is(err, rtph263.ErrMorePacketsNeeded)
+}
+
+type frameEncoder func([][]byte) ([]*rtp.Packet, error)
+
+func wrapSingleDecoder(dec func(*rtp.Packet) ([]byte, error)) frameDecoder {
+	return func(pkt *rtp.Packet) ([][]byte, error) {
+		frame, err := dec(pkt)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{frame}, nil
+	}
+}
+
+func wrapSingleEncoder(enc func([]byte) ([]*rtp.Packet, error)) frameEncoder {
+	return func(payload [][]byte) ([]*rtp.Packet, error) {
+		if len(payload) != 1 {
+			return nil, fmt.Errorf("payload must contain exactly one element")
+		}
+		return enc(payload[0])
+	}
+}
+
+func newFrameDecoder(forma format.Format) (frameDecoder, error) {
+	switch forma := forma.(type) {
+	case *format.H264:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.H265:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.EVC:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.AV1:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.VP8:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.VP9:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.MJPEG:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.JPEG2000:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.JPEGXS:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.RawVideo:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.MPEG1Video:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.MPEG4Video:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.H263:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.Theora:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.MPEGTS:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.MPEG4Audio:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.MPEG1Audio:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.AC3:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.AC4:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.DTS:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.TTML:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.FLAC:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.AMR:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.AMRWB:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.Opus:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.G722:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.G729:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.ILBC:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.Decode, nil
+
+	case *format.G711:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+
+	case *format.LPCM:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleDecoder(dec.Decode), nil
+	}
+
+	return nil, fmt.Errorf("unsupported format: %T", forma)
+}
+
+func newFrameEncoder(forma format.Format) (frameEncoder, error) {
+	switch forma := forma.(type) {
+	case *format.H264:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.H265:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.EVC:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.AV1:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.VP8:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.VP9:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.MJPEG:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.JPEG2000:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.JPEGXS:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.RawVideo:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.MPEG1Video:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.MPEG4Video:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.H263:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.Theora:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.MPEGTS:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.MPEG4Audio:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.MPEG1Audio:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.AC3:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.AC4:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.DTS:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.TTML:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.FLAC:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.AMR:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.AMRWB:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.Opus:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(func(frame []byte) ([]*rtp.Packet, error) {
+			pkt, err := enc.Encode(frame)
+			if err != nil {
+				return nil, err
+			}
+			return []*rtp.Packet{pkt}, nil
+		}), nil
+
+	case *format.G722:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(func(frame []byte) ([]*rtp.Packet, error) {
+			pkt, err := enc.Encode(frame)
+			if err != nil {
+				return nil, err
+			}
+			return []*rtp.Packet{pkt}, nil
+		}), nil
+
+	case *format.G729:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.ILBC:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.Encode, nil
+
+	case *format.G711:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+
+	case *format.LPCM:
+		enc, err := forma.CreateEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return wrapSingleEncoder(enc.Encode), nil
+	}
+
+	return nil, fmt.Errorf("unsupported format: %T", forma)
+}
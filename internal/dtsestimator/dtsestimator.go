@@ -0,0 +1,117 @@
+// Package dtsestimator implements an algorithm that estimates the DTS of RTP packets.
+package dtsestimator
+
+import (
+	"github.com/bluenviron/mediacommon/pkg/codecs/h264"
+	"github.com/bluenviron/mediacommon/pkg/codecs/h265"
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+// Estimator estimates the DTS of RTP packets.
+// With H264 and H265, packets are grouped into access units and DTS is computed
+// from the picture order count, that allows to handle B-frames.
+// With other formats, DTS is equal to PTS.
+type Estimator struct {
+	Format format.Format
+
+	// copy payloads of packets before decoding them.
+	// It must be enabled when payloads are reused after Estimate returns,
+	// since decoders keep references to fragments of access units.
+	CopyPayloads bool
+
+	decode  func(*rtp.Packet) ([][]byte, error)
+	extract func([][]byte, int64) (int64, error)
+}
+
+// withInitialParams prepends parameters contained in the stream description
+// to the first access unit, since they are needed to compute DTS and
+// they may not be sent in-band.
+func withInitialParams(
+	extract func([][]byte, int64) (int64, error),
+	params ...[]byte,
+) func([][]byte, int64) (int64, error) {
+	var initial [][]byte
+	for _, p := range params {
+		if p != nil {
+			initial = append(initial, p)
+		}
+	}
+
+	return func(au [][]byte, pts int64) (int64, error) {
+		if initial != nil {
+			au = append(initial, au...)
+			initial = nil
+		}
+		return extract(au, pts)
+	}
+}
+
+// Initialize initializes Estimator.
+func (e *Estimator) Initialize() error {
+	switch forma := e.Format.(type) {
+	case *format.H264:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return err
+		}
+		e.decode = dec.Decode
+
+		sps, pps := forma.SafeParams()
+		e.extract = withInitialParams(h264.NewDTSExtractor2().Extract, sps, pps)
+
+	case *format.H265:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return err
+		}
+		e.decode = dec.Decode
+
+		vps, sps, pps := forma.SafeParams()
+		e.extract = withInitialParams(h265.NewDTSExtractor2().Extract, vps, sps, pps)
+	}
+
+	return nil
+}
+
+// Estimate processes a RTP packet and its PTS.
+// With H264 and H265, it must be called with every packet, in order,
+// and the DTS is returned with the last packet of each access unit only.
+// It returns false when the DTS is not available.
+func (e *Estimator) Estimate(pkt *rtp.Packet, pts int64) (int64, bool) {
+	if e.decode == nil {
+		return pts, true
+	}
+
+	if e.CopyPayloads {
+		pkt = &rtp.Packet{
+			Header:  pkt.Header,
+			Payload: append([]byte(nil), pkt.Payload...),
+		}
+	}
+
+	au, err := e.decode(pkt)
+	if err != nil {
+		return 0, false
+	}
+
+	return e.EstimateAccessUnit(au, pts)
+}
+
+// EstimateAccessUnit processes an access unit that has already been decoded
+// from RTP packets, and its PTS.
+// With H264 and H265, it must be called with every access unit, in order.
+// It must not be used together with Estimate.
+func (e *Estimator) EstimateAccessUnit(au [][]byte, pts int64) (int64, bool) {
+	if e.extract == nil {
+		return pts, true
+	}
+
+	dts, err := e.extract(au, pts)
+	if err != nil {
+		return 0, false
+	}
+
+	return dts, true
+}
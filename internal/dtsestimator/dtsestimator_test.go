@@ -0,0 +1,124 @@
+package dtsestimator
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+func TestEstimatorH264(t *testing.T) {
+	e := &Estimator{
+		Format: &format.H264{
+			PayloadTyp:        96,
+			PacketizationMode: 1,
+			SPS: []byte{
+				0x67, 0x64, 0x00, 0x28, 0xac, 0xd9, 0x40, 0x78,
+				0x02, 0x27, 0xe5, 0x84, 0x00, 0x00, 0x03, 0x00,
+				0x04, 0x00, 0x00, 0x03, 0x00, 0xf0, 0x3c, 0x60,
+				0xc6, 0x58,
+			},
+		},
+	}
+	err := e.Initialize()
+	require.NoError(t, err)
+
+	for i, sample := range []struct {
+		nalu []byte
+		pts  int64
+		dts  int64
+	}{
+		{[]byte{0x65, 0x88, 0x84, 0x00, 0x33, 0xff}, 30000, 30000},
+		{[]byte{0x41, 0x9a, 0x21, 0x6c, 0x45, 0xff}, 33000, 33000},
+		{[]byte{0x41, 0x9a, 0x42, 0x3c, 0x21, 0x93}, 36000, 36000},
+		{[]byte{0x41, 0x9a, 0x63, 0x49, 0xe1, 0x0f}, 39000, 39000},
+		{[]byte{0x41, 0x9a, 0x86, 0x49, 0xe1, 0x0f}, 48000, 39090},
+		{[]byte{0x41, 0x9e, 0xa5, 0x42, 0x7f, 0xf9}, 45000, 39180},
+		{[]byte{0x01, 0x9e, 0xc4, 0x69, 0x13, 0xff}, 42000, 42000},
+	} {
+		dts, ok := e.Estimate(&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: uint16(i),
+			},
+			Payload: sample.nalu,
+		}, sample.pts)
+		require.True(t, ok)
+		require.Equal(t, sample.dts, dts)
+	}
+}
+
+func TestEstimatorH264AccessUnit(t *testing.T) {
+	e := &Estimator{
+		Format: &format.H264{
+			PayloadTyp:        96,
+			PacketizationMode: 1,
+			SPS: []byte{
+				0x67, 0x64, 0x00, 0x28, 0xac, 0xd9, 0x40, 0x78,
+				0x02, 0x27, 0xe5, 0x84, 0x00, 0x00, 0x03, 0x00,
+				0x04, 0x00, 0x00, 0x03, 0x00, 0xf0, 0x3c, 0x60,
+				0xc6, 0x58,
+			},
+		},
+	}
+	err := e.Initialize()
+	require.NoError(t, err)
+
+	for _, sample := range []struct {
+		nalu []byte
+		pts  int64
+		dts  int64
+	}{
+		{[]byte{0x65, 0x88, 0x84, 0x00, 0x33, 0xff}, 30000, 30000},
+		{[]byte{0x41, 0x9a, 0x21, 0x6c, 0x45, 0xff}, 33000, 33000},
+		{[]byte{0x41, 0x9a, 0x42, 0x3c, 0x21, 0x93}, 36000, 36000},
+		{[]byte{0x41, 0x9a, 0x63, 0x49, 0xe1, 0x0f}, 39000, 39000},
+		{[]byte{0x41, 0x9a, 0x86, 0x49, 0xe1, 0x0f}, 48000, 39090},
+		{[]byte{0x41, 0x9e, 0xa5, 0x42, 0x7f, 0xf9}, 45000, 39180},
+		{[]byte{0x01, 0x9e, 0xc4, 0x69, 0x13, 0xff}, 42000, 42000},
+	} {
+		dts, ok := e.EstimateAccessUnit([][]byte{sample.nalu}, sample.pts)
+		require.True(t, ok)
+		require.Equal(t, sample.dts, dts)
+	}
+}
+
+func TestEstimatorH264Fragmented(t *testing.T) {
+	e := &Estimator{
+		Format: &format.H264{
+			PayloadTyp:        96,
+			PacketizationMode: 1,
+		},
+	}
+	err := e.Initialize()
+	require.NoError(t, err)
+
+	// first fragment of a FU-A, access unit is not complete
+	_, ok := e.Estimate(&rtp.Packet{
+		Header: rtp.Header{
+			Version:     2,
+			PayloadType: 96,
+		},
+		Payload: []byte{0x7c, 0x85, 0x01, 0x02},
+	}, 0)
+	require.False(t, ok)
+}
+
+func TestEstimatorOtherFormats(t *testing.T) {
+	e := &Estimator{
+		Format: &format.Opus{
+			PayloadTyp:   96,
+			ChannelCount: 2,
+		},
+	}
+	err := e.Initialize()
+	require.NoError(t, err)
+
+	dts, ok := e.Estimate(&rtp.Packet{}, 1234)
+	require.True(t, ok)
+	require.Equal(t, int64(1234), dts)
+}
@@ -0,0 +1,159 @@
+// Package happyeyeballs contains a dialer that connects to hosts with multiple addresses (RFC 8305).
+package happyeyeballs
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// DefaultAttemptDelay is the recommended delay between connection attempts.
+const DefaultAttemptDelay = 250 * time.Millisecond
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// sortAddrs interleaves address families, keeping the order returned by the resolver
+// inside each family and starting with the family of the first address.
+func sortAddrs(addrs []net.IPAddr) []net.IPAddr {
+	var primary []net.IPAddr
+	var secondary []net.IPAddr
+
+	firstIsIPv4 := addrs[0].IP.To4() != nil
+
+	for _, addr := range addrs {
+		if (addr.IP.To4() != nil) == firstIsIPv4 {
+			primary = append(primary, addr)
+		} else {
+			secondary = append(secondary, addr)
+		}
+	}
+
+	ret := make([]net.IPAddr, 0, len(addrs))
+
+	for len(primary) != 0 || len(secondary) != 0 {
+		if len(primary) != 0 {
+			ret = append(ret, primary[0])
+			primary = primary[1:]
+		}
+		if len(secondary) != 0 {
+			ret = append(ret, secondary[0])
+			secondary = secondary[1:]
+		}
+	}
+
+	return ret
+}
+
+// Dialer connects to hosts that resolve to multiple addresses
+// by starting staggered connection attempts and using the first one that succeeds.
+type Dialer struct {
+	// function used to resolve host names.
+	LookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+	// function used to connect to a single address.
+	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// delay between connection attempts.
+	AttemptDelay time.Duration
+}
+
+// Dial connects to the address on the named network.
+func (d *Dialer) Dial(ctx context.Context, network string, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if net.ParseIP(host) != nil {
+		return d.DialContext(ctx, network, address)
+	}
+
+	addrs, err := d.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(addrs) {
+	case 0:
+		return nil, fmt.Errorf("no addresses found for host %s", host)
+
+	case 1:
+		return d.DialContext(ctx, network, net.JoinHostPort(addrs[0].String(), port))
+	}
+
+	addrs = sortAddrs(addrs)
+
+	attemptCtx, attemptCtxCancel := context.WithCancel(ctx)
+	defer attemptCtxCancel()
+
+	results := make(chan dialResult, len(addrs))
+	started := 0
+	pending := 0
+
+	startAttempt := func() {
+		addr := net.JoinHostPort(addrs[started].String(), port)
+		started++
+		pending++
+
+		go func() {
+			conn, err2 := d.DialContext(attemptCtx, network, addr)
+			results <- dialResult{conn, err2}
+		}()
+	}
+
+	startAttempt()
+
+	attemptTimer := time.NewTimer(d.AttemptDelay)
+	defer attemptTimer.Stop()
+
+	var firstErr error
+
+	for {
+		select {
+		case <-attemptTimer.C:
+			if started < len(addrs) {
+				startAttempt()
+				attemptTimer.Reset(d.AttemptDelay)
+			}
+
+		case res := <-results:
+			pending--
+
+			if res.err == nil {
+				// close connections of attempts that are still in progress
+				go func(pending int) {
+					for i := 0; i < pending; i++ {
+						res2 := <-results
+						if res2.conn != nil {
+							res2.conn.Close()
+						}
+					}
+				}(pending)
+
+				return res.conn, nil
+			}
+
+			if firstErr == nil {
+				firstErr = res.err
+			}
+
+			// a failed attempt starts the next one immediately
+			if started < len(addrs) {
+				if !attemptTimer.Stop() {
+					select {
+					case <-attemptTimer.C:
+					default:
+					}
+				}
+				startAttempt()
+				attemptTimer.Reset(d.AttemptDelay)
+			} else if pending == 0 {
+				return nil, firstErr
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package happyeyeballs
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortAddrs(t *testing.T) {
+	addrs := sortAddrs([]net.IPAddr{
+		{IP: net.ParseIP("2001:db8::1")},
+		{IP: net.ParseIP("2001:db8::2")},
+		{IP: net.ParseIP("2001:db8::3")},
+		{IP: net.ParseIP("192.0.2.1")},
+		{IP: net.ParseIP("192.0.2.2")},
+	})
+
+	require.Equal(t, []net.IPAddr{
+		{IP: net.ParseIP("2001:db8::1")},
+		{IP: net.ParseIP("192.0.2.1")},
+		{IP: net.ParseIP("2001:db8::2")},
+		{IP: net.ParseIP("192.0.2.2")},
+		{IP: net.ParseIP("2001:db8::3")},
+	}, addrs)
+}
+
+func TestDialer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+
+	for _, ca := range []string{
+		"unreachable",
+		"refused",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			var mutex sync.Mutex
+			var attempts []string
+
+			d := &Dialer{
+				LookupIPAddr: func(_ context.Context, host string) ([]net.IPAddr, error) {
+					require.Equal(t, "myhost", host)
+					return []net.IPAddr{
+						{IP: net.ParseIP("2001:db8::1")},
+						{IP: net.ParseIP("127.0.0.1")},
+					}, nil
+				},
+				DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+					mutex.Lock()
+					attempts = append(attempts, address)
+					mutex.Unlock()
+
+					if address == net.JoinHostPort("2001:db8::1", port) {
+						if ca == "unreachable" {
+							<-ctx.Done()
+							return nil, ctx.Err()
+						}
+						return nil, fmt.Errorf("connection refused")
+					}
+
+					return (&net.Dialer{}).DialContext(ctx, network, address)
+				},
+				AttemptDelay: 100 * time.Millisecond,
+			}
+
+			start := time.Now()
+
+			nconn, err := d.Dial(context.Background(), "tcp", net.JoinHostPort("myhost", port))
+			require.NoError(t, err)
+			defer nconn.Close()
+
+			require.Equal(t, l.Addr().String(), nconn.RemoteAddr().String())
+
+			if ca == "unreachable" {
+				require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+			} else {
+				require.Less(t, time.Since(start), 100*time.Millisecond)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			require.Equal(t, []string{
+				net.JoinHostPort("2001:db8::1", port),
+				net.JoinHostPort("127.0.0.1", port),
+			}, attempts)
+		})
+	}
+}
+
+func TestDialerAllFailed(t *testing.T) {
+	d := &Dialer{
+		LookupIPAddr: func(_ context.Context, _ string) ([]net.IPAddr, error) {
+			return []net.IPAddr{
+				{IP: net.ParseIP("192.0.2.1")},
+				{IP: net.ParseIP("192.0.2.2")},
+			}, nil
+		},
+		DialContext: func(_ context.Context, _, address string) (net.Conn, error) {
+			return nil, fmt.Errorf("unable to connect to %s", address)
+		},
+		AttemptDelay: 100 * time.Millisecond,
+	}
+
+	_, err := d.Dial(context.Background(), "tcp", "myhost:554")
+	require.EqualError(t, err, "unable to connect to 192.0.2.1:554")
+}
@@ -0,0 +1,52 @@
+// Package pacer contains a token-bucket pacer.
+package pacer
+
+import (
+	"time"
+)
+
+// Pacer is a token-bucket pacer, that limits the rate of outgoing data
+// while allowing short bursts.
+type Pacer struct {
+	// maximum bitrate, in bits per second.
+	Bitrate int
+
+	// size of the bucket, in bytes.
+	Burst int
+
+	TimeNow func() time.Time
+
+	tokens float64
+	last   time.Time
+}
+
+// Initialize initializes Pacer.
+func (p *Pacer) Initialize() {
+	if p.TimeNow == nil {
+		p.TimeNow = time.Now
+	}
+
+	p.tokens = float64(p.Burst)
+	p.last = p.TimeNow()
+}
+
+// Consume consumes n bytes.
+// It returns the duration that must elapse before the bytes can be sent.
+func (p *Pacer) Consume(n int) time.Duration {
+	now := p.TimeNow()
+
+	p.tokens += now.Sub(p.last).Seconds() * float64(p.Bitrate) / 8
+	if p.tokens > float64(p.Burst) {
+		p.tokens = float64(p.Burst)
+	}
+	p.last = now
+
+	// tokens are allowed to become negative, in order to support
+	// data that is bigger than the bucket.
+	p.tokens -= float64(n)
+	if p.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-p.tokens * 8 / float64(p.Bitrate) * float64(time.Second))
+}
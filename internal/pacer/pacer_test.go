@@ -0,0 +1,39 @@
+package pacer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacer(t *testing.T) {
+	now := time.Date(2008, 5, 20, 22, 15, 20, 0, time.UTC)
+
+	p := &Pacer{
+		Bitrate: 8000,
+		Burst:   2000,
+		TimeNow: func() time.Time { return now },
+	}
+	p.Initialize()
+
+	// burst
+	require.Equal(t, time.Duration(0), p.Consume(1000))
+	require.Equal(t, time.Duration(0), p.Consume(1000))
+
+	// bucket is empty
+	require.Equal(t, 500*time.Millisecond, p.Consume(500))
+
+	// bucket is refilled
+	now = now.Add(1500 * time.Millisecond)
+	require.Equal(t, time.Duration(0), p.Consume(1000))
+
+	// bucket does not exceed burst
+	now = now.Add(10 * time.Second)
+	require.Equal(t, time.Duration(0), p.Consume(2000))
+	require.Equal(t, 100*time.Millisecond, p.Consume(100))
+
+	// data bigger than the bucket
+	now = now.Add(10 * time.Second)
+	require.Equal(t, 1*time.Second, p.Consume(3000))
+}
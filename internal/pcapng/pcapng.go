@@ -0,0 +1,201 @@
+// Package pcapng contains a writer of pcapng files.
+// Packets are written as UDP datagrams, in order to allow Wireshark to decode them as RTP or RTCP.
+package pcapng
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// https://www.ietf.org/archive/id/draft-ietf-opsawg-pcapng-01.html
+const (
+	blockTypeSectionHeader        = 0x0A0D0D0A
+	blockTypeInterfaceDescription = 0x00000001
+	blockTypeEnhancedPacket       = 0x00000006
+
+	byteOrderMagic = 0x1A2B3C4D
+
+	linkTypeRaw = 101
+
+	optionEndOfOpt  = 0
+	optionIfTsresol = 9
+	optionEpbFlags  = 2
+
+	ipv4HeaderSize = 20
+	ipv6HeaderSize = 40
+	udpHeaderSize  = 8
+)
+
+// Direction is the direction of a packet.
+type Direction int
+
+// directions.
+const (
+	DirectionInbound Direction = iota + 1
+	DirectionOutbound
+)
+
+// Packet is a packet that is written into a file.
+type Packet struct {
+	// time of the packet.
+	Time time.Time
+
+	// direction of the packet.
+	Direction Direction
+
+	// source address.
+	Src *net.UDPAddr
+
+	// destination address.
+	Dst *net.UDPAddr
+
+	// payload of the UDP datagram.
+	Payload []byte
+}
+
+func padding(l int) int {
+	return (4 - (l % 4)) % 4
+}
+
+func checksum(sum uint32, buf []byte) uint32 {
+	for i := 0; i+1 < len(buf); i += 2 {
+		sum += uint32(buf[i])<<8 | uint32(buf[i+1])
+	}
+	if (len(buf) % 2) != 0 {
+		sum += uint32(buf[len(buf)-1]) << 8
+	}
+	return sum
+}
+
+func foldChecksum(sum uint32) uint16 {
+	for (sum >> 16) != 0 {
+		sum = (sum & 0xFFFF) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
+
+// Writer is a pcapng writer.
+type Writer struct {
+	// destination of the file.
+	W io.Writer
+
+	buf []byte
+}
+
+// Initialize initializes a Writer and writes the file header.
+func (w *Writer) Initialize() error {
+	// section header block
+	buf := make([]byte, 28)
+	binary.LittleEndian.PutUint32(buf[0:], blockTypeSectionHeader)
+	binary.LittleEndian.PutUint32(buf[4:], 28)
+	binary.LittleEndian.PutUint32(buf[8:], byteOrderMagic)
+	binary.LittleEndian.PutUint16(buf[12:], 1)                  // major version
+	binary.LittleEndian.PutUint16(buf[14:], 0)                  // minor version
+	binary.LittleEndian.PutUint64(buf[16:], 0xFFFFFFFFFFFFFFFF) // section length is not specified
+	binary.LittleEndian.PutUint32(buf[24:], 28)
+
+	// interface description block, with timestamps in nanoseconds
+	buf2 := make([]byte, 32)
+	binary.LittleEndian.PutUint32(buf2[0:], blockTypeInterfaceDescription)
+	binary.LittleEndian.PutUint32(buf2[4:], 32)
+	binary.LittleEndian.PutUint16(buf2[8:], linkTypeRaw)
+	binary.LittleEndian.PutUint32(buf2[12:], 0) // no snap length
+	binary.LittleEndian.PutUint16(buf2[16:], optionIfTsresol)
+	binary.LittleEndian.PutUint16(buf2[18:], 1)
+	buf2[20] = 9
+	binary.LittleEndian.PutUint16(buf2[24:], optionEndOfOpt)
+	binary.LittleEndian.PutUint16(buf2[26:], 0)
+	binary.LittleEndian.PutUint32(buf2[28:], 32)
+
+	_, err := w.W.Write(append(buf, buf2...))
+	return err
+}
+
+// WritePacket writes a packet.
+func (w *Writer) WritePacket(pkt *Packet) error {
+	src4 := pkt.Src.IP.To4()
+	dst4 := pkt.Dst.IP.To4()
+	isIPv4 := src4 != nil && dst4 != nil
+
+	ipHeaderSize := ipv6HeaderSize
+	if isIPv4 {
+		ipHeaderSize = ipv4HeaderSize
+	}
+
+	udpLen := udpHeaderSize + len(pkt.Payload)
+	dataLen := ipHeaderSize + udpLen
+
+	if udpLen > 0xFFFF {
+		return fmt.Errorf("payload is too big")
+	}
+
+	blockLen := 28 + dataLen + padding(dataLen) + 12 + 4
+
+	if cap(w.buf) < blockLen {
+		w.buf = make([]byte, blockLen)
+	}
+	buf := w.buf[:blockLen]
+	clear(buf)
+
+	ts := uint64(pkt.Time.UnixNano())
+
+	binary.LittleEndian.PutUint32(buf[0:], blockTypeEnhancedPacket)
+	binary.LittleEndian.PutUint32(buf[4:], uint32(blockLen))
+	binary.LittleEndian.PutUint32(buf[8:], 0) // interface ID
+	binary.LittleEndian.PutUint32(buf[12:], uint32(ts>>32))
+	binary.LittleEndian.PutUint32(buf[16:], uint32(ts))
+	binary.LittleEndian.PutUint32(buf[20:], uint32(dataLen))
+	binary.LittleEndian.PutUint32(buf[24:], uint32(dataLen))
+
+	data := buf[28 : 28+dataLen]
+	udp := data[ipHeaderSize:]
+
+	if isIPv4 {
+		data[0] = 0x45
+		binary.BigEndian.PutUint16(data[2:], uint16(dataLen))
+		data[8] = 64 // TTL
+		data[9] = 17 // UDP
+		copy(data[12:], src4)
+		copy(data[16:], dst4)
+		binary.BigEndian.PutUint16(data[10:], foldChecksum(checksum(0, data[:ipv4HeaderSize])))
+	} else {
+		data[0] = 0x60
+		binary.BigEndian.PutUint16(data[4:], uint16(udpLen))
+		data[6] = 17 // UDP
+		data[7] = 64 // hop limit
+		copy(data[8:], pkt.Src.IP.To16())
+		copy(data[24:], pkt.Dst.IP.To16())
+	}
+
+	binary.BigEndian.PutUint16(udp[0:], uint16(pkt.Src.Port))
+	binary.BigEndian.PutUint16(udp[2:], uint16(pkt.Dst.Port))
+	binary.BigEndian.PutUint16(udp[4:], uint16(udpLen))
+	copy(udp[udpHeaderSize:], pkt.Payload)
+
+	// the UDP checksum is optional with IPv4 and mandatory with IPv6.
+	if !isIPv4 {
+		sum := checksum(0, data[8:40])
+		sum += uint32(udpLen)
+		sum += 17
+		sum = checksum(sum, udp)
+		cs := foldChecksum(sum)
+		if cs == 0 {
+			cs = 0xFFFF
+		}
+		binary.BigEndian.PutUint16(udp[6:], cs)
+	}
+
+	opts := buf[28+dataLen+padding(dataLen):]
+	binary.LittleEndian.PutUint16(opts[0:], optionEpbFlags)
+	binary.LittleEndian.PutUint16(opts[2:], 4)
+	binary.LittleEndian.PutUint32(opts[4:], uint32(pkt.Direction))
+	binary.LittleEndian.PutUint16(opts[8:], optionEndOfOpt)
+	binary.LittleEndian.PutUint16(opts[10:], 0)
+	binary.LittleEndian.PutUint32(opts[12:], uint32(blockLen))
+
+	_, err := w.W.Write(buf)
+	return err
+}
@@ -0,0 +1,77 @@
+package pcapng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	for _, ca := range []struct {
+		name   string
+		src    *net.UDPAddr
+		dst    *net.UDPAddr
+		header []byte
+	}{
+		{
+			"ipv4",
+			&net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: 8000},
+			&net.UDPAddr{IP: net.ParseIP("192.168.1.3"), Port: 5000},
+			[]byte{
+				0x45, 0x00, 0x00, 0x1f, 0x00, 0x00, 0x00, 0x00,
+				0x40, 0x11, 0xf7, 0x78, 0xc0, 0xa8, 0x01, 0x02,
+				0xc0, 0xa8, 0x01, 0x03, 0x1f, 0x40, 0x13, 0x88,
+				0x00, 0x0b, 0x00, 0x00,
+			},
+		},
+		{
+			"ipv6",
+			&net.UDPAddr{IP: net.ParseIP("::1"), Port: 8000},
+			&net.UDPAddr{IP: net.ParseIP("::1"), Port: 5000},
+			[]byte{
+				0x60, 0x00, 0x00, 0x00, 0x00, 0x0b, 0x11, 0x40,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+				0x1f, 0x40, 0x13, 0x88, 0x00, 0x0b, 0xc9, 0x0c,
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := &Writer{W: &buf}
+			err := w.Initialize()
+			require.NoError(t, err)
+			require.Equal(t, 28+32, buf.Len())
+
+			err = w.WritePacket(&Packet{
+				Time:      time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+				Direction: DirectionOutbound,
+				Src:       ca.src,
+				Dst:       ca.dst,
+				Payload:   []byte{1, 2, 3},
+			})
+			require.NoError(t, err)
+
+			byts := buf.Bytes()[28+32:]
+			dataLen := len(ca.header) + 3
+			blockLen := 28 + dataLen + padding(dataLen) + 16
+
+			require.Equal(t, blockLen, len(byts))
+			require.Equal(t, uint32(blockTypeEnhancedPacket), binary.LittleEndian.Uint32(byts[0:]))
+			require.Equal(t, uint32(blockLen), binary.LittleEndian.Uint32(byts[4:]))
+			require.Equal(t, uint64(1262304000000000000),
+				uint64(binary.LittleEndian.Uint32(byts[12:]))<<32|uint64(binary.LittleEndian.Uint32(byts[16:])))
+			require.Equal(t, uint32(dataLen), binary.LittleEndian.Uint32(byts[20:]))
+			require.Equal(t, ca.header, byts[28:28+len(ca.header)])
+			require.Equal(t, []byte{1, 2, 3}, byts[28+len(ca.header):28+dataLen])
+			require.Equal(t, uint32(DirectionOutbound), binary.LittleEndian.Uint32(byts[blockLen-12:]))
+			require.Equal(t, uint32(blockLen), binary.LittleEndian.Uint32(byts[blockLen-4:]))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package ratemeter contains a rolling-window rate meter.
+package ratemeter
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	numBuckets = 10
+)
+
+type bucket struct {
+	slot    int64
+	bytes   uint64
+	packets uint64
+	frames  uint64
+}
+
+// Rates are rates computed by a Meter.
+type Rates struct {
+	// bits per second.
+	Bitrate float64
+	// packets per second.
+	PacketRate float64
+	// frames per second.
+	FrameRate float64
+}
+
+// Meter computes bitrate, packet rate and frame rate of a flow
+// in a rolling window.
+// It is safe for concurrent use.
+type Meter struct {
+	// duration of the window.
+	Window time.Duration
+
+	bucketDuration time.Duration
+	mutex          sync.Mutex
+	buckets        [numBuckets]bucket
+	first          time.Time
+}
+
+// Initialize initializes Meter.
+func (m *Meter) Initialize() {
+	m.bucketDuration = m.Window / numBuckets
+	for i := range m.buckets {
+		m.buckets[i].slot = -1
+	}
+}
+
+// Add adds a packet of n bytes.
+// frameEnd tells whether the packet is the last one of a frame.
+func (m *Meter) Add(now time.Time, n int, frameEnd bool) {
+	slot := now.UnixNano() / int64(m.bucketDuration)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.first.IsZero() {
+		m.first = now
+	}
+
+	b := &m.buckets[slot%numBuckets]
+	if b.slot != slot {
+		*b = bucket{slot: slot}
+	}
+
+	b.bytes += uint64(n)
+	b.packets++
+	if frameEnd {
+		b.frames++
+	}
+}
+
+// Rates returns the rates of the window that ends at the given time.
+func (m *Meter) Rates(now time.Time) Rates {
+	slot := now.UnixNano() / int64(m.bucketDuration)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.first.IsZero() {
+		return Rates{}
+	}
+
+	var bytes, packets, frames uint64
+
+	for _, b := range m.buckets {
+		if b.slot > (slot-numBuckets) && b.slot <= slot {
+			bytes += b.bytes
+			packets += b.packets
+			frames += b.frames
+		}
+	}
+
+	// the window covers the previous buckets and the elapsed part of the current one,
+	// and can't be longer than the time elapsed since the first packet.
+	// A minimum of one bucket prevents huge rates right after the first packet.
+	elapsed := time.Duration(numBuckets-1)*m.bucketDuration +
+		time.Duration(now.UnixNano()-slot*int64(m.bucketDuration))
+	if d := now.Sub(m.first); d < elapsed {
+		elapsed = max(d, m.bucketDuration)
+	}
+
+	secs := elapsed.Seconds()
+
+	return Rates{
+		Bitrate:    float64(bytes*8) / secs,
+		PacketRate: float64(packets) / secs,
+		FrameRate:  float64(frames) / secs,
+	}
+}
@@ -0,0 +1,62 @@
+package ratemeter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeter(t *testing.T) {
+	m := &Meter{
+		Window: 1 * time.Second,
+	}
+	m.Initialize()
+
+	now := time.Date(2008, 5, 20, 22, 15, 20, 0, time.UTC)
+
+	require.Equal(t, Rates{}, m.Rates(now))
+
+	// 1 second of a 50 fps video, 2 packets per frame, 1000 bytes per packet
+	for i := 0; i < 100; i++ {
+		m.Add(now, 1000, (i%2) == 1)
+		now = now.Add(10 * time.Millisecond)
+	}
+
+	require.Equal(t, Rates{
+		Bitrate:    800000,
+		PacketRate: 100,
+		FrameRate:  50,
+	}, m.Rates(now))
+
+	// packets stop, the window contains 40 packets in 0.9 seconds
+	now = now.Add(500 * time.Millisecond)
+
+	r := m.Rates(now)
+	require.InDelta(t, 355555.55, r.Bitrate, 0.01)
+	require.InDelta(t, 44.44, r.PacketRate, 0.01)
+	require.InDelta(t, 22.22, r.FrameRate, 0.01)
+
+	now = now.Add(1 * time.Second)
+
+	require.Equal(t, Rates{}, m.Rates(now))
+}
+
+func TestMeterStart(t *testing.T) {
+	m := &Meter{
+		Window: 1 * time.Second,
+	}
+	m.Initialize()
+
+	now := time.Date(2008, 5, 20, 22, 15, 20, 0, time.UTC)
+
+	for i := 0; i < 10; i++ {
+		m.Add(now, 1000, false)
+		now = now.Add(50 * time.Millisecond)
+	}
+
+	require.Equal(t, Rates{
+		Bitrate:    160000,
+		PacketRate: 20,
+	}, m.Rates(now))
+}
@@ -4,7 +4,10 @@ package rtcpreceiver
 import (
 	"crypto/rand"
 	"fmt"
+	"math"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/rtcp"
@@ -18,6 +21,13 @@ func ntpTimeRTCPToGo(v uint64) time.Time {
 	return time.Unix(0, nano)
 }
 
+// seconds since 1st January 1900
+// higher 32 bits are the integer part, lower 32 bits are the fractional part
+func ntpTimeGoToRTCP(v time.Time) uint64 {
+	s := uint64(v.UnixNano()) + 2208988800*1000000000
+	return (s/1000000000)<<32 | (s % 1000000000)
+}
+
 func randUint32() (uint32, error) {
 	var b [4]byte
 	_, err := rand.Read(b[:])
@@ -27,6 +37,62 @@ func randUint32() (uint32, error) {
 	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
 }
 
+// statsSnapshot contains statistics that can be read without locking.
+// Writers hold the RTCPReceiver mutex and increase the generation before and after
+// updating fields, therefore the generation is odd while an update is in progress.
+// Readers retry until they read the same even generation before and after reading fields.
+type statsSnapshot struct {
+	generation            atomic.Uint64
+	valid                 atomic.Bool
+	remoteSSRC            atomic.Uint32
+	lastSequenceNumber    atomic.Uint32
+	lastRTP               atomic.Uint32
+	jitter                atomic.Uint64
+	ntpReferenceAvailable atomic.Bool
+	ntpReferenceNTP       atomic.Uint64
+	ntpReferenceRTP       atomic.Uint32
+}
+
+func (s *statsSnapshot) load(clockRate int) *Stats {
+	for {
+		gen := s.generation.Load()
+		if (gen % 2) != 0 {
+			runtime.Gosched()
+			continue
+		}
+
+		if !s.valid.Load() {
+			return nil
+		}
+
+		ret := &Stats{
+			RemoteSSRC:         s.remoteSSRC.Load(),
+			LastSequenceNumber: uint16(s.lastSequenceNumber.Load()),
+			LastRTP:            s.lastRTP.Load(),
+			Jitter:             math.Float64frombits(s.jitter.Load()),
+		}
+
+		if s.ntpReferenceAvailable.Load() {
+			ret.LastNTP = packetNTP(
+				s.ntpReferenceNTP.Load(),
+				s.ntpReferenceRTP.Load(),
+				clockRate,
+				ret.LastRTP)
+		}
+
+		if s.generation.Load() == gen {
+			return ret
+		}
+	}
+}
+
+func packetNTP(refNTP uint64, refRTP uint32, clockRate int, ts uint32) time.Time {
+	timeDiff := int32(ts - refRTP)
+	timeDiffGo := (time.Duration(timeDiff) * time.Second) / time.Duration(clockRate)
+
+	return ntpTimeRTCPToGo(refNTP).Add(timeDiffGo)
+}
+
 // RTCPReceiver is a utility to generate RTCP receiver reports.
 type RTCPReceiver struct {
 	ClockRate       int
@@ -35,7 +101,7 @@ type RTCPReceiver struct {
 	TimeNow         func() time.Time
 	WritePacketRTCP func(rtcp.Packet)
 
-	mutex sync.RWMutex
+	mutex sync.Mutex
 
 	// data from RTP packets
 	firstRTPPacketReceived bool
@@ -56,6 +122,16 @@ type RTCPReceiver struct {
 	lastSenderReportTimeRTP    uint32
 	lastSenderReportTimeSystem time.Time
 
+	// data from external clock sources
+	clockSyncReceived      bool
+	clockSyncTimeNTP       uint64
+	clockSyncTimeRTP       uint32
+	clockSyncPending       bool
+	clockSyncPendingNTP    time.Time
+	clockSyncPendingSystem time.Time
+
+	stats statsSnapshot
+
 	terminate chan struct{}
 	done      chan struct{}
 }
@@ -215,6 +291,13 @@ func (rr *RTCPReceiver) ProcessPacketRTP(pkt *rtp.Packet, system time.Time, ptsE
 		}
 	}
 
+	if rr.clockSyncPending && rr.timeInitialized {
+		rr.clockSyncPending = false
+		rr.applyClockSync(rr.clockSyncPendingNTP, rr.clockSyncPendingSystem)
+	}
+
+	rr.updateStats()
+
 	return nil
 }
 
@@ -227,17 +310,75 @@ func (rr *RTCPReceiver) ProcessSenderReport(sr *rtcp.SenderReport, system time.T
 	rr.lastSenderReportTimeNTP = sr.NTPTime
 	rr.lastSenderReportTimeRTP = sr.RTPTime
 	rr.lastSenderReportTimeSystem = system
+
+	rr.updateStats()
+}
+
+// ProcessClockSync extracts the needed data from an external clock source.
+// ntp is the absolute time of the stream at the given system time.
+// It is used to compute NTP timestamps only when RTCP sender reports are absent.
+func (rr *RTCPReceiver) ProcessClockSync(ntp time.Time, system time.Time) {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+
+	// the clock source may be queried before RTP packets are received,
+	// therefore its value is applied when the first packet arrives.
+	if !rr.timeInitialized {
+		rr.clockSyncPending = true
+		rr.clockSyncPendingNTP = ntp
+		rr.clockSyncPendingSystem = system
+		return
+	}
+
+	rr.applyClockSync(ntp, system)
+
+	rr.updateStats()
+}
+
+func (rr *RTCPReceiver) applyClockSync(ntp time.Time, system time.Time) {
+	rr.clockSyncReceived = true
+	rr.clockSyncTimeNTP = ntpTimeGoToRTCP(ntp.Add(-system.Sub(rr.lastTimeSystem)))
+	rr.clockSyncTimeRTP = rr.lastTimeRTP
+}
+
+func (rr *RTCPReceiver) ntpReference() (uint64, uint32, bool) {
+	if rr.firstSenderReportReceived {
+		return rr.lastSenderReportTimeNTP, rr.lastSenderReportTimeRTP, true
+	}
+
+	if rr.clockSyncReceived {
+		return rr.clockSyncTimeNTP, rr.clockSyncTimeRTP, true
+	}
+
+	return 0, 0, false
+}
+
+func (rr *RTCPReceiver) updateStats() {
+	if !rr.firstRTPPacketReceived && !rr.firstSenderReportReceived {
+		return
+	}
+
+	refNTP, refRTP, refOK := rr.ntpReference()
+
+	rr.stats.generation.Add(1)
+	rr.stats.valid.Store(rr.firstRTPPacketReceived)
+	rr.stats.remoteSSRC.Store(rr.remoteSSRC)
+	rr.stats.lastSequenceNumber.Store(uint32(rr.lastSequenceNumber))
+	rr.stats.lastRTP.Store(rr.lastTimeRTP)
+	rr.stats.jitter.Store(math.Float64bits(rr.jitter))
+	rr.stats.ntpReferenceAvailable.Store(refOK)
+	rr.stats.ntpReferenceNTP.Store(refNTP)
+	rr.stats.ntpReferenceRTP.Store(refRTP)
+	rr.stats.generation.Add(1)
 }
 
 func (rr *RTCPReceiver) packetNTPUnsafe(ts uint32) (time.Time, bool) {
-	if !rr.firstSenderReportReceived {
+	refNTP, refRTP, ok := rr.ntpReference()
+	if !ok {
 		return time.Time{}, false
 	}
 
-	timeDiff := int32(ts - rr.lastSenderReportTimeRTP)
-	timeDiffGo := (time.Duration(timeDiff) * time.Second) / time.Duration(rr.ClockRate)
-
-	return ntpTimeRTCPToGo(rr.lastSenderReportTimeNTP).Add(timeDiffGo), true
+	return packetNTP(refNTP, refRTP, rr.ClockRate, ts), true
 }
 
 // PacketNTP returns the NTP timestamp of the packet.
@@ -258,21 +399,8 @@ type Stats struct {
 }
 
 // Stats returns statistics.
+// It never locks, therefore it can be called frequently without slowing down ProcessPacketRTP().
+// Returned fields are always consistent with each other.
 func (rr *RTCPReceiver) Stats() *Stats {
-	rr.mutex.RLock()
-	defer rr.mutex.RUnlock()
-
-	if !rr.firstRTPPacketReceived {
-		return nil
-	}
-
-	ntp, _ := rr.packetNTPUnsafe(rr.lastTimeRTP)
-
-	return &Stats{
-		RemoteSSRC:         rr.remoteSSRC,
-		LastSequenceNumber: rr.lastSequenceNumber,
-		LastRTP:            rr.lastTimeRTP,
-		LastNTP:            ntp,
-		Jitter:             rr.jitter,
-	}
+	return rr.stats.load(rr.ClockRate)
 }
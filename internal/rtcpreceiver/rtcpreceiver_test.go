@@ -400,3 +400,87 @@ func TestRTCPReceiverJitter(t *testing.T) {
 
 	<-done
 }
+
+func TestRTCPReceiverClockSync(t *testing.T) {
+	rr := &RTCPReceiver{
+		ClockRate:       90000,
+		LocalSSRC:       uint32Ptr(0x65f83afb),
+		Period:          500 * time.Millisecond,
+		WritePacketRTCP: func(_ rtcp.Packet) {},
+	}
+	err := rr.Initialize()
+	require.NoError(t, err)
+	defer rr.Close()
+
+	rtpPkt := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 946,
+			Timestamp:      0xafb45733,
+			SSRC:           0xba9da416,
+		},
+		Payload: []byte("\x00\x00"),
+	}
+	ts := time.Date(2008, 0o5, 20, 22, 15, 20, 0, time.UTC)
+	err = rr.ProcessPacketRTP(&rtpPkt, ts, true)
+	require.NoError(t, err)
+
+	_, ok := rr.PacketNTP(0xafb45733)
+	require.False(t, ok)
+
+	rr.ProcessClockSync(time.Date(2015, 0o3, 10, 10, 0, 1, 0, time.UTC),
+		time.Date(2008, 0o5, 20, 22, 15, 21, 0, time.UTC))
+
+	ntp, ok := rr.PacketNTP(0xafb45733 + 90000)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2015, 0o3, 10, 10, 0, 1, 0, time.UTC), ntp.UTC())
+
+	stats := rr.Stats()
+	require.Equal(t, time.Date(2015, 0o3, 10, 10, 0, 0, 0, time.UTC), stats.LastNTP.UTC())
+
+	// sender reports have priority over external clock sources
+	srPkt := rtcp.SenderReport{
+		SSRC:    0xba9da416,
+		NTPTime: 0xe363887a17ced916,
+		RTPTime: 0xafb45733,
+	}
+	rr.ProcessSenderReport(&srPkt, ts)
+
+	ntp, ok = rr.PacketNTP(0xafb45733)
+	require.True(t, ok)
+	require.Equal(t, ntpTimeRTCPToGo(0xe363887a17ced916), ntp)
+}
+
+func TestRTCPReceiverClockSyncBeforePackets(t *testing.T) {
+	rr := &RTCPReceiver{
+		ClockRate:       90000,
+		LocalSSRC:       uint32Ptr(0x65f83afb),
+		Period:          500 * time.Millisecond,
+		WritePacketRTCP: func(_ rtcp.Packet) {},
+	}
+	err := rr.Initialize()
+	require.NoError(t, err)
+	defer rr.Close()
+
+	rr.ProcessClockSync(time.Date(2015, 0o3, 10, 10, 0, 0, 0, time.UTC),
+		time.Date(2008, 0o5, 20, 22, 15, 19, 0, time.UTC))
+
+	err = rr.ProcessPacketRTP(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 946,
+			Timestamp:      0xafb45733,
+			SSRC:           0xba9da416,
+		},
+		Payload: []byte("\x00\x00"),
+	}, time.Date(2008, 0o5, 20, 22, 15, 20, 0, time.UTC), true)
+	require.NoError(t, err)
+
+	ntp, ok := rr.PacketNTP(0xafb45733 + 90000)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2015, 0o3, 10, 10, 0, 2, 0, time.UTC), ntp.UTC())
+}
@@ -2,7 +2,9 @@
 package rtcpsender
 
 import (
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/rtcp"
@@ -16,6 +18,44 @@ func ntpTimeGoToRTCP(v time.Time) uint64 {
 	return (s/1000000000)<<32 | (s % 1000000000)
 }
 
+// statsSnapshot contains statistics that can be read without locking.
+// Writers hold the RTCPSender mutex and increase the generation before and after
+// updating fields, therefore the generation is odd while an update is in progress.
+// Readers retry until they read the same even generation before and after reading fields.
+type statsSnapshot struct {
+	generation         atomic.Uint64
+	valid              atomic.Bool
+	localSSRC          atomic.Uint32
+	lastSequenceNumber atomic.Uint32
+	lastRTP            atomic.Uint32
+	lastNTP            atomic.Int64
+}
+
+func (s *statsSnapshot) load() *Stats {
+	for {
+		gen := s.generation.Load()
+		if (gen % 2) != 0 {
+			runtime.Gosched()
+			continue
+		}
+
+		if !s.valid.Load() {
+			return nil
+		}
+
+		ret := &Stats{
+			LocalSSRC:          s.localSSRC.Load(),
+			LastSequenceNumber: uint16(s.lastSequenceNumber.Load()),
+			LastRTP:            s.lastRTP.Load(),
+			LastNTP:            time.Unix(0, s.lastNTP.Load()),
+		}
+
+		if s.generation.Load() == gen {
+			return ret
+		}
+	}
+}
+
 // RTCPSender is a utility to generate RTCP sender reports.
 type RTCPSender struct {
 	ClockRate       int
@@ -23,7 +63,13 @@ type RTCPSender struct {
 	TimeNow         func() time.Time
 	WritePacketRTCP func(rtcp.Packet)
 
-	mutex sync.RWMutex
+	// canonical name and user name, written into SDES packets
+	// that are sent together with sender reports.
+	// SDES packets are sent only if CNAME is not empty.
+	CNAME string
+	Name  string
+
+	mutex sync.Mutex
 
 	// data from RTP packets
 	firstRTPPacketSent bool
@@ -35,6 +81,8 @@ type RTCPSender struct {
 	packetCount        uint32
 	octetCount         uint32
 
+	stats statsSnapshot
+
 	terminate chan struct{}
 	done      chan struct{}
 }
@@ -89,13 +137,39 @@ func (rs *RTCPSender) report() rtcp.Packet {
 	ntpTime := rs.lastTimeNTP.Add(systemTimeDiff)
 	rtpTime := rs.lastTimeRTP + uint32(systemTimeDiff.Seconds()*float64(rs.ClockRate))
 
-	return &rtcp.SenderReport{
+	sr := &rtcp.SenderReport{
 		SSRC:        rs.localSSRC,
 		NTPTime:     ntpTimeGoToRTCP(ntpTime),
 		RTPTime:     rtpTime,
 		PacketCount: rs.packetCount,
 		OctetCount:  rs.octetCount,
 	}
+
+	if rs.CNAME == "" {
+		return sr
+	}
+
+	items := []rtcp.SourceDescriptionItem{{
+		Type: rtcp.SDESCNAME,
+		Text: rs.CNAME,
+	}}
+
+	if rs.Name != "" {
+		items = append(items, rtcp.SourceDescriptionItem{
+			Type: rtcp.SDESName,
+			Text: rs.Name,
+		})
+	}
+
+	return &rtcp.CompoundPacket{
+		sr,
+		&rtcp.SourceDescription{
+			Chunks: []rtcp.SourceDescriptionChunk{{
+				Source: rs.localSSRC,
+				Items:  items,
+			}},
+		},
+	}
 }
 
 // ProcessPacketRTP extracts data from RTP packets.
@@ -115,6 +189,22 @@ func (rs *RTCPSender) ProcessPacketRTP(pkt *rtp.Packet, ntp time.Time, ptsEquals
 
 	rs.packetCount++
 	rs.octetCount += uint32(len(pkt.Payload))
+
+	rs.updateStats()
+}
+
+func (rs *RTCPSender) updateStats() {
+	if !rs.firstRTPPacketSent {
+		return
+	}
+
+	rs.stats.generation.Add(1)
+	rs.stats.valid.Store(true)
+	rs.stats.localSSRC.Store(rs.localSSRC)
+	rs.stats.lastSequenceNumber.Store(uint32(rs.lastSequenceNumber))
+	rs.stats.lastRTP.Store(rs.lastTimeRTP)
+	rs.stats.lastNTP.Store(rs.lastTimeNTP.UnixNano())
+	rs.stats.generation.Add(1)
 }
 
 // Stats are statistics.
@@ -126,18 +216,8 @@ type Stats struct {
 }
 
 // Stats returns statistics.
+// It never locks, therefore it can be called frequently without slowing down ProcessPacketRTP().
+// Returned fields are always consistent with each other.
 func (rs *RTCPSender) Stats() *Stats {
-	rs.mutex.RLock()
-	defer rs.mutex.RUnlock()
-
-	if !rs.firstRTPPacketSent {
-		return nil
-	}
-
-	return &Stats{
-		LocalSSRC:          rs.localSSRC,
-		LastSequenceNumber: rs.lastSequenceNumber,
-		LastRTP:            rs.lastTimeRTP,
-		LastNTP:            rs.lastTimeNTP,
-	}
+	return rs.stats.load()
 }
@@ -98,3 +98,99 @@ func TestRTCPSender(t *testing.T) {
 
 	<-sent
 }
+
+func TestRTCPSenderStatsConcurrent(t *testing.T) {
+	rs := &RTCPSender{
+		ClockRate:       90000,
+		Period:          time.Hour,
+		WritePacketRTCP: func(rtcp.Packet) {},
+	}
+	rs.Initialize()
+	defer rs.Close()
+
+	require.Nil(t, rs.Stats())
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 1000; i++ {
+			rs.ProcessPacketRTP(&rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					PayloadType:    96,
+					SequenceNumber: uint16(i),
+					Timestamp:      uint32(i * 2),
+					SSRC:           0xba9da416,
+				},
+			}, time.Unix(int64(i), 0), true)
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			stats := rs.Stats()
+			require.Equal(t, uint16(999), stats.LastSequenceNumber)
+			require.Equal(t, uint32(1998), stats.LastRTP)
+			return
+
+		default:
+			stats := rs.Stats()
+			if stats != nil {
+				require.Equal(t, uint32(0xba9da416), stats.LocalSSRC)
+				require.Equal(t, uint32(stats.LastSequenceNumber)*2, stats.LastRTP)
+				require.Equal(t, int64(stats.LastSequenceNumber), stats.LastNTP.Unix())
+			}
+		}
+	}
+}
+
+func TestRTCPSenderSourceDescription(t *testing.T) {
+	rs := &RTCPSender{
+		ClockRate: 90000,
+		Period:    1 * time.Hour,
+		TimeNow: func() time.Time {
+			return time.Date(2008, 5, 20, 22, 16, 20, 0, time.UTC)
+		},
+		WritePacketRTCP: func(_ rtcp.Packet) {},
+		CNAME:           "mycname",
+		Name:            "myname",
+	}
+	rs.Initialize()
+	defer rs.Close()
+
+	rs.ProcessPacketRTP(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 946,
+			Timestamp:      1287987768,
+			SSRC:           0xba9da416,
+		},
+		Payload: []byte("\x00\x00"),
+	}, time.Date(2008, 5, 20, 22, 15, 20, 0, time.UTC), true)
+
+	pkt := rs.report()
+	cp, ok := pkt.(*rtcp.CompoundPacket)
+	require.True(t, ok)
+	require.Len(t, *cp, 2)
+
+	_, ok = (*cp)[0].(*rtcp.SenderReport)
+	require.True(t, ok)
+
+	require.Equal(t, &rtcp.SourceDescription{
+		Chunks: []rtcp.SourceDescriptionChunk{{
+			Source: 0xba9da416,
+			Items: []rtcp.SourceDescriptionItem{
+				{Type: rtcp.SDESCNAME, Text: "mycname"},
+				{Type: rtcp.SDESName, Text: "myname"},
+			},
+		}},
+	}, (*cp)[1])
+
+	_, err := pkt.Marshal()
+	require.NoError(t, err)
+}
@@ -0,0 +1,110 @@
+// Package rtpnormalizer contains a utility to normalize RTP packets.
+package rtpnormalizer
+
+import (
+	"crypto/rand"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Normalizer rewrites SSRC, sequence numbers and timestamps of RTP packets,
+// in order to hide discontinuities caused by restarts or SSRC changes of the source.
+// Gaps and reorderings of the source are preserved.
+type Normalizer struct {
+	// clock rate of the format.
+	ClockRate int
+
+	mutex sync.Mutex
+
+	ssrc        uint32
+	initialized bool
+	sourceSSRC  uint32
+	seqDelta    uint16
+	tsDelta     uint32
+	lastSeq     uint16
+	lastTS      uint32
+	lastNTP     time.Time
+}
+
+// Initialize initializes a Normalizer.
+func (n *Normalizer) Initialize() error {
+	var err error
+	n.ssrc, err = randUint32()
+	if err != nil {
+		return err
+	}
+
+	initialSeq, err := randUint32()
+	if err != nil {
+		return err
+	}
+	n.lastSeq = uint16(initialSeq)
+
+	n.lastTS, err = randUint32()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SSRC returns the SSRC of outgoing packets.
+func (n *Normalizer) SSRC() uint32 {
+	return n.ssrc
+}
+
+// Process normalizes a RTP packet.
+// The input packet is not modified.
+func (n *Normalizer) Process(pkt *rtp.Packet, ntp time.Time) *rtp.Packet {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	switch {
+	case !n.initialized:
+		n.initialized = true
+		n.sourceSSRC = pkt.SSRC
+		n.seqDelta = n.lastSeq - pkt.SequenceNumber
+		n.tsDelta = n.lastTS - pkt.Timestamp
+
+	// the source changed SSRC: continue from the last outgoing packet
+	case pkt.SSRC != n.sourceSSRC:
+		n.sourceSSRC = pkt.SSRC
+		n.seqDelta = n.lastSeq + 1 - pkt.SequenceNumber
+
+		ts := n.lastTS
+		if elapsed := ntp.Sub(n.lastNTP); elapsed > 0 {
+			ts += uint32(math.Round(elapsed.Seconds() * float64(n.ClockRate)))
+		}
+		n.tsDelta = ts - pkt.Timestamp
+	}
+
+	out := &rtp.Packet{
+		Header:      pkt.Header,
+		Payload:     pkt.Payload,
+		PaddingSize: pkt.PaddingSize,
+	}
+	out.SSRC = n.ssrc
+	out.SequenceNumber = pkt.SequenceNumber + n.seqDelta
+	out.Timestamp = pkt.Timestamp + n.tsDelta
+
+	// do not move backwards in case of reordered packets
+	if diff := int16(out.SequenceNumber - n.lastSeq); diff >= 0 {
+		n.lastSeq = out.SequenceNumber
+		n.lastTS = out.Timestamp
+		n.lastNTP = ntp
+	}
+
+	return out
+}
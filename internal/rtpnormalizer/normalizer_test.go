@@ -0,0 +1,74 @@
+package rtpnormalizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizer(t *testing.T) {
+	n := &Normalizer{
+		ClockRate: 90000,
+	}
+	err := n.Initialize()
+	require.NoError(t, err)
+
+	ntp := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	in := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 100,
+			Timestamp:      1000,
+			SSRC:           0x11223344,
+		},
+		Payload: []byte{1, 2, 3},
+	}
+	first := n.Process(in, ntp)
+	require.Equal(t, n.SSRC(), first.SSRC)
+	require.Equal(t, []byte{1, 2, 3}, first.Payload)
+	require.Equal(t, uint32(0x11223344), in.SSRC)
+	require.Equal(t, uint16(100), in.SequenceNumber)
+
+	// gaps are preserved
+	out := n.Process(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 102,
+			Timestamp:      4000,
+			SSRC:           0x11223344,
+		},
+	}, ntp.Add(1*time.Second/30))
+	require.Equal(t, first.SequenceNumber+2, out.SequenceNumber)
+	require.Equal(t, first.Timestamp+3000, out.Timestamp)
+
+	// source restarted with another SSRC
+	out = n.Process(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 5000,
+			Timestamp:      123456,
+			SSRC:           0x55667788,
+		},
+	}, ntp.Add(2*time.Second/30))
+	require.Equal(t, n.SSRC(), out.SSRC)
+	require.Equal(t, first.SequenceNumber+3, out.SequenceNumber)
+	require.Equal(t, first.Timestamp+6000, out.Timestamp)
+
+	out = n.Process(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 5001,
+			Timestamp:      126456,
+			SSRC:           0x55667788,
+		},
+	}, ntp.Add(3*time.Second/30))
+	require.Equal(t, first.SequenceNumber+4, out.SequenceNumber)
+	require.Equal(t, first.Timestamp+9000, out.Timestamp)
+}
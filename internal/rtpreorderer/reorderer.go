@@ -13,6 +13,9 @@ const (
 // - order packets
 // - remove duplicate packets
 type Reorderer struct {
+	// called when a packet is discarded (optional).
+	OnDiscard func(*rtp.Packet)
+
 	initialized    bool
 	expectedSeqNum uint16
 	buffer         []*rtp.Packet
@@ -49,7 +52,10 @@ func (r *Reorderer) Process(pkt *rtp.Packet) ([]*rtp.Packet, uint) {
 			// clear buffer
 			for i := uint16(0); i < bufferSize; i++ {
 				p := (r.absPos + i) & (bufferSize - 1)
-				r.buffer[p] = nil
+				if r.buffer[p] != nil {
+					r.discard(r.buffer[p])
+					r.buffer[p] = nil
+				}
 			}
 
 			// reset position
@@ -57,6 +63,7 @@ func (r *Reorderer) Process(pkt *rtp.Packet) ([]*rtp.Packet, uint) {
 			return []*rtp.Packet{pkt}, 0
 		}
 
+		r.discard(pkt)
 		return nil, 0
 	}
 	r.negativeCount = 0
@@ -95,6 +102,7 @@ func (r *Reorderer) Process(pkt *rtp.Packet) ([]*rtp.Packet, uint) {
 
 		// current packet is a duplicate. discard
 		if r.buffer[p] != nil {
+			r.discard(pkt)
 			return nil, 0
 		}
 
@@ -131,3 +139,9 @@ func (r *Reorderer) Process(pkt *rtp.Packet) ([]*rtp.Packet, uint) {
 
 	return ret, 0
 }
+
+func (r *Reorderer) discard(pkt *rtp.Packet) {
+	if r.OnDiscard != nil {
+		r.OnDiscard(pkt)
+	}
+}
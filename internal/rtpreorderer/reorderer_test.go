@@ -258,3 +258,24 @@ func TestReset(t *testing.T) {
 	}}, out)
 	require.Equal(t, uint(0), missing)
 }
+
+func TestDiscard(t *testing.T) {
+	var discarded []uint16
+
+	r := &Reorderer{
+		OnDiscard: func(pkt *rtp.Packet) {
+			discarded = append(discarded, pkt.SequenceNumber)
+		},
+	}
+	r.Initialize()
+
+	for _, seq := range []uint16{100, 99, 102, 102, 101} {
+		r.Process(&rtp.Packet{
+			Header: rtp.Header{
+				SequenceNumber: seq,
+			},
+		})
+	}
+
+	require.Equal(t, []uint16{99, 102}, discarded)
+}
@@ -0,0 +1,16 @@
+// Package sockbuf contains utilities to read the size of socket buffers.
+package sockbuf
+
+import (
+	"syscall"
+)
+
+// ReadBufferSize returns the size of the receive buffer of a socket.
+func ReadBufferSize(c syscall.Conn) (int, error) {
+	return getBufferSize(c, false)
+}
+
+// WriteBufferSize returns the size of the transmit buffer of a socket.
+func WriteBufferSize(c syscall.Conn) (int, error) {
+	return getBufferSize(c, true)
+}
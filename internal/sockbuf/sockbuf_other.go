@@ -0,0 +1,12 @@
+//go:build !unix
+
+package sockbuf
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func getBufferSize(_ syscall.Conn, _ bool) (int, error) {
+	return 0, fmt.Errorf("unsupported on this platform")
+}
@@ -0,0 +1,32 @@
+//go:build unix
+
+package sockbuf
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferSize(t *testing.T) {
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	uc := pc.(*net.UDPConn)
+
+	err = uc.SetReadBuffer(0x10000)
+	require.NoError(t, err)
+
+	err = uc.SetWriteBuffer(0x10000)
+	require.NoError(t, err)
+
+	v, err := ReadBufferSize(uc)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, v, 0x10000)
+
+	v, err = WriteBufferSize(uc)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, v, 0x10000)
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package sockbuf
+
+import (
+	"syscall"
+)
+
+func getBufferSize(c syscall.Conn, write bool) (int, error) {
+	rc, err := c.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	opt := syscall.SO_RCVBUF
+	if write {
+		opt = syscall.SO_SNDBUF
+	}
+
+	var v int
+	var err2 error
+
+	err = rc.Control(func(fd uintptr) {
+		v, err2 = syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, opt)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err2 != nil {
+		return 0, err2
+	}
+
+	return v, nil
+}
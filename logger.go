@@ -0,0 +1,55 @@
+package gortsplib
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger is a structured logger.
+// Arguments are alternating keys and values, as in log/slog,
+// therefore a *slog.Logger can be used as Logger.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// stdLogger is the default Logger.
+// It writes entries to the standard logger and discards debug entries.
+type stdLogger struct{}
+
+func (stdLogger) Debug(_ string, _ ...interface{}) {
+}
+
+func (l stdLogger) Info(msg string, args ...interface{}) {
+	l.print(msg, args)
+}
+
+func (l stdLogger) Warn(msg string, args ...interface{}) {
+	l.print(msg, args)
+}
+
+func (l stdLogger) Error(msg string, args ...interface{}) {
+	l.print(msg, args)
+}
+
+func (stdLogger) print(msg string, args []interface{}) {
+	log.Println(formatLogEntry(msg, args))
+}
+
+func formatLogEntry(msg string, args []interface{}) string {
+	var b strings.Builder
+	b.WriteString(msg)
+
+	for i := 0; i < len(args); i += 2 {
+		if i == (len(args) - 1) {
+			fmt.Fprintf(&b, " %v", args[i])
+		} else {
+			fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+		}
+	}
+
+	return b.String()
+}
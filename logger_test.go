@@ -0,0 +1,25 @@
+package gortsplib
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatLogEntry(t *testing.T) {
+	require.Equal(t, "message", formatLogEntry("message", nil))
+	require.Equal(t, "message session=abc lost=5",
+		formatLogEntry("message", []interface{}{"session", "abc", "lost", 5}))
+	require.Equal(t, "message session=abc extra",
+		formatLogEntry("message", []interface{}{"session", "abc", "extra"}))
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	var l Logger = slog.New(slog.NewTextHandler(&buf, nil))
+
+	l.Warn("message", "session", "abc")
+	require.Contains(t, buf.String(), "level=WARN msg=message session=abc")
+}
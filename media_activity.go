@@ -0,0 +1,26 @@
+package gortsplib
+
+import (
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+)
+
+// MediaActivity is the expected activity of a media.
+type MediaActivity int
+
+// activities.
+const (
+	// packets are received continuously, like in case of audio and video.
+	// When no packets are received within ReadTimeout, the stream is considered dead.
+	MediaActivityContinuous MediaActivity = iota
+
+	// packets are received sporadically, like in case of KLV metadata or real-time text.
+	// Sparse medias have their own timeout and are never used as timing reference.
+	MediaActivitySparse
+)
+
+func defaultMediaActivity(medi *description.Media) MediaActivity {
+	if medi.Type == description.MediaTypeApplication || medi.Type == description.MediaTypeText {
+		return MediaActivitySparse
+	}
+	return MediaActivityContinuous
+}
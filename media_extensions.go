@@ -0,0 +1,106 @@
+package gortsplib
+
+import (
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/rtpextension"
+)
+
+// mediaExtensions contains IDs of the RTP header extensions
+// that are generated automatically when writing packets,
+// since they are declared with extmap attributes in the media.
+type mediaExtensions struct {
+	media                *description.Media
+	absSendTimeID        uint8
+	transmissionOffsetID uint8
+}
+
+func newMediaExtensions(medi *description.Media) mediaExtensions {
+	e := mediaExtensions{media: medi}
+
+	if len(medi.Extensions) != 0 {
+		e.absSendTimeID, _ = medi.ExtensionID(rtpextension.AbsSendTimeURI)
+		e.transmissionOffsetID, _ = medi.ExtensionID(rtpextension.TransmissionOffsetURI)
+	}
+
+	return e
+}
+
+func (e mediaExtensions) enabled() bool {
+	return e.absSendTimeID != 0 || e.transmissionOffsetID != 0
+}
+
+// apply returns a copy of the packet with extensions,
+// in order not to edit packets of the caller.
+func (e mediaExtensions) apply(pkt *rtp.Packet, now time.Time, ntp time.Time) (*rtp.Packet, error) {
+	ret := *pkt
+	ret.Header.Extensions = append([]rtp.Extension(nil), pkt.Header.Extensions...)
+
+	if e.absSendTimeID != 0 {
+		err := rtpextension.Set(&ret.Header, e.absSendTimeID, rtpextension.NewAbsSendTime(now).Marshal())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if e.transmissionOffsetID != 0 {
+		var clockRate int
+		for _, forma := range e.media.Formats {
+			if forma.PayloadType() == pkt.PayloadType {
+				clockRate = forma.ClockRate()
+				break
+			}
+		}
+
+		offset := int64(now.Sub(ntp).Seconds() * float64(clockRate))
+		err := rtpextension.Set(&ret.Header, e.transmissionOffsetID, rtpextension.NewTransmissionOffset(offset).Marshal())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ret, nil
+}
+
+func packetAbsSendTime(medi *description.Media, pkt *rtp.Packet, now time.Time) (time.Time, bool) {
+	id, ok := medi.ExtensionID(rtpextension.AbsSendTimeURI)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	buf, ok := rtpextension.Get(&pkt.Header, id)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	var ext rtpextension.AbsSendTime
+	err := ext.Unmarshal(buf)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return ext.Time(now), true
+}
+
+func packetTransmissionOffset(medi *description.Media, pkt *rtp.Packet) (int32, bool) {
+	id, ok := medi.ExtensionID(rtpextension.TransmissionOffsetURI)
+	if !ok {
+		return 0, false
+	}
+
+	buf, ok := rtpextension.Get(&pkt.Header, id)
+	if !ok {
+		return 0, false
+	}
+
+	var ext rtpextension.TransmissionOffset
+	err := ext.Unmarshal(buf)
+	if err != nil {
+		return 0, false
+	}
+
+	return ext.Offset, true
+}
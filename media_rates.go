@@ -0,0 +1,39 @@
+package gortsplib
+
+import (
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/internal/ratemeter"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+)
+
+// mediaRates measures rates of RTP packets received and sent by a media.
+// Frames are counted only with video medias, whose frames end with the RTP marker bit.
+type mediaRates struct {
+	media *description.Media
+
+	isVideo  bool
+	received *ratemeter.Meter
+	sent     *ratemeter.Meter
+}
+
+func (r *mediaRates) initialize() {
+	r.isVideo = r.media.Type == description.MediaTypeVideo
+
+	r.received = &ratemeter.Meter{Window: mediaRatesWindow}
+	r.received.Initialize()
+
+	r.sent = &ratemeter.Meter{Window: mediaRatesWindow}
+	r.sent.Initialize()
+}
+
+func (r *mediaRates) addReceived(now time.Time, pkt *rtp.Packet) {
+	r.received.Add(now, pkt.MarshalSize(), r.isVideo && pkt.Marker)
+}
+
+func (r *mediaRates) addSent(now time.Time, payload []byte) {
+	// the marker bit is the most significant bit of the second byte of the RTP header
+	r.sent.Add(now, len(payload), r.isVideo && len(payload) >= 2 && (payload[1]&0x80) != 0)
+}
@@ -0,0 +1,61 @@
+package gortsplib
+
+import (
+	"net"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+// MediaTransportInfo contains transport parameters of a media, negotiated during SETUP.
+type MediaTransportInfo struct {
+	// transport protocol.
+	Transport Transport
+
+	// local RTP port (UDP and UDP-multicast).
+	LocalRTPPort int
+
+	// local RTCP port (UDP and UDP-multicast).
+	LocalRTCPPort int
+
+	// remote RTP port (UDP and UDP-multicast).
+	RemoteRTPPort int
+
+	// remote RTCP port (UDP and UDP-multicast).
+	RemoteRTCPPort int
+
+	// interleaved IDs of RTP and RTCP packets (TCP).
+	InterleavedIDs *[2]int
+
+	// multicast group (UDP-multicast).
+	MulticastGroup net.IP
+
+	// size of the kernel receive buffer of the RTP socket, as obtained
+	// from the operating system (UDP and UDP-multicast).
+	ReadBufferSize int
+
+	// size of the kernel transmit buffer of the RTP socket, as obtained
+	// from the operating system (UDP and UDP-multicast).
+	WriteBufferSize int
+
+	// SSRCs of outgoing packets, by format.
+	// Formats whose SSRC is not known yet are not present.
+	LocalSSRCs map[format.Format]uint32
+
+	// SSRCs of incoming packets, by format.
+	// Formats whose SSRC is not known yet are not present.
+	RemoteSSRCs map[format.Format]uint32
+}
+
+func (i *MediaTransportInfo) fillSSRCs(stats StatsSessionMedia) {
+	i.LocalSSRCs = make(map[format.Format]uint32)
+	i.RemoteSSRCs = make(map[format.Format]uint32)
+
+	for forma, fs := range stats.Formats {
+		if fs.LocalSSRC != 0 {
+			i.LocalSSRCs[forma] = fs.LocalSSRC
+		}
+		if fs.RemoteSSRC != 0 {
+			i.RemoteSSRCs[forma] = fs.RemoteSSRC
+		}
+	}
+}
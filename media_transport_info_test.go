@@ -0,0 +1,120 @@
+package gortsplib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+)
+
+func TestMediaTransports(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"tcp",
+	} {
+		t.Run(transport, func(t *testing.T) {
+			var stream *ServerStream
+			var serverInfos map[*description.Media]MediaTransportInfo
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						serverInfos = ctx.Session.MediaTransports()
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			if transport == "udp" {
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+			defer stream.Close()
+
+			err = stream.SetRTPMode(ServerStreamRTPModeNormalize)
+			require.NoError(t, err)
+
+			c := Client{
+				Transport: func() *Transport {
+					if transport == "udp" {
+						v := TransportUDP
+						return &v
+					}
+					v := TransportTCP
+					return &v
+				}(),
+			}
+
+			u, err := base.ParseURL("rtsp://localhost:8554/teststream")
+			require.NoError(t, err)
+
+			err = c.Start(u.Scheme, u.Host)
+			require.NoError(t, err)
+			defer c.Close()
+
+			desc, _, err := c.Describe(u)
+			require.NoError(t, err)
+
+			err = c.SetupAll(desc.BaseURL, desc.Medias)
+			require.NoError(t, err)
+
+			_, err = c.Play(nil)
+			require.NoError(t, err)
+
+			serverInfo := serverInfos[stream.Description().Medias[0]]
+			clientInfo := c.MediaTransports()[desc.Medias[0]]
+
+			if transport == "udp" {
+				require.Equal(t, TransportUDP, serverInfo.Transport)
+				require.Equal(t, 8000, serverInfo.LocalRTPPort)
+				require.Equal(t, 8001, serverInfo.LocalRTCPPort)
+				require.Nil(t, serverInfo.InterleavedIDs)
+				require.NotZero(t, serverInfo.ReadBufferSize)
+				require.NotZero(t, serverInfo.WriteBufferSize)
+
+				require.Equal(t, TransportUDP, clientInfo.Transport)
+				require.Equal(t, 8000, clientInfo.RemoteRTPPort)
+				require.Equal(t, 8001, clientInfo.RemoteRTCPPort)
+				require.Equal(t, clientInfo.LocalRTPPort, serverInfo.RemoteRTPPort)
+				require.Equal(t, clientInfo.LocalRTCPPort, serverInfo.RemoteRTCPPort)
+				require.Nil(t, clientInfo.InterleavedIDs)
+				require.NotZero(t, clientInfo.ReadBufferSize)
+				require.NotZero(t, clientInfo.WriteBufferSize)
+			} else {
+				require.Equal(t, TransportTCP, serverInfo.Transport)
+				require.Equal(t, &[2]int{0, 1}, serverInfo.InterleavedIDs)
+				require.Equal(t, 0, serverInfo.LocalRTPPort)
+				require.Zero(t, serverInfo.ReadBufferSize)
+
+				require.Equal(t, TransportTCP, clientInfo.Transport)
+				require.Equal(t, &[2]int{0, 1}, clientInfo.InterleavedIDs)
+				require.Equal(t, 0, clientInfo.LocalRTPPort)
+			}
+
+			ssrc, ok := stream.localSSRC(stream.Description().Medias[0])
+			require.True(t, ok)
+			require.Equal(t, ssrc, serverInfo.LocalSSRCs[stream.Description().Medias[0].Formats[0]])
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package gortsplib
+
+// MetricsErrorType is the type of an error reported to Metrics.
+type MetricsErrorType int
+
+// error types.
+const (
+	MetricsErrorTypeRTPDecode MetricsErrorType = iota
+	MetricsErrorTypeRTCPDecode
+	MetricsErrorTypeRTPPacketsLost
+)
+
+var metricsErrorTypeLabels = map[MetricsErrorType]string{
+	MetricsErrorTypeRTPDecode:      "rtp_decode",
+	MetricsErrorTypeRTCPDecode:     "rtcp_decode",
+	MetricsErrorTypeRTPPacketsLost: "rtp_packets_lost",
+}
+
+// String implements fmt.Stringer.
+// The returned value can be used as label of a Prometheus metric.
+func (t MetricsErrorType) String() string {
+	if l, ok := metricsErrorTypeLabels[t]; ok {
+		return l
+	}
+	return "unknown"
+}
+
+// Metrics receives updates of counters and gauges of a Client or Server.
+// Methods are called by multiple goroutines, including the ones that
+// read and write packets, therefore they must be concurrency-safe and fast.
+// Each method can be bound to a Prometheus counter or gauge.
+type Metrics interface {
+	// called when a connection is opened (delta = 1) or closed (delta = -1).
+	AddConns(delta int)
+	// called when a session is created (delta = 1) or closed (delta = -1).
+	AddSessions(delta int)
+	// called when RTP or RTCP bytes are received.
+	AddBytesReceived(n uint64)
+	// called when RTP or RTCP bytes are sent.
+	AddBytesSent(n uint64)
+	// called when outgoing RTP or RTCP packets are dropped.
+	AddPacketsDropped(n uint64)
+	// called when errors occur.
+	AddErrors(t MetricsErrorType, n uint64)
+}
+
+// nilMetrics is the default Metrics.
+type nilMetrics struct{}
+
+func (nilMetrics) AddConns(_ int) {
+}
+
+func (nilMetrics) AddSessions(_ int) {
+}
+
+func (nilMetrics) AddBytesReceived(_ uint64) {
+}
+
+func (nilMetrics) AddBytesSent(_ uint64) {
+}
+
+func (nilMetrics) AddPacketsDropped(_ uint64) {
+}
+
+func (nilMetrics) AddErrors(_ MetricsErrorType, _ uint64) {
+}
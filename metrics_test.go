@@ -0,0 +1,131 @@
+package gortsplib
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+type testMetrics struct {
+	conns         int64
+	sessions      int64
+	bytesReceived uint64
+	bytesSent     uint64
+	dropped       uint64
+	errors        [3]uint64
+}
+
+func (m *testMetrics) AddConns(delta int) {
+	atomic.AddInt64(&m.conns, int64(delta))
+}
+
+func (m *testMetrics) AddSessions(delta int) {
+	atomic.AddInt64(&m.sessions, int64(delta))
+}
+
+func (m *testMetrics) AddBytesReceived(n uint64) {
+	atomic.AddUint64(&m.bytesReceived, n)
+}
+
+func (m *testMetrics) AddBytesSent(n uint64) {
+	atomic.AddUint64(&m.bytesSent, n)
+}
+
+func (m *testMetrics) AddPacketsDropped(n uint64) {
+	atomic.AddUint64(&m.dropped, n)
+}
+
+func (m *testMetrics) AddErrors(t MetricsErrorType, n uint64) {
+	atomic.AddUint64(&m.errors[t], n)
+}
+
+func TestMetricsErrorTypeString(t *testing.T) {
+	require.Equal(t, "rtp_decode", MetricsErrorTypeRTPDecode.String())
+	require.Equal(t, "rtcp_decode", MetricsErrorTypeRTCPDecode.String())
+	require.Equal(t, "rtp_packets_lost", MetricsErrorTypeRTPPacketsLost.String())
+	require.Equal(t, "unknown", MetricsErrorType(100).String())
+}
+
+func TestMetricsRecord(t *testing.T) {
+	sm := &testMetrics{}
+	received := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				var once sync.Once
+				ctx.Session.OnPacketRTPAny(func(_ *description.Media, _ format.Format, _ *rtp.Packet) {
+					once.Do(func() { close(received) })
+				})
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress:          "localhost:8554",
+		ObservabilityOptions: ObservabilityOptions{Metrics: sm},
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	cm := &testMetrics{}
+
+	c := Client{
+		Transport:            transportPtr(TransportTCP),
+		ObservabilityOptions: ObservabilityOptions{Metrics: cm},
+	}
+
+	media := testH264Media
+	err = c.StartRecording("rtsp://localhost:8554/teststream",
+		&description.Session{Medias: []*description.Media{media}})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(1), atomic.LoadInt64(&cm.conns))
+	require.Equal(t, int64(1), atomic.LoadInt64(&cm.sessions))
+
+	err = c.WritePacketRTP(media, &testRTPPacket)
+	require.NoError(t, err)
+
+	<-received
+
+	require.Equal(t, int64(1), atomic.LoadInt64(&sm.conns))
+	require.Equal(t, int64(1), atomic.LoadInt64(&sm.sessions))
+	require.NotZero(t, atomic.LoadUint64(&sm.bytesReceived))
+	require.NotZero(t, atomic.LoadUint64(&cm.bytesSent))
+
+	c.Close()
+
+	require.Equal(t, int64(0), atomic.LoadInt64(&cm.conns))
+	require.Equal(t, int64(0), atomic.LoadInt64(&cm.sessions))
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&sm.conns) == 0 && atomic.LoadInt64(&sm.sessions) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+
+	s.Close()
+
+	// gauges must not be decreased twice when the server is closed.
+	require.Equal(t, int64(0), atomic.LoadInt64(&sm.conns))
+	require.Equal(t, int64(0), atomic.LoadInt64(&sm.sessions))
+}
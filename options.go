@@ -0,0 +1,124 @@
+package gortsplib
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+)
+
+// TimeoutOptions groups timeout settings of a Server or Client.
+type TimeoutOptions struct {
+	// timeout of read operations.
+	// It defaults to 10 seconds.
+	Read time.Duration
+	// timeout of write operations.
+	// It defaults to 10 seconds.
+	Write time.Duration
+}
+
+// SecurityOptions groups security settings of a Server or Client.
+type SecurityOptions struct {
+	// a TLS configuration.
+	// On a Server, it allows to accept TLS (RTSPS) connections.
+	// On a Client, it is used to connect to RTSPS servers.
+	TLSConfig *tls.Config
+	// when TLS is enabled, medias are sent with the TCP transport
+	// inside the encrypted connection. This function allows to send
+	// specific medias (for instance public metadata) unencrypted
+	// with the UDP transport, when the peer asks for it.
+	// Since all medias of a session share the same transport,
+	// unencrypted medias must be setupped in a dedicated session.
+	// It defaults to nil (all medias are encrypted).
+	UnencryptedMedia func(medi *description.Media) bool
+}
+
+// SocketOptions groups socket settings of a Server or Client.
+type SocketOptions struct {
+	// DSCP (Differentiated Services Code Point) of RTP and RTCP packets
+	// sent with the UDP and UDP-multicast transports, between 0 and 63.
+	// It defaults to 0 (not set).
+	DSCP int
+	// disable TCP_NODELAY on RTSP connections.
+	DisableTCPNoDelay bool
+	// period of TCP keepalive probes of RTSP connections.
+	// If negative, keepalive probes are disabled.
+	// It defaults to 0 (left unchanged).
+	TCPKeepAlivePeriod time.Duration
+	// size of the kernel receive buffer (SO_RCVBUF) of UDP sockets.
+	// The kernel may cap it, the size actually obtained is available in MediaTransportInfo.
+	// It defaults to 524288.
+	UDPReadBufferSize int
+	// size of the kernel transmit buffer (SO_SNDBUF) of UDP sockets.
+	// The kernel may cap it, the size actually obtained is available in MediaTransportInfo.
+	// It defaults to 0 (operating system default).
+	UDPWriteBufferSize int
+	// maximum size of payloads of incoming UDP packets.
+	// Read buffers are initially sized for a 1500 bytes MTU and are enlarged up to this value
+	// when truncated packets are received, in order to support jumbo frames.
+	// Truncated packets are discarded and counted in StatsSessionMedia.
+	// It defaults to 65535.
+	UDPMaxPayloadSize int
+}
+
+// MediaActivityOptions groups settings about the expected activity of medias
+// received by a Server or Client.
+type MediaActivityOptions struct {
+	// function that returns the expected activity of a media.
+	// It defaults to a function that returns MediaActivitySparse for application and text medias
+	// and MediaActivityContinuous for the others.
+	MediaActivity func(*description.Media) MediaActivity
+	// timeout of read operations when all received medias are sparse.
+	// It defaults to 60 seconds.
+	SparseReadTimeout time.Duration
+}
+
+// ObservabilityOptions groups logging, metrics and statistics settings of a Server or Client.
+type ObservabilityOptions struct {
+	// logger of non-fatal events, that are not handled by callbacks.
+	// It defaults to a logger that writes to the standard logger.
+	Logger Logger
+	// receiver of counters and gauges about connections, sessions, bytes, drops and errors.
+	// It defaults to a Metrics that discards all updates.
+	Metrics Metrics
+	// period of statistics callbacks (Client.OnStats and ServerHandlerOnSessionStats).
+	// It defaults to 1 second.
+	StatsPeriod time.Duration
+}
+
+// MemoryOptions groups memory management settings of a Server or Client.
+type MemoryOptions struct {
+	// take received RTP packets and their buffers from pools, and return them
+	// to pools after OnPacketRTP callbacks return, in order to reduce allocations.
+	// Buffers of outgoing packets are taken from pools too.
+	// When enabled, packets can't be used after callbacks return,
+	// unless they are retained with RetainPacketRTP().
+	PoolPackets bool
+}
+
+// StrictOptions groups settings of the strict mode of a Server or Client.
+// In strict mode, messages received from the peer are validated against RFC 2326
+// (presence of headers, monotonicity of CSeq, legality of methods and states)
+// and violations are reported.
+type StrictOptions struct {
+	// enable the strict mode.
+	Enabled bool
+	// close the connection when a violation is detected.
+	// It defaults to false (violations are only reported).
+	FailOnViolation bool
+}
+
+// SenderIdentity is the identity of a publisher,
+// that allows multi-source servers to identify contributors.
+type SenderIdentity struct {
+	// canonical name, written into RTCP SDES packets.
+	// It must be unique among publishers.
+	CNAME string
+	// user name, written into RTCP SDES packets.
+	// It defaults to "" (not sent).
+	Name string
+	// add a label attribute (a=label) to medias of the stream description,
+	// in the format CNAME-index, when medias don't have a label yet.
+	// It defaults to false.
+	LabelMedias bool
+}
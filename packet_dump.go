@@ -0,0 +1,102 @@
+package gortsplib
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/internal/pcapng"
+)
+
+// packetDump writes RTP and RTCP packets into a pcapng file.
+// Packets are written as UDP datagrams between the addresses of a media.
+// With the TCP transport, ports are set to the interleaved channels.
+type packetDump struct {
+	w *pcapng.Writer
+
+	mutex  sync.Mutex
+	err    error
+	closed bool
+}
+
+func newPacketDump(w io.Writer) (*packetDump, error) {
+	pw := &pcapng.Writer{W: w}
+	err := pw.Initialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return &packetDump{
+		w: pw,
+	}, nil
+}
+
+func (d *packetDump) write(now time.Time, inbound bool, addrs *packetDumpAddrs, isRTCP bool, payload []byte) {
+	local, remote := addrs.rtpLocal, addrs.rtpRemote
+	if isRTCP {
+		local, remote = addrs.rtcpLocal, addrs.rtcpRemote
+	}
+
+	pkt := &pcapng.Packet{
+		Time:    now,
+		Payload: payload,
+	}
+
+	if inbound {
+		pkt.Direction = pcapng.DirectionInbound
+		pkt.Src, pkt.Dst = remote, local
+	} else {
+		pkt.Direction = pcapng.DirectionOutbound
+		pkt.Src, pkt.Dst = local, remote
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	// stop writing after the first error
+	if d.closed || d.err != nil {
+		return
+	}
+
+	d.err = d.w.WritePacket(pkt)
+}
+
+// close returns the first write error.
+func (d *packetDump) close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.closed = true
+	return d.err
+}
+
+// packetDumpAddrs are the addresses of a media that are written into packet dumps.
+type packetDumpAddrs struct {
+	rtpLocal   *net.UDPAddr
+	rtpRemote  *net.UDPAddr
+	rtcpLocal  *net.UDPAddr
+	rtcpRemote *net.UDPAddr
+}
+
+func newPacketDumpAddrsTCP(local net.Addr, remote net.Addr, channel int) *packetDumpAddrs {
+	localIP := addrIP(local)
+	remoteIP := addrIP(remote)
+
+	return &packetDumpAddrs{
+		rtpLocal:   &net.UDPAddr{IP: localIP, Port: channel},
+		rtpRemote:  &net.UDPAddr{IP: remoteIP, Port: channel},
+		rtcpLocal:  &net.UDPAddr{IP: localIP, Port: channel + 1},
+		rtcpRemote: &net.UDPAddr{IP: remoteIP, Port: channel + 1},
+	}
+}
+
+func addrIP(addr net.Addr) net.IP {
+	switch taddr := addr.(type) {
+	case *net.TCPAddr:
+		return taddr.IP
+	case *net.UDPAddr:
+		return taddr.IP
+	}
+	return net.IPv4zero
+}
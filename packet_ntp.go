@@ -0,0 +1,21 @@
+package gortsplib
+
+import (
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/rtpextension"
+)
+
+// packetNTPFromExtension returns the absolute timestamp attached by NVRs
+// to RTP packets through the ONVIF replay extension.
+func packetNTPFromExtension(pkt *rtp.Packet) (time.Time, bool) {
+	var ext rtpextension.ONVIFReplay
+	ok, err := ext.Read(&pkt.Header)
+	if !ok || err != nil {
+		return time.Time{}, false
+	}
+
+	return ext.NTPTime, true
+}
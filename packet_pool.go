@@ -0,0 +1,148 @@
+package gortsplib
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pion/rtp"
+)
+
+// size of pooled buffers, that are big enough to contain a UDP packet.
+const packetBufferSize = udpMaxPayloadSize + 1
+
+var packetBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new([packetBufferSize]byte)
+	},
+}
+
+var rtpPacketPool = sync.Pool{
+	New: func() interface{} {
+		return &rtp.Packet{}
+	},
+}
+
+// getPacketBuffer returns a buffer with the given size.
+// Buffers bigger than packetBufferSize are not pooled.
+func getPacketBuffer(size int) []byte {
+	if size > packetBufferSize {
+		return make([]byte, size)
+	}
+	return packetBufferPool.Get().(*[packetBufferSize]byte)[:size]
+}
+
+func putPacketBuffer(buf []byte) {
+	if cap(buf) == packetBufferSize {
+		packetBufferPool.Put((*[packetBufferSize]byte)(buf[:packetBufferSize]))
+	}
+}
+
+// receivedPackets decodes incoming RTP packets.
+// When pooling is enabled, packets and their buffers are taken from pools
+// and are returned to pools once they have been processed,
+// unless they have been retained.
+// It must be used by a single routine.
+type receivedPackets struct {
+	pool bool
+
+	inUse map[*rtp.Packet][]byte
+}
+
+func (rp *receivedPackets) initialize() {
+	if rp.pool {
+		rp.inUse = make(map[*rtp.Packet][]byte)
+	}
+}
+
+// decode decodes a RTP packet.
+// When pooling is enabled and decoding succeeds, buf is owned by receivedPackets
+// until the packet is released, retained or discarded.
+func (rp *receivedPackets) decode(buf []byte) (*rtp.Packet, error) {
+	if !rp.pool {
+		pkt := &rtp.Packet{}
+		err := pkt.Unmarshal(buf)
+		return pkt, err
+	}
+
+	pkt := rtpPacketPool.Get().(*rtp.Packet)
+	*pkt = rtp.Packet{
+		Header: rtp.Header{
+			CSRC:       pkt.CSRC[:0],
+			Extensions: pkt.Extensions[:0],
+		},
+	}
+
+	err := pkt.Unmarshal(buf)
+	if err != nil {
+		rtpPacketPool.Put(pkt)
+		return nil, err
+	}
+
+	rp.inUse[pkt] = buf
+	return pkt, nil
+}
+
+// release returns a packet and its buffer to pools.
+func (rp *receivedPackets) release(pkt *rtp.Packet) {
+	if !rp.pool {
+		return
+	}
+
+	buf, ok := rp.inUse[pkt]
+	if !ok {
+		return
+	}
+	delete(rp.inUse, pkt)
+
+	putPacketBuffer(buf)
+	rtpPacketPool.Put(pkt)
+}
+
+// discard returns a packet to pools and gives the ownership of its buffer back to the caller.
+func (rp *receivedPackets) discard(pkt *rtp.Packet) {
+	if !rp.pool {
+		return
+	}
+
+	delete(rp.inUse, pkt)
+	rtpPacketPool.Put(pkt)
+}
+
+// retain prevents a packet from being returned to pools.
+func (rp *receivedPackets) retain(pkt *rtp.Packet) {
+	if !rp.pool {
+		return
+	}
+
+	delete(rp.inUse, pkt)
+}
+
+// sharedPacketBuffers are buffers of outgoing packets that are shared between
+// readers of a stream. They are returned to the pool when all readers
+// have released them.
+// A nil *sharedPacketBuffers is valid and does nothing.
+type sharedPacketBuffers struct {
+	byts [][]byte
+	refs int32
+}
+
+func newSharedPacketBuffers(byts [][]byte) *sharedPacketBuffers {
+	return &sharedPacketBuffers{
+		byts: byts,
+		refs: 1,
+	}
+}
+
+func (sb *sharedPacketBuffers) retain() {
+	if sb != nil {
+		atomic.AddInt32(&sb.refs, 1)
+	}
+}
+
+func (sb *sharedPacketBuffers) release() {
+	if sb != nil && atomic.AddInt32(&sb.refs, -1) == 0 {
+		for _, b := range sb.byts {
+			putPacketBuffer(b)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package gortsplib
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+func TestReceivedPackets(t *testing.T) {
+	rp := &receivedPackets{pool: true}
+	rp.initialize()
+
+	marshal := func(seq uint16) []byte {
+		buf := getPacketBuffer(udpMaxPayloadSize + 1)
+		n, err := (&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: seq,
+			},
+			Payload: []byte{1, 2, 3, 4},
+		}).MarshalTo(buf)
+		require.NoError(t, err)
+		return buf[:n]
+	}
+
+	pkt, err := rp.decode(marshal(1))
+	require.NoError(t, err)
+	require.Equal(t, uint16(1), pkt.SequenceNumber)
+	require.Len(t, rp.inUse, 1)
+
+	rp.release(pkt)
+	require.Len(t, rp.inUse, 0)
+
+	pkt, err = rp.decode(marshal(2))
+	require.NoError(t, err)
+
+	rp.retain(pkt)
+	require.Len(t, rp.inUse, 0)
+
+	// releasing a retained packet has no effect
+	rp.release(pkt)
+	require.Equal(t, uint16(2), pkt.SequenceNumber)
+	require.Equal(t, []byte{1, 2, 3, 4}, pkt.Payload)
+
+	buf := marshal(3)
+	pkt, err = rp.decode(buf)
+	require.NoError(t, err)
+
+	rp.discard(pkt)
+	require.Len(t, rp.inUse, 0)
+
+	_, err = rp.decode([]byte{1, 2})
+	require.Error(t, err)
+	require.Len(t, rp.inUse, 0)
+}
+
+func TestSharedPacketBuffers(t *testing.T) {
+	sb := newSharedPacketBuffers([][]byte{getPacketBuffer(10)})
+	sb.retain()
+	sb.release()
+	require.Equal(t, int32(1), sb.refs)
+	sb.release()
+	require.Equal(t, int32(0), sb.refs)
+
+	// nil buffers are allowed
+	var nb *sharedPacketBuffers
+	nb.retain()
+	nb.release()
+}
+
+func TestServerClientPoolPackets(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"tcp",
+	} {
+		t.Run(transport, func(t *testing.T) {
+			received := make(chan []*rtp.Packet)
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil, nil
+					},
+					onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+						var retained []*rtp.Packet
+
+						medi := ctx.Session.AnnouncedDescription().Medias[0]
+
+						ctx.Session.OnPacketRTP(medi, medi.Formats[0], func(pkt *rtp.Packet) {
+							ctx.Session.RetainPacketRTP(medi, pkt)
+							retained = append(retained, pkt)
+
+							if len(retained) == 10 {
+								received <- retained
+							}
+						})
+
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress:   "localhost:8554",
+				MemoryOptions: MemoryOptions{PoolPackets: true},
+			}
+
+			if transport == "udp" {
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			c := Client{
+				Transport: func() *Transport {
+					if transport == "udp" {
+						v := TransportUDP
+						return &v
+					}
+					v := TransportTCP
+					return &v
+				}(),
+				MemoryOptions: MemoryOptions{PoolPackets: true},
+			}
+
+			desc := &description.Session{
+				Medias: []*description.Media{{
+					Type: description.MediaTypeVideo,
+					Formats: []format.Format{&format.H264{
+						PayloadTyp:        96,
+						PacketizationMode: 1,
+					}},
+				}},
+			}
+
+			err = c.StartRecording("rtsp://localhost:8554/teststream", desc)
+			require.NoError(t, err)
+			defer c.Close()
+
+			for i := 0; i < 10; i++ {
+				err = c.WritePacketRTP(desc.Medias[0], &rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						PayloadType:    96,
+						SequenceNumber: uint16(1000 + i),
+						Marker:         true,
+					},
+					Payload: []byte{5, byte(i), byte(i), byte(i)},
+				})
+				require.NoError(t, err)
+			}
+
+			retained := <-received
+
+			for i, pkt := range retained {
+				require.Equal(t, uint16(1000+i), pkt.SequenceNumber)
+				require.Equal(t, []byte{5, byte(i), byte(i), byte(i)}, pkt.Payload)
+			}
+		})
+	}
+}
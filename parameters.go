@@ -0,0 +1,90 @@
+package gortsplib
+
+import (
+	"strings"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+)
+
+// Parameter is a RTSP parameter, exchanged with GET_PARAMETER and SET_PARAMETER requests.
+type Parameter struct {
+	// parameter name.
+	Name string
+	// parameter value.
+	// It is empty when the parameter is requested with GET_PARAMETER.
+	Value string
+}
+
+// unmarshalParameters decodes a text/parameters body.
+// Lines can contain a name only (GET_PARAMETER requests)
+// or a name and a value separated by a colon.
+func unmarshalParameters(byts []byte) []Parameter {
+	var ret []Parameter
+
+	for _, line := range strings.Split(string(byts), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		p := Parameter{Name: strings.TrimSpace(parts[0])}
+		if len(parts) == 2 {
+			p.Value = strings.TrimSpace(parts[1])
+		}
+
+		ret = append(ret, p)
+	}
+
+	return ret
+}
+
+// isParametersBody checks whether the body of a GET_PARAMETER or SET_PARAMETER request
+// can be decoded as text/parameters. Bodies with other content types (i.e. text/xml)
+// are passed to the raw callbacks.
+func isParametersBody(req *base.Request) bool {
+	ct, ok := req.Header.ContentType()
+	return !ok || ct == "text/parameters"
+}
+
+func marshalParameters(params []Parameter) []byte {
+	var buf strings.Builder
+
+	for _, p := range params {
+		buf.WriteString(p.Name + ": " + p.Value + "\r\n")
+	}
+
+	return []byte(buf.String())
+}
+
+func parametersResponse(res *base.Response, params []Parameter) *base.Response {
+	if res.StatusCode != base.StatusOK || params == nil {
+		return res
+	}
+
+	if res.Header == nil {
+		res.Header = make(base.Header)
+	}
+	res.Header["Content-Type"] = base.HeaderValue{"text/parameters"}
+	res.Body = marshalParameters(params)
+
+	return res
+}
+
+func keepaliveResponse() *base.Response {
+	return &base.Response{
+		StatusCode: base.StatusOK,
+		Header: base.Header{
+			"Content-Type": base.HeaderValue{"text/parameters"},
+		},
+		Body: []byte{},
+	}
+}
+
+func serverHandlerSupportsSetParameter(h ServerHandler) bool {
+	if _, ok := h.(ServerHandlerOnSetParameters); ok {
+		return true
+	}
+	_, ok := h.(ServerHandlerOnSetParameter)
+	return ok
+}
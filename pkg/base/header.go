@@ -34,6 +34,17 @@ type HeaderValue []string
 // Header is a RTSP reader, present in both Requests and Responses.
 type Header map[string]HeaderValue
 
+// ContentType returns the media type contained in the Content-Type header,
+// without parameters (i.e. charset) and in lower case.
+func (h Header) ContentType() (string, bool) {
+	v, ok := h["Content-Type"]
+	if !ok || len(v) != 1 {
+		return "", false
+	}
+
+	return strings.ToLower(strings.TrimSpace(strings.Split(v[0], ";")[0])), true
+}
+
 func (h *Header) unmarshal(br *bufio.Reader) error {
 	*h = make(Header)
 	count := 0
@@ -144,3 +144,43 @@ func FuzzHeaderUnmarshal(f *testing.F) {
 		}
 	})
 }
+
+func TestHeaderContentType(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		h    Header
+		ct   string
+		ok   bool
+	}{
+		{
+			"missing",
+			Header{},
+			"",
+			false,
+		},
+		{
+			"plain",
+			Header{"Content-Type": HeaderValue{"application/sdp"}},
+			"application/sdp",
+			true,
+		},
+		{
+			"with parameters",
+			Header{"Content-Type": HeaderValue{"Text/XML; charset=utf-8"}},
+			"text/xml",
+			true,
+		},
+		{
+			"multiple values",
+			Header{"Content-Type": HeaderValue{"application/sdp", "text/xml"}},
+			"",
+			false,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			ct, ok := ca.h.ContentType()
+			require.Equal(t, ca.ok, ok)
+			require.Equal(t, ca.ct, ct)
+		})
+	}
+}
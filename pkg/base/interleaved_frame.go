@@ -23,6 +23,13 @@ type InterleavedFrame struct {
 
 // Unmarshal decodes an interleaved frame.
 func (f *InterleavedFrame) Unmarshal(br *bufio.Reader) error {
+	return f.UnmarshalWithAllocator(br, nil)
+}
+
+// UnmarshalWithAllocator decodes an interleaved frame.
+// The payload is stored into a buffer returned by alloc, that must have the requested size.
+// When alloc is nil, the buffer is allocated with make().
+func (f *InterleavedFrame) UnmarshalWithAllocator(br *bufio.Reader, alloc func(size int) []byte) error {
 	var header [4]byte
 	_, err := io.ReadFull(br, header[:])
 	if err != nil {
@@ -37,7 +44,11 @@ func (f *InterleavedFrame) Unmarshal(br *bufio.Reader) error {
 	payloadLen := int(uint16(header[2])<<8 | uint16(header[3]))
 
 	f.Channel = int(header[1])
-	f.Payload = make([]byte, payloadLen)
+	if alloc != nil {
+		f.Payload = alloc(payloadLen)
+	} else {
+		f.Payload = make([]byte, payloadLen)
+	}
 
 	_, err = io.ReadFull(br, f.Payload)
 	return err
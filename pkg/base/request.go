@@ -26,6 +26,7 @@ const (
 	Pause        Method = "PAUSE"
 	Play         Method = "PLAY"
 	Record       Method = "RECORD"
+	Redirect     Method = "REDIRECT"
 	Setup        Method = "SETUP"
 	SetParameter Method = "SET_PARAMETER"
 	Teardown     Method = "TEARDOWN"
@@ -3,7 +3,6 @@ package base
 import (
 	"fmt"
 	"net/url"
-	"regexp"
 	"strings"
 )
 
@@ -12,19 +11,48 @@ import (
 // control attributes.
 type URL url.URL
 
-var escapeRegexp = regexp.MustCompile(`^(.+?)://(.*?)@(.*?)/(.*?)$`)
+// escapeIPv6Zone escapes the zone ID of a bracketed IPv6 literal,
+// that is often written without escaping the percent sign.
+// https://github.com/golang/go/issues/30611
+func escapeIPv6Zone(s string) string {
+	i := strings.Index(s, "://")
+	if i < 0 {
+		return s
+	}
+	i += 3
 
-// ParseURL parses a RTSP URL.
-func ParseURL(s string) (*URL, error) {
-	// https://github.com/golang/go/issues/30611
-	m := escapeRegexp.FindStringSubmatch(s)
-	if m != nil {
-		m[3] = strings.ReplaceAll(m[3], "%25", "%")
-		m[3] = strings.ReplaceAll(m[3], "%", "%25")
-		s = m[1] + "://" + m[2] + "@" + m[3] + "/" + m[4]
+	authorityEnd := strings.IndexAny(s[i:], "/?#")
+	if authorityEnd < 0 {
+		authorityEnd = len(s)
+	} else {
+		authorityEnd += i
 	}
 
-	u, err := url.Parse(s)
+	authority := s[i:authorityEnd]
+
+	start := strings.LastIndexByte(authority, '[')
+	if start < 0 {
+		return s
+	}
+
+	end := strings.IndexByte(authority[start:], ']')
+	if end < 0 {
+		return s
+	}
+	end += start
+
+	host := authority[start+1 : end]
+	host = strings.ReplaceAll(host, "%25", "%")
+	host = strings.ReplaceAll(host, "%", "%25")
+
+	return s[:i] + authority[:start+1] + host + authority[end:] + s[authorityEnd:]
+}
+
+// ParseURL parses a RTSP URL.
+// Hosts can be IPv6 literals enclosed in square brackets,
+// with an optional zone ID, whose percent sign can be escaped or not.
+func ParseURL(s string) (*URL, error) {
+	u, err := url.Parse(escapeIPv6Zone(s))
 	if err != nil {
 		return nil, err
 	}
@@ -105,3 +133,86 @@ func (u *URL) Hostname() string {
 func (u *URL) Port() string {
 	return (*url.URL)(u).Port()
 }
+
+// SetCredentials sets the user name and password of the URL.
+func (u *URL) SetCredentials(user string, pass string) {
+	u.User = url.UserPassword(user, pass)
+}
+
+// RemoveCredentials removes the user name and password from the URL.
+func (u *URL) RemoveCredentials() {
+	u.User = nil
+}
+
+// queryKey returns the unescaped key of a query parameter.
+func queryKey(param string) string {
+	key, _, _ := strings.Cut(param, "=")
+	if unescaped, err := url.QueryUnescape(key); err == nil {
+		return unescaped
+	}
+	return key
+}
+
+func encodeQueryParam(key string, value string) string {
+	return url.QueryEscape(key) + "=" + url.QueryEscape(value)
+}
+
+func (u *URL) queryParams() []string {
+	if u.RawQuery == "" {
+		return nil
+	}
+	return strings.Split(u.RawQuery, "&")
+}
+
+func (u *URL) setQueryParams(params []string) {
+	u.RawQuery = strings.Join(params, "&")
+	if u.RawQuery == "" {
+		u.ForceQuery = false
+	}
+}
+
+// AddQueryParam appends a query parameter to the URL.
+// Existing parameters are left untouched, including their order and encoding.
+func (u *URL) AddQueryParam(key string, value string) {
+	u.setQueryParams(append(u.queryParams(), encodeQueryParam(key, value)))
+}
+
+// SetQueryParam sets the value of a query parameter of the URL.
+// The first parameter with the same key is replaced and the others are removed.
+// If the parameter is not present, it is appended.
+func (u *URL) SetQueryParam(key string, value string) {
+	params := u.queryParams()
+	out := params[:0]
+	found := false
+
+	for _, param := range params {
+		if queryKey(param) == key {
+			if !found {
+				out = append(out, encodeQueryParam(key, value))
+				found = true
+			}
+			continue
+		}
+		out = append(out, param)
+	}
+
+	if !found {
+		out = append(out, encodeQueryParam(key, value))
+	}
+
+	u.setQueryParams(out)
+}
+
+// RemoveQueryParam removes all query parameters with the given key from the URL.
+func (u *URL) RemoveQueryParam(key string) {
+	params := u.queryParams()
+	out := params[:0]
+
+	for _, param := range params {
+		if queryKey(param) != key {
+			out = append(out, param)
+		}
+	}
+
+	u.setQueryParams(out)
+}
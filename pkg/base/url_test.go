@@ -31,6 +31,52 @@ func TestParseURL(t *testing.T) {
 				User:   url.UserPassword("user", "pa#ss"),
 			},
 		},
+		{
+			"ipv6 with port",
+			`rtsp://[2001:db8::1]:8554/teststream`,
+			&URL{
+				Scheme: "rtsp",
+				Host:   "[2001:db8::1]:8554",
+				Path:   "/teststream",
+			},
+		},
+		{
+			"ipv6 without port",
+			`rtsp://[::1]/teststream`,
+			&URL{
+				Scheme: "rtsp",
+				Host:   "[::1]",
+				Path:   "/teststream",
+			},
+		},
+		{
+			"ipv6 zone without credentials",
+			`rtsp://[fe80::1%eth0]:8554/teststream?a=b`,
+			&URL{
+				Scheme:   "rtsp",
+				Host:     "[fe80::1%eth0]:8554",
+				Path:     "/teststream",
+				RawQuery: "a=b",
+			},
+		},
+		{
+			"ipv6 zone without path",
+			`rtsp://[fe80::1%eth0]:8554`,
+			&URL{
+				Scheme: "rtsp",
+				Host:   "[fe80::1%eth0]:8554",
+			},
+		},
+		{
+			"ipv6 escaped zone",
+			`rtsp://user:pass@[fe80::1%25eth0]:8554/teststream`,
+			&URL{
+				Scheme: "rtsp",
+				Host:   "[fe80::1%eth0]:8554",
+				Path:   "/teststream",
+				User:   url.UserPassword("user", "pass"),
+			},
+		},
 	} {
 		t.Run(ca.name, func(t *testing.T) {
 			u, err := ParseURL(ca.enc)
@@ -163,3 +209,36 @@ func TestURLRTSPPathAndQuery(t *testing.T) {
 		require.Equal(t, ca.b, b)
 	}
 }
+
+func TestURLCredentials(t *testing.T) {
+	u := mustParseURL("rtsp://[fe80::1%eth0]:8554/teststream")
+
+	u.SetCredentials("user", "pa@ss")
+	require.Equal(t, "rtsp://user:pa%40ss@[fe80::1%25eth0]:8554/teststream", u.String())
+	require.Equal(t, u, mustParseURL(u.String()))
+
+	u.RemoveCredentials()
+	require.Equal(t, "rtsp://[fe80::1%25eth0]:8554/teststream", u.String())
+}
+
+func TestURLQueryParams(t *testing.T) {
+	u := mustParseURL("rtsp://localhost:8554/teststream?channel=1&subtype=0&x%20y=z&channel=2")
+
+	u.SetQueryParam("channel", "3")
+	require.Equal(t, "rtsp://localhost:8554/teststream?channel=3&subtype=0&x%20y=z", u.String())
+
+	u.RemoveQueryParam("x y")
+	require.Equal(t, "rtsp://localhost:8554/teststream?channel=3&subtype=0", u.String())
+
+	u.AddQueryParam("token", "a&b")
+	require.Equal(t, "rtsp://localhost:8554/teststream?channel=3&subtype=0&token=a%26b", u.String())
+
+	u.SetQueryParam("unicast", "true")
+	require.Equal(t, "rtsp://localhost:8554/teststream?channel=3&subtype=0&token=a%26b&unicast=true", u.String())
+
+	u.RemoveQueryParam("channel")
+	u.RemoveQueryParam("subtype")
+	u.RemoveQueryParam("token")
+	u.RemoveQueryParam("unicast")
+	require.Equal(t, "rtsp://localhost:8554/teststream", u.String())
+}
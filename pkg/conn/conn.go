@@ -19,6 +19,8 @@ type Conn struct {
 
 	// reuse interleaved frames. they should never be passed to secondary routines
 	fr base.InterleavedFrame
+
+	allocPayload func(int) []byte
 }
 
 // NewConn allocates a Conn.
@@ -29,6 +31,12 @@ func NewConn(rw io.ReadWriter) *Conn {
 	}
 }
 
+// SetPayloadAllocator sets a function that allocates payloads of interleaved frames.
+// It allows to take buffers from a pool instead of allocating them.
+func (c *Conn) SetPayloadAllocator(alloc func(size int) []byte) {
+	c.allocPayload = alloc
+}
+
 // Read reads a Request, a Response or an Interleaved frame.
 func (c *Conn) Read() (interface{}, error) {
 	for {
@@ -79,7 +87,7 @@ func (c *Conn) ReadResponse() (*base.Response, error) {
 
 // ReadInterleavedFrame reads a InterleavedFrame.
 func (c *Conn) ReadInterleavedFrame() (*base.InterleavedFrame, error) {
-	err := c.fr.Unmarshal(c.br)
+	err := c.fr.UnmarshalWithAllocator(c.br, c.allocPayload)
 	return &c.fr, err
 }
 
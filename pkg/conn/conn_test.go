@@ -102,6 +102,21 @@ func TestReadConsecutiveFrameMagicBytes(t *testing.T) {
 		}, dec2)
 }
 
+func TestReadPayloadAllocator(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{0x24, 0x6, 0x0, 0x4, 0x1, 0x2, 0x3, 0x4})
+	conn := NewConn(buf)
+
+	pool := make([]byte, 16)
+	conn.SetPayloadAllocator(func(size int) []byte {
+		return pool[:size]
+	})
+
+	fr, err := conn.ReadInterleavedFrame()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, fr.Payload)
+	require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, pool[:4])
+}
+
 func TestReadError(t *testing.T) {
 	var buf bytes.Buffer
 	conn := NewConn(&buf)
@@ -0,0 +1,253 @@
+// Package corruptiondetector contains a utility to detect corrupted H264 and H265 access units.
+package corruptiondetector
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+// GOPStats are statistics about a group of pictures,
+// that is a sequence of access units that starts with a random access point.
+type GOPStats struct {
+	// number of access units.
+	AccessUnits int
+
+	// number of corrupted access units.
+	CorruptedAccessUnits int
+}
+
+// CorruptionRatio returns the ratio between corrupted access units and access units.
+func (s GOPStats) CorruptionRatio() float64 {
+	if s.AccessUnits == 0 {
+		return 0
+	}
+	return float64(s.CorruptedAccessUnits) / float64(s.AccessUnits)
+}
+
+type packetInfo struct {
+	// packet is a fragment of a NALU
+	isFragment    bool
+	fragmentStart bool
+	fragmentEnd   bool
+
+	// packet contains a random access point
+	isRandomAccess bool
+}
+
+func parseH264(payload []byte) (packetInfo, error) {
+	if len(payload) < 1 {
+		return packetInfo{}, fmt.Errorf("payload is too short")
+	}
+
+	var info packetInfo
+
+	switch typ := payload[0] & 0x1F; typ {
+	case 28: // FU-A
+		if len(payload) < 2 {
+			return packetInfo{}, fmt.Errorf("invalid FU-A packet")
+		}
+		info.isFragment = true
+		info.fragmentStart = (payload[1] >> 7) != 0
+		info.fragmentEnd = ((payload[1] >> 6) & 0x01) != 0
+		info.isRandomAccess = info.fragmentStart && (payload[1]&0x1F) == 5
+
+	case 24: // STAP-A
+		payload = payload[1:]
+		for len(payload) >= 3 {
+			size := int(payload[0])<<8 | int(payload[1])
+			if size == 0 || size > len(payload[2:]) {
+				break
+			}
+			if (payload[2] & 0x1F) == 5 {
+				info.isRandomAccess = true
+			}
+			payload = payload[2+size:]
+		}
+
+	default:
+		info.isRandomAccess = typ == 5
+	}
+
+	return info, nil
+}
+
+func isH265RandomAccess(typ byte) bool {
+	// BLA_W_LP, BLA_W_RADL, BLA_N_LP, IDR_W_RADL, IDR_N_LP, CRA_NUT
+	return typ >= 16 && typ <= 21
+}
+
+func parseH265(payload []byte) (packetInfo, error) {
+	if len(payload) < 2 {
+		return packetInfo{}, fmt.Errorf("payload is too short")
+	}
+
+	var info packetInfo
+
+	switch typ := (payload[0] >> 1) & 0x3F; typ {
+	case 49: // FU
+		if len(payload) < 3 {
+			return packetInfo{}, fmt.Errorf("invalid FU packet")
+		}
+		info.isFragment = true
+		info.fragmentStart = (payload[2] >> 7) != 0
+		info.fragmentEnd = ((payload[2] >> 6) & 0x01) != 0
+		info.isRandomAccess = info.fragmentStart && isH265RandomAccess(payload[2]&0x3F)
+
+	case 48: // AP
+		payload = payload[2:]
+		for len(payload) >= 4 {
+			size := int(payload[0])<<8 | int(payload[1])
+			if size == 0 || size > len(payload[2:]) {
+				break
+			}
+			if isH265RandomAccess((payload[2] >> 1) & 0x3F) {
+				info.isRandomAccess = true
+			}
+			payload = payload[2+size:]
+		}
+
+	default:
+		info.isRandomAccess = isH265RandomAccess(typ)
+	}
+
+	return info, nil
+}
+
+// Detector detects corrupted access units of H264 and H265 streams,
+// that is access units with missing packets or incomplete fragmented NALUs,
+// and computes corruption statistics of each group of pictures.
+type Detector struct {
+	// format of the stream. It must be *format.H264 or *format.H265.
+	Format format.Format
+
+	// called when an access unit is complete.
+	// It is optional.
+	OnAccessUnit func(timestamp uint32, corrupted bool)
+
+	// called when a group of pictures is complete, that is when the next one begins.
+	// Access units received before the first random access point are not part of any group.
+	// It is optional.
+	OnGOP func(GOPStats)
+
+	parse func([]byte) (packetInfo, error)
+
+	firstPacketReceived bool
+	expectedSeqNum      uint16
+	auStarted           bool
+	auTimestamp         uint32
+	auCorrupted         bool
+	auRandomAccess      bool
+	inFragment          bool
+	gopStarted          bool
+	gop                 GOPStats
+}
+
+// Initialize initializes the Detector.
+func (d *Detector) Initialize() error {
+	switch d.Format.(type) {
+	case *format.H264:
+		d.parse = parseH264
+
+	case *format.H265:
+		d.parse = parseH265
+
+	default:
+		return fmt.Errorf("unsupported format: %T", d.Format)
+	}
+
+	return nil
+}
+
+// Process processes a RTP packet.
+// Packets must be passed in order, after being reordered.
+func (d *Detector) Process(pkt *rtp.Packet) {
+	lost := d.firstPacketReceived && pkt.SequenceNumber != d.expectedSeqNum
+	d.firstPacketReceived = true
+	d.expectedSeqNum = pkt.SequenceNumber + 1
+
+	if d.auStarted && pkt.Timestamp != d.auTimestamp {
+		// the last packet of the previous access unit was lost
+		d.completeAccessUnit()
+	}
+
+	if !d.auStarted {
+		d.auStarted = true
+		d.auTimestamp = pkt.Timestamp
+		d.auCorrupted = false
+		d.auRandomAccess = false
+	}
+
+	if lost {
+		d.auCorrupted = true
+		d.inFragment = false
+	}
+
+	info, err := d.parse(pkt.Payload)
+	if err != nil {
+		d.auCorrupted = true
+		d.inFragment = false
+	} else {
+		if info.isRandomAccess {
+			d.auRandomAccess = true
+		}
+
+		switch {
+		case info.isFragment && info.fragmentStart:
+			// a fragmented NALU started before the end of the previous one
+			if d.inFragment {
+				d.auCorrupted = true
+			}
+			d.inFragment = !info.fragmentEnd
+
+		case info.isFragment:
+			// fragment of a NALU whose start is missing
+			if !d.inFragment {
+				d.auCorrupted = true
+			}
+			d.inFragment = !info.fragmentEnd
+
+		default:
+			// a NALU started before the end of a fragmented NALU
+			if d.inFragment {
+				d.auCorrupted = true
+				d.inFragment = false
+			}
+		}
+	}
+
+	if pkt.Marker {
+		d.completeAccessUnit()
+	}
+}
+
+func (d *Detector) completeAccessUnit() {
+	// access unit ended before the end of a fragmented NALU
+	if d.inFragment {
+		d.auCorrupted = true
+		d.inFragment = false
+	}
+
+	d.auStarted = false
+
+	if d.auRandomAccess {
+		if d.gopStarted && d.OnGOP != nil {
+			d.OnGOP(d.gop)
+		}
+		d.gopStarted = true
+		d.gop = GOPStats{}
+	}
+
+	if d.gopStarted {
+		d.gop.AccessUnits++
+		if d.auCorrupted {
+			d.gop.CorruptedAccessUnits++
+		}
+	}
+
+	if d.OnAccessUnit != nil {
+		d.OnAccessUnit(d.auTimestamp, d.auCorrupted)
+	}
+}
@@ -0,0 +1,118 @@
+package corruptiondetector
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+type testAccessUnit struct {
+	timestamp uint32
+	corrupted bool
+}
+
+func TestDetectorH264(t *testing.T) {
+	var aus []testAccessUnit
+	var gops []GOPStats
+
+	d := &Detector{
+		Format: &format.H264{},
+		OnAccessUnit: func(timestamp uint32, corrupted bool) {
+			aus = append(aus, testAccessUnit{timestamp, corrupted})
+		},
+		OnGOP: func(s GOPStats) {
+			gops = append(gops, s)
+		},
+	}
+	err := d.Initialize()
+	require.NoError(t, err)
+
+	for _, pkt := range []*rtp.Packet{
+		// non-IDR before the first IDR
+		{Header: rtp.Header{SequenceNumber: 99, Timestamp: 0, Marker: true}, Payload: []byte{0x41, 0x01}},
+		// IDR
+		{Header: rtp.Header{SequenceNumber: 100, Timestamp: 3000, Marker: true}, Payload: []byte{0x65, 0x01}},
+		// complete fragmented NALU
+		{Header: rtp.Header{SequenceNumber: 101, Timestamp: 6000}, Payload: []byte{0x7c, 0x81, 0x01}},
+		{Header: rtp.Header{SequenceNumber: 102, Timestamp: 6000}, Payload: []byte{0x7c, 0x01, 0x02}},
+		{Header: rtp.Header{SequenceNumber: 103, Timestamp: 6000, Marker: true}, Payload: []byte{0x7c, 0x41, 0x03}},
+		// fragmented NALU without end
+		{Header: rtp.Header{SequenceNumber: 104, Timestamp: 9000}, Payload: []byte{0x7c, 0x81, 0x01}},
+		{Header: rtp.Header{SequenceNumber: 105, Timestamp: 9000}, Payload: []byte{0x7c, 0x81, 0x01}},
+		{Header: rtp.Header{SequenceNumber: 106, Timestamp: 9000, Marker: true}, Payload: []byte{0x7c, 0x41, 0x03}},
+		// missing packet
+		{Header: rtp.Header{SequenceNumber: 108, Timestamp: 12000, Marker: true}, Payload: []byte{0x41, 0x01}},
+		// IDR inside a STAP-A
+		{Header: rtp.Header{SequenceNumber: 109, Timestamp: 15000, Marker: true}, Payload: []byte{
+			0x18, 0x00, 0x02, 0x67, 0x01, 0x00, 0x02, 0x65, 0x01,
+		}},
+	} {
+		d.Process(pkt)
+	}
+
+	require.Equal(t, []testAccessUnit{
+		{0, false},
+		{3000, false},
+		{6000, false},
+		{9000, true},
+		{12000, true},
+		{15000, false},
+	}, aus)
+
+	require.Equal(t, []GOPStats{{
+		AccessUnits:          4,
+		CorruptedAccessUnits: 2,
+	}}, gops)
+	require.Equal(t, 0.5, gops[0].CorruptionRatio())
+}
+
+func TestDetectorH265(t *testing.T) {
+	var aus []testAccessUnit
+	var gops []GOPStats
+
+	d := &Detector{
+		Format: &format.H265{},
+		OnAccessUnit: func(timestamp uint32, corrupted bool) {
+			aus = append(aus, testAccessUnit{timestamp, corrupted})
+		},
+		OnGOP: func(s GOPStats) {
+			gops = append(gops, s)
+		},
+	}
+	err := d.Initialize()
+	require.NoError(t, err)
+
+	for _, pkt := range []*rtp.Packet{
+		// IDR_W_RADL split into fragments
+		{Header: rtp.Header{SequenceNumber: 100, Timestamp: 0}, Payload: []byte{0x62, 0x01, 0x93, 0x01}},
+		{Header: rtp.Header{SequenceNumber: 101, Timestamp: 0, Marker: true}, Payload: []byte{0x62, 0x01, 0x53, 0x02}},
+		// fragment without start
+		{Header: rtp.Header{SequenceNumber: 102, Timestamp: 3000, Marker: true}, Payload: []byte{0x62, 0x01, 0x41, 0x02}},
+		// CRA
+		{Header: rtp.Header{SequenceNumber: 103, Timestamp: 6000, Marker: true}, Payload: []byte{0x2a, 0x01, 0x01}},
+	} {
+		d.Process(pkt)
+	}
+
+	require.Equal(t, []testAccessUnit{
+		{0, false},
+		{3000, true},
+		{6000, false},
+	}, aus)
+
+	require.Equal(t, []GOPStats{{
+		AccessUnits:          2,
+		CorruptedAccessUnits: 1,
+	}}, gops)
+}
+
+func TestDetectorUnsupportedFormat(t *testing.T) {
+	d := &Detector{
+		Format: &format.Opus{},
+	}
+	err := d.Initialize()
+	require.EqualError(t, err, "unsupported format: *format.Opus")
+}
@@ -0,0 +1,40 @@
+package description
+
+import (
+	psdp "github.com/pion/sdp/v3"
+)
+
+// unmarshalAttributes returns attributes that are not decoded into other fields.
+func unmarshalAttributes(attributes []psdp.Attribute, isKnown func(attr psdp.Attribute) bool) map[string][]string {
+	var ret map[string][]string
+
+	for _, attr := range attributes {
+		if isKnown(attr) {
+			continue
+		}
+
+		if ret == nil {
+			ret = make(map[string][]string)
+		}
+
+		ret[attr.Key] = append(ret[attr.Key], attr.Value)
+	}
+
+	return ret
+}
+
+// marshalAttributes encodes attributes, sorted by key.
+func marshalAttributes(attributes map[string][]string) []psdp.Attribute {
+	var ret []psdp.Attribute
+
+	for _, key := range sortedKeys(attributes) {
+		for _, value := range attributes[key] {
+			ret = append(ret, psdp.Attribute{
+				Key:   key,
+				Value: value,
+			})
+		}
+	}
+
+	return ret
+}
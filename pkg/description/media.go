@@ -33,10 +33,20 @@ func isBackChannel(attributes []psdp.Attribute) bool {
 	return false
 }
 
-func sortedKeys(fmtp map[string]string) []string {
-	keys := make([]string, len(fmtp))
+func getDirection(attributes []psdp.Attribute) MediaDirection {
+	for _, attr := range attributes {
+		switch MediaDirection(attr.Key) {
+		case MediaDirectionSendOnly, MediaDirectionRecvOnly, MediaDirectionSendRecv, MediaDirectionInactive:
+			return MediaDirection(attr.Key)
+		}
+	}
+	return MediaDirectionUnspecified
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, len(m))
 	i := 0
-	for key := range fmtp {
+	for key := range m {
 		keys[i] = key
 		i++
 	}
@@ -44,6 +54,49 @@ func sortedKeys(fmtp map[string]string) []string {
 	return keys
 }
 
+// attributes that are decoded into fields of Media or into formats.
+var mediaKnownAttributes = map[string]struct{}{
+	"mid":      {},
+	"sendonly": {},
+	"recvonly": {},
+	"sendrecv": {},
+	"inactive": {},
+	"control":  {},
+	"crypto":   {},
+	"extmap":   {},
+	"label":    {},
+	"ssrc":     {},
+	"rtpmap":   {},
+	"fmtp":     {},
+}
+
+func isMediaKnownAttribute(attr psdp.Attribute) bool {
+	if attr.Key == "framerate" {
+		_, ok := parseFrameRate(attr.Value)
+		return ok
+	}
+
+	_, ok := mediaKnownAttributes[attr.Key]
+	return ok
+}
+
+func parseFrameRate(v string) (float64, bool) {
+	tmp, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || tmp <= 0 {
+		return 0, false
+	}
+	return tmp, true
+}
+
+func getBandwidth(bandwidths []psdp.Bandwidth, typ string) uint64 {
+	for _, b := range bandwidths {
+		if !b.Experimental && b.Type == typ {
+			return b.Bandwidth
+		}
+	}
+	return 0
+}
+
 func isAlphaNumeric(v string) bool {
 	for _, r := range v {
 		if !unicode.IsLetter(r) && !unicode.IsNumber(r) {
@@ -61,6 +114,19 @@ const (
 	MediaTypeVideo       MediaType = "video"
 	MediaTypeAudio       MediaType = "audio"
 	MediaTypeApplication MediaType = "application"
+	MediaTypeText        MediaType = "text"
+)
+
+// MediaDirection is the direction of a media stream.
+type MediaDirection string
+
+// media directions.
+const (
+	MediaDirectionUnspecified MediaDirection = ""
+	MediaDirectionSendOnly    MediaDirection = "sendonly"
+	MediaDirectionRecvOnly    MediaDirection = "recvonly"
+	MediaDirectionSendRecv    MediaDirection = "sendrecv"
+	MediaDirectionInactive    MediaDirection = "inactive"
 )
 
 // Media is a media stream.
@@ -75,40 +141,228 @@ type Media struct {
 	// Whether this media is a back channel.
 	IsBackChannel bool
 
+	// Direction (optional), from the sendonly, recvonly, sendrecv and inactive attributes.
+	// It is decoded and encoded independently of IsBackChannel.
+	// When it is not specified and IsBackChannel is true, sendonly is encoded.
+	Direction MediaDirection
+
 	// Control attribute.
 	Control string
 
+	// Title (optional), from the i= line, used to describe the media.
+	Title string
+
+	// Media-level connection information (optional, read only).
+	Connection *MediaConnection
+
+	// Crypto attributes (optional, read only), used to exchange SRTP keys.
+	// They are not marshaled, since SRTP is not supported.
+	Crypto []MediaCrypto
+
+	// RTP header extensions (optional).
+	Extensions []MediaExtension
+
+	// Label (optional), used to identify the media.
+	Label string
+
+	// Attributes of sources (optional).
+	SSRCs []MediaSSRC
+
+	// Nominal frame rate, from the framerate attribute (optional).
+	FrameRate float64
+
+	// Application-specific maximum bandwidth in kbit/s,
+	// from the b=AS line (optional).
+	BandwidthAS uint64
+
+	// Transport independent application-specific maximum bandwidth in bit/s,
+	// from the b=TIAS line (optional).
+	BandwidthTIAS uint64
+
+	// Attributes that are not decoded into other fields (optional), by key.
+	// They are encoded again by Marshal, sorted by key.
+	Attributes map[string][]string
+
 	// Formats contained into the media.
 	Formats []format.Format
 }
 
+func hasRTPMap(attributes []psdp.Attribute, payloadType string) bool {
+	for _, attr := range attributes {
+		if attr.Key == "rtpmap" && strings.HasPrefix(strings.TrimSpace(attr.Value), payloadType+" ") {
+			return true
+		}
+	}
+	return false
+}
+
 // Unmarshal decodes the media from the SDP format.
 func (m *Media) Unmarshal(md *psdp.MediaDescription) error {
+	return m.unmarshal(md, nil)
+}
+
+// unmarshal decodes the media.
+// When warn is not nil, non-fatal problems are passed to warn instead of being returned.
+func (m *Media) unmarshal(md *psdp.MediaDescription, warn func(string)) error {
 	m.Type = MediaType(md.MediaName.Media)
 
 	m.ID = getAttribute(md.Attributes, "mid")
 	if m.ID != "" && !isAlphaNumeric(m.ID) {
-		return fmt.Errorf("invalid mid: %v", m.ID)
+		if warn == nil {
+			return fmt.Errorf("invalid mid: %v", m.ID)
+		}
+		warn(fmt.Sprintf("invalid mid: %v", m.ID))
+		m.ID = ""
 	}
 
 	m.IsBackChannel = isBackChannel(md.Attributes)
+	m.Direction = getDirection(md.Attributes)
 	m.Control = getAttribute(md.Attributes, "control")
 
+	m.Title = ""
+	if md.MediaTitle != nil {
+		m.Title = string(*md.MediaTitle)
+	}
+
+	m.Connection = nil
+
+	if md.ConnectionInformation != nil && md.ConnectionInformation.Address != nil {
+		var c MediaConnection
+		err := c.unmarshal(md.ConnectionInformation)
+		if err != nil {
+			if warn == nil {
+				return err
+			}
+			warn(fmt.Sprintf("connection skipped: %v", err))
+		} else {
+			m.Connection = &c
+		}
+	}
+
+	m.Crypto = nil
+
+	for _, attr := range md.Attributes {
+		if attr.Key == "crypto" {
+			var c MediaCrypto
+			err := c.Unmarshal(attr.Value)
+			if err != nil {
+				// crypto attributes are not needed to read the stream,
+				// therefore invalid ones are skipped in strict mode too.
+				if warn != nil {
+					warn(fmt.Sprintf("crypto attribute skipped: %v", err))
+				}
+				continue
+			}
+
+			m.Crypto = append(m.Crypto, c)
+		}
+	}
+
+	m.Extensions = nil
+
+	for _, attr := range md.Attributes {
+		if attr.Key == "extmap" {
+			var e MediaExtension
+			err := e.Unmarshal(attr.Value)
+			if err != nil {
+				if warn == nil {
+					return err
+				}
+				warn(fmt.Sprintf("extmap attribute skipped: %v", err))
+				continue
+			}
+
+			m.Extensions = append(m.Extensions, e)
+		}
+	}
+
+	m.Label = getAttribute(md.Attributes, "label")
+
+	m.SSRCs = nil
+
+	for _, attr := range md.Attributes {
+		if attr.Key == "ssrc" {
+			var s MediaSSRC
+			err := s.Unmarshal(attr.Value)
+			if err != nil {
+				if warn == nil {
+					return err
+				}
+				warn(fmt.Sprintf("ssrc attribute skipped: %v", err))
+				continue
+			}
+
+			m.SSRCs = append(m.SSRCs, s)
+		}
+	}
+
+	m.FrameRate = 0
+
+	for _, attr := range md.Attributes {
+		if attr.Key == "framerate" {
+			if v, ok := parseFrameRate(attr.Value); ok {
+				m.FrameRate = v
+			}
+		}
+	}
+
+	m.BandwidthAS = getBandwidth(md.Bandwidth, "AS")
+	m.BandwidthTIAS = getBandwidth(md.Bandwidth, "TIAS")
+
+	m.Attributes = unmarshalAttributes(md.Attributes, isMediaKnownAttribute)
+
 	m.Formats = nil
+	payloadTypes := make(map[string]struct{})
 
 	for _, payloadType := range md.MediaName.Formats {
-		format, err := format.Unmarshal(md, payloadType)
+		if warn != nil {
+			if _, ok := payloadTypes[payloadType]; ok {
+				warn(fmt.Sprintf("duplicate payload type %v", payloadType))
+				continue
+			}
+			payloadTypes[payloadType] = struct{}{}
+		}
+
+		forma, err := format.Unmarshal(md, payloadType)
 		if err != nil {
-			return err
+			if warn == nil {
+				return err
+			}
+
+			if !hasRTPMap(md.Attributes, payloadType) {
+				warn(fmt.Sprintf("payload type %v has no rtpmap", payloadType))
+				continue
+			}
+
+			// fall back to a generic format, that allows to route packets anyway
+			generic, err2 := format.UnmarshalGeneric(md, payloadType)
+			if err2 != nil {
+				warn(fmt.Sprintf("payload type %v is invalid: %v", payloadType, err))
+				continue
+			}
+
+			warn(fmt.Sprintf("payload type %v is invalid, decoded as generic format: %v", payloadType, err))
+			forma = generic
 		}
 
-		m.Formats = append(m.Formats, format)
+		m.Formats = append(m.Formats, forma)
 	}
 
 	if m.Formats == nil {
 		return fmt.Errorf("no formats found")
 	}
 
+	if warn != nil {
+		for _, forma := range m.Formats {
+			if rtx, ok := forma.(*format.RTX); ok {
+				if _, ok = m.AssociatedFormat(rtx); !ok {
+					warn(fmt.Sprintf("payload type %v is associated with missing payload type %v",
+						rtx.PayloadTyp, rtx.AssociatedPayloadType))
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -121,6 +375,11 @@ func (m Media) Marshal() *psdp.MediaDescription {
 		},
 	}
 
+	if m.Title != "" {
+		title := psdp.Information(m.Title)
+		md.MediaTitle = &title
+	}
+
 	if m.ID != "" {
 		md.Attributes = append(md.Attributes, psdp.Attribute{
 			Key:   "mid",
@@ -128,9 +387,15 @@ func (m Media) Marshal() *psdp.MediaDescription {
 		})
 	}
 
-	if m.IsBackChannel {
+	switch {
+	case m.Direction != MediaDirectionUnspecified:
+		md.Attributes = append(md.Attributes, psdp.Attribute{
+			Key: string(m.Direction),
+		})
+
+	case m.IsBackChannel:
 		md.Attributes = append(md.Attributes, psdp.Attribute{
-			Key: "sendonly",
+			Key: string(MediaDirectionSendOnly),
 		})
 	}
 
@@ -139,6 +404,27 @@ func (m Media) Marshal() *psdp.MediaDescription {
 		Value: m.Control,
 	})
 
+	for _, e := range m.Extensions {
+		md.Attributes = append(md.Attributes, psdp.Attribute{
+			Key:   "extmap",
+			Value: e.Marshal(),
+		})
+	}
+
+	if m.Label != "" {
+		md.Attributes = append(md.Attributes, psdp.Attribute{
+			Key:   "label",
+			Value: m.Label,
+		})
+	}
+
+	for _, s := range m.SSRCs {
+		md.Attributes = append(md.Attributes, psdp.Attribute{
+			Key:   "ssrc",
+			Value: s.Marshal(),
+		})
+	}
+
 	for _, forma := range m.Formats {
 		typ := strconv.FormatUint(uint64(forma.PayloadType()), 10)
 		md.MediaName.Formats = append(md.MediaName.Formats, typ)
@@ -155,7 +441,12 @@ func (m Media) Marshal() *psdp.MediaDescription {
 		if len(fmtp) != 0 {
 			tmp := make([]string, len(fmtp))
 			for i, key := range sortedKeys(fmtp) {
-				tmp[i] = key + "=" + fmtp[key]
+				// parameters without value, like the ones of RED formats
+				if fmtp[key] == "" {
+					tmp[i] = key
+				} else {
+					tmp[i] = key + "=" + fmtp[key]
+				}
 			}
 
 			md.Attributes = append(md.Attributes, psdp.Attribute{
@@ -165,9 +456,52 @@ func (m Media) Marshal() *psdp.MediaDescription {
 		}
 	}
 
+	if m.FrameRate != 0 {
+		md.Attributes = append(md.Attributes, psdp.Attribute{
+			Key:   "framerate",
+			Value: strconv.FormatFloat(m.FrameRate, 'f', -1, 64),
+		})
+	}
+
+	if m.BandwidthAS != 0 {
+		md.Bandwidth = append(md.Bandwidth, psdp.Bandwidth{
+			Type:      "AS",
+			Bandwidth: m.BandwidthAS,
+		})
+	}
+
+	if m.BandwidthTIAS != 0 {
+		md.Bandwidth = append(md.Bandwidth, psdp.Bandwidth{
+			Type:      "TIAS",
+			Bandwidth: m.BandwidthTIAS,
+		})
+	}
+
+	md.Attributes = append(md.Attributes, marshalAttributes(m.Attributes)...)
+
 	return md
 }
 
+// ExtensionID returns the ID of the RTP header extension with the given URI.
+func (m Media) ExtensionID(uri string) (uint8, bool) {
+	for _, e := range m.Extensions {
+		if e.URI == uri {
+			return uint8(e.ID), true
+		}
+	}
+	return 0, false
+}
+
+// CNAME returns the canonical name of a source.
+func (m Media) CNAME(ssrc uint32) (string, bool) {
+	for _, s := range m.SSRCs {
+		if s.SSRC == ssrc && s.Attribute == "cname" {
+			return s.Value, true
+		}
+	}
+	return "", false
+}
+
 // URL returns the absolute URL of the media.
 func (m Media) URL(contentBase *base.URL) (*base.URL, error) {
 	if contentBase == nil {
@@ -216,3 +550,23 @@ func (m Media) FindFormat(forma interface{}) bool {
 	}
 	return false
 }
+
+// AssociatedFormat returns the format whose packets are retransmitted by a RTX format.
+func (m Media) AssociatedFormat(rtx *format.RTX) (format.Format, bool) {
+	for _, forma := range m.Formats {
+		if forma != rtx && forma.PayloadType() == rtx.AssociatedPayloadType {
+			return forma, true
+		}
+	}
+	return nil, false
+}
+
+// RTXFormat returns the RTX format that retransmits packets of a format.
+func (m Media) RTXFormat(forma format.Format) (*format.RTX, bool) {
+	for _, formak := range m.Formats {
+		if rtx, ok := formak.(*format.RTX); ok && rtx.AssociatedPayloadType == forma.PayloadType() {
+			return rtx, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,73 @@
+package description
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	psdp "github.com/pion/sdp/v3"
+)
+
+// MediaConnection is the connection information of a media (c= line).
+type MediaConnection struct {
+	// Connection address. It can be an IP or a hostname.
+	Address string
+
+	// Time to live of IPv4 multicast addresses (optional).
+	TTL *int
+
+	// Number of contiguous multicast addresses (optional).
+	NumberOfAddresses *int
+}
+
+func (c *MediaConnection) unmarshal(ci *psdp.ConnectionInformation) error {
+	parts := strings.Split(ci.Address.Address, "/")
+
+	c.Address = parts[0]
+	c.TTL = ci.Address.TTL
+	c.NumberOfAddresses = ci.Address.Range
+
+	parseInt := func(name string, v string) (*int, error) {
+		tmp, err := strconv.ParseUint(v, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", name, v)
+		}
+		i := int(tmp)
+		return &i, nil
+	}
+
+	var err error
+
+	// RFC4566: IPv6 multicast addresses do not have a TTL
+	if ci.AddressType == "IP6" {
+		switch len(parts) {
+		case 1:
+		case 2:
+			c.NumberOfAddresses, err = parseInt("number of addresses", parts[1])
+		default:
+			err = fmt.Errorf("invalid connection address: %v", ci.Address.Address)
+		}
+	} else {
+		switch len(parts) {
+		case 1:
+		case 2:
+			c.TTL, err = parseInt("TTL", parts[1])
+		case 3:
+			c.TTL, err = parseInt("TTL", parts[1])
+			if err == nil {
+				c.NumberOfAddresses, err = parseInt("number of addresses", parts[2])
+			}
+		default:
+			err = fmt.Errorf("invalid connection address: %v", ci.Address.Address)
+		}
+	}
+
+	return err
+}
+
+// IsMulticast checks whether the connection address is a multicast IP.
+func (c MediaConnection) IsMulticast() bool {
+	ip := net.ParseIP(c.Address)
+	return ip != nil && ip.IsMulticast()
+}
@@ -0,0 +1,156 @@
+package description
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MediaCryptoKey is a key contained into a crypto attribute.
+type MediaCryptoKey struct {
+	// Key method. Only "inline" is defined by RFC4568.
+	Method string
+
+	// Concatenation of master key and master salt.
+	KeySalt []byte
+
+	// Lifetime of the key (optional), in the original format (i.e. "2^20" or "1048576").
+	Lifetime string
+
+	// MKI value (optional).
+	MKIValue uint64
+
+	// MKI length in bytes (optional). If zero, MKI is not present.
+	MKILength int
+}
+
+func (k *MediaCryptoKey) unmarshal(v string) error {
+	parts := strings.SplitN(v, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid key parameter: %v", v)
+	}
+
+	k.Method = parts[0]
+	if k.Method != "inline" {
+		return fmt.Errorf("unsupported key method: %v", k.Method)
+	}
+
+	parts = strings.Split(parts[1], "|")
+
+	var err error
+	k.KeySalt, err = base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		// padding is optional in RFC4568
+		k.KeySalt, err = base64.RawStdEncoding.DecodeString(parts[0])
+		if err != nil {
+			return fmt.Errorf("invalid key: %v", parts[0])
+		}
+	}
+
+	for _, part := range parts[1:] {
+		if strings.Contains(part, ":") {
+			tmp := strings.SplitN(part, ":", 2)
+
+			val, err := strconv.ParseUint(tmp[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid MKI value: %v", tmp[0])
+			}
+			k.MKIValue = val
+
+			le, err := strconv.ParseUint(tmp[1], 10, 8)
+			if err != nil || le == 0 || le > 128 {
+				return fmt.Errorf("invalid MKI length: %v", tmp[1])
+			}
+			k.MKILength = int(le)
+		} else {
+			if k.Lifetime != "" {
+				return fmt.Errorf("multiple lifetimes")
+			}
+			k.Lifetime = part
+		}
+	}
+
+	return nil
+}
+
+func (k MediaCryptoKey) marshal() string {
+	ret := k.Method + ":" + base64.StdEncoding.EncodeToString(k.KeySalt)
+
+	if k.Lifetime != "" {
+		ret += "|" + k.Lifetime
+	}
+
+	if k.MKILength != 0 {
+		ret += "|" + strconv.FormatUint(k.MKIValue, 10) + ":" + strconv.FormatInt(int64(k.MKILength), 10)
+	}
+
+	return ret
+}
+
+// MediaCrypto is a crypto attribute, used to exchange SRTP keys
+// with Security Descriptions (SDES).
+// Specification: https://datatracker.ietf.org/doc/html/rfc4568
+type MediaCrypto struct {
+	// Tag, used to identify the attribute.
+	Tag int
+
+	// Crypto suite (i.e. "AES_CM_128_HMAC_SHA1_80").
+	Suite string
+
+	// Keys.
+	Keys []MediaCryptoKey
+
+	// Session parameters (optional), in the original format (i.e. "UNENCRYPTED_SRTCP").
+	SessionParams []string
+}
+
+// Unmarshal decodes a crypto attribute value.
+func (c *MediaCrypto) Unmarshal(v string) error {
+	parts := strings.Fields(v)
+	if len(parts) < 3 {
+		return fmt.Errorf("invalid crypto attribute: %v", v)
+	}
+
+	tmp, err := strconv.ParseUint(parts[0], 10, 31)
+	if err != nil || len(parts[0]) > 9 {
+		return fmt.Errorf("invalid crypto tag: %v", parts[0])
+	}
+	c.Tag = int(tmp)
+
+	c.Suite = parts[1]
+
+	c.Keys = nil
+	for _, kp := range strings.Split(parts[2], ";") {
+		var k MediaCryptoKey
+		err = k.unmarshal(kp)
+		if err != nil {
+			return err
+		}
+		c.Keys = append(c.Keys, k)
+	}
+
+	if len(parts) > 3 {
+		c.SessionParams = parts[3:]
+	} else {
+		c.SessionParams = nil
+	}
+
+	return nil
+}
+
+// Marshal encodes a crypto attribute value.
+func (c MediaCrypto) Marshal() string {
+	keys := make([]string, len(c.Keys))
+	for i, k := range c.Keys {
+		keys[i] = k.marshal()
+	}
+
+	ret := strconv.FormatInt(int64(c.Tag), 10) + " " + c.Suite + " " + strings.Join(keys, ";")
+
+	if len(c.SessionParams) != 0 {
+		ret += " " + strings.Join(c.SessionParams, " ")
+	}
+
+	return ret
+}
@@ -0,0 +1,74 @@
+package description
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MediaExtension is a extmap attribute, used to negotiate RTP header extensions.
+// Specification: https://datatracker.ietf.org/doc/html/rfc8285
+type MediaExtension struct {
+	// ID of the extension, between 1 and 255.
+	// IDs between 1 and 14 can be used with the one-byte header format.
+	ID int
+
+	// Direction (optional), i.e. "sendonly", "recvonly", "sendrecv" or "inactive".
+	Direction string
+
+	// URI that identifies the extension.
+	URI string
+
+	// Extension attributes (optional), in the original format.
+	Attributes string
+}
+
+// Unmarshal decodes a extmap attribute value.
+func (e *MediaExtension) Unmarshal(v string) error {
+	parts := strings.SplitN(v, " ", 3)
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid extmap attribute: %v", v)
+	}
+
+	id, direction, _ := strings.Cut(parts[0], "/")
+
+	tmp, err := strconv.ParseUint(id, 10, 8)
+	if err != nil || tmp == 0 {
+		return fmt.Errorf("invalid extmap ID: %v", id)
+	}
+	e.ID = int(tmp)
+
+	switch direction {
+	case "", "sendonly", "recvonly", "sendrecv", "inactive":
+	default:
+		return fmt.Errorf("invalid extmap direction: %v", direction)
+	}
+	e.Direction = direction
+
+	e.URI = parts[1]
+
+	if len(parts) == 3 {
+		e.Attributes = parts[2]
+	} else {
+		e.Attributes = ""
+	}
+
+	return nil
+}
+
+// Marshal encodes a extmap attribute value.
+func (e MediaExtension) Marshal() string {
+	ret := strconv.FormatInt(int64(e.ID), 10)
+
+	if e.Direction != "" {
+		ret += "/" + e.Direction
+	}
+
+	ret += " " + e.URI
+
+	if e.Attributes != "" {
+		ret += " " + e.Attributes
+	}
+
+	return ret
+}
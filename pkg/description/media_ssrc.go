@@ -0,0 +1,49 @@
+package description
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MediaSSRC is a ssrc attribute, used to describe a source of a media.
+// Specification: https://datatracker.ietf.org/doc/html/rfc5576
+type MediaSSRC struct {
+	// SSRC of the source.
+	SSRC uint32
+
+	// Name of the attribute, i.e. "cname".
+	Attribute string
+
+	// Value of the attribute (optional).
+	Value string
+}
+
+// Unmarshal decodes a ssrc attribute value.
+func (s *MediaSSRC) Unmarshal(v string) error {
+	ssrc, attr, ok := strings.Cut(v, " ")
+	if !ok || attr == "" {
+		return fmt.Errorf("invalid ssrc attribute: %v", v)
+	}
+
+	tmp, err := strconv.ParseUint(ssrc, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid SSRC: %v", ssrc)
+	}
+	s.SSRC = uint32(tmp)
+
+	s.Attribute, s.Value, _ = strings.Cut(attr, ":")
+
+	return nil
+}
+
+// Marshal encodes a ssrc attribute value.
+func (s MediaSSRC) Marshal() string {
+	ret := strconv.FormatUint(uint64(s.SSRC), 10) + " " + s.Attribute
+
+	if s.Value != "" {
+		ret += ":" + s.Value
+	}
+
+	return ret
+}
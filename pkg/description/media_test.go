@@ -173,3 +173,311 @@ func TestMediaURLError(t *testing.T) {
 	_, err := media.URL(nil)
 	require.EqualError(t, err, "Content-Base header not provided")
 }
+
+func TestMediaCryptoUnmarshalError(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		v    string
+		err  string
+	}{
+		{
+			"missing fields",
+			"1 AES_CM_128_HMAC_SHA1_80",
+			"invalid crypto attribute: 1 AES_CM_128_HMAC_SHA1_80",
+		},
+		{
+			"invalid tag",
+			"a AES_CM_128_HMAC_SHA1_80 inline:AAAA",
+			"invalid crypto tag: a",
+		},
+		{
+			"unsupported key method",
+			"1 AES_CM_128_HMAC_SHA1_80 uri:AAAA",
+			"unsupported key method: uri",
+		},
+		{
+			"invalid key",
+			"1 AES_CM_128_HMAC_SHA1_80 inline:!!!!",
+			"invalid key: !!!!",
+		},
+		{
+			"invalid mki length",
+			"1 AES_CM_128_HMAC_SHA1_80 inline:AAAA|1:0",
+			"invalid MKI length: 0",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var c MediaCrypto
+			err := c.Unmarshal(ca.v)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestMediaExtension(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		v    string
+		ext  MediaExtension
+	}{
+		{
+			"standard",
+			"1 urn:ietf:params:rtp-hdrext:ssrc-audio-level",
+			MediaExtension{
+				ID:  1,
+				URI: "urn:ietf:params:rtp-hdrext:ssrc-audio-level",
+			},
+		},
+		{
+			"direction and attributes",
+			"20/sendonly urn:ietf:params:rtp-hdrext:ssrc-audio-level vad=on",
+			MediaExtension{
+				ID:         20,
+				Direction:  "sendonly",
+				URI:        "urn:ietf:params:rtp-hdrext:ssrc-audio-level",
+				Attributes: "vad=on",
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var e MediaExtension
+			err := e.Unmarshal(ca.v)
+			require.NoError(t, err)
+			require.Equal(t, ca.ext, e)
+			require.Equal(t, ca.v, e.Marshal())
+		})
+	}
+}
+
+func TestMediaExtensionUnmarshalError(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		v    string
+		err  string
+	}{
+		{
+			"missing uri",
+			"1",
+			"invalid extmap attribute: 1",
+		},
+		{
+			"invalid id",
+			"256 urn:ietf:params:rtp-hdrext:toffset",
+			"invalid extmap ID: 256",
+		},
+		{
+			"invalid direction",
+			"1/both urn:ietf:params:rtp-hdrext:toffset",
+			"invalid extmap direction: both",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var e MediaExtension
+			err := e.Unmarshal(ca.v)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestMediaExtensionID(t *testing.T) {
+	m := Media{
+		Extensions: []MediaExtension{
+			{ID: 3, URI: "urn:ietf:params:rtp-hdrext:toffset"},
+		},
+	}
+
+	id, ok := m.ExtensionID("urn:ietf:params:rtp-hdrext:toffset")
+	require.True(t, ok)
+	require.Equal(t, uint8(3), id)
+
+	_, ok = m.ExtensionID("urn:ietf:params:rtp-hdrext:sdes:mid")
+	require.False(t, ok)
+}
+
+func TestMediaSSRC(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		v    string
+		ssrc MediaSSRC
+	}{
+		{
+			"cname",
+			"3754810229 cname:CvU1TYqkVsjj5XOt",
+			MediaSSRC{
+				SSRC:      3754810229,
+				Attribute: "cname",
+				Value:     "CvU1TYqkVsjj5XOt",
+			},
+		},
+		{
+			"no value",
+			"3754810229 mute",
+			MediaSSRC{
+				SSRC:      3754810229,
+				Attribute: "mute",
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var s MediaSSRC
+			err := s.Unmarshal(ca.v)
+			require.NoError(t, err)
+			require.Equal(t, ca.ssrc, s)
+			require.Equal(t, ca.v, s.Marshal())
+		})
+	}
+}
+
+func TestMediaSSRCUnmarshalError(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		v    string
+		err  string
+	}{
+		{
+			"missing attribute",
+			"3754810229",
+			"invalid ssrc attribute: 3754810229",
+		},
+		{
+			"invalid ssrc",
+			"abc cname:test",
+			"invalid SSRC: abc",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var s MediaSSRC
+			err := s.Unmarshal(ca.v)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestMediaCNAME(t *testing.T) {
+	m := Media{
+		SSRCs: []MediaSSRC{
+			{SSRC: 123, Attribute: "msid", Value: "test"},
+			{SSRC: 123, Attribute: "cname", Value: "mycname"},
+		},
+	}
+
+	cname, ok := m.CNAME(123)
+	require.True(t, ok)
+	require.Equal(t, "mycname", cname)
+
+	_, ok = m.CNAME(456)
+	require.False(t, ok)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestMediaConnection(t *testing.T) {
+	for _, ca := range []struct {
+		name      string
+		c         string
+		conn      *MediaConnection
+		multicast bool
+	}{
+		{
+			"ipv4 unicast",
+			"c=IN IP4 192.168.1.10\r\n",
+			&MediaConnection{Address: "192.168.1.10"},
+			false,
+		},
+		{
+			"ipv4 multicast with ttl",
+			"c=IN IP4 224.2.1.1/127\r\n",
+			&MediaConnection{Address: "224.2.1.1", TTL: intPtr(127)},
+			true,
+		},
+		{
+			"ipv4 multicast with ttl and number of addresses",
+			"c=IN IP4 224.2.1.1/127/3\r\n",
+			&MediaConnection{Address: "224.2.1.1", TTL: intPtr(127), NumberOfAddresses: intPtr(3)},
+			true,
+		},
+		{
+			"ipv6 multicast with number of addresses",
+			"c=IN IP6 ff15::101/3\r\n",
+			&MediaConnection{Address: "ff15::101", NumberOfAddresses: intPtr(3)},
+			true,
+		},
+		{
+			"missing",
+			"",
+			nil,
+			false,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var sd sdp.SessionDescription
+			err := sd.Unmarshal([]byte("v=0\r\n" +
+				"s= \r\n" +
+				"m=video 0 RTP/AVP 96\r\n" +
+				ca.c +
+				"a=rtpmap:96 H264/90000\r\n"))
+			require.NoError(t, err)
+
+			var media Media
+			err = media.Unmarshal(sd.MediaDescriptions[0])
+			require.NoError(t, err)
+			require.Equal(t, ca.conn, media.Connection)
+
+			if ca.conn != nil {
+				require.Equal(t, ca.multicast, ca.conn.IsMulticast())
+			}
+		})
+	}
+}
+
+func TestMediaConnectionError(t *testing.T) {
+	var sd sdp.SessionDescription
+	err := sd.Unmarshal([]byte("v=0\r\n" +
+		"s= \r\n" +
+		"m=video 0 RTP/AVP 96\r\n" +
+		"c=IN IP4 224.2.1.1/abc\r\n" +
+		"a=rtpmap:96 H264/90000\r\n"))
+	require.NoError(t, err)
+
+	var media Media
+	err = media.Unmarshal(sd.MediaDescriptions[0])
+	require.EqualError(t, err, "invalid TTL: abc")
+}
+
+func TestMediaRTX(t *testing.T) {
+	var sd sdp.SessionDescription
+	err := sd.Unmarshal([]byte("v=0\r\n" +
+		"s= \r\n" +
+		"m=video 0 RTP/AVP 96 97 98 99\r\n" +
+		"a=rtpmap:96 H264/90000\r\n" +
+		"a=rtpmap:97 rtx/90000\r\n" +
+		"a=fmtp:97 apt=96\r\n" +
+		"a=rtpmap:98 VP8/90000\r\n" +
+		"a=rtpmap:99 rtx/90000\r\n" +
+		"a=fmtp:99 apt=100\r\n"))
+	require.NoError(t, err)
+
+	var media Media
+	var warnings []string
+	err = media.unmarshal(sd.MediaDescriptions[0], func(w string) {
+		warnings = append(warnings, w)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"payload type 99 is associated with missing payload type 100"}, warnings)
+
+	rtx, ok := media.RTXFormat(media.Formats[0])
+	require.True(t, ok)
+	require.Equal(t, media.Formats[1], rtx)
+
+	forma, ok := media.AssociatedFormat(rtx)
+	require.True(t, ok)
+	require.Equal(t, media.Formats[0], forma)
+
+	_, ok = media.RTXFormat(media.Formats[2])
+	require.False(t, ok)
+
+	_, ok = media.AssociatedFormat(media.Formats[3].(*format.RTX))
+	require.False(t, ok)
+}
@@ -37,6 +37,18 @@ func hasMediaWithID(medias []*Media, id string) bool {
 	return false
 }
 
+// session attributes that are decoded into fields of Session.
+// The control attribute is discarded since it refers to the original server.
+func isSessionKnownAttribute(attr psdp.Attribute) bool {
+	if attr.Key == "range" {
+		var r SessionRange
+		return r.Unmarshal(attr.Value) == nil
+	}
+
+	return attr.Key == "control" ||
+		(attr.Key == "group" && strings.HasPrefix(attr.Value, "FEC "))
+}
+
 // SessionFECGroup is a FEC group.
 type SessionFECGroup []string
 
@@ -51,6 +63,31 @@ type Session struct {
 	// FEC groups (RFC5109).
 	FECGroups []SessionFECGroup
 
+	// Time range of the stream (optional).
+	// It allows to know whether the stream is live or recorded, and its duration.
+	Range *SessionRange
+
+	// Attributes that are not decoded into other fields (optional), by key.
+	// They are encoded again by Marshal, sorted by key.
+	Attributes map[string][]string
+
+	// Origin of the description, encoded into the o= line (optional).
+	// It is used by Marshal only, since the origin of a decoded description
+	// refers to the original server.
+	Origin *SessionOrigin
+
+	// Information about the stream, encoded into the i= line (optional).
+	// It is used by Marshal only.
+	Information string
+
+	// Name of the tool that created the description, encoded into the tool attribute (optional).
+	// It is used by Marshal only.
+	Tool string
+
+	// Session-level connection address (optional).
+	// It is used by Marshal only, in place of 0.0.0.0, when multicast is false.
+	ConnectionAddress string
+
 	// Media streams.
 	Medias []*Media
 }
@@ -69,38 +106,91 @@ func (d *Session) FindFormat(forma interface{}) *Media {
 
 // Unmarshal decodes the description from SDP.
 func (d *Session) Unmarshal(ssd *sdp.SessionDescription) error {
+	_, err := d.unmarshal(ssd, false)
+	return err
+}
+
+// UnmarshalLenient decodes the description from SDP in lenient mode.
+// Many devices emit broken descriptions, therefore problems that don't prevent
+// the stream from being read (invalid fmtp values, duplicate payload types,
+// missing rtpmap attributes, invalid media attributes) are returned as warnings
+// instead of errors. Formats that can't be decoded are decoded as Generic formats
+// or skipped, and medias without valid formats are skipped.
+// An error is returned only when no media can be decoded.
+func (d *Session) UnmarshalLenient(ssd *sdp.SessionDescription) ([]Warning, error) {
+	return d.unmarshal(ssd, true)
+}
+
+func (d *Session) unmarshal(ssd *sdp.SessionDescription, lenient bool) ([]Warning, error) {
+	var warnings []Warning
+
 	d.Title = string(ssd.SessionName)
 	if d.Title == " " {
 		d.Title = ""
 	}
 
-	d.Medias = make([]*Media, len(ssd.MediaDescriptions))
+	d.Medias = nil
 
 	for i, md := range ssd.MediaDescriptions {
+		var warn func(string)
+		if lenient {
+			warn = func(desc string) {
+				warnings = append(warnings, Warning{Media: i + 1, Description: desc})
+			}
+		}
+
 		var m Media
-		err := m.Unmarshal(md)
+		err := m.unmarshal(md, warn)
 		if err != nil {
-			return fmt.Errorf("media %d is invalid: %w", i+1, err)
+			if !lenient {
+				return nil, fmt.Errorf("media %d is invalid: %w", i+1, err)
+			}
+			warn(fmt.Sprintf("media skipped: %v", err))
+			continue
 		}
 
-		if m.ID != "" && hasMediaWithID(d.Medias[:i], m.ID) {
-			return fmt.Errorf("duplicate media IDs")
+		if m.ID != "" && hasMediaWithID(d.Medias, m.ID) {
+			if !lenient {
+				return nil, fmt.Errorf("duplicate media IDs")
+			}
+			warn(fmt.Sprintf("duplicate mid: %v", m.ID))
+			m.ID = ""
 		}
 
-		d.Medias[i] = &m
+		d.Medias = append(d.Medias, &m)
+	}
+
+	if lenient && len(d.Medias) == 0 && len(ssd.MediaDescriptions) != 0 {
+		return nil, fmt.Errorf("no valid medias found")
 	}
 
 	if atLeastOneHasMID(d.Medias) && atLeastOneDoesntHaveMID(d.Medias) {
-		return fmt.Errorf("media IDs sent partially")
+		if !lenient {
+			return nil, fmt.Errorf("media IDs sent partially")
+		}
+		warnings = append(warnings, Warning{Description: "media IDs sent partially, discarding them"})
+
+		for _, m := range d.Medias {
+			m.ID = ""
+		}
 	}
 
+	d.FECGroups = nil
+
+outer:
 	for _, attr := range ssd.Attributes {
 		if attr.Key == "group" && strings.HasPrefix(attr.Value, "FEC ") {
 			group := SessionFECGroup(strings.Split(attr.Value[len("FEC "):], " "))
 
 			for _, id := range group {
 				if !hasMediaWithID(d.Medias, id) {
-					return fmt.Errorf("FEC group points to an invalid media ID: %v", id)
+					if !lenient {
+						return nil, fmt.Errorf("FEC group points to an invalid media ID: %v", id)
+					}
+					warnings = append(warnings, Warning{
+						Description: fmt.Sprintf("FEC group points to an invalid media ID: %v", id),
+					})
+					continue outer
 				}
 			}
 
@@ -108,7 +198,20 @@ func (d *Session) Unmarshal(ssd *sdp.SessionDescription) error {
 		}
 	}
 
-	return nil
+	d.Range = nil
+
+	for _, attr := range ssd.Attributes {
+		if attr.Key == "range" {
+			var r SessionRange
+			if r.Unmarshal(attr.Value) == nil {
+				d.Range = &r
+			}
+		}
+	}
+
+	d.Attributes = unmarshalAttributes(ssd.Attributes, isSessionKnownAttribute)
+
+	return warnings, nil
 }
 
 // Marshal encodes the description in SDP.
@@ -123,24 +226,27 @@ func (d Session) Marshal(multicast bool) ([]byte, error) {
 	}
 
 	var address string
-	if multicast {
+	switch {
+	case multicast:
 		address = "224.1.0.0"
-	} else {
+	case d.ConnectionAddress != "":
+		address = d.ConnectionAddress
+	default:
 		address = "0.0.0.0"
 	}
 
+	origin := d.Origin
+	if origin == nil {
+		origin = &SessionOrigin{}
+	}
+
 	sout := &sdp.SessionDescription{
 		SessionName: sessionName,
-		Origin: psdp.Origin{
-			Username:       "-",
-			NetworkType:    "IN",
-			AddressType:    "IP4",
-			UnicastAddress: "127.0.0.1",
-		},
+		Origin:      origin.marshal(),
 		// required by Darwin Streaming Server
 		ConnectionInformation: &psdp.ConnectionInformation{
 			NetworkType: "IN",
-			AddressType: "IP4",
+			AddressType: addressType(address),
 			Address:     &psdp.Address{Address: address},
 		},
 		TimeDescriptions: []psdp.TimeDescription{
@@ -149,6 +255,11 @@ func (d Session) Marshal(multicast bool) ([]byte, error) {
 		MediaDescriptions: make([]*psdp.MediaDescription, len(d.Medias)),
 	}
 
+	if d.Information != "" {
+		info := psdp.Information(d.Information)
+		sout.SessionInformation = &info
+	}
+
 	for i, media := range d.Medias {
 		sout.MediaDescriptions[i] = media.Marshal()
 	}
@@ -160,5 +271,21 @@ func (d Session) Marshal(multicast bool) ([]byte, error) {
 		})
 	}
 
+	if d.Range != nil {
+		sout.Attributes = append(sout.Attributes, psdp.Attribute{
+			Key:   "range",
+			Value: d.Range.Marshal(),
+		})
+	}
+
+	if d.Tool != "" {
+		sout.Attributes = append(sout.Attributes, psdp.Attribute{
+			Key:   "tool",
+			Value: d.Tool,
+		})
+	}
+
+	sout.Attributes = append(sout.Attributes, marshalAttributes(d.Attributes)...)
+
 	return sout.Marshal()
 }
@@ -0,0 +1,50 @@
+package description
+
+import (
+	"net"
+
+	psdp "github.com/pion/sdp/v3"
+)
+
+func addressType(address string) string {
+	if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+		return "IP6"
+	}
+	return "IP4"
+}
+
+// SessionOrigin is the origin of a stream description, encoded into the o= line.
+type SessionOrigin struct {
+	// user name on the host that created the description.
+	// It defaults to "-".
+	Username string
+	// identifier of the session.
+	SessionID uint64
+	// version of the description.
+	SessionVersion uint64
+	// address of the host that created the description.
+	// It defaults to "127.0.0.1".
+	Address string
+}
+
+func (o *SessionOrigin) marshal() psdp.Origin {
+	out := psdp.Origin{
+		Username:       "-",
+		SessionID:      o.SessionID,
+		SessionVersion: o.SessionVersion,
+		NetworkType:    "IN",
+		AddressType:    "IP4",
+		UnicastAddress: "127.0.0.1",
+	}
+
+	if o.Username != "" {
+		out.Username = o.Username
+	}
+
+	if o.Address != "" {
+		out.AddressType = addressType(o.Address)
+		out.UnicastAddress = o.Address
+	}
+
+	return out
+}
@@ -0,0 +1,73 @@
+package description
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/headers"
+)
+
+// SessionRange is the time range of a stream, decoded from the range attribute.
+type SessionRange struct {
+	// range expressed in a certain unit
+	// (*headers.RangeNPT, *headers.RangeSMPTE or *headers.RangeUTC).
+	// It is nil when the range is "npt=now-", that is, the stream is live.
+	Value headers.RangeValue
+}
+
+// Unmarshal decodes the range from the value of a range attribute.
+func (r *SessionRange) Unmarshal(v string) error {
+	v = strings.TrimSpace(v)
+
+	if v == "npt=now-" {
+		r.Value = nil
+		return nil
+	}
+
+	var h headers.Range
+	err := h.Unmarshal(base.HeaderValue{v})
+	if err != nil {
+		return err
+	}
+
+	r.Value = h.Value
+	return nil
+}
+
+// Marshal encodes the range into the value of a range attribute.
+func (r SessionRange) Marshal() string {
+	if r.Value == nil {
+		return "npt=now-"
+	}
+
+	return headers.Range{Value: r.Value}.Marshal()[0]
+}
+
+// IsLive returns whether the stream is live, that is, the range has no end.
+func (r SessionRange) IsLive() bool {
+	_, ok := r.Duration()
+	return !ok
+}
+
+// Duration returns the duration of a recorded stream.
+func (r SessionRange) Duration() (time.Duration, bool) {
+	switch v := r.Value.(type) {
+	case *headers.RangeNPT:
+		if v.End != nil {
+			return *v.End - v.Start, true
+		}
+
+	case *headers.RangeSMPTE:
+		if v.End != nil {
+			return v.End.Time - v.Start.Time, true
+		}
+
+	case *headers.RangeUTC:
+		if v.End != nil {
+			return v.End.Sub(v.Start), true
+		}
+	}
+
+	return 0, false
+}
@@ -2,10 +2,12 @@ package description
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/headers"
 	"github.com/bluenviron/gortsplib/v4/pkg/sdp"
 )
 
@@ -46,31 +48,49 @@ var casesSession = []struct {
 			"s=Media Presentation\r\n" +
 			"c=IN IP4 0.0.0.0\r\n" +
 			"t=0 0\r\n" +
+			"a=range:npt=now-\r\n" +
 			"m=video 0 RTP/AVP 97\r\n" +
+			"b=AS:2560\r\n" +
 			"a=control:rtsp://10.0.100.50/profile5/media.smp/trackID=v\r\n" +
 			"a=rtpmap:97 H264/90000\r\n" +
 			"a=fmtp:97 packetization-mode=1; profile-level-id=640028; sprop-parameter-sets=Z2QAKKy0A8ARPyo=,aO4Bniw=\r\n" +
+			"a=framerate:30\r\n" +
+			"a=cliprect:0,0,1080,1920\r\n" +
+			"a=framesize:97 1920-1080\r\n" +
 			"m=audio 0 RTP/AVP 0\r\n" +
+			"b=AS:64\r\n" +
+			"a=recvonly\r\n" +
 			"a=control:rtsp://10.0.100.50/profile5/media.smp/trackID=a\r\n" +
 			"a=rtpmap:0 PCMU/8000\r\n" +
 			"m=application 0 RTP/AVP 107\r\n" +
+			"b=AS:8\r\n" +
 			"a=control\r\n",
 		Session{
 			Title: `Media Presentation`,
+			Range: &SessionRange{},
 			Medias: []*Media{
 				{
-					Type:    MediaTypeVideo,
-					Control: "rtsp://10.0.100.50/profile5/media.smp/trackID=v",
+					Type:        MediaTypeVideo,
+					Control:     "rtsp://10.0.100.50/profile5/media.smp/trackID=v",
+					FrameRate:   30,
+					BandwidthAS: 2560,
+					Attributes: map[string][]string{
+						"cliprect":  {"0,0,1080,1920"},
+						"framesize": {"97 1920-1080"},
+					},
 					Formats: []format.Format{&format.H264{
 						PayloadTyp:        97,
 						PacketizationMode: 1,
 						SPS:               []byte{0x67, 0x64, 0x00, 0x28, 0xac, 0xb4, 0x03, 0xc0, 0x11, 0x3f, 0x2a},
 						PPS:               []byte{0x68, 0xee, 0x01, 0x9e, 0x2c},
+						ProfileLevelID:    []byte{0x64, 0x00, 0x28},
 					}},
 				},
 				{
-					Type:    MediaTypeAudio,
-					Control: "rtsp://10.0.100.50/profile5/media.smp/trackID=a",
+					Type:        MediaTypeAudio,
+					Direction:   MediaDirectionRecvOnly,
+					Control:     "rtsp://10.0.100.50/profile5/media.smp/trackID=a",
+					BandwidthAS: 64,
 					Formats: []format.Format{&format.G711{
 						PayloadTyp:   0,
 						MULaw:        true,
@@ -79,7 +99,8 @@ var casesSession = []struct {
 					}},
 				},
 				{
-					Type: MediaTypeApplication,
+					Type:        MediaTypeApplication,
+					BandwidthAS: 8,
 					Formats: []format.Format{&format.Generic{
 						PayloadTyp: 107,
 					}},
@@ -117,31 +138,49 @@ var casesSession = []struct {
 			"s=Media Presentation\r\n" +
 			"c=IN IP4 0.0.0.0\r\n" +
 			"t=0 0\r\n" +
+			"a=range:npt=now-\r\n" +
 			"m=video 0 RTP/AVP 97\r\n" +
+			"b=AS:2560\r\n" +
 			"a=control:trackID=1\r\n" +
 			"a=rtpmap:97 H264/90000\r\n" +
 			"a=fmtp:97 packetization-mode=1; profile-level-id=640028; sprop-parameter-sets=Z2QAKKy0A8ARPyo=,aO4Bniw=\r\n" +
+			"a=framerate:30\r\n" +
+			"a=cliprect:0,0,1080,1920\r\n" +
+			"a=framesize:97 1920-1080\r\n" +
 			"m=audio 0 RTP/AVP 0\r\n" +
+			"b=AS:64\r\n" +
+			"a=recvonly\r\n" +
 			"a=control:trackID=2\r\n" +
 			"a=rtpmap:0 PCMU/8000\r\n" +
 			"m=application 0 RTP/AVP 107\r\n" +
+			"b=AS:8\r\n" +
 			"a=control\r\n",
 		Session{
 			Title: `Media Presentation`,
+			Range: &SessionRange{},
 			Medias: []*Media{
 				{
-					Type:    MediaTypeVideo,
-					Control: "trackID=1",
+					Type:        MediaTypeVideo,
+					Control:     "trackID=1",
+					FrameRate:   30,
+					BandwidthAS: 2560,
+					Attributes: map[string][]string{
+						"cliprect":  {"0,0,1080,1920"},
+						"framesize": {"97 1920-1080"},
+					},
 					Formats: []format.Format{&format.H264{
 						PayloadTyp:        97,
 						PacketizationMode: 1,
 						SPS:               []byte{0x67, 0x64, 0x00, 0x28, 0xac, 0xb4, 0x03, 0xc0, 0x11, 0x3f, 0x2a},
 						PPS:               []byte{0x68, 0xee, 0x01, 0x9e, 0x2c},
+						ProfileLevelID:    []byte{0x64, 0x00, 0x28},
 					}},
 				},
 				{
-					Type:    MediaTypeAudio,
-					Control: "trackID=2",
+					Type:        MediaTypeAudio,
+					Direction:   MediaDirectionRecvOnly,
+					Control:     "trackID=2",
+					BandwidthAS: 64,
 					Formats: []format.Format{&format.G711{
 						PayloadTyp:   0,
 						MULaw:        true,
@@ -150,7 +189,8 @@ var casesSession = []struct {
 					}},
 				},
 				{
-					Type: MediaTypeApplication,
+					Type:        MediaTypeApplication,
+					BandwidthAS: 8,
 					Formats: []format.Format{&format.Generic{
 						PayloadTyp: 107,
 					}},
@@ -265,16 +305,26 @@ var casesSession = []struct {
 			"s= \r\n" +
 			"c=IN IP4 0.0.0.0\r\n" +
 			"t=0 0\r\n" +
+			"a=group:BUNDLE audio video\r\n" +
+			"a=msid-semantic: WMS mediaSessionLocal\r\n" +
 			"m=audio 0 RTP/AVP 111 103 104 9 102 0 8 106 105 13 110 112 113 126\r\n" +
 			"a=mid:audio\r\n" +
 			"a=sendonly\r\n" +
 			"a=control\r\n" +
+			"a=extmap:1 urn:ietf:params:rtp-hdrext:ssrc-audio-level\r\n" +
+			"a=extmap:2 http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time\r\n" +
+			"a=extmap:3 http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01\r\n" +
+			"a=ssrc:3754810229 cname:CvU1TYqkVsjj5XOt\r\n" +
+			"a=ssrc:3754810229 msid:mediaSessionLocal 101\r\n" +
+			"a=ssrc:3754810229 mslabel:mediaSessionLocal\r\n" +
+			"a=ssrc:3754810229 label:101\r\n" +
 			"a=rtpmap:111 opus/48000/2\r\n" +
 			"a=fmtp:111 sprop-stereo=0\r\n" +
 			"a=rtpmap:103 ISAC/16000\r\n" +
 			"a=rtpmap:104 ISAC/32000\r\n" +
 			"a=rtpmap:9 G722/8000\r\n" +
-			"a=rtpmap:102 ILBC/8000\r\n" +
+			"a=rtpmap:102 iLBC/8000\r\n" +
+			"a=fmtp:102 mode=30\r\n" +
 			"a=rtpmap:0 PCMU/8000\r\n" +
 			"a=rtpmap:8 PCMA/8000\r\n" +
 			"a=rtpmap:106 CN/32000\r\n" +
@@ -284,10 +334,35 @@ var casesSession = []struct {
 			"a=rtpmap:112 telephone-event/32000\r\n" +
 			"a=rtpmap:113 telephone-event/16000\r\n" +
 			"a=rtpmap:126 telephone-event/8000\r\n" +
+			"a=fingerprint:sha-256" +
+			" 5E:B5:97:8B:B4:D8:AE:2B:89:F6:82:44:47:69:77:83:05:29:C5:C8:EE:67:50:C3:77:6B:A7:BA:10:E3:08:B8\r\n" +
+			"a=ice-options:trickle renomination\r\n" +
+			"a=ice-pwd:V3YEqLGAJJhUDUa13C/pKbWe\r\n" +
+			"a=ice-ufrag:0D6Y\r\n" +
+			"a=rtcp:9 IN IP4 0.0.0.0\r\n" +
+			"a=rtcp-fb:111 transport-cc\r\n" +
+			"a=rtcp-mux\r\n" +
+			"a=setup:actpass\r\n" +
 			"m=video 0 RTP/AVP 96 97 98 99 100 101 127 124 125\r\n" +
 			"a=mid:video\r\n" +
 			"a=sendonly\r\n" +
 			"a=control\r\n" +
+			"a=extmap:14 urn:ietf:params:rtp-hdrext:toffset\r\n" +
+			"a=extmap:2 http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time\r\n" +
+			"a=extmap:13 urn:3gpp:video-orientation\r\n" +
+			"a=extmap:3 http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01\r\n" +
+			"a=extmap:5 http://www.webrtc.org/experiments/rtp-hdrext/playout-delay\r\n" +
+			"a=extmap:6 http://www.webrtc.org/experiments/rtp-hdrext/video-content-type\r\n" +
+			"a=extmap:7 http://www.webrtc.org/experiments/rtp-hdrext/video-timing\r\n" +
+			"a=extmap:8 http://www.webrtc.org/experiments/rtp-hdrext/color-space\r\n" +
+			"a=ssrc:2712436124 cname:CvU1TYqkVsjj5XOt\r\n" +
+			"a=ssrc:2712436124 msid:mediaSessionLocal 100\r\n" +
+			"a=ssrc:2712436124 mslabel:mediaSessionLocal\r\n" +
+			"a=ssrc:2712436124 label:100\r\n" +
+			"a=ssrc:1733091158 cname:CvU1TYqkVsjj5XOt\r\n" +
+			"a=ssrc:1733091158 msid:mediaSessionLocal 100\r\n" +
+			"a=ssrc:1733091158 mslabel:mediaSessionLocal\r\n" +
+			"a=ssrc:1733091158 label:100\r\n" +
 			"a=rtpmap:96 VP8/90000\r\n" +
 			"a=rtpmap:97 rtx/90000\r\n" +
 			"a=fmtp:97 apt=96\r\n" +
@@ -295,19 +370,72 @@ var casesSession = []struct {
 			"a=rtpmap:99 rtx/90000\r\n" +
 			"a=fmtp:99 apt=98\r\n" +
 			"a=rtpmap:100 H264/90000\r\n" +
-			"a=fmtp:100 packetization-mode=1\r\n" +
+			"a=fmtp:100 packetization-mode=1; profile-level-id=42E01F\r\n" +
 			"a=rtpmap:101 rtx/90000\r\n" +
 			"a=fmtp:101 apt=100\r\n" +
 			"a=rtpmap:127 red/90000\r\n" +
 			"a=rtpmap:124 rtx/90000\r\n" +
-			"a=fmtp:124 apt=127\r\na=rtpmap:125 ulpfec/90000\r\n",
+			"a=fmtp:124 apt=127\r\n" +
+			"a=rtpmap:125 ulpfec/90000\r\n" +
+			"a=fingerprint:sha-256" +
+			" 5E:B5:97:8B:B4:D8:AE:2B:89:F6:82:44:47:69:77:83:05:29:C5:C8:EE:67:50:C3:77:6B:A7:BA:10:E3:08:B8\r\n" +
+			"a=ice-options:trickle renomination\r\n" +
+			"a=ice-pwd:V3YEqLGAJJhUDUa13C/pKbWe\r\n" +
+			"a=ice-ufrag:0D6Y\r\n" +
+			"a=rtcp:9 IN IP4 0.0.0.0\r\n" +
+			"a=rtcp-fb:96 goog-remb\r\n" +
+			"a=rtcp-fb:96 transport-cc\r\n" +
+			"a=rtcp-fb:96 ccm fir\r\n" +
+			"a=rtcp-fb:96 nack\r\n" +
+			"a=rtcp-fb:96 nack pli\r\n" +
+			"a=rtcp-fb:98 goog-remb\r\n" +
+			"a=rtcp-fb:98 transport-cc\r\n" +
+			"a=rtcp-fb:98 ccm fir\r\n" +
+			"a=rtcp-fb:98 nack\r\n" +
+			"a=rtcp-fb:98 nack pli\r\n" +
+			"a=rtcp-fb:100 goog-remb\r\n" +
+			"a=rtcp-fb:100 transport-cc\r\n" +
+			"a=rtcp-fb:100 ccm fir\r\n" +
+			"a=rtcp-fb:100 nack\r\n" +
+			"a=rtcp-fb:100 nack pli\r\n" +
+			"a=rtcp-mux\r\n" +
+			"a=rtcp-rsize\r\n" +
+			"a=setup:actpass\r\n" +
+			"a=ssrc-group:FID 2712436124 1733091158\r\n",
 		Session{
 			Title: ``,
+			Attributes: map[string][]string{
+				"group":         {"BUNDLE audio video"},
+				"msid-semantic": {" WMS mediaSessionLocal"},
+			},
 			Medias: []*Media{
 				{
 					ID:            "audio",
 					Type:          MediaTypeAudio,
 					IsBackChannel: true,
+					Direction:     MediaDirectionSendOnly,
+					Connection:    &MediaConnection{Address: "0.0.0.0"},
+					Extensions: []MediaExtension{
+						{ID: 1, URI: "urn:ietf:params:rtp-hdrext:ssrc-audio-level"},
+						{ID: 2, URI: "http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time"},
+						{ID: 3, URI: "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"},
+					},
+					SSRCs: []MediaSSRC{
+						{SSRC: 3754810229, Attribute: "cname", Value: "CvU1TYqkVsjj5XOt"},
+						{SSRC: 3754810229, Attribute: "msid", Value: "mediaSessionLocal 101"},
+						{SSRC: 3754810229, Attribute: "mslabel", Value: "mediaSessionLocal"},
+						{SSRC: 3754810229, Attribute: "label", Value: "101"},
+					},
+					Attributes: map[string][]string{
+						"fingerprint": {"sha-256 5E:B5:97:8B:B4:D8:AE:2B:89:F6:82:44:47:69:77:83:05:29:C5:C8:EE:67:50:C3:77:6B:A7:BA:10:E3:08:B8"},
+						"ice-options": {"trickle renomination"},
+						"ice-pwd":     {"V3YEqLGAJJhUDUa13C/pKbWe"},
+						"ice-ufrag":   {"0D6Y"},
+						"rtcp":        {"9 IN IP4 0.0.0.0"},
+						"rtcp-fb":     {"111 transport-cc"},
+						"rtcp-mux":    {""},
+						"setup":       {"actpass"},
+					},
 					Formats: []format.Format{
 						&format.Opus{
 							PayloadTyp:   111,
@@ -325,10 +453,9 @@ var casesSession = []struct {
 							ClockRat:   32000,
 						},
 						&format.G722{},
-						&format.Generic{
+						&format.ILBC{
 							PayloadTyp: 102,
-							RTPMa:      "ILBC/8000",
-							ClockRat:   8000,
+							Mode:       30,
 						},
 						&format.G711{
 							PayloadTyp:   0,
@@ -383,53 +510,91 @@ var casesSession = []struct {
 					ID:            "video",
 					Type:          MediaTypeVideo,
 					IsBackChannel: true,
+					Direction:     MediaDirectionSendOnly,
+					Connection:    &MediaConnection{Address: "0.0.0.0"},
+					Extensions: []MediaExtension{
+						{ID: 14, URI: "urn:ietf:params:rtp-hdrext:toffset"},
+						{ID: 2, URI: "http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time"},
+						{ID: 13, URI: "urn:3gpp:video-orientation"},
+						{ID: 3, URI: "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"},
+						{ID: 5, URI: "http://www.webrtc.org/experiments/rtp-hdrext/playout-delay"},
+						{ID: 6, URI: "http://www.webrtc.org/experiments/rtp-hdrext/video-content-type"},
+						{ID: 7, URI: "http://www.webrtc.org/experiments/rtp-hdrext/video-timing"},
+						{ID: 8, URI: "http://www.webrtc.org/experiments/rtp-hdrext/color-space"},
+					},
+					SSRCs: []MediaSSRC{
+						{SSRC: 2712436124, Attribute: "cname", Value: "CvU1TYqkVsjj5XOt"},
+						{SSRC: 2712436124, Attribute: "msid", Value: "mediaSessionLocal 100"},
+						{SSRC: 2712436124, Attribute: "mslabel", Value: "mediaSessionLocal"},
+						{SSRC: 2712436124, Attribute: "label", Value: "100"},
+						{SSRC: 1733091158, Attribute: "cname", Value: "CvU1TYqkVsjj5XOt"},
+						{SSRC: 1733091158, Attribute: "msid", Value: "mediaSessionLocal 100"},
+						{SSRC: 1733091158, Attribute: "mslabel", Value: "mediaSessionLocal"},
+						{SSRC: 1733091158, Attribute: "label", Value: "100"},
+					},
+					Attributes: map[string][]string{
+						"fingerprint": {"sha-256 5E:B5:97:8B:B4:D8:AE:2B:89:F6:82:44:47:69:77:83:05:29:C5:C8:EE:67:50:C3:77:6B:A7:BA:10:E3:08:B8"},
+						"ice-options": {"trickle renomination"},
+						"ice-pwd":     {"V3YEqLGAJJhUDUa13C/pKbWe"},
+						"ice-ufrag":   {"0D6Y"},
+						"rtcp":        {"9 IN IP4 0.0.0.0"},
+						"rtcp-fb": {
+							"96 goog-remb",
+							"96 transport-cc",
+							"96 ccm fir",
+							"96 nack",
+							"96 nack pli",
+							"98 goog-remb",
+							"98 transport-cc",
+							"98 ccm fir",
+							"98 nack",
+							"98 nack pli",
+							"100 goog-remb",
+							"100 transport-cc",
+							"100 ccm fir",
+							"100 nack",
+							"100 nack pli",
+						},
+						"rtcp-mux":   {""},
+						"rtcp-rsize": {""},
+						"setup":      {"actpass"},
+						"ssrc-group": {"FID 2712436124 1733091158"},
+					},
 					Formats: []format.Format{
 						&format.VP8{
 							PayloadTyp: 96,
 						},
-						&format.Generic{
-							PayloadTyp: 97,
-							RTPMa:      "rtx/90000",
-							FMT: map[string]string{
-								"apt": "96",
-							},
-							ClockRat: 90000,
+						&format.RTX{
+							PayloadTyp:            97,
+							ClockRat:              90000,
+							AssociatedPayloadType: 96,
 						},
 						&format.VP9{
 							PayloadTyp: 98,
 						},
-						&format.Generic{
-							PayloadTyp: 99,
-							RTPMa:      "rtx/90000",
-							FMT: map[string]string{
-								"apt": "98",
-							},
-							ClockRat: 90000,
+						&format.RTX{
+							PayloadTyp:            99,
+							ClockRat:              90000,
+							AssociatedPayloadType: 98,
 						},
 						&format.H264{
 							PayloadTyp:        100,
 							PacketizationMode: 1,
+							ProfileLevelID:    []byte{0x42, 0xe0, 0x1f},
 						},
-						&format.Generic{
-							PayloadTyp: 101,
-							RTPMa:      "rtx/90000",
-							FMT: map[string]string{
-								"apt": "100",
-							},
-							ClockRat: 90000,
+						&format.RTX{
+							PayloadTyp:            101,
+							ClockRat:              90000,
+							AssociatedPayloadType: 100,
 						},
-						&format.Generic{
+						&format.RED{
 							PayloadTyp: 127,
-							RTPMa:      "red/90000",
 							ClockRat:   90000,
 						},
-						&format.Generic{
-							PayloadTyp: 124,
-							RTPMa:      "rtx/90000",
-							FMT: map[string]string{
-								"apt": "127",
-							},
-							ClockRat: 90000,
+						&format.RTX{
+							PayloadTyp:            124,
+							ClockRat:              90000,
+							AssociatedPayloadType: 127,
 						},
 						&format.Generic{
 							PayloadTyp: 125,
@@ -463,12 +628,16 @@ var casesSession = []struct {
 			"a=rtpmap:96 H264/90000\r\n" +
 			"a=fmtp:96 packetization-mode=1; profile-level-id=4D002A; " +
 			"sprop-parameter-sets=Z00AKp2oHgCJ+WbgICAgQA==,aO48gA==\r\n" +
-			"a=rtpmap:98 MetaData\r\n",
+			"a=rtpmap:98 MetaData\r\n" +
+			"a=rtcp-mux\r\n",
 		Session{
 			Title: `-`,
 			Medias: []*Media{
 				{
 					Type: MediaTypeVideo,
+					Attributes: map[string][]string{
+						"rtcp-mux": {""},
+					},
 					Formats: []format.Format{
 						&format.H264{
 							PayloadTyp: 96,
@@ -478,6 +647,7 @@ var casesSession = []struct {
 							},
 							PPS:               []byte{0x68, 0xee, 0x3c, 0x80},
 							PacketizationMode: 1,
+							ProfileLevelID:    []byte{0x4d, 0x00, 0x2a},
 						},
 						&format.Generic{
 							PayloadTyp: 98,
@@ -509,9 +679,11 @@ var casesSession = []struct {
 			"c=IN IP4 0.0.0.0\r\n" +
 			"t=0 0\r\n" +
 			"m=video 0 RTP/AVP 26\r\n" +
+			"a=recvonly\r\n" +
 			"a=control:rtsp://192.168.0.1/video\r\n" +
 			"a=rtpmap:26 JPEG/90000\r\n" +
 			"m=audio 0 RTP/AVP 0\r\n" +
+			"a=recvonly\r\n" +
 			"a=control:rtsp://192.168.0.1/audio\r\n" +
 			"a=rtpmap:0 PCMU/8000\r\n" +
 			"m=audio 0 RTP/AVP 0\r\n" +
@@ -522,13 +694,15 @@ var casesSession = []struct {
 			Title: `RTSP Session with audiobackchannel`,
 			Medias: []*Media{
 				{
-					Type:    MediaTypeVideo,
-					Control: "rtsp://192.168.0.1/video",
-					Formats: []format.Format{&format.MJPEG{}},
+					Type:      MediaTypeVideo,
+					Direction: MediaDirectionRecvOnly,
+					Control:   "rtsp://192.168.0.1/video",
+					Formats:   []format.Format{&format.MJPEG{}},
 				},
 				{
-					Type:    MediaTypeAudio,
-					Control: "rtsp://192.168.0.1/audio",
+					Type:      MediaTypeAudio,
+					Direction: MediaDirectionRecvOnly,
+					Control:   "rtsp://192.168.0.1/audio",
 					Formats: []format.Format{&format.G711{
 						PayloadTyp:   0,
 						MULaw:        true,
@@ -539,6 +713,7 @@ var casesSession = []struct {
 				{
 					Type:          MediaTypeAudio,
 					IsBackChannel: true,
+					Direction:     MediaDirectionSendOnly,
 					Control:       "rtsp://192.168.0.1/audioback",
 					Formats: []format.Format{&format.G711{
 						PayloadTyp:   0,
@@ -629,6 +804,10 @@ var casesSession = []struct {
 				{
 					ID:   "4",
 					Type: MediaTypeApplication,
+					Connection: &MediaConnection{
+						Address: "224.2.17.13",
+						TTL:     intPtr(127),
+					},
 					Formats: []format.Format{&format.Generic{
 						PayloadTyp: 101,
 						RTPMa:      "ulpfec/8000",
@@ -638,6 +817,331 @@ var casesSession = []struct {
 			},
 		},
 	},
+	{
+		"sdes crypto",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/SAVP 96\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n" +
+			"a=crypto:1 AES_CM_128_HMAC_SHA1_80 inline:WVNfX19zZW1jdGwgKCkgewkyMjA7fQp9CnVubGVz|2^20|1:4 " +
+			"UNENCRYPTED_SRTCP\r\n" +
+			"a=crypto:2 AES_CM_128_HMAC_SHA1_32 inline:NzB4d1BINUAvLEw6UzF3WSJ+PSdFcGdUJShpX1Zj\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n",
+		Session{
+			Title: "Stream",
+			Medias: []*Media{
+				{
+					Type:    MediaTypeVideo,
+					Control: "trackID=0",
+					Crypto: []MediaCrypto{
+						{
+							Tag:   1,
+							Suite: "AES_CM_128_HMAC_SHA1_80",
+							Keys: []MediaCryptoKey{{
+								Method: "inline",
+								KeySalt: []byte{
+									0x59, 0x53, 0x5f, 0x5f, 0x5f, 0x73, 0x65, 0x6d,
+									0x63, 0x74, 0x6c, 0x20, 0x28, 0x29, 0x20, 0x7b,
+									0x09, 0x32, 0x32, 0x30, 0x3b, 0x7d, 0x0a, 0x7d,
+									0x0a, 0x75, 0x6e, 0x6c, 0x65, 0x73,
+								},
+								Lifetime:  "2^20",
+								MKIValue:  1,
+								MKILength: 4,
+							}},
+							SessionParams: []string{"UNENCRYPTED_SRTCP"},
+						},
+						{
+							Tag:   2,
+							Suite: "AES_CM_128_HMAC_SHA1_32",
+							Keys: []MediaCryptoKey{{
+								Method: "inline",
+								KeySalt: []byte{
+									0x37, 0x30, 0x78, 0x77, 0x50, 0x48, 0x35, 0x40,
+									0x2f, 0x2c, 0x4c, 0x3a, 0x53, 0x31, 0x77, 0x59,
+									0x22, 0x7e, 0x3d, 0x27, 0x45, 0x70, 0x67, 0x54,
+									0x25, 0x28, 0x69, 0x5f, 0x56, 0x63,
+								},
+							}},
+						},
+					},
+					Formats: []format.Format{&format.H264{
+						PayloadTyp:        96,
+						PacketizationMode: 1,
+					}},
+				},
+			},
+		},
+	},
+	{
+		"redundant encodings",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 0 RTP/AVP 120 111\r\n" +
+			"a=rtpmap:120 red/48000/2\r\n" +
+			"a=fmtp:120 111/111\r\n" +
+			"a=rtpmap:111 opus/48000/2\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 0 RTP/AVP 120 111\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:120 red/48000/2\r\n" +
+			"a=fmtp:120 111/111\r\n" +
+			"a=rtpmap:111 opus/48000/2\r\n" +
+			"a=fmtp:111 sprop-stereo=0\r\n",
+		Session{
+			Title: "Stream",
+			Medias: []*Media{
+				{
+					Type: MediaTypeAudio,
+					Formats: []format.Format{
+						&format.RED{
+							PayloadTyp:           120,
+							ClockRat:             48000,
+							ChannelCount:         2,
+							EncodingPayloadTypes: []uint8{111, 111},
+						},
+						&format.Opus{
+							PayloadTyp:   111,
+							ChannelCount: 1,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"label and ssrc",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=label:camera1-0\r\n" +
+			"a=ssrc:123456 cname:camera1\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=label:camera1-0\r\n" +
+			"a=ssrc:123456 cname:camera1\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n",
+		Session{
+			Title: "Stream",
+			Medias: []*Media{
+				{
+					Type:    MediaTypeVideo,
+					Control: "trackID=0",
+					Label:   "camera1-0",
+					SSRCs: []MediaSSRC{
+						{SSRC: 123456, Attribute: "cname", Value: "camera1"},
+					},
+					Formats: []format.Format{
+						&format.H264{
+							PayloadTyp:        96,
+							PacketizationMode: 1,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"framerate and bandwidth",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"b=AS:4000\r\n" +
+			"b=TIAS:3800000\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=framerate:29.97\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"b=AS:4000\r\n" +
+			"b=TIAS:3800000\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n" +
+			"a=framerate:29.97\r\n",
+		Session{
+			Title: "Stream",
+			Medias: []*Media{
+				{
+					Type:          MediaTypeVideo,
+					Control:       "trackID=0",
+					FrameRate:     29.97,
+					BandwidthAS:   4000,
+					BandwidthTIAS: 3800000,
+					Formats: []format.Format{
+						&format.H264{
+							PayloadTyp:        96,
+							PacketizationMode: 1,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"media directions",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"a=inactive\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n" +
+			"m=audio 0 RTP/AVP 0\r\n" +
+			"a=sendrecv\r\n" +
+			"a=control:trackID=1\r\n" +
+			"a=rtpmap:0 PCMU/8000\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"a=inactive\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n" +
+			"m=audio 0 RTP/AVP 0\r\n" +
+			"a=sendrecv\r\n" +
+			"a=control:trackID=1\r\n" +
+			"a=rtpmap:0 PCMU/8000\r\n",
+		Session{
+			Title: "Stream",
+			Medias: []*Media{
+				{
+					Type:      MediaTypeVideo,
+					Direction: MediaDirectionInactive,
+					Control:   "trackID=0",
+					Formats: []format.Format{
+						&format.H264{
+							PayloadTyp:        96,
+							PacketizationMode: 1,
+						},
+					},
+				},
+				{
+					Type:      MediaTypeAudio,
+					Direction: MediaDirectionSendRecv,
+					Control:   "trackID=1",
+					Formats: []format.Format{&format.G711{
+						PayloadTyp:   0,
+						MULaw:        true,
+						SampleRate:   8000,
+						ChannelCount: 1,
+					}},
+				},
+			},
+		},
+	},
+	{
+		"titles and labels",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"i=Front door\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=label:front\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"i=Back door\r\n" +
+			"a=control:trackID=1\r\n" +
+			"a=label:back\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"i=Front door\r\n" +
+			"a=control:trackID=0\r\n" +
+			"a=label:front\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"i=Back door\r\n" +
+			"a=control:trackID=1\r\n" +
+			"a=label:back\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
+			"a=fmtp:96 packetization-mode=1\r\n",
+		Session{
+			Title: "Stream",
+			Medias: []*Media{
+				{
+					Type:    MediaTypeVideo,
+					Control: "trackID=0",
+					Title:   "Front door",
+					Label:   "front",
+					Formats: []format.Format{
+						&format.H264{
+							PayloadTyp:        96,
+							PacketizationMode: 1,
+						},
+					},
+				},
+				{
+					Type:    MediaTypeVideo,
+					Control: "trackID=1",
+					Title:   "Back door",
+					Label:   "back",
+					Formats: []format.Format{
+						&format.H264{
+							PayloadTyp:        96,
+							PacketizationMode: 1,
+						},
+					},
+				},
+			},
+		},
+	},
 }
 
 func TestSessionUnmarshal(t *testing.T) {
@@ -665,6 +1169,236 @@ func TestSessionMarshal(t *testing.T) {
 	}
 }
 
+func TestSessionMarshalOrigin(t *testing.T) {
+	desc := Session{
+		Title: "Recording",
+		Origin: &SessionOrigin{
+			Username:       "recorder",
+			SessionID:      1234,
+			SessionVersion: 2,
+			Address:        "2001:db8::1",
+		},
+		Information:       "camera 1",
+		Tool:              "gortsplib",
+		ConnectionAddress: "192.168.1.10",
+		Medias: []*Media{
+			{
+				Type: MediaTypeVideo,
+				Formats: []format.Format{&format.H264{
+					PayloadTyp:        96,
+					PacketizationMode: 1,
+				}},
+			},
+		},
+	}
+
+	byts, err := desc.Marshal(false)
+	require.NoError(t, err)
+	require.Equal(t, "v=0\r\n"+
+		"o=recorder 1234 2 IN IP6 2001:db8::1\r\n"+
+		"s=Recording\r\n"+
+		"i=camera 1\r\n"+
+		"c=IN IP4 192.168.1.10\r\n"+
+		"t=0 0\r\n"+
+		"a=tool:gortsplib\r\n"+
+		"m=video 0 RTP/AVP 96\r\n"+
+		"a=control\r\n"+
+		"a=rtpmap:96 H264/90000\r\n"+
+		"a=fmtp:96 packetization-mode=1\r\n", string(byts))
+
+	byts, err = desc.Marshal(true)
+	require.NoError(t, err)
+	require.Contains(t, string(byts), "c=IN IP4 224.1.0.0\r\n")
+}
+
+func TestSessionUnmarshalLenient(t *testing.T) {
+	var sd sdp.SessionDescription
+	err := sd.Unmarshal([]byte("v=0\r\n" +
+		"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+		"s=Stream\r\n" +
+		"c=IN IP4 0.0.0.0\r\n" +
+		"t=0 0\r\n" +
+		"m=video 0 RTP/AVP 96 96\r\n" +
+		"a=rtpmap:96 H264/90000\r\n" +
+		"a=fmtp:96 packetization-mode=a\r\n" +
+		"a=control:trackID=0\r\n" +
+		"m=video 0 RTP/AVP 97\r\n" +
+		"a=control:trackID=1\r\n" +
+		"m=audio 0 RTP/AVP 0\r\n" +
+		"a=ssrc:abc\r\n" +
+		"a=control:trackID=2\r\n"))
+	require.NoError(t, err)
+
+	var desc Session
+	err = desc.Unmarshal(&sd)
+	require.EqualError(t, err, "media 1 is invalid: invalid packetization-mode (a)")
+
+	warnings, err := desc.UnmarshalLenient(&sd)
+	require.NoError(t, err)
+
+	require.Equal(t, []Warning{
+		{
+			Media:       1,
+			Description: "payload type 96 is invalid, decoded as generic format: invalid packetization-mode (a)",
+		},
+		{
+			Media:       1,
+			Description: "duplicate payload type 96",
+		},
+		{
+			Media:       2,
+			Description: "payload type 97 has no rtpmap",
+		},
+		{
+			Media:       2,
+			Description: "media skipped: no formats found",
+		},
+		{
+			Media:       3,
+			Description: "ssrc attribute skipped: invalid ssrc attribute: abc",
+		},
+	}, warnings)
+
+	require.Equal(t, []*Media{
+		{
+			Type:    MediaTypeVideo,
+			Control: "trackID=0",
+			Formats: []format.Format{&format.Generic{
+				PayloadTyp: 96,
+				RTPMa:      "H264/90000",
+				FMT: map[string]string{
+					"packetization-mode": "a",
+				},
+				ClockRat: 90000,
+			}},
+		},
+		{
+			Type:    MediaTypeAudio,
+			Control: "trackID=2",
+			Formats: []format.Format{&format.G711{
+				PayloadTyp:   0,
+				MULaw:        true,
+				SampleRate:   8000,
+				ChannelCount: 1,
+			}},
+		},
+	}, desc.Medias)
+
+	require.Equal(t, "media 2: payload type 97 has no rtpmap", warnings[2].String())
+}
+
+func TestSessionUnmarshalInvalidCrypto(t *testing.T) {
+	var sd sdp.SessionDescription
+	err := sd.Unmarshal([]byte("v=0\r\n" +
+		"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+		"s=Stream\r\n" +
+		"c=IN IP4 0.0.0.0\r\n" +
+		"t=0 0\r\n" +
+		"m=video 0 RTP/SAVP 96\r\n" +
+		"a=rtpmap:96 H264/90000\r\n" +
+		"a=control:trackID=0\r\n" +
+		"a=crypto:1 AES_CM_128_HMAC_SHA1_80 uri:http://example.com/key\r\n"))
+	require.NoError(t, err)
+
+	var desc Session
+	err = desc.Unmarshal(&sd)
+	require.NoError(t, err)
+	require.Nil(t, desc.Medias[0].Crypto)
+
+	warnings, err := desc.UnmarshalLenient(&sd)
+	require.NoError(t, err)
+	require.Equal(t, []Warning{{
+		Media:       1,
+		Description: "crypto attribute skipped: unsupported key method: uri",
+	}}, warnings)
+}
+
+func TestSessionUnmarshalLenientError(t *testing.T) {
+	var sd sdp.SessionDescription
+	err := sd.Unmarshal([]byte("v=0\r\n" +
+		"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+		"s=Stream\r\n" +
+		"c=IN IP4 0.0.0.0\r\n" +
+		"t=0 0\r\n" +
+		"m=video 0 RTP/AVP 97\r\n" +
+		"a=control:trackID=1\r\n"))
+	require.NoError(t, err)
+
+	var desc Session
+	_, err = desc.UnmarshalLenient(&sd)
+	require.EqualError(t, err, "no valid medias found")
+}
+
+func TestSessionRange(t *testing.T) {
+	durationPtr := func(v time.Duration) *time.Duration {
+		return &v
+	}
+
+	for _, ca := range []struct {
+		name     string
+		v        string
+		r        SessionRange
+		live     bool
+		duration time.Duration
+	}{
+		{
+			"live",
+			"npt=now-",
+			SessionRange{},
+			true,
+			0,
+		},
+		{
+			"npt without end",
+			"npt=0-",
+			SessionRange{
+				Value: &headers.RangeNPT{},
+			},
+			true,
+			0,
+		},
+		{
+			"npt recording",
+			"npt=0-34.5",
+			SessionRange{
+				Value: &headers.RangeNPT{
+					End: durationPtr(34500 * time.Millisecond),
+				},
+			},
+			false,
+			34500 * time.Millisecond,
+		},
+		{
+			"clock recording",
+			"clock=20230101T100000Z-20230101T110000Z",
+			SessionRange{
+				Value: &headers.RangeUTC{
+					Start: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC),
+					End: func() *time.Time {
+						v := time.Date(2023, 1, 1, 11, 0, 0, 0, time.UTC)
+						return &v
+					}(),
+				},
+			},
+			false,
+			time.Hour,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var r SessionRange
+			err := r.Unmarshal(ca.v)
+			require.NoError(t, err)
+			require.Equal(t, ca.r, r)
+			require.Equal(t, ca.v, r.Marshal())
+			require.Equal(t, ca.live, r.IsLive())
+
+			duration, ok := r.Duration()
+			require.Equal(t, !ca.live, ok)
+			require.Equal(t, ca.duration, duration)
+		})
+	}
+}
+
 func TestSessionFindFormat(t *testing.T) {
 	tr := &format.Generic{
 		PayloadTyp: 97,
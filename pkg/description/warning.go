@@ -0,0 +1,22 @@
+package description
+
+import (
+	"strconv"
+)
+
+// Warning is a non-fatal problem found by Session.UnmarshalLenient.
+type Warning struct {
+	// index of the media, starting from 1.
+	// It is zero when the problem concerns the session.
+	Media int
+	// description of the problem.
+	Description string
+}
+
+// String implements fmt.Stringer.
+func (w Warning) String() string {
+	if w.Media != 0 {
+		return "media " + strconv.FormatInt(int64(w.Media), 10) + ": " + w.Description
+	}
+	return w.Description
+}
@@ -0,0 +1,103 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpfragmentedaudio"
+)
+
+// AC4 is the RTP format for the AC-4 codec.
+// Each frame is carried by one or more packets, the last of which has the marker bit set.
+// Specification: ETSI TS 103 190-2
+type AC4 struct {
+	PayloadTyp   uint8
+	SampleRate   int
+	ChannelCount int
+}
+
+func (f *AC4) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	tmp := strings.SplitN(ctx.clock, "/", 2)
+
+	tmp1, err := strconv.ParseUint(tmp[0], 10, 31)
+	if err != nil || tmp1 == 0 {
+		return fmt.Errorf("invalid sample rate: '%s'", tmp[0])
+	}
+	f.SampleRate = int(tmp1)
+
+	if len(tmp) >= 2 {
+		tmp1, err := strconv.ParseUint(tmp[1], 10, 31)
+		if err != nil || tmp1 == 0 {
+			return fmt.Errorf("invalid channel count: '%s'", tmp[1])
+		}
+		f.ChannelCount = int(tmp1)
+	} else {
+		// RFC8866: if the number of channels is omitted,
+		// a single channel is implied.
+		f.ChannelCount = 1
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *AC4) Codec() string {
+	return "AC-4"
+}
+
+// ClockRate implements Format.
+func (f *AC4) ClockRate() int {
+	return f.SampleRate
+}
+
+// PayloadType implements Format.
+func (f *AC4) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *AC4) RTPMap() string {
+	return "AC4/" + strconv.FormatInt(int64(f.SampleRate), 10) +
+		"/" + strconv.FormatInt(int64(f.ChannelCount), 10)
+}
+
+// FMTP implements Format.
+func (f *AC4) FMTP() map[string]string {
+	return nil
+}
+
+// PTSEqualsDTS implements Format.
+func (f *AC4) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *AC4) CreateDecoder() (*rtpfragmentedaudio.Decoder, error) {
+	d := &rtpfragmentedaudio.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *AC4) CreateEncoder() (*rtpfragmentedaudio.Encoder, error) {
+	e := &rtpfragmentedaudio.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
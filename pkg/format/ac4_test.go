@@ -0,0 +1,53 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpfragmentedaudio"
+)
+
+func TestAC4Attributes(t *testing.T) {
+	format := &AC4{
+		PayloadTyp:   96,
+		SampleRate:   48000,
+		ChannelCount: 6,
+	}
+	require.Equal(t, "AC-4", format.Codec())
+	require.Equal(t, 48000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestAC4DecEncoder(t *testing.T) {
+	format := &AC4{
+		PayloadTyp:   96,
+		SampleRate:   48000,
+		ChannelCount: 6,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode(bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 512))
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	var byts []byte
+
+	for _, pkt := range pkts {
+		byts, err = dec.Decode(pkt)
+		if errors.Is(err, rtpfragmentedaudio.ErrMorePacketsNeeded) {
+			continue
+		}
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 512), byts)
+}
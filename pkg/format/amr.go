@@ -0,0 +1,244 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpamr"
+)
+
+func amrUnmarshal(f *AMR, ctx *unmarshalContext, maxMode int) error {
+	f.PayloadTyp = ctx.payloadType
+
+	tmp := strings.SplitN(ctx.clock, "/", 2)
+
+	if len(tmp) >= 2 {
+		tmp1, err := strconv.ParseUint(tmp[1], 10, 31)
+		if err != nil || tmp1 == 0 {
+			return fmt.Errorf("invalid channel count: '%s'", tmp[1])
+		}
+		f.ChannelCount = int(tmp1)
+	} else {
+		f.ChannelCount = 1
+	}
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "octet-align":
+			f.OctetAlign = (val == "1")
+
+		case "mode-set":
+			for _, mode := range strings.Split(val, ",") {
+				tmp, err := strconv.ParseUint(strings.TrimSpace(mode), 10, 31)
+				if err != nil || int(tmp) > maxMode {
+					return fmt.Errorf("invalid mode-set: %v", val)
+				}
+				f.ModeSet = append(f.ModeSet, int(tmp))
+			}
+
+		case "mode-change-period":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid mode-change-period: %v", val)
+			}
+			f.ModeChangePeriod = int(tmp)
+
+		case "mode-change-neighbor":
+			f.ModeChangeNeighbor = (val == "1")
+
+		case "crc":
+			f.CRC = (val == "1")
+
+		case "robust-sorting":
+			f.RobustSorting = (val == "1")
+
+		case "interleaving":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid interleaving: %v", val)
+			}
+			f.Interleaving = int(tmp)
+		}
+	}
+
+	f.OctetAlign = amrOctetAligned(f)
+
+	return nil
+}
+
+// RFC4867: crc, robust-sorting and interleaving imply the octet-aligned mode.
+func amrOctetAligned(f *AMR) bool {
+	return f.OctetAlign || f.CRC || f.RobustSorting || f.Interleaving != 0
+}
+
+func amrRTPMap(f *AMR, codec string, sampleRate int) string {
+	ret := codec + "/" + strconv.FormatInt(int64(sampleRate), 10)
+
+	if f.ChannelCount > 1 {
+		ret += "/" + strconv.FormatInt(int64(f.ChannelCount), 10)
+	}
+
+	return ret
+}
+
+func amrFMTP(f *AMR) map[string]string {
+	fmtp := make(map[string]string)
+
+	if amrOctetAligned(f) {
+		fmtp["octet-align"] = "1"
+	}
+
+	if f.ModeSet != nil {
+		tmp := make([]string, len(f.ModeSet))
+		for i, mode := range f.ModeSet {
+			tmp[i] = strconv.FormatInt(int64(mode), 10)
+		}
+		fmtp["mode-set"] = strings.Join(tmp, ",")
+	}
+
+	if f.ModeChangePeriod != 0 {
+		fmtp["mode-change-period"] = strconv.FormatInt(int64(f.ModeChangePeriod), 10)
+	}
+
+	if f.ModeChangeNeighbor {
+		fmtp["mode-change-neighbor"] = "1"
+	}
+
+	if f.CRC {
+		fmtp["crc"] = "1"
+	}
+
+	if f.RobustSorting {
+		fmtp["robust-sorting"] = "1"
+	}
+
+	if f.Interleaving != 0 {
+		fmtp["interleaving"] = strconv.FormatInt(int64(f.Interleaving), 10)
+	}
+
+	return fmtp
+}
+
+func amrCreateDecoder(f *AMR, wideBand bool) (*rtpamr.Decoder, error) {
+	if f.RobustSorting {
+		return nil, fmt.Errorf("robust sorting is not supported (yet)")
+	}
+
+	d := &rtpamr.Decoder{
+		WideBand:     wideBand,
+		OctetAligned: amrOctetAligned(f),
+		ChannelCount: f.ChannelCount,
+		CRC:          f.CRC,
+		Interleaving: f.Interleaving != 0,
+	}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func amrCreateEncoder(f *AMR, wideBand bool) (*rtpamr.Encoder, error) {
+	if f.CRC {
+		return nil, fmt.Errorf("CRC is not supported (yet)")
+	}
+
+	if f.RobustSorting {
+		return nil, fmt.Errorf("robust sorting is not supported (yet)")
+	}
+
+	e := &rtpamr.Encoder{
+		PayloadType:  f.PayloadTyp,
+		WideBand:     wideBand,
+		OctetAligned: amrOctetAligned(f),
+		ChannelCount: f.ChannelCount,
+		Interleaving: f.Interleaving != 0,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// AMR is the RTP format for the AMR codec.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4867
+type AMR struct {
+	PayloadTyp   uint8
+	ChannelCount int
+
+	// whether the octet-aligned mode is in use.
+	// Otherwise, the bandwidth-efficient mode is in use.
+	// It is implied by CRC, RobustSorting and Interleaving.
+	OctetAlign bool
+
+	// modes that can be used (optional).
+	ModeSet []int
+
+	// period of mode changes, in frame-blocks (optional).
+	ModeChangePeriod int
+
+	// whether mode changes are restricted to neighboring modes.
+	ModeChangeNeighbor bool
+
+	// whether frames are protected by CRCs.
+	CRC bool
+
+	// whether robust sorting is in use.
+	RobustSorting bool
+
+	// maximum number of frame-blocks of an interleaving group (optional).
+	// When it is not zero, interleaving is in use.
+	Interleaving int
+}
+
+func (f *AMR) unmarshal(ctx *unmarshalContext) error {
+	return amrUnmarshal(f, ctx, 7)
+}
+
+// Codec implements Format.
+func (f *AMR) Codec() string {
+	return "AMR"
+}
+
+// ClockRate implements Format.
+func (f *AMR) ClockRate() int {
+	return 8000
+}
+
+// PayloadType implements Format.
+func (f *AMR) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *AMR) RTPMap() string {
+	return amrRTPMap(f, "AMR", 8000)
+}
+
+// FMTP implements Format.
+func (f *AMR) FMTP() map[string]string {
+	return amrFMTP(f)
+}
+
+// PTSEqualsDTS implements Format.
+func (f *AMR) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *AMR) CreateDecoder() (*rtpamr.Decoder, error) {
+	return amrCreateDecoder(f, false)
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *AMR) CreateEncoder() (*rtpamr.Encoder, error) {
+	return amrCreateEncoder(f, false)
+}
@@ -0,0 +1,46 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAMRAttributes(t *testing.T) {
+	format := &AMR{
+		PayloadTyp:   96,
+		ChannelCount: 1,
+	}
+	require.Equal(t, "AMR", format.Codec())
+	require.Equal(t, 8000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestAMRDecEncoder(t *testing.T) {
+	for _, octetAlign := range []bool{false, true} {
+		format := &AMR{
+			PayloadTyp:   96,
+			ChannelCount: 1,
+			OctetAlign:   octetAlign,
+		}
+
+		frame := append([]byte{0x3c}, bytes.Repeat([]byte{0x01}, 31)...)
+		frame[31] = 0x10
+
+		enc, err := format.CreateEncoder()
+		require.NoError(t, err)
+
+		pkts, err := enc.Encode([][]byte{frame})
+		require.NoError(t, err)
+		require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+		dec, err := format.CreateDecoder()
+		require.NoError(t, err)
+
+		frames, err := dec.Decode(pkts[0])
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{frame}, frames)
+	}
+}
@@ -0,0 +1,82 @@
+package format
+
+import (
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpamr"
+)
+
+// AMRWB is the RTP format for the AMR-WB codec.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4867
+type AMRWB struct {
+	PayloadTyp   uint8
+	ChannelCount int
+
+	// whether the octet-aligned mode is in use.
+	// Otherwise, the bandwidth-efficient mode is in use.
+	// It is implied by CRC, RobustSorting and Interleaving.
+	OctetAlign bool
+
+	// modes that can be used (optional).
+	ModeSet []int
+
+	// period of mode changes, in frame-blocks (optional).
+	ModeChangePeriod int
+
+	// whether mode changes are restricted to neighboring modes.
+	ModeChangeNeighbor bool
+
+	// whether frames are protected by CRCs.
+	CRC bool
+
+	// whether robust sorting is in use.
+	RobustSorting bool
+
+	// maximum number of frame-blocks of an interleaving group (optional).
+	// When it is not zero, interleaving is in use.
+	Interleaving int
+}
+
+func (f *AMRWB) unmarshal(ctx *unmarshalContext) error {
+	return amrUnmarshal((*AMR)(f), ctx, 8)
+}
+
+// Codec implements Format.
+func (f *AMRWB) Codec() string {
+	return "AMR-WB"
+}
+
+// ClockRate implements Format.
+func (f *AMRWB) ClockRate() int {
+	return 16000
+}
+
+// PayloadType implements Format.
+func (f *AMRWB) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *AMRWB) RTPMap() string {
+	return amrRTPMap((*AMR)(f), "AMR-WB", 16000)
+}
+
+// FMTP implements Format.
+func (f *AMRWB) FMTP() map[string]string {
+	return amrFMTP((*AMR)(f))
+}
+
+// PTSEqualsDTS implements Format.
+func (f *AMRWB) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *AMRWB) CreateDecoder() (*rtpamr.Decoder, error) {
+	return amrCreateDecoder((*AMR)(f), true)
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *AMRWB) CreateEncoder() (*rtpamr.Encoder, error) {
+	return amrCreateEncoder((*AMR)(f), true)
+}
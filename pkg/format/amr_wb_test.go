@@ -0,0 +1,44 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAMRWBAttributes(t *testing.T) {
+	format := &AMRWB{
+		PayloadTyp:   96,
+		ChannelCount: 1,
+	}
+	require.Equal(t, "AMR-WB", format.Codec())
+	require.Equal(t, 16000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestAMRWBDecEncoder(t *testing.T) {
+	format := &AMRWB{
+		PayloadTyp:   96,
+		ChannelCount: 1,
+		Interleaving: 2,
+	}
+
+	frame := append([]byte{0x14}, bytes.Repeat([]byte{0x01}, 32)...)
+	frame[32] = 0x80
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode([][]byte{frame})
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	frames, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{frame}, frames)
+}
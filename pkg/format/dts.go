@@ -0,0 +1,113 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpfragmentedaudio"
+)
+
+// DTS is the RTP format for the DTS and DTS-HD codecs.
+// Each frame is carried by one or more packets, the last of which has the marker bit set.
+// Specification: ETSI TS 102 114
+type DTS struct {
+	PayloadTyp   uint8
+	HD           bool
+	SampleRate   int
+	ChannelCount int
+}
+
+func (f *DTS) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+	f.HD = (ctx.codec == "vnd.dts.hd")
+
+	tmp := strings.SplitN(ctx.clock, "/", 2)
+
+	tmp1, err := strconv.ParseUint(tmp[0], 10, 31)
+	if err != nil || tmp1 == 0 {
+		return fmt.Errorf("invalid sample rate: '%s'", tmp[0])
+	}
+	f.SampleRate = int(tmp1)
+
+	if len(tmp) >= 2 {
+		tmp1, err := strconv.ParseUint(tmp[1], 10, 31)
+		if err != nil || tmp1 == 0 {
+			return fmt.Errorf("invalid channel count: '%s'", tmp[1])
+		}
+		f.ChannelCount = int(tmp1)
+	} else {
+		// RFC8866: if the number of channels is omitted,
+		// a single channel is implied.
+		f.ChannelCount = 1
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *DTS) Codec() string {
+	if f.HD {
+		return "DTS-HD"
+	}
+	return "DTS"
+}
+
+// ClockRate implements Format.
+func (f *DTS) ClockRate() int {
+	return f.SampleRate
+}
+
+// PayloadType implements Format.
+func (f *DTS) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *DTS) RTPMap() string {
+	codec := "vnd.dts"
+	if f.HD {
+		codec = "vnd.dts.hd"
+	}
+
+	return codec + "/" + strconv.FormatInt(int64(f.SampleRate), 10) +
+		"/" + strconv.FormatInt(int64(f.ChannelCount), 10)
+}
+
+// FMTP implements Format.
+func (f *DTS) FMTP() map[string]string {
+	return nil
+}
+
+// PTSEqualsDTS implements Format.
+func (f *DTS) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *DTS) CreateDecoder() (*rtpfragmentedaudio.Decoder, error) {
+	d := &rtpfragmentedaudio.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *DTS) CreateEncoder() (*rtpfragmentedaudio.Encoder, error) {
+	e := &rtpfragmentedaudio.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,256 @@
+package format
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpevc"
+)
+
+// EVC NALU types, as they appear in the NALU header (nal_unit_type_plus1).
+const (
+	evcNALUTypeIDR               = 2
+	evcNALUTypeSPS               = 25
+	evcNALUTypePPS               = 26
+	evcNALUTypeAggregationUnit   = 56
+	evcNALUTypeFragmentationUnit = 57
+)
+
+func evcIsRandomAccessNALUType(typ uint8) bool {
+	switch typ {
+	case evcNALUTypeIDR, evcNALUTypeSPS, evcNALUTypePPS:
+		return true
+	}
+	return false
+}
+
+// EVC is the RTP format for the MPEG-5 Essential Video Coding (EVC) codec.
+// Specification: https://datatracker.ietf.org/doc/html/rfc9584
+type EVC struct {
+	PayloadTyp uint8
+	ProfileID  *int
+	LevelID    *int
+	ToolsetID  *int
+	SPS        []byte
+	PPS        []byte
+	MaxDONDiff int
+
+	mutex sync.RWMutex
+}
+
+func evcParseFMTPInt(key string, val string) (*int, error) {
+	n, err := strconv.ParseUint(val, 10, 31)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", key, val)
+	}
+
+	v2 := int(n)
+	return &v2, nil
+}
+
+func (f *EVC) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "profile-id":
+			var err error
+			f.ProfileID, err = evcParseFMTPInt(key, val)
+			if err != nil {
+				return err
+			}
+
+		case "level-id":
+			var err error
+			f.LevelID, err = evcParseFMTPInt(key, val)
+			if err != nil {
+				return err
+			}
+
+		case "toolset-id":
+			var err error
+			f.ToolsetID, err = evcParseFMTPInt(key, val)
+			if err != nil {
+				return err
+			}
+
+		case "sprop-sps":
+			var err error
+			f.SPS, err = base64.StdEncoding.DecodeString(val)
+			if err != nil {
+				return fmt.Errorf("invalid sprop-sps (%v)", ctx.fmtp)
+			}
+
+		case "sprop-pps":
+			var err error
+			f.PPS, err = base64.StdEncoding.DecodeString(val)
+			if err != nil {
+				return fmt.Errorf("invalid sprop-pps (%v)", ctx.fmtp)
+			}
+
+		case "sprop-max-don-diff":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid sprop-max-don-diff (%v)", ctx.fmtp)
+			}
+			f.MaxDONDiff = int(tmp)
+		}
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *EVC) Codec() string {
+	return "EVC"
+}
+
+// ClockRate implements Format.
+func (f *EVC) ClockRate() int {
+	return 90000
+}
+
+// PayloadType implements Format.
+func (f *EVC) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *EVC) RTPMap() string {
+	return "EVC/90000"
+}
+
+// FMTP implements Format.
+func (f *EVC) FMTP() map[string]string {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	fmtp := make(map[string]string)
+	if f.ProfileID != nil {
+		fmtp["profile-id"] = strconv.FormatInt(int64(*f.ProfileID), 10)
+	}
+	if f.LevelID != nil {
+		fmtp["level-id"] = strconv.FormatInt(int64(*f.LevelID), 10)
+	}
+	if f.ToolsetID != nil {
+		fmtp["toolset-id"] = strconv.FormatInt(int64(*f.ToolsetID), 10)
+	}
+	if f.SPS != nil {
+		fmtp["sprop-sps"] = base64.StdEncoding.EncodeToString(f.SPS)
+	}
+	if f.PPS != nil {
+		fmtp["sprop-pps"] = base64.StdEncoding.EncodeToString(f.PPS)
+	}
+	if f.MaxDONDiff != 0 {
+		fmtp["sprop-max-don-diff"] = strconv.FormatInt(int64(f.MaxDONDiff), 10)
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *EVC) PTSEqualsDTS(pkt *rtp.Packet) bool {
+	if len(pkt.Payload) == 0 {
+		return false
+	}
+
+	typ := (pkt.Payload[0] >> 1) & 0b111111
+
+	switch typ {
+	case evcNALUTypeAggregationUnit:
+		if len(pkt.Payload) < 4 {
+			return false
+		}
+
+		payload := pkt.Payload[2:]
+
+		for {
+			size := uint16(payload[0])<<8 | uint16(payload[1])
+			payload = payload[2:]
+
+			if size == 0 || int(size) > len(payload) {
+				return false
+			}
+
+			var nalu []byte
+			nalu, payload = payload[:size], payload[size:]
+
+			if evcIsRandomAccessNALUType((nalu[0] >> 1) & 0b111111) {
+				return true
+			}
+
+			if len(payload) == 0 {
+				break
+			}
+
+			if len(payload) < 2 {
+				return false
+			}
+		}
+
+	case evcNALUTypeFragmentationUnit:
+		if len(pkt.Payload) < 3 {
+			return false
+		}
+
+		start := pkt.Payload[2] >> 7
+		if start != 1 {
+			return false
+		}
+
+		return evcIsRandomAccessNALUType(pkt.Payload[2] & 0b111111)
+
+	default:
+		return evcIsRandomAccessNALUType(typ)
+	}
+
+	return false
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *EVC) CreateDecoder() (*rtpevc.Decoder, error) {
+	d := &rtpevc.Decoder{
+		MaxDONDiff: f.MaxDONDiff,
+	}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *EVC) CreateEncoder() (*rtpevc.Encoder, error) {
+	e := &rtpevc.Encoder{
+		PayloadType: f.PayloadTyp,
+		MaxDONDiff:  f.MaxDONDiff,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// SafeSetParams sets the codec parameters.
+func (f *EVC) SafeSetParams(sps []byte, pps []byte) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.SPS = sps
+	f.PPS = pps
+}
+
+// SafeParams returns the codec parameters.
+func (f *EVC) SafeParams() ([]byte, []byte) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.SPS, f.PPS
+}
@@ -0,0 +1,76 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEVCAttributes(t *testing.T) {
+	format := &EVC{
+		PayloadTyp: 96,
+		SPS:        []byte{0x01, 0x02},
+		PPS:        []byte{0x03, 0x04},
+	}
+	require.Equal(t, "EVC", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+
+	sps, pps := format.SafeParams()
+	require.Equal(t, []byte{0x01, 0x02}, sps)
+	require.Equal(t, []byte{0x03, 0x04}, pps)
+
+	format.SafeSetParams([]byte{0x05, 0x06}, []byte{0x07, 0x08})
+
+	sps, pps = format.SafeParams()
+	require.Equal(t, []byte{0x05, 0x06}, sps)
+	require.Equal(t, []byte{0x07, 0x08}, pps)
+}
+
+func TestEVCPTSEqualsDTS(t *testing.T) {
+	format := &EVC{
+		PayloadTyp: 96,
+	}
+
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{
+		Payload: []byte{evcNALUTypeIDR << 1, 0x00},
+	}))
+
+	// IDR inside FragmentationUnit
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{
+		Payload: []byte{evcNALUTypeFragmentationUnit << 1, 0x00, 0x80 | evcNALUTypeIDR, 0x01},
+	}))
+
+	// SPS inside AggregationUnit
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{
+		Payload: []byte{evcNALUTypeAggregationUnit << 1, 0x00, 0x00, 0x02, evcNALUTypeSPS << 1, 0x00},
+	}))
+
+	require.Equal(t, false, format.PTSEqualsDTS(&rtp.Packet{
+		Payload: []byte{1 << 1, 0x00},
+	}))
+}
+
+func TestEVCDecEncoder(t *testing.T) {
+	format := &EVC{}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode([][]byte{{0x01, 0x02, 0x03, 0x04}})
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	byts, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x01, 0x02, 0x03, 0x04}}, byts)
+}
+
+func FuzzEVCPTSEqualsDTS(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, b []byte) {
+		(&EVC{}).PTSEqualsDTS(&rtp.Packet{Payload: b})
+	})
+}
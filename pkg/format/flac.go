@@ -0,0 +1,128 @@
+package format
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpfragmentedaudio"
+)
+
+// size of the STREAMINFO metadata block, without header.
+const flacStreamInfoSize = 34
+
+// FLAC is the RTP format for the FLAC codec.
+// Each frame is carried by one or more packets, the last of which has the marker bit set.
+// The STREAMINFO metadata block is transmitted in the fmtp attribute.
+// Specification: https://datatracker.ietf.org/doc/html/rfc9639
+type FLAC struct {
+	PayloadTyp   uint8
+	SampleRate   int
+	ChannelCount int
+
+	// STREAMINFO metadata block, without header.
+	StreamInfo []byte
+}
+
+func (f *FLAC) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	tmp := strings.SplitN(ctx.clock, "/", 2)
+
+	tmp1, err := strconv.ParseUint(tmp[0], 10, 31)
+	if err != nil || tmp1 == 0 {
+		return fmt.Errorf("invalid sample rate: '%s'", tmp[0])
+	}
+	f.SampleRate = int(tmp1)
+
+	if len(tmp) >= 2 {
+		tmp1, err := strconv.ParseUint(tmp[1], 10, 31)
+		if err != nil || tmp1 == 0 {
+			return fmt.Errorf("invalid channel count: '%s'", tmp[1])
+		}
+		f.ChannelCount = int(tmp1)
+	} else {
+		// RFC8866: if the number of channels is omitted,
+		// a single channel is implied.
+		f.ChannelCount = 1
+	}
+
+	for key, val := range ctx.fmtp {
+		if key == "streaminfo" {
+			streamInfo, err := base64.StdEncoding.DecodeString(val)
+			if err != nil || len(streamInfo) != flacStreamInfoSize {
+				return fmt.Errorf("invalid streaminfo: %v", val)
+			}
+
+			f.StreamInfo = streamInfo
+		}
+	}
+
+	if f.StreamInfo == nil {
+		return fmt.Errorf("streaminfo is missing")
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *FLAC) Codec() string {
+	return "FLAC"
+}
+
+// ClockRate implements Format.
+func (f *FLAC) ClockRate() int {
+	return f.SampleRate
+}
+
+// PayloadType implements Format.
+func (f *FLAC) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *FLAC) RTPMap() string {
+	return "FLAC/" + strconv.FormatInt(int64(f.SampleRate), 10) +
+		"/" + strconv.FormatInt(int64(f.ChannelCount), 10)
+}
+
+// FMTP implements Format.
+func (f *FLAC) FMTP() map[string]string {
+	return map[string]string{
+		"streaminfo": base64.StdEncoding.EncodeToString(f.StreamInfo),
+	}
+}
+
+// PTSEqualsDTS implements Format.
+func (f *FLAC) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *FLAC) CreateDecoder() (*rtpfragmentedaudio.Decoder, error) {
+	d := &rtpfragmentedaudio.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *FLAC) CreateEncoder() (*rtpfragmentedaudio.Encoder, error) {
+	e := &rtpfragmentedaudio.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
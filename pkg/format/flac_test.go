@@ -0,0 +1,63 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpfragmentedaudio"
+)
+
+var flacTestStreamInfo = []byte{
+	0x10, 0x00, 0x10, 0x00, 0x00, 0x00, 0x0e, 0x00,
+	0x1f, 0x5e, 0x0b, 0xb8, 0x02, 0xf0, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00,
+}
+
+func TestFLACAttributes(t *testing.T) {
+	format := &FLAC{
+		PayloadTyp:   96,
+		SampleRate:   48000,
+		ChannelCount: 6,
+		StreamInfo:   flacTestStreamInfo,
+	}
+	require.Equal(t, "FLAC", format.Codec())
+	require.Equal(t, 48000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestFLACDecEncoder(t *testing.T) {
+	format := &FLAC{
+		PayloadTyp:   96,
+		SampleRate:   48000,
+		ChannelCount: 6,
+		StreamInfo:   flacTestStreamInfo,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode(bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 512))
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	var byts []byte
+
+	for _, pkt := range pkts {
+		byts, err = dec.Decode(pkt)
+		if errors.Is(err, rtpfragmentedaudio.ErrMorePacketsNeeded) {
+			continue
+		}
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 512), byts)
+}
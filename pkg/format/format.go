@@ -85,6 +85,8 @@ type unmarshalContext struct {
 	codec       string
 	rtpMap      string
 	fmtp        map[string]string
+	rawFMTP     string
+	ptime       string
 }
 
 // Format is a media format.
@@ -111,9 +113,16 @@ type Format interface {
 	PTSEqualsDTS(*rtp.Packet) bool
 }
 
-// Unmarshal decodes a format from a media description.
-func Unmarshal(md *psdp.MediaDescription, payloadTypeStr string) (Format, error) {
-	mediaType := md.MediaName.Media
+func getMediaAttribute(attributes []psdp.Attribute, key string) string {
+	for _, attr := range attributes {
+		if attr.Key == key {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	return ""
+}
+
+func newUnmarshalContext(md *psdp.MediaDescription, payloadTypeStr string) (*unmarshalContext, error) {
 	payloadTypeStr = replaceSmartPayloadType(payloadTypeStr, md.Attributes)
 
 	tmp, err := strconv.ParseUint(payloadTypeStr, 10, 8)
@@ -123,9 +132,32 @@ func Unmarshal(md *psdp.MediaDescription, payloadTypeStr string) (Format, error)
 	payloadType := uint8(tmp)
 
 	rtpMap := getFormatAttribute(md.Attributes, payloadType, "rtpmap")
-	fmtp := decodeFMTP(getFormatAttribute(md.Attributes, payloadType, "fmtp"))
+	rawFMTP := getFormatAttribute(md.Attributes, payloadType, "fmtp")
 	codec, clock := getCodecAndClock(rtpMap)
 
+	return &unmarshalContext{
+		mediaType:   md.MediaName.Media,
+		payloadType: payloadType,
+		clock:       clock,
+		codec:       codec,
+		rtpMap:      rtpMap,
+		fmtp:        decodeFMTP(rawFMTP),
+		rawFMTP:     rawFMTP,
+		ptime:       getMediaAttribute(md.Attributes, "ptime"),
+	}, nil
+}
+
+// Unmarshal decodes a format from a media description.
+func Unmarshal(md *psdp.MediaDescription, payloadTypeStr string) (Format, error) {
+	ctx, err := newUnmarshalContext(md, payloadTypeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadType := ctx.payloadType
+	codec := ctx.codec
+	clock := ctx.clock
+
 	format := func() Format {
 		switch {
 		/*
@@ -146,12 +178,54 @@ func Unmarshal(md *psdp.MediaDescription, payloadTypeStr string) (Format, error)
 		case codec == "h265" && clock == "90000" && payloadType >= 96 && payloadType <= 127:
 			return &H265{}
 
+		case codec == "evc" && clock == "90000" && payloadType >= 96 && payloadType <= 127:
+			return &EVC{}
+
 		case codec == "h264" && clock == "90000" && ((payloadType >= 96 && payloadType <= 127) || payloadType == 35):
 			return &H264{}
 
 		case codec == "mp4v-es" && clock == "90000" && payloadType >= 96 && payloadType <= 127:
 			return &MPEG4Video{}
 
+		case (codec == "h263-1998" || codec == "h263-2000") && clock == "90000" && payloadType >= 96 && payloadType <= 127:
+			return &H263{}
+
+		case codec == "jpeg2000" && clock == "90000" && payloadType >= 96 && payloadType <= 127:
+			return &JPEG2000{}
+
+		case codec == "jxsv" && clock == "90000" && payloadType >= 96 && payloadType <= 127:
+			return &JPEGXS{}
+
+		case codec == "raw" && clock == "90000" && payloadType >= 96 && payloadType <= 127:
+			return &RawVideo{}
+
+		case codec == "theora" && clock == "90000" && payloadType >= 96 && payloadType <= 127:
+			return &Theora{}
+
+		case codec == "smpte291" && clock == "90000" && payloadType >= 96 && payloadType <= 127:
+			return &SMPTE291{}
+
+		// application
+
+		case codec == "rtp-midi" && payloadType >= 96 && payloadType <= 127:
+			return &MIDI{}
+
+		// text
+
+		case codec == "t140" && clock == "1000" && payloadType >= 96 && payloadType <= 127:
+			return &T140{}
+
+		case codec == "ttml+xml" && payloadType >= 96 && payloadType <= 127:
+			return &TTML{}
+
+		// audio and video
+
+		case codec == "red" && payloadType >= 96 && payloadType <= 127:
+			return &RED{}
+
+		case codec == "rtx" && payloadType >= 96 && payloadType <= 127:
+			return &RTX{}
+
 		// audio
 
 		case codec == "opus", codec == "multiopus" && payloadType >= 96 && payloadType <= 127:
@@ -166,9 +240,24 @@ func Unmarshal(md *psdp.MediaDescription, payloadTypeStr string) (Format, error)
 		case codec == "ac3" && payloadType >= 96 && payloadType <= 127:
 			return &AC3{}
 
+		case codec == "ac4" && payloadType >= 96 && payloadType <= 127:
+			return &AC4{}
+
+		case codec == "flac" && payloadType >= 96 && payloadType <= 127:
+			return &FLAC{}
+
+		case (codec == "vnd.dts" || codec == "vnd.dts.hd") && payloadType >= 96 && payloadType <= 127:
+			return &DTS{}
+
 		case codec == "speex" && payloadType >= 96 && payloadType <= 127:
 			return &Speex{}
 
+		case codec == "amr" && strings.SplitN(clock, "/", 2)[0] == "8000" && payloadType >= 96 && payloadType <= 127:
+			return &AMR{}
+
+		case codec == "amr-wb" && strings.SplitN(clock, "/", 2)[0] == "16000" && payloadType >= 96 && payloadType <= 127:
+			return &AMRWB{}
+
 		case (codec == "g726-16" ||
 			codec == "g726-24" ||
 			codec == "g726-32" ||
@@ -179,6 +268,12 @@ func Unmarshal(md *psdp.MediaDescription, payloadTypeStr string) (Format, error)
 			codec == "aal2-g726-40") && clock == "8000" && payloadType >= 96 && payloadType <= 127:
 			return &G726{}
 
+		case codec == "g729" && clock == "8000" && payloadType >= 96 && payloadType <= 127:
+			return &G729{}
+
+		case codec == "ilbc" && clock == "8000" && payloadType >= 96 && payloadType <= 127:
+			return &ILBC{}
+
 		case codec == "pcma", codec == "pcmu" && payloadType >= 96 && payloadType <= 127:
 			return &G711{}
 
@@ -208,6 +303,9 @@ func Unmarshal(md *psdp.MediaDescription, payloadTypeStr string) (Format, error)
 		case payloadType == 9:
 			return &G722{}
 
+		case payloadType == 18:
+			return &G729{}
+
 		case payloadType == 0, payloadType == 8:
 			return &G711{}
 
@@ -218,14 +316,25 @@ func Unmarshal(md *psdp.MediaDescription, payloadTypeStr string) (Format, error)
 		return &Generic{}
 	}()
 
-	err = format.unmarshal(&unmarshalContext{
-		mediaType:   mediaType,
-		payloadType: payloadType,
-		clock:       clock,
-		codec:       codec,
-		rtpMap:      rtpMap,
-		fmtp:        fmtp,
-	})
+	err = format.unmarshal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return format, nil
+}
+
+// UnmarshalGeneric decodes a format from a media description into a Generic format,
+// regardless of its codec.
+// It can be used to recover formats whose codec-specific parameters are invalid.
+func UnmarshalGeneric(md *psdp.MediaDescription, payloadTypeStr string) (*Generic, error) {
+	ctx, err := newUnmarshalContext(md, payloadTypeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	format := &Generic{}
+	err = format.unmarshal(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package format
 
 import (
 	"testing"
+	"time"
 
 	"github.com/bluenviron/gortsplib/v4/pkg/sdp"
 	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
@@ -96,6 +97,53 @@ var casesFormat = []struct {
 		"G722/8000",
 		nil,
 	},
+	{
+		"audio g729",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 18\n",
+		&G729{
+			PayloadTyp: 18,
+			AnnexB:     true,
+		},
+		18,
+		"G729/8000",
+		nil,
+	},
+	{
+		"audio g729 annex b disabled",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 18\n" +
+			"a=rtpmap:18 G729/8000\n" +
+			"a=fmtp:18 annexb=no\n",
+		&G729{
+			PayloadTyp: 18,
+			AnnexB:     false,
+		},
+		18,
+		"G729/8000",
+		map[string]string{
+			"annexb": "no",
+		},
+	},
+	{
+		"audio ilbc",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 97\n" +
+			"a=rtpmap:97 iLBC/8000\n" +
+			"a=fmtp:97 mode=20\n",
+		&ILBC{
+			PayloadTyp: 97,
+			Mode:       20,
+		},
+		97,
+		"iLBC/8000",
+		map[string]string{
+			"mode": "20",
+		},
+	},
 	{
 		"audio g726 le 1",
 		"v=0\n" +
@@ -245,6 +293,28 @@ var casesFormat = []struct {
 		"L16/16000/1",
 		nil,
 	},
+	{
+		"audio lpcm 24 multichannel",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 97\n" +
+			"a=rtpmap:97 L24/48000/8\n" +
+			"a=fmtp:97 channel-order=SMPTE2110.(SGRP,SGRP)\n" +
+			"a=ptime:0.125\n",
+		&LPCM{
+			PayloadTyp:   97,
+			BitDepth:     24,
+			SampleRate:   48000,
+			ChannelCount: 8,
+			ChannelOrder: "SMPTE2110.(SGRP,SGRP)",
+			PacketTime:   125 * time.Microsecond,
+		},
+		97,
+		"L24/48000/8",
+		map[string]string{
+			"channel-order": "SMPTE2110.(SGRP,SGRP)",
+		},
+	},
 	{
 		"audio lpcm 24",
 		"v=0\n" +
@@ -602,6 +672,77 @@ var casesFormat = []struct {
 			"profile-level-id": "30",
 		},
 	},
+	{
+		"audio ac4",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 AC4/48000/6\n",
+		&AC4{
+			PayloadTyp:   96,
+			SampleRate:   48000,
+			ChannelCount: 6,
+		},
+		96,
+		"AC4/48000/6",
+		nil,
+	},
+	{
+		"audio dts",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 vnd.dts/48000/2\n",
+		&DTS{
+			PayloadTyp:   96,
+			SampleRate:   48000,
+			ChannelCount: 2,
+		},
+		96,
+		"vnd.dts/48000/2",
+		nil,
+	},
+	{
+		"audio dts-hd",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 97\n" +
+			"a=rtpmap:97 vnd.dts.hd/96000/8\n",
+		&DTS{
+			PayloadTyp:   97,
+			HD:           true,
+			SampleRate:   96000,
+			ChannelCount: 8,
+		},
+		97,
+		"vnd.dts.hd/96000/8",
+		nil,
+	},
+	{
+		"audio flac",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 FLAC/48000/2\n" +
+			"a=fmtp:96 streaminfo=EAAQAAAADgAfXgu4AvAAAAAAAAAAAAAAAAAAAAAAAAAAAA==\n",
+		&FLAC{
+			PayloadTyp:   96,
+			SampleRate:   48000,
+			ChannelCount: 2,
+			StreamInfo: []byte{
+				0x10, 0x00, 0x10, 0x00, 0x00, 0x00, 0x0e, 0x00,
+				0x1f, 0x5e, 0x0b, 0xb8, 0x02, 0xf0, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00,
+			},
+		},
+		96,
+		"FLAC/48000/2",
+		map[string]string{
+			"streaminfo": "EAAQAAAADgAfXgu4AvAAAAAAAAAAAAAAAAAAAAAAAAAAAA==",
+		},
+	},
 	{
 		"audio speex",
 		"v=0\n" +
@@ -620,6 +761,68 @@ var casesFormat = []struct {
 			"vbr": "off",
 		},
 	},
+	{
+		"audio amr",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 AMR/8000\n" +
+			"a=fmtp:96 mode-set=0,2,5,7; mode-change-period=2\n",
+		&AMR{
+			PayloadTyp:       96,
+			ChannelCount:     1,
+			ModeSet:          []int{0, 2, 5, 7},
+			ModeChangePeriod: 2,
+		},
+		96,
+		"AMR/8000",
+		map[string]string{
+			"mode-set":           "0,2,5,7",
+			"mode-change-period": "2",
+		},
+	},
+	{
+		"audio amr octet-aligned",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 97\n" +
+			"a=rtpmap:97 AMR/8000/2\n" +
+			"a=fmtp:97 crc=1; interleaving=4; octet-align=1\n",
+		&AMR{
+			PayloadTyp:   97,
+			ChannelCount: 2,
+			OctetAlign:   true,
+			CRC:          true,
+			Interleaving: 4,
+		},
+		97,
+		"AMR/8000/2",
+		map[string]string{
+			"octet-align":  "1",
+			"crc":          "1",
+			"interleaving": "4",
+		},
+	},
+	{
+		"audio amr-wb",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 98\n" +
+			"a=rtpmap:98 AMR-WB/16000\n" +
+			"a=fmtp:98 mode-set=0,1,2,8; octet-align=1\n",
+		&AMRWB{
+			PayloadTyp:   98,
+			ChannelCount: 1,
+			OctetAlign:   true,
+			ModeSet:      []int{0, 1, 2, 8},
+		},
+		98,
+		"AMR-WB/16000",
+		map[string]string{
+			"octet-align": "1",
+			"mode-set":    "0,1,2,8",
+		},
+	},
 	{
 		"audio vorbis",
 		"v=0\n" +
@@ -665,8 +868,11 @@ var casesFormat = []struct {
 			"a=rtpmap:96 multiopus/48000/6\n" +
 			"a=fmtp:96 num_streams=4; coupled_streams=2; channel_mapping=0,4,1,2,3,5\n",
 		&Opus{
-			PayloadTyp:   96,
-			ChannelCount: 6,
+			PayloadTyp:         96,
+			ChannelCount:       6,
+			StreamCount:        4,
+			CoupledStreamCount: 2,
+			ChannelMapping:     []uint8{0, 4, 1, 2, 3, 5},
 		},
 		96,
 		"multiopus/48000/6",
@@ -677,6 +883,30 @@ var casesFormat = []struct {
 			"sprop-maxcapturerate": "48000",
 		},
 	},
+	{
+		"audio opus ambisonics",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 multiopus/48000/4\n" +
+			"a=fmtp:96 num_streams=4; coupled_streams=0; channel_mapping=0,1,2,3; sprop-maxcapturerate=24000\n",
+		&Opus{
+			PayloadTyp:         96,
+			ChannelCount:       4,
+			StreamCount:        4,
+			CoupledStreamCount: 0,
+			ChannelMapping:     []uint8{0, 1, 2, 3},
+			MaxCaptureRate:     24000,
+		},
+		96,
+		"multiopus/48000/4",
+		map[string]string{
+			"channel_mapping":      "0,1,2,3",
+			"coupled_streams":      "0",
+			"num_streams":          "4",
+			"sprop-maxcapturerate": "24000",
+		},
+	},
 	{
 		"audio ac3",
 		"v=0\n" +
@@ -784,6 +1014,7 @@ var casesFormat = []struct {
 				0x68, 0xee, 0x3c, 0x80,
 			},
 			PacketizationMode: 1,
+			ProfileLevelID:    []byte{0x64, 0x00, 0x0c},
 		},
 		96,
 		"H264/90000",
@@ -813,6 +1044,7 @@ var casesFormat = []struct {
 				0x68, 0xeb, 0xe3, 0xcb, 0x22, 0xc0,
 			},
 			PacketizationMode: 1,
+			ProfileLevelID:    []byte{0x64, 0x00, 0x1f},
 		},
 		96,
 		"H264/90000",
@@ -842,6 +1074,7 @@ var casesFormat = []struct {
 				0x68, 0xfa, 0x8f, 0x2c,
 			},
 			PacketizationMode: 1,
+			ProfileLevelID:    []byte{0x64, 0x00, 0x29},
 		},
 		96,
 		"H264/90000",
@@ -887,6 +1120,7 @@ var casesFormat = []struct {
 				0x68, 0xee, 0x38, 0x80,
 			},
 			PacketizationMode: 1,
+			ProfileLevelID:    []byte{0x4d, 0xe0, 0x28},
 		},
 		96,
 		"H264/90000",
@@ -907,11 +1141,13 @@ var casesFormat = []struct {
 		&H264{
 			PayloadTyp:        96,
 			PacketizationMode: 1,
+			ProfileLevelID:    []byte{0x01, 0x01, 0x01},
 		},
 		96,
 		"H264/90000",
 		map[string]string{
 			"packetization-mode": "1",
+			"profile-level-id":   "010101",
 		},
 	},
 	{
@@ -957,6 +1193,7 @@ var casesFormat = []struct {
 				0x68, 0xee, 0x38, 0x80,
 			},
 			PacketizationMode: 1,
+			ProfileLevelID:    []byte{0x4d, 0x40, 0x29},
 		},
 		35,
 		"H264/90000",
@@ -966,6 +1203,53 @@ var casesFormat = []struct {
 			"sprop-parameter-sets": "Z01AKY2NYDwBE/LgLcBDQECA,aO44gA==",
 		},
 	},
+	{
+		"video h264 with limits",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 H264/90000\n" +
+			"a=fmtp:96 packetization-mode=1; profile-level-id=42e01f; " +
+			"max-mbps=108000; max-fs=3600; max-br=14000; sar-understood=16\n",
+		&H264{
+			PayloadTyp:        96,
+			PacketizationMode: 1,
+			ProfileLevelID:    []byte{0x42, 0xe0, 0x1f},
+			MaxMBPS:           intPtr(108000),
+			MaxFS:             intPtr(3600),
+			MaxBR:             intPtr(14000),
+			SARUnderstood:     intPtr(16),
+		},
+		96,
+		"H264/90000",
+		map[string]string{
+			"packetization-mode": "1",
+			"profile-level-id":   "42E01F",
+			"max-mbps":           "108000",
+			"max-fs":             "3600",
+			"max-br":             "14000",
+			"sar-understood":     "16",
+		},
+	},
+	{
+		"video h264 interleaved",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 H264/90000\n" +
+			"a=fmtp:96 packetization-mode=2; sprop-interleaving-depth=3\n",
+		&H264{
+			PayloadTyp:             96,
+			PacketizationMode:      2,
+			SpropInterleavingDepth: 3,
+		},
+		96,
+		"H264/90000",
+		map[string]string{
+			"packetization-mode":       "2",
+			"sprop-interleaving-depth": "3",
+		},
+	},
 	{
 		"video h265",
 		"v=0\n" +
@@ -1038,6 +1322,202 @@ var casesFormat = []struct {
 			"sprop-pps": "RAHgdrAmQA==",
 		},
 	},
+	{
+		"video evc",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 EVC/90000\n" +
+			"a=fmtp:96 profile-id=1; level-id=93; toolset-id=0; " +
+			"sprop-sps=MgA=; sprop-pps=NAA=; sprop-max-don-diff=2\n",
+		&EVC{
+			PayloadTyp: 96,
+			ProfileID:  intPtr(1),
+			LevelID:    intPtr(93),
+			ToolsetID:  intPtr(0),
+			SPS:        []byte{0x32, 0x00},
+			PPS:        []byte{0x34, 0x00},
+			MaxDONDiff: 2,
+		},
+		96,
+		"EVC/90000",
+		map[string]string{
+			"profile-id":         "1",
+			"level-id":           "93",
+			"toolset-id":         "0",
+			"sprop-sps":          "MgA=",
+			"sprop-pps":          "NAA=",
+			"sprop-max-don-diff": "2",
+		},
+	},
+	{
+		"video jpeg 2000",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 98\n" +
+			"a=rtpmap:98 jpeg2000/90000\n" +
+			"a=fmtp:98 sampling=YCbCr-4:2:0; width=1920; height=1080; interlace=1\n",
+		&JPEG2000{
+			PayloadTyp: 98,
+			Sampling:   "YCbCr-4:2:0",
+			Width:      1920,
+			Height:     1080,
+			Interlace:  true,
+		},
+		98,
+		"jpeg2000/90000",
+		map[string]string{
+			"sampling":  "YCbCr-4:2:0",
+			"width":     "1920",
+			"height":    "1080",
+			"interlace": "1",
+		},
+	},
+	{
+		"video jpeg xs",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 112\n" +
+			"a=rtpmap:112 jxsv/90000\n" +
+			"a=fmtp:112 packetmode=0; transmode=1; profile=High444.12; level=2k-1; sublevel=Sublev3bpp; " +
+			"sampling=YCbCr-4:2:2; depth=10; width=1920; height=1080\n",
+		&JPEGXS{
+			PayloadTyp: 112,
+			PacketMode: 0,
+			TransMode:  intPtr(1),
+			Profile:    "High444.12",
+			Level:      "2k-1",
+			Sublevel:   "Sublev3bpp",
+			Sampling:   "YCbCr-4:2:2",
+			Depth:      10,
+			Width:      1920,
+			Height:     1080,
+		},
+		112,
+		"jxsv/90000",
+		map[string]string{
+			"packetmode": "0",
+			"transmode":  "1",
+			"profile":    "High444.12",
+			"level":      "2k-1",
+			"sublevel":   "Sublev3bpp",
+			"sampling":   "YCbCr-4:2:2",
+			"depth":      "10",
+			"width":      "1920",
+			"height":     "1080",
+		},
+	},
+	{
+		"video raw",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 raw/90000\n" +
+			"a=fmtp:96 sampling=YCbCr-4:2:2; width=1920; height=1080; depth=10; " +
+			"colorimetry=BT709; interlace; exactframerate=30000/1001\n",
+		&RawVideo{
+			PayloadTyp:     96,
+			Sampling:       "YCbCr-4:2:2",
+			Width:          1920,
+			Height:         1080,
+			Depth:          10,
+			Colorimetry:    "BT709",
+			Interlace:      true,
+			ExactFrameRate: "30000/1001",
+		},
+		96,
+		"raw/90000",
+		map[string]string{
+			"sampling":       "YCbCr-4:2:2",
+			"width":          "1920",
+			"height":         "1080",
+			"depth":          "10",
+			"colorimetry":    "BT709",
+			"interlace":      "",
+			"exactframerate": "30000/1001",
+		},
+	},
+	{
+		"video smpte 291",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 100\n" +
+			"a=rtpmap:100 smpte291/90000\n" +
+			"a=fmtp:100 DID_SDID={0x61,0x02};DID_SDID={0x41,0x05};VPID_Code=132\n",
+		&SMPTE291{
+			PayloadTyp: 100,
+			DataIdentifiers: []SMPTE291DataIdentifier{
+				{DID: 0x61, SDID: 0x02},
+				{DID: 0x41, SDID: 0x05},
+			},
+			VPIDCode: 132,
+		},
+		100,
+		"smpte291/90000",
+		map[string]string{
+			"DID_SDID={0x61,0x02}": "",
+			"DID_SDID={0x41,0x05}": "",
+			"VPID_Code":            "132",
+		},
+	},
+	{
+		"video theora",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 theora/90000\n" +
+			"a=fmtp:96 sampling=YCbCr-4:2:0; width=640; height=480; " +
+			"delivery-method=inline; configuration=AAAAAcg6FQ==\n",
+		&Theora{
+			PayloadTyp:    96,
+			Sampling:      "YCbCr-4:2:0",
+			Width:         640,
+			Height:        480,
+			Configuration: []byte{0x00, 0x00, 0x00, 0x01, 0xc8, 0x3a, 0x15},
+		},
+		96,
+		"theora/90000",
+		map[string]string{
+			"sampling":        "YCbCr-4:2:0",
+			"width":           "640",
+			"height":          "480",
+			"delivery-method": "inline",
+			"configuration":   "AAAAAcg6FQ==",
+		},
+	},
+	{
+		"video h263-1998",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 H263-1998/90000\n",
+		&H263{
+			PayloadTyp: 96,
+		},
+		96,
+		"H263-1998/90000",
+		nil,
+	},
+	{
+		"video h263-2000",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 H263-2000/90000\n" +
+			"a=fmtp:96 profile=3; level=10\n",
+		&H263{
+			PayloadTyp:  96,
+			Version2000: true,
+			Profile:     3,
+			Level:       10,
+		},
+		96,
+		"H263-2000/90000",
+		map[string]string{
+			"profile": "3",
+			"level":   "10",
+		},
+	},
 	{
 		"video vp8",
 		"v=0\n" +
@@ -1099,6 +1579,76 @@ var casesFormat = []struct {
 			"tier":      "1",
 		},
 	},
+	{
+		"application midi",
+		"v=0\n" +
+			"s=\n" +
+			"m=application 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 rtp-midi/44100\n" +
+			"a=fmtp:96 j_sec=none\n",
+		&MIDI{
+			PayloadTyp: 96,
+			ClockRat:   44100,
+		},
+		96,
+		"rtp-midi/44100",
+		map[string]string{
+			"j_sec": "none",
+		},
+	},
+	{
+		"text t140",
+		"v=0\n" +
+			"s=\n" +
+			"m=text 0 RTP/AVP 98\n" +
+			"a=rtpmap:98 t140/1000\n" +
+			"a=fmtp:98 cps=30\n",
+		&T140{
+			PayloadTyp: 98,
+			CPS:        30,
+		},
+		98,
+		"t140/1000",
+		map[string]string{
+			"cps": "30",
+		},
+	},
+	{
+		"text red",
+		"v=0\n" +
+			"s=\n" +
+			"m=text 0 RTP/AVP 100\n" +
+			"a=rtpmap:100 red/1000\n" +
+			"a=fmtp:100 98/98/98\n",
+		&RED{
+			PayloadTyp:           100,
+			ClockRat:             1000,
+			EncodingPayloadTypes: []uint8{98, 98, 98},
+		},
+		100,
+		"red/1000",
+		map[string]string{
+			"98/98/98": "",
+		},
+	},
+	{
+		"text ttml",
+		"v=0\n" +
+			"s=\n" +
+			"m=text 0 RTP/AVP 96\n" +
+			"a=rtpmap:96 ttml+xml/1000\n" +
+			"a=fmtp:96 codecs=im1t\n",
+		&TTML{
+			PayloadTyp: 96,
+			ClockRat:   1000,
+			Codecs:     "im1t",
+		},
+		96,
+		"ttml+xml/1000",
+		map[string]string{
+			"codecs": "im1t",
+		},
+	},
 	{
 		"application",
 		"v=0\n" +
@@ -1194,6 +1744,59 @@ var casesFormat = []struct {
 		"TP-LINK/90000",
 		nil,
 	},
+	{
+		"video rtx",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 97\n" +
+			"a=rtpmap:97 rtx/90000\n" +
+			"a=fmtp:97 apt=96; rtx-time=3000\n",
+		&RTX{
+			PayloadTyp:            97,
+			ClockRat:              90000,
+			AssociatedPayloadType: 96,
+			RTXTime:               3000,
+		},
+		97,
+		"rtx/90000",
+		map[string]string{
+			"apt":      "96",
+			"rtx-time": "3000",
+		},
+	},
+	{
+		"audio red",
+		"v=0\n" +
+			"s=\n" +
+			"m=audio 0 RTP/AVP 120\n" +
+			"a=rtpmap:120 red/48000/2\n" +
+			"a=fmtp:120 111/111\n",
+		&RED{
+			PayloadTyp:           120,
+			ClockRat:             48000,
+			ChannelCount:         2,
+			EncodingPayloadTypes: []uint8{111, 111},
+		},
+		120,
+		"red/48000/2",
+		map[string]string{
+			"111/111": "",
+		},
+	},
+	{
+		"video red",
+		"v=0\n" +
+			"s=\n" +
+			"m=video 0 RTP/AVP 127\n" +
+			"a=rtpmap:127 red/90000\n",
+		&RED{
+			PayloadTyp: 127,
+			ClockRat:   90000,
+		},
+		127,
+		"red/90000",
+		nil,
+	},
 }
 
 func TestUnmarshal(t *testing.T) {
@@ -1212,6 +1815,28 @@ func TestUnmarshal(t *testing.T) {
 	}
 }
 
+func TestUnmarshalGeneric(t *testing.T) {
+	var desc sdp.SessionDescription
+	err := desc.Unmarshal([]byte("v=0\n" +
+		"s=\n" +
+		"m=video 0 RTP/AVP 96\n" +
+		"a=rtpmap:96 H264/90000\n" +
+		"a=fmtp:96 packetization-mode=a\n"))
+	require.NoError(t, err)
+
+	_, err = Unmarshal(desc.MediaDescriptions[0], "96")
+	require.EqualError(t, err, "invalid packetization-mode (a)")
+
+	dec, err := UnmarshalGeneric(desc.MediaDescriptions[0], "96")
+	require.NoError(t, err)
+	require.Equal(t, &Generic{
+		PayloadTyp: 96,
+		RTPMa:      "H264/90000",
+		FMT:        map[string]string{"packetization-mode": "a"},
+		ClockRat:   90000,
+	}, dec)
+}
+
 func TestMarshal(t *testing.T) {
 	for _, ca := range casesFormat {
 		t.Run(ca.name, func(t *testing.T) {
@@ -0,0 +1,100 @@
+package format
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpg729"
+)
+
+// G729 is the RTP format for the G729 codec.
+// Specification: https://datatracker.ietf.org/doc/html/rfc3551
+// Specification: https://datatracker.ietf.org/doc/html/rfc4856
+type G729 struct {
+	PayloadTyp uint8
+
+	// whether annex B (voice activity detection and comfort noise generation) is in use.
+	AnnexB bool
+}
+
+func (f *G729) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	// RFC4856: annexb defaults to yes
+	f.AnnexB = true
+
+	for key, val := range ctx.fmtp {
+		if key == "annexb" {
+			if val != "yes" && val != "no" {
+				return fmt.Errorf("invalid annexb value: %v", val)
+			}
+
+			f.AnnexB = (val == "yes")
+		}
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *G729) Codec() string {
+	return "G729"
+}
+
+// ClockRate implements Format.
+func (f *G729) ClockRate() int {
+	return 8000
+}
+
+// PayloadType implements Format.
+func (f *G729) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *G729) RTPMap() string {
+	return "G729/8000"
+}
+
+// FMTP implements Format.
+func (f *G729) FMTP() map[string]string {
+	if f.AnnexB {
+		return nil
+	}
+
+	return map[string]string{
+		"annexb": "no",
+	}
+}
+
+// PTSEqualsDTS implements Format.
+func (f *G729) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *G729) CreateDecoder() (*rtpg729.Decoder, error) {
+	d := &rtpg729.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *G729) CreateEncoder() (*rtpg729.Encoder, error) {
+	e := &rtpg729.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,45 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestG729Attributes(t *testing.T) {
+	format := &G729{
+		PayloadTyp: 18,
+		AnnexB:     true,
+	}
+	require.Equal(t, "G729", format.Codec())
+	require.Equal(t, 8000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestG729DecEncoder(t *testing.T) {
+	format := &G729{
+		PayloadTyp: 18,
+		AnnexB:     true,
+	}
+
+	frames := [][]byte{
+		bytes.Repeat([]byte{0x01}, 10),
+		{0x02, 0x03},
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode(frames)
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	byts, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, frames, byts)
+}
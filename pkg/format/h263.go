@@ -0,0 +1,121 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph263"
+)
+
+// H263 is the RTP format for the H263 codec, in its H263-1998 and H263-2000 variants.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4629
+type H263 struct {
+	PayloadTyp uint8
+
+	// whether the format is H263-2000 instead of H263-1998.
+	Version2000 bool
+
+	// profile and level (optional).
+	Profile int
+	Level   int
+}
+
+func (f *H263) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+	f.Version2000 = (ctx.codec == "h263-2000")
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "profile":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid profile: %v", val)
+			}
+			f.Profile = int(tmp)
+
+		case "level":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid level: %v", val)
+			}
+			f.Level = int(tmp)
+		}
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *H263) Codec() string {
+	return "H263"
+}
+
+// ClockRate implements Format.
+func (f *H263) ClockRate() int {
+	return 90000
+}
+
+// PayloadType implements Format.
+func (f *H263) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *H263) RTPMap() string {
+	if f.Version2000 {
+		return "H263-2000/90000"
+	}
+	return "H263-1998/90000"
+}
+
+// FMTP implements Format.
+func (f *H263) FMTP() map[string]string {
+	fmtp := make(map[string]string)
+
+	if f.Profile != 0 {
+		fmtp["profile"] = strconv.FormatInt(int64(f.Profile), 10)
+	}
+
+	if f.Level != 0 {
+		fmtp["level"] = strconv.FormatInt(int64(f.Level), 10)
+	}
+
+	if len(fmtp) == 0 {
+		return nil
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *H263) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *H263) CreateDecoder() (*rtph263.Decoder, error) {
+	d := &rtph263.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *H263) CreateEncoder() (*rtph263.Encoder, error) {
+	e := &rtph263.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
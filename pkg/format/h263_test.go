@@ -0,0 +1,40 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestH263Attributes(t *testing.T) {
+	format := &H263{
+		PayloadTyp:  96,
+		Version2000: true,
+		Profile:     3,
+		Level:       10,
+	}
+	require.Equal(t, "H263", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestH263DecEncoder(t *testing.T) {
+	format := &H263{
+		PayloadTyp: 96,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode([]byte{0x00, 0x00, 0x80, 0x02, 0x1c, 0x01, 0x02})
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	byts, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00, 0x00, 0x80, 0x02, 0x1c, 0x01, 0x02}, byts)
+}
@@ -23,6 +23,18 @@ type H264 struct {
 	PPS               []byte
 	PacketizationMode int
 
+	// sprop-interleaving-depth, used when PacketizationMode is 2.
+	// It is zero when NALUs are sent in decoding order.
+	SpropInterleavingDepth int
+
+	// profile_idc, profile-iop and level_idc.
+	// When SPS is available, profile-level-id is generated from it.
+	ProfileLevelID []byte
+	MaxMBPS        *int
+	MaxFS          *int
+	MaxBR          *int
+	SARUnderstood  *int
+
 	mutex sync.RWMutex
 }
 
@@ -67,12 +79,68 @@ func (f *H264) unmarshal(ctx *unmarshalContext) error {
 			}
 
 			f.PacketizationMode = int(tmp)
+
+		case "sprop-interleaving-depth":
+			tmp, err := strconv.ParseUint(val, 10, 15)
+			if err != nil {
+				return fmt.Errorf("invalid sprop-interleaving-depth (%v)", val)
+			}
+
+			f.SpropInterleavingDepth = int(tmp)
+
+		case "profile-level-id":
+			// profile-level-id can be generated from the SPS,
+			// therefore invalid values are ignored.
+			tmp, err := hex.DecodeString(val)
+			if err != nil || len(tmp) != 3 {
+				continue
+			}
+
+			f.ProfileLevelID = tmp
+
+		case "max-mbps":
+			tmp, err := h264ParseFMTPInt(key, val)
+			if err != nil {
+				return err
+			}
+			f.MaxMBPS = tmp
+
+		case "max-fs":
+			tmp, err := h264ParseFMTPInt(key, val)
+			if err != nil {
+				return err
+			}
+			f.MaxFS = tmp
+
+		case "max-br":
+			tmp, err := h264ParseFMTPInt(key, val)
+			if err != nil {
+				return err
+			}
+			f.MaxBR = tmp
+
+		case "sar-understood":
+			tmp, err := h264ParseFMTPInt(key, val)
+			if err != nil {
+				return err
+			}
+			f.SARUnderstood = tmp
 		}
 	}
 
 	return nil
 }
 
+func h264ParseFMTPInt(key string, val string) (*int, error) {
+	tmp, err := strconv.ParseUint(val, 10, 31)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s (%v)", key, val)
+	}
+
+	v := int(tmp)
+	return &v, nil
+}
+
 // Codec implements Format.
 func (f *H264) Codec() string {
 	return "H264"
@@ -104,6 +172,11 @@ func (f *H264) FMTP() map[string]string {
 		fmtp["packetization-mode"] = strconv.FormatInt(int64(f.PacketizationMode), 10)
 	}
 
+	// sprop-interleaving-depth is mandatory in interleaved mode.
+	if f.PacketizationMode == 2 {
+		fmtp["sprop-interleaving-depth"] = strconv.FormatInt(int64(f.SpropInterleavingDepth), 10)
+	}
+
 	var tmp []string
 	if f.SPS != nil {
 		tmp = append(tmp, base64.StdEncoding.EncodeToString(f.SPS))
@@ -114,8 +187,26 @@ func (f *H264) FMTP() map[string]string {
 	if tmp != nil {
 		fmtp["sprop-parameter-sets"] = strings.Join(tmp, ",")
 	}
+
+	// a profile-level-id that doesn't match the SPS is refused by some clients (i.e. iOS),
+	// therefore the SPS takes precedence.
 	if len(f.SPS) >= 4 {
 		fmtp["profile-level-id"] = strings.ToUpper(hex.EncodeToString(f.SPS[1:4]))
+	} else if len(f.ProfileLevelID) == 3 {
+		fmtp["profile-level-id"] = strings.ToUpper(hex.EncodeToString(f.ProfileLevelID))
+	}
+
+	if f.MaxMBPS != nil {
+		fmtp["max-mbps"] = strconv.FormatInt(int64(*f.MaxMBPS), 10)
+	}
+	if f.MaxFS != nil {
+		fmtp["max-fs"] = strconv.FormatInt(int64(*f.MaxFS), 10)
+	}
+	if f.MaxBR != nil {
+		fmtp["max-br"] = strconv.FormatInt(int64(*f.MaxBR), 10)
+	}
+	if f.SARUnderstood != nil {
+		fmtp["sar-understood"] = strconv.FormatInt(int64(*f.SARUnderstood), 10)
 	}
 
 	return fmtp
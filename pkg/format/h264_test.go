@@ -28,6 +28,24 @@ func TestH264Attributes(t *testing.T) {
 	require.Equal(t, []byte{0x09, 0x0A}, pps)
 }
 
+func TestH264ProfileLevelIDFromSPS(t *testing.T) {
+	format := &H264{
+		PayloadTyp: 96,
+		SPS: []byte{
+			0x67, 0x64, 0x00, 0x0c, 0xac, 0x3b, 0x50, 0xb0,
+			0x4b, 0x42, 0x00, 0x00, 0x03, 0x00, 0x02, 0x00,
+			0x00, 0x03, 0x00, 0x3d, 0x08,
+		},
+		PPS:               []byte{0x68, 0xee, 0x3c, 0x80},
+		PacketizationMode: 1,
+		ProfileLevelID:    []byte{0x42, 0xe0, 0x1f},
+	}
+	require.Equal(t, "64000C", format.FMTP()["profile-level-id"])
+
+	format.SafeSetParams(nil, nil)
+	require.Equal(t, "42E01F", format.FMTP()["profile-level-id"])
+}
+
 func TestH264PTSEqualsDTS(t *testing.T) {
 	format := &H264{
 		PayloadTyp:        96,
@@ -0,0 +1,100 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpilbc"
+)
+
+// ILBC is the RTP format for the iLBC codec.
+// Specification: https://datatracker.ietf.org/doc/html/rfc3952
+type ILBC struct {
+	PayloadTyp uint8
+
+	// frame duration in milliseconds, 20 or 30.
+	Mode int
+}
+
+func (f *ILBC) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	// RFC3952: if no mode parameter is present, 30ms frames are in use
+	f.Mode = 30
+
+	for key, val := range ctx.fmtp {
+		if key == "mode" {
+			if val != "20" && val != "30" {
+				return fmt.Errorf("invalid mode: %v", val)
+			}
+
+			tmp, _ := strconv.ParseUint(val, 10, 31)
+			f.Mode = int(tmp)
+		}
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *ILBC) Codec() string {
+	return "iLBC"
+}
+
+// ClockRate implements Format.
+func (f *ILBC) ClockRate() int {
+	return 8000
+}
+
+// PayloadType implements Format.
+func (f *ILBC) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *ILBC) RTPMap() string {
+	return "iLBC/8000"
+}
+
+// FMTP implements Format.
+func (f *ILBC) FMTP() map[string]string {
+	return map[string]string{
+		"mode": strconv.FormatInt(int64(f.Mode), 10),
+	}
+}
+
+// PTSEqualsDTS implements Format.
+func (f *ILBC) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *ILBC) CreateDecoder() (*rtpilbc.Decoder, error) {
+	d := &rtpilbc.Decoder{
+		Mode: f.Mode,
+	}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *ILBC) CreateEncoder() (*rtpilbc.Encoder, error) {
+	e := &rtpilbc.Encoder{
+		PayloadType: f.PayloadTyp,
+		Mode:        f.Mode,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,45 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestILBCAttributes(t *testing.T) {
+	format := &ILBC{
+		PayloadTyp: 96,
+		Mode:       30,
+	}
+	require.Equal(t, "iLBC", format.Codec())
+	require.Equal(t, 8000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestILBCDecEncoder(t *testing.T) {
+	format := &ILBC{
+		PayloadTyp: 96,
+		Mode:       20,
+	}
+
+	frames := [][]byte{
+		bytes.Repeat([]byte{0x01}, 38),
+		bytes.Repeat([]byte{0x02}, 38),
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode(frames)
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	byts, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, frames, byts)
+}
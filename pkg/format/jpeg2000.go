@@ -0,0 +1,130 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpj2k"
+)
+
+// JPEG2000 is the RTP format for the JPEG 2000 codec.
+// Specification: https://datatracker.ietf.org/doc/html/rfc5371
+type JPEG2000 struct {
+	PayloadTyp uint8
+
+	// color space and sampling of the image, for instance "YCbCr-4:2:0".
+	Sampling string
+
+	// image size (optional).
+	Width  int
+	Height int
+
+	// whether the video is interlaced.
+	Interlace bool
+}
+
+func (f *JPEG2000) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "sampling":
+			f.Sampling = val
+
+		case "width":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid width: %v", val)
+			}
+			f.Width = int(tmp)
+
+		case "height":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid height: %v", val)
+			}
+			f.Height = int(tmp)
+
+		case "interlace":
+			f.Interlace = (val != "0")
+		}
+	}
+
+	if f.Sampling == "" {
+		return fmt.Errorf("sampling is missing")
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *JPEG2000) Codec() string {
+	return "JPEG 2000"
+}
+
+// ClockRate implements Format.
+func (f *JPEG2000) ClockRate() int {
+	return 90000
+}
+
+// PayloadType implements Format.
+func (f *JPEG2000) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *JPEG2000) RTPMap() string {
+	return "jpeg2000/90000"
+}
+
+// FMTP implements Format.
+func (f *JPEG2000) FMTP() map[string]string {
+	fmtp := map[string]string{
+		"sampling": f.Sampling,
+	}
+
+	if f.Width != 0 {
+		fmtp["width"] = strconv.FormatInt(int64(f.Width), 10)
+	}
+	if f.Height != 0 {
+		fmtp["height"] = strconv.FormatInt(int64(f.Height), 10)
+	}
+	if f.Interlace {
+		fmtp["interlace"] = "1"
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *JPEG2000) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *JPEG2000) CreateDecoder() (*rtpj2k.Decoder, error) {
+	d := &rtpj2k.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *JPEG2000) CreateEncoder() (*rtpj2k.Encoder, error) {
+	e := &rtpj2k.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
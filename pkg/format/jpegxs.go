@@ -0,0 +1,190 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpjpegxs"
+)
+
+// JPEGXS is the RTP format for the JPEG XS codec.
+// Specification: https://datatracker.ietf.org/doc/html/rfc9134
+type JPEGXS struct {
+	PayloadTyp uint8
+
+	// packetization mode.
+	// 0 is codestream mode, 1 is slice mode.
+	PacketMode int
+
+	// transmission mode (optional).
+	// 0 is out-of-order, 1 is sequential.
+	TransMode *int
+
+	// profile, level and sublevel of the codestream (optional).
+	Profile  string
+	Level    string
+	Sublevel string
+
+	// color space and sampling of the image (optional).
+	Sampling string
+
+	// bit depth (optional).
+	Depth int
+
+	// image size (optional).
+	Width  int
+	Height int
+}
+
+func (f *JPEGXS) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	packetModeFound := false
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "packetmode":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil || tmp > 1 {
+				return fmt.Errorf("invalid packetmode: %v", val)
+			}
+			f.PacketMode = int(tmp)
+			packetModeFound = true
+
+		case "transmode":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil || tmp > 1 {
+				return fmt.Errorf("invalid transmode: %v", val)
+			}
+			v2 := int(tmp)
+			f.TransMode = &v2
+
+		case "profile":
+			f.Profile = val
+
+		case "level":
+			f.Level = val
+
+		case "sublevel":
+			f.Sublevel = val
+
+		case "sampling":
+			f.Sampling = val
+
+		case "depth":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid depth: %v", val)
+			}
+			f.Depth = int(tmp)
+
+		case "width":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid width: %v", val)
+			}
+			f.Width = int(tmp)
+
+		case "height":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid height: %v", val)
+			}
+			f.Height = int(tmp)
+		}
+	}
+
+	if !packetModeFound {
+		return fmt.Errorf("packetmode is missing")
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *JPEGXS) Codec() string {
+	return "JPEG XS"
+}
+
+// ClockRate implements Format.
+func (f *JPEGXS) ClockRate() int {
+	return 90000
+}
+
+// PayloadType implements Format.
+func (f *JPEGXS) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *JPEGXS) RTPMap() string {
+	return "jxsv/90000"
+}
+
+// FMTP implements Format.
+func (f *JPEGXS) FMTP() map[string]string {
+	fmtp := map[string]string{
+		"packetmode": strconv.FormatInt(int64(f.PacketMode), 10),
+	}
+
+	if f.TransMode != nil {
+		fmtp["transmode"] = strconv.FormatInt(int64(*f.TransMode), 10)
+	}
+	if f.Profile != "" {
+		fmtp["profile"] = f.Profile
+	}
+	if f.Level != "" {
+		fmtp["level"] = f.Level
+	}
+	if f.Sublevel != "" {
+		fmtp["sublevel"] = f.Sublevel
+	}
+	if f.Sampling != "" {
+		fmtp["sampling"] = f.Sampling
+	}
+	if f.Depth != 0 {
+		fmtp["depth"] = strconv.FormatInt(int64(f.Depth), 10)
+	}
+	if f.Width != 0 {
+		fmtp["width"] = strconv.FormatInt(int64(f.Width), 10)
+	}
+	if f.Height != 0 {
+		fmtp["height"] = strconv.FormatInt(int64(f.Height), 10)
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *JPEGXS) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *JPEGXS) CreateDecoder() (*rtpjpegxs.Decoder, error) {
+	d := &rtpjpegxs.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *JPEGXS) CreateEncoder() (*rtpjpegxs.Encoder, error) {
+	e := &rtpjpegxs.Encoder{
+		PayloadType: f.PayloadTyp,
+		PacketMode:  f.PacketMode,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
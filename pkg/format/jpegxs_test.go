@@ -0,0 +1,39 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJPEGXSAttributes(t *testing.T) {
+	format := &JPEGXS{
+		PayloadTyp: 96,
+		PacketMode: 0,
+	}
+	require.Equal(t, "JPEG XS", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestJPEGXSDecEncoder(t *testing.T) {
+	format := &JPEGXS{
+		PayloadTyp: 96,
+		PacketMode: 0,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode([]byte{0xff, 0x10, 0xff, 0x50, 0x01, 0x02})
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	byts, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xff, 0x10, 0xff, 0x50, 0x01, 0x02}, byts)
+}
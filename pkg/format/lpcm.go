@@ -1,8 +1,10 @@
 package format
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pion/rtp"
 
@@ -17,11 +19,35 @@ type LPCM struct {
 	BitDepth     int
 	SampleRate   int
 	ChannelCount int
+
+	// order of channels, for instance "SMPTE2110.(51,ST)" (optional).
+	ChannelOrder string
+
+	// duration of audio contained in each packet (optional).
+	// It is filled with the ptime attribute of the media,
+	// and it is used by the encoder to size packets.
+	PacketTime time.Duration
+}
+
+func parsePacketTime(v string) (time.Duration, error) {
+	tmp, err := strconv.ParseFloat(v, 64)
+	if err != nil || tmp <= 0 {
+		return 0, fmt.Errorf("invalid ptime: %v", v)
+	}
+	return time.Duration(tmp * float64(time.Millisecond)), nil
 }
 
 func (f *LPCM) unmarshal(ctx *unmarshalContext) error {
 	f.PayloadTyp = ctx.payloadType
 
+	if ctx.ptime != "" {
+		var err error
+		f.PacketTime, err = parsePacketTime(ctx.ptime)
+		if err != nil {
+			return err
+		}
+	}
+
 	if ctx.payloadType == 10 {
 		f.BitDepth = 16
 		f.SampleRate = 44100
@@ -65,6 +91,10 @@ func (f *LPCM) unmarshal(ctx *unmarshalContext) error {
 		f.ChannelCount = 1
 	}
 
+	if val, ok := ctx.fmtp["channel-order"]; ok {
+		f.ChannelOrder = val
+	}
+
 	return nil
 }
 
@@ -103,7 +133,13 @@ func (f *LPCM) RTPMap() string {
 
 // FMTP implements Format.
 func (f *LPCM) FMTP() map[string]string {
-	return nil
+	if f.ChannelOrder == "" {
+		return nil
+	}
+
+	return map[string]string{
+		"channel-order": f.ChannelOrder,
+	}
 }
 
 // PTSEqualsDTS implements Format.
@@ -129,9 +165,11 @@ func (f *LPCM) CreateDecoder() (*rtplpcm.Decoder, error) {
 // CreateEncoder creates an encoder able to encode the content of the format.
 func (f *LPCM) CreateEncoder() (*rtplpcm.Encoder, error) {
 	e := &rtplpcm.Encoder{
-		PayloadType:  f.PayloadTyp,
-		BitDepth:     f.BitDepth,
-		ChannelCount: f.ChannelCount,
+		PayloadType:    f.PayloadTyp,
+		BitDepth:       f.BitDepth,
+		ChannelCount:   f.ChannelCount,
+		SampleRate:     f.SampleRate,
+		PacketDuration: f.PacketTime,
 	}
 
 	err := e.Init()
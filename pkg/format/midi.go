@@ -0,0 +1,89 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmidi"
+)
+
+// MIDI is the RTP format for MIDI commands.
+// The recovery journal is not supported.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6295
+type MIDI struct {
+	PayloadTyp uint8
+	ClockRat   int
+}
+
+func (f *MIDI) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	tmp, err := strconv.ParseUint(ctx.clock, 10, 31)
+	if err != nil || tmp == 0 {
+		return fmt.Errorf("invalid clock rate: '%s'", ctx.clock)
+	}
+	f.ClockRat = int(tmp)
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *MIDI) Codec() string {
+	return "MIDI"
+}
+
+// ClockRate implements Format.
+func (f *MIDI) ClockRate() int {
+	return f.ClockRat
+}
+
+// PayloadType implements Format.
+func (f *MIDI) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *MIDI) RTPMap() string {
+	return "rtp-midi/" + strconv.FormatInt(int64(f.ClockRat), 10)
+}
+
+// FMTP implements Format.
+func (f *MIDI) FMTP() map[string]string {
+	// RFC6295: j_sec=none signals that the recovery journal is not in use.
+	return map[string]string{
+		"j_sec": "none",
+	}
+}
+
+// PTSEqualsDTS implements Format.
+func (f *MIDI) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *MIDI) CreateDecoder() (*rtpmidi.Decoder, error) {
+	d := &rtpmidi.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *MIDI) CreateEncoder() (*rtpmidi.Encoder, error) {
+	e := &rtpmidi.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,51 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmidi"
+)
+
+func TestMIDIAttributes(t *testing.T) {
+	format := &MIDI{
+		PayloadTyp: 96,
+		ClockRat:   44100,
+	}
+	require.Equal(t, "MIDI", format.Codec())
+	require.Equal(t, 44100, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestMIDIDecEncoder(t *testing.T) {
+	format := &MIDI{
+		PayloadTyp: 96,
+		ClockRat:   44100,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	cmds := []*rtpmidi.Command{
+		{
+			Data: []byte{0x90, 0x3c, 0x40},
+		},
+		{
+			DeltaTime: 100,
+			Data:      []byte{0x80, 0x3c, 0x00},
+		},
+	}
+
+	pkts, err := enc.Encode(cmds)
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	cmds2, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, cmds, cmds2)
+}
@@ -7,9 +7,41 @@ import (
 
 	"github.com/pion/rtp"
 
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmultiopus"
 	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpsimpleaudio"
 )
 
+type opusStreamLayout struct {
+	streamCount        int
+	coupledStreamCount int
+	channelMapping     []uint8
+}
+
+// layouts of channel mapping family 1.
+// Specification: https://datatracker.ietf.org/doc/html/rfc7845#section-5.1.1.2
+var opusMappingFamily1 = map[int]opusStreamLayout{
+	3: {2, 1, []uint8{0, 2, 1}},
+	4: {2, 2, []uint8{0, 1, 2, 3}},
+	5: {3, 2, []uint8{0, 4, 1, 2, 3}},
+	6: {4, 2, []uint8{0, 4, 1, 2, 3, 5}},
+	7: {4, 3, []uint8{0, 4, 1, 2, 3, 5, 6}},
+	8: {5, 3, []uint8{0, 6, 1, 4, 5, 2, 3, 7}},
+}
+
+func parseOpusChannelMapping(val string) ([]uint8, error) {
+	var ret []uint8
+
+	for _, v := range strings.Split(val, ",") {
+		tmp, err := strconv.ParseUint(strings.TrimSpace(v), 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel_mapping: %v", val)
+		}
+		ret = append(ret, uint8(tmp))
+	}
+
+	return ret, nil
+}
+
 // Opus is the RTP format for the Opus codec.
 // Specification: https://datatracker.ietf.org/doc/html/rfc7587
 // Specification: https://webrtc-review.googlesource.com/c/src/+/129768
@@ -17,6 +49,17 @@ type Opus struct {
 	PayloadTyp   uint8
 	ChannelCount int
 
+	// number of streams, number of coupled (stereo) streams
+	// and mapping between channels and decoded streams (optional).
+	// They are used when there are more than 2 channels,
+	// and default to the layouts of channel mapping family 1.
+	StreamCount        int
+	CoupledStreamCount int
+	ChannelMapping     []uint8
+
+	// maximum input sample rate of the source (optional).
+	MaxCaptureRate int
+
 	// Deprecated: replaced by ChannelCount.
 	IsStereo bool
 }
@@ -45,11 +88,18 @@ func (f *Opus) unmarshal(ctx *unmarshalContext) error {
 		f.IsStereo = false
 
 		for key, val := range ctx.fmtp {
-			if key == "sprop-stereo" {
+			switch key {
+			case "sprop-stereo":
 				if val == "1" {
 					f.ChannelCount = 2
 					f.IsStereo = true
 				}
+
+			case "sprop-maxcapturerate":
+				err = f.unmarshalMaxCaptureRate(val)
+				if err != nil {
+					return err
+				}
 			}
 		}
 	} else {
@@ -63,17 +113,97 @@ func (f *Opus) unmarshal(ctx *unmarshalContext) error {
 			return fmt.Errorf("invalid sample rate: '%s'", tmp[0])
 		}
 
-		channelCount, err := strconv.ParseUint(tmp[1], 10, 31)
-		if err != nil {
+		channelCount, err := strconv.ParseUint(tmp[1], 10, 8)
+		if err != nil || channelCount == 0 {
 			return fmt.Errorf("invalid channel count: '%s'", tmp[1])
 		}
 
 		f.ChannelCount = int(channelCount)
+
+		for key, val := range ctx.fmtp {
+			switch key {
+			case "num_streams":
+				tmp, err := strconv.ParseUint(val, 10, 8)
+				if err != nil || tmp == 0 {
+					return fmt.Errorf("invalid num_streams: %v", val)
+				}
+				f.StreamCount = int(tmp)
+
+			case "coupled_streams":
+				tmp, err := strconv.ParseUint(val, 10, 8)
+				if err != nil {
+					return fmt.Errorf("invalid coupled_streams: %v", val)
+				}
+				f.CoupledStreamCount = int(tmp)
+
+			case "channel_mapping":
+				f.ChannelMapping, err = parseOpusChannelMapping(val)
+				if err != nil {
+					return err
+				}
+
+			case "sprop-maxcapturerate":
+				err = f.unmarshalMaxCaptureRate(val)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if f.StreamCount != 0 {
+			err = f.checkStreamLayout()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *Opus) unmarshalMaxCaptureRate(val string) error {
+	tmp, err := strconv.ParseUint(val, 10, 31)
+	if err != nil || tmp == 0 {
+		return fmt.Errorf("invalid sprop-maxcapturerate: %v", val)
+	}
+	f.MaxCaptureRate = int(tmp)
+	return nil
+}
+
+func (f *Opus) checkStreamLayout() error {
+	if f.CoupledStreamCount > f.StreamCount || (f.StreamCount+f.CoupledStreamCount) > 255 {
+		return fmt.Errorf("invalid stream count (%d) or coupled stream count (%d)",
+			f.StreamCount, f.CoupledStreamCount)
+	}
+
+	if len(f.ChannelMapping) != f.ChannelCount {
+		return fmt.Errorf("channel mapping has %d entries, but there are %d channels",
+			len(f.ChannelMapping), f.ChannelCount)
+	}
+
+	for _, v := range f.ChannelMapping {
+		// 255 is used for silent channels
+		if v != 255 && int(v) >= (f.StreamCount+f.CoupledStreamCount) {
+			return fmt.Errorf("invalid channel mapping entry: %d", v)
+		}
 	}
 
 	return nil
 }
 
+func (f *Opus) streamLayout() opusStreamLayout {
+	if f.StreamCount != 0 {
+		return opusStreamLayout{f.StreamCount, f.CoupledStreamCount, f.ChannelMapping}
+	}
+
+	if l, ok := opusMappingFamily1[f.ChannelCount]; ok {
+		return l
+	}
+
+	// assume 8
+	return opusMappingFamily1[8]
+}
+
 // Codec implements Format.
 func (f *Opus) Codec() string {
 	return "Opus"
@@ -104,8 +234,10 @@ func (f *Opus) RTPMap() string {
 
 // FMTP implements Format.
 func (f *Opus) FMTP() map[string]string {
+	maxCaptureRate := f.MaxCaptureRate
+
 	if f.ChannelCount <= 2 {
-		return map[string]string{
+		fmtp := map[string]string{
 			"sprop-stereo": func() string {
 				if f.ChannelCount == 2 || (f.ChannelCount == 0 && f.IsStereo) {
 					return "1"
@@ -113,56 +245,30 @@ func (f *Opus) FMTP() map[string]string {
 				return "0"
 			}(),
 		}
-	}
 
-	switch f.ChannelCount {
-	case 3:
-		return map[string]string{
-			"num_streams":          "2",
-			"coupled_streams":      "1",
-			"channel_mapping":      "0,2,1",
-			"sprop-maxcapturerate": "48000",
+		if maxCaptureRate != 0 {
+			fmtp["sprop-maxcapturerate"] = strconv.FormatInt(int64(maxCaptureRate), 10)
 		}
 
-	case 4:
-		return map[string]string{
-			"num_streams":          "2",
-			"coupled_streams":      "2",
-			"channel_mapping":      "0,1,2,3",
-			"sprop-maxcapturerate": "48000",
-		}
+		return fmtp
+	}
 
-	case 5:
-		return map[string]string{
-			"num_streams":          "3",
-			"coupled_streams":      "2",
-			"channel_mapping":      "0,4,1,2,3",
-			"sprop-maxcapturerate": "48000",
-		}
+	if maxCaptureRate == 0 {
+		maxCaptureRate = 48000
+	}
 
-	case 6:
-		return map[string]string{
-			"num_streams":          "4",
-			"coupled_streams":      "2",
-			"channel_mapping":      "0,4,1,2,3,5",
-			"sprop-maxcapturerate": "48000",
-		}
+	l := f.streamLayout()
 
-	case 7:
-		return map[string]string{
-			"num_streams":          "4",
-			"coupled_streams":      "3",
-			"channel_mapping":      "0,4,1,2,3,5,6",
-			"sprop-maxcapturerate": "48000",
-		}
+	channelMapping := make([]string, len(l.channelMapping))
+	for i, v := range l.channelMapping {
+		channelMapping[i] = strconv.FormatUint(uint64(v), 10)
+	}
 
-	default: // assume 8
-		return map[string]string{
-			"num_streams":          "5",
-			"coupled_streams":      "3",
-			"channel_mapping":      "0,6,1,4,5,2,3,7",
-			"sprop-maxcapturerate": "48000",
-		}
+	return map[string]string{
+		"num_streams":          strconv.FormatInt(int64(l.streamCount), 10),
+		"coupled_streams":      strconv.FormatInt(int64(l.coupledStreamCount), 10),
+		"channel_mapping":      strings.Join(channelMapping, ","),
+		"sprop-maxcapturerate": strconv.FormatInt(int64(maxCaptureRate), 10),
 	}
 }
 
@@ -196,3 +302,34 @@ func (f *Opus) CreateEncoder() (*rtpsimpleaudio.Encoder, error) {
 
 	return e, nil
 }
+
+// CreateMultistreamDecoder creates a decoder able to extract the packets of each stream
+// from the content of the format, when there are more than 2 channels.
+func (f *Opus) CreateMultistreamDecoder() (*rtpmultiopus.Decoder, error) {
+	d := &rtpmultiopus.Decoder{
+		StreamCount: f.streamLayout().streamCount,
+	}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateMultistreamEncoder creates an encoder able to encode the packets of each stream
+// into the content of the format, when there are more than 2 channels.
+func (f *Opus) CreateMultistreamEncoder() (*rtpmultiopus.Encoder, error) {
+	e := &rtpmultiopus.Encoder{
+		PayloadType: f.PayloadTyp,
+		StreamCount: f.streamLayout().streamCount,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
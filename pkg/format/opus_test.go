@@ -34,3 +34,31 @@ func TestOpusDecEncoder(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, byts)
 }
+
+func TestOpusMultistreamDecEncoder(t *testing.T) {
+	format := &Opus{
+		PayloadTyp:   96,
+		ChannelCount: 6,
+	}
+
+	enc, err := format.CreateMultistreamEncoder()
+	require.NoError(t, err)
+
+	packets := [][]byte{
+		{0x78, 0x01, 0x02},
+		{0x78, 0x03, 0x04},
+		{0x78, 0x05},
+		{0x78, 0x06, 0x07, 0x08},
+	}
+
+	pkt, err := enc.Encode(packets)
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkt.PayloadType)
+
+	dec, err := format.CreateMultistreamDecoder()
+	require.NoError(t, err)
+
+	packets2, err := dec.Decode(pkt)
+	require.NoError(t, err)
+	require.Equal(t, packets, packets2)
+}
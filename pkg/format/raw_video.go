@@ -0,0 +1,180 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtprawvideo"
+)
+
+// RawVideo is the RTP format for uncompressed video.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4175
+// Specification: https://pub.smpte.org/doc/st2110-20/
+type RawVideo struct {
+	PayloadTyp uint8
+
+	// color space and sampling of the image, for instance "YCbCr-4:2:2".
+	Sampling string
+
+	// image size.
+	Width  int
+	Height int
+
+	// bit depth of samples.
+	Depth int
+
+	// colorimetry, for instance "BT709-2" (optional).
+	Colorimetry string
+
+	// whether the video is interlaced.
+	Interlace bool
+
+	// exact frame rate, for instance "30000/1001" (optional).
+	ExactFrameRate string
+}
+
+func (f *RawVideo) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "sampling":
+			f.Sampling = val
+
+		case "width":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid width: %v", val)
+			}
+			f.Width = int(tmp)
+
+		case "height":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid height: %v", val)
+			}
+			f.Height = int(tmp)
+
+		case "depth":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid depth: %v", val)
+			}
+			f.Depth = int(tmp)
+
+		case "colorimetry":
+			f.Colorimetry = val
+
+		case "exactframerate":
+			f.ExactFrameRate = val
+		}
+	}
+
+	// interlace is a flag without value
+	for _, kv := range strings.Split(ctx.rawFMTP, ";") {
+		if strings.ToLower(strings.TrimSpace(kv)) == "interlace" {
+			f.Interlace = true
+		}
+	}
+
+	switch {
+	case f.Sampling == "":
+		return fmt.Errorf("sampling is missing")
+
+	case f.Width == 0:
+		return fmt.Errorf("width is missing")
+
+	case f.Height == 0:
+		return fmt.Errorf("height is missing")
+
+	case f.Depth == 0:
+		return fmt.Errorf("depth is missing")
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *RawVideo) Codec() string {
+	return "raw video"
+}
+
+// ClockRate implements Format.
+func (f *RawVideo) ClockRate() int {
+	return 90000
+}
+
+// PayloadType implements Format.
+func (f *RawVideo) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *RawVideo) RTPMap() string {
+	return "raw/90000"
+}
+
+// FMTP implements Format.
+func (f *RawVideo) FMTP() map[string]string {
+	fmtp := map[string]string{
+		"sampling": f.Sampling,
+		"width":    strconv.FormatInt(int64(f.Width), 10),
+		"height":   strconv.FormatInt(int64(f.Height), 10),
+		"depth":    strconv.FormatInt(int64(f.Depth), 10),
+	}
+
+	if f.Colorimetry != "" {
+		fmtp["colorimetry"] = f.Colorimetry
+	}
+	if f.Interlace {
+		fmtp["interlace"] = ""
+	}
+	if f.ExactFrameRate != "" {
+		fmtp["exactframerate"] = f.ExactFrameRate
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *RawVideo) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *RawVideo) CreateDecoder() (*rtprawvideo.Decoder, error) {
+	d := &rtprawvideo.Decoder{
+		Sampling: f.Sampling,
+		Depth:    f.Depth,
+		Width:    f.Width,
+		Height:   f.Height,
+	}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *RawVideo) CreateEncoder() (*rtprawvideo.Encoder, error) {
+	e := &rtprawvideo.Encoder{
+		PayloadType: f.PayloadTyp,
+		Sampling:    f.Sampling,
+		Depth:       f.Depth,
+		Width:       f.Width,
+		Height:      f.Height,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,50 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawVideoAttributes(t *testing.T) {
+	format := &RawVideo{
+		PayloadTyp: 96,
+		Sampling:   "YCbCr-4:2:2",
+		Width:      4,
+		Height:     2,
+		Depth:      8,
+	}
+	require.Equal(t, "raw video", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestRawVideoDecEncoder(t *testing.T) {
+	format := &RawVideo{
+		PayloadTyp: 96,
+		Sampling:   "YCbCr-4:2:2",
+		Width:      4,
+		Height:     2,
+		Depth:      8,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	frame := []byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+	}
+
+	pkts, err := enc.Encode(frame)
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	byts, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, frame, byts)
+}
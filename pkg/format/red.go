@@ -0,0 +1,113 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpred"
+)
+
+// RED is the RTP format for redundant audio or video data.
+// Packets contain a primary encoding and zero or more redundant encodings,
+// each one with its own payload type, that must be defined in the same media.
+// Specification: https://datatracker.ietf.org/doc/html/rfc2198
+type RED struct {
+	PayloadTyp   uint8
+	ClockRat     int
+	ChannelCount int
+
+	// payload types of encodings carried by packets, as listed in the fmtp attribute.
+	// It can be empty.
+	EncodingPayloadTypes []uint8
+}
+
+func (f *RED) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	tmp, err := strconv.ParseUint(strings.SplitN(ctx.clock, "/", 2)[0], 10, 31)
+	if err != nil || tmp == 0 {
+		return fmt.Errorf("invalid clock rate: '%s'", ctx.clock)
+	}
+	f.ClockRat = int(tmp)
+
+	if parts := strings.SplitN(ctx.clock, "/", 2); len(parts) == 2 {
+		tmp, err = strconv.ParseUint(parts[1], 10, 31)
+		if err != nil || tmp == 0 {
+			return fmt.Errorf("invalid channel count: '%s'", parts[1])
+		}
+		f.ChannelCount = int(tmp)
+	}
+
+	if ctx.rawFMTP != "" {
+		for _, pt := range strings.Split(strings.TrimSpace(ctx.rawFMTP), "/") {
+			tmp, err = strconv.ParseUint(pt, 10, 7)
+			if err != nil {
+				return fmt.Errorf("invalid encoding payload type: '%s'", pt)
+			}
+			f.EncodingPayloadTypes = append(f.EncodingPayloadTypes, uint8(tmp))
+		}
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *RED) Codec() string {
+	return "RED"
+}
+
+// ClockRate implements Format.
+func (f *RED) ClockRate() int {
+	return f.ClockRat
+}
+
+// PayloadType implements Format.
+func (f *RED) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *RED) RTPMap() string {
+	ret := "red/" + strconv.FormatInt(int64(f.ClockRat), 10)
+	if f.ChannelCount != 0 {
+		ret += "/" + strconv.FormatInt(int64(f.ChannelCount), 10)
+	}
+	return ret
+}
+
+// FMTP implements Format.
+// The payload types of encodings are returned as a single key without value.
+func (f *RED) FMTP() map[string]string {
+	if len(f.EncodingPayloadTypes) == 0 {
+		return nil
+	}
+
+	tmp := make([]string, len(f.EncodingPayloadTypes))
+	for i, pt := range f.EncodingPayloadTypes {
+		tmp[i] = strconv.FormatUint(uint64(pt), 10)
+	}
+
+	return map[string]string{
+		strings.Join(tmp, "/"): "",
+	}
+}
+
+// PTSEqualsDTS implements Format.
+func (f *RED) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to extract the primary encoding from packets.
+func (f *RED) CreateDecoder() (*rtpred.Decoder, error) {
+	d := &rtpred.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,43 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestREDAttributes(t *testing.T) {
+	format := &RED{
+		PayloadTyp:           120,
+		ClockRat:             48000,
+		ChannelCount:         2,
+		EncodingPayloadTypes: []uint8{111, 111},
+	}
+	require.Equal(t, "RED", format.Codec())
+	require.Equal(t, 48000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestREDDecoder(t *testing.T) {
+	format := &RED{
+		PayloadTyp:           120,
+		ClockRat:             48000,
+		ChannelCount:         2,
+		EncodingPayloadTypes: []uint8{111},
+	}
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	primary, redundant, err := dec.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			PayloadType: 120,
+		},
+		Payload: []byte{111, 0x01, 0x02},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint8(111), primary.PayloadType)
+	require.Equal(t, []byte{0x01, 0x02}, primary.Payload)
+	require.Empty(t, redundant)
+}
@@ -0,0 +1,293 @@
+package rtpamr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bluenviron/mediacommon/pkg/bits"
+	"github.com/pion/rtp"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+type tocEntry struct {
+	ft uint8
+	q  bool
+}
+
+// Decoder is a RTP/AMR and RTP/AMR-WB decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4867
+type Decoder struct {
+	// whether the payload is AMR-WB.
+	WideBand bool
+
+	// whether the payload is in octet-aligned mode.
+	OctetAligned bool
+
+	// channel count.
+	// It defaults to 1.
+	ChannelCount int
+
+	// whether frames are preceded by CRCs (octet-aligned mode only).
+	CRC bool
+
+	// whether frames are interleaved (octet-aligned mode only).
+	Interleaving bool
+
+	groupTimestamp uint32
+	groupLength    int
+	groupReceived  uint32
+	groupBlocks    [][][]byte
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	if d.ChannelCount == 0 {
+		d.ChannelCount = 1
+	}
+
+	if !d.OctetAligned && (d.CRC || d.Interleaving) {
+		return fmt.Errorf("CRC and interleaving require the octet-aligned mode")
+	}
+
+	return nil
+}
+
+func (d *Decoder) resetGroup() {
+	d.groupBlocks = nil
+	d.groupReceived = 0
+}
+
+func (d *Decoder) readTOCBandwidthEfficient(buf []byte, pos *int) ([]tocEntry, error) {
+	var toc []tocEntry
+
+	for {
+		err := bits.HasSpace(buf, *pos, 6)
+		if err != nil {
+			return nil, err
+		}
+
+		f := bits.ReadFlagUnsafe(buf, pos)
+		ft := uint8(bits.ReadBitsUnsafe(buf, pos, 4))
+		q := bits.ReadFlagUnsafe(buf, pos)
+
+		if !frameTypeValid(d.WideBand, ft) {
+			return nil, fmt.Errorf("invalid frame type: %d", ft)
+		}
+
+		toc = append(toc, tocEntry{ft: ft, q: q})
+
+		if !f {
+			break
+		}
+
+		if len(toc) >= maxFramesPerGroup {
+			return nil, fmt.Errorf("frame count exceeds maximum allowed (%d)", maxFramesPerGroup)
+		}
+	}
+
+	return toc, nil
+}
+
+func (d *Decoder) decodeBandwidthEfficient(payload []byte) ([][]byte, error) {
+	// skip CMR
+	pos := 4
+
+	toc, err := d.readTOCBandwidthEfficient(payload, &pos)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make([][]byte, len(toc))
+
+	for i, entry := range toc {
+		n := frameTypeBits(d.WideBand, entry.ft)
+
+		err = bits.HasSpace(payload, pos, n)
+		if err != nil {
+			return nil, err
+		}
+
+		frame := make([]byte, frameSize(d.WideBand, entry.ft))
+		frame[0] = frameHeader(entry.ft, entry.q)
+
+		j := 1
+		for ; n >= 8; n -= 8 {
+			frame[j] = uint8(bits.ReadBitsUnsafe(payload, &pos, 8))
+			j++
+		}
+		if n > 0 {
+			frame[j] = uint8(bits.ReadBitsUnsafe(payload, &pos, n)) << (8 - n)
+		}
+
+		frames[i] = frame
+	}
+
+	return frames, nil
+}
+
+func (d *Decoder) decodeOctetAligned(payload []byte, pos int) ([][]byte, error) {
+	var toc []tocEntry
+
+	for {
+		if len(payload) <= pos {
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		b := payload[pos]
+		pos++
+
+		ft := (b >> 3) & 0x0F
+		if !frameTypeValid(d.WideBand, ft) {
+			return nil, fmt.Errorf("invalid frame type: %d", ft)
+		}
+
+		toc = append(toc, tocEntry{ft: ft, q: (b>>2)&0x01 != 0})
+
+		if (b >> 7) == 0 {
+			break
+		}
+
+		if len(toc) >= maxFramesPerGroup {
+			return nil, fmt.Errorf("frame count exceeds maximum allowed (%d)", maxFramesPerGroup)
+		}
+	}
+
+	if d.CRC {
+		// CRCs are present for frames that contain speech bits only
+		for _, entry := range toc {
+			if frameTypeBits(d.WideBand, entry.ft) != 0 {
+				pos++
+			}
+		}
+	}
+
+	frames := make([][]byte, len(toc))
+
+	for i, entry := range toc {
+		size := frameSize(d.WideBand, entry.ft) - 1
+
+		if len(payload[min(pos, len(payload)):]) < size {
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		frame := make([]byte, 1+size)
+		frame[0] = frameHeader(entry.ft, entry.q)
+		copy(frame[1:], payload[pos:])
+		pos += size
+
+		frames[i] = frame
+	}
+
+	return frames, nil
+}
+
+func (d *Decoder) decodeInterleaved(pkt *rtp.Packet) ([][]byte, error) {
+	if len(pkt.Payload) < 2 {
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	ill := int(pkt.Payload[1] >> 4)
+	ilp := int(pkt.Payload[1] & 0x0F)
+
+	if ilp > ill {
+		return nil, fmt.Errorf("invalid interleaving index: %d", ilp)
+	}
+
+	// skip CMR and interleaving header
+	frames, err := d.decodeOctetAligned(pkt.Payload, 2)
+	if err != nil {
+		d.resetGroup()
+		return nil, err
+	}
+
+	if (len(frames) % d.ChannelCount) != 0 {
+		d.resetGroup()
+		return nil, fmt.Errorf("frame count is not a multiple of channel count")
+	}
+
+	// the timestamp of a packet is the one of its first frame-block,
+	// that is placed at position ILP of the interleaving group.
+	groupTimestamp := pkt.Timestamp - uint32(ilp)*samplesPerFrame(d.WideBand)
+
+	var groupErr error
+
+	if d.groupReceived != 0 && (groupTimestamp != d.groupTimestamp || ill != d.groupLength ||
+		(d.groupReceived&(1<<ilp)) != 0) {
+		d.resetGroup()
+		groupErr = fmt.Errorf("discarding interleaving group since a RTP packet is missing")
+	}
+
+	if d.groupReceived == 0 {
+		d.groupTimestamp = groupTimestamp
+		d.groupLength = ill
+	}
+
+	blockCount := len(frames) / d.ChannelCount
+
+	for i := 0; i < blockCount; i++ {
+		index := ilp + i*(ill+1)
+
+		if index >= maxFramesPerGroup {
+			d.resetGroup()
+			return nil, fmt.Errorf("frame count exceeds maximum allowed (%d)", maxFramesPerGroup)
+		}
+
+		if index >= len(d.groupBlocks) {
+			d.groupBlocks = append(d.groupBlocks, make([][][]byte, index+1-len(d.groupBlocks))...)
+		}
+
+		d.groupBlocks[index] = frames[i*d.ChannelCount : (i+1)*d.ChannelCount]
+	}
+
+	d.groupReceived |= 1 << ilp
+
+	if d.groupReceived != (1<<(ill+1))-1 {
+		if groupErr != nil {
+			return nil, groupErr
+		}
+		return nil, ErrMorePacketsNeeded
+	}
+
+	var ret [][]byte
+
+	for _, block := range d.groupBlocks {
+		ret = append(ret, block...)
+	}
+
+	d.resetGroup()
+
+	return ret, nil
+}
+
+// Decode decodes frames from a RTP packet.
+// Frames are returned in the storage format, in which each frame is made of a header byte
+// (containing frame type and quality indicator), followed by speech bits padded to an octet boundary.
+// In case of multiple channels, frames of the same frame-block are consecutive.
+// When interleaving is in use, frames are returned once all packets of an interleaving group
+// have been received, and the first frame corresponds to the packet whose interleaving index is zero.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	if d.Interleaving {
+		return d.decodeInterleaved(pkt)
+	}
+
+	var frames [][]byte
+	var err error
+
+	if d.OctetAligned {
+		// skip CMR
+		frames, err = d.decodeOctetAligned(pkt.Payload, 1)
+	} else {
+		frames, err = d.decodeBandwidthEfficient(pkt.Payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if (len(frames) % d.ChannelCount) != 0 {
+		return nil, fmt.Errorf("frame count is not a multiple of channel count")
+	}
+
+	return frames, nil
+}
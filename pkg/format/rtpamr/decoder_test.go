@@ -0,0 +1,137 @@
+package rtpamr
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				WideBand:     ca.wideBand,
+				OctetAligned: ca.octetAligned,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var frames [][]byte
+
+			for _, pkt := range ca.pkts {
+				partial, err := d.Decode(pkt)
+				require.NoError(t, err)
+				frames = append(frames, partial...)
+			}
+
+			require.Equal(t, ca.frames, frames)
+		})
+	}
+}
+
+func TestDecodeInterleaved(t *testing.T) {
+	d := &Decoder{
+		OctetAligned: true,
+		Interleaving: true,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      0,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0xf0, 0x10, 0xfc, 0x7c},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	frames, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17646,
+			Timestamp:      160,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: mergeBytes(
+			[]byte{0xf0, 0x11, 0xbc, 0x3c},
+			frame122[1:],
+			frame122[1:],
+		),
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x7c}, frame122, {0x7c}, frame122}, frames)
+}
+
+func TestDecodeErrorMissingPacket(t *testing.T) {
+	d := &Decoder{
+		OctetAligned: true,
+		Interleaving: true,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      0,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0xf0, 0x10, 0xfc, 0x7c},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17647,
+			Timestamp:      320,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0xf0, 0x10, 0xfc, 0x7c},
+	})
+	require.EqualError(t, err, "discarding interleaving group since a RTP packet is missing")
+}
+
+func FuzzDecoder(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		for _, pkt := range ca.pkts {
+			f.Add(pkt.Payload, ca.wideBand, ca.octetAligned, false)
+		}
+	}
+
+	f.Fuzz(func(_ *testing.T, b []byte, wideBand bool, octetAligned bool, interleaving bool) {
+		d := &Decoder{
+			WideBand:     wideBand,
+			OctetAligned: octetAligned,
+			CRC:          octetAligned,
+			Interleaving: octetAligned && interleaving,
+		}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
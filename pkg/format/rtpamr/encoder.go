@@ -0,0 +1,284 @@
+package rtpamr
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/bluenviron/mediacommon/pkg/bits"
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+
+	// no mode request is present.
+	cmrNoRequest = 15
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/AMR and RTP/AMR-WB encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4867
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// whether the payload is AMR-WB.
+	WideBand bool
+
+	// whether the payload is in octet-aligned mode.
+	OctetAligned bool
+
+	// channel count.
+	// It defaults to 1.
+	ChannelCount int
+
+	// whether frames are interleaved (octet-aligned mode only).
+	Interleaving bool
+
+	// interleaving length, that is the number of packets
+	// of an interleaving group minus one.
+	// It is used when Interleaving is true.
+	InterleavingLength int
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.ChannelCount == 0 {
+		e.ChannelCount = 1
+	}
+
+	if e.Interleaving {
+		if !e.OctetAligned {
+			return fmt.Errorf("interleaving requires the octet-aligned mode")
+		}
+
+		if e.InterleavingLength < 0 || e.InterleavingLength > 15 {
+			return fmt.Errorf("invalid interleaving length: %d", e.InterleavingLength)
+		}
+	}
+
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	return nil
+}
+
+func (e *Encoder) headerSize() int {
+	if e.Interleaving {
+		return 2
+	}
+	return 1
+}
+
+func (e *Encoder) payloadSize(frames [][]byte) int {
+	if e.OctetAligned {
+		n := e.headerSize()
+		for _, frame := range frames {
+			n += len(frame)
+		}
+		return n
+	}
+
+	n := 4
+	for _, frame := range frames {
+		n += 6 + frameTypeBits(e.WideBand, (frame[0]>>3)&0x0F)
+	}
+	return (n + 7) / 8
+}
+
+func (e *Encoder) writePayload(frames [][]byte, ilp int) []byte {
+	payload := make([]byte, e.payloadSize(frames))
+
+	if e.OctetAligned {
+		payload[0] = cmrNoRequest << 4
+		pos := 1
+
+		if e.Interleaving {
+			payload[1] = byte(e.InterleavingLength<<4) | byte(ilp)
+			pos++
+		}
+
+		for i, frame := range frames {
+			payload[pos] = frame[0] & 0x7C
+			if i != (len(frames) - 1) {
+				payload[pos] |= 1 << 7
+			}
+			pos++
+		}
+
+		for _, frame := range frames {
+			pos += copy(payload[pos:], frame[1:])
+		}
+
+		return payload
+	}
+
+	pos := 0
+	bits.WriteBitsUnsafe(payload, &pos, cmrNoRequest, 4)
+
+	for i, frame := range frames {
+		f := uint64(0)
+		if i != (len(frames) - 1) {
+			f = 1
+		}
+		bits.WriteBitsUnsafe(payload, &pos, f<<5|uint64(frame[0]>>2)&0x1F, 6)
+	}
+
+	for _, frame := range frames {
+		n := frameTypeBits(e.WideBand, (frame[0]>>3)&0x0F)
+		j := 1
+
+		for ; n >= 8; n -= 8 {
+			bits.WriteBitsUnsafe(payload, &pos, uint64(frame[j]), 8)
+			j++
+		}
+		if n > 0 {
+			bits.WriteBitsUnsafe(payload, &pos, uint64(frame[j]>>(8-n)), n)
+		}
+	}
+
+	return payload
+}
+
+func (e *Encoder) newPacket(payload []byte, timestamp uint32) *rtp.Packet {
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    e.PayloadType,
+			SequenceNumber: e.sequenceNumber,
+			Timestamp:      timestamp,
+			SSRC:           *e.SSRC,
+			Marker:         false,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return pkt
+}
+
+func (e *Encoder) encodeInterleaved(frames [][]byte) ([]*rtp.Packet, error) {
+	blockCount := len(frames) / e.ChannelCount
+	packetCount := e.InterleavingLength + 1
+
+	if (blockCount % packetCount) != 0 {
+		return nil, fmt.Errorf("frame-block count is not a multiple of the interleaving group size")
+	}
+
+	ret := make([]*rtp.Packet, packetCount)
+
+	for ilp := range ret {
+		var packetFrames [][]byte
+
+		for i := ilp; i < blockCount; i += packetCount {
+			packetFrames = append(packetFrames, frames[i*e.ChannelCount:(i+1)*e.ChannelCount]...)
+		}
+
+		if e.payloadSize(packetFrames) > e.PayloadMaxSize {
+			return nil, fmt.Errorf("frames are too big")
+		}
+
+		// the timestamp of a packet is the one of its first frame-block.
+		ret[ilp] = e.newPacket(e.writePayload(packetFrames, ilp),
+			uint32(ilp)*samplesPerFrame(e.WideBand))
+	}
+
+	return ret, nil
+}
+
+// Encode encodes frames into RTP packets.
+// Frames must be in the storage format, in which each frame is made of a header byte
+// (containing frame type and quality indicator), followed by speech bits padded to an octet boundary.
+// In case of multiple channels, frames of the same frame-block must be consecutive.
+// When interleaving is in use, frames must fill one or more interleaving groups.
+func (e *Encoder) Encode(frames [][]byte) ([]*rtp.Packet, error) {
+	if (len(frames) % e.ChannelCount) != 0 {
+		return nil, fmt.Errorf("frame count is not a multiple of channel count")
+	}
+
+	if len(frames) > maxFramesPerGroup {
+		return nil, fmt.Errorf("frame count exceeds maximum allowed (%d)", maxFramesPerGroup)
+	}
+
+	for _, frame := range frames {
+		if len(frame) == 0 {
+			return nil, fmt.Errorf("invalid frame")
+		}
+
+		ft := (frame[0] >> 3) & 0x0F
+		if !frameTypeValid(e.WideBand, ft) || len(frame) != frameSize(e.WideBand, ft) {
+			return nil, fmt.Errorf("invalid frame")
+		}
+	}
+
+	if e.Interleaving {
+		return e.encodeInterleaved(frames)
+	}
+
+	var ret []*rtp.Packet
+	var batch [][]byte
+	timestamp := uint32(0)
+
+	for i := 0; i < len(frames); i += e.ChannelCount {
+		block := frames[i : i+e.ChannelCount]
+
+		if e.payloadSize(append(batch, block...)) > e.PayloadMaxSize {
+			if batch == nil {
+				return nil, fmt.Errorf("frames are too big")
+			}
+
+			ret = append(ret, e.newPacket(e.writePayload(batch, 0), timestamp))
+			timestamp += uint32(len(batch)/e.ChannelCount) * samplesPerFrame(e.WideBand)
+			batch = nil
+		}
+
+		batch = append(batch, block...)
+	}
+
+	if batch != nil {
+		ret = append(ret, e.newPacket(e.writePayload(batch, 0), timestamp))
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,268 @@
+package rtpamr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+// AMR 12.2 kbit/s frame
+var frame122 = mergeBytes(
+	[]byte{0x3c},
+	bytes.Repeat([]byte{0x55}, 30),
+	[]byte{0x50},
+)
+
+// AMR-WB 12.65 kbit/s frame
+var frame1265 = mergeBytes(
+	[]byte{0x14},
+	bytes.Repeat([]byte{0xaa}, 31),
+	[]byte{0x80},
+)
+
+var cases = []struct {
+	name         string
+	wideBand     bool
+	octetAligned bool
+	frames       [][]byte
+	pkts         []*rtp.Packet
+}{
+	{
+		"bandwidth-efficient",
+		false,
+		false,
+		[][]byte{frame122, {0x7c}},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0xfb, 0xdf},
+					bytes.Repeat([]byte{0x55}, 30),
+					[]byte{0x50},
+				),
+			},
+		},
+	},
+	{
+		"octet-aligned",
+		false,
+		true,
+		[][]byte{frame122, {0x7c}},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0xf0, 0xbc, 0x7c},
+					bytes.Repeat([]byte{0x55}, 30),
+					[]byte{0x50},
+				),
+			},
+		},
+	},
+	{
+		"wideband bandwidth-efficient",
+		true,
+		false,
+		[][]byte{frame1265},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0xf1, 0x6a},
+					bytes.Repeat([]byte{0xaa}, 30),
+					[]byte{0xa0},
+				),
+			},
+		},
+	},
+	{
+		"wideband octet-aligned",
+		true,
+		true,
+		[][]byte{frame1265},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0xf0, 0x14},
+					bytes.Repeat([]byte{0xaa}, 31),
+					[]byte{0x80},
+				),
+			},
+		},
+	},
+	{
+		"multiple packets",
+		false,
+		true,
+		func() [][]byte {
+			frames := make([][]byte, 50)
+			for i := range frames {
+				frames[i] = frame122
+			}
+			return frames
+		}(),
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0xf0},
+					bytes.Repeat([]byte{0xbc}, 44),
+					[]byte{0x3c},
+					bytes.Repeat(frame122[1:], 45),
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      7200,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0xf0},
+					bytes.Repeat([]byte{0xbc}, 4),
+					[]byte{0x3c},
+					bytes.Repeat(frame122[1:], 5),
+				),
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           96,
+				WideBand:              ca.wideBand,
+				OctetAligned:          ca.octetAligned,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.frames)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeInterleaved(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		OctetAligned:          true,
+		Interleaving:          true,
+		InterleavingLength:    1,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode([][]byte{{0x7c}, frame122, {0x7c}, frame122})
+	require.NoError(t, err)
+	require.Equal(t, []*rtp.Packet{
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      0,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0xf0, 0x10, 0xfc, 0x7c},
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				Timestamp:      160,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: mergeBytes(
+				[]byte{0xf0, 0x11, 0xbc, 0x3c},
+				frame122[1:],
+				frame122[1:],
+			),
+		},
+	}, pkts)
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
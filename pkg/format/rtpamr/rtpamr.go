@@ -0,0 +1,57 @@
+// Package rtpamr contains a RTP/AMR and RTP/AMR-WB decoder and encoder.
+package rtpamr
+
+const (
+	frameTypeNoData = 15
+
+	// maximum number of frames contained in a packet or in an interleaving group.
+	maxFramesPerGroup = 256
+)
+
+// size of AMR speech frames in bits, by frame type.
+var frameBitsNB = [16]int{
+	95, 103, 118, 134, 148, 159, 204, 244, 39, 43, 38, 37, 0, 0, 0, 0,
+}
+
+// size of AMR-WB speech frames in bits, by frame type.
+var frameBitsWB = [16]int{
+	132, 177, 253, 285, 317, 365, 397, 461, 477, 40, 0, 0, 0, 0, 0, 0,
+}
+
+func frameTypeValid(wideBand bool, ft uint8) bool {
+	if wideBand {
+		return ft <= 9 || ft >= 14
+	}
+	return ft <= 11 || ft == frameTypeNoData
+}
+
+func frameTypeBits(wideBand bool, ft uint8) int {
+	if wideBand {
+		return frameBitsWB[ft]
+	}
+	return frameBitsNB[ft]
+}
+
+// samples per frame, expressed in clock rate units.
+func samplesPerFrame(wideBand bool) uint32 {
+	if wideBand {
+		return 320
+	}
+	return 160
+}
+
+// frames are exchanged in the storage format, in which each frame is made
+// of a header byte, containing frame type and quality indicator,
+// followed by speech bits, padded to an octet boundary.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4867#section-5.3
+func frameHeader(ft uint8, q bool) byte {
+	b := ft << 3
+	if q {
+		b |= 1 << 2
+	}
+	return b
+}
+
+func frameSize(wideBand bool, ft uint8) int {
+	return 1 + (frameTypeBits(wideBand, ft)+7)/8
+}
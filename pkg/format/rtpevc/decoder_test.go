@@ -0,0 +1,130 @@
+package rtpevc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var nalus [][]byte
+
+			for _, pkt := range ca.pkts {
+				clone := pkt.Clone()
+
+				addNALUs, err := d.Decode(pkt)
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+
+				if errors.Is(err, ErrMorePacketsNeeded) {
+					continue
+				}
+
+				require.NoError(t, err)
+				nalus = append(nalus, addNALUs...)
+			}
+
+			require.Equal(t, ca.nalus, nalus)
+		})
+	}
+}
+
+func TestDecoderErrorLimit(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	for i := 0; i <= maxNALUsPerAccessUnit; i++ {
+		_, err = d.Decode(&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{1, 2, 3, 4},
+		})
+	}
+
+	require.EqualError(t, err, "NALU count exceeds maximum allowed (21)")
+}
+
+func TestDecodeErrorMissingPacket(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x73, 0x02, 0x80, 0x03, 0x04},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17647,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x73, 0x02, 0x00, 0x04},
+	})
+	require.EqualError(t, err, "discarding frame since a RTP packet is missing")
+}
+
+func FuzzDecoder(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		for i, pkt := range ca.pkts {
+			next := ca.pkts[(i+1)%len(ca.pkts)]
+			f.Add(pkt.Payload, next.Payload)
+		}
+	}
+
+	f.Fuzz(func(_ *testing.T, a []byte, b []byte) {
+		d := &Decoder{}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: a,
+		})
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
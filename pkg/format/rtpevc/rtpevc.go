@@ -0,0 +1,16 @@
+// Package rtpevc contains a RTP/EVC decoder and encoder.
+package rtpevc
+
+const (
+	// maxAccessUnitSize is the maximum size of an access unit.
+	maxAccessUnitSize = 8 * 1024 * 1024
+
+	// maxNALUsPerAccessUnit is the maximum number of NALUs per access unit.
+	maxNALUsPerAccessUnit = 21
+)
+
+// NALU types of RTP/EVC payload headers.
+const (
+	naluTypeAggregationUnit   = 56
+	naluTypeFragmentationUnit = 57
+)
@@ -0,0 +1,101 @@
+package rtpfragmentedaudio
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var frame []byte
+
+			for _, pkt := range ca.pkts {
+				frame, err = d.Decode(pkt)
+				if errors.Is(err, ErrMorePacketsNeeded) {
+					continue
+				}
+
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, ca.frame, frame)
+		})
+	}
+}
+
+func TestDecodeErrorMissingPacket(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x01, 0x02, 0x03, 0x04},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17647,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x01, 0x02, 0x03, 0x04},
+	})
+	require.EqualError(t, err, "discarding frame since a RTP packet is missing")
+}
+
+func FuzzDecoder(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		for i, pkt := range ca.pkts {
+			next := ca.pkts[(i+1)%len(ca.pkts)]
+			f.Add(pkt.Payload, pkt.Marker, next.Payload, next.Marker)
+		}
+	}
+
+	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool) {
+		d := &Decoder{}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         am,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: a,
+		})
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         bm,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
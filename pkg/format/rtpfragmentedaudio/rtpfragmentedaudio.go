@@ -0,0 +1,8 @@
+// Package rtpfragmentedaudio contains a RTP decoder and encoder for audio codecs
+// whose frames are split into one or more packets, with the marker bit set on the last one.
+package rtpfragmentedaudio
+
+const (
+	// maximum size of a frame.
+	maxFrameSize = 128 * 1024
+)
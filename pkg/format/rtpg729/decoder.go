@@ -0,0 +1,51 @@
+package rtpg729
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// Decoder is a RTP/G729 decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc3551#section-4.5.6
+type Decoder struct{}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+// Decode decodes frames from a RTP packet.
+// Speech frames are 10 bytes long. The last frame can be a 2-bytes annex B SID frame.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	plen := len(pkt.Payload)
+
+	if plen == 0 {
+		return nil, fmt.Errorf("payload is empty")
+	}
+
+	rem := plen % frameSize
+	if rem != 0 && rem != sidFrameSize {
+		return nil, fmt.Errorf("received payload of wrong size")
+	}
+
+	n := plen / frameSize
+	if rem != 0 {
+		n++
+	}
+
+	frames := make([][]byte, n)
+	pos := 0
+
+	for i := range frames {
+		size := frameSize
+		if size > plen-pos {
+			size = plen - pos
+		}
+
+		frames[i] = pkt.Payload[pos : pos+size]
+		pos += size
+	}
+
+	return frames, nil
+}
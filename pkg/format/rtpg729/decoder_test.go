@@ -0,0 +1,54 @@
+package rtpg729
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var frames [][]byte
+
+			for _, pkt := range ca.pkts {
+				partial, err := d.Decode(pkt)
+				require.NoError(t, err)
+				frames = append(frames, partial...)
+			}
+
+			require.Equal(t, ca.frames, frames)
+		})
+	}
+}
+
+func FuzzDecoder(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		for _, pkt := range ca.pkts {
+			f.Add(pkt.Payload)
+		}
+	}
+
+	f.Fuzz(func(_ *testing.T, b []byte) {
+		d := &Decoder{}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    18,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
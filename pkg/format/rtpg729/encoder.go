@@ -0,0 +1,118 @@
+package rtpg729
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/G729 encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc3551#section-4.5.6
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+	maxFrameCount  int
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	e.maxFrameCount = e.PayloadMaxSize / frameSize
+
+	if e.maxFrameCount == 0 {
+		return fmt.Errorf("PayloadMaxSize is too small")
+	}
+
+	return nil
+}
+
+// Encode encodes frames into RTP packets.
+// Speech frames must be 10 bytes long. The last frame can be a 2-bytes annex B SID frame.
+func (e *Encoder) Encode(frames [][]byte) ([]*rtp.Packet, error) {
+	for i, frame := range frames {
+		if len(frame) != frameSize && (i != (len(frames)-1) || len(frame) != sidFrameSize) {
+			return nil, fmt.Errorf("invalid frame size: %d", len(frame))
+		}
+	}
+
+	var ret []*rtp.Packet
+	timestamp := uint32(0)
+
+	for len(frames) != 0 {
+		n := e.maxFrameCount
+		if n > len(frames) {
+			n = len(frames)
+		}
+
+		var payload []byte
+		for _, frame := range frames[:n] {
+			payload = append(payload, frame...)
+		}
+
+		ret = append(ret, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      timestamp,
+				SSRC:           *e.SSRC,
+				Marker:         false,
+			},
+			Payload: payload,
+		})
+
+		e.sequenceNumber++
+		timestamp += uint32(n) * samplesPerFrame
+		frames = frames[n:]
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,126 @@
+package rtpg729
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+var cases = []struct {
+	name   string
+	frames [][]byte
+	pkts   []*rtp.Packet
+}{
+	{
+		"single",
+		[][]byte{
+			bytes.Repeat([]byte{0x01}, 10),
+			bytes.Repeat([]byte{0x02}, 10),
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    18,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: append(bytes.Repeat([]byte{0x01}, 10), bytes.Repeat([]byte{0x02}, 10)...),
+			},
+		},
+	},
+	{
+		"sid",
+		[][]byte{
+			bytes.Repeat([]byte{0x01}, 10),
+			{0x03, 0x04},
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    18,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: append(bytes.Repeat([]byte{0x01}, 10), 0x03, 0x04),
+			},
+		},
+	},
+	{
+		"multiple",
+		func() [][]byte {
+			frames := make([][]byte, 147)
+			for i := range frames {
+				frames[i] = bytes.Repeat([]byte{0x01}, 10)
+			}
+			return frames
+		}(),
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    18,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01}, 1460),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    18,
+					SequenceNumber: 17646,
+					Timestamp:      11680,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01}, 10),
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           18,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.frames)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 18,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
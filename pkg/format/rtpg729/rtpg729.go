@@ -0,0 +1,13 @@
+// Package rtpg729 contains a RTP/G729 decoder and encoder.
+package rtpg729
+
+const (
+	// size of a speech frame, that contains 10ms of audio.
+	frameSize = 10
+
+	// size of an annex B SID (silence insertion descriptor) frame.
+	sidFrameSize = 2
+
+	// samples per frame, expressed in clock rate units.
+	samplesPerFrame = 80
+)
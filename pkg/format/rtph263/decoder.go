@@ -0,0 +1,125 @@
+package rtph263
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a fragmented frame and we didn't received anything before.
+// It's normal to receive this when decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+func joinFragments(fragments [][]byte, size int) []byte {
+	ret := make([]byte, size)
+	n := 0
+	for _, p := range fragments {
+		n += copy(ret[n:], p)
+	}
+	return ret
+}
+
+// Decoder is a RTP/H263 decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4629
+type Decoder struct {
+	fragments          [][]byte
+	fragmentsSize      int
+	fragmentNextSeqNum uint16
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragments = d.fragments[:0]
+	d.fragmentsSize = 0
+}
+
+func decodePayload(payload []byte) ([]byte, bool, error) {
+	if len(payload) < 2 {
+		return nil, false, fmt.Errorf("payload is too short")
+	}
+
+	p := (payload[0] & 0x04) != 0
+	v := (payload[0] & 0x02) != 0
+	plen := int(payload[0]&0x01)<<5 | int(payload[1]>>3)
+
+	n := 2 + plen
+	if v {
+		n++
+	}
+
+	if len(payload) < n {
+		return nil, false, fmt.Errorf("payload is too short")
+	}
+
+	payload = payload[n:]
+
+	// the two zero bytes of the start code are omitted
+	if p {
+		buf := make([]byte, 2+len(payload))
+		copy(buf[2:], payload)
+		payload = buf
+	}
+
+	return payload, p, nil
+}
+
+// Decode decodes a frame from a RTP packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	data, start, err := decodePayload(pkt.Payload)
+	if err != nil {
+		d.resetFragments()
+		return nil, err
+	}
+
+	var frame []byte
+
+	if d.fragmentsSize == 0 {
+		if !start {
+			return nil, ErrNonStartingPacketAndNoPrevious
+		}
+
+		if pkt.Marker {
+			frame = data
+		} else {
+			d.fragmentsSize = len(data)
+			d.fragments = append(d.fragments, data)
+			d.fragmentNextSeqNum = pkt.SequenceNumber + 1
+			return nil, ErrMorePacketsNeeded
+		}
+	} else {
+		if pkt.SequenceNumber != d.fragmentNextSeqNum {
+			d.resetFragments()
+			return nil, fmt.Errorf("discarding frame since a RTP packet is missing")
+		}
+
+		d.fragmentsSize += len(data)
+
+		if d.fragmentsSize > maxFrameSize {
+			d.resetFragments()
+			return nil, fmt.Errorf("frame size (%d) is too big, maximum is %d", d.fragmentsSize, maxFrameSize)
+		}
+
+		d.fragments = append(d.fragments, data)
+		d.fragmentNextSeqNum++
+
+		if !pkt.Marker {
+			return nil, ErrMorePacketsNeeded
+		}
+
+		frame = joinFragments(d.fragments, d.fragmentsSize)
+		d.resetFragments()
+	}
+
+	return frame, nil
+}
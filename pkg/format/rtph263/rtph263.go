@@ -0,0 +1,7 @@
+// Package rtph263 contains a RTP/H263 decoder and encoder.
+package rtph263
+
+const (
+	// maximum size of a frame.
+	maxFrameSize = 1 * 1024 * 1024
+)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/pion/rtp"
 
@@ -38,28 +39,113 @@ func isAllZero(buf []byte) bool {
 	return true
 }
 
+func parseAggregated(payload []byte, typ h264.NALUType) ([][]byte, error) {
+	var nalus [][]byte
+
+	for {
+		if len(payload) < 2 {
+			return nil, fmt.Errorf("invalid %v packet (invalid size)", typ)
+		}
+
+		size := uint16(payload[0])<<8 | uint16(payload[1])
+		payload = payload[2:]
+
+		// discard padding
+		if size == 0 && isAllZero(payload) {
+			break
+		}
+
+		if int(size) > len(payload) {
+			return nil, fmt.Errorf("invalid %v packet (invalid size)", typ)
+		}
+
+		nalus = append(nalus, payload[:size])
+		payload = payload[size:]
+
+		if len(payload) == 0 {
+			break
+		}
+	}
+
+	if nalus == nil {
+		return nil, fmt.Errorf("%v packet doesn't contain any NALU", typ)
+	}
+
+	return nalus, nil
+}
+
+func parseMTAP(payload []byte, typ h264.NALUType) ([][]byte, []uint16, error) {
+	if len(payload) < 2 {
+		return nil, nil, fmt.Errorf("invalid %v packet (invalid size)", typ)
+	}
+
+	donb := uint16(payload[0])<<8 | uint16(payload[1])
+	payload = payload[2:]
+
+	// DOND and timestamp offset
+	unitHeaderSize := 3
+	if typ == h264.NALUTypeMTAP24 {
+		unitHeaderSize = 4
+	}
+
+	units, err := parseAggregated(payload, typ)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nalus := make([][]byte, len(units))
+	dons := make([]uint16, len(units))
+
+	for i, unit := range units {
+		if len(unit) <= unitHeaderSize {
+			return nil, nil, fmt.Errorf("invalid %v packet (invalid size)", typ)
+		}
+
+		// the RTP timestamp of a MTAP is the lowest timestamp of its NALUs,
+		// therefore a nonzero offset means that NALUs have different timestamps.
+		for _, b := range unit[1:unitHeaderSize] {
+			if b != 0 {
+				return nil, nil, fmt.Errorf("invalid %v packet (NALUs with different timestamps are not supported)", typ)
+			}
+		}
+
+		dons[i] = donb + uint16(unit[0])
+		nalus[i] = unit[unitHeaderSize:]
+	}
+
+	return nalus, dons, nil
+}
+
 // Decoder is a RTP/H264 decoder.
 // Specification: https://datatracker.ietf.org/doc/html/rfc6184
 type Decoder struct {
 	// indicates the packetization mode.
+	// When it is 2 (interleaved mode), NALUs of each access unit
+	// are reordered by their decoding order number (DON).
+	// Reordering across access units is not performed,
+	// and MTAPs that contain NALUs with different timestamps are refused.
 	PacketizationMode int
 
 	firstPacketReceived bool
 	fragments           [][]byte
 	fragmentsSize       int
 	fragmentNextSeqNum  uint16
+	fragmentsDON        uint16
+	fragmentsHaveDON    bool
 	annexBMode          bool
+	lastDON             uint16
 
 	// for Decode()
 	frameBuffer     [][]byte
+	frameBufferDONs []uint16
 	frameBufferLen  int
 	frameBufferSize int
 }
 
 // Init initializes the decoder.
 func (d *Decoder) Init() error {
-	if d.PacketizationMode >= 2 {
-		return fmt.Errorf("PacketizationMode >= 2 is not supported")
+	if d.PacketizationMode > 2 {
+		return fmt.Errorf("PacketizationMode > 2 is not supported")
 	}
 	return nil
 }
@@ -69,19 +155,38 @@ func (d *Decoder) resetFragments() {
 	d.fragmentsSize = 0
 }
 
-func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
+// decodeNALUs decodes NALUs from a RTP packet.
+// It also returns the decoding order numbers of NALUs, when they are available.
+func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, []uint16, error) {
 	if len(pkt.Payload) < 1 {
 		d.resetFragments()
-		return nil, fmt.Errorf("payload is too short")
+		return nil, nil, fmt.Errorf("payload is too short")
 	}
 
 	typ := h264.NALUType(pkt.Payload[0] & 0x1F)
 	var nalus [][]byte
+	var dons []uint16
+
+	// RFC6184: STAP-B, MTAPs and FU-B are allowed only in interleaved mode.
+	if d.PacketizationMode < 2 {
+		switch typ {
+		case h264.NALUTypeSTAPB, h264.NALUTypeMTAP16,
+			h264.NALUTypeMTAP24, h264.NALUTypeFUB:
+			d.resetFragments()
+			d.firstPacketReceived = true
+			return nil, nil, fmt.Errorf("packet type not supported (%v)", typ)
+		}
+	}
 
 	switch typ {
-	case h264.NALUTypeFUA:
-		if len(pkt.Payload) < 2 {
-			return nil, fmt.Errorf("invalid FU-A packet (invalid size)")
+	case h264.NALUTypeFUA, h264.NALUTypeFUB:
+		headerSize := 2
+		if typ == h264.NALUTypeFUB {
+			headerSize = 4 // DON
+		}
+
+		if len(pkt.Payload) < headerSize {
+			return nil, nil, fmt.Errorf("invalid %v packet (invalid size)", typ)
 		}
 
 		start := pkt.Payload[1] >> 7
@@ -91,10 +196,14 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 			d.resetFragments()
 
 			nri := (pkt.Payload[0] >> 5) & 0x03
-			typ := pkt.Payload[1] & 0x1F
-			d.fragmentsSize = len(pkt.Payload[1:])
-			d.fragments = append(d.fragments, []byte{(nri << 5) | typ}, pkt.Payload[2:])
+			typ2 := pkt.Payload[1] & 0x1F
+			d.fragmentsSize = 1 + len(pkt.Payload[headerSize:])
+			d.fragments = append(d.fragments, []byte{(nri << 5) | typ2}, pkt.Payload[headerSize:])
 			d.fragmentNextSeqNum = pkt.SequenceNumber + 1
+			d.fragmentsHaveDON = (typ == h264.NALUTypeFUB)
+			if d.fragmentsHaveDON {
+				d.fragmentsDON = uint16(pkt.Payload[2])<<8 | uint16(pkt.Payload[3])
+			}
 			d.firstPacketReceived = true
 
 			// RFC 6184 clearly states:
@@ -107,84 +216,99 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 			// emit one fragmented NAL unit for sufficiently small P-frames.
 			if end != 0 {
 				nalus = [][]byte{joinFragments(d.fragments, d.fragmentsSize)}
+				if d.fragmentsHaveDON {
+					dons = []uint16{d.fragmentsDON}
+				}
 				d.resetFragments()
 				break
 			}
 
-			return nil, ErrMorePacketsNeeded
+			return nil, nil, ErrMorePacketsNeeded
+		}
+
+		// RFC6184: FU-B must be used only for the first fragment of a NALU.
+		if typ == h264.NALUTypeFUB {
+			d.resetFragments()
+			return nil, nil, fmt.Errorf("invalid FU-B packet (non-starting)")
 		}
 
 		if d.fragmentsSize == 0 {
 			if !d.firstPacketReceived {
-				return nil, ErrNonStartingPacketAndNoPrevious
+				return nil, nil, ErrNonStartingPacketAndNoPrevious
 			}
 
-			return nil, fmt.Errorf("invalid FU-A packet (non-starting)")
+			return nil, nil, fmt.Errorf("invalid FU-A packet (non-starting)")
 		}
 
 		if pkt.SequenceNumber != d.fragmentNextSeqNum {
 			d.resetFragments()
-			return nil, fmt.Errorf("discarding frame since a RTP packet is missing")
+			return nil, nil, fmt.Errorf("discarding frame since a RTP packet is missing")
 		}
 
 		d.fragmentsSize += len(pkt.Payload[2:])
 
 		if d.fragmentsSize > h264.MaxAccessUnitSize {
 			d.resetFragments()
-			return nil, fmt.Errorf("NALU size (%d) is too big, maximum is %d", d.fragmentsSize, h264.MaxAccessUnitSize)
+			return nil, nil, fmt.Errorf("NALU size (%d) is too big, maximum is %d", d.fragmentsSize, h264.MaxAccessUnitSize)
 		}
 
 		d.fragments = append(d.fragments, pkt.Payload[2:])
 		d.fragmentNextSeqNum++
 
 		if end != 1 {
-			return nil, ErrMorePacketsNeeded
+			return nil, nil, ErrMorePacketsNeeded
 		}
 
 		nalus = [][]byte{joinFragments(d.fragments, d.fragmentsSize)}
+		if d.fragmentsHaveDON {
+			dons = []uint16{d.fragmentsDON}
+		}
 		d.resetFragments()
 
 	case h264.NALUTypeSTAPA:
 		d.resetFragments()
 
-		payload := pkt.Payload[1:]
-
-		for {
-			if len(payload) < 2 {
-				return nil, fmt.Errorf("invalid STAP-A packet (invalid size)")
-			}
+		var err error
+		nalus, err = parseAggregated(pkt.Payload[1:], typ)
+		if err != nil {
+			return nil, nil, err
+		}
 
-			size := uint16(payload[0])<<8 | uint16(payload[1])
-			payload = payload[2:]
+		d.firstPacketReceived = true
 
-			// discard padding
-			if size == 0 && isAllZero(payload) {
-				break
-			}
+	case h264.NALUTypeSTAPB:
+		d.resetFragments()
 
-			if int(size) > len(payload) {
-				return nil, fmt.Errorf("invalid STAP-A packet (invalid size)")
-			}
+		if len(pkt.Payload) < 3 {
+			return nil, nil, fmt.Errorf("invalid STAP-B packet (invalid size)")
+		}
 
-			nalus = append(nalus, payload[:size])
-			payload = payload[size:]
+		don := uint16(pkt.Payload[1])<<8 | uint16(pkt.Payload[2])
 
-			if len(payload) == 0 {
-				break
-			}
+		var err error
+		nalus, err = parseAggregated(pkt.Payload[3:], typ)
+		if err != nil {
+			return nil, nil, err
 		}
 
-		if nalus == nil {
-			return nil, fmt.Errorf("STAP-A packet doesn't contain any NALU")
+		// RFC6184: the DON of each following NALU is incremented by one.
+		dons = make([]uint16, len(nalus))
+		for i := range dons {
+			dons[i] = don + uint16(i)
 		}
 
 		d.firstPacketReceived = true
 
-	case h264.NALUTypeSTAPB, h264.NALUTypeMTAP16,
-		h264.NALUTypeMTAP24, h264.NALUTypeFUB:
+	case h264.NALUTypeMTAP16, h264.NALUTypeMTAP24:
 		d.resetFragments()
+
+		var err error
+		nalus, dons, err = parseMTAP(pkt.Payload[1:], typ)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		d.firstPacketReceived = true
-		return nil, fmt.Errorf("packet type not supported (%v)", typ)
 
 	default:
 		d.resetFragments()
@@ -192,17 +316,21 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 		nalus = [][]byte{pkt.Payload}
 	}
 
-	nalus, err := d.removeAnnexB(nalus)
-	if err != nil {
-		return nil, err
+	// NALUs with a DON are never wrapped into Annex-B
+	if dons == nil {
+		var err error
+		nalus, err = d.removeAnnexB(nalus)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return nalus, nil
+	return nalus, dons, nil
 }
 
 // Decode decodes an access unit from a RTP packet.
 func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
-	nalus, err := d.decodeNALUs(pkt)
+	nalus, dons, err := d.decodeNALUs(pkt)
 	if err != nil {
 		return nil, err
 	}
@@ -210,6 +338,7 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 
 	if (d.frameBufferLen + l) > h264.MaxNALUsPerAccessUnit {
 		d.frameBuffer = nil
+		d.frameBufferDONs = nil
 		d.frameBufferLen = 0
 		d.frameBufferSize = 0
 		return nil, fmt.Errorf("NALU count exceeds maximum allowed (%d)",
@@ -224,12 +353,29 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 
 	if (d.frameBufferSize + addSize) > h264.MaxAccessUnitSize {
 		d.frameBuffer = nil
+		d.frameBufferDONs = nil
 		d.frameBufferLen = 0
 		d.frameBufferSize = 0
 		return nil, fmt.Errorf("access unit size (%d) is too big, maximum is %d",
 			d.frameBufferSize+addSize, h264.MaxAccessUnitSize)
 	}
 
+	if d.PacketizationMode == 2 {
+		// NALUs without a DON follow the previous one
+		if dons == nil {
+			dons = make([]uint16, l)
+			for i := range dons {
+				dons[i] = d.lastDON + 1 + uint16(i)
+			}
+		}
+
+		if l != 0 {
+			d.lastDON = dons[l-1]
+		}
+
+		d.frameBufferDONs = append(d.frameBufferDONs, dons...)
+	}
+
 	d.frameBuffer = append(d.frameBuffer, nalus...)
 	d.frameBufferLen += l
 	d.frameBufferSize += addSize
@@ -240,8 +386,13 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 
 	ret := d.frameBuffer
 
+	if d.PacketizationMode == 2 {
+		reorderByDON(ret, d.frameBufferDONs)
+	}
+
 	// do not reuse frameBuffer to avoid race conditions
 	d.frameBuffer = nil
+	d.frameBufferDONs = nil
 	d.frameBufferLen = 0
 	d.frameBufferSize = 0
 
@@ -268,3 +419,32 @@ func (d *Decoder) removeAnnexB(nalus [][]byte) ([][]byte, error) {
 
 	return nalus, nil
 }
+
+// reorderByDON sorts NALUs by their decoding order number.
+// DONs are compared with the first one, in order to handle wrap-arounds.
+func reorderByDON(nalus [][]byte, dons []uint16) {
+	if len(nalus) < 2 {
+		return
+	}
+
+	sort.Stable(&donSorter{nalus, dons, dons[0]})
+}
+
+type donSorter struct {
+	nalus [][]byte
+	dons  []uint16
+	base  uint16
+}
+
+func (s *donSorter) Len() int {
+	return len(s.nalus)
+}
+
+func (s *donSorter) Less(i, j int) bool {
+	return int16(s.dons[i]-s.base) < int16(s.dons[j]-s.base)
+}
+
+func (s *donSorter) Swap(i, j int) {
+	s.nalus[i], s.nalus[j] = s.nalus[j], s.nalus[i]
+	s.dons[i], s.dons[j] = s.dons[j], s.dons[i]
+}
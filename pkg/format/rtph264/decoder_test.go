@@ -40,6 +40,226 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecodeInterleaved(t *testing.T) {
+	for _, ca := range casesInterleaved {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				PacketizationMode: 2,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var nalus [][]byte
+
+			for _, pkt := range ca.pkts {
+				addNALUs, err := d.Decode(pkt)
+				if errors.Is(err, ErrMorePacketsNeeded) {
+					continue
+				}
+
+				require.NoError(t, err)
+				nalus = append(nalus, addNALUs...)
+			}
+
+			require.Equal(t, ca.nalus, nalus)
+		})
+	}
+}
+
+func TestDecodeMTAP(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		payload []byte
+	}{
+		{
+			"mtap16",
+			[]byte{
+				0x1a, 0x00, 0x0a, // header, DONB
+				0x00, 0x05, 0x01, 0x00, 0x00, 0x01, 0x02, // size, DOND, TS offset, NALU
+				0x00, 0x05, 0x00, 0x00, 0x00, 0x03, 0x04, // size, DOND, TS offset, NALU
+			},
+		},
+		{
+			"mtap24",
+			[]byte{
+				0x1b, 0x00, 0x0a, // header, DONB
+				0x00, 0x06, 0x01, 0x00, 0x00, 0x00, 0x01, 0x02, // size, DOND, TS offset, NALU
+				0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x03, 0x04, // size, DOND, TS offset, NALU
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				PacketizationMode: 2,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			nalus, err := d.Decode(&rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: ca.payload,
+			})
+			require.NoError(t, err)
+			require.Equal(t, [][]byte{{0x03, 0x04}, {0x01, 0x02}}, nalus)
+		})
+	}
+}
+
+func TestDecodeErrorMTAPTimestampOffsets(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		payload []byte
+		err     string
+	}{
+		{
+			"mtap16",
+			[]byte{
+				0x1a, 0x00, 0x0a, // header, DONB
+				0x00, 0x05, 0x00, 0x00, 0x00, 0x01, 0x02, // size, DOND, TS offset, NALU
+				0x00, 0x05, 0x01, 0x0b, 0xb8, 0x03, 0x04, // size, DOND, TS offset, NALU
+			},
+			"invalid MTAP-16 packet (NALUs with different timestamps are not supported)",
+		},
+		{
+			"mtap24",
+			[]byte{
+				0x1b, 0x00, 0x0a, // header, DONB
+				0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02, // size, DOND, TS offset, NALU
+				0x00, 0x06, 0x01, 0x01, 0x00, 0x00, 0x03, 0x04, // size, DOND, TS offset, NALU
+			},
+			"invalid MTAP-24 packet (NALUs with different timestamps are not supported)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				PacketizationMode: 2,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			_, err = d.Decode(&rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: ca.payload,
+			})
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestDecodeReorderByDON(t *testing.T) {
+	d := &Decoder{
+		PacketizationMode: 2,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x19, 0x00, 0x00, 0x00, 0x02, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	nalus, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17646,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x19, 0xff, 0xff, 0x00, 0x02, 0x03, 0x04},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x03, 0x04}, {0x01, 0x02}}, nalus)
+}
+
+func TestDecodeErrorFUBNonStarting(t *testing.T) {
+	d := &Decoder{
+		PacketizationMode: 2,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x1d, 0x45, 0x00, 0x00, 0x01, 0x02},
+	})
+	require.EqualError(t, err, "invalid FU-B packet (non-starting)")
+}
+
+func TestDecodeErrorInterleavedPacketNonInterleaved(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		payload []byte
+		err     string
+	}{
+		{
+			"stap-b",
+			[]byte{0x19, 0x00, 0x00, 0x00, 0x02, 0x01, 0x02},
+			"packet type not supported (STAP-B)",
+		},
+		{
+			"mtap16",
+			[]byte{0x1a, 0x00, 0x0a, 0x00, 0x05, 0x00, 0x00, 0x00, 0x01, 0x02},
+			"packet type not supported (MTAP-16)",
+		},
+		{
+			"mtap24",
+			[]byte{0x1b, 0x00, 0x0a, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02},
+			"packet type not supported (MTAP-24)",
+		},
+		{
+			"fu-b",
+			[]byte{0x1d, 0x85, 0x00, 0x00, 0x01, 0x02},
+			"packet type not supported (FU-B)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				PacketizationMode: 1,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			_, err = d.Decode(&rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: ca.payload,
+			})
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
 func TestDecodeCorruptedFragment(t *testing.T) {
 	d := &Decoder{}
 	err := d.Init()
@@ -275,32 +495,51 @@ func TestDecodeErrorMissingPacket(t *testing.T) {
 }
 
 func FuzzDecoder(f *testing.F) {
-	f.Fuzz(func(_ *testing.T, a []byte, b []byte) {
-		d := &Decoder{}
-		d.Init() //nolint:errcheck
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		for i, pkt := range ca.pkts {
+			next := ca.pkts[(i+1)%len(ca.pkts)]
+			f.Add(pkt.Payload, next.Payload)
+		}
+	}
 
-		d.Decode(&rtp.Packet{ //nolint:errcheck
-			Header: rtp.Header{
-				Version:        2,
-				Marker:         false,
-				PayloadType:    96,
-				SequenceNumber: 17645,
-				Timestamp:      2289527317,
-				SSRC:           0x9dbb7812,
-			},
-			Payload: a,
-		})
+	for _, ca := range casesInterleaved {
+		for i, pkt := range ca.pkts {
+			next := ca.pkts[(i+1)%len(ca.pkts)]
+			f.Add(pkt.Payload, next.Payload)
+		}
+	}
 
-		d.Decode(&rtp.Packet{ //nolint:errcheck
-			Header: rtp.Header{
-				Version:        2,
-				Marker:         false,
-				PayloadType:    96,
-				SequenceNumber: 17645,
-				Timestamp:      2289527317,
-				SSRC:           0x9dbb7812,
-			},
-			Payload: b,
-		})
+	f.Fuzz(func(_ *testing.T, a []byte, b []byte) {
+		for _, mode := range []int{1, 2} {
+			d := &Decoder{
+				PacketizationMode: mode,
+			}
+			d.Init() //nolint:errcheck
+
+			d.Decode(&rtp.Packet{ //nolint:errcheck
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289527317,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: a,
+			})
+
+			d.Decode(&rtp.Packet{ //nolint:errcheck
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289527317,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: b,
+			})
+		}
 	})
 }
@@ -65,15 +65,19 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
+	// packetization mode (optional).
+	// When it is 2, interleaved mode is used: NALUs are sent
+	// with STAP-B, FU-B and FU-A packets, together with a decoding order number (DON).
 	PacketizationMode int
 
 	sequenceNumber uint16
+	don            uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
-	if e.PacketizationMode >= 2 {
-		return fmt.Errorf("PacketizationMode >= 2 is not supported")
+	if e.PacketizationMode > 2 {
+		return fmt.Errorf("PacketizationMode > 2 is not supported")
 	}
 
 	if e.SSRC == nil {
@@ -106,7 +110,7 @@ func (e *Encoder) Encode(au [][]byte) ([]*rtp.Packet, error) {
 
 	// split NALUs into batches
 	for _, nalu := range au {
-		if lenAggregated(batch, nalu) <= e.PayloadMaxSize {
+		if e.lenBatch(batch, nalu) <= e.PayloadMaxSize {
 			// add to existing batch
 			batch = append(batch, nalu)
 		} else {
@@ -135,7 +139,25 @@ func (e *Encoder) Encode(au [][]byte) ([]*rtp.Packet, error) {
 	return rets, nil
 }
 
+func (e *Encoder) lenBatch(nalus [][]byte, addNALU []byte) int {
+	n := lenAggregated(nalus, addNALU)
+	if e.PacketizationMode == 2 {
+		n += 2 // DON
+	}
+	return n
+}
+
 func (e *Encoder) writeBatch(nalus [][]byte, marker bool) ([]*rtp.Packet, error) {
+	if e.PacketizationMode == 2 {
+		// single NAL unit packets are not allowed in interleaved mode,
+		// therefore single NALUs are sent with STAP-B too.
+		if len(nalus) == 1 && e.lenBatch(nalus, nil) > e.PayloadMaxSize {
+			return e.writeFragmentedInterleaved(nalus[0], marker)
+		}
+
+		return e.writeAggregatedInterleaved(nalus, marker)
+	}
+
 	if len(nalus) == 1 {
 		// the NALU fits into a single RTP packet
 		if len(nalus[0]) < e.PayloadMaxSize {
@@ -246,3 +268,102 @@ func (e *Encoder) writeAggregated(nalus [][]byte, marker bool) ([]*rtp.Packet, e
 
 	return []*rtp.Packet{pkt}, nil
 }
+
+func (e *Encoder) writeFragmentedInterleaved(nalu []byte, marker bool) ([]*rtp.Packet, error) {
+	// the first fragment is sent with FU-B, that contains the DON,
+	// while the following ones are sent with FU-A.
+	nri := (nalu[0] >> 5) & 0x03
+	typ := nalu[0] & 0x1F
+	nalu = nalu[1:] // remove header
+
+	// a FU must not contain both the start and the end of a NALU
+	firstLen := min(e.PayloadMaxSize-4, len(nalu)-1)
+	avail := e.PayloadMaxSize - 2
+	packetCount := 1 + packetCount(avail, len(nalu)-firstLen)
+
+	ret := make([]*rtp.Packet, packetCount)
+
+	for i := range ret {
+		var data []byte
+
+		if i == 0 {
+			data = make([]byte, 4+firstLen)
+			data[0] = (nri << 5) | uint8(h264.NALUTypeFUB)
+			data[1] = (1 << 7) | typ
+			data[2] = uint8(e.don >> 8)
+			data[3] = uint8(e.don)
+			copy(data[4:], nalu)
+			nalu = nalu[firstLen:]
+		} else {
+			le := avail
+			end := uint8(0)
+			if i == (packetCount - 1) {
+				end = 1
+				le = len(nalu)
+			}
+
+			data = make([]byte, 2+le)
+			data[0] = (nri << 5) | uint8(h264.NALUTypeFUA)
+			data[1] = (end << 6) | typ
+			copy(data[2:], nalu)
+			nalu = nalu[le:]
+		}
+
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				SSRC:           *e.SSRC,
+				Marker:         (i == (packetCount-1) && marker),
+			},
+			Payload: data,
+		}
+
+		e.sequenceNumber++
+	}
+
+	e.don++
+
+	return ret, nil
+}
+
+func (e *Encoder) writeAggregatedInterleaved(nalus [][]byte, marker bool) ([]*rtp.Packet, error) {
+	payload := make([]byte, e.lenBatch(nalus, nil))
+
+	// header
+	payload[0] = uint8(h264.NALUTypeSTAPB)
+
+	// DON of the first NALU. DONs of following NALUs are incremented by one.
+	payload[1] = uint8(e.don >> 8)
+	payload[2] = uint8(e.don)
+	pos := 3
+
+	for _, nalu := range nalus {
+		// size
+		naluLen := len(nalu)
+		payload[pos] = uint8(naluLen >> 8)
+		payload[pos+1] = uint8(naluLen)
+		pos += 2
+
+		// nalu
+		copy(payload[pos:], nalu)
+		pos += naluLen
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    e.PayloadType,
+			SequenceNumber: e.sequenceNumber,
+			SSRC:           *e.SSRC,
+			Marker:         marker,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+	e.don += uint16(len(nalus))
+
+	return []*rtp.Packet{pkt}, nil
+}
@@ -300,6 +300,147 @@ var cases = []struct {
 	},
 }
 
+var casesInterleaved = []struct {
+	name  string
+	nalus [][]byte
+	pkts  []*rtp.Packet
+}{
+	{
+		"single",
+		[][]byte{
+			{0x05, 0x01, 0x02, 0x03},
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x19, 0x00, 0x00, 0x00, 0x04, 0x05, 0x01, 0x02,
+					0x03,
+				},
+			},
+		},
+	},
+	{
+		"aggregated",
+		[][]byte{
+			{0x09, 0xf0},
+			{0x06, 0x01},
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x19, 0x00, 0x00, 0x00, 0x02, 0x09, 0xf0, 0x00,
+					0x02, 0x06, 0x01,
+				},
+			},
+		},
+	},
+	{
+		"fragmented",
+		[][]byte{
+			{
+				0x65, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06,
+				0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e,
+				0x0f,
+			},
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x7d, 0x85, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03,
+					0x04, 0x05, 0x06, 0x07,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x7c, 0x45, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d,
+					0x0e, 0x0f,
+				},
+			},
+		},
+	},
+	{
+		"fragmented followed by aggregated",
+		[][]byte{
+			{
+				0x65, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06,
+				0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e,
+				0x0f,
+			},
+			{0x09, 0xf0},
+			{0x06, 0x01},
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x7d, 0x85, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03,
+					0x04, 0x05, 0x06, 0x07,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x7c, 0x45, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d,
+					0x0e, 0x0f,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x19, 0x00, 0x01, 0x00, 0x02, 0x09, 0xf0, 0x00,
+					0x02, 0x06, 0x01,
+				},
+			},
+		},
+	},
+}
+
 func TestEncode(t *testing.T) {
 	for _, ca := range cases {
 		t.Run(ca.name, func(t *testing.T) {
@@ -318,6 +459,26 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodeInterleaved(t *testing.T) {
+	for _, ca := range casesInterleaved {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           96,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+				PayloadMaxSize:        12,
+				PacketizationMode:     2,
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.nalus)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
 func TestEncodeRandomInitialState(t *testing.T) {
 	e := &Encoder{
 		PayloadType: 96,
@@ -0,0 +1,40 @@
+package rtpilbc
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// Decoder is a RTP/iLBC decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc3952
+type Decoder struct {
+	// frame duration in milliseconds, 20 or 30.
+	Mode int
+
+	frameSize int
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	var err error
+	d.frameSize, _, err = modeParams(d.Mode)
+	return err
+}
+
+// Decode decodes frames from a RTP packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	plen := len(pkt.Payload)
+
+	if plen == 0 || (plen%d.frameSize) != 0 {
+		return nil, fmt.Errorf("received payload of wrong size")
+	}
+
+	frames := make([][]byte, plen/d.frameSize)
+
+	for i := range frames {
+		frames[i] = pkt.Payload[i*d.frameSize : (i+1)*d.frameSize]
+	}
+
+	return frames, nil
+}
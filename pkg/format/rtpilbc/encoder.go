@@ -0,0 +1,128 @@
+package rtpilbc
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/iLBC encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc3952
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// frame duration in milliseconds, 20 or 30.
+	Mode int
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber  uint16
+	frameSize       int
+	samplesPerFrame uint32
+	maxFrameCount   int
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	var err error
+	e.frameSize, e.samplesPerFrame, err = modeParams(e.Mode)
+	if err != nil {
+		return err
+	}
+
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	e.maxFrameCount = e.PayloadMaxSize / e.frameSize
+
+	if e.maxFrameCount == 0 {
+		return fmt.Errorf("PayloadMaxSize is too small")
+	}
+
+	return nil
+}
+
+// Encode encodes frames into RTP packets.
+func (e *Encoder) Encode(frames [][]byte) ([]*rtp.Packet, error) {
+	for _, frame := range frames {
+		if len(frame) != e.frameSize {
+			return nil, fmt.Errorf("invalid frame size: %d", len(frame))
+		}
+	}
+
+	var ret []*rtp.Packet
+	timestamp := uint32(0)
+
+	for len(frames) != 0 {
+		n := e.maxFrameCount
+		if n > len(frames) {
+			n = len(frames)
+		}
+
+		payload := make([]byte, 0, n*e.frameSize)
+		for _, frame := range frames[:n] {
+			payload = append(payload, frame...)
+		}
+
+		ret = append(ret, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      timestamp,
+				SSRC:           *e.SSRC,
+				Marker:         false,
+			},
+			Payload: payload,
+		})
+
+		e.sequenceNumber++
+		timestamp += uint32(n) * e.samplesPerFrame
+		frames = frames[n:]
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,111 @@
+package rtpilbc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+var cases = []struct {
+	name   string
+	mode   int
+	frames [][]byte
+	pkts   []*rtp.Packet
+}{
+	{
+		"20ms",
+		20,
+		[][]byte{
+			bytes.Repeat([]byte{0x01}, 38),
+			bytes.Repeat([]byte{0x02}, 38),
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: append(bytes.Repeat([]byte{0x01}, 38), bytes.Repeat([]byte{0x02}, 38)...),
+			},
+		},
+	},
+	{
+		"30ms multiple",
+		30,
+		func() [][]byte {
+			frames := make([][]byte, 30)
+			for i := range frames {
+				frames[i] = bytes.Repeat([]byte{0x01}, 50)
+			}
+			return frames
+		}(),
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      0,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01}, 1450),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      6960,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01}, 50),
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           96,
+				Mode:                  ca.mode,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.frames)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+		Mode:        30,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
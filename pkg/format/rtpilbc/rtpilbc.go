@@ -0,0 +1,19 @@
+// Package rtpilbc contains a RTP/iLBC decoder and encoder.
+package rtpilbc
+
+import (
+	"fmt"
+)
+
+// returns frame size and samples per frame of a mode.
+func modeParams(mode int) (int, uint32, error) {
+	switch mode {
+	case 20:
+		return 38, 160, nil
+
+	case 30:
+		return 50, 240, nil
+	}
+
+	return 0, 0, fmt.Errorf("invalid mode: %d", mode)
+}
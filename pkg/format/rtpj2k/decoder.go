@@ -0,0 +1,100 @@
+package rtpj2k
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// fragment of a codestream and we didn't received anything before.
+// It's normal to receive this when decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+func joinFragments(fragments [][]byte, size int) []byte {
+	ret := make([]byte, size)
+	n := 0
+	for _, p := range fragments {
+		n += copy(ret[n:], p)
+	}
+	return ret
+}
+
+// Decoder is a RTP/JPEG 2000 decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc5371
+type Decoder struct {
+	firstPacketReceived bool
+	fragments           [][]byte
+	fragmentsSize       int
+	fragmentNextSeqNum  uint16
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragments = d.fragments[:0]
+	d.fragmentsSize = 0
+}
+
+// Decode decodes a JPEG 2000 codestream from a RTP packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	var h header
+	n, err := h.unmarshal(pkt.Payload)
+	if err != nil {
+		d.resetFragments()
+		return nil, err
+	}
+	payload := pkt.Payload[n:]
+
+	if h.FragmentOffset == 0 {
+		d.resetFragments()
+		d.firstPacketReceived = true
+	} else {
+		if d.fragmentsSize == 0 {
+			if !d.firstPacketReceived {
+				return nil, ErrNonStartingPacketAndNoPrevious
+			}
+
+			return nil, fmt.Errorf("received a non-starting fragment")
+		}
+
+		if pkt.SequenceNumber != d.fragmentNextSeqNum {
+			d.resetFragments()
+			return nil, fmt.Errorf("discarding frame since a RTP packet is missing")
+		}
+
+		if int(h.FragmentOffset) != d.fragmentsSize {
+			d.resetFragments()
+			return nil, fmt.Errorf("received wrong fragment offset (%d, expected %d)",
+				h.FragmentOffset, d.fragmentsSize)
+		}
+	}
+
+	d.fragmentsSize += len(payload)
+
+	if d.fragmentsSize > maxCodestreamSize {
+		d.resetFragments()
+		return nil, fmt.Errorf("codestream size (%d) is too big, maximum is %d", d.fragmentsSize, maxCodestreamSize)
+	}
+
+	d.fragments = append(d.fragments, payload)
+	d.fragmentNextSeqNum = pkt.SequenceNumber + 1
+
+	if !pkt.Marker {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	codestream := joinFragments(d.fragments, d.fragmentsSize)
+	d.resetFragments()
+
+	return codestream, nil
+}
@@ -0,0 +1,145 @@
+package rtpj2k
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+// start of tile-part marker, that terminates the main header.
+var markerSOT = []byte{0xFF, 0x90}
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+func mainHeaderFlag(offset int, le int, mainHeaderSize int) uint8 {
+	switch {
+	case offset >= mainHeaderSize:
+		return mainHeaderNone
+
+	case (offset + le) < mainHeaderSize:
+		return mainHeaderFragment
+
+	case offset == 0:
+		return mainHeaderComplete
+
+	default:
+		return mainHeaderLast
+	}
+}
+
+// Encoder is a RTP/JPEG 2000 encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc5371
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	return nil
+}
+
+// Encode encodes a JPEG 2000 codestream into RTP/JPEG 2000 packets.
+func (e *Encoder) Encode(codestream []byte) ([]*rtp.Packet, error) {
+	if len(codestream) == 0 {
+		return nil, fmt.Errorf("codestream is empty")
+	}
+
+	if len(codestream) > maxCodestreamSize {
+		return nil, fmt.Errorf("codestream size (%d) is too big, maximum is %d", len(codestream), maxCodestreamSize)
+	}
+
+	mainHeaderSize := bytes.Index(codestream, markerSOT)
+	if mainHeaderSize < 0 {
+		mainHeaderSize = 0
+	}
+
+	avail := e.PayloadMaxSize - headerSize
+	var ret []*rtp.Packet
+	offset := 0
+
+	for offset < len(codestream) {
+		le := avail
+		if le > (len(codestream) - offset) {
+			le = len(codestream) - offset
+		}
+
+		h := header{
+			MainHeaderFlag:   mainHeaderFlag(offset, le, mainHeaderSize),
+			TileNumberIgnore: true,
+			Priority:         255,
+			FragmentOffset:   uint32(offset),
+		}
+		if h.MainHeaderFlag != mainHeaderNone {
+			h.Priority = 0
+		}
+
+		payload := make([]byte, headerSize+le)
+		h.marshalTo(payload)
+		copy(payload[headerSize:], codestream[offset:offset+le])
+		offset += le
+
+		ret = append(ret, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				SSRC:           *e.SSRC,
+				Marker:         offset == len(codestream),
+			},
+			Payload: payload,
+		})
+
+		e.sequenceNumber++
+	}
+
+	return ret, nil
+}
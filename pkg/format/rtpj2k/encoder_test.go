@@ -0,0 +1,139 @@
+package rtpj2k
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+var fragmentedCodestream = mergeBytes(
+	[]byte{0xff, 0x4f, 0xff, 0x51, 0x00, 0x02},
+	bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 1024),
+	[]byte{0xff, 0x90, 0x05, 0x06},
+)
+
+var cases = []struct {
+	name       string
+	codestream []byte
+	pkts       []*rtp.Packet
+}{
+	{
+		"single",
+		[]byte{0xff, 0x4f, 0xff, 0x51, 0x00, 0x02, 0xff, 0x90, 0x01, 0x02},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x31, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+					0xff, 0x4f, 0xff, 0x51, 0x00, 0x02, 0xff, 0x90,
+					0x01, 0x02,
+				},
+			},
+		},
+	},
+	{
+		"fragmented",
+		fragmentedCodestream,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x11, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+					fragmentedCodestream[:1452],
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x11, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05, 0xac},
+					fragmentedCodestream[1452:2904],
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x21, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0b, 0x58},
+					fragmentedCodestream[2904:],
+				),
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           96,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.codestream)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
@@ -0,0 +1,55 @@
+package rtpj2k
+
+import (
+	"fmt"
+)
+
+const headerSize = 8
+
+// main header flag values.
+const (
+	mainHeaderNone     = 0
+	mainHeaderFragment = 1
+	mainHeaderLast     = 2
+	mainHeaderComplete = 3
+)
+
+type header struct {
+	Type             uint8
+	MainHeaderFlag   uint8
+	MainHeaderID     uint8
+	TileNumberIgnore bool
+	Priority         uint8
+	TileNumber       uint16
+	FragmentOffset   uint32
+}
+
+func (h *header) unmarshal(buf []byte) (int, error) {
+	if len(buf) < headerSize {
+		return 0, fmt.Errorf("payload is too short")
+	}
+
+	h.Type = buf[0] >> 6
+	h.MainHeaderFlag = (buf[0] >> 4) & 0b11
+	h.MainHeaderID = (buf[0] >> 1) & 0b111
+	h.TileNumberIgnore = (buf[0] & 0b1) != 0
+	h.Priority = buf[1]
+	h.TileNumber = uint16(buf[2])<<8 | uint16(buf[3])
+	h.FragmentOffset = uint32(buf[5])<<16 | uint32(buf[6])<<8 | uint32(buf[7])
+
+	return headerSize, nil
+}
+
+func (h header) marshalTo(buf []byte) {
+	buf[0] = h.Type<<6 | h.MainHeaderFlag<<4 | h.MainHeaderID<<1
+	if h.TileNumberIgnore {
+		buf[0] |= 1
+	}
+	buf[1] = h.Priority
+	buf[2] = byte(h.TileNumber >> 8)
+	buf[3] = byte(h.TileNumber)
+	buf[4] = 0
+	buf[5] = byte(h.FragmentOffset >> 16)
+	buf[6] = byte(h.FragmentOffset >> 8)
+	buf[7] = byte(h.FragmentOffset)
+}
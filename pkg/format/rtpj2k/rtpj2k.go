@@ -0,0 +1,5 @@
+// Package rtpj2k contains a RTP/JPEG 2000 decoder and encoder.
+package rtpj2k
+
+// maxCodestreamSize is the maximum size of a codestream.
+const maxCodestreamSize = 16 * 1024 * 1024
@@ -0,0 +1,103 @@
+package rtpjpegxs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a frame and we didn't received anything before.
+// It's normal to receive this when decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+func joinFragments(fragments [][]byte, size int) []byte {
+	ret := make([]byte, size)
+	n := 0
+	for _, p := range fragments {
+		n += copy(ret[n:], p)
+	}
+	return ret
+}
+
+// Decoder is a RTP/JPEG XS decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc9134
+type Decoder struct {
+	firstPacketReceived bool
+	fragments           [][]byte
+	fragmentsSize       int
+	fragmentNextSeqNum  uint16
+	frameCounter        uint8
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragments = d.fragments[:0]
+	d.fragmentsSize = 0
+}
+
+// Decode decodes a JPEG XS codestream from a RTP packet.
+// In slice packetization mode, slices are concatenated into a codestream.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	var h header
+	n, err := h.unmarshal(pkt.Payload)
+	if err != nil {
+		d.resetFragments()
+		return nil, err
+	}
+
+	if !h.SequentialTransmission {
+		d.resetFragments()
+		return nil, fmt.Errorf("out-of-order transmission mode is not supported (yet)")
+	}
+
+	payload := pkt.Payload[n:]
+
+	if h.SEPCounter == 0 && h.PacketCounter == 0 {
+		d.resetFragments()
+		d.firstPacketReceived = true
+		d.frameCounter = h.FrameCounter
+	} else {
+		if d.fragmentsSize == 0 {
+			if !d.firstPacketReceived {
+				return nil, ErrNonStartingPacketAndNoPrevious
+			}
+
+			return nil, fmt.Errorf("received a non-starting fragment")
+		}
+
+		if pkt.SequenceNumber != d.fragmentNextSeqNum || h.FrameCounter != d.frameCounter {
+			d.resetFragments()
+			return nil, fmt.Errorf("discarding frame since a RTP packet is missing")
+		}
+	}
+
+	d.fragmentsSize += len(payload)
+
+	if d.fragmentsSize > maxCodestreamSize {
+		d.resetFragments()
+		return nil, fmt.Errorf("codestream size (%d) is too big, maximum is %d", d.fragmentsSize, maxCodestreamSize)
+	}
+
+	d.fragments = append(d.fragments, payload)
+	d.fragmentNextSeqNum = pkt.SequenceNumber + 1
+
+	if !pkt.Marker {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	codestream := joinFragments(d.fragments, d.fragmentsSize)
+	d.resetFragments()
+
+	return codestream, nil
+}
@@ -0,0 +1,77 @@
+package rtpjpegxs
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var codestream []byte
+
+			for _, pkt := range ca.pkts {
+				codestream, err = d.Decode(pkt)
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, ca.codestream, codestream)
+		})
+	}
+}
+
+func TestDecodeErrorMissingPacket(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(cases[1].pkts[0])
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	_, err = d.Decode(cases[1].pkts[2])
+	require.EqualError(t, err, "discarding frame since a RTP packet is missing")
+}
+
+func TestDecodeErrorNonStarting(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(cases[1].pkts[1])
+	require.Equal(t, ErrNonStartingPacketAndNoPrevious, err)
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool) {
+		d := &Decoder{}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         am,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: a,
+		})
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         bm,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
@@ -0,0 +1,133 @@
+package rtpjpegxs
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/JPEG XS encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc9134
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	// packetization mode.
+	// 0 is codestream mode, 1 is slice mode.
+	PacketMode int
+
+	sequenceNumber uint16
+	frameCounter   uint8
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.PacketMode != 0 {
+		return fmt.Errorf("PacketMode != 0 is not supported (yet)")
+	}
+
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	return nil
+}
+
+// Encode encodes a JPEG XS codestream into RTP/JPEG XS packets.
+func (e *Encoder) Encode(codestream []byte) ([]*rtp.Packet, error) {
+	if len(codestream) == 0 {
+		return nil, fmt.Errorf("codestream is empty")
+	}
+
+	if len(codestream) > maxCodestreamSize {
+		return nil, fmt.Errorf("codestream size (%d) is too big, maximum is %d", len(codestream), maxCodestreamSize)
+	}
+
+	avail := e.PayloadMaxSize - headerSize
+	var ret []*rtp.Packet
+	offset := 0
+	counter := 0
+
+	for offset < len(codestream) {
+		le := avail
+		if le > (len(codestream) - offset) {
+			le = len(codestream) - offset
+		}
+
+		last := (offset + le) == len(codestream)
+
+		h := header{
+			SequentialTransmission: true,
+			Last:                   last,
+			FrameCounter:           e.frameCounter,
+			SEPCounter:             uint16(counter >> 11),
+			PacketCounter:          uint16(counter & 0x7FF),
+		}
+
+		payload := make([]byte, headerSize+le)
+		h.marshalTo(payload)
+		copy(payload[headerSize:], codestream[offset:offset+le])
+		offset += le
+
+		ret = append(ret, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				SSRC:           *e.SSRC,
+				Marker:         last,
+			},
+			Payload: payload,
+		})
+
+		e.sequenceNumber++
+		counter++
+	}
+
+	e.frameCounter = (e.frameCounter + 1) & 0b11111
+
+	return ret, nil
+}
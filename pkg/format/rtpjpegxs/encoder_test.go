@@ -0,0 +1,153 @@
+package rtpjpegxs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+var cases = []struct {
+	name       string
+	codestream []byte
+	pkts       []*rtp.Packet
+}{
+	{
+		"single",
+		[]byte{0xff, 0x10, 0xff, 0x50, 0x01, 0x02},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0xa0, 0x00, 0x00, 0x00, 0xff, 0x10, 0xff, 0x50,
+					0x01, 0x02,
+				},
+			},
+		},
+	},
+	{
+		"fragmented",
+		bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 1024),
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x80, 0x00, 0x00, 0x00},
+					bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 364),
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x80, 0x00, 0x00, 0x01},
+					bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 364),
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0xa0, 0x00, 0x00, 0x02},
+					bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 296),
+				),
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           96,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.codestream)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeFrameCounter(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	for i := 0; i < 33; i++ {
+		var pkts []*rtp.Packet
+		pkts, err = e.Encode([]byte{0x01, 0x02})
+		require.NoError(t, err)
+
+		var h header
+		_, err = h.unmarshal(pkts[0].Payload)
+		require.NoError(t, err)
+		require.Equal(t, uint8(i%32), h.FrameCounter)
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
@@ -0,0 +1,62 @@
+package rtpjpegxs
+
+import (
+	"fmt"
+)
+
+const headerSize = 4
+
+type header struct {
+	// whether packets are sent in order.
+	SequentialTransmission bool
+
+	// whether the payload contains slices, instead of a full codestream.
+	SliceMode bool
+
+	// whether this is the last packet of a packetization unit.
+	Last bool
+
+	// interlaced information.
+	Interlaced uint8
+
+	// frame counter, modulo 32.
+	FrameCounter uint8
+
+	// SEP counter, incremented when the P counter wraps around.
+	SEPCounter uint16
+
+	// packet counter, modulo 2048.
+	PacketCounter uint16
+}
+
+func (h *header) unmarshal(buf []byte) (int, error) {
+	if len(buf) < headerSize {
+		return 0, fmt.Errorf("payload is too short")
+	}
+
+	h.SequentialTransmission = (buf[0] >> 7) != 0
+	h.SliceMode = ((buf[0] >> 6) & 0b1) != 0
+	h.Last = ((buf[0] >> 5) & 0b1) != 0
+	h.Interlaced = (buf[0] >> 3) & 0b11
+	h.FrameCounter = (buf[0]&0b111)<<2 | buf[1]>>6
+	h.SEPCounter = uint16(buf[1]&0b111111)<<5 | uint16(buf[2]>>3)
+	h.PacketCounter = uint16(buf[2]&0b111)<<8 | uint16(buf[3])
+
+	return headerSize, nil
+}
+
+func (h header) marshalTo(buf []byte) {
+	buf[0] = (h.Interlaced&0b11)<<3 | (h.FrameCounter>>2)&0b111
+	if h.SequentialTransmission {
+		buf[0] |= 1 << 7
+	}
+	if h.SliceMode {
+		buf[0] |= 1 << 6
+	}
+	if h.Last {
+		buf[0] |= 1 << 5
+	}
+	buf[1] = h.FrameCounter<<6 | byte(h.SEPCounter>>5)&0b111111
+	buf[2] = byte(h.SEPCounter<<3) | byte(h.PacketCounter>>8)&0b111
+	buf[3] = byte(h.PacketCounter)
+}
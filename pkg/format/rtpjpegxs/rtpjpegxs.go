@@ -0,0 +1,5 @@
+// Package rtpjpegxs contains a RTP/JPEG XS decoder and encoder.
+package rtpjpegxs
+
+// maxCodestreamSize is the maximum size of a codestream.
+const maxCodestreamSize = 16 * 1024 * 1024
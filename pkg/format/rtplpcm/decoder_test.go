@@ -31,6 +31,13 @@ func TestDecode(t *testing.T) {
 }
 
 func FuzzDecoder(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		for _, pkt := range ca.pkts {
+			f.Add(pkt.Payload)
+		}
+	}
+
 	f.Fuzz(func(_ *testing.T, b []byte) {
 		d := &Decoder{
 			BitDepth:     24,
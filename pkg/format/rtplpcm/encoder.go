@@ -3,6 +3,7 @@ package rtplpcm
 import (
 	"crypto/rand"
 	"fmt"
+	"time"
 
 	"github.com/pion/rtp"
 )
@@ -33,6 +34,14 @@ type Encoder struct {
 	// channel count.
 	ChannelCount int
 
+	// sample rate (optional).
+	// It is needed by PacketDuration.
+	SampleRate int
+
+	// duration of audio contained in each packet (optional).
+	// It defaults to the maximum amount that fits into a packet.
+	PacketDuration time.Duration
+
 	// SSRC of packets (optional).
 	// It defaults to a random value.
 	SSRC *uint32
@@ -74,6 +83,22 @@ func (e *Encoder) Init() error {
 	e.sequenceNumber = *e.InitialSequenceNumber
 	e.sampleSize = e.BitDepth * e.ChannelCount / 8
 	e.maxPayloadSize = (e.PayloadMaxSize / e.sampleSize) * e.sampleSize
+
+	if e.PacketDuration != 0 {
+		if e.SampleRate == 0 {
+			return fmt.Errorf("SampleRate is required when PacketDuration is set")
+		}
+
+		samplesPerPacket := int(int64(e.SampleRate) * int64(e.PacketDuration) / int64(time.Second))
+		if samplesPerPacket == 0 {
+			return fmt.Errorf("PacketDuration is too small")
+		}
+
+		if (samplesPerPacket * e.sampleSize) < e.maxPayloadSize {
+			e.maxPayloadSize = samplesPerPacket * e.sampleSize
+		}
+	}
+
 	return nil
 }
 
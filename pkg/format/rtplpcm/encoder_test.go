@@ -3,6 +3,7 @@ package rtplpcm
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
@@ -86,6 +87,46 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodePacketDuration(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		BitDepth:              24,
+		ChannelCount:          8,
+		SampleRate:            48000,
+		PacketDuration:        1 * time.Millisecond,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode(bytes.Repeat([]byte{0x01, 0x02, 0x03}, 8*96))
+	require.NoError(t, err)
+	require.Equal(t, []*rtp.Packet{
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03}, 8*48),
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				Timestamp:      48,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03}, 8*48),
+		},
+	}, pkts)
+}
+
 func TestEncodeRandomInitialState(t *testing.T) {
 	e := &Encoder{
 		PayloadType:  96,
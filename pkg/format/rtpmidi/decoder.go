@@ -0,0 +1,138 @@
+package rtpmidi
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// Decoder is a RTP/MIDI decoder.
+// The recovery journal is ignored.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6295
+type Decoder struct{}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+func readCommand(buf []byte, pos *int, runningStatus *byte) ([]byte, error) {
+	if len(buf) <= *pos {
+		return nil, fmt.Errorf("command is truncated")
+	}
+
+	start := *pos
+	status := buf[*pos]
+	hasStatus := (status >= 0x80)
+
+	if !hasStatus {
+		if *runningStatus == 0 {
+			return nil, fmt.Errorf("received a data byte without running status")
+		}
+		status = *runningStatus
+	} else {
+		*pos++
+
+		switch {
+		case status < 0xF0:
+			*runningStatus = status
+
+		case status < 0xF8:
+			// system common commands cancel running status
+			*runningStatus = 0
+		}
+	}
+
+	le := dataLength(status)
+
+	if le < 0 {
+		// system exclusive commands end with 0xF7, or
+		// with 0xF0 / 0xF4 in case of segmented commands.
+		for {
+			if len(buf) <= *pos {
+				return nil, fmt.Errorf("command is truncated")
+			}
+
+			b := buf[*pos]
+			*pos++
+
+			if b == 0xF7 || b == 0xF0 || b == 0xF4 {
+				break
+			}
+		}
+
+		return buf[start:*pos], nil
+	}
+
+	if len(buf[*pos:]) < le {
+		return nil, fmt.Errorf("command is truncated")
+	}
+
+	data := buf[*pos : *pos+le]
+	*pos += le
+
+	if hasStatus {
+		return buf[start:*pos], nil
+	}
+
+	// restore the status byte omitted by running status
+	cmd := make([]byte, 1+le)
+	cmd[0] = status
+	copy(cmd[1:], data)
+	return cmd, nil
+}
+
+// Decode decodes MIDI commands from a RTP packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]*Command, error) {
+	buf := pkt.Payload
+
+	if len(buf) < 1 {
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	b := (buf[0] >> 7) != 0
+	z := ((buf[0] >> 5) & 0x01) != 0
+	le := int(buf[0] & 0x0F)
+	pos := 1
+
+	if b {
+		if len(buf) < 2 {
+			return nil, fmt.Errorf("payload is too short")
+		}
+		le = le<<8 | int(buf[1])
+		pos++
+	}
+
+	if len(buf[pos:]) < le {
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	// the recovery journal, if present, follows the MIDI list and is ignored.
+	list := buf[pos : pos+le]
+	pos = 0
+
+	var cmds []*Command
+	var runningStatus byte
+
+	for pos < len(list) {
+		var cmd Command
+
+		if len(cmds) != 0 || z {
+			var err error
+			cmd.DeltaTime, err = readDeltaTime(list, &pos)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		cmd.Data, err = readCommand(list, &pos, &runningStatus)
+		if err != nil {
+			return nil, err
+		}
+
+		cmds = append(cmds, &cmd)
+	}
+
+	return cmds, nil
+}
@@ -0,0 +1,100 @@
+package rtpmidi
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var cmds []*Command
+			elapsed := uint32(0)
+
+			for _, pkt := range ca.pkts {
+				partial, err := d.Decode(pkt)
+				require.NoError(t, err)
+
+				// the delta time of the first command of a packet
+				// is embedded into the RTP timestamp.
+				partial[0].DeltaTime = pkt.Timestamp - elapsed
+
+				for _, cmd := range partial {
+					elapsed += cmd.DeltaTime
+				}
+
+				cmds = append(cmds, partial...)
+			}
+
+			require.Equal(t, ca.cmds, cmds)
+		})
+	}
+}
+
+func TestDecodeRunningStatusAndJournal(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	cmds, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{
+			0x69, 0x0a, 0x90, 0x3c, 0x40, 0x05, 0x3e, 0x40,
+			0x00, 0xf8, // MIDI list
+			0x01, 0x02, 0x03, // recovery journal
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*Command{
+		{
+			DeltaTime: 10,
+			Data:      []byte{0x90, 0x3c, 0x40},
+		},
+		{
+			DeltaTime: 5,
+			Data:      []byte{0x90, 0x3e, 0x40},
+		},
+		{
+			DeltaTime: 0,
+			Data:      []byte{0xf8},
+		},
+	}, cmds)
+}
+
+func FuzzDecoder(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		for _, pkt := range ca.pkts {
+			f.Add(pkt.Payload)
+		}
+	}
+
+	f.Fuzz(func(_ *testing.T, b []byte) {
+		d := &Decoder{}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
@@ -0,0 +1,166 @@
+package rtpmidi
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/MIDI encoder.
+// The recovery journal is not emitted.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6295
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+	maxListLength  int
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+
+	e.maxListLength = e.PayloadMaxSize - 2
+	if e.maxListLength > maxListLength {
+		e.maxListLength = maxListLength
+	}
+
+	return nil
+}
+
+func (e *Encoder) writePacket(cmds []*Command, listLength int, timestamp uint32) *rtp.Packet {
+	var payload []byte
+	pos := 0
+
+	if listLength > 0x0F {
+		payload = make([]byte, 2+listLength)
+		payload[0] = 1<<7 | byte(listLength>>8)
+		payload[1] = byte(listLength)
+		pos = 2
+	} else {
+		payload = make([]byte, 1+listLength)
+		payload[0] = byte(listLength)
+		pos = 1
+	}
+
+	for i, cmd := range cmds {
+		if i != 0 {
+			pos += writeDeltaTime(payload[pos:], cmd.DeltaTime)
+		}
+		pos += copy(payload[pos:], cmd.Data)
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    e.PayloadType,
+			SequenceNumber: e.sequenceNumber,
+			Timestamp:      timestamp,
+			SSRC:           *e.SSRC,
+			Marker:         (listLength != 0),
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return pkt
+}
+
+// Encode encodes MIDI commands into RTP packets.
+// The delta time of the first command is used as timestamp of the first packet.
+func (e *Encoder) Encode(cmds []*Command) ([]*rtp.Packet, error) {
+	var ret []*rtp.Packet
+	var batch []*Command
+	batchSize := 0
+	batchTimestamp := uint32(0)
+	timestamp := uint32(0)
+
+	for _, cmd := range cmds {
+		if len(cmd.Data) == 0 || cmd.Data[0] < 0x80 {
+			return nil, fmt.Errorf("command does not start with a status byte")
+		}
+
+		if cmd.DeltaTime > maxDeltaTime {
+			return nil, fmt.Errorf("delta time is too big")
+		}
+
+		timestamp += cmd.DeltaTime
+
+		size := len(cmd.Data)
+		if batch != nil {
+			size += deltaTimeSize(cmd.DeltaTime)
+		}
+
+		if batch != nil && (batchSize+size) > e.maxListLength {
+			ret = append(ret, e.writePacket(batch, batchSize, batchTimestamp))
+			batch = nil
+			batchSize = 0
+			size = len(cmd.Data)
+		}
+
+		if size > e.maxListLength {
+			return nil, fmt.Errorf("command is too big")
+		}
+
+		if batch == nil {
+			batchTimestamp = timestamp
+		}
+
+		batch = append(batch, cmd)
+		batchSize += size
+	}
+
+	if batch != nil {
+		ret = append(ret, e.writePacket(batch, batchSize, batchTimestamp))
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,90 @@
+// Package rtpmidi contains a RTP/MIDI decoder and encoder.
+package rtpmidi
+
+import (
+	"fmt"
+)
+
+const (
+	// maximum length of the MIDI list.
+	maxListLength = 4095
+
+	// maximum value of a delta time.
+	maxDeltaTime = 1<<28 - 1
+)
+
+// Command is a MIDI command.
+type Command struct {
+	// delta time in clock rate units, relative to the previous command
+	// (or to the RTP timestamp in case of the first command of a packet).
+	DeltaTime uint32
+
+	// MIDI command, including the status byte.
+	Data []byte
+}
+
+func readDeltaTime(buf []byte, pos *int) (uint32, error) {
+	v := uint32(0)
+
+	for i := 0; i < 4; i++ {
+		if len(buf) <= *pos {
+			return 0, fmt.Errorf("delta time is truncated")
+		}
+
+		b := buf[*pos]
+		*pos++
+		v = (v << 7) | uint32(b&0x7F)
+
+		if (b & 0x80) == 0 {
+			return v, nil
+		}
+	}
+
+	return 0, fmt.Errorf("delta time is too long")
+}
+
+func deltaTimeSize(v uint32) int {
+	n := 1
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+	return n
+}
+
+func writeDeltaTime(buf []byte, v uint32) int {
+	n := deltaTimeSize(v)
+
+	for i := n - 1; i >= 0; i-- {
+		buf[i] = byte(v & 0x7F)
+		if i != (n - 1) {
+			buf[i] |= 0x80
+		}
+		v >>= 7
+	}
+
+	return n
+}
+
+// returns the number of data bytes that follow a status byte,
+// or -1 in case of system exclusive commands.
+func dataLength(status byte) int {
+	switch {
+	case status < 0xC0, status >= 0xE0 && status < 0xF0:
+		return 2
+
+	case status < 0xE0:
+		return 1
+
+	case status == 0xF0:
+		return -1
+
+	case status == 0xF1, status == 0xF3:
+		return 1
+
+	case status == 0xF2:
+		return 2
+	}
+
+	return 0
+}
@@ -57,6 +57,14 @@ func TestDecodeErrorMissingPacket(t *testing.T) {
 }
 
 func FuzzDecoder(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		for i, pkt := range ca.pkts {
+			next := ca.pkts[(i+1)%len(ca.pkts)]
+			f.Add(pkt.Payload, next.Payload)
+		}
+	}
+
 	f.Fuzz(func(_ *testing.T, a []byte, b []byte) {
 		d := &Decoder{}
 		d.Init() //nolint:errcheck
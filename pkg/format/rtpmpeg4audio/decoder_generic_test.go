@@ -112,6 +112,18 @@ func TestDecodeGenericErrorMissingPacket(t *testing.T) {
 }
 
 func FuzzDecoderGeneric(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range casesGeneric {
+		if ca.sizeLength != 13 || ca.indexLength != 3 || ca.indexDeltaLength != 3 {
+			continue
+		}
+
+		for i, pkt := range ca.pkts {
+			next := ca.pkts[(i+1)%len(ca.pkts)]
+			f.Add(pkt.Payload, pkt.Marker, next.Payload, next.Marker)
+		}
+	}
+
 	f.Fuzz(func(t *testing.T, a []byte, am bool, b []byte, bm bool) {
 		d := &Decoder{
 			SizeLength:       13,
@@ -104,6 +104,14 @@ func TestDecodeLATMErrorMissingPacket(t *testing.T) {
 }
 
 func FuzzDecoderLATM(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range casesLATM {
+		for i, pkt := range ca.pkts {
+			next := ca.pkts[(i+1)%len(ca.pkts)]
+			f.Add(pkt.Payload, pkt.Marker, next.Payload, next.Marker)
+		}
+	}
+
 	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool) {
 		d := &Decoder{
 			LATM: true,
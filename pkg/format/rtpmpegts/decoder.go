@@ -0,0 +1,41 @@
+package rtpmpegts
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// Decoder is a RTP/MPEG-TS decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc2250
+type Decoder struct{}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+// Decode decodes MPEG-TS packets from a RTP packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	le := len(pkt.Payload)
+
+	if le == 0 || (le%packetSize) != 0 {
+		return nil, fmt.Errorf("payload size (%d) is not a multiple of %d", le, packetSize)
+	}
+
+	n := le / packetSize
+	tsPackets := make([][]byte, n)
+
+	for i := range tsPackets {
+		tsPacket := pkt.Payload[i*packetSize : (i+1)*packetSize]
+
+		err := checkPacket(tsPacket)
+		if err != nil {
+			return nil, err
+		}
+
+		tsPackets[i] = tsPacket
+	}
+
+	return tsPackets, nil
+}
@@ -0,0 +1,78 @@
+package rtpmpegts
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var tsPackets [][]byte
+
+			for _, pkt := range ca.pkts {
+				var tmp [][]byte
+				tmp, err = d.Decode(pkt)
+				require.NoError(t, err)
+				tsPackets = append(tsPackets, tmp...)
+			}
+
+			require.Equal(t, ca.tsPackets, tsPackets)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		payload []byte
+		err     string
+	}{
+		{
+			"unaligned",
+			tsPacket(1)[:100],
+			"payload size (100) is not a multiple of 188",
+		},
+		{
+			"invalid sync byte",
+			append([]byte{0x48}, tsPacket(1)[1:]...),
+			"invalid MPEG-TS sync byte: 0x48",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			_, err = d.Decode(&rtp.Packet{Payload: ca.payload})
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func FuzzDecoder(f *testing.F) {
+	for _, ca := range cases {
+		f.Add(ca.pkts[0].Payload)
+	}
+
+	f.Fuzz(func(_ *testing.T, b []byte) {
+		d := &Decoder{}
+		d.Init()              //nolint:errcheck
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    33,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
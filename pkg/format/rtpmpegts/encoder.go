@@ -0,0 +1,118 @@
+package rtpmpegts
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/MPEG-TS encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc2250
+type Encoder struct {
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+	packetsPerRTP  int
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.packetsPerRTP = e.PayloadMaxSize / packetSize
+	if e.packetsPerRTP == 0 {
+		return fmt.Errorf("payload max size is too small to contain a MPEG-TS packet")
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	return nil
+}
+
+func (e *Encoder) writePacket(tsPackets [][]byte) *rtp.Packet {
+	payload := make([]byte, len(tsPackets)*packetSize)
+	n := 0
+	for _, tsPacket := range tsPackets {
+		n += copy(payload[n:], tsPacket)
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    33,
+			SequenceNumber: e.sequenceNumber,
+			SSRC:           *e.SSRC,
+			Marker:         false,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return pkt
+}
+
+// Encode encodes MPEG-TS packets into RTP packets.
+func (e *Encoder) Encode(tsPackets [][]byte) ([]*rtp.Packet, error) {
+	if len(tsPackets) == 0 {
+		return nil, fmt.Errorf("no MPEG-TS packets provided")
+	}
+
+	for _, tsPacket := range tsPackets {
+		err := checkPacket(tsPacket)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ret []*rtp.Packet
+
+	for len(tsPackets) > 0 {
+		n := min(e.packetsPerRTP, len(tsPackets))
+		ret = append(ret, e.writePacket(tsPackets[:n]))
+		tsPackets = tsPackets[n:]
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,142 @@
+package rtpmpegts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+func tsPacket(b byte) []byte {
+	return append([]byte{0x47}, bytes.Repeat([]byte{b}, 187)...)
+}
+
+var cases = []struct {
+	name      string
+	tsPackets [][]byte
+	pkts      []*rtp.Packet
+}{
+	{
+		"single",
+		[][]byte{tsPacket(1)},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    33,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: tsPacket(1),
+			},
+		},
+	},
+	{
+		"aggregated",
+		[][]byte{tsPacket(1), tsPacket(2), tsPacket(3)},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    33,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(tsPacket(1), tsPacket(2), tsPacket(3)),
+			},
+		},
+	},
+	{
+		"split",
+		[][]byte{
+			tsPacket(1), tsPacket(2), tsPacket(3), tsPacket(4),
+			tsPacket(5), tsPacket(6), tsPacket(7), tsPacket(8),
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    33,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					tsPacket(1), tsPacket(2), tsPacket(3), tsPacket(4),
+					tsPacket(5), tsPacket(6), tsPacket(7),
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    33,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: tsPacket(8),
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.tsPackets)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
+
+func TestEncodeErrorInvalidPacket(t *testing.T) {
+	e := &Encoder{}
+	err := e.Init()
+	require.NoError(t, err)
+
+	_, err = e.Encode([][]byte{{0x47, 0x01, 0x02}})
+	require.EqualError(t, err, "invalid MPEG-TS packet size: 3")
+}
@@ -0,0 +1,26 @@
+// Package rtpmpegts contains a RTP/MPEG-TS decoder and encoder.
+package rtpmpegts
+
+import (
+	"fmt"
+)
+
+const (
+	// size of a MPEG-TS packet.
+	packetSize = 188
+
+	// first byte of every MPEG-TS packet.
+	syncByte = 0x47
+)
+
+func checkPacket(tsPacket []byte) error {
+	if len(tsPacket) != packetSize {
+		return fmt.Errorf("invalid MPEG-TS packet size: %d", len(tsPacket))
+	}
+
+	if tsPacket[0] != syncByte {
+		return fmt.Errorf("invalid MPEG-TS sync byte: 0x%.2x", tsPacket[0])
+	}
+
+	return nil
+}
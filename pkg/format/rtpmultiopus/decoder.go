@@ -0,0 +1,63 @@
+package rtpmultiopus
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// Decoder is a RTP/multistream Opus decoder.
+type Decoder struct {
+	// number of streams.
+	StreamCount int
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	if d.StreamCount <= 0 || d.StreamCount > 255 {
+		return fmt.Errorf("invalid stream count: %d", d.StreamCount)
+	}
+	return nil
+}
+
+// Decode decodes the Opus packets of all streams from a RTP packet.
+// Returned packets use the standard framing.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	buf := pkt.Payload
+	packets := make([][]byte, d.StreamCount)
+
+	for i := 0; i < (d.StreamCount - 1); i++ {
+		l, err := parseSelfDelimited(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		size := l.headerSize + l.bodySize
+		if len(buf) < size {
+			return nil, fmt.Errorf("packet is too short")
+		}
+
+		// remove the self-delimiting length
+		n := lengthSize(l.delimiterValue)
+		packet := make([]byte, size-n)
+		copy(packet, buf[:l.delimiterPos])
+		copy(packet[l.delimiterPos:], buf[l.delimiterPos+n:size])
+
+		packets[i] = packet
+		buf = buf[size:]
+	}
+
+	l, err := parseStandard(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkLayout(l)
+	if err != nil {
+		return nil, err
+	}
+
+	packets[d.StreamCount-1] = buf
+
+	return packets, nil
+}
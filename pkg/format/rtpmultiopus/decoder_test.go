@@ -0,0 +1,112 @@
+package rtpmultiopus
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				StreamCount: len(ca.packets),
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			packets, err := d.Decode(ca.pkt)
+			require.NoError(t, err)
+			require.Equal(t, ca.packets, packets)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		payload []byte
+		err     string
+	}{
+		{
+			"empty",
+			[]byte{},
+			"packet is too short",
+		},
+		{
+			"truncated length",
+			[]byte{0x78},
+			"length is truncated",
+		},
+		{
+			"truncated packet",
+			[]byte{0x78, 0x05, 0x01},
+			"packet is too short",
+		},
+		{
+			"invalid frame count",
+			[]byte{0x7b, 0x80, 0x01},
+			"invalid frame count",
+		},
+		{
+			"missing stream",
+			[]byte{0x78, 0x01, 0x01},
+			"packet is too short",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				StreamCount: 2,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			_, err = d.Decode(&rtp.Packet{Payload: ca.payload})
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func FuzzDecoder(f *testing.F) {
+	for _, ca := range cases {
+		f.Add(uint8(len(ca.packets)), ca.pkt.Payload)
+	}
+
+	f.Fuzz(func(t *testing.T, streamCount uint8, b []byte) {
+		d := &Decoder{
+			StreamCount: int(streamCount),
+		}
+		err := d.Init()
+		if err != nil {
+			return
+		}
+
+		packets, err := d.Decode(&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+		if err != nil {
+			return
+		}
+
+		e := &Encoder{
+			PayloadType:    96,
+			StreamCount:    int(streamCount),
+			SSRC:           uint32Ptr(0x9dbb7812),
+			PayloadMaxSize: len(b),
+		}
+		err = e.Init()
+		require.NoError(t, err)
+
+		pkt, err := e.Encode(packets)
+		require.NoError(t, err)
+		require.Equal(t, b, pkt.Payload)
+	})
+}
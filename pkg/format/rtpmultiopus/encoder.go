@@ -0,0 +1,138 @@
+package rtpmultiopus
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/multistream Opus encoder.
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// number of streams.
+	StreamCount int
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.StreamCount <= 0 || e.StreamCount > 255 {
+		return fmt.Errorf("invalid stream count: %d", e.StreamCount)
+	}
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	return nil
+}
+
+// Encode encodes the Opus packets of all streams into a RTP packet.
+// Packets must use the standard framing.
+func (e *Encoder) Encode(packets [][]byte) (*rtp.Packet, error) {
+	if len(packets) != e.StreamCount {
+		return nil, fmt.Errorf("packet count (%d) doesn't match stream count (%d)", len(packets), e.StreamCount)
+	}
+
+	layouts := make([]*packetLayout, len(packets))
+	size := 0
+
+	for i, packet := range packets {
+		l, err := parseStandard(packet)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkLayout(l)
+		if err != nil {
+			return nil, err
+		}
+
+		layouts[i] = l
+		size += len(packet)
+
+		if i != (len(packets) - 1) {
+			size += lengthSize(l.delimiterValue)
+		}
+	}
+
+	if size > e.PayloadMaxSize {
+		return nil, fmt.Errorf("multistream packet is too big")
+	}
+
+	payload := make([]byte, size)
+	n := 0
+
+	for i, packet := range packets {
+		if i == (len(packets) - 1) {
+			copy(payload[n:], packet)
+			break
+		}
+
+		// add the self-delimiting length
+		l := layouts[i]
+		n += copy(payload[n:], packet[:l.delimiterPos])
+		n += writeLength(payload[n:], l.delimiterValue)
+		n += copy(payload[n:], packet[l.delimiterPos:])
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    e.PayloadType,
+			SequenceNumber: e.sequenceNumber,
+			SSRC:           *e.SSRC,
+			Marker:         false,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return pkt, nil
+}
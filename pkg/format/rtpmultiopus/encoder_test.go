@@ -0,0 +1,145 @@
+package rtpmultiopus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+var cases = []struct {
+	name    string
+	packets [][]byte
+	pkt     *rtp.Packet
+}{
+	{
+		"single stream",
+		[][]byte{
+			{0x78, 0x01, 0x02, 0x03},
+		},
+		&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x78, 0x01, 0x02, 0x03},
+		},
+	},
+	{
+		"all codes",
+		[][]byte{
+			{0x78, 0x01, 0x02, 0x03},                         // code 0
+			{0x79, 0x01, 0x02, 0x01, 0x02},                   // code 1
+			{0x7a, 0x02, 0x01, 0x02, 0x03, 0x04, 0x05},       // code 2
+			{0x7b, 0x82, 0x02, 0x01, 0x02, 0x03, 0x04, 0x05}, // code 3, VBR
+			{0x7b, 0x42, 0x01, 0x01, 0x02, 0x01, 0x02, 0x00}, // code 3, CBR, padding
+		},
+		&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{
+				0x78, 0x03, 0x01, 0x02, 0x03,
+				0x79, 0x02, 0x01, 0x02, 0x01, 0x02,
+				0x7a, 0x02, 0x03, 0x01, 0x02, 0x03, 0x04, 0x05,
+				0x7b, 0x82, 0x02, 0x03, 0x01, 0x02, 0x03, 0x04, 0x05,
+				0x7b, 0x42, 0x01, 0x01, 0x02, 0x01, 0x02, 0x00,
+			},
+		},
+	},
+	{
+		"long frame",
+		[][]byte{
+			mergeBytes([]byte{0x78}, bytes.Repeat([]byte{0x01}, 300)),
+			{0x78, 0x01},
+		},
+		&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: mergeBytes(
+				[]byte{0x78, 0xfc, 0x0c},
+				bytes.Repeat([]byte{0x01}, 300),
+				[]byte{0x78, 0x01},
+			),
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           96,
+				StreamCount:           len(ca.packets),
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkt, err := e.Encode(ca.packets)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkt, pkt)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+		StreamCount: 2,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
+
+func TestEncodeErrorStreamCount(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+		StreamCount: 2,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	_, err = e.Encode([][]byte{{0x78, 0x01}})
+	require.EqualError(t, err, "packet count (1) doesn't match stream count (2)")
+}
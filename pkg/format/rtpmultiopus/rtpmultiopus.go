@@ -0,0 +1,283 @@
+// Package rtpmultiopus contains a RTP decoder and encoder for multistream Opus,
+// used to carry more than 2 channels.
+// Each RTP packet contains a multistream packet, that is made of one Opus packet per stream.
+// All packets except the last one use the self-delimiting framing.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6716#appendix-B
+// Specification: https://datatracker.ietf.org/doc/html/rfc7845#section-5.1.1
+package rtpmultiopus
+
+import (
+	"fmt"
+)
+
+const (
+	// maximum size of a frame.
+	maxFrameSize = 1275
+)
+
+func checkLayout(l *packetLayout) error {
+	if l.delimiterValue > maxFrameSize {
+		return fmt.Errorf("frame size (%d) is too big, maximum is %d", l.delimiterValue, maxFrameSize)
+	}
+	return nil
+}
+
+func readLength(buf []byte) (int, int, error) {
+	if len(buf) < 1 {
+		return 0, 0, fmt.Errorf("length is truncated")
+	}
+
+	if buf[0] < 252 {
+		return int(buf[0]), 1, nil
+	}
+
+	if len(buf) < 2 {
+		return 0, 0, fmt.Errorf("length is truncated")
+	}
+
+	return int(buf[1])*4 + int(buf[0]), 2, nil
+}
+
+func lengthSize(v int) int {
+	if v < 252 {
+		return 1
+	}
+	return 2
+}
+
+func writeLength(buf []byte, v int) int {
+	if v < 252 {
+		buf[0] = byte(v)
+		return 1
+	}
+
+	buf[0] = byte(252 + (v & 0x03))
+	buf[1] = byte((v - int(buf[0])) >> 2)
+	return 2
+}
+
+// packetLayout describes the structure of an Opus packet.
+type packetLayout struct {
+	// size of the TOC byte, the frame count byte, the padding length and the frame lengths.
+	headerSize int
+	// position in the header where the self-delimiting length is placed.
+	delimiterPos int
+	// value of the self-delimiting length.
+	delimiterValue int
+	// size of frames and padding.
+	bodySize int
+}
+
+func parseFrameCountByte(buf []byte) (int, bool, int, int, error) {
+	if len(buf) < 2 {
+		return 0, false, 0, 0, fmt.Errorf("packet is too short")
+	}
+
+	count := int(buf[1] & 0x3F)
+	if count == 0 {
+		return 0, false, 0, 0, fmt.Errorf("invalid frame count")
+	}
+
+	vbr := (buf[1] & 0x80) != 0
+	pos := 2
+	padding := 0
+
+	if (buf[1] & 0x40) != 0 {
+		for {
+			if len(buf) <= pos {
+				return 0, false, 0, 0, fmt.Errorf("padding length is truncated")
+			}
+
+			b := buf[pos]
+			pos++
+
+			if b == 255 {
+				padding += 254
+			} else {
+				padding += int(b)
+				break
+			}
+		}
+	}
+
+	return count, vbr, pos, padding, nil
+}
+
+// parseSelfDelimited parses the layout of a self-delimited packet.
+func parseSelfDelimited(buf []byte) (*packetLayout, error) {
+	if len(buf) < 1 {
+		return nil, fmt.Errorf("packet is too short")
+	}
+
+	switch buf[0] & 0x03 {
+	case 0:
+		le, n, err := readLength(buf[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return &packetLayout{
+			headerSize:     1 + n,
+			delimiterPos:   1,
+			delimiterValue: le,
+			bodySize:       le,
+		}, nil
+
+	case 1:
+		le, n, err := readLength(buf[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return &packetLayout{
+			headerSize:     1 + n,
+			delimiterPos:   1,
+			delimiterValue: le,
+			bodySize:       2 * le,
+		}, nil
+
+	case 2:
+		le1, n1, err := readLength(buf[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		le2, n2, err := readLength(buf[1+n1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return &packetLayout{
+			headerSize:     1 + n1 + n2,
+			delimiterPos:   1 + n1,
+			delimiterValue: le2,
+			bodySize:       le1 + le2,
+		}, nil
+
+	default:
+		count, vbr, pos, padding, err := parseFrameCountByte(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		bodySize := padding
+
+		if vbr {
+			for i := 0; i < (count - 1); i++ {
+				le, n, err := readLength(buf[pos:])
+				if err != nil {
+					return nil, err
+				}
+				pos += n
+				bodySize += le
+			}
+		}
+
+		le, n, err := readLength(buf[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		if vbr {
+			bodySize += le
+		} else {
+			bodySize += count * le
+		}
+
+		return &packetLayout{
+			headerSize:     pos + n,
+			delimiterPos:   pos,
+			delimiterValue: le,
+			bodySize:       bodySize,
+		}, nil
+	}
+}
+
+// parseStandard parses the layout of a packet that uses the standard framing,
+// computing the self-delimiting length that is needed to convert it.
+func parseStandard(buf []byte) (*packetLayout, error) {
+	if len(buf) < 1 {
+		return nil, fmt.Errorf("packet is too short")
+	}
+
+	switch buf[0] & 0x03 {
+	case 0:
+		return &packetLayout{
+			headerSize:     1,
+			delimiterPos:   1,
+			delimiterValue: len(buf) - 1,
+			bodySize:       len(buf) - 1,
+		}, nil
+
+	case 1:
+		if ((len(buf) - 1) % 2) != 0 {
+			return nil, fmt.Errorf("invalid packet size")
+		}
+
+		return &packetLayout{
+			headerSize:     1,
+			delimiterPos:   1,
+			delimiterValue: (len(buf) - 1) / 2,
+			bodySize:       len(buf) - 1,
+		}, nil
+
+	case 2:
+		le1, n1, err := readLength(buf[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		le2 := len(buf) - 1 - n1 - le1
+		if le2 < 0 {
+			return nil, fmt.Errorf("packet is too short")
+		}
+
+		return &packetLayout{
+			headerSize:     1 + n1,
+			delimiterPos:   1 + n1,
+			delimiterValue: le2,
+			bodySize:       le1 + le2,
+		}, nil
+
+	default:
+		count, vbr, pos, padding, err := parseFrameCountByte(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		framesSize := 0
+
+		if vbr {
+			for i := 0; i < (count - 1); i++ {
+				le, n, err := readLength(buf[pos:])
+				if err != nil {
+					return nil, err
+				}
+				pos += n
+				framesSize += le
+			}
+		}
+
+		remaining := len(buf) - pos - padding - framesSize
+		if remaining < 0 {
+			return nil, fmt.Errorf("packet is too short")
+		}
+
+		var le int
+		if vbr {
+			le = remaining
+		} else {
+			if (remaining % count) != 0 {
+				return nil, fmt.Errorf("invalid packet size")
+			}
+			le = remaining / count
+		}
+
+		return &packetLayout{
+			headerSize:     pos,
+			delimiterPos:   pos,
+			delimiterValue: le,
+			bodySize:       len(buf) - pos,
+		}, nil
+	}
+}
@@ -0,0 +1,156 @@
+package rtprawvideo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a frame and we didn't received anything before.
+// It's normal to receive this when decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+type lineSegment struct {
+	length int
+	line   int
+	offset int
+}
+
+// Decoder is a RTP/raw video decoder.
+// It assembles scan lines into frames.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4175
+type Decoder struct {
+	// color space and sampling of the image.
+	Sampling string
+
+	// bit depth of samples.
+	Depth int
+
+	// image size.
+	Width  int
+	Height int
+
+	pg                  pixelGroup
+	stride              int
+	firstPacketReceived bool
+	frame               []byte
+	frameNextSeqNum     uint16
+	frameTimestamp      uint32
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	var err error
+	d.pg, err = findPixelGroup(d.Sampling, d.Depth)
+	if err != nil {
+		return err
+	}
+
+	d.stride, err = lineStride(d.pg, d.Width)
+	if err != nil {
+		return err
+	}
+
+	if d.Height <= 0 {
+		return fmt.Errorf("invalid height: %v", d.Height)
+	}
+
+	return nil
+}
+
+func (d *Decoder) parseSegments(payload []byte) ([]lineSegment, []byte, error) {
+	if len(payload) < extendedSequenceNumberSize {
+		return nil, nil, fmt.Errorf("payload is too short")
+	}
+	payload = payload[extendedSequenceNumberSize:]
+
+	var segments []lineSegment
+
+	for {
+		if len(payload) < lineHeaderSize {
+			return nil, nil, fmt.Errorf("payload is too short")
+		}
+
+		segments = append(segments, lineSegment{
+			length: int(payload[0])<<8 | int(payload[1]),
+			line:   int(payload[2]&0x7F)<<8 | int(payload[3]),
+			offset: int(payload[4]&0x7F)<<8 | int(payload[5]),
+		})
+
+		continuation := (payload[4] >> 7) != 0
+		payload = payload[lineHeaderSize:]
+
+		if !continuation {
+			break
+		}
+	}
+
+	return segments, payload, nil
+}
+
+// Decode decodes a frame from a RTP packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	segments, payload, err := d.parseSegments(pkt.Payload)
+	if err != nil {
+		d.frame = nil
+		return nil, err
+	}
+
+	if segments[0].line == 0 && segments[0].offset == 0 {
+		d.frame = make([]byte, d.stride*d.Height)
+		d.frameTimestamp = pkt.Timestamp
+		d.firstPacketReceived = true
+	} else {
+		if d.frame == nil {
+			if !d.firstPacketReceived {
+				return nil, ErrNonStartingPacketAndNoPrevious
+			}
+
+			return nil, fmt.Errorf("received a non-starting fragment")
+		}
+
+		if pkt.SequenceNumber != d.frameNextSeqNum || pkt.Timestamp != d.frameTimestamp {
+			d.frame = nil
+			return nil, fmt.Errorf("discarding frame since a RTP packet is missing")
+		}
+	}
+
+	for _, seg := range segments {
+		if len(payload) < seg.length {
+			d.frame = nil
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		if (seg.offset%d.pg.pixels) != 0 || (seg.length%d.pg.size) != 0 {
+			d.frame = nil
+			return nil, fmt.Errorf("line segment is not aligned to pixel groups")
+		}
+
+		linePos := (seg.offset / d.pg.pixels) * d.pg.size
+		if seg.line >= d.Height || (linePos+seg.length) > d.stride {
+			d.frame = nil
+			return nil, fmt.Errorf("line segment is out of bounds")
+		}
+
+		copy(d.frame[seg.line*d.stride+linePos:], payload[:seg.length])
+		payload = payload[seg.length:]
+	}
+
+	d.frameNextSeqNum = pkt.SequenceNumber + 1
+
+	if !pkt.Marker {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	frame := d.frame
+	d.frame = nil
+
+	return frame, nil
+}
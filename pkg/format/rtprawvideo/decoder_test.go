@@ -0,0 +1,97 @@
+package rtprawvideo
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				Sampling: "YCbCr-4:2:2",
+				Depth:    8,
+				Width:    4,
+				Height:   2,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var frame []byte
+
+			for _, pkt := range ca.pkts {
+				frame, err = d.Decode(pkt)
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, ca.frame, frame)
+		})
+	}
+}
+
+func TestDecodeErrorMissingPacket(t *testing.T) {
+	d := &Decoder{
+		Sampling: "YCbCr-4:2:2",
+		Depth:    8,
+		Width:    4,
+		Height:   2,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(cases[2].pkts[0])
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	_, err = d.Decode(cases[2].pkts[2])
+	require.EqualError(t, err, "discarding frame since a RTP packet is missing")
+}
+
+func TestDecodeErrorNonStarting(t *testing.T) {
+	d := &Decoder{
+		Sampling: "YCbCr-4:2:2",
+		Depth:    8,
+		Width:    4,
+		Height:   2,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(cases[2].pkts[1])
+	require.Equal(t, ErrNonStartingPacketAndNoPrevious, err)
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool) {
+		d := &Decoder{
+			Sampling: "YCbCr-4:2:2",
+			Depth:    10,
+			Width:    16,
+			Height:   16,
+		}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         am,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: a,
+		})
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         bm,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
@@ -0,0 +1,193 @@
+package rtprawvideo
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/raw video encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4175
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// color space and sampling of the image.
+	Sampling string
+
+	// bit depth of samples.
+	Depth int
+
+	// image size.
+	Width  int
+	Height int
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	pg                     pixelGroup
+	stride                 int
+	sequenceNumber         uint16
+	extendedSequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	var err error
+	e.pg, err = findPixelGroup(e.Sampling, e.Depth)
+	if err != nil {
+		return err
+	}
+
+	e.stride, err = lineStride(e.pg, e.Width)
+	if err != nil {
+		return err
+	}
+
+	if e.Height <= 0 {
+		return fmt.Errorf("invalid height: %v", e.Height)
+	}
+
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	if e.PayloadMaxSize < (extendedSequenceNumberSize + lineHeaderSize + e.pg.size) {
+		return fmt.Errorf("PayloadMaxSize is too small")
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	return nil
+}
+
+// Encode encodes a frame into RTP/raw video packets.
+func (e *Encoder) Encode(frame []byte) ([]*rtp.Packet, error) {
+	if len(frame) != e.stride*e.Height {
+		return nil, fmt.Errorf("frame size (%d) doesn't match image size (%d)", len(frame), e.stride*e.Height)
+	}
+
+	var ret []*rtp.Packet
+	line := 0
+	linePos := 0
+
+	for line < e.Height {
+		var segments []lineSegment
+		avail := e.PayloadMaxSize - extendedSequenceNumberSize
+
+		// fill the packet with line segments
+		for line < e.Height {
+			segLen := ((avail - lineHeaderSize) / e.pg.size) * e.pg.size
+			if segLen <= 0 {
+				break
+			}
+
+			if segLen > (e.stride - linePos) {
+				segLen = e.stride - linePos
+			}
+
+			segments = append(segments, lineSegment{
+				length: segLen,
+				line:   line,
+				offset: (linePos / e.pg.size) * e.pg.pixels,
+			})
+			avail -= lineHeaderSize + segLen
+
+			linePos += segLen
+			if linePos == e.stride {
+				line++
+				linePos = 0
+			}
+		}
+
+		ret = append(ret, e.writePacket(frame, segments, line == e.Height))
+	}
+
+	return ret, nil
+}
+
+func (e *Encoder) writePacket(frame []byte, segments []lineSegment, marker bool) *rtp.Packet {
+	size := extendedSequenceNumberSize
+	for _, seg := range segments {
+		size += lineHeaderSize + seg.length
+	}
+
+	payload := make([]byte, size)
+	payload[0] = byte(e.extendedSequenceNumber >> 8)
+	payload[1] = byte(e.extendedSequenceNumber)
+	n := extendedSequenceNumberSize
+
+	for i, seg := range segments {
+		payload[n] = byte(seg.length >> 8)
+		payload[n+1] = byte(seg.length)
+		payload[n+2] = byte(seg.line>>8) & 0x7F
+		payload[n+3] = byte(seg.line)
+		payload[n+4] = byte(seg.offset>>8) & 0x7F
+		if i != (len(segments) - 1) {
+			payload[n+4] |= 0x80
+		}
+		payload[n+5] = byte(seg.offset)
+		n += lineHeaderSize
+	}
+
+	for _, seg := range segments {
+		pos := seg.line*e.stride + (seg.offset/e.pg.pixels)*e.pg.size
+		n += copy(payload[n:], frame[pos:pos+seg.length])
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    e.PayloadType,
+			SequenceNumber: e.sequenceNumber,
+			SSRC:           *e.SSRC,
+			Marker:         marker,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+	if e.sequenceNumber == 0 {
+		e.extendedSequenceNumber++
+	}
+
+	return pkt
+}
@@ -0,0 +1,199 @@
+package rtprawvideo
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+var testFrame = []byte{
+	0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+	0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+}
+
+var cases = []struct {
+	name           string
+	payloadMaxSize int
+	frame          []byte
+	pkts           []*rtp.Packet
+}{
+	{
+		"single",
+		1460,
+		testFrame,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x08, 0x00, 0x00, 0x80, 0x00,
+					0x00, 0x08, 0x00, 0x01, 0x00, 0x00,
+					0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+					0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+				},
+			},
+		},
+	},
+	{
+		"line per packet",
+		20,
+		testFrame,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x08, 0x00, 0x00, 0x00, 0x00,
+					0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x08, 0x00, 0x01, 0x00, 0x00,
+					0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+				},
+			},
+		},
+	},
+	{
+		"split lines",
+		12,
+		testFrame,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x04, 0x00, 0x00, 0x00, 0x00,
+					0x01, 0x02, 0x03, 0x04,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x04, 0x00, 0x00, 0x00, 0x02,
+					0x05, 0x06, 0x07, 0x08,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x04, 0x00, 0x01, 0x00, 0x00,
+					0x09, 0x0a, 0x0b, 0x0c,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17648,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x04, 0x00, 0x01, 0x00, 0x02,
+					0x0d, 0x0e, 0x0f, 0x10,
+				},
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           96,
+				Sampling:              "YCbCr-4:2:2",
+				Depth:                 8,
+				Width:                 4,
+				Height:                2,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+				PayloadMaxSize:        ca.payloadMaxSize,
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.frame)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+		Sampling:    "RGB",
+		Depth:       8,
+		Width:       4,
+		Height:      2,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
+
+func TestEncodeErrorUnsupported(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+		Sampling:    "YCbCr-4:2:0",
+		Depth:       8,
+		Width:       4,
+		Height:      2,
+	}
+	err := e.Init()
+	require.EqualError(t, err, "unsupported sampling: YCbCr-4:2:0")
+}
@@ -0,0 +1,76 @@
+// Package rtprawvideo contains a RTP/raw video decoder and encoder.
+package rtprawvideo
+
+import (
+	"fmt"
+)
+
+const (
+	extendedSequenceNumberSize = 2
+	lineHeaderSize             = 6
+)
+
+type pixelGroup struct {
+	// size of the pixel group, in bytes.
+	size int
+
+	// pixels contained in the pixel group.
+	pixels int
+}
+
+// pixel groups of supported samplings and depths.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4175#section-4.3
+var pixelGroups = map[string]map[int]pixelGroup{
+	"RGB": {
+		8:  {3, 1},
+		10: {15, 4},
+		12: {9, 2},
+		16: {6, 1},
+	},
+	"RGBA": {
+		8:  {4, 1},
+		10: {5, 1},
+		12: {6, 1},
+		16: {8, 1},
+	},
+	"YCbCr-4:2:2": {
+		8:  {4, 2},
+		10: {5, 2},
+		12: {6, 2},
+		16: {8, 2},
+	},
+	"YCbCr-4:1:1": {
+		8:  {6, 4},
+		10: {15, 8},
+		12: {9, 4},
+		16: {12, 4},
+	},
+}
+
+func init() {
+	pixelGroups["BGR"] = pixelGroups["RGB"]
+	pixelGroups["YCbCr-4:4:4"] = pixelGroups["RGB"]
+	pixelGroups["BGRA"] = pixelGroups["RGBA"]
+}
+
+func findPixelGroup(sampling string, depth int) (pixelGroup, error) {
+	depths, ok := pixelGroups[sampling]
+	if !ok {
+		return pixelGroup{}, fmt.Errorf("unsupported sampling: %v", sampling)
+	}
+
+	pg, ok := depths[depth]
+	if !ok {
+		return pixelGroup{}, fmt.Errorf("unsupported depth: %v", depth)
+	}
+
+	return pg, nil
+}
+
+func lineStride(pg pixelGroup, width int) (int, error) {
+	if width <= 0 || (width%pg.pixels) != 0 {
+		return 0, fmt.Errorf("invalid width: %v", width)
+	}
+
+	return (width / pg.pixels) * pg.size, nil
+}
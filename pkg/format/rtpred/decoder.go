@@ -0,0 +1,76 @@
+package rtpred
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// Block is a redundant block contained in a RED packet.
+type Block struct {
+	// payload type of the block.
+	PayloadType uint8
+
+	// difference between the timestamp of the RED packet and the timestamp of the block.
+	TimestampOffset uint16
+
+	// block payload.
+	Payload []byte
+}
+
+// Decoder is a RTP/RED decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc2198
+type Decoder struct{}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+// Decode decodes a RED packet into the RTP packet of the primary encoding
+// and into the redundant blocks, ordered from the oldest to the newest.
+func (d *Decoder) Decode(pkt *rtp.Packet) (*rtp.Packet, []Block, error) {
+	buf := pkt.Payload
+	var redundant []Block
+	var lengths []int
+
+	for {
+		if len(buf) < 1 {
+			return nil, nil, fmt.Errorf("payload is too short")
+		}
+
+		// last header, describing the primary encoding
+		if (buf[0] & 0x80) == 0 {
+			primaryPayloadType := buf[0] & 0x7F
+			buf = buf[1:]
+
+			for i, le := range lengths {
+				if len(buf) < le {
+					return nil, nil, fmt.Errorf("payload is too short")
+				}
+
+				redundant[i].Payload = buf[:le]
+				buf = buf[le:]
+			}
+
+			primary := &rtp.Packet{
+				Header:  pkt.Header,
+				Payload: buf,
+			}
+			primary.Header.PayloadType = primaryPayloadType
+
+			return primary, redundant, nil
+		}
+
+		if len(buf) < 4 {
+			return nil, nil, fmt.Errorf("payload is too short")
+		}
+
+		redundant = append(redundant, Block{
+			PayloadType:     buf[0] & 0x7F,
+			TimestampOffset: uint16(buf[1])<<6 | uint16(buf[2])>>2,
+		})
+		lengths = append(lengths, int(buf[2]&0x03)<<8|int(buf[3]))
+		buf = buf[4:]
+	}
+}
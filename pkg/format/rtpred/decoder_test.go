@@ -0,0 +1,102 @@
+package rtpred
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	primary, redundant, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    121,
+			SequenceNumber: 123,
+			Timestamp:      45678,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{
+			0x80 | 111, 0x03, 0xc0, 0x03, // redundant block, offset 240, length 3
+			111,              // primary block
+			0x01, 0x02, 0x03, // redundant data
+			0x04, 0x05, // primary data
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    111,
+			SequenceNumber: 123,
+			Timestamp:      45678,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x04, 0x05},
+	}, primary)
+
+	require.Equal(t, []Block{{
+		PayloadType:     111,
+		TimestampOffset: 240,
+		Payload:         []byte{0x01, 0x02, 0x03},
+	}}, redundant)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		payload []byte
+	}{
+		{
+			"empty",
+			[]byte{},
+		},
+		{
+			"truncated header",
+			[]byte{0x80 | 111, 0x03},
+		},
+		{
+			"truncated block",
+			[]byte{0x80 | 111, 0x03, 0xc0, 0x03, 111, 0x01},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			_, _, err = d.Decode(&rtp.Packet{Payload: ca.payload})
+			require.Error(t, err)
+		})
+	}
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Add([]byte{
+		0x80 | 111, 0x03, 0xc0, 0x03,
+		111,
+		0x01, 0x02, 0x03,
+		0x04, 0x05,
+	})
+
+	f.Fuzz(func(_ *testing.T, b []byte) {
+		d := &Decoder{}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    121,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
@@ -0,0 +1,2 @@
+// Package rtpred contains a RTP decoder for redundant data (RED).
+package rtpred
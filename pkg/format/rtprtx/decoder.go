@@ -0,0 +1,44 @@
+package rtprtx
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// Decoder is a RTP/RTX decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4588
+type Decoder struct {
+	// payload type of the associated (original) format.
+	AssociatedPayloadType uint8
+
+	// SSRC of the original stream (optional).
+	// When it is nil, the SSRC of retransmission packets is kept.
+	AssociatedSSRC *uint32
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+// Decode restores the original packet from a retransmission packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) (*rtp.Packet, error) {
+	if len(pkt.Payload) < 2 {
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	orig := &rtp.Packet{
+		Header:  pkt.Header,
+		Payload: pkt.Payload[2:],
+	}
+	orig.Header.PayloadType = d.AssociatedPayloadType
+	orig.Header.SequenceNumber = uint16(pkt.Payload[0])<<8 | uint16(pkt.Payload[1])
+	orig.Header.Padding = false
+
+	if d.AssociatedSSRC != nil {
+		orig.Header.SSRC = *d.AssociatedSSRC
+	}
+
+	return orig, nil
+}
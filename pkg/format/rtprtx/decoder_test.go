@@ -0,0 +1,55 @@
+package rtprtx
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				AssociatedPayloadType: 96,
+				AssociatedSSRC:        uint32Ptr(0x12345678),
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			orig, err := d.Decode(ca.pkt)
+			require.NoError(t, err)
+			require.Equal(t, ca.orig, orig)
+		})
+	}
+}
+
+func TestDecodeErrorTooShort(t *testing.T) {
+	d := &Decoder{
+		AssociatedPayloadType: 96,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{Payload: []byte{0x01}})
+	require.EqualError(t, err, "payload is too short")
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, b []byte) {
+		d := &Decoder{
+			AssociatedPayloadType: 96,
+		}
+		d.Init()              //nolint:errcheck
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    97,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
@@ -0,0 +1,88 @@
+package rtprtx
+
+import (
+	"crypto/rand"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion = 2
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/RTX encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4588
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	// It must differ from the SSRC of the original stream.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	return nil
+}
+
+// Encode encodes an original packet into a retransmission packet.
+func (e *Encoder) Encode(orig *rtp.Packet) (*rtp.Packet, error) {
+	payload := make([]byte, 2+len(orig.Payload))
+	payload[0] = byte(orig.SequenceNumber >> 8)
+	payload[1] = byte(orig.SequenceNumber)
+	copy(payload[2:], orig.Payload)
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:          rtpVersion,
+			Marker:           orig.Marker,
+			PayloadType:      e.PayloadType,
+			SequenceNumber:   e.sequenceNumber,
+			Timestamp:        orig.Timestamp,
+			SSRC:             *e.SSRC,
+			CSRC:             orig.CSRC,
+			Extension:        orig.Extension,
+			ExtensionProfile: orig.ExtensionProfile,
+			Extensions:       orig.Extensions,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return pkt, nil
+}
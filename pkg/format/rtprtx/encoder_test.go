@@ -0,0 +1,76 @@
+package rtprtx
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+var cases = []struct {
+	name string
+	orig *rtp.Packet
+	pkt  *rtp.Packet
+}{
+	{
+		"standard",
+		&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 1234,
+				Timestamp:      45678,
+				SSRC:           0x12345678,
+			},
+			Payload: []byte{0x01, 0x02, 0x03, 0x04},
+		},
+		&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    97,
+				SequenceNumber: 17645,
+				Timestamp:      45678,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x04, 0xd2, 0x01, 0x02, 0x03, 0x04},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           97,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkt, err := e.Encode(ca.orig)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkt, pkt)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 97,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
@@ -0,0 +1,2 @@
+// Package rtprtx contains a RTP retransmission (RTX) decoder and encoder.
+package rtprtx
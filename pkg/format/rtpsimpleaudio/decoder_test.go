@@ -3,6 +3,7 @@ package rtpsimpleaudio
 import (
 	"testing"
 
+	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,3 +20,26 @@ func TestDecode(t *testing.T) {
 		})
 	}
 }
+
+func FuzzDecoder(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		f.Add(ca.pkt.Payload)
+	}
+
+	f.Fuzz(func(_ *testing.T, b []byte) {
+		d := &Decoder{}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    0,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
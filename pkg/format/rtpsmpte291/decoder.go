@@ -0,0 +1,86 @@
+package rtpsmpte291
+
+import (
+	"fmt"
+
+	"github.com/bluenviron/mediacommon/pkg/bits"
+	"github.com/pion/rtp"
+)
+
+// Decoder is a RTP/SMPTE ST 291 decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc8331
+type Decoder struct{}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+// Decode decodes ancillary data packets from a RTP packet.
+// The end of a frame or field is signaled by the marker bit of the RTP packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]*ANCPacket, error) {
+	if len(pkt.Payload) < headerSize {
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	le := int(pkt.Payload[2])<<8 | int(pkt.Payload[3])
+	count := int(pkt.Payload[4])
+	buf := pkt.Payload[headerSize:]
+
+	if len(buf) < le {
+		return nil, fmt.Errorf("payload is too short")
+	}
+	buf = buf[:le]
+
+	ancs := make([]*ANCPacket, count)
+	pos := 0
+
+	for i := 0; i < count; i++ {
+		err := bits.HasSpace(buf, pos, 32+10*3)
+		if err != nil {
+			return nil, err
+		}
+
+		anc := &ANCPacket{
+			ColorDifference:  bits.ReadFlagUnsafe(buf, &pos),
+			LineNumber:       uint16(bits.ReadBitsUnsafe(buf, &pos, 11)),
+			HorizontalOffset: uint16(bits.ReadBitsUnsafe(buf, &pos, 12)),
+			StreamNumValid:   bits.ReadFlagUnsafe(buf, &pos),
+			StreamNum:        uint8(bits.ReadBitsUnsafe(buf, &pos, 7)),
+		}
+
+		did := uint16(bits.ReadBitsUnsafe(buf, &pos, 10))
+		sdid := uint16(bits.ReadBitsUnsafe(buf, &pos, 10))
+		dc := uint16(bits.ReadBitsUnsafe(buf, &pos, 10))
+		anc.DID = uint8(did)
+		anc.SDID = uint8(sdid)
+
+		words := []uint16{did, sdid, dc}
+
+		err = bits.HasSpace(buf, pos, 10*(int(uint8(dc))+1))
+		if err != nil {
+			return nil, err
+		}
+
+		anc.UserData = make([]byte, uint8(dc))
+		for j := range anc.UserData {
+			w := uint16(bits.ReadBitsUnsafe(buf, &pos, 10))
+			anc.UserData[j] = uint8(w)
+			words = append(words, w)
+		}
+
+		checksum := uint16(bits.ReadBitsUnsafe(buf, &pos, 10))
+		if (checksum & 0x1FF) != (computeChecksum(words) & 0x1FF) {
+			return nil, fmt.Errorf("checksum mismatch")
+		}
+
+		// word_align
+		if (pos % 32) != 0 {
+			pos += 32 - (pos % 32)
+		}
+
+		ancs[i] = anc
+	}
+
+	return ancs, nil
+}
@@ -0,0 +1,76 @@
+package rtpsmpte291
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var ancs []*ANCPacket
+
+			for _, pkt := range ca.pkts {
+				var addANCs []*ANCPacket
+				addANCs, err = d.Decode(pkt)
+				require.NoError(t, err)
+				ancs = append(ancs, addANCs...)
+			}
+
+			require.Equal(t, ca.ancs, ancs)
+		})
+	}
+}
+
+func TestDecodeErrorChecksum(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{
+			0x00, 0x00, 0x00, 0x0c, 0x01, 0x00, 0x00, 0x00,
+			0x00, 0x90, 0x00, 0x00, 0x58, 0x50, 0x14, 0x0a,
+			0x96, 0x9a, 0x66, 0x20,
+		},
+	})
+	require.EqualError(t, err, "checksum mismatch")
+}
+
+func FuzzDecoder(f *testing.F) {
+	// use packets of test cases as seed corpus.
+	for _, ca := range cases {
+		for _, pkt := range ca.pkts {
+			f.Add(pkt.Payload)
+		}
+	}
+
+	f.Fuzz(func(_ *testing.T, b []byte) {
+		d := &Decoder{}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
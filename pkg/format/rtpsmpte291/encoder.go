@@ -0,0 +1,162 @@
+package rtpsmpte291
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/bluenviron/mediacommon/pkg/bits"
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+// Encoder is a RTP/SMPTE ST 291 encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc8331
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber         uint16
+	extendedSequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	return nil
+}
+
+// Encode encodes the ancillary data packets of a frame or field into RTP packets.
+func (e *Encoder) Encode(ancs []*ANCPacket) ([]*rtp.Packet, error) {
+	var rets []*rtp.Packet
+	var batch []*ANCPacket
+	batchSize := 0
+
+	for _, anc := range ancs {
+		if len(anc.UserData) > 255 {
+			return nil, fmt.Errorf("user data is too big")
+		}
+
+		size := anc.marshalSize()
+		if (headerSize + size) > e.PayloadMaxSize {
+			return nil, fmt.Errorf("ANC packet doesn't fit into a RTP packet")
+		}
+
+		if batch != nil && ((headerSize+batchSize+size) > e.PayloadMaxSize || len(batch) == 255) {
+			rets = append(rets, e.writeBatch(batch, batchSize, false))
+			batch = nil
+			batchSize = 0
+		}
+
+		batch = append(batch, anc)
+		batchSize += size
+	}
+
+	rets = append(rets, e.writeBatch(batch, batchSize, true))
+
+	return rets, nil
+}
+
+func (e *Encoder) writeBatch(ancs []*ANCPacket, size int, marker bool) *rtp.Packet {
+	payload := make([]byte, headerSize+size)
+	payload[0] = byte(e.extendedSequenceNumber >> 8)
+	payload[1] = byte(e.extendedSequenceNumber)
+	payload[2] = byte(size >> 8)
+	payload[3] = byte(size)
+	payload[4] = byte(len(ancs))
+
+	buf := payload[headerSize:]
+	pos := 0
+
+	for _, anc := range ancs {
+		start := pos
+
+		if anc.ColorDifference {
+			bits.WriteBitsUnsafe(buf, &pos, 1, 1)
+		} else {
+			bits.WriteBitsUnsafe(buf, &pos, 0, 1)
+		}
+		bits.WriteBitsUnsafe(buf, &pos, uint64(anc.LineNumber), 11)
+		bits.WriteBitsUnsafe(buf, &pos, uint64(anc.HorizontalOffset), 12)
+		if anc.StreamNumValid {
+			bits.WriteBitsUnsafe(buf, &pos, 1, 1)
+		} else {
+			bits.WriteBitsUnsafe(buf, &pos, 0, 1)
+		}
+		bits.WriteBitsUnsafe(buf, &pos, uint64(anc.StreamNum), 7)
+
+		words := make([]uint16, 0, 3+len(anc.UserData))
+		words = append(words, addParity(anc.DID), addParity(anc.SDID), addParity(uint8(len(anc.UserData))))
+		for _, b := range anc.UserData {
+			words = append(words, addParity(b))
+		}
+		words = append(words, computeChecksum(words))
+
+		for _, w := range words {
+			bits.WriteBitsUnsafe(buf, &pos, uint64(w), 10)
+		}
+
+		pos = start + anc.marshalSize()*8
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    e.PayloadType,
+			SequenceNumber: e.sequenceNumber,
+			SSRC:           *e.SSRC,
+			Marker:         marker,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+	if e.sequenceNumber == 0 {
+		e.extendedSequenceNumber++
+	}
+
+	return pkt
+}
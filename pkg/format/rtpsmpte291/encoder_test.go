@@ -0,0 +1,131 @@
+package rtpsmpte291
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+var cases = []struct {
+	name string
+	ancs []*ANCPacket
+	pkts []*rtp.Packet
+}{
+	{
+		"single",
+		[]*ANCPacket{{
+			LineNumber: 9,
+			DID:        0x61,
+			SDID:       0x01,
+			UserData:   []byte{0x96, 0x69},
+		}},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00, 0x00, 0x0c, 0x01, 0x00, 0x00, 0x00,
+					0x00, 0x90, 0x00, 0x00, 0x58, 0x50, 0x14, 0x0a,
+					0x96, 0x9a, 0x66, 0x30,
+				},
+			},
+		},
+	},
+	{
+		"multiple",
+		[]*ANCPacket{
+			{
+				LineNumber: 9,
+				DID:        0x61,
+				SDID:       0x01,
+				UserData:   []byte{0x96, 0x69},
+			},
+			{
+				LineNumber:       10,
+				HorizontalOffset: 0xFFF,
+				StreamNumValid:   true,
+				StreamNum:        1,
+				DID:              0x41,
+				SDID:             0x05,
+				UserData:         []byte{0x01, 0x02, 0x03, 0x04, 0x05},
+			},
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00, 0x00, 0x1c, 0x02, 0x00, 0x00, 0x00,
+					0x00, 0x90, 0x00, 0x00, 0x58, 0x50, 0x14, 0x0a,
+					0x96, 0x9a, 0x66, 0x30, 0x00, 0xaf, 0xff, 0x81,
+					0x90, 0x60, 0x58, 0x15, 0x01, 0x40, 0xa0, 0x34,
+					0x12, 0x05, 0x56, 0x80,
+				},
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           96,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.ancs)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeSplit(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		PayloadMaxSize:        30,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode(cases[1].ancs)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(pkts))
+	require.Equal(t, false, pkts[0].Marker)
+	require.Equal(t, true, pkts[1].Marker)
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
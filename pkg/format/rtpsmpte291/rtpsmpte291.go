@@ -0,0 +1,58 @@
+// Package rtpsmpte291 contains a RTP/SMPTE ST 291 ancillary data decoder and encoder.
+package rtpsmpte291
+
+const (
+	headerSize = 8
+)
+
+// ANCPacket is a SMPTE ST 291 ancillary data packet.
+type ANCPacket struct {
+	// whether the packet is carried in the color-difference data channel.
+	ColorDifference bool
+
+	// interface line number. 0x7FF means unspecified.
+	LineNumber uint16
+
+	// horizontal offset. 0xFFF means unspecified.
+	HorizontalOffset uint16
+
+	// whether StreamNum is valid.
+	StreamNumValid bool
+
+	// stream number.
+	StreamNum uint8
+
+	// data identifier.
+	DID uint8
+
+	// secondary data identifier.
+	SDID uint8
+
+	// user data words, without parity bits.
+	UserData []byte
+}
+
+func (p *ANCPacket) marshalSize() int {
+	bits := 32 + 10*(3+len(p.UserData)+1)
+	words := (bits + 31) / 32
+	return words * 4
+}
+
+// addParity converts a 8-bit value into a 10-bit word,
+// by adding an even parity bit (b8) and its inverse (b9).
+func addParity(v uint8) uint16 {
+	p := uint16(0)
+	for i := 0; i < 8; i++ {
+		p ^= uint16(v>>i) & 1
+	}
+	return (^p&1)<<9 | p<<8 | uint16(v)
+}
+
+func computeChecksum(words []uint16) uint16 {
+	sum := uint16(0)
+	for _, w := range words {
+		sum += w & 0x1FF
+	}
+	sum &= 0x1FF
+	return (^(sum>>8)&1)<<9 | sum
+}
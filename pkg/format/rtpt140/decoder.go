@@ -0,0 +1,77 @@
+package rtpt140
+
+import (
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpred"
+)
+
+// Decoder is a RTP/T.140 decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4103
+type Decoder struct {
+	// whether packets are RED packets that contain redundant generations
+	// of T.140 blocks in addition to the primary one.
+	RED bool
+
+	redDec         *rtpred.Decoder
+	initialized    bool
+	expectedSeqNum uint16
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	if d.RED {
+		d.redDec = &rtpred.Decoder{}
+		err := d.redDec.Init()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decode decodes text from a RTP packet.
+// Text lost in transit is recovered from redundant generations when possible,
+// otherwise it is replaced by the U+FFFD character.
+// The returned text can be empty.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	primary := pkt
+	var redundant []rtpred.Block
+
+	if d.RED {
+		var err error
+		primary, redundant, err = d.redDec.Decode(pkt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lost := 0
+	if d.initialized {
+		lost = int(pkt.SequenceNumber - d.expectedSeqNum)
+		// packet is old or duplicated
+		if lost >= 0x8000 {
+			return nil, nil
+		}
+	}
+
+	d.initialized = true
+	d.expectedSeqNum = pkt.SequenceNumber + 1
+
+	var text []byte
+
+	if lost > len(redundant) {
+		text = append(text, lossMarker...)
+		lost = len(redundant)
+	}
+
+	// redundant blocks are ordered from the oldest to the newest
+	for _, block := range redundant[len(redundant)-lost:] {
+		text = append(text, block.Payload...)
+	}
+
+	text = append(text, primary.Payload...)
+
+	return text, nil
+}
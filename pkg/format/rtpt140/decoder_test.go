@@ -0,0 +1,97 @@
+package rtpt140
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				RED: ca.redundancy != 0,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var text []byte
+
+			for _, pkt := range ca.pkts {
+				var buf []byte
+				buf, err = d.Decode(pkt)
+				require.NoError(t, err)
+				text = append(text, buf...)
+			}
+
+			require.Equal(t, bytes.Join(ca.texts, nil), text)
+		})
+	}
+}
+
+func TestDecodeRecoverPacket(t *testing.T) {
+	d := &Decoder{
+		RED: true,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	text, err := d.Decode(cases[1].pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, []byte("ab"), text)
+
+	text, err = d.Decode(cases[1].pkts[2])
+	require.NoError(t, err)
+	require.Equal(t, []byte("cde"), text)
+}
+
+func TestDecodeMissingPacket(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				RED: ca.redundancy != 0,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			text, err := d.Decode(ca.pkts[0])
+			require.NoError(t, err)
+			require.Equal(t, ca.texts[0], text)
+
+			pkt := *ca.pkts[2]
+			pkt.SequenceNumber += 5
+
+			text, err = d.Decode(&pkt)
+			require.NoError(t, err)
+			require.Equal(t, []byte("�"), text[:3])
+		})
+	}
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Add(true, []byte{
+		0x80 | 98, 0x04, 0xb0, 0x02,
+		98,
+		'a', 'b',
+		'c',
+	})
+
+	f.Fuzz(func(_ *testing.T, red bool, b []byte) {
+		d := &Decoder{
+			RED: red,
+		}
+		d.Init()              //nolint:errcheck
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    100,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
@@ -0,0 +1,175 @@
+package rtpt140
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+
+	// maximum timestamp offset and length of a redundant block.
+	maxTimestampOffset = 1<<14 - 1
+	maxBlockLength     = 1<<10 - 1
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+type generation struct {
+	timestamp uint32
+	text      []byte
+}
+
+// Encoder is a RTP/T.140 encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4103
+type Encoder struct {
+	// payload type of T.140 blocks.
+	PayloadType uint8
+
+	// payload type of RED packets (optional).
+	// It is used only when RedundancyLevel is greater than zero.
+	RedundancyPayloadType uint8
+
+	// number of redundant generations of T.140 blocks carried by each packet (optional).
+	// When it is greater than zero, RED packets are generated.
+	RedundancyLevel int
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+	generations    []generation
+	idle           bool
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	e.generations = make([]generation, e.RedundancyLevel)
+	e.idle = true
+	return nil
+}
+
+func (e *Encoder) encodeRED(text []byte, timestamp uint32) ([]byte, error) {
+	size := 1 + len(text)
+	for _, g := range e.generations {
+		size += 4 + len(g.text)
+	}
+
+	if size > e.PayloadMaxSize {
+		return nil, fmt.Errorf("text is too big")
+	}
+
+	payload := make([]byte, size)
+	n := 0
+
+	for _, g := range e.generations {
+		offset := timestamp - g.timestamp
+		if g.text == nil || offset > maxTimestampOffset {
+			offset = 0
+		}
+
+		payload[n] = 0x80 | e.PayloadType
+		payload[n+1] = byte(offset >> 6)
+		payload[n+2] = byte(offset<<2) | byte(len(g.text)>>8)
+		payload[n+3] = byte(len(g.text))
+		n += 4
+	}
+
+	payload[n] = e.PayloadType
+	n++
+
+	for _, g := range e.generations {
+		n += copy(payload[n:], g.text)
+	}
+
+	copy(payload[n:], text)
+
+	return payload, nil
+}
+
+// Encode encodes text into a RTP packet.
+// Text can be empty, in order to send the last redundant generations.
+func (e *Encoder) Encode(text []byte, timestamp uint32) (*rtp.Packet, error) {
+	var payload []byte
+	payloadType := e.PayloadType
+
+	if e.RedundancyLevel > 0 {
+		if len(text) > maxBlockLength {
+			return nil, fmt.Errorf("text is too big")
+		}
+
+		var err error
+		payload, err = e.encodeRED(text, timestamp)
+		if err != nil {
+			return nil, err
+		}
+
+		e.generations = append(e.generations[1:], generation{
+			timestamp: timestamp,
+			text:      text,
+		})
+		payloadType = e.RedundancyPayloadType
+	} else {
+		if len(text) > e.PayloadMaxSize {
+			return nil, fmt.Errorf("text is too big")
+		}
+		payload = text
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    payloadType,
+			SequenceNumber: e.sequenceNumber,
+			Timestamp:      timestamp,
+			SSRC:           *e.SSRC,
+			// the marker bit signals the first packet after an idle period.
+			Marker: e.idle && len(text) != 0,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+	e.idle = len(text) == 0
+
+	return pkt, nil
+}
@@ -0,0 +1,178 @@
+package rtpt140
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+var cases = []struct {
+	name       string
+	redundancy int
+	texts      [][]byte
+	timestamps []uint32
+	pkts       []*rtp.Packet
+}{
+	{
+		"plain",
+		0,
+		[][]byte{
+			[]byte("hello"),
+			[]byte(" world"),
+			{},
+		},
+		[]uint32{1000, 1300, 1600},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    98,
+					SequenceNumber: 17645,
+					Timestamp:      1000,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte("hello"),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    98,
+					SequenceNumber: 17646,
+					Timestamp:      1300,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte(" world"),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    98,
+					SequenceNumber: 17647,
+					Timestamp:      1600,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{},
+			},
+		},
+	},
+	{
+		"red",
+		2,
+		[][]byte{
+			[]byte("ab"),
+			[]byte("c"),
+			[]byte("de"),
+		},
+		[]uint32{1000, 1300, 1600},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    100,
+					SequenceNumber: 17645,
+					Timestamp:      1000,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x80 | 98, 0x00, 0x00, 0x00,
+					0x80 | 98, 0x00, 0x00, 0x00,
+					98,
+					'a', 'b',
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    100,
+					SequenceNumber: 17646,
+					Timestamp:      1300,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x80 | 98, 0x00, 0x00, 0x00,
+					0x80 | 98, 0x04, 0xb0, 0x02, // offset 300, length 2
+					98,
+					'a', 'b',
+					'c',
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    100,
+					SequenceNumber: 17647,
+					Timestamp:      1600,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x80 | 98, 0x09, 0x60, 0x02, // offset 600, length 2
+					0x80 | 98, 0x04, 0xb0, 0x01, // offset 300, length 1
+					98,
+					'a', 'b',
+					'c',
+					'd', 'e',
+				},
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           98,
+				RedundancyPayloadType: 100,
+				RedundancyLevel:       ca.redundancy,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			for i, text := range ca.texts {
+				pkt, err := e.Encode(text, ca.timestamps[i])
+				require.NoError(t, err)
+				require.Equal(t, ca.pkts[i], pkt)
+			}
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 98,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
+
+func TestEncodeTooBig(t *testing.T) {
+	e := &Encoder{
+		PayloadType:     98,
+		RedundancyLevel: 2,
+		PayloadMaxSize:  10,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	_, err = e.Encode([]byte("hello"), 0)
+	require.EqualError(t, err, "text is too big")
+}
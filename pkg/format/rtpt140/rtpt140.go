@@ -0,0 +1,5 @@
+// Package rtpt140 contains a RTP/T.140 decoder and encoder.
+package rtpt140
+
+// replacement character, inserted in place of text lost in transit.
+var lossMarker = []byte("�")
@@ -0,0 +1,167 @@
+package rtptheora
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a fragmented frame and we didn't received anything before.
+// It's normal to receive this when decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+func joinFragments(fragments [][]byte, size int) []byte {
+	ret := make([]byte, size)
+	n := 0
+	for _, p := range fragments {
+		n += copy(ret[n:], p)
+	}
+	return ret
+}
+
+// Decoder is a RTP/Theora decoder.
+// Specification: https://datatracker.ietf.org/doc/html/draft-barbato-avt-rtp-theora-01
+type Decoder struct {
+	firstPacketReceived bool
+	fragments           [][]byte
+	fragmentsSize       int
+	fragmentNextSeqNum  uint16
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragments = d.fragments[:0]
+	d.fragmentsSize = 0
+}
+
+// Decode decodes Theora packets from a RTP packet.
+// Configuration and comment packets delivered in-band are not supported.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	if len(pkt.Payload) < headerSize {
+		d.resetFragments()
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	var h header
+	h.unmarshal(pkt.Payload)
+	buf := pkt.Payload[headerSize:]
+
+	if h.dataType != dataTypeRaw {
+		d.resetFragments()
+		return nil, fmt.Errorf("unsupported data type: %d", h.dataType)
+	}
+
+	switch h.fragmentType {
+	case fragmentTypeNone:
+		d.resetFragments()
+		d.firstPacketReceived = true
+
+		if h.packetCount == 0 {
+			return nil, fmt.Errorf("invalid packet count")
+		}
+
+		frames := make([][]byte, h.packetCount)
+
+		for i := range frames {
+			if len(buf) < 2 {
+				return nil, fmt.Errorf("payload is too short")
+			}
+
+			le := int(buf[0])<<8 | int(buf[1])
+			buf = buf[2:]
+
+			if len(buf) < le {
+				return nil, fmt.Errorf("payload is too short")
+			}
+
+			frames[i] = buf[:le]
+			buf = buf[le:]
+		}
+
+		return frames, nil
+
+	case fragmentTypeStart:
+		d.resetFragments()
+		d.firstPacketReceived = true
+
+		if h.packetCount != 0 {
+			return nil, fmt.Errorf("invalid packet count")
+		}
+
+		if len(buf) < 2 {
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		le := int(buf[0])<<8 | int(buf[1])
+		buf = buf[2:]
+
+		if len(buf) != le {
+			return nil, fmt.Errorf("invalid fragment size")
+		}
+
+		d.fragmentsSize = le
+		d.fragments = append(d.fragments, buf)
+		d.fragmentNextSeqNum = pkt.SequenceNumber + 1
+
+		return nil, ErrMorePacketsNeeded
+	}
+
+	// continuation or end fragment
+
+	if len(d.fragments) == 0 {
+		if !d.firstPacketReceived {
+			return nil, ErrNonStartingPacketAndNoPrevious
+		}
+
+		return nil, fmt.Errorf("received a non-starting fragment")
+	}
+
+	if pkt.SequenceNumber != d.fragmentNextSeqNum {
+		d.resetFragments()
+		return nil, fmt.Errorf("discarding frame since a RTP packet is missing")
+	}
+
+	if len(buf) < 2 {
+		d.resetFragments()
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	le := int(buf[0])<<8 | int(buf[1])
+	buf = buf[2:]
+
+	if len(buf) != le {
+		d.resetFragments()
+		return nil, fmt.Errorf("invalid fragment size")
+	}
+
+	d.fragmentsSize += le
+
+	if d.fragmentsSize > maxFrameSize {
+		errSize := d.fragmentsSize
+		d.resetFragments()
+		return nil, fmt.Errorf("frame size (%d) is too big, maximum is %d", errSize, maxFrameSize)
+	}
+
+	d.fragments = append(d.fragments, buf)
+	d.fragmentNextSeqNum++
+
+	if h.fragmentType != fragmentTypeEnd {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	frame := joinFragments(d.fragments, d.fragmentsSize)
+	d.resetFragments()
+
+	return [][]byte{frame}, nil
+}
@@ -0,0 +1,178 @@
+package rtptheora
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+func packetCount(avail, le int) int {
+	n := le / avail
+	if (le % avail) != 0 {
+		n++
+	}
+	return n
+}
+
+// Encoder is a RTP/Theora encoder.
+// Specification: https://datatracker.ietf.org/doc/html/draft-barbato-avt-rtp-theora-01
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// identifier of the configuration.
+	Ident uint32
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	return nil
+}
+
+// Encode encodes a Theora packet into RTP packets.
+func (e *Encoder) Encode(frame []byte) ([]*rtp.Packet, error) {
+	if len(frame) > maxFrameSize {
+		return nil, fmt.Errorf("frame is too big")
+	}
+
+	if (headerSize + 2 + len(frame)) <= e.PayloadMaxSize {
+		return []*rtp.Packet{e.writeSingle(frame)}, nil
+	}
+
+	return e.writeFragmented(frame)
+}
+
+func (e *Encoder) writeSingle(frame []byte) *rtp.Packet {
+	payload := make([]byte, headerSize+2+len(frame))
+
+	header{
+		ident:        e.Ident,
+		fragmentType: fragmentTypeNone,
+		dataType:     dataTypeRaw,
+		packetCount:  1,
+	}.marshalTo(payload)
+
+	payload[headerSize] = byte(len(frame) >> 8)
+	payload[headerSize+1] = byte(len(frame))
+	copy(payload[headerSize+2:], frame)
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    e.PayloadType,
+			SequenceNumber: e.sequenceNumber,
+			SSRC:           *e.SSRC,
+			Marker:         true,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return pkt
+}
+
+func (e *Encoder) writeFragmented(frame []byte) ([]*rtp.Packet, error) {
+	avail := e.PayloadMaxSize - headerSize - 2
+	if avail <= 0 {
+		return nil, fmt.Errorf("PayloadMaxSize is too small")
+	}
+
+	packetCount := packetCount(avail, len(frame))
+	ret := make([]*rtp.Packet, packetCount)
+
+	for i := range ret {
+		le := avail
+		if le > len(frame) {
+			le = len(frame)
+		}
+
+		var fragmentType uint8
+		switch i {
+		case 0:
+			fragmentType = fragmentTypeStart
+		case packetCount - 1:
+			fragmentType = fragmentTypeEnd
+		default:
+			fragmentType = fragmentTypeContinuation
+		}
+
+		payload := make([]byte, headerSize+2+le)
+
+		header{
+			ident:        e.Ident,
+			fragmentType: fragmentType,
+			dataType:     dataTypeRaw,
+			packetCount:  0,
+		}.marshalTo(payload)
+
+		payload[headerSize] = byte(le >> 8)
+		payload[headerSize+1] = byte(le)
+		copy(payload[headerSize+2:], frame[:le])
+		frame = frame[le:]
+
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				SSRC:           *e.SSRC,
+				Marker:         (i == packetCount-1),
+			},
+			Payload: payload,
+		}
+
+		e.sequenceNumber++
+	}
+
+	return ret, nil
+}
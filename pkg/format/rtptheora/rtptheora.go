@@ -0,0 +1,39 @@
+// Package rtptheora contains a RTP/Theora decoder and encoder.
+package rtptheora
+
+const (
+	headerSize = 4
+
+	// maximum size of a frame.
+	maxFrameSize = 4 * 1024 * 1024
+
+	fragmentTypeNone         = 0
+	fragmentTypeStart        = 1
+	fragmentTypeContinuation = 2
+	fragmentTypeEnd          = 3
+
+	dataTypeRaw = 0
+)
+
+// payload header.
+// Specification: https://datatracker.ietf.org/doc/html/draft-barbato-avt-rtp-theora-01#section-2.2
+type header struct {
+	ident        uint32
+	fragmentType uint8
+	dataType     uint8
+	packetCount  uint8
+}
+
+func (h *header) unmarshal(buf []byte) {
+	h.ident = uint32(buf[0])<<16 | uint32(buf[1])<<8 | uint32(buf[2])
+	h.fragmentType = buf[3] >> 6
+	h.dataType = (buf[3] >> 4) & 0x03
+	h.packetCount = buf[3] & 0x0F
+}
+
+func (h header) marshalTo(buf []byte) {
+	buf[0] = byte(h.ident >> 16)
+	buf[1] = byte(h.ident >> 8)
+	buf[2] = byte(h.ident)
+	buf[3] = h.fragmentType<<6 | h.dataType<<4 | h.packetCount
+}
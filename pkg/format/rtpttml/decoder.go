@@ -0,0 +1,90 @@
+package rtpttml
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+func joinFragments(fragments [][]byte, size int) []byte {
+	ret := make([]byte, size)
+	n := 0
+	for _, p := range fragments {
+		n += copy(ret[n:], p)
+	}
+	return ret
+}
+
+// Decoder is a RTP/TTML decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc8759
+type Decoder struct {
+	fragments          [][]byte
+	fragmentsSize      int
+	fragmentNextSeqNum uint16
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragments = d.fragments[:0]
+	d.fragmentsSize = 0
+}
+
+// Decode decodes a TTML document from a RTP packet.
+// Documents are returned once the packet with the marker bit is received.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	if len(pkt.Payload) < headerSize {
+		d.resetFragments()
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	le := int(pkt.Payload[2])<<8 | int(pkt.Payload[3])
+	if le != (len(pkt.Payload) - headerSize) {
+		d.resetFragments()
+		return nil, fmt.Errorf("invalid length field: %d", le)
+	}
+
+	words := pkt.Payload[headerSize:]
+
+	if d.fragmentsSize == 0 {
+		if pkt.Marker {
+			return words, nil
+		}
+
+		d.fragmentsSize = len(words)
+		d.fragments = append(d.fragments, words)
+		d.fragmentNextSeqNum = pkt.SequenceNumber + 1
+		return nil, ErrMorePacketsNeeded
+	}
+
+	if pkt.SequenceNumber != d.fragmentNextSeqNum {
+		d.resetFragments()
+		return nil, fmt.Errorf("discarding document since a RTP packet is missing")
+	}
+
+	d.fragmentsSize += len(words)
+
+	if d.fragmentsSize > maxDocumentSize {
+		d.resetFragments()
+		return nil, fmt.Errorf("document size (%d) is too big, maximum is %d", d.fragmentsSize, maxDocumentSize)
+	}
+
+	d.fragments = append(d.fragments, words)
+	d.fragmentNextSeqNum++
+
+	if !pkt.Marker {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	doc := joinFragments(d.fragments, d.fragmentsSize)
+	d.resetFragments()
+
+	return doc, nil
+}
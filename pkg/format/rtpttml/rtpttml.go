@@ -0,0 +1,10 @@
+// Package rtpttml contains a RTP/TTML decoder and encoder.
+package rtpttml
+
+const (
+	// maximum size of a TTML document.
+	maxDocumentSize = 1024 * 1024
+
+	// size of the payload header (reserved and length fields).
+	headerSize = 4
+)
@@ -0,0 +1,128 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtprtx"
+)
+
+// RTX is the RTP format for retransmissions of another format,
+// that must be defined in the same media.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4588
+type RTX struct {
+	PayloadTyp uint8
+	ClockRat   int
+
+	// payload type of the format whose packets are retransmitted.
+	AssociatedPayloadType uint8
+
+	// time in milliseconds during which packets are kept available
+	// for retransmission (optional).
+	RTXTime int
+}
+
+func (f *RTX) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	tmp, err := strconv.ParseUint(ctx.clock, 10, 31)
+	if err != nil || tmp == 0 {
+		return fmt.Errorf("invalid clock rate: '%s'", ctx.clock)
+	}
+	f.ClockRat = int(tmp)
+
+	aptFound := false
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "apt":
+			tmp, err = strconv.ParseUint(val, 10, 7)
+			if err != nil {
+				return fmt.Errorf("invalid apt: %v", val)
+			}
+			f.AssociatedPayloadType = uint8(tmp)
+			aptFound = true
+
+		case "rtx-time":
+			tmp, err = strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid rtx-time: %v", val)
+			}
+			f.RTXTime = int(tmp)
+		}
+	}
+
+	if !aptFound {
+		return fmt.Errorf("apt is missing")
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *RTX) Codec() string {
+	return "RTX"
+}
+
+// ClockRate implements Format.
+func (f *RTX) ClockRate() int {
+	return f.ClockRat
+}
+
+// PayloadType implements Format.
+func (f *RTX) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *RTX) RTPMap() string {
+	return "rtx/" + strconv.FormatInt(int64(f.ClockRat), 10)
+}
+
+// FMTP implements Format.
+func (f *RTX) FMTP() map[string]string {
+	fmtp := map[string]string{
+		"apt": strconv.FormatUint(uint64(f.AssociatedPayloadType), 10),
+	}
+
+	if f.RTXTime != 0 {
+		fmtp["rtx-time"] = strconv.FormatInt(int64(f.RTXTime), 10)
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *RTX) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to restore original packets from retransmissions.
+func (f *RTX) CreateDecoder() (*rtprtx.Decoder, error) {
+	d := &rtprtx.Decoder{
+		AssociatedPayloadType: f.AssociatedPayloadType,
+	}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to wrap original packets into retransmissions.
+func (f *RTX) CreateEncoder() (*rtprtx.Encoder, error) {
+	e := &rtprtx.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
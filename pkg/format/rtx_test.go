@@ -0,0 +1,54 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTXAttributes(t *testing.T) {
+	format := &RTX{
+		PayloadTyp:            97,
+		ClockRat:              90000,
+		AssociatedPayloadType: 96,
+	}
+	require.Equal(t, "RTX", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestRTXDecEncoder(t *testing.T) {
+	format := &RTX{
+		PayloadTyp:            97,
+		ClockRat:              90000,
+		AssociatedPayloadType: 96,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	orig := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 1234,
+			Timestamp:      45678,
+			SSRC:           0x12345678,
+		},
+		Payload: []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	pkt, err := enc.Encode(orig)
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkt.PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	orig2, err := dec.Decode(pkt)
+	require.NoError(t, err)
+	require.Equal(t, uint8(96), orig2.PayloadType)
+	require.Equal(t, uint16(1234), orig2.SequenceNumber)
+	require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, orig2.Payload)
+}
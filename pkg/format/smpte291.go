@@ -0,0 +1,152 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpsmpte291"
+)
+
+// SMPTE291DataIdentifier is a DID/SDID pair of ancillary data packets.
+type SMPTE291DataIdentifier struct {
+	DID  uint8
+	SDID uint8
+}
+
+func (i *SMPTE291DataIdentifier) unmarshal(val string) error {
+	if !strings.HasPrefix(val, "{") || !strings.HasSuffix(val, "}") {
+		return fmt.Errorf("invalid DID_SDID: %v", val)
+	}
+
+	parts := strings.Split(val[1:len(val)-1], ",")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid DID_SDID: %v", val)
+	}
+
+	tmp, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 0, 8)
+	if err != nil {
+		return fmt.Errorf("invalid DID_SDID: %v", val)
+	}
+	i.DID = uint8(tmp)
+
+	tmp, err = strconv.ParseUint(strings.TrimSpace(parts[1]), 0, 8)
+	if err != nil {
+		return fmt.Errorf("invalid DID_SDID: %v", val)
+	}
+	i.SDID = uint8(tmp)
+
+	return nil
+}
+
+func (i SMPTE291DataIdentifier) marshal() string {
+	return fmt.Sprintf("{0x%02X,0x%02X}", i.DID, i.SDID)
+}
+
+// SMPTE291 is the RTP format for SMPTE ST 291 ancillary data,
+// that carries closed captions, timecodes and other metadata.
+// Specification: https://datatracker.ietf.org/doc/html/rfc8331
+type SMPTE291 struct {
+	PayloadTyp uint8
+
+	// DID/SDID pairs of the ancillary data packets that can be found in the stream (optional).
+	DataIdentifiers []SMPTE291DataIdentifier
+
+	// video payload identifier code of the associated video (optional).
+	VPIDCode int
+}
+
+func (f *SMPTE291) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	// DID_SDID can be repeated, therefore it is parsed from the raw fmtp
+	for _, kv := range strings.Split(ctx.rawFMTP, ";") {
+		tmp := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+		if len(tmp) == 2 && strings.ToLower(tmp[0]) == "did_sdid" {
+			var i SMPTE291DataIdentifier
+			err := i.unmarshal(tmp[1])
+			if err != nil {
+				return err
+			}
+			f.DataIdentifiers = append(f.DataIdentifiers, i)
+		}
+	}
+
+	if val, ok := ctx.fmtp["vpid_code"]; ok {
+		tmp, err := strconv.ParseUint(val, 10, 31)
+		if err != nil {
+			return fmt.Errorf("invalid VPID_Code: %v", val)
+		}
+		f.VPIDCode = int(tmp)
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *SMPTE291) Codec() string {
+	return "SMPTE 291"
+}
+
+// ClockRate implements Format.
+func (f *SMPTE291) ClockRate() int {
+	return 90000
+}
+
+// PayloadType implements Format.
+func (f *SMPTE291) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *SMPTE291) RTPMap() string {
+	return "smpte291/90000"
+}
+
+// FMTP implements Format.
+// Since DID_SDID can be repeated, each pair is returned as a key without value.
+func (f *SMPTE291) FMTP() map[string]string {
+	fmtp := make(map[string]string)
+
+	for _, i := range f.DataIdentifiers {
+		fmtp["DID_SDID="+i.marshal()] = ""
+	}
+	if f.VPIDCode != 0 {
+		fmtp["VPID_Code"] = strconv.FormatInt(int64(f.VPIDCode), 10)
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *SMPTE291) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *SMPTE291) CreateDecoder() (*rtpsmpte291.Decoder, error) {
+	d := &rtpsmpte291.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *SMPTE291) CreateEncoder() (*rtpsmpte291.Encoder, error) {
+	e := &rtpsmpte291.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
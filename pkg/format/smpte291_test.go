@@ -0,0 +1,46 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpsmpte291"
+)
+
+func TestSMPTE291Attributes(t *testing.T) {
+	format := &SMPTE291{
+		PayloadTyp: 100,
+	}
+	require.Equal(t, "SMPTE 291", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestSMPTE291DecEncoder(t *testing.T) {
+	format := &SMPTE291{
+		PayloadTyp: 100,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	ancs := []*rtpsmpte291.ANCPacket{{
+		LineNumber: 9,
+		DID:        0x61,
+		SDID:       0x01,
+		UserData:   []byte{0x01, 0x02, 0x03},
+	}}
+
+	pkts, err := enc.Encode(ancs)
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	dec2, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, ancs, dec2)
+}
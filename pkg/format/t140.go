@@ -0,0 +1,141 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpt140"
+)
+
+// T140 is the RTP format for real-time text.
+// Redundancy is provided by a RED format defined in the same media.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4103
+type T140 struct {
+	PayloadTyp uint8
+
+	// maximum number of characters per second (optional).
+	CPS int
+}
+
+func (f *T140) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	for key, val := range ctx.fmtp {
+		if key == "cps" {
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil || tmp == 0 {
+				return fmt.Errorf("invalid cps: %v", val)
+			}
+			f.CPS = int(tmp)
+		}
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *T140) Codec() string {
+	return "T140"
+}
+
+// ClockRate implements Format.
+func (f *T140) ClockRate() int {
+	return 1000
+}
+
+// PayloadType implements Format.
+func (f *T140) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *T140) RTPMap() string {
+	return "t140/1000"
+}
+
+// FMTP implements Format.
+func (f *T140) FMTP() map[string]string {
+	if f.CPS == 0 {
+		return nil
+	}
+
+	return map[string]string{
+		"cps": strconv.FormatInt(int64(f.CPS), 10),
+	}
+}
+
+// PTSEqualsDTS implements Format.
+func (f *T140) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *T140) CreateDecoder() (*rtpt140.Decoder, error) {
+	d := &rtpt140.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *T140) CreateEncoder() (*rtpt140.Encoder, error) {
+	e := &rtpt140.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// CreateREDDecoder creates a decoder able to decode the content of the format
+// when it is carried by a RED format.
+func (f *T140) CreateREDDecoder() (*rtpt140.Decoder, error) {
+	d := &rtpt140.Decoder{
+		RED: true,
+	}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateREDEncoder creates an encoder able to encode the content of the format
+// into packets of the given RED format.
+// The number of redundant generations is deduced from the encodings of the RED format.
+func (f *T140) CreateREDEncoder(red *RED) (*rtpt140.Encoder, error) {
+	if len(red.EncodingPayloadTypes) < 2 {
+		return nil, fmt.Errorf("RED format does not contain redundant encodings")
+	}
+
+	for _, pt := range red.EncodingPayloadTypes {
+		if pt != f.PayloadTyp {
+			return nil, fmt.Errorf("RED format contains encodings of a different format")
+		}
+	}
+
+	e := &rtpt140.Encoder{
+		PayloadType:           f.PayloadTyp,
+		RedundancyPayloadType: red.PayloadTyp,
+		RedundancyLevel:       len(red.EncodingPayloadTypes) - 1,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
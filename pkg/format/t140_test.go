@@ -0,0 +1,71 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestT140Attributes(t *testing.T) {
+	format := &T140{
+		PayloadTyp: 98,
+		CPS:        30,
+	}
+	require.Equal(t, "T140", format.Codec())
+	require.Equal(t, 1000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestT140DecEncoder(t *testing.T) {
+	format := &T140{
+		PayloadTyp: 98,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkt, err := enc.Encode([]byte("hello"), 1000)
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkt.PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	text, err := dec.Decode(pkt)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), text)
+}
+
+func TestT140REDDecEncoder(t *testing.T) {
+	format := &T140{
+		PayloadTyp: 98,
+	}
+
+	red := &RED{
+		PayloadTyp:           100,
+		ClockRat:             1000,
+		EncodingPayloadTypes: []uint8{98, 98, 98},
+	}
+
+	enc, err := format.CreateREDEncoder(red)
+	require.NoError(t, err)
+
+	pkt1, err := enc.Encode([]byte("hel"), 1000)
+	require.NoError(t, err)
+	require.Equal(t, red.PayloadType(), pkt1.PayloadType)
+
+	pkt2, err := enc.Encode([]byte("lo"), 1300)
+	require.NoError(t, err)
+
+	dec, err := format.CreateREDDecoder()
+	require.NoError(t, err)
+
+	text, err := dec.Decode(pkt1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hel"), text)
+
+	text, err = dec.Decode(pkt2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("lo"), text)
+}
@@ -0,0 +1,145 @@
+package format
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtptheora"
+)
+
+// Theora is the RTP format for the Theora codec.
+// Specification: https://datatracker.ietf.org/doc/html/draft-barbato-avt-rtp-theora-01
+type Theora struct {
+	PayloadTyp uint8
+	Sampling   string
+	Width      int
+	Height     int
+
+	// packed headers, in the same format of the Vorbis configuration.
+	Configuration []byte
+}
+
+func (f *Theora) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "sampling":
+			f.Sampling = val
+
+		case "width":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid width: %v", val)
+			}
+			f.Width = int(tmp)
+
+		case "height":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid height: %v", val)
+			}
+			f.Height = int(tmp)
+
+		case "delivery-method":
+			if val != "inline" {
+				return fmt.Errorf("unsupported delivery method: %v", val)
+			}
+
+		case "configuration":
+			conf, err := base64.StdEncoding.DecodeString(val)
+			if err != nil {
+				return fmt.Errorf("invalid config: %v", val)
+			}
+
+			f.Configuration = conf
+		}
+	}
+
+	if f.Configuration == nil {
+		return fmt.Errorf("config is missing")
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *Theora) Codec() string {
+	return "Theora"
+}
+
+// ClockRate implements Format.
+func (f *Theora) ClockRate() int {
+	return 90000
+}
+
+// PayloadType implements Format.
+func (f *Theora) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *Theora) RTPMap() string {
+	return "theora/90000"
+}
+
+// FMTP implements Format.
+func (f *Theora) FMTP() map[string]string {
+	fmtp := map[string]string{
+		"delivery-method": "inline",
+		"configuration":   base64.StdEncoding.EncodeToString(f.Configuration),
+	}
+
+	if f.Sampling != "" {
+		fmtp["sampling"] = f.Sampling
+	}
+
+	if f.Width != 0 {
+		fmtp["width"] = strconv.FormatInt(int64(f.Width), 10)
+	}
+
+	if f.Height != 0 {
+		fmtp["height"] = strconv.FormatInt(int64(f.Height), 10)
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *Theora) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *Theora) CreateDecoder() (*rtptheora.Decoder, error) {
+	d := &rtptheora.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *Theora) CreateEncoder() (*rtptheora.Encoder, error) {
+	e := &rtptheora.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	// packed headers start with their count (32 bits), followed by the identifier (24 bits)
+	if len(f.Configuration) >= 7 {
+		e.Ident = uint32(f.Configuration[4])<<16 | uint32(f.Configuration[5])<<8 | uint32(f.Configuration[6])
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,46 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTheoraAttributes(t *testing.T) {
+	format := &Theora{
+		PayloadTyp:    96,
+		Sampling:      "YCbCr-4:2:0",
+		Width:         640,
+		Height:        480,
+		Configuration: []byte{0x00, 0x00, 0x00, 0x01, 0xc8, 0x3a, 0x15},
+	}
+	require.Equal(t, "Theora", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestTheoraDecEncoder(t *testing.T) {
+	format := &Theora{
+		PayloadTyp:    96,
+		Sampling:      "YCbCr-4:2:0",
+		Width:         640,
+		Height:        480,
+		Configuration: []byte{0x00, 0x00, 0x00, 0x01, 0xc8, 0x3a, 0x15},
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode([]byte{0x01, 0x02, 0x03, 0x04})
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+	require.Equal(t, []byte{0xc8, 0x3a, 0x15}, pkts[0].Payload[:3])
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	byts, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x01, 0x02, 0x03, 0x04}}, byts)
+}
@@ -0,0 +1,107 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpttml"
+)
+
+// TTML is the RTP format for Timed Text Markup Language documents.
+// Specification: https://datatracker.ietf.org/doc/html/rfc8759
+type TTML struct {
+	PayloadTyp uint8
+	ClockRat   int
+
+	// profiles the documents conform to (optional).
+	Codecs string
+}
+
+func (f *TTML) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	tmp, err := strconv.ParseUint(ctx.clock, 10, 31)
+	if err != nil || tmp == 0 {
+		return fmt.Errorf("invalid clock rate: '%s'", ctx.clock)
+	}
+	f.ClockRat = int(tmp)
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "codecs":
+			f.Codecs = val
+
+		case "charset":
+			// RFC8759: the only allowed character set is UTF-8.
+			if val != "UTF-8" && val != "utf-8" {
+				return fmt.Errorf("unsupported charset: %v", val)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *TTML) Codec() string {
+	return "TTML"
+}
+
+// ClockRate implements Format.
+func (f *TTML) ClockRate() int {
+	return f.ClockRat
+}
+
+// PayloadType implements Format.
+func (f *TTML) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *TTML) RTPMap() string {
+	return "ttml+xml/" + strconv.FormatInt(int64(f.ClockRat), 10)
+}
+
+// FMTP implements Format.
+func (f *TTML) FMTP() map[string]string {
+	if f.Codecs == "" {
+		return nil
+	}
+
+	return map[string]string{
+		"codecs": f.Codecs,
+	}
+}
+
+// PTSEqualsDTS implements Format.
+func (f *TTML) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *TTML) CreateDecoder() (*rtpttml.Decoder, error) {
+	d := &rtpttml.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *TTML) CreateEncoder() (*rtpttml.Encoder, error) {
+	e := &rtpttml.Encoder{
+		PayloadType: f.PayloadTyp,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,39 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTMLAttributes(t *testing.T) {
+	format := &TTML{
+		PayloadTyp: 96,
+		ClockRat:   1000,
+	}
+	require.Equal(t, "TTML", format.Codec())
+	require.Equal(t, 1000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestTTMLDecEncoder(t *testing.T) {
+	format := &TTML{
+		PayloadTyp: 96,
+		ClockRat:   1000,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode([]byte("<tt xmlns=\"http://www.w3.org/ns/ttml\"/>"))
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	byts, err := dec.Decode(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, []byte("<tt xmlns=\"http://www.w3.org/ns/ttml\"/>"), byts)
+}
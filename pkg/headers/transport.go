@@ -121,6 +121,10 @@ type Transport struct {
 	// (optional) Source IP
 	Source *net.IP
 
+	// (optional) Source host name, when the source is not an IP.
+	// It is not resolved.
+	SourceHost string
+
 	// (optional) destination IP
 	Destination *net.IP
 
@@ -189,16 +193,10 @@ func (h *Transport) Unmarshal(v base.HeaderValue) error {
 			if v != "" {
 				ip := net.ParseIP(v)
 				if ip == nil {
-					addrs, err2 := net.LookupHost(v)
-					if err2 != nil {
-						return fmt.Errorf("invalid source (%v)", v)
-					}
-					ip = net.ParseIP(addrs[0])
-					if ip == nil {
-						return fmt.Errorf("invalid source (%v)", v)
-					}
+					h.SourceHost = v
+				} else {
+					h.Source = &ip
 				}
-				h.Source = &ip
 			}
 
 		case "destination":
@@ -292,6 +290,8 @@ func (h Transport) Marshal() base.HeaderValue {
 
 	if h.Source != nil {
 		rets = append(rets, "source="+h.Source.String())
+	} else if h.SourceHost != "" {
+		rets = append(rets, "source="+h.SourceHost)
 	}
 
 	if h.Destination != nil {
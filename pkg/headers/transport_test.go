@@ -156,6 +156,19 @@ var casesTransport = []struct {
 			ServerPorts: &[2]int{3046, 3047},
 		},
 	},
+	{
+		"source host name",
+		base.HeaderValue{`RTP/AVP;multicast;source=camera.local;destination=225.219.201.15;port=7000-7001;ttl=127`},
+		base.HeaderValue{`RTP/AVP;multicast;source=camera.local;destination=225.219.201.15;port=7000-7001;ttl=127`},
+		Transport{
+			Protocol:    TransportProtocolUDP,
+			Delivery:    deliveryPtr(TransportDeliveryMulticast),
+			SourceHost:  "camera.local",
+			Destination: ipPtr(net.ParseIP("225.219.201.15")),
+			TTL:         uintPtr(127),
+			Ports:       &[2]int{7000, 7001},
+		},
+	},
 	{
 		"invalid ssrc",
 		base.HeaderValue{`RTP/AVP;unicast;client_port=14236;source=172.16.8.2;server_port=56002;ssrc=1449463210`},
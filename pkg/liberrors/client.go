@@ -179,6 +179,19 @@ func (e ErrClientUDPTimeout) Error() string {
 	return "UDP timeout"
 }
 
+// ErrClientMulticastSilence is an error that can be returned by a client.
+type ErrClientMulticastSilence struct {
+	Rejoin bool
+}
+
+// Error implements the error interface.
+func (e ErrClientMulticastSilence) Error() string {
+	if e.Rejoin {
+		return "no multicast packets received recently, rejoining groups"
+	}
+	return "no multicast packets received recently, leaving groups"
+}
+
 // ErrClientTCPTimeout is an error that can be returned by a client.
 type ErrClientTCPTimeout struct{}
 
@@ -228,7 +241,15 @@ type ErrClientRTSPSTCP struct{}
 
 // Error implements the error interface.
 func (e ErrClientRTSPSTCP) Error() string {
-	return "RTSPS can be used only with TCP"
+	return "RTSPS can be used only with TCP, or with UDP when UnencryptedMedia is set"
+}
+
+// ErrClientMediaMustBeEncrypted is an error that can be returned by a client.
+type ErrClientMediaMustBeEncrypted struct{}
+
+// Error implements the error interface.
+func (e ErrClientMediaMustBeEncrypted) Error() string {
+	return "media must be encrypted and can't be setupped with the UDP transport"
 }
 
 // ErrClientUnhandledMethod is an error that can be returned by a client.
@@ -241,6 +262,52 @@ func (e ErrClientUnhandledMethod) Error() string {
 	return fmt.Sprintf("unhandled method: %v", e.Method)
 }
 
+// ErrClientRedirectLocationInvalid is an error that can be returned by a client.
+type ErrClientRedirectLocationInvalid struct{}
+
+// Error implements the error interface.
+func (e ErrClientRedirectLocationInvalid) Error() string {
+	return "REDIRECT request contains an invalid Location header"
+}
+
+// ErrClientRedirected is an error that can be returned by a client.
+type ErrClientRedirected struct {
+	URL *base.URL
+}
+
+// Error implements the error interface.
+func (e ErrClientRedirected) Error() string {
+	return fmt.Sprintf("redirected to %v", e.URL.CloneWithoutCredentials())
+}
+
+// ErrClientTooManyRedirects is an error that can be returned by a client.
+type ErrClientTooManyRedirects struct {
+	Max int
+}
+
+// Error implements the error interface.
+func (e ErrClientTooManyRedirects) Error() string {
+	return fmt.Sprintf("too many redirects (more than %d)", e.Max)
+}
+
+// ErrClientRedirectLoop is an error that can be returned by a client.
+type ErrClientRedirectLoop struct {
+	URL *base.URL
+}
+
+// Error implements the error interface.
+func (e ErrClientRedirectLoop) Error() string {
+	return fmt.Sprintf("redirect loop detected at %v", e.URL.CloneWithoutCredentials())
+}
+
+// ErrClientRedirectIncompatibleMedias is an error that can be returned by a client.
+type ErrClientRedirectIncompatibleMedias struct{}
+
+// Error implements the error interface.
+func (e ErrClientRedirectIncompatibleMedias) Error() string {
+	return "medias of the redirect location are not compatible with the setupped ones"
+}
+
 // ErrClientWriteQueueFull is an error that can be returned by a client.
 type ErrClientWriteQueueFull struct{}
 
@@ -249,6 +316,18 @@ func (e ErrClientWriteQueueFull) Error() string {
 	return "write queue is full"
 }
 
+// ErrClientCorruptedGOP is an error that can be returned by a client.
+type ErrClientCorruptedGOP struct {
+	AccessUnits          int
+	CorruptedAccessUnits int
+}
+
+// Error implements the error interface.
+func (e ErrClientCorruptedGOP) Error() string {
+	return fmt.Sprintf("%d of %d access units of a group of pictures are corrupted",
+		e.CorruptedAccessUnits, e.AccessUnits)
+}
+
 // ErrClientRTPPacketsLost is an error that can be returned by a client.
 type ErrClientRTPPacketsLost struct {
 	Lost uint
@@ -320,6 +399,52 @@ func (e ErrClientSwitchToTCP2) Error() string {
 	return "switching to TCP because server requested it"
 }
 
+// ErrClientSwitchToTCP3 is an error that can be returned by a client.
+type ErrClientSwitchToTCP3 struct{}
+
+// Error implements the error interface.
+func (e ErrClientSwitchToTCP3) Error() string {
+	return "server did not provide UDP ports, switching to TCP"
+}
+
+// ErrClientTransportHeaderInvalidSource is an error that can be returned by a client.
+type ErrClientTransportHeaderInvalidSource struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrClientTransportHeaderInvalidSource) Error() string {
+	return fmt.Sprintf("unable to resolve source of Transport header: %v", e.Err)
+}
+
+// ErrClientSwitchToMulticast is an error that can be returned by a client.
+type ErrClientSwitchToMulticast struct{}
+
+// Error implements the error interface.
+func (e ErrClientSwitchToMulticast) Error() string {
+	return "no UDP packets received, switching to UDP-multicast"
+}
+
+// ErrClientSwitchFromMulticastToTCP is an error that can be returned by a client.
+type ErrClientSwitchFromMulticastToTCP struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrClientSwitchFromMulticastToTCP) Error() string {
+	return fmt.Sprintf("unable to switch to UDP-multicast (%v), switching to TCP", e.Err)
+}
+
+// ErrClientSwitchSessionToTCP is an error that can be returned by a client.
+type ErrClientSwitchSessionToTCP struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrClientSwitchSessionToTCP) Error() string {
+	return fmt.Sprintf("unable to switch session to TCP: %v", e.Err)
+}
+
 // ErrClientAuthSetup is an error that can be returned by a client.
 type ErrClientAuthSetup struct {
 	Err error
@@ -339,3 +464,26 @@ type ErrClientSDPInvalid struct {
 func (e ErrClientSDPInvalid) Error() string {
 	return fmt.Sprintf("invalid SDP: %v", e.Err)
 }
+
+// ErrClientEndOfStream is an error that can be returned by a client.
+type ErrClientEndOfStream struct {
+	EndOfRange bool
+}
+
+// Error implements the error interface.
+func (e ErrClientEndOfStream) Error() string {
+	if e.EndOfRange {
+		return "end of stream: end of range reached and no packets received recently"
+	}
+	return "end of stream: RTCP BYE received on all medias"
+}
+
+// ErrClientProtocolViolation is an error that can be returned by a client.
+type ErrClientProtocolViolation struct {
+	Description string
+}
+
+// Error implements the error interface.
+func (e ErrClientProtocolViolation) Error() string {
+	return fmt.Sprintf("protocol violation: %s", e.Description)
+}
@@ -26,6 +26,16 @@ func (e ErrServerSessionTimedOut) Error() string {
 	return "session timed out"
 }
 
+// ErrServerSessionKicked is an error that can be returned by a server.
+type ErrServerSessionKicked struct {
+	Reason string
+}
+
+// Error implements the error interface.
+func (e ErrServerSessionKicked) Error() string {
+	return "session kicked: " + e.Reason
+}
+
 // ErrServerCSeqMissing is an error that can be returned by a server.
 type ErrServerCSeqMissing struct{}
 
@@ -224,6 +234,14 @@ func (e ErrServerSessionNotInUse) Error() string {
 	return "not in use"
 }
 
+// ErrServerSessionNotConnected is an error that can be returned by a server.
+type ErrServerSessionNotConnected struct{}
+
+// Error implements the error interface.
+func (e ErrServerSessionNotConnected) Error() string {
+	return "session is not associated with any connection"
+}
+
 // ErrServerUnexpectedFrame is an error that can be returned by a server.
 type ErrServerUnexpectedFrame = ErrClientUnexpectedFrame
 
@@ -241,6 +259,9 @@ type ErrServerWriteQueueFull = ErrClientWriteQueueFull
 // ErrServerRTPPacketsLost is an error that can be returned by a server.
 type ErrServerRTPPacketsLost = ErrClientRTPPacketsLost
 
+// ErrServerCorruptedGOP is an error that can be returned by a server.
+type ErrServerCorruptedGOP = ErrClientCorruptedGOP
+
 // ErrServerRTPPacketUnknownPayloadType is an error that can be returned by a server.
 type ErrServerRTPPacketUnknownPayloadType = ErrClientRTPPacketUnknownPayloadType
 
@@ -270,3 +291,45 @@ func (ErrServerInvalidSetupPath) Error() string {
 		"This typically happens when VLC fails a request, and then switches to an " +
 		"unsupported RTSP dialect"
 }
+
+// ErrServerTooManyConnections is an error that can be returned by a server.
+type ErrServerTooManyConnections struct{}
+
+// Error implements the error interface.
+func (ErrServerTooManyConnections) Error() string {
+	return "too many connections"
+}
+
+// ErrServerTooManyRequests is an error that can be returned by a server.
+type ErrServerTooManyRequests struct{}
+
+// Error implements the error interface.
+func (ErrServerTooManyRequests) Error() string {
+	return "too many requests"
+}
+
+// ErrServerTooManyBadRequests is an error that can be returned by a server.
+type ErrServerTooManyBadRequests struct{}
+
+// Error implements the error interface.
+func (ErrServerTooManyBadRequests) Error() string {
+	return "too many bad requests"
+}
+
+// ErrServerTooManySessions is an error that can be returned by a server.
+type ErrServerTooManySessions struct{}
+
+// Error implements the error interface.
+func (ErrServerTooManySessions) Error() string {
+	return "too many sessions"
+}
+
+// ErrServerProtocolViolation is an error that can be returned by a server.
+type ErrServerProtocolViolation struct {
+	Description string
+}
+
+// Error implements the error interface.
+func (e ErrServerProtocolViolation) Error() string {
+	return fmt.Sprintf("protocol violation: %s", e.Description)
+}
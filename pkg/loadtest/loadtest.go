@@ -0,0 +1,290 @@
+// Package loadtest contains a utility to test the capacity of RTSP servers.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
+)
+
+// LatencyStats are statistics about a latency.
+type LatencyStats struct {
+	// number of samples.
+	Count int
+
+	// minimum latency.
+	Min time.Duration
+
+	// maximum latency.
+	Max time.Duration
+
+	// mean latency.
+	Mean time.Duration
+}
+
+type latencyAccumulator struct {
+	count int
+	sum   time.Duration
+	min   time.Duration
+	max   time.Duration
+}
+
+func (a *latencyAccumulator) add(d time.Duration) {
+	if a.count == 0 || d < a.min {
+		a.min = d
+	}
+	if d > a.max {
+		a.max = d
+	}
+	a.count++
+	a.sum += d
+}
+
+func (a *latencyAccumulator) stats() LatencyStats {
+	if a.count == 0 {
+		return LatencyStats{}
+	}
+	return LatencyStats{
+		Count: a.count,
+		Min:   a.min,
+		Max:   a.max,
+		Mean:  a.sum / time.Duration(a.count),
+	}
+}
+
+// Stats are aggregate statistics of a LoadTest.
+type Stats struct {
+	// number of readers that have been started.
+	ReadersStarted int
+
+	// number of readers that are reading.
+	ReadersReading int
+
+	// number of readers that failed.
+	ReadersFailed int
+
+	// time between the start of a reader and the response to PLAY.
+	SetupLatency LatencyStats
+
+	// time between the PLAY request and the first RTP packet.
+	FirstPacketLatency LatencyStats
+
+	// number of received RTP packets.
+	RTPPacketsReceived uint64
+
+	// number of lost RTP packets.
+	RTPPacketsLost uint64
+}
+
+// PacketLossRatio returns the ratio between lost RTP packets and expected RTP packets.
+func (s Stats) PacketLossRatio() float64 {
+	expected := s.RTPPacketsReceived + s.RTPPacketsLost
+	if expected == 0 {
+		return 0
+	}
+	return float64(s.RTPPacketsLost) / float64(expected)
+}
+
+// LoadTest spawns multiple readers that read a stream from a RTSP server,
+// and collects aggregate statistics about them.
+type LoadTest struct {
+	// URL of the stream.
+	URL string
+
+	// number of readers.
+	Readers int
+
+	// transports of readers, that are assigned to readers in a round-robin fashion.
+	// It defaults to TCP only.
+	Transports []gortsplib.Transport
+
+	// interval between the start of two consecutive readers.
+	// It defaults to 0 (readers are started all at once).
+	RampUpInterval time.Duration
+
+	// function that creates a Client, in order to customize its settings.
+	// Transport, OnPacketLost and OnDecodeError are overridden.
+	// It defaults to a function that returns an empty Client.
+	NewClient func() *gortsplib.Client
+
+	u         *base.URL
+	ctx       context.Context
+	ctxCancel func()
+	wg        sync.WaitGroup
+
+	rtpPacketsReceived uint64
+	rtpPacketsLost     uint64
+
+	mutex              sync.Mutex
+	readersStarted     int
+	readersReading     int
+	readersFailed      int
+	setupLatency       latencyAccumulator
+	firstPacketLatency latencyAccumulator
+}
+
+// Start starts the test.
+func (lt *LoadTest) Start() error {
+	if lt.Readers <= 0 {
+		return fmt.Errorf("Readers must be greater than zero")
+	}
+
+	if lt.Transports == nil {
+		lt.Transports = []gortsplib.Transport{gortsplib.TransportTCP}
+	}
+	if lt.NewClient == nil {
+		lt.NewClient = func() *gortsplib.Client {
+			return &gortsplib.Client{}
+		}
+	}
+
+	var err error
+	lt.u, err = base.ParseURL(lt.URL)
+	if err != nil {
+		return err
+	}
+
+	lt.ctx, lt.ctxCancel = context.WithCancel(context.Background())
+
+	lt.wg.Add(1)
+	go lt.run()
+
+	return nil
+}
+
+// Close stops all readers.
+func (lt *LoadTest) Close() {
+	lt.ctxCancel()
+	lt.wg.Wait()
+}
+
+// Stats returns aggregate statistics.
+func (lt *LoadTest) Stats() *Stats {
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+
+	return &Stats{
+		ReadersStarted:     lt.readersStarted,
+		ReadersReading:     lt.readersReading,
+		ReadersFailed:      lt.readersFailed,
+		SetupLatency:       lt.setupLatency.stats(),
+		FirstPacketLatency: lt.firstPacketLatency.stats(),
+		RTPPacketsReceived: atomic.LoadUint64(&lt.rtpPacketsReceived),
+		RTPPacketsLost:     atomic.LoadUint64(&lt.rtpPacketsLost),
+	}
+}
+
+func (lt *LoadTest) run() {
+	defer lt.wg.Done()
+
+	for i := 0; i < lt.Readers; i++ {
+		if i != 0 && lt.RampUpInterval != 0 {
+			select {
+			case <-time.After(lt.RampUpInterval):
+			case <-lt.ctx.Done():
+				return
+			}
+		}
+
+		lt.mutex.Lock()
+		lt.readersStarted++
+		lt.mutex.Unlock()
+
+		lt.wg.Add(1)
+		go lt.runReader(lt.Transports[i%len(lt.Transports)])
+	}
+}
+
+func (lt *LoadTest) runReader(transport gortsplib.Transport) {
+	defer lt.wg.Done()
+
+	err := lt.runReaderInner(transport)
+	if err != nil {
+		lt.mutex.Lock()
+		lt.readersFailed++
+		lt.mutex.Unlock()
+	}
+}
+
+func (lt *LoadTest) runReaderInner(transport gortsplib.Transport) error {
+	start := time.Now()
+
+	c := lt.NewClient()
+	c.Transport = &transport
+	c.OnPacketLost = func(err error) {
+		var lerr liberrors.ErrClientRTPPacketsLost
+		if errors.As(err, &lerr) {
+			atomic.AddUint64(&lt.rtpPacketsLost, uint64(lerr.Lost))
+		}
+	}
+	c.OnDecodeError = func(error) {}
+
+	err := c.Start(lt.u.Scheme, lt.u.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	desc, _, err := c.Describe(lt.u)
+	if err != nil {
+		return err
+	}
+
+	err = c.SetupAll(desc.BaseURL, desc.Medias)
+	if err != nil {
+		return err
+	}
+
+	playStart := time.Now()
+	var firstPacket sync.Once
+
+	c.OnPacketRTPAny(func(_ *description.Media, _ format.Format, _ *rtp.Packet) {
+		atomic.AddUint64(&lt.rtpPacketsReceived, 1)
+
+		firstPacket.Do(func() {
+			lt.mutex.Lock()
+			lt.firstPacketLatency.add(time.Since(playStart))
+			lt.mutex.Unlock()
+		})
+	})
+
+	_, err = c.Play(nil)
+	if err != nil {
+		return err
+	}
+
+	lt.mutex.Lock()
+	lt.setupLatency.add(time.Since(start))
+	lt.readersReading++
+	lt.mutex.Unlock()
+
+	defer func() {
+		lt.mutex.Lock()
+		lt.readersReading--
+		lt.mutex.Unlock()
+	}()
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- c.Wait()
+	}()
+
+	select {
+	case err = <-waitErr:
+		return err
+
+	case <-lt.ctx.Done():
+		return nil
+	}
+}
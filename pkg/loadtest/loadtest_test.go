@@ -0,0 +1,129 @@
+package loadtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+type testServerHandler struct {
+	stream *gortsplib.ServerStream
+}
+
+func (sh *testServerHandler) OnDescribe(
+	_ *gortsplib.ServerHandlerOnDescribeCtx,
+) (*base.Response, *gortsplib.ServerStream, error) {
+	return &base.Response{
+		StatusCode: base.StatusOK,
+	}, sh.stream, nil
+}
+
+func (sh *testServerHandler) OnSetup(
+	_ *gortsplib.ServerHandlerOnSetupCtx,
+) (*base.Response, *gortsplib.ServerStream, error) {
+	return &base.Response{
+		StatusCode: base.StatusOK,
+	}, sh.stream, nil
+}
+
+func (sh *testServerHandler) OnPlay(_ *gortsplib.ServerHandlerOnPlayCtx) (*base.Response, error) {
+	return &base.Response{
+		StatusCode: base.StatusOK,
+	}, nil
+}
+
+func TestLoadTest(t *testing.T) {
+	h := &testServerHandler{}
+
+	s := &gortsplib.Server{
+		Handler:     h,
+		RTSPAddress: "localhost:8554",
+		TransportOptions: gortsplib.ServerTransportOptions{
+			UDPRTPAddress:  "localhost:8000",
+			UDPRTCPAddress: "localhost:8001",
+		},
+	}
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	medi := &description.Media{
+		Type: description.MediaTypeVideo,
+		Formats: []format.Format{&format.H264{
+			PayloadTyp:        96,
+			PacketizationMode: 1,
+		}},
+	}
+
+	h.stream = gortsplib.NewServerStream(s, &description.Session{Medias: []*description.Media{medi}})
+	defer h.stream.Close()
+
+	lt := &LoadTest{
+		URL:            "rtsp://localhost:8554/stream",
+		Readers:        4,
+		Transports:     []gortsplib.Transport{gortsplib.TransportTCP, gortsplib.TransportUDP},
+		RampUpInterval: 10 * time.Millisecond,
+	}
+	err = lt.Start()
+	require.NoError(t, err)
+	defer lt.Close()
+
+	require.Eventually(t, func() bool {
+		return lt.Stats().ReadersReading == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		err = h.stream.WritePacketRTP(medi, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: uint16(123 + i),
+				SSRC:           0x38F27A2F,
+			},
+			Payload: []byte{5, 1, 2, 3},
+		})
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return lt.Stats().RTPPacketsReceived == 8
+	}, 5*time.Second, 10*time.Millisecond)
+
+	st := lt.Stats()
+	require.Equal(t, 4, st.ReadersStarted)
+	require.Equal(t, 0, st.ReadersFailed)
+	require.Equal(t, 4, st.SetupLatency.Count)
+	require.Equal(t, 4, st.FirstPacketLatency.Count)
+	require.LessOrEqual(t, st.FirstPacketLatency.Min, st.FirstPacketLatency.Mean)
+	require.LessOrEqual(t, st.FirstPacketLatency.Mean, st.FirstPacketLatency.Max)
+	require.Equal(t, float64(0), st.PacketLossRatio())
+}
+
+func TestLoadTestFailure(t *testing.T) {
+	lt := &LoadTest{
+		URL:     "rtsp://localhost:8554/stream",
+		Readers: 2,
+	}
+	err := lt.Start()
+	require.NoError(t, err)
+	defer lt.Close()
+
+	require.Eventually(t, func() bool {
+		return lt.Stats().ReadersFailed == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLoadTestInvalidReaders(t *testing.T) {
+	lt := &LoadTest{
+		URL: "rtsp://localhost:8554/stream",
+	}
+	err := lt.Start()
+	require.EqualError(t, err, "Readers must be greater than zero")
+}
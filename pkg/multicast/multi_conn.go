@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"golang.org/x/net/ipv4"
+
+	"github.com/bluenviron/gortsplib/v4/internal/sockbuf"
 )
 
 // MultiConn is a multicast connection
@@ -133,6 +135,45 @@ func (c *MultiConn) SetReadBuffer(bytes int) error {
 	return c.readConn.SetReadBuffer(bytes)
 }
 
+// SetWriteBuffer implements Conn.
+func (c *MultiConn) SetWriteBuffer(bytes int) error {
+	var err error
+	for _, c := range c.writeConns {
+		err2 := c.SetWriteBuffer(bytes)
+		if err == nil {
+			err = err2
+		}
+	}
+	return err
+}
+
+// ReadBufferSize implements Conn.
+func (c *MultiConn) ReadBufferSize() (int, error) {
+	return sockbuf.ReadBufferSize(c.readConn)
+}
+
+// WriteBufferSize implements Conn.
+// It returns the size of the buffer of the first interface.
+func (c *MultiConn) WriteBufferSize() (int, error) {
+	if len(c.writeConns) == 0 {
+		return 0, fmt.Errorf("connection is read-only")
+	}
+	return sockbuf.WriteBufferSize(c.writeConns[0])
+}
+
+// SetTOS implements Conn.
+// It sets the type-of-service field of packets written on all interfaces.
+func (c *MultiConn) SetTOS(tos int) error {
+	var err error
+	for _, c := range c.writeConnIPs {
+		err2 := c.SetTOS(tos)
+		if err == nil {
+			err = err2
+		}
+	}
+	return err
+}
+
 // LocalAddr implements Conn.
 func (c *MultiConn) LocalAddr() net.Addr {
 	return c.readConn.LocalAddr()
@@ -178,3 +219,26 @@ func (c *MultiConn) WriteTo(b []byte, addr net.Addr) (int, error) {
 func (c *MultiConn) ReadFrom(b []byte) (int, net.Addr, error) {
 	return c.readConn.ReadFrom(b)
 }
+
+// ReadBatch reads multiple packets with a single syscall, when supported.
+func (c *MultiConn) ReadBatch(ms []ipv4.Message, flags int) (int, error) {
+	return c.readConnIP.ReadBatch(ms, flags)
+}
+
+// WriteBatch writes multiple packets with a single syscall, when supported.
+// Packets are written on all interfaces.
+// Progress is tracked per interface, therefore the returned count is either len(ms) or zero
+// and packets are never written twice on the same interface.
+func (c *MultiConn) WriteBatch(ms []ipv4.Message, flags int) (int, error) {
+	var err error
+	for _, c := range c.writeConnIPs {
+		err2 := writeBatchAll(c, ms, flags)
+		if err == nil {
+			err = err2
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(ms), nil
+}
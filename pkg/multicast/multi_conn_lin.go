@@ -3,21 +3,29 @@
 package multicast
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"syscall"
 	"time"
+
+	"golang.org/x/net/ipv4"
 )
 
 // MultiConn is a multicast connection
 // that works in parallel on all interfaces.
 type MultiConn struct {
-	addr       *net.UDPAddr
-	readFile   *os.File
-	readConn   net.PacketConn
-	writeFiles []*os.File
-	writeConns []net.PacketConn
+	addr         *net.UDPAddr
+	readFile     *os.File
+	readConn     net.PacketConn
+	readConnIP   *ipv4.PacketConn
+	writeFiles   []*os.File
+	writeConns   []net.PacketConn
+	writeConnIPs []*ipv4.PacketConn
+
+	// interfaces whose network device doesn't support GSO.
+	gsoUnsupported []bool
 }
 
 // NewMultiConn allocates a MultiConn.
@@ -86,6 +94,7 @@ func NewMultiConn(
 
 	var writeFiles []*os.File
 	var writeConns []net.PacketConn
+	var writeConnIPs []*ipv4.PacketConn
 
 	if !readOnly {
 		writeSocks := make([]int, len(enabledInterfaces))
@@ -151,10 +160,12 @@ func NewMultiConn(
 
 		writeFiles = make([]*os.File, len(writeSocks))
 		writeConns = make([]net.PacketConn, len(writeSocks))
+		writeConnIPs = make([]*ipv4.PacketConn, len(writeSocks))
 
 		for i, writeSock := range writeSocks {
 			writeFiles[i] = os.NewFile(uintptr(writeSock), "")
 			writeConns[i], _ = net.FilePacketConn(writeFiles[i])
+			writeConnIPs[i] = ipv4.NewPacketConn(writeConns[i])
 		}
 	}
 
@@ -162,11 +173,14 @@ func NewMultiConn(
 	readConn, _ := net.FilePacketConn(readFile)
 
 	return &MultiConn{
-		addr:       addr,
-		readFile:   readFile,
-		readConn:   readConn,
-		writeFiles: writeFiles,
-		writeConns: writeConns,
+		addr:           addr,
+		readFile:       readFile,
+		readConn:       readConn,
+		readConnIP:     ipv4.NewPacketConn(readConn),
+		writeFiles:     writeFiles,
+		writeConns:     writeConns,
+		writeConnIPs:   writeConnIPs,
+		gsoUnsupported: make([]bool, len(writeConns)),
 	}, nil
 }
 
@@ -186,6 +200,45 @@ func (c *MultiConn) SetReadBuffer(bytes int) error {
 	return syscall.SetsockoptInt(int(c.readFile.Fd()), syscall.SOL_SOCKET, syscall.SO_RCVBUF, bytes)
 }
 
+// SetWriteBuffer implements Conn.
+func (c *MultiConn) SetWriteBuffer(bytes int) error {
+	var err error
+	for _, f := range c.writeFiles {
+		err2 := syscall.SetsockoptInt(int(f.Fd()), syscall.SOL_SOCKET, syscall.SO_SNDBUF, bytes)
+		if err == nil {
+			err = err2
+		}
+	}
+	return err
+}
+
+// ReadBufferSize implements Conn.
+func (c *MultiConn) ReadBufferSize() (int, error) {
+	return syscall.GetsockoptInt(int(c.readFile.Fd()), syscall.SOL_SOCKET, syscall.SO_RCVBUF)
+}
+
+// WriteBufferSize implements Conn.
+// It returns the size of the buffer of the first interface.
+func (c *MultiConn) WriteBufferSize() (int, error) {
+	if len(c.writeFiles) == 0 {
+		return 0, fmt.Errorf("connection is read-only")
+	}
+	return syscall.GetsockoptInt(int(c.writeFiles[0].Fd()), syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+}
+
+// SetTOS implements Conn.
+// It sets the type-of-service field of packets written on all interfaces.
+func (c *MultiConn) SetTOS(tos int) error {
+	var err error
+	for _, c := range c.writeConnIPs {
+		err2 := c.SetTOS(tos)
+		if err == nil {
+			err = err2
+		}
+	}
+	return err
+}
+
 // LocalAddr implements Conn.
 func (c *MultiConn) LocalAddr() net.Addr {
 	return c.readConn.LocalAddr()
@@ -231,3 +284,80 @@ func (c *MultiConn) WriteTo(b []byte, addr net.Addr) (int, error) {
 func (c *MultiConn) ReadFrom(b []byte) (int, net.Addr, error) {
 	return c.readConn.ReadFrom(b)
 }
+
+// ReadBatch reads multiple packets with a single syscall, when supported.
+func (c *MultiConn) ReadBatch(ms []ipv4.Message, flags int) (int, error) {
+	return c.readConnIP.ReadBatch(ms, flags)
+}
+
+// WriteBatch writes multiple packets with a single syscall, when supported.
+// Packets are written on all interfaces.
+// Progress is tracked per interface, therefore the returned count is either len(ms) or zero
+// and packets are never written twice on the same interface.
+func (c *MultiConn) WriteBatch(ms []ipv4.Message, flags int) (int, error) {
+	var err error
+	for _, c := range c.writeConnIPs {
+		err2 := writeBatchAll(c, ms, flags)
+		if err == nil {
+			err = err2
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(ms), nil
+}
+
+// WriteGSO writes multiple packets with a single syscall,
+// using UDP Generic Segmentation Offload.
+// b contains packets one after the other. All packets must have segmentSize size,
+// except the last one, that can be smaller.
+// Packets are written on all interfaces. Interfaces that don't support GSO are detected
+// with the first write, and packets are written one by one on them.
+// It must not be called by multiple routines at once.
+func (c *MultiConn) WriteGSO(b []byte, segmentSize int, addr net.Addr) (int, error) {
+	oob := gsoControlMessage(segmentSize)
+
+	var err error
+	for i, wc := range c.writeConns {
+		err2 := c.writeGSOInterface(i, wc.(*net.UDPConn), b, segmentSize, oob, addr.(*net.UDPAddr))
+		if err == nil {
+			err = err2
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *MultiConn) writeGSOInterface(
+	i int,
+	wc *net.UDPConn,
+	b []byte,
+	segmentSize int,
+	oob []byte,
+	addr *net.UDPAddr,
+) error {
+	if !c.gsoUnsupported[i] {
+		_, _, err := wc.WriteMsgUDP(b, oob, addr)
+
+		// the kernel or the network device doesn't support GSO.
+		// Nothing has been written, therefore segments can be written one by one.
+		if !errors.Is(err, syscall.EIO) && !errors.Is(err, syscall.EINVAL) {
+			return err
+		}
+		c.gsoUnsupported[i] = true
+	}
+
+	for len(b) != 0 {
+		l := min(segmentSize, len(b))
+		_, err := wc.WriteTo(b[:l], addr)
+		if err != nil {
+			return err
+		}
+		b = b[l:]
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ package multicast
 import (
 	"fmt"
 	"net"
+
+	"golang.org/x/net/ipv4"
 )
 
 // Conn is a Multicast connection.
@@ -41,3 +43,14 @@ func InterfaceForSource(ip net.IP) (*net.Interface, error) {
 
 	return nil, fmt.Errorf("found no interface that is multicast-capable and can communicate with IP %v", ip)
 }
+
+func writeBatchAll(c *ipv4.PacketConn, ms []ipv4.Message, flags int) error {
+	for len(ms) != 0 {
+		n, err := c.WriteBatch(ms, flags)
+		if err != nil {
+			return err
+		}
+		ms = ms[n:]
+	}
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"golang.org/x/net/ipv4"
+
+	"github.com/bluenviron/gortsplib/v4/internal/sockbuf"
 )
 
 const (
@@ -77,6 +79,26 @@ func (c *SingleConn) SetReadBuffer(bytes int) error {
 	return c.conn.SetReadBuffer(bytes)
 }
 
+// SetWriteBuffer implements Conn.
+func (c *SingleConn) SetWriteBuffer(bytes int) error {
+	return c.conn.SetWriteBuffer(bytes)
+}
+
+// ReadBufferSize implements Conn.
+func (c *SingleConn) ReadBufferSize() (int, error) {
+	return sockbuf.ReadBufferSize(c.conn)
+}
+
+// WriteBufferSize implements Conn.
+func (c *SingleConn) WriteBufferSize() (int, error) {
+	return sockbuf.WriteBufferSize(c.conn)
+}
+
+// SetTOS implements Conn.
+func (c *SingleConn) SetTOS(tos int) error {
+	return c.connIP.SetTOS(tos)
+}
+
 // LocalAddr implements Conn.
 func (c *SingleConn) LocalAddr() net.Addr {
 	return c.conn.LocalAddr()
@@ -106,3 +128,13 @@ func (c *SingleConn) WriteTo(b []byte, addr net.Addr) (int, error) {
 func (c *SingleConn) ReadFrom(b []byte) (int, net.Addr, error) {
 	return c.conn.ReadFrom(b)
 }
+
+// ReadBatch reads multiple packets with a single syscall, when supported.
+func (c *SingleConn) ReadBatch(ms []ipv4.Message, flags int) (int, error) {
+	return c.connIP.ReadBatch(ms, flags)
+}
+
+// WriteBatch writes multiple packets with a single syscall, when supported.
+func (c *SingleConn) WriteBatch(ms []ipv4.Message, flags int) (int, error) {
+	return c.connIP.WriteBatch(ms, flags)
+}
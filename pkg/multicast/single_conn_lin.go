@@ -3,18 +3,43 @@
 package multicast
 
 import (
+	"encoding/binary"
 	"fmt"
 	"net"
 	"os"
 	"syscall"
 	"time"
+
+	"golang.org/x/net/ipv4"
 )
 
 const (
 	// same size as GStreamer's rtspsrc
 	multicastTTL = 16
+
+	// https://github.com/torvalds/linux/blob/v6.6/include/uapi/linux/udp.h#L34
+	udpSegment = 103
 )
 
+// gsoControlMessage generates a control message that sets the UDP segment size.
+func gsoControlMessage(segmentSize int) []byte {
+	oob := make([]byte, syscall.CmsgSpace(2))
+
+	if syscall.SizeofCmsghdr == 16 {
+		binary.NativeEndian.PutUint64(oob, uint64(syscall.CmsgLen(2)))
+		binary.NativeEndian.PutUint32(oob[8:], syscall.IPPROTO_UDP)
+		binary.NativeEndian.PutUint32(oob[12:], udpSegment)
+	} else {
+		binary.NativeEndian.PutUint32(oob, uint32(syscall.CmsgLen(2)))
+		binary.NativeEndian.PutUint32(oob[4:], syscall.IPPROTO_UDP)
+		binary.NativeEndian.PutUint32(oob[8:], udpSegment)
+	}
+
+	binary.NativeEndian.PutUint16(oob[syscall.CmsgLen(0):], uint16(segmentSize))
+
+	return oob
+}
+
 // https://cs.opensource.google/go/x/net/+/refs/tags/v0.15.0:ipv4/sys_asmreq.go;l=51
 func setIPMreqInterface(mreq *syscall.IPMreq, ifi *net.Interface) error {
 	if ifi == nil {
@@ -44,9 +69,10 @@ func setIPMreqInterface(mreq *syscall.IPMreq, ifi *net.Interface) error {
 // SingleConn is a multicast connection
 // that works on a single interface.
 type SingleConn struct {
-	addr *net.UDPAddr
-	file *os.File
-	conn net.PacketConn
+	addr   *net.UDPAddr
+	file   *os.File
+	conn   net.PacketConn
+	connIP *ipv4.PacketConn
 }
 
 // NewSingleConn allocates a SingleConn.
@@ -123,9 +149,10 @@ func NewSingleConn(
 	}
 
 	return &SingleConn{
-		addr: addr,
-		file: file,
-		conn: conn,
+		addr:   addr,
+		file:   file,
+		conn:   conn,
+		connIP: ipv4.NewPacketConn(conn),
 	}, nil
 }
 
@@ -141,6 +168,26 @@ func (c *SingleConn) SetReadBuffer(bytes int) error {
 	return syscall.SetsockoptInt(int(c.file.Fd()), syscall.SOL_SOCKET, syscall.SO_RCVBUF, bytes)
 }
 
+// SetWriteBuffer implements Conn.
+func (c *SingleConn) SetWriteBuffer(bytes int) error {
+	return syscall.SetsockoptInt(int(c.file.Fd()), syscall.SOL_SOCKET, syscall.SO_SNDBUF, bytes)
+}
+
+// ReadBufferSize implements Conn.
+func (c *SingleConn) ReadBufferSize() (int, error) {
+	return syscall.GetsockoptInt(int(c.file.Fd()), syscall.SOL_SOCKET, syscall.SO_RCVBUF)
+}
+
+// WriteBufferSize implements Conn.
+func (c *SingleConn) WriteBufferSize() (int, error) {
+	return syscall.GetsockoptInt(int(c.file.Fd()), syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+}
+
+// SetTOS implements Conn.
+func (c *SingleConn) SetTOS(tos int) error {
+	return c.connIP.SetTOS(tos)
+}
+
 // LocalAddr implements Conn.
 func (c *SingleConn) LocalAddr() net.Addr {
 	return c.conn.LocalAddr()
@@ -170,3 +217,13 @@ func (c *SingleConn) WriteTo(b []byte, addr net.Addr) (int, error) {
 func (c *SingleConn) ReadFrom(b []byte) (int, net.Addr, error) {
 	return c.conn.ReadFrom(b)
 }
+
+// ReadBatch reads multiple packets with a single syscall, when supported.
+func (c *SingleConn) ReadBatch(ms []ipv4.Message, flags int) (int, error) {
+	return c.connIP.ReadBatch(ms, flags)
+}
+
+// WriteBatch writes multiple packets with a single syscall, when supported.
+func (c *SingleConn) WriteBatch(ms []ipv4.Message, flags int) (int, error) {
+	return c.connIP.WriteBatch(ms, flags)
+}
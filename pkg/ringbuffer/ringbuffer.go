@@ -80,6 +80,35 @@ func (r *RingBuffer) Push(data interface{}) bool {
 	return true
 }
 
+// PushMultiple pushes multiple entries at the end of the buffer.
+// Either all entries are pushed or none of them is.
+func (r *RingBuffer) PushMultiple(data []interface{}) bool {
+	r.mutex.Lock()
+
+	if uint64(len(data)) > r.size {
+		r.mutex.Unlock()
+		return false
+	}
+
+	for i := range data {
+		if r.buffer[(r.writeIndex+uint64(i))%r.size] != nil {
+			r.mutex.Unlock()
+			return false
+		}
+	}
+
+	for _, entry := range data {
+		r.buffer[r.writeIndex] = entry
+		r.writeIndex = (r.writeIndex + 1) % r.size
+	}
+
+	r.mutex.Unlock()
+
+	r.cond.Broadcast()
+
+	return true
+}
+
 // Pull pulls data from the beginning of the buffer.
 func (r *RingBuffer) Pull() (interface{}, bool) {
 	for {
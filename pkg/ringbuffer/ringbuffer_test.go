@@ -26,6 +26,30 @@ func TestPushBeforePull(t *testing.T) {
 	require.Equal(t, bytes.Repeat([]byte{1, 2, 3, 4}, 1024/4), ret)
 }
 
+func TestPushMultiple(t *testing.T) {
+	r, err := New(4)
+	require.NoError(t, err)
+	defer r.Close()
+
+	ok := r.Push(1)
+	require.Equal(t, true, ok)
+
+	ok = r.PushMultiple([]interface{}{2, 3, 4, 5})
+	require.Equal(t, false, ok)
+
+	ok = r.PushMultiple([]interface{}{2, 3, 4})
+	require.Equal(t, true, ok)
+
+	for i := 1; i <= 4; i++ {
+		ret, ok := r.Pull()
+		require.Equal(t, true, ok)
+		require.Equal(t, i, ret)
+	}
+
+	ok = r.PushMultiple([]interface{}{1, 2, 3, 4, 5})
+	require.Equal(t, false, ok)
+}
+
 func TestPullBeforePush(t *testing.T) {
 	r, err := New(1024)
 	require.NoError(t, err)
@@ -0,0 +1,56 @@
+package rtpextension
+
+import (
+	"fmt"
+	"time"
+)
+
+// AbsSendTimeURI is the URI of the abs-send-time extension.
+const AbsSendTimeURI = "http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time"
+
+const absSendTimeSize = 3
+
+// AbsSendTime is the abs-send-time extension,
+// that contains the time at which a packet has been sent.
+// Specification: https://webrtc.googlesource.com/src/+/refs/heads/main/docs/native-code/rtp-hdrext/abs-send-time
+type AbsSendTime struct {
+	// send time, in NTP format, with 6 bits of seconds and 18 bits of fraction.
+	Timestamp uint32
+}
+
+// NewAbsSendTime allocates an AbsSendTime.
+func NewAbsSendTime(t time.Time) AbsSendTime {
+	return AbsSendTime{
+		Timestamp: uint32(ntpTimeFromGo(t)>>14) & 0xFFFFFF,
+	}
+}
+
+// Unmarshal decodes the extension.
+func (e *AbsSendTime) Unmarshal(buf []byte) error {
+	if len(buf) != absSendTimeSize {
+		return fmt.Errorf("invalid abs-send-time extension size (%d)", len(buf))
+	}
+
+	e.Timestamp = uint32(buf[0])<<16 | uint32(buf[1])<<8 | uint32(buf[2])
+
+	return nil
+}
+
+// Marshal encodes the extension.
+func (e AbsSendTime) Marshal() []byte {
+	return []byte{byte(e.Timestamp >> 16), byte(e.Timestamp >> 8), byte(e.Timestamp)}
+}
+
+// Time returns the absolute send time.
+// Since the timestamp wraps around every 64 seconds,
+// it is reconstructed with the receive time, that must be less than 64 seconds later.
+func (e AbsSendTime) Time(receiveTime time.Time) time.Time {
+	recv := ntpTimeFromGo(receiveTime)
+	ntp := (recv &^ (0xFFFFFF << 14)) | uint64(e.Timestamp&0xFFFFFF)<<14
+
+	if ntp > recv {
+		ntp -= 1 << 38
+	}
+
+	return ntpTimeToGo(ntp)
+}
@@ -0,0 +1,39 @@
+package rtpextension
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbsSendTimeUnmarshal(t *testing.T) {
+	var e AbsSendTime
+	err := e.Unmarshal([]byte{0x12, 0x34, 0x56})
+	require.NoError(t, err)
+	require.Equal(t, AbsSendTime{Timestamp: 0x123456}, e)
+}
+
+func TestAbsSendTimeMarshal(t *testing.T) {
+	require.Equal(t, []byte{0x12, 0x34, 0x56}, AbsSendTime{Timestamp: 0x123456}.Marshal())
+}
+
+func TestAbsSendTimeTime(t *testing.T) {
+	sendTime := time.Date(2023, 8, 10, 12, 34, 56, 500000000, time.UTC)
+	e := NewAbsSendTime(sendTime)
+
+	for _, recv := range []time.Duration{
+		0,
+		100 * time.Millisecond,
+		30 * time.Second,
+	} {
+		dec := e.Time(sendTime.Add(recv))
+		require.InDelta(t, 0, dec.Sub(sendTime).Seconds(), 0.00001)
+	}
+}
+
+func TestAbsSendTimeUnmarshalError(t *testing.T) {
+	var e AbsSendTime
+	err := e.Unmarshal([]byte{1, 2})
+	require.EqualError(t, err, "invalid abs-send-time extension size (2)")
+}
@@ -0,0 +1,110 @@
+package rtpextension
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// ONVIFReplayProfile is the profile of the ONVIF replay extension.
+const ONVIFReplayProfile = 0xABAC
+
+const onvifReplaySize = 12
+
+// seconds since 1st January 1900
+// higher 32 bits are the integer part, lower 32 bits are the fractional part
+func ntpTimeToGo(v uint64) time.Time {
+	sec := int64(v>>32) - 2208988800
+	nsec := int64(((v & 0xFFFFFFFF) * 1000000000) >> 32)
+	return time.Unix(sec, nsec).UTC()
+}
+
+func ntpTimeFromGo(v time.Time) uint64 {
+	sec := uint64(v.Unix() + 2208988800)
+	frac := (uint64(v.Nanosecond()) << 32) / 1000000000
+	return sec<<32 | frac
+}
+
+// ONVIFReplay is the ONVIF replay extension,
+// that is used by NVRs to attach the absolute recording time to RTP packets
+// (ONVIF Streaming Specification, section 6.3).
+type ONVIFReplay struct {
+	// absolute time of the first byte of the packet.
+	NTPTime time.Time
+	// C flag: the packet starts an access unit that can be decoded independently.
+	CleanPoint bool
+	// E flag: the packet is the last one of a contiguous section of the recording.
+	End bool
+	// D flag: the packet is the first one after a discontinuity.
+	Discontinuity bool
+	// T flag: the packet is the last one of the recording.
+	Terminal bool
+	// lower 8 bits of the CSeq of the request that started the replay.
+	CSeq uint8
+}
+
+// Unmarshal decodes the extension.
+func (e *ONVIFReplay) Unmarshal(buf []byte) error {
+	if len(buf) != onvifReplaySize {
+		return fmt.Errorf("invalid ONVIF replay extension size (%d)", len(buf))
+	}
+
+	e.NTPTime = ntpTimeToGo(binary.BigEndian.Uint64(buf[0:8]))
+	e.CleanPoint = (buf[8] & 0x80) != 0
+	e.End = (buf[8] & 0x40) != 0
+	e.Discontinuity = (buf[8] & 0x20) != 0
+	e.Terminal = (buf[8] & 0x10) != 0
+	e.CSeq = buf[9]
+
+	return nil
+}
+
+// Marshal encodes the extension.
+func (e ONVIFReplay) Marshal() []byte {
+	buf := make([]byte, onvifReplaySize)
+	binary.BigEndian.PutUint64(buf[0:8], ntpTimeFromGo(e.NTPTime))
+
+	if e.CleanPoint {
+		buf[8] |= 0x80
+	}
+	if e.End {
+		buf[8] |= 0x40
+	}
+	if e.Discontinuity {
+		buf[8] |= 0x20
+	}
+	if e.Terminal {
+		buf[8] |= 0x10
+	}
+
+	buf[9] = e.CSeq
+
+	return buf
+}
+
+// Read reads the extension from a RTP header.
+// It returns false if the header doesn't contain the extension.
+func (e *ONVIFReplay) Read(h *rtp.Header) (bool, error) {
+	if !h.Extension || h.ExtensionProfile != ONVIFReplayProfile {
+		return false, nil
+	}
+
+	err := e.Unmarshal(h.GetExtension(0))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Write writes the extension into a RTP header.
+// Since the extension doesn't use the RFC 8285 format,
+// it replaces any other extension.
+func (e ONVIFReplay) Write(h *rtp.Header) {
+	h.Extension = true
+	h.ExtensionProfile = ONVIFReplayProfile
+	h.Extensions = nil
+	h.SetExtension(0, e.Marshal()) //nolint:errcheck
+}
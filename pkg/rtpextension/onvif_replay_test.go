@@ -0,0 +1,95 @@
+package rtpextension
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+var casesONVIFReplay = []struct {
+	name string
+	enc  []byte
+	dec  ONVIFReplay
+}{
+	{
+		"clean point",
+		[]byte{
+			0xe8, 0x7f, 0x57, 0xf0, 0x80, 0x00, 0x00, 0x00,
+			0x80, 0x05, 0x00, 0x00,
+		},
+		ONVIFReplay{
+			NTPTime:    time.Date(2023, 8, 10, 12, 34, 56, 500000000, time.UTC),
+			CleanPoint: true,
+			CSeq:       5,
+		},
+	},
+	{
+		"all flags",
+		[]byte{
+			0xe8, 0x7f, 0x57, 0xf0, 0x40, 0x00, 0x00, 0x00,
+			0xf0, 0xff, 0x00, 0x00,
+		},
+		ONVIFReplay{
+			NTPTime:       time.Date(2023, 8, 10, 12, 34, 56, 250000000, time.UTC),
+			CleanPoint:    true,
+			End:           true,
+			Discontinuity: true,
+			Terminal:      true,
+			CSeq:          255,
+		},
+	},
+}
+
+func TestONVIFReplayUnmarshal(t *testing.T) {
+	for _, ca := range casesONVIFReplay {
+		t.Run(ca.name, func(t *testing.T) {
+			var dec ONVIFReplay
+			err := dec.Unmarshal(ca.enc)
+			require.NoError(t, err)
+			require.Equal(t, ca.dec, dec)
+		})
+	}
+}
+
+func TestONVIFReplayMarshal(t *testing.T) {
+	for _, ca := range casesONVIFReplay {
+		t.Run(ca.name, func(t *testing.T) {
+			require.Equal(t, ca.enc, ca.dec.Marshal())
+		})
+	}
+}
+
+func TestONVIFReplayReadWrite(t *testing.T) {
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 123,
+			Timestamp:      45343,
+			SSRC:           563423,
+		},
+		Payload: []byte{1, 2, 3, 4},
+	}
+
+	casesONVIFReplay[1].dec.Write(&pkt.Header)
+
+	buf, err := pkt.Marshal()
+	require.NoError(t, err)
+
+	var dec rtp.Packet
+	err = dec.Unmarshal(buf)
+	require.NoError(t, err)
+
+	var ext ONVIFReplay
+	ok, err := ext.Read(&dec.Header)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, casesONVIFReplay[1].dec, ext)
+	require.Equal(t, []byte{1, 2, 3, 4}, dec.Payload)
+
+	ok, err = ext.Read(&rtp.Header{})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,77 @@
+// Package rtpextension contains functions to read and write RTP header extensions
+// (RFC 8285) and the ONVIF replay extension.
+//
+// Extensions can be attached to packets generated by encoders before passing them
+// to WritePacketRTP(), and can be read from packets received by OnPacketRTP().
+// Decoders ignore extensions.
+// IDs of extensions are negotiated through the Extensions field of description.Media.
+// The abs-send-time and toffset extensions are generated automatically by Client and ServerStream
+// when they are declared in the media.
+package rtpextension
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	profileOneByte = 0xBEDE
+	profileTwoByte = 0x1000
+)
+
+// Set sets an extension in a RTP header.
+// The one-byte header format is used when possible,
+// otherwise the header is switched to the two-byte format.
+func Set(h *rtp.Header, id uint8, payload []byte) error {
+	if id == 0 {
+		return fmt.Errorf("invalid extension ID: %d", id)
+	}
+
+	if len(payload) > 255 {
+		return fmt.Errorf("extension payload is too big (%d bytes)", len(payload))
+	}
+
+	needsTwoByte := id > 14 || len(payload) == 0 || len(payload) > 16
+
+	if h.Extension {
+		switch h.ExtensionProfile {
+		case profileOneByte:
+			// all one-byte extensions can be represented with the two-byte format
+			if needsTwoByte {
+				h.ExtensionProfile = profileTwoByte
+			}
+
+		case profileTwoByte:
+
+		default:
+			return fmt.Errorf("unsupported extension profile: 0x%.4x", h.ExtensionProfile)
+		}
+	} else {
+		h.Extension = true
+		h.Extensions = nil
+
+		if needsTwoByte {
+			h.ExtensionProfile = profileTwoByte
+		} else {
+			h.ExtensionProfile = profileOneByte
+		}
+	}
+
+	return h.SetExtension(id, payload)
+}
+
+// Get returns an extension of a RTP header.
+func Get(h *rtp.Header, id uint8) ([]byte, bool) {
+	if !h.Extension || (h.ExtensionProfile != profileOneByte && h.ExtensionProfile != profileTwoByte) {
+		return nil, false
+	}
+
+	for _, eid := range h.GetExtensionIDs() {
+		if eid == id {
+			return h.GetExtension(id), true
+		}
+	}
+
+	return nil, false
+}
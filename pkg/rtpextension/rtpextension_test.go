@@ -0,0 +1,97 @@
+package rtpextension
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetGet(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		exts    map[uint8][]byte
+		profile uint16
+	}{
+		{
+			"one-byte",
+			map[uint8][]byte{
+				1:  {1, 2, 3},
+				14: {4},
+			},
+			profileOneByte,
+		},
+		{
+			"two-byte because of ID",
+			map[uint8][]byte{
+				1:  {1, 2, 3},
+				20: {4},
+			},
+			profileTwoByte,
+		},
+		{
+			"two-byte because of size",
+			map[uint8][]byte{
+				1: {1, 2, 3},
+				2: make([]byte, 100),
+			},
+			profileTwoByte,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			pkt := &rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					PayloadType:    96,
+					SequenceNumber: 123,
+					Timestamp:      45343,
+					SSRC:           563423,
+				},
+				Payload: []byte{5, 6, 7, 8},
+			}
+
+			for _, id := range []uint8{1, 2, 14, 20} {
+				if pl, ok := ca.exts[id]; ok {
+					err := Set(&pkt.Header, id, pl)
+					require.NoError(t, err)
+				}
+			}
+
+			require.Equal(t, ca.profile, pkt.ExtensionProfile)
+
+			buf, err := pkt.Marshal()
+			require.NoError(t, err)
+
+			var dec rtp.Packet
+			err = dec.Unmarshal(buf)
+			require.NoError(t, err)
+
+			for id, pl := range ca.exts {
+				v, ok := Get(&dec.Header, id)
+				require.True(t, ok)
+				require.Equal(t, pl, v)
+			}
+
+			_, ok := Get(&dec.Header, 3)
+			require.False(t, ok)
+
+			require.Equal(t, []byte{5, 6, 7, 8}, dec.Payload)
+		})
+	}
+}
+
+func TestSetError(t *testing.T) {
+	var h rtp.Header
+	err := Set(&h, 0, []byte{1})
+	require.EqualError(t, err, "invalid extension ID: 0")
+
+	err = Set(&h, 1, make([]byte, 256))
+	require.EqualError(t, err, "extension payload is too big (256 bytes)")
+
+	h = rtp.Header{
+		Extension:        true,
+		ExtensionProfile: 0x1234,
+	}
+	err = Set(&h, 1, []byte{1})
+	require.EqualError(t, err, "unsupported extension profile: 0x1234")
+}
@@ -0,0 +1,59 @@
+package rtpextension
+
+import (
+	"fmt"
+)
+
+// TransmissionOffsetURI is the URI of the transmission time offset extension.
+const TransmissionOffsetURI = "urn:ietf:params:rtp-hdrext:toffset"
+
+const (
+	transmissionOffsetSize = 3
+	transmissionOffsetMax  = 1<<23 - 1
+	transmissionOffsetMin  = -(1 << 23)
+)
+
+// TransmissionOffset is the transmission time offset extension,
+// that contains the offset between the RTP timestamp of a packet and the time at which it has been sent.
+// Specification: https://datatracker.ietf.org/doc/html/rfc5450
+type TransmissionOffset struct {
+	// offset, in clock rate units, between -8388608 and 8388607.
+	Offset int32
+}
+
+// NewTransmissionOffset allocates a TransmissionOffset.
+// The offset is clamped into the allowed range.
+func NewTransmissionOffset(offset int64) TransmissionOffset {
+	switch {
+	case offset > transmissionOffsetMax:
+		offset = transmissionOffsetMax
+	case offset < transmissionOffsetMin:
+		offset = transmissionOffsetMin
+	}
+
+	return TransmissionOffset{Offset: int32(offset)}
+}
+
+// Unmarshal decodes the extension.
+func (e *TransmissionOffset) Unmarshal(buf []byte) error {
+	if len(buf) != transmissionOffsetSize {
+		return fmt.Errorf("invalid transmission offset extension size (%d)", len(buf))
+	}
+
+	v := int32(buf[0])<<16 | int32(buf[1])<<8 | int32(buf[2])
+
+	// sign extension
+	if (v & 0x800000) != 0 {
+		v -= 1 << 24
+	}
+
+	e.Offset = v
+
+	return nil
+}
+
+// Marshal encodes the extension.
+func (e TransmissionOffset) Marshal() []byte {
+	v := uint32(e.Offset)
+	return []byte{byte(v >> 16), byte(v >> 8), byte(v)}
+}
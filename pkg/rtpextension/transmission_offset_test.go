@@ -0,0 +1,49 @@
+package rtpextension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var casesTransmissionOffset = []struct {
+	name string
+	enc  []byte
+	dec  TransmissionOffset
+}{
+	{
+		"positive",
+		[]byte{0x00, 0x0e, 0x10},
+		TransmissionOffset{Offset: 3600},
+	},
+	{
+		"negative",
+		[]byte{0xff, 0xf1, 0xf0},
+		TransmissionOffset{Offset: -3600},
+	},
+}
+
+func TestTransmissionOffsetUnmarshal(t *testing.T) {
+	for _, ca := range casesTransmissionOffset {
+		t.Run(ca.name, func(t *testing.T) {
+			var dec TransmissionOffset
+			err := dec.Unmarshal(ca.enc)
+			require.NoError(t, err)
+			require.Equal(t, ca.dec, dec)
+		})
+	}
+}
+
+func TestTransmissionOffsetMarshal(t *testing.T) {
+	for _, ca := range casesTransmissionOffset {
+		t.Run(ca.name, func(t *testing.T) {
+			require.Equal(t, ca.enc, ca.dec.Marshal())
+		})
+	}
+}
+
+func TestNewTransmissionOffset(t *testing.T) {
+	require.Equal(t, int32(-3600), NewTransmissionOffset(-3600).Offset)
+	require.Equal(t, int32(8388607), NewTransmissionOffset(1<<30).Offset)
+	require.Equal(t, int32(-8388608), NewTransmissionOffset(-(1 << 30)).Offset)
+}
@@ -39,16 +39,39 @@ type GlobalDecoder2 struct {
 	startNTP          time.Time
 	startPTS          int64
 	startPTSClockRate int64
+	speed             float64
 	tracks            map[GlobalDecoder2Track]*globalDecoder2TrackData
+	sparseTracks      map[GlobalDecoder2Track]struct{}
 }
 
 // NewGlobalDecoder2 allocates a GlobalDecoder.
 func NewGlobalDecoder2() *GlobalDecoder2 {
 	return &GlobalDecoder2{
-		tracks: make(map[GlobalDecoder2Track]*globalDecoder2TrackData),
+		tracks:       make(map[GlobalDecoder2Track]*globalDecoder2TrackData),
+		sparseTracks: make(map[GlobalDecoder2Track]struct{}),
 	}
 }
 
+// SetSparse marks a track as sparse, that is, as a track whose packets are received sporadically.
+// Sparse tracks are never used as reference to compute the timestamp of other tracks.
+// It must be called before decoding any packet of the track.
+func (d *GlobalDecoder2) SetSparse(track GlobalDecoder2Track) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.sparseTracks[track] = struct{}{}
+}
+
+// SetSpeed sets the speed at which packets are received, relative to real time.
+// It is used to compute the timestamp of tracks that start after the leading one,
+// and must be set when the stream is received faster or slower than real time.
+func (d *GlobalDecoder2) SetSpeed(speed float64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.speed = speed
+}
+
 // Decode decodes a timestamp.
 func (d *GlobalDecoder2) Decode(
 	track GlobalDecoder2Track,
@@ -71,8 +94,7 @@ func (d *GlobalDecoder2) Decode(
 
 		now := timeNow()
 
-		if d.leadingTrack == nil {
-			d.leadingTrack = track
+		if d.startPTSClockRate == 0 {
 			d.startNTP = now
 			d.startPTS = 0
 			d.startPTSClockRate = int64(track.ClockRate())
@@ -80,7 +102,19 @@ func (d *GlobalDecoder2) Decode(
 
 		// start from the PTS of the leading track
 		startPTS := multiplyAndDivide2(d.startPTS, int64(track.ClockRate()), d.startPTSClockRate)
-		startPTS += multiplyAndDivide2(int64(now.Sub(d.startNTP)), int64(track.ClockRate()), int64(time.Second))
+		elapsed := now.Sub(d.startNTP)
+		if d.speed > 0 {
+			elapsed = time.Duration(float64(elapsed) * d.speed)
+		}
+		startPTS += multiplyAndDivide2(int64(elapsed), int64(track.ClockRate()), int64(time.Second))
+
+		// sparse tracks can't be used as reference since their timestamp would become stale.
+		if _, sparse := d.sparseTracks[track]; d.leadingTrack == nil && !sparse {
+			d.leadingTrack = track
+			d.startNTP = now
+			d.startPTS = startPTS
+			d.startPTSClockRate = int64(track.ClockRate())
+		}
 
 		d.tracks[track] = &globalDecoder2TrackData{
 			overall: startPTS,
@@ -0,0 +1,82 @@
+package rtptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalDecoder2Sparse(t *testing.T) {
+	g := NewGlobalDecoder2()
+
+	t1 := &dummyTrack{clockRate: 90000, ptsEqualsDTS: true}
+	t2 := &dummyTrack{clockRate: 48000, ptsEqualsDTS: true}
+	t3 := &dummyTrack{clockRate: 90000, ptsEqualsDTS: true}
+
+	g.SetSparse(t1)
+
+	timeNow = func() time.Time {
+		return time.Date(2008, 0o5, 20, 22, 15, 20, 0, time.UTC)
+	}
+
+	pts, ok := g.Decode(t1, &rtp.Packet{Header: rtp.Header{Timestamp: 22500}})
+	require.Equal(t, true, ok)
+	require.Equal(t, int64(0), pts)
+
+	timeNow = func() time.Time {
+		return time.Date(2008, 0o5, 20, 22, 15, 22, 0, time.UTC)
+	}
+
+	// first non-sparse track becomes the leading track
+	pts, ok = g.Decode(t2, &rtp.Packet{Header: rtp.Header{Timestamp: 33100}})
+	require.Equal(t, true, ok)
+	require.Equal(t, int64(2*48000), pts)
+
+	timeNow = func() time.Time {
+		return time.Date(2008, 0o5, 20, 22, 15, 23, 0, time.UTC)
+	}
+
+	pts, ok = g.Decode(t2, &rtp.Packet{Header: rtp.Header{Timestamp: 33100 + 48000}})
+	require.Equal(t, true, ok)
+	require.Equal(t, int64(3*48000), pts)
+
+	timeNow = func() time.Time {
+		return time.Date(2008, 0o5, 20, 22, 15, 30, 0, time.UTC)
+	}
+
+	pts, ok = g.Decode(t1, &rtp.Packet{Header: rtp.Header{Timestamp: 22500 + 10*90000}})
+	require.Equal(t, true, ok)
+	require.Equal(t, int64(10*90000), pts)
+
+	// new tracks are synchronized with the leading track
+	pts, ok = g.Decode(t3, &rtp.Packet{Header: rtp.Header{Timestamp: 12345}})
+	require.Equal(t, true, ok)
+	require.Equal(t, int64(10*90000), pts)
+}
+
+func TestGlobalDecoder2Speed(t *testing.T) {
+	g := NewGlobalDecoder2()
+	g.SetSpeed(4)
+
+	t1 := &dummyTrack{clockRate: 90000, ptsEqualsDTS: true}
+	t2 := &dummyTrack{clockRate: 48000, ptsEqualsDTS: true}
+
+	timeNow = func() time.Time {
+		return time.Date(2008, 0o5, 20, 22, 15, 20, 0, time.UTC)
+	}
+
+	pts, ok := g.Decode(t1, &rtp.Packet{Header: rtp.Header{Timestamp: 22500}})
+	require.Equal(t, true, ok)
+	require.Equal(t, int64(0), pts)
+
+	timeNow = func() time.Time {
+		return time.Date(2008, 0o5, 20, 22, 15, 22, 0, time.UTC)
+	}
+
+	// two seconds of real time are eight seconds of stream time
+	pts, ok = g.Decode(t2, &rtp.Packet{Header: rtp.Header{Timestamp: 33100}})
+	require.Equal(t, true, ok)
+	require.Equal(t, int64(8*48000), pts)
+}
@@ -0,0 +1,48 @@
+package gortsplib
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+func lookupIP(
+	ctx context.Context,
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error),
+	host string,
+) (net.IP, error) {
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for host %s", host)
+	}
+
+	return addrs[0].IP, nil
+}
+
+// resolveAddress replaces the host name of an address with its first IP.
+// Addresses that contain IPs or no host are returned unchanged.
+func resolveAddress(
+	ctx context.Context,
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error),
+	address string,
+) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+
+	if host == "" || net.ParseIP(host) != nil {
+		return address, nil
+	}
+
+	ip, err := lookupIP(ctx, lookupIPAddr, host)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(ip.String(), port), nil
+}
@@ -3,10 +3,10 @@ package gortsplib
 import (
 	"context"
 	"crypto/tls"
-	"fmt"
 	"net"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bluenviron/gortsplib/v4/pkg/base"
@@ -45,6 +45,14 @@ type chGetMulticastIPReq struct {
 	res chan net.IP
 }
 
+type chGetSessionsReq struct {
+	res chan []*ServerSession
+}
+
+type chGetStatsReq struct {
+	res chan *StatsServer
+}
+
 // Server is a RTSP server.
 type Server struct {
 	//
@@ -53,32 +61,54 @@ type Server struct {
 	// the RTSP address of the server, to accept connections and send and receive
 	// packets with the TCP transport.
 	RTSPAddress string
+	// transport settings.
+	TransportOptions ServerTransportOptions
+	// timeout settings.
+	TimeoutOptions TimeoutOptions
+	// security settings.
+	SecurityOptions SecurityOptions
+	// socket settings.
+	SocketOptions SocketOptions
+	// strict mode settings.
+	StrictOptions StrictOptions
+	// limits.
+	LimitOptions ServerLimitOptions
+	// authentication settings.
+	AuthOptions ServerAuthOptions
+	// session settings.
+	SessionOptions ServerSessionOptions
+	// media activity settings.
+	MediaActivityOptions MediaActivityOptions
+	// logging, metrics and statistics settings.
+	ObservabilityOptions ObservabilityOptions
+	// memory management settings.
+	MemoryOptions MemoryOptions
 	// a port to send and receive RTP packets with the UDP transport.
-	// If UDPRTPAddress and UDPRTCPAddress are filled, the server can support the UDP transport.
+	// Deprecated: use TransportOptions.UDPRTPAddress.
 	UDPRTPAddress string
 	// a port to send and receive RTCP packets with the UDP transport.
-	// If UDPRTPAddress and UDPRTCPAddress are filled, the server can support the UDP transport.
+	// Deprecated: use TransportOptions.UDPRTCPAddress.
 	UDPRTCPAddress string
 	// a range of multicast IPs to use with the UDP-multicast transport.
-	// If MulticastIPRange, MulticastRTPPort, MulticastRTCPPort are filled, the server
-	// can support the UDP-multicast transport.
+	// Deprecated: use TransportOptions.MulticastIPRange.
 	MulticastIPRange string
 	// a port to send RTP packets with the UDP-multicast transport.
-	// If MulticastIPRange, MulticastRTPPort, MulticastRTCPPort are filled, the server
-	// can support the UDP-multicast transport.
+	// Deprecated: use TransportOptions.MulticastRTPPort.
 	MulticastRTPPort int
 	// a port to send RTCP packets with the UDP-multicast transport.
-	// If MulticastIPRange, MulticastRTPPort, MulticastRTCPPort are filled, the server
-	// can support the UDP-multicast transport.
+	// Deprecated: use TransportOptions.MulticastRTCPPort.
 	MulticastRTCPPort int
 	// timeout of read operations.
-	// It defaults to 10 seconds
+	// Deprecated: use TimeoutOptions.Read.
 	ReadTimeout time.Duration
 	// timeout of write operations.
-	// It defaults to 10 seconds
+	// Deprecated: use TimeoutOptions.Write.
 	WriteTimeout time.Duration
 	// a TLS configuration to accept TLS (RTSPS) connections.
+	// Deprecated: use SecurityOptions.TLSConfig.
 	TLSConfig *tls.Config
+	// the following settings predate option groups and are not deprecated,
+	// therefore they are kept here in order not to break existing code.
 	// Size of the queue of outgoing packets.
 	// It defaults to 256.
 	WriteQueueSize int
@@ -96,6 +126,19 @@ type Server struct {
 	// It may implement one or more of the ServerHandler* interfaces.
 	Handler ServerHandler
 
+	//
+	// callbacks (all optional)
+	//
+	// called when a connection is accepted, with the remote address of the connection.
+	// If it returns false, the connection is closed before reading any data from it.
+	OnConnFilter func(net.Addr) bool
+	// called to retrieve the password of a user that is authenticating.
+	// When set, the server sends authentication challenges and validates credentials
+	// of requests whose method is in AuthOptions.RequiredMethods, before calling handlers.
+	// It must return false when the user doesn't exist.
+	// It can be changed while the server is running with SetLimits().
+	OnAuthLookup func(*ServerAuthLookupCtx) (string, bool)
+
 	//
 	// system functions (all optional)
 	//
@@ -105,15 +148,19 @@ type Server struct {
 	// function used to initialize UDP listeners.
 	// It defaults to net.ListenPacket.
 	ListenPacket func(network, address string) (net.PacketConn, error)
+	// function used to resolve host names of RTSPAddress, UDPRTPAddress and UDPRTCPAddress.
+	// It allows to use a private DNS, mDNS or a static map.
+	// It defaults to net.DefaultResolver.LookupIPAddr.
+	LookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
 
 	//
 	// private
 	//
 
+	limits               atomic.Pointer[ServerLimits]
 	timeNow              func() time.Time
 	senderReportPeriod   time.Duration
 	receiverReportPeriod time.Duration
-	sessionTimeout       time.Duration
 	checkStreamPeriod    time.Duration
 
 	ctx             context.Context
@@ -126,7 +173,13 @@ type Server struct {
 	udpRTCPListener *serverUDPListener
 	sessions        map[string]*ServerSession
 	conns           map[*ServerConn]struct{}
+	acceptedConns   int
+	connsPerIP      map[string]int
+	sessionsPerIP   map[string]int
 	closeError      error
+	connsAccepted   uint64
+	tlsFailures     uint64
+	transportStats  map[Transport]*serverTransportStats
 
 	// in
 	chNewConn        chan net.Conn
@@ -135,27 +188,62 @@ type Server struct {
 	chHandleRequest  chan sessionRequestReq
 	chCloseSession   chan *ServerSession
 	chGetMulticastIP chan chGetMulticastIPReq
+	chGetSessions    chan chGetSessionsReq
+	chGetStats       chan chGetStatsReq
 }
 
 // Start starts the server.
 func (s *Server) Start() error {
+	err := s.Validate()
+	if err != nil {
+		return err
+	}
+
+	s.TransportOptions, s.TimeoutOptions, s.SecurityOptions = s.mergeDeprecatedFields()
+
 	// RTSP parameters
-	if s.ReadTimeout == 0 {
-		s.ReadTimeout = 10 * time.Second
+	if s.TimeoutOptions.Read == 0 {
+		s.TimeoutOptions.Read = 10 * time.Second
 	}
-	if s.WriteTimeout == 0 {
-		s.WriteTimeout = 10 * time.Second
+	if s.TimeoutOptions.Write == 0 {
+		s.TimeoutOptions.Write = 10 * time.Second
 	}
 	if s.WriteQueueSize == 0 {
 		s.WriteQueueSize = 256
-	} else if (s.WriteQueueSize & (s.WriteQueueSize - 1)) != 0 {
-		return fmt.Errorf("WriteQueueSize must be a power of two")
+	}
+	if s.SocketOptions.UDPReadBufferSize == 0 {
+		s.SocketOptions.UDPReadBufferSize = udpKernelReadBufferSize
+	}
+	if s.SocketOptions.UDPMaxPayloadSize == 0 {
+		s.SocketOptions.UDPMaxPayloadSize = udpMaxReadPayloadSize
 	}
 	if s.MaxPacketSize == 0 {
 		s.MaxPacketSize = udpMaxPayloadSize
-	} else if s.MaxPacketSize > udpMaxPayloadSize {
-		return fmt.Errorf("MaxPacketSize must be less than %d", udpMaxPayloadSize)
 	}
+	if s.SessionOptions.Timeout == 0 {
+		s.SessionOptions.Timeout = 1 * 60 * time.Second
+	}
+	if s.ObservabilityOptions.StatsPeriod == 0 {
+		s.ObservabilityOptions.StatsPeriod = 1 * time.Second
+	}
+	if s.SessionOptions.Activity == 0 {
+		s.SessionOptions.Activity = ServerSessionActivityRequests | ServerSessionActivityPackets
+	}
+	if s.MediaActivityOptions.MediaActivity == nil {
+		s.MediaActivityOptions.MediaActivity = defaultMediaActivity
+	}
+	if s.MediaActivityOptions.SparseReadTimeout == 0 {
+		s.MediaActivityOptions.SparseReadTimeout = 60 * time.Second
+	}
+	if s.ObservabilityOptions.Logger == nil {
+		s.ObservabilityOptions.Logger = stdLogger{}
+	}
+	if s.ObservabilityOptions.Metrics == nil {
+		s.ObservabilityOptions.Metrics = nilMetrics{}
+	}
+	limits := s.initialLimits(s.TimeoutOptions)
+	limits.fillDefaults()
+	s.limits.Store(&limits)
 
 	// system functions
 	if s.Listen == nil {
@@ -164,6 +252,9 @@ func (s *Server) Start() error {
 	if s.ListenPacket == nil {
 		s.ListenPacket = net.ListenPacket
 	}
+	if s.LookupIPAddr == nil {
+		s.LookupIPAddr = net.DefaultResolver.LookupIPAddr
+	}
 
 	// private
 	if s.timeNow == nil {
@@ -175,54 +266,38 @@ func (s *Server) Start() error {
 	if s.receiverReportPeriod == 0 {
 		s.receiverReportPeriod = 10 * time.Second
 	}
-	if s.sessionTimeout == 0 {
-		s.sessionTimeout = 1 * 60 * time.Second
-	}
 	if s.checkStreamPeriod == 0 {
 		s.checkStreamPeriod = 1 * time.Second
 	}
 
-	if s.TLSConfig != nil && s.UDPRTPAddress != "" {
-		return fmt.Errorf("TLS can't be used with UDP")
-	}
-
-	if s.TLSConfig != nil && s.MulticastIPRange != "" {
-		return fmt.Errorf("TLS can't be used with UDP-multicast")
-	}
-
-	if s.RTSPAddress == "" {
-		return fmt.Errorf("RTSPAddress not provided")
-	}
-
-	if (s.UDPRTPAddress != "" && s.UDPRTCPAddress == "") ||
-		(s.UDPRTPAddress == "" && s.UDPRTCPAddress != "") {
-		return fmt.Errorf("UDPRTPAddress and UDPRTCPAddress must be used together")
+	rtspAddress, err := resolveAddress(context.Background(), s.LookupIPAddr, s.RTSPAddress)
+	if err != nil {
+		return err
 	}
 
-	if s.UDPRTPAddress != "" {
-		rtpPort, err := extractPort(s.UDPRTPAddress)
+	if s.TransportOptions.UDPRTPAddress != "" {
+		var udpRTPAddress string
+		udpRTPAddress, err = resolveAddress(context.Background(), s.LookupIPAddr, s.TransportOptions.UDPRTPAddress)
 		if err != nil {
 			return err
 		}
 
-		rtcpPort, err := extractPort(s.UDPRTCPAddress)
+		var udpRTCPAddress string
+		udpRTCPAddress, err = resolveAddress(context.Background(), s.LookupIPAddr, s.TransportOptions.UDPRTCPAddress)
 		if err != nil {
 			return err
 		}
 
-		if (rtpPort % 2) != 0 {
-			return fmt.Errorf("RTP port must be even")
-		}
-
-		if rtcpPort != (rtpPort + 1) {
-			return fmt.Errorf("RTP and RTCP ports must be consecutive")
-		}
-
 		s.udpRTPListener = &serverUDPListener{
 			listenPacket:    s.ListenPacket,
-			writeTimeout:    s.WriteTimeout,
+			writeTimeout:    s.TimeoutOptions.Write,
 			multicastEnable: false,
-			address:         s.UDPRTPAddress,
+			address:         udpRTPAddress,
+			poolBuffers:     s.MemoryOptions.PoolPackets,
+			readBufferSize:  s.SocketOptions.UDPReadBufferSize,
+			writeBufferSize: s.SocketOptions.UDPWriteBufferSize,
+			maxPayloadSize:  s.SocketOptions.UDPMaxPayloadSize,
+			dscp:            s.SocketOptions.DSCP,
 		}
 		err = s.udpRTPListener.initialize()
 		if err != nil {
@@ -231,9 +306,13 @@ func (s *Server) Start() error {
 
 		s.udpRTCPListener = &serverUDPListener{
 			listenPacket:    s.ListenPacket,
-			writeTimeout:    s.WriteTimeout,
+			writeTimeout:    s.TimeoutOptions.Write,
 			multicastEnable: false,
-			address:         s.UDPRTCPAddress,
+			address:         udpRTCPAddress,
+			readBufferSize:  s.SocketOptions.UDPReadBufferSize,
+			writeBufferSize: s.SocketOptions.UDPWriteBufferSize,
+			maxPayloadSize:  s.SocketOptions.UDPMaxPayloadSize,
+			dscp:            s.SocketOptions.DSCP,
 		}
 		err = s.udpRTCPListener.initialize()
 		if err != nil {
@@ -242,51 +321,8 @@ func (s *Server) Start() error {
 		}
 	}
 
-	if s.MulticastIPRange != "" && (s.MulticastRTPPort == 0 || s.MulticastRTCPPort == 0) ||
-		(s.MulticastRTPPort != 0 && (s.MulticastRTCPPort == 0 || s.MulticastIPRange == "")) ||
-		s.MulticastRTCPPort != 0 && (s.MulticastRTPPort == 0 || s.MulticastIPRange == "") {
-		if s.udpRTPListener != nil {
-			s.udpRTPListener.close()
-		}
-		if s.udpRTCPListener != nil {
-			s.udpRTCPListener.close()
-		}
-		return fmt.Errorf("MulticastIPRange, MulticastRTPPort and MulticastRTCPPort must be used together")
-	}
-
-	if s.MulticastIPRange != "" {
-		if (s.MulticastRTPPort % 2) != 0 {
-			if s.udpRTPListener != nil {
-				s.udpRTPListener.close()
-			}
-			if s.udpRTCPListener != nil {
-				s.udpRTCPListener.close()
-			}
-			return fmt.Errorf("RTP port must be even")
-		}
-
-		if s.MulticastRTCPPort != (s.MulticastRTPPort + 1) {
-			if s.udpRTPListener != nil {
-				s.udpRTPListener.close()
-			}
-			if s.udpRTCPListener != nil {
-				s.udpRTCPListener.close()
-			}
-			return fmt.Errorf("RTP and RTCP ports must be consecutive")
-		}
-
-		var err error
-		_, s.multicastNet, err = net.ParseCIDR(s.MulticastIPRange)
-		if err != nil {
-			if s.udpRTPListener != nil {
-				s.udpRTPListener.close()
-			}
-			if s.udpRTCPListener != nil {
-				s.udpRTCPListener.close()
-			}
-			return err
-		}
-
+	if s.TransportOptions.MulticastIPRange != "" {
+		_, s.multicastNet, _ = net.ParseCIDR(s.TransportOptions.MulticastIPRange)
 		s.multicastNextIP = s.multicastNet.IP
 	}
 
@@ -294,17 +330,27 @@ func (s *Server) Start() error {
 
 	s.sessions = make(map[string]*ServerSession)
 	s.conns = make(map[*ServerConn]struct{})
+	s.transportStats = map[Transport]*serverTransportStats{
+		TransportUDP:          {},
+		TransportUDPMulticast: {},
+		TransportTCP:          {},
+	}
+	s.connsPerIP = make(map[string]int)
+	s.sessionsPerIP = make(map[string]int)
 	s.chNewConn = make(chan net.Conn)
 	s.chAcceptErr = make(chan error)
 	s.chCloseConn = make(chan *ServerConn)
 	s.chHandleRequest = make(chan sessionRequestReq)
 	s.chCloseSession = make(chan *ServerSession)
 	s.chGetMulticastIP = make(chan chGetMulticastIPReq)
+	s.chGetSessions = make(chan chGetSessionsReq)
+	s.chGetStats = make(chan chGetStatsReq)
 
 	s.tcpListener = &serverTCPListener{
-		s: s,
+		s:       s,
+		address: rtspAddress,
 	}
-	err := s.tcpListener.initialize()
+	err = s.tcpListener.initialize()
 	if err != nil {
 		if s.udpRTPListener != nil {
 			s.udpRTPListener.close()
@@ -342,6 +388,12 @@ func (s *Server) run() {
 
 	s.ctxCancel()
 
+	// connections and sessions that are still in maps are closed by the context.
+	// They were not counted as closed yet, since removals from maps
+	// and gauge decrements happen together in runInner(), that is not running anymore.
+	s.ObservabilityOptions.Metrics.AddConns(-len(s.conns))
+	s.ObservabilityOptions.Metrics.AddSessions(-len(s.sessions))
+
 	if s.udpRTCPListener != nil {
 		s.udpRTCPListener.close()
 	}
@@ -360,12 +412,23 @@ func (s *Server) runInner() error {
 			return err
 
 		case nconn := <-s.chNewConn:
+			ip := nconn.RemoteAddr().(*net.TCPAddr).IP.String()
+
+			if s.connLimitReached(ip) {
+				s.rejectConn(nconn)
+				continue
+			}
+
 			sc := &ServerConn{
 				s:     s,
 				nconn: nconn,
 			}
+			s.ObservabilityOptions.Metrics.AddConns(1)
 			sc.initialize()
 			s.conns[sc] = struct{}{}
+			s.acceptedConns++
+			s.connsAccepted++
+			s.connsPerIP[ip]++
 
 		case sc := <-s.chCloseConn:
 			if _, ok := s.conns[sc]; !ok {
@@ -373,6 +436,9 @@ func (s *Server) runInner() error {
 			}
 			delete(s.conns, sc)
 			sc.Close()
+			s.ObservabilityOptions.Metrics.AddConns(-1)
+			s.acceptedConns--
+			decreaseIPCount(s.connsPerIP, sc.ip().String())
 
 		case req := <-s.chHandleRequest:
 			if ss, ok := s.sessions[req.id]; ok {
@@ -408,12 +474,26 @@ func (s *Server) runInner() error {
 					continue
 				}
 
+				ip := req.sc.ip().String()
+
+				if s.sessionLimitReached(ip) {
+					req.res <- sessionRequestRes{
+						res: &base.Response{
+							StatusCode: base.StatusServiceUnavailable,
+						},
+						err: liberrors.ErrServerTooManySessions{},
+					}
+					continue
+				}
+
 				ss := &ServerSession{
 					s:      s,
 					author: req.sc,
 				}
 				ss.initialize()
 				s.sessions[ss.secretID] = ss
+				s.sessionsPerIP[ip]++
+				s.ObservabilityOptions.Metrics.AddSessions(1)
 
 				select {
 				case ss.chHandleRequest <- req:
@@ -433,6 +513,8 @@ func (s *Server) runInner() error {
 			}
 			delete(s.sessions, ss.secretID)
 			ss.Close()
+			s.ObservabilityOptions.Metrics.AddSessions(-1)
+			decreaseIPCount(s.sessionsPerIP, ss.author.ip().String())
 
 		case req := <-s.chGetMulticastIP:
 			ip32 := uint32(s.multicastNextIP[0])<<24 | uint32(s.multicastNextIP[1])<<16 |
@@ -448,6 +530,16 @@ func (s *Server) runInner() error {
 			s.multicastNextIP = ip
 			req.res <- ip
 
+		case req := <-s.chGetSessions:
+			ret := make([]*ServerSession, 0, len(s.sessions))
+			for _, ss := range s.sessions {
+				ret = append(ret, ss)
+			}
+			req.res <- ret
+
+		case req := <-s.chGetStats:
+			req.res <- s.stats()
+
 		case <-s.ctx.Done():
 			return liberrors.ErrServerTerminated{}
 		}
@@ -464,6 +556,54 @@ func (s *Server) StartAndWait() error {
 	return s.Wait()
 }
 
+// Sessions returns all open sessions, in no particular order.
+// Sessions can be inspected with their State(), SetuppedTransport(), RemoteAddr()
+// and Stats() methods, and can be closed with Kick().
+// It must not be called inside a ServerHandler callback.
+func (s *Server) Sessions() []*ServerSession {
+	res := make(chan []*ServerSession)
+	select {
+	case s.chGetSessions <- chGetSessionsReq{res: res}:
+		return <-res
+
+	case <-s.ctx.Done():
+		return nil
+	}
+}
+
+// Stats returns server statistics.
+// It must not be called inside a ServerHandler callback.
+func (s *Server) Stats() *StatsServer {
+	res := make(chan *StatsServer)
+	select {
+	case s.chGetStats <- chGetStatsReq{res: res}:
+		return <-res
+
+	case <-s.ctx.Done():
+		return nil
+	}
+}
+
+func (s *Server) stats() *StatsServer {
+	st := &StatsServer{
+		ConnsAccepted:        s.connsAccepted,
+		ConnsOpen:            len(s.conns),
+		TLSHandshakeFailures: atomic.LoadUint64(&s.tlsFailures),
+		Sessions:             make(map[ServerSessionState]int),
+		Transports:           make(map[Transport]StatsServerTransport, len(s.transportStats)),
+	}
+
+	for _, ss := range s.sessions {
+		st.Sessions[ss.loadState()]++
+	}
+
+	for tr, ts := range s.transportStats {
+		st.Transports[tr] = ts.stats()
+	}
+
+	return st
+}
+
 func (s *Server) getMulticastIP() (net.IP, error) {
 	res := make(chan net.IP)
 	select {
@@ -0,0 +1,95 @@
+package gortsplib
+
+import (
+	"github.com/bluenviron/gortsplib/v4/pkg/auth"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/headers"
+)
+
+// ServerAuthLookupCtx is the context of Server.OnAuthLookup.
+type ServerAuthLookupCtx struct {
+	Conn    *ServerConn
+	Request *base.Request
+	Path    string
+	Query   string
+	User    string
+}
+
+var defaultAuthRequiredMethods = []base.Method{
+	base.Describe,
+	base.Announce,
+	base.Setup,
+}
+
+var defaultAuthValidateMethods = []auth.ValidateMethod{
+	auth.ValidateMethodDigestMD5,
+	auth.ValidateMethodSHA256,
+}
+
+func (l *ServerLimits) authRequired(method base.Method) bool {
+	if l.OnAuthLookup == nil {
+		return false
+	}
+
+	for _, m := range l.AuthRequiredMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate validates credentials of a request.
+// It returns a response when the request is not authenticated.
+func (sc *ServerConn) authenticate(req *base.Request, l *ServerLimits) *base.Response {
+	if sc.authNonce == "" {
+		var err error
+		sc.authNonce, err = auth.GenerateNonce()
+		if err != nil {
+			return &base.Response{
+				StatusCode: base.StatusInternalServerError,
+			}
+		}
+	}
+
+	var hdr headers.Authorization
+	if hdr.Unmarshal(req.Header["Authorization"]) == nil {
+		user := hdr.Username
+		if hdr.Method == headers.AuthMethodBasic {
+			user = hdr.BasicUser
+		}
+
+		ctx := &ServerAuthLookupCtx{
+			Conn:    sc,
+			Request: req,
+			User:    user,
+		}
+
+		if req.URL != nil {
+			switch req.Method {
+			case base.Setup:
+				ctx.Path, ctx.Query, _, _ = getPathAndQueryAndTrackID(req.URL)
+
+			case base.Announce:
+				ctx.Path, ctx.Query = getPathAndQuery(req.URL, true)
+
+			default:
+				ctx.Path, ctx.Query = getPathAndQuery(req.URL, false)
+			}
+		}
+
+		if pass, ok := l.OnAuthLookup(ctx); ok {
+			err := auth.Validate(req, user, pass, l.AuthValidateMethods, l.AuthRealm, sc.authNonce)
+			if err == nil {
+				return nil
+			}
+		}
+	}
+
+	return &base.Response{
+		StatusCode: base.StatusUnauthorized,
+		Header: base.Header{
+			"WWW-Authenticate": auth.GenerateWWWAuthenticate(l.AuthValidateMethods, l.AuthRealm, sc.authNonce),
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package gortsplib
+
+// ServerCongestionPolicy is the policy used to drop packets
+// when the write queue of a reader is full.
+type ServerCongestionPolicy int
+
+// policies.
+const (
+	// drop packets that do not fit into the queue.
+	ServerCongestionPolicyDropPackets ServerCongestionPolicy = iota
+
+	// when a packet does not fit into the queue,
+	// drop remaining packets of the same access unit.
+	ServerCongestionPolicyDropAccessUnits
+
+	// when a packet does not fit into the queue,
+	// drop packets until the next random access point (i.e. IDR frame).
+	// While dropping, packets of each access unit are buffered until the access unit
+	// is complete, in order to check whether it contains a random access point.
+	// With formats that don't provide random access points,
+	// this is equivalent to ServerCongestionPolicyDropAccessUnits.
+	ServerCongestionPolicyDropUntilRandomAccess
+)
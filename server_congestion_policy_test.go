@@ -0,0 +1,182 @@
+package gortsplib
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+)
+
+func TestServerCongestionPolicy(t *testing.T) {
+	for _, ca := range []struct {
+		name        string
+		policy      ServerCongestionPolicy
+		sent        []string
+		congestions []uint64
+	}{
+		{
+			"drop packets",
+			ServerCongestionPolicyDropPackets,
+			[]string{"A", "B1", "B3", "C", "E1", "E2", "D1", "D2"},
+			[]uint64{1},
+		},
+		{
+			"drop access units",
+			ServerCongestionPolicyDropAccessUnits,
+			[]string{"A", "B1", "C", "E1", "E2", "D1", "D2"},
+			[]uint64{1},
+		},
+		{
+			"drop until random access",
+			ServerCongestionPolicyDropUntilRandomAccess,
+			[]string{"A", "B1", "D1", "D2"},
+			[]uint64{1},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			medi := &description.Media{
+				Type:    description.MediaTypeVideo,
+				Formats: []format.Format{&format.H264{PayloadTyp: 96}},
+			}
+
+			var congestions []uint64
+
+			ss := &ServerSession{
+				s: &Server{
+					SessionOptions:       ServerSessionOptions{CongestionPolicy: ca.policy},
+					ObservabilityOptions: ObservabilityOptions{Metrics: nilMetrics{}},
+					Handler: &testServerHandler{
+						onReaderCongestion: func(ctx *ServerHandlerOnReaderCongestionCtx) {
+							require.Equal(t, medi, ctx.Media)
+							require.Equal(t, 2, ctx.QueueSize)
+							congestions = append(congestions, ctx.PacketsDropped)
+						},
+					},
+				},
+				writer: &asyncProcessor{bufferSize: 2},
+			}
+			ss.writer.initialize()
+
+			sm := &serverSessionMedia{ss: ss, media: medi}
+			sf := &serverSessionFormat{sm: sm, format: medi.Formats[0]}
+			sf.initialize()
+			sf.accessUnitStart = true
+
+			var sent []string
+			sf.writePacketRTPInQueue = func(byts []byte, _ bool) error {
+				var pkt rtp.Packet
+				err := pkt.Unmarshal(byts)
+				require.NoError(t, err)
+				sent = append(sent, string(pkt.Payload[2:]))
+				return nil
+			}
+
+			written := 0
+
+			write := func(payload []byte, marker bool) {
+				pkt := &rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						PayloadType:    96,
+						Marker:         marker,
+						SequenceNumber: uint16(written),
+					},
+					Payload: payload,
+				}
+				byts, err := pkt.Marshal()
+				require.NoError(t, err)
+				sf.writePacketsRTP([]*rtp.Packet{pkt}, [][]byte{byts}, nil) //nolint:errcheck
+				written++
+			}
+
+			drain := func() {
+				queued := written - int(atomic.LoadUint64(sf.rtpPacketsDropped)) - len(sent)
+				for i := 0; i < queued; i++ {
+					cb, ok := ss.writer.buffer.Pull()
+					require.True(t, ok)
+					err := runAsyncEntry(cb)
+					require.NoError(t, err)
+				}
+			}
+
+			// non-IDR
+			write([]byte{0x41, 0x00, 'A'}, true)
+			// fragmented non-IDR
+			write([]byte{0x7c, 0x81, 'B', '1'}, false)
+			write([]byte{0x7c, 0x01, 'B', '2'}, false)
+			drain()
+			write([]byte{0x7c, 0x41, 'B', '3'}, true)
+			// non-IDR
+			write([]byte{0x41, 0x00, 'C'}, true)
+			drain()
+			// SPS followed by non-IDR
+			write([]byte{0x67, 0x00, 'E', '1'}, false)
+			write([]byte{0x41, 0x00, 'E', '2'}, true)
+			drain()
+			// fragmented IDR
+			write([]byte{0x7c, 0x85, 'D', '1'}, false)
+			write([]byte{0x7c, 0x45, 'D', '2'}, true)
+			drain()
+
+			require.Equal(t, ca.sent, sent)
+			require.Equal(t, ca.congestions, congestions)
+		})
+	}
+}
+
+func TestServerSessionFormatWriteAllocs(t *testing.T) {
+	medi := &description.Media{
+		Type:    description.MediaTypeVideo,
+		Formats: []format.Format{&format.H264{PayloadTyp: 96}},
+	}
+
+	ss := &ServerSession{
+		s: &Server{
+			ObservabilityOptions: ObservabilityOptions{Metrics: nilMetrics{}},
+		},
+		writer: &asyncProcessor{bufferSize: 8},
+	}
+	ss.writer.initialize()
+
+	sm := &serverSessionMedia{ss: ss, media: medi}
+	sf := &serverSessionFormat{sm: sm, format: medi.Formats[0]}
+	sf.initialize()
+	sf.writePacketRTPInQueue = func([]byte, bool) error {
+		return nil
+	}
+
+	pkts := []*rtp.Packet{
+		{
+			Header:  rtp.Header{Version: 2, PayloadType: 96},
+			Payload: []byte{0x7c, 0x85, 1, 2},
+		},
+		{
+			Header:  rtp.Header{Version: 2, PayloadType: 96, Marker: true},
+			Payload: []byte{0x7c, 0x45, 3, 4},
+		},
+	}
+
+	byts := make([][]byte, len(pkts))
+	for i, pkt := range pkts {
+		var err error
+		byts[i], err = pkt.Marshal()
+		require.NoError(t, err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		err := sf.writePacketsRTP(pkts, byts, nil)
+		require.NoError(t, err)
+
+		for range pkts {
+			cb, ok := ss.writer.buffer.Pull()
+			require.True(t, ok)
+			err = runAsyncEntry(cb)
+			require.NoError(t, err)
+		}
+	})
+	require.Equal(t, float64(0), allocs)
+}
@@ -8,8 +8,12 @@ import (
 	gourl "net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/bluenviron/gortsplib/v4/pkg/base"
 	"github.com/bluenviron/gortsplib/v4/pkg/bytecounter"
 	"github.com/bluenviron/gortsplib/v4/pkg/conn"
@@ -26,9 +30,13 @@ func getSessionID(header base.Header) string {
 
 func serverSideDescription(d *description.Session) *description.Session {
 	out := &description.Session{
-		Title:     d.Title,
-		FECGroups: d.FECGroups,
-		Medias:    make([]*description.Media, len(d.Medias)),
+		Title:             d.Title,
+		FECGroups:         d.FECGroups,
+		Origin:            d.Origin,
+		Information:       d.Information,
+		Tool:              d.Tool,
+		ConnectionAddress: d.ConnectionAddress,
+		Medias:            make([]*description.Media, len(d.Medias)),
 	}
 
 	for i, medi := range d.Medias {
@@ -36,9 +44,12 @@ func serverSideDescription(d *description.Session) *description.Session {
 			Type:          medi.Type,
 			ID:            medi.ID,
 			IsBackChannel: medi.IsBackChannel,
+			Direction:     medi.Direction,
 			// we have to use trackID=number in order to support clients
 			// like the Grandstream GXV3500.
 			Control: "trackID=" + strconv.FormatInt(int64(i), 10),
+			Title:   medi.Title,
+			Label:   medi.Label,
 			Formats: medi.Formats,
 		}
 	}
@@ -56,6 +67,7 @@ type ServerConn struct {
 	s     *Server
 	nconn net.Conn
 
+	id         uuid.UUID
 	ctx        context.Context
 	ctxCancel  func()
 	userData   interface{}
@@ -65,6 +77,15 @@ type ServerConn struct {
 	session    *ServerSession
 	reader     *serverConnReader
 
+	serverCSeq            int
+	strictCSeq            strictCSeqChecker
+	pendingServerRequests map[string]time.Time // CSeq -> expiration
+	pendingMutex          sync.Mutex
+	rateWindowStart       time.Time
+	rateWindowRequests    int
+	badRequests           int
+	authNonce             string
+
 	// in
 	chRemoveSession chan *ServerSession
 
@@ -75,14 +96,16 @@ type ServerConn struct {
 func (sc *ServerConn) initialize() {
 	ctx, ctxCancel := context.WithCancel(sc.s.ctx)
 
-	if sc.s.TLSConfig != nil {
-		sc.nconn = tls.Server(sc.nconn, sc.s.TLSConfig)
+	if sc.s.SecurityOptions.TLSConfig != nil {
+		sc.nconn = tls.Server(sc.nconn, sc.s.SecurityOptions.TLSConfig)
 	}
 
+	sc.id = uuid.New()
 	sc.bc = bytecounter.New(sc.nconn, nil, nil)
 	sc.ctx = ctx
 	sc.ctxCancel = ctxCancel
 	sc.remoteAddr = sc.nconn.RemoteAddr().(*net.TCPAddr)
+	sc.pendingServerRequests = make(map[string]time.Time)
 	sc.chRemoveSession = make(chan *ServerSession)
 	sc.done = make(chan struct{})
 
@@ -95,6 +118,11 @@ func (sc *ServerConn) Close() {
 	sc.ctxCancel()
 }
 
+// ID returns a unique identifier of the connection.
+func (sc *ServerConn) ID() uuid.UUID {
+	return sc.id
+}
+
 // NetConn returns the underlying net.Conn.
 func (sc *ServerConn) NetConn() net.Conn {
 	return sc.nconn
@@ -156,6 +184,9 @@ func (sc *ServerConn) run() {
 	}
 
 	sc.conn = conn.NewConn(sc.bc)
+	if sc.s.MemoryOptions.PoolPackets {
+		sc.conn.SetPayloadAllocator(getPacketBuffer)
+	}
 	sc.reader = &serverConnReader{
 		sc: sc,
 	}
@@ -171,6 +202,13 @@ func (sc *ServerConn) run() {
 		sc.reader.wait()
 	}
 
+	// connection state can be read safely once the reader has exited.
+	if tc, ok := sc.nconn.(*tls.Conn); ok && !tc.ConnectionState().HandshakeComplete {
+		if _, ok2 := err.(liberrors.ErrServerTerminated); !ok2 {
+			atomic.AddUint64(&sc.s.tlsFailures, 1)
+		}
+	}
+
 	if sc.session != nil {
 		sc.session.removeConn(sc)
 	}
@@ -213,12 +251,24 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 		}, liberrors.ErrServerCSeqMissing{}
 	}
 
+	if sc.isRequestRateExceeded() {
+		return &base.Response{
+			StatusCode: base.StatusServiceUnavailable,
+		}, liberrors.ErrServerTooManyRequests{}
+	}
+
 	if req.Method != base.Options && req.URL == nil {
 		return &base.Response{
 			StatusCode: base.StatusBadRequest,
 		}, liberrors.ErrServerInvalidPath{}
 	}
 
+	if l := sc.s.limits.Load(); l.authRequired(req.Method) {
+		if res := sc.authenticate(req, l); res != nil {
+			return res, nil
+		}
+	}
+
 	sxID := getSessionID(req.Header)
 
 	var path string
@@ -255,7 +305,7 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 			methods = append(methods, string(base.Pause))
 		}
 		methods = append(methods, string(base.GetParameter))
-		if _, ok := sc.s.Handler.(ServerHandlerOnSetParameter); ok {
+		if serverHandlerSupportsSetParameter(sc.s.Handler) {
 			methods = append(methods, string(base.SetParameter))
 		}
 		methods = append(methods, string(base.Teardown))
@@ -269,12 +319,14 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 
 	case base.Describe:
 		if h, ok := sc.s.Handler.(ServerHandlerOnDescribe); ok {
-			res, stream, err := h.OnDescribe(&ServerHandlerOnDescribeCtx{
+			ctx := &ServerHandlerOnDescribeCtx{
 				Conn:    sc,
 				Request: req,
 				Path:    path,
 				Query:   query,
-			})
+			}
+
+			res, stream, err := h.OnDescribe(ctx)
 
 			if res.StatusCode == base.StatusOK {
 				if res.Header == nil {
@@ -288,7 +340,7 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 				// therefore, we introduce a special query (vlcmulticast) that allows
 				// to return a SDP that contains a multicast address.
 				multicast := false
-				if sc.s.MulticastIPRange != "" {
+				if sc.s.TransportOptions.MulticastIPRange != "" {
 					if q, err2 := gourl.ParseQuery(query); err2 == nil {
 						if _, ok := q["vlcmulticast"]; ok {
 							multicast = true
@@ -297,7 +349,13 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 				}
 
 				if stream != nil {
-					byts, _ := serverSideDescription(stream.desc).Marshal(multicast)
+					desc := serverSideDescription(stream.desc)
+
+					if ctx.RewriteDescription != nil {
+						ctx.RewriteDescription(desc)
+					}
+
+					byts, _ := desc.Marshal(multicast)
 					res.Body = byts
 				}
 			}
@@ -346,6 +404,25 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 			return sc.handleRequestInSession(sxID, req, false)
 		}
 
+		if h, ok := sc.s.Handler.(ServerHandlerOnGetParameters); ok && isParametersBody(req) {
+			params := unmarshalParameters(req.Body)
+			if len(params) == 0 {
+				return keepaliveResponse(), nil
+			}
+
+			res, values, err := h.OnGetParameters(&ServerHandlerOnGetParametersCtx{
+				Conn:       sc,
+				Request:    req,
+				Path:       path,
+				Query:      query,
+				Parameters: params,
+			})
+			if err != nil {
+				return res, err
+			}
+			return parametersResponse(res, values), nil
+		}
+
 		if h, ok := sc.s.Handler.(ServerHandlerOnGetParameter); ok {
 			return h.OnGetParameter(&ServerHandlerOnGetParameterCtx{
 				Conn:    sc,
@@ -360,6 +437,16 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 			return sc.handleRequestInSession(sxID, req, false)
 		}
 
+		if h, ok := sc.s.Handler.(ServerHandlerOnSetParameters); ok && isParametersBody(req) {
+			return h.OnSetParameters(&ServerHandlerOnSetParametersCtx{
+				Conn:       sc,
+				Request:    req,
+				Path:       path,
+				Query:      query,
+				Parameters: unmarshalParameters(req.Body),
+			})
+		}
+
 		if h, ok := sc.s.Handler.(ServerHandlerOnSetParameter); ok {
 			return h.OnSetParameter(&ServerHandlerOnSetParameterCtx{
 				Conn:    sc,
@@ -376,11 +463,40 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 }
 
 func (sc *ServerConn) handleRequestOuter(req *base.Request) error {
+	start := sc.s.timeNow()
+
 	if h, ok := sc.s.Handler.(ServerHandlerOnRequest); ok {
 		h.OnRequest(sc, req)
 	}
 
-	res, err := sc.handleRequestInner(req)
+	var res *base.Response
+	var err error
+
+	if sc.s.StrictOptions.Enabled {
+		err = sc.reportViolations(strictCheckRequest(&sc.strictCSeq, req))
+		if err != nil {
+			res = &base.Response{
+				StatusCode: base.StatusBadRequest,
+			}
+		}
+	}
+
+	if err == nil {
+		res, err = sc.handleRequestInner(req)
+
+		if sc.s.StrictOptions.Enabled {
+			var eerr liberrors.ErrServerInvalidState
+			if errors.As(err, &eerr) {
+				// the connection is closed anyway because of the error
+				sc.reportViolations([]*Violation{{ //nolint:errcheck
+					Type:        ViolationTypeStateInvalid,
+					Section:     "A.1",
+					Description: string(req.Method) + " request is not allowed in the current state: " + err.Error(),
+					Request:     req,
+				}})
+			}
+		}
+	}
 
 	if res.Header == nil {
 		res.Header = make(base.Header)
@@ -395,19 +511,130 @@ func (sc *ServerConn) handleRequestOuter(req *base.Request) error {
 	// add server
 	res.Header["Server"] = base.HeaderValue{"gortsplib"}
 
+	if res.StatusCode >= base.StatusBadRequest {
+		sc.badRequests++
+		if maxBad := sc.s.limits.Load().MaxBadRequests; err == nil && maxBad != 0 && sc.badRequests > maxBad {
+			err = liberrors.ErrServerTooManyBadRequests{}
+		}
+	}
+
 	if h, ok := sc.s.Handler.(ServerHandlerOnResponse); ok {
 		h.OnResponse(sc, res)
 	}
 
-	sc.nconn.SetWriteDeadline(time.Now().Add(sc.s.WriteTimeout))
+	sc.nconn.SetWriteDeadline(time.Now().Add(sc.s.limits.Load().WriteTimeout))
 	err2 := sc.conn.WriteResponse(res)
 	if err == nil && err2 != nil {
 		err = err2
 	}
 
+	if h, ok := sc.s.Handler.(ServerHandlerOnRequestDone); ok {
+		h.OnRequestDone(&ServerHandlerOnRequestDoneCtx{
+			Conn:     sc,
+			Request:  req,
+			Response: res,
+			Start:    start,
+			Duration: sc.s.timeNow().Sub(start),
+			Error:    err,
+		})
+	}
+
 	return err
 }
 
+// reportViolations reports violations detected in strict mode.
+// It returns an error if FailOnViolation is true and there's at least a violation.
+func (sc *ServerConn) reportViolations(vs []*Violation) error {
+	for _, v := range vs {
+		if h, ok := sc.s.Handler.(ServerHandlerOnViolation); ok {
+			h.OnViolation(&ServerHandlerOnViolationCtx{
+				Conn:      sc,
+				Violation: v,
+			})
+		} else {
+			args := []interface{}{"conn", sc.ID()}
+			if sc.session != nil {
+				args = append(args, "session", sc.session.id)
+			}
+			sc.s.ObservabilityOptions.Logger.Warn(v.String(), args...)
+		}
+	}
+
+	if len(vs) != 0 && sc.s.StrictOptions.FailOnViolation {
+		return liberrors.ErrServerProtocolViolation{Description: vs[0].Description}
+	}
+
+	return nil
+}
+
+func (sc *ServerConn) isRequestRateExceeded() bool {
+	maxRate := sc.s.limits.Load().MaxRequestsPerSecond
+	if maxRate == 0 {
+		return false
+	}
+
+	now := sc.s.timeNow()
+	if now.Sub(sc.rateWindowStart) >= time.Second {
+		sc.rateWindowStart = now
+		sc.rateWindowRequests = 0
+	}
+
+	sc.rateWindowRequests++
+	return sc.rateWindowRequests > maxRate
+}
+
+// writeServerRequest writes a request originated by the server.
+// It is called by the session routine.
+func (sc *ServerConn) writeServerRequest(req *base.Request) error {
+	if req.Header == nil {
+		req.Header = make(base.Header)
+	}
+
+	sc.serverCSeq++
+	cseq := strconv.FormatInt(int64(sc.serverCSeq), 10)
+	req.Header["CSeq"] = base.HeaderValue{cseq}
+
+	// the response must be ignored by the reader
+	sc.pendingMutex.Lock()
+	sc.removeExpiredServerRequests()
+	sc.pendingServerRequests[cseq] = sc.s.timeNow().Add(sc.s.limits.Load().ReadTimeout)
+	sc.pendingMutex.Unlock()
+
+	sc.nconn.SetWriteDeadline(time.Now().Add(sc.s.limits.Load().WriteTimeout))
+	return sc.conn.WriteRequest(req)
+}
+
+// consumeServerResponse checks whether a response matches, by CSeq,
+// a request originated by the server that is still pending.
+// It is called by the reader.
+func (sc *ServerConn) consumeServerResponse(res *base.Response) bool {
+	sc.pendingMutex.Lock()
+	defer sc.pendingMutex.Unlock()
+
+	sc.removeExpiredServerRequests()
+
+	cseq, ok := res.Header["CSeq"]
+	if !ok || len(cseq) != 1 {
+		return false
+	}
+
+	if _, ok := sc.pendingServerRequests[cseq[0]]; !ok {
+		return false
+	}
+
+	delete(sc.pendingServerRequests, cseq[0])
+	return true
+}
+
+func (sc *ServerConn) removeExpiredServerRequests() {
+	now := sc.s.timeNow()
+	for cseq, expiration := range sc.pendingServerRequests {
+		if now.After(expiration) {
+			delete(sc.pendingServerRequests, cseq)
+		}
+	}
+}
+
 func (sc *ServerConn) handleRequestInSession(
 	sxID string,
 	req *base.Request,
@@ -91,7 +91,9 @@ func (cr *serverConnReader) readFuncStandard() error {
 			}
 
 		case *base.Response:
-			return liberrors.ErrServerUnexpectedResponse{}
+			if !cr.sc.consumeServerResponse(what) {
+				return liberrors.ErrServerUnexpectedResponse{}
+			}
 
 		case *base.InterleavedFrame:
 			return liberrors.ErrServerUnexpectedFrame{}
@@ -107,7 +109,7 @@ func (cr *serverConnReader) readFuncTCP() error {
 
 	for {
 		if cr.sc.session.state == ServerSessionStateRecord {
-			cr.sc.nconn.SetReadDeadline(time.Now().Add(cr.sc.s.ReadTimeout))
+			cr.sc.nconn.SetReadDeadline(time.Now().Add(cr.sc.session.readTimeout()))
 		}
 
 		what, err := cr.sc.conn.Read()
@@ -127,7 +129,9 @@ func (cr *serverConnReader) readFuncTCP() error {
 			}
 
 		case *base.Response:
-			return liberrors.ErrServerUnexpectedResponse{}
+			if !cr.sc.consumeServerResponse(what) {
+				return liberrors.ErrServerUnexpectedResponse{}
+			}
 
 		case *base.InterleavedFrame:
 			if cb, ok := cr.sc.session.tcpCallbackByChannel[what.Channel]; ok {
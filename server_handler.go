@@ -1,8 +1,12 @@
 package gortsplib
 
 import (
+	"time"
+
 	"github.com/bluenviron/gortsplib/v4/pkg/base"
 	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/headers"
 )
 
 // ServerHandler is the interface implemented by all the server handlers.
@@ -67,12 +71,38 @@ type ServerHandlerOnResponse interface {
 	OnResponse(*ServerConn, *base.Response)
 }
 
+// ServerHandlerOnRequestDoneCtx is the context of OnRequestDone.
+type ServerHandlerOnRequestDoneCtx struct {
+	Conn     *ServerConn
+	Request  *base.Request
+	Response *base.Response
+	// time at which processing of the request started.
+	Start time.Time
+	// time elapsed between start of processing and write of the response.
+	Duration time.Duration
+	// error returned by the request handler or by the write of the response, if any.
+	Error error
+}
+
+// ServerHandlerOnRequestDone can be implemented by a ServerHandler.
+type ServerHandlerOnRequestDone interface {
+	// called after a response has been written to a connection.
+	// It can be used to build request logs and latency metrics.
+	OnRequestDone(*ServerHandlerOnRequestDoneCtx)
+}
+
 // ServerHandlerOnDescribeCtx is the context of OnDescribe.
 type ServerHandlerOnDescribeCtx struct {
 	Conn    *ServerConn
 	Request *base.Request
 	Path    string
 	Query   string
+	// function that edits the stream description sent to this client only.
+	// It can be set by OnDescribe in order to hide medias (for instance the back channel)
+	// without creating an additional ServerStream.
+	// It receives a copy of the description of the stream, whose medias can be
+	// removed or edited. Formats are shared with the stream and must not be edited.
+	RewriteDescription func(*description.Session)
 }
 
 // ServerHandlerOnDescribe can be implemented by a ServerHandler.
@@ -97,6 +127,23 @@ type ServerHandlerOnAnnounce interface {
 	OnAnnounce(*ServerHandlerOnAnnounceCtx) (*base.Response, error)
 }
 
+// ServerHandlerOnAnnounceBodyCtx is the context of OnAnnounceBody.
+type ServerHandlerOnAnnounceBodyCtx struct {
+	Session     *ServerSession
+	Conn        *ServerConn
+	Request     *base.Request
+	Path        string
+	Query       string
+	ContentType string
+}
+
+// ServerHandlerOnAnnounceBody can be implemented by a ServerHandler.
+type ServerHandlerOnAnnounceBody interface {
+	// called when receiving an ANNOUNCE request whose body is not application/sdp.
+	// It must decode the body into a stream description, that is then passed to OnAnnounce.
+	OnAnnounceBody(*ServerHandlerOnAnnounceBodyCtx) (*description.Session, error)
+}
+
 // ServerHandlerOnSetupCtx is the context of OnSetup.
 type ServerHandlerOnSetupCtx struct {
 	Session   *ServerSession
@@ -105,6 +152,13 @@ type ServerHandlerOnSetupCtx struct {
 	Path      string
 	Query     string
 	Transport Transport
+
+	// transport header proposed by the client and selected by the server.
+	// It contains client ports (UDP), interleaved IDs (TCP)
+	// and destination (UDP-multicast), if provided by the client.
+	// The handler can deny the transport by returning StatusUnsupportedTransport,
+	// in order to make the client try another one.
+	TransportHeader *headers.Transport
 }
 
 // ServerHandlerOnSetup can be implemented by a ServerHandler.
@@ -192,6 +246,44 @@ type ServerHandlerOnSetParameter interface {
 	OnSetParameter(*ServerHandlerOnSetParameterCtx) (*base.Response, error)
 }
 
+// ServerHandlerOnGetParametersCtx is the context of OnGetParameters.
+type ServerHandlerOnGetParametersCtx struct {
+	Session    *ServerSession
+	Conn       *ServerConn
+	Request    *base.Request
+	Path       string
+	Query      string
+	Parameters []Parameter
+}
+
+// ServerHandlerOnGetParameters can be implemented by a ServerHandler.
+// It takes precedence over ServerHandlerOnGetParameter,
+// except for requests whose Content-Type is not text/parameters.
+type ServerHandlerOnGetParameters interface {
+	// called when receiving a GET_PARAMETER request that contains parameter names.
+	// Returned parameters are written into the response body.
+	// GET_PARAMETER requests without parameters are keepalives and are not passed to this callback.
+	OnGetParameters(*ServerHandlerOnGetParametersCtx) (*base.Response, []Parameter, error)
+}
+
+// ServerHandlerOnSetParametersCtx is the context of OnSetParameters.
+type ServerHandlerOnSetParametersCtx struct {
+	Session    *ServerSession
+	Conn       *ServerConn
+	Request    *base.Request
+	Path       string
+	Query      string
+	Parameters []Parameter
+}
+
+// ServerHandlerOnSetParameters can be implemented by a ServerHandler.
+// It takes precedence over ServerHandlerOnSetParameter,
+// except for requests whose Content-Type is not text/parameters.
+type ServerHandlerOnSetParameters interface {
+	// called when receiving a SET_PARAMETER request.
+	OnSetParameters(*ServerHandlerOnSetParametersCtx) (*base.Response, error)
+}
+
 // ServerHandlerOnPacketLostCtx is the context of OnPacketLost.
 type ServerHandlerOnPacketLostCtx struct {
 	Session *ServerSession
@@ -216,6 +308,27 @@ type ServerHandlerOnDecodeError interface {
 	OnDecodeError(*ServerHandlerOnDecodeErrorCtx)
 }
 
+// ServerHandlerOnReaderCongestionCtx is the context of OnReaderCongestion.
+type ServerHandlerOnReaderCongestionCtx struct {
+	Session *ServerSession
+	Media   *description.Media
+	// format of the dropped RTP packet, or nil if the dropped packet is a RTCP packet.
+	Format format.Format
+	// size of the write queue.
+	QueueSize int
+	// number of RTP packets of the format, or of RTCP packets of the media,
+	// that have been dropped so far.
+	PacketsDropped uint64
+}
+
+// ServerHandlerOnReaderCongestion can be implemented by a ServerHandler.
+type ServerHandlerOnReaderCongestion interface {
+	// called when the write queue of a reader is full and a packet is dropped.
+	// It is called by the routine that is writing to the ServerStream,
+	// therefore it must not block.
+	OnReaderCongestion(*ServerHandlerOnReaderCongestionCtx)
+}
+
 // ServerHandlerOnStreamWriteErrorCtx is the context of OnStreamWriteError.
 type ServerHandlerOnStreamWriteErrorCtx struct {
 	Session *ServerSession
@@ -227,3 +340,29 @@ type ServerHandlerOnStreamWriteError interface {
 	// called when a ServerStream is unable to write packets to a session.
 	OnStreamWriteError(*ServerHandlerOnStreamWriteErrorCtx)
 }
+
+// ServerHandlerOnSessionStatsCtx is the context of OnSessionStats.
+type ServerHandlerOnSessionStatsCtx struct {
+	Session *ServerSession
+	Stats   *StatsSession
+}
+
+// ServerHandlerOnSessionStats can be implemented by a ServerHandler.
+type ServerHandlerOnSessionStats interface {
+	// called periodically, every Server.ObservabilityOptions.StatsPeriod, with session statistics,
+	// in order to monitor rates without polling ServerSession.Stats().
+	OnSessionStats(*ServerHandlerOnSessionStatsCtx)
+}
+
+// ServerHandlerOnViolationCtx is the context of OnViolation.
+type ServerHandlerOnViolationCtx struct {
+	Conn      *ServerConn
+	Violation *Violation
+}
+
+// ServerHandlerOnViolation can be implemented by a ServerHandler.
+type ServerHandlerOnViolation interface {
+	// called when StrictOptions.Enabled is true and a client violates RFC 2326.
+	// If not implemented, violations are written to the logger.
+	OnViolation(*ServerHandlerOnViolationCtx)
+}
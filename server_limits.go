@@ -0,0 +1,223 @@
+package gortsplib
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/auth"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+)
+
+// ServerLimits contains the settings of a Server that can be changed while the server is running.
+type ServerLimits struct {
+	// timeout of read operations.
+	// It defaults to 10 seconds.
+	ReadTimeout time.Duration
+	// timeout of write operations.
+	// It defaults to 10 seconds.
+	WriteTimeout time.Duration
+	// Size of the queue of outgoing packets.
+	// It defaults to 256.
+	WriteQueueSize int
+	// maximum number of simultaneous connections.
+	// Connections above the limit are closed as soon as they are accepted,
+	// after writing a 503 response.
+	// It defaults to 0 (unlimited).
+	MaxConnections int
+	// maximum number of simultaneous connections from the same IP.
+	// Connections above the limit are closed as soon as they are accepted,
+	// after writing a 503 response.
+	// It defaults to 0 (unlimited).
+	MaxConnectionsPerIP int
+	// maximum number of simultaneous sessions.
+	// It defaults to 0 (unlimited).
+	MaxSessions int
+	// maximum number of simultaneous sessions created from the same IP.
+	// It defaults to 0 (unlimited).
+	MaxSessionsPerIP int
+	// maximum number of requests per second that can be sent by a connection.
+	// When exceeded, the connection is closed.
+	// It defaults to 0 (unlimited).
+	MaxRequestsPerSecond int
+	// maximum number of requests of a connection that can receive an error response
+	// (status code 400 or greater), including the ones of failed authentication attempts.
+	// When exceeded, the connection is closed.
+	// It defaults to 0 (unlimited).
+	MaxBadRequests int
+	// maximum bitrate, in bits per second, of packets sent to each reader
+	// that is using the TCP transport.
+	// It defaults to 0 (unlimited).
+	MaxReaderBitrate int
+	// maximum number of bytes that can be sent to a reader in a single burst
+	// when MaxReaderBitrate is set.
+	// It defaults to the amount of bytes sent in 100 milliseconds at MaxReaderBitrate.
+	MaxReaderBurst int
+	// methods of requests that require authentication when OnAuthLookup is set.
+	// It defaults to DESCRIBE, ANNOUNCE and SETUP.
+	AuthRequiredMethods []base.Method
+	// authentication methods offered to clients when OnAuthLookup is set.
+	// Basic authentication sends credentials in plain text and must be enabled explicitly.
+	// It defaults to digest MD5 and digest SHA-256.
+	AuthValidateMethods []auth.ValidateMethod
+	// realm of authentication challenges.
+	// It defaults to "IPCAM".
+	AuthRealm string
+	// called to retrieve the password of a user that is authenticating.
+	// When nil, authentication is disabled.
+	OnAuthLookup func(*ServerAuthLookupCtx) (string, bool)
+}
+
+func (l *ServerLimits) validate() error {
+	if l.ReadTimeout < 0 {
+		return fmt.Errorf("ReadTimeout must not be negative")
+	}
+
+	if l.WriteTimeout < 0 {
+		return fmt.Errorf("WriteTimeout must not be negative")
+	}
+
+	if l.WriteQueueSize < 0 || (l.WriteQueueSize&(l.WriteQueueSize-1)) != 0 {
+		return fmt.Errorf("WriteQueueSize must be a power of two")
+	}
+
+	if l.MaxConnections < 0 {
+		return fmt.Errorf("MaxConnections must not be negative")
+	}
+
+	if l.MaxConnectionsPerIP < 0 {
+		return fmt.Errorf("MaxConnectionsPerIP must not be negative")
+	}
+
+	if l.MaxSessions < 0 {
+		return fmt.Errorf("MaxSessions must not be negative")
+	}
+
+	if l.MaxSessionsPerIP < 0 {
+		return fmt.Errorf("MaxSessionsPerIP must not be negative")
+	}
+
+	if l.MaxRequestsPerSecond < 0 {
+		return fmt.Errorf("MaxRequestsPerSecond must not be negative")
+	}
+
+	if l.MaxBadRequests < 0 {
+		return fmt.Errorf("MaxBadRequests must not be negative")
+	}
+
+	if l.MaxReaderBitrate < 0 {
+		return fmt.Errorf("MaxReaderBitrate must not be negative")
+	}
+
+	if l.MaxReaderBurst < 0 {
+		return fmt.Errorf("MaxReaderBurst must not be negative")
+	}
+
+	return nil
+}
+
+func (l *ServerLimits) fillDefaults() {
+	if l.ReadTimeout == 0 {
+		l.ReadTimeout = 10 * time.Second
+	}
+	if l.WriteTimeout == 0 {
+		l.WriteTimeout = 10 * time.Second
+	}
+	if l.WriteQueueSize == 0 {
+		l.WriteQueueSize = 256
+	}
+	if l.AuthRequiredMethods == nil {
+		l.AuthRequiredMethods = defaultAuthRequiredMethods
+	}
+	if l.AuthValidateMethods == nil {
+		l.AuthValidateMethods = defaultAuthValidateMethods
+	}
+	if l.AuthRealm == "" {
+		l.AuthRealm = "IPCAM"
+	}
+}
+
+func (s *Server) initialLimits(timeoutOptions TimeoutOptions) ServerLimits {
+	return ServerLimits{
+		ReadTimeout:          timeoutOptions.Read,
+		WriteTimeout:         timeoutOptions.Write,
+		WriteQueueSize:       s.WriteQueueSize,
+		MaxConnections:       s.LimitOptions.MaxConnections,
+		MaxConnectionsPerIP:  s.LimitOptions.MaxConnectionsPerIP,
+		MaxSessions:          s.LimitOptions.MaxSessions,
+		MaxSessionsPerIP:     s.LimitOptions.MaxSessionsPerIP,
+		MaxRequestsPerSecond: s.LimitOptions.MaxRequestsPerSecond,
+		MaxBadRequests:       s.LimitOptions.MaxBadRequests,
+		MaxReaderBitrate:     s.LimitOptions.MaxReaderBitrate,
+		MaxReaderBurst:       s.LimitOptions.MaxReaderBurst,
+		AuthRequiredMethods:  s.AuthOptions.RequiredMethods,
+		AuthValidateMethods:  s.AuthOptions.ValidateMethods,
+		AuthRealm:            s.AuthOptions.Realm,
+		OnAuthLookup:         s.OnAuthLookup,
+	}
+}
+
+func decreaseIPCount(counts map[string]int, ip string) {
+	counts[ip]--
+	if counts[ip] <= 0 {
+		delete(counts, ip)
+	}
+}
+
+func (s *Server) connLimitReached(ip string) bool {
+	l := s.limits.Load()
+	return (l.MaxConnections != 0 && s.acceptedConns >= l.MaxConnections) ||
+		(l.MaxConnectionsPerIP != 0 && s.connsPerIP[ip] >= l.MaxConnectionsPerIP)
+}
+
+// rejectConn closes a connection that exceeds limits, without reading from it.
+// When TLS is not in use, a response is written before closing,
+// in order to let the client know the reason.
+func (s *Server) rejectConn(nconn net.Conn) {
+	if s.SecurityOptions.TLSConfig == nil {
+		buf, _ := base.Response{
+			StatusCode: base.StatusServiceUnavailable,
+		}.Marshal()
+		nconn.SetWriteDeadline(time.Now().Add(s.limits.Load().WriteTimeout))
+		nconn.Write(buf) //nolint:errcheck
+	}
+
+	nconn.Close()
+}
+
+func (s *Server) sessionLimitReached(ip string) bool {
+	l := s.limits.Load()
+	return (l.MaxSessions != 0 && len(s.sessions) >= l.MaxSessions) ||
+		(l.MaxSessionsPerIP != 0 && s.sessionsPerIP[ip] >= l.MaxSessionsPerIP)
+}
+
+// Limits returns the limits currently in use by the server.
+func (s *Server) Limits() ServerLimits {
+	return *s.limits.Load()
+}
+
+// SetLimits changes the limits of a running server.
+// Zero values are replaced by defaults.
+// Timeouts are applied to new and existing connections,
+// except the ones of UDP listeners, that are fixed when the server starts.
+// WriteQueueSize, MaxReaderBitrate and MaxReaderBurst are applied to sessions
+// and streams that start writing afterwards.
+// Connection and session caps are applied to new connections and sessions;
+// existing ones are kept open.
+// Request limits and authentication settings are applied to new requests
+// of new and existing connections.
+func (s *Server) SetLimits(l ServerLimits) error {
+	if s.limits.Load() == nil {
+		return fmt.Errorf("server is not running")
+	}
+
+	err := l.validate()
+	if err != nil {
+		return err
+	}
+
+	l.fillDefaults()
+	s.limits.Store(&l)
+
+	return nil
+}
@@ -2,18 +2,22 @@ package gortsplib
 
 import (
 	"net"
+	"sync"
 
 	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
 )
 
 type serverMulticastWriter struct {
-	s *Server
+	s              *Server
+	writeQueueSize int
 
 	rtpl     *serverUDPListener
 	rtcpl    *serverUDPListener
 	writer   *asyncProcessor
 	rtpAddr  *net.UDPAddr
 	rtcpAddr *net.UDPAddr
+
+	rtpPending [][]byte
 }
 
 func (h *serverMulticastWriter) initialize() error {
@@ -24,10 +28,15 @@ func (h *serverMulticastWriter) initialize() error {
 
 	rtpl, rtcpl, err := createUDPListenerMulticastPair(
 		h.s.ListenPacket,
-		h.s.WriteTimeout,
-		h.s.MulticastRTPPort,
-		h.s.MulticastRTCPPort,
+		h.s.limits.Load().WriteTimeout,
+		h.s.TransportOptions.MulticastRTPPort,
+		h.s.TransportOptions.MulticastRTCPPort,
 		ip,
+		h.s.TransportOptions.MulticastGSO,
+		h.s.SocketOptions.UDPReadBufferSize,
+		h.s.SocketOptions.UDPWriteBufferSize,
+		h.s.SocketOptions.UDPMaxPayloadSize,
+		h.s.SocketOptions.DSCP,
 	)
 	if err != nil {
 		return err
@@ -49,7 +58,12 @@ func (h *serverMulticastWriter) initialize() error {
 	h.rtcpAddr = rtcpAddr
 
 	h.writer = &asyncProcessor{
-		bufferSize: h.s.WriteQueueSize,
+		bufferSize: func() int {
+			if h.writeQueueSize != 0 {
+				return h.writeQueueSize
+			}
+			return h.s.limits.Load().WriteQueueSize
+		}(),
 	}
 	h.writer.initialize()
 	h.writer.start()
@@ -67,22 +81,89 @@ func (h *serverMulticastWriter) ip() net.IP {
 	return h.rtpl.ip()
 }
 
-func (h *serverMulticastWriter) writePacketRTP(byts []byte) error {
-	ok := h.writer.push(func() error {
-		return h.rtpl.write(byts, h.rtpAddr)
-	})
+// serverMulticastPacket is a RTP packet in the write queue of a multicast writer.
+// It is taken from a pool, in order to avoid allocating a closure for each packet.
+type serverMulticastPacket struct {
+	h      *serverMulticastWriter
+	byts   []byte
+	last   bool
+	shared *sharedPacketBuffers
+}
+
+var serverMulticastPacketPool = sync.Pool{
+	New: func() interface{} {
+		return &serverMulticastPacket{}
+	},
+}
+
+func (p *serverMulticastPacket) put() {
+	*p = serverMulticastPacket{}
+	serverMulticastPacketPool.Put(p)
+}
+
+func (p *serverMulticastPacket) run() error {
+	h, byts, last, shared := p.h, p.byts, p.last, p.shared
+	p.put()
+
+	h.rtpPending = append(h.rtpPending, byts)
+	if !last {
+		return nil
+	}
+
+	defer shared.release()
+	return h.flushRTP()
+}
+
+func (h *serverMulticastWriter) writePacketsRTP(byts [][]byte, shared *sharedPacketBuffers) error {
+	// access units usually fit into this array, that is allocated on the stack.
+	var entriesBuf [16]interface{}
+	entries := entriesBuf[:0]
+
+	for i, b := range byts {
+		p := serverMulticastPacketPool.Get().(*serverMulticastPacket)
+		p.h = h
+		p.byts = b
+		p.last = (i == len(byts)-1)
+		p.shared = shared
+		entries = append(entries, p)
+	}
+
+	shared.retain()
+
+	ok := h.writer.pushMultiple(entries)
 	if !ok {
+		shared.release()
+
+		for _, entry := range entries {
+			entry.(*serverMulticastPacket).put()
+		}
+
+		h.s.ObservabilityOptions.Metrics.AddPacketsDropped(uint64(len(entries)))
 		return liberrors.ErrServerWriteQueueFull{}
 	}
 
 	return nil
 }
 
+// flushRTP writes pending packets of an access unit together.
+func (h *serverMulticastWriter) flushRTP() error {
+	pending := h.rtpPending
+	defer func() {
+		for i := range pending {
+			pending[i] = nil
+		}
+		h.rtpPending = pending[:0]
+	}()
+
+	return h.rtpl.writeMultiple(pending, h.rtpAddr)
+}
+
 func (h *serverMulticastWriter) writePacketRTCP(byts []byte) error {
 	ok := h.writer.push(func() error {
 		return h.rtcpl.write(byts, h.rtcpAddr)
 	})
 	if !ok {
+		h.s.ObservabilityOptions.Metrics.AddPacketsDropped(1)
 		return liberrors.ErrServerWriteQueueFull{}
 	}
 
@@ -0,0 +1,353 @@
+package gortsplib
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/auth"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+)
+
+// ServerTransportOptions groups transport settings of a Server.
+type ServerTransportOptions struct {
+	// a port to send and receive RTP packets with the UDP transport.
+	// If UDPRTPAddress and UDPRTCPAddress are filled, the server can support the UDP transport.
+	UDPRTPAddress string
+	// a port to send and receive RTCP packets with the UDP transport.
+	// If UDPRTPAddress and UDPRTCPAddress are filled, the server can support the UDP transport.
+	UDPRTCPAddress string
+	// a range of multicast IPs to use with the UDP-multicast transport.
+	// If MulticastIPRange, MulticastRTPPort, MulticastRTCPPort are filled, the server
+	// can support the UDP-multicast transport.
+	MulticastIPRange string
+	// a port to send RTP packets with the UDP-multicast transport.
+	// If MulticastIPRange, MulticastRTPPort, MulticastRTCPPort are filled, the server
+	// can support the UDP-multicast transport.
+	MulticastRTPPort int
+	// a port to send RTCP packets with the UDP-multicast transport.
+	// If MulticastIPRange, MulticastRTPPort, MulticastRTCPPort are filled, the server
+	// can support the UDP-multicast transport.
+	MulticastRTCPPort int
+	// use UDP Generic Segmentation Offload to write packets with the UDP-multicast transport,
+	// in order to write multiple packets of the same size with a single syscall.
+	// It is supported on Linux only (kernel 4.18 or newer) and is disabled
+	// automatically when the kernel or the network interface don't support it.
+	MulticastGSO bool
+}
+
+// ServerLimitOptions groups limits of a Server.
+// They can be changed while the server is running with Server.SetLimits().
+type ServerLimitOptions struct {
+	// maximum number of simultaneous connections.
+	// Connections above the limit are closed as soon as they are accepted,
+	// after writing a 503 response.
+	// It defaults to 0 (unlimited).
+	MaxConnections int
+	// maximum number of simultaneous connections from the same IP.
+	// Connections above the limit are closed as soon as they are accepted,
+	// after writing a 503 response.
+	// It defaults to 0 (unlimited).
+	MaxConnectionsPerIP int
+	// maximum number of simultaneous sessions.
+	// It defaults to 0 (unlimited).
+	MaxSessions int
+	// maximum number of simultaneous sessions created from the same IP.
+	// It defaults to 0 (unlimited).
+	MaxSessionsPerIP int
+	// maximum number of requests per second that can be sent by a connection.
+	// When exceeded, the connection is closed.
+	// It defaults to 0 (unlimited).
+	MaxRequestsPerSecond int
+	// maximum number of requests of a connection that can receive an error response
+	// (status code 400 or greater), including the ones of failed authentication attempts.
+	// When exceeded, the connection is closed.
+	// It defaults to 0 (unlimited).
+	MaxBadRequests int
+	// maximum bitrate, in bits per second, of packets sent to each reader
+	// that is using the TCP transport.
+	// It defaults to 0 (unlimited).
+	MaxReaderBitrate int
+	// maximum number of bytes that can be sent to a reader in a single burst
+	// when MaxReaderBitrate is set.
+	// It defaults to the amount of bytes sent in 100 milliseconds at MaxReaderBitrate.
+	MaxReaderBurst int
+}
+
+// ServerAuthOptions groups authentication settings of a Server,
+// that are used when Server.OnAuthLookup is set.
+type ServerAuthOptions struct {
+	// methods of requests that require authentication.
+	// It defaults to DESCRIBE, ANNOUNCE and SETUP.
+	RequiredMethods []base.Method
+	// authentication methods offered to clients.
+	// Basic authentication sends credentials in plain text and must be enabled explicitly.
+	// It defaults to digest MD5 and digest SHA-256.
+	ValidateMethods []auth.ValidateMethod
+	// realm of authentication challenges.
+	// It defaults to "IPCAM".
+	Realm string
+}
+
+// ServerSessionOptions groups session settings of a Server.
+type ServerSessionOptions struct {
+	// timeout of sessions that are reading.
+	// It is advertised to clients, that must send keepalives within this interval.
+	// It can be overridden per session with ServerSession.SetTimeout().
+	// It defaults to 60 seconds.
+	Timeout time.Duration
+	// events that keep alive sessions that are reading.
+	// It defaults to ServerSessionActivityRequests | ServerSessionActivityPackets.
+	Activity ServerSessionActivity
+	// policy used to drop packets when the write queue of a reader is full.
+	// It defaults to ServerCongestionPolicyDropPackets.
+	CongestionPolicy ServerCongestionPolicy
+	// in sessions that are publishing, check H264 and H265 access units
+	// for missing packets and incomplete fragmented NALUs.
+	// Groups of pictures that contain corrupted access units are reported with
+	// ServerHandlerOnDecodeError, and access units are counted in format statistics.
+	// It defaults to false.
+	DetectCorruption bool
+}
+
+// ServerOption is a functional option of NewServer.
+type ServerOption func(*Server)
+
+// WithServerTransport sets transport settings.
+func WithServerTransport(o ServerTransportOptions) ServerOption {
+	return func(s *Server) {
+		s.TransportOptions = o
+	}
+}
+
+// WithServerTimeouts sets timeout settings.
+func WithServerTimeouts(o TimeoutOptions) ServerOption {
+	return func(s *Server) {
+		s.TimeoutOptions = o
+	}
+}
+
+// WithServerSecurity sets security settings.
+func WithServerSecurity(o SecurityOptions) ServerOption {
+	return func(s *Server) {
+		s.SecurityOptions = o
+	}
+}
+
+// WithServerSocket sets socket settings.
+func WithServerSocket(o SocketOptions) ServerOption {
+	return func(s *Server) {
+		s.SocketOptions = o
+	}
+}
+
+// WithServerStrict sets strict mode settings.
+func WithServerStrict(o StrictOptions) ServerOption {
+	return func(s *Server) {
+		s.StrictOptions = o
+	}
+}
+
+// WithServerLimits sets limits.
+func WithServerLimits(o ServerLimitOptions) ServerOption {
+	return func(s *Server) {
+		s.LimitOptions = o
+	}
+}
+
+// WithServerAuth sets authentication settings.
+func WithServerAuth(o ServerAuthOptions) ServerOption {
+	return func(s *Server) {
+		s.AuthOptions = o
+	}
+}
+
+// WithServerSession sets session settings.
+func WithServerSession(o ServerSessionOptions) ServerOption {
+	return func(s *Server) {
+		s.SessionOptions = o
+	}
+}
+
+// WithServerMediaActivity sets media activity settings.
+func WithServerMediaActivity(o MediaActivityOptions) ServerOption {
+	return func(s *Server) {
+		s.MediaActivityOptions = o
+	}
+}
+
+// WithServerObservability sets logging, metrics and statistics settings.
+func WithServerObservability(o ObservabilityOptions) ServerOption {
+	return func(s *Server) {
+		s.ObservabilityOptions = o
+	}
+}
+
+// WithServerMemory sets memory management settings.
+func WithServerMemory(o MemoryOptions) ServerOption {
+	return func(s *Server) {
+		s.MemoryOptions = o
+	}
+}
+
+// NewServer allocates a Server.
+func NewServer(rtspAddress string, handler ServerHandler, opts ...ServerOption) *Server {
+	s := &Server{
+		RTSPAddress: rtspAddress,
+		Handler:     handler,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// mergeDeprecatedFields returns transport, timeout and security settings,
+// filled with values of deprecated fields when the corresponding group field is not set.
+// The server is left untouched.
+func (s *Server) mergeDeprecatedFields() (ServerTransportOptions, TimeoutOptions, SecurityOptions) {
+	transportOptions := s.TransportOptions
+	if transportOptions.UDPRTPAddress == "" {
+		transportOptions.UDPRTPAddress = s.UDPRTPAddress
+	}
+	if transportOptions.UDPRTCPAddress == "" {
+		transportOptions.UDPRTCPAddress = s.UDPRTCPAddress
+	}
+	if transportOptions.MulticastIPRange == "" {
+		transportOptions.MulticastIPRange = s.MulticastIPRange
+	}
+	if transportOptions.MulticastRTPPort == 0 {
+		transportOptions.MulticastRTPPort = s.MulticastRTPPort
+	}
+	if transportOptions.MulticastRTCPPort == 0 {
+		transportOptions.MulticastRTCPPort = s.MulticastRTCPPort
+	}
+
+	timeoutOptions := s.TimeoutOptions
+	if timeoutOptions.Read == 0 {
+		timeoutOptions.Read = s.ReadTimeout
+	}
+	if timeoutOptions.Write == 0 {
+		timeoutOptions.Write = s.WriteTimeout
+	}
+
+	securityOptions := s.SecurityOptions
+	if securityOptions.TLSConfig == nil {
+		securityOptions.TLSConfig = s.TLSConfig
+	}
+
+	return transportOptions, timeoutOptions, securityOptions
+}
+
+// Validate checks the configuration of the server.
+// Deprecated fields are checked together with their groups, without being moved.
+// It is called automatically by Start().
+func (s *Server) Validate() error {
+	transportOptions, timeoutOptions, securityOptions := s.mergeDeprecatedFields()
+
+	if s.RTSPAddress == "" {
+		return fmt.Errorf("RTSPAddress not provided")
+	}
+
+	limits := s.initialLimits(timeoutOptions)
+	err := limits.validate()
+	if err != nil {
+		return err
+	}
+
+	if s.SessionOptions.Timeout < 0 || (s.SessionOptions.Timeout != 0 && s.SessionOptions.Timeout < time.Second) {
+		return fmt.Errorf("SessionTimeout must be at least one second")
+	}
+
+	if (s.SessionOptions.Activity &^ (ServerSessionActivityRequests | ServerSessionActivityPackets)) != 0 {
+		return fmt.Errorf("SessionActivity contains unknown events")
+	}
+
+	if s.SessionOptions.CongestionPolicy < ServerCongestionPolicyDropPackets ||
+		s.SessionOptions.CongestionPolicy > ServerCongestionPolicyDropUntilRandomAccess {
+		return fmt.Errorf("invalid CongestionPolicy")
+	}
+
+	if s.MediaActivityOptions.SparseReadTimeout < 0 {
+		return fmt.Errorf("SparseReadTimeout must not be negative")
+	}
+
+	if s.SocketOptions.UDPMaxPayloadSize < 0 || s.SocketOptions.UDPMaxPayloadSize > udpMaxReadPayloadSize {
+		return fmt.Errorf("UDPMaxPayloadSize must be less than %d", udpMaxReadPayloadSize)
+	}
+
+	if s.MaxPacketSize < 0 || s.MaxPacketSize > udpMaxPayloadSize {
+		return fmt.Errorf("MaxPacketSize must be less than %d", udpMaxPayloadSize)
+	}
+
+	if s.SocketOptions.DSCP < 0 || s.SocketOptions.DSCP > 63 {
+		return fmt.Errorf("DSCP must be between 0 and 63")
+	}
+
+	if securityOptions.TLSConfig != nil && securityOptions.UnencryptedMedia == nil &&
+		transportOptions.UDPRTPAddress != "" {
+		return fmt.Errorf("TLS can't be used with UDP: unset UDPRTPAddress and UDPRTCPAddress, " +
+			"or set UnencryptedMedia")
+	}
+
+	if securityOptions.TLSConfig != nil && transportOptions.MulticastIPRange != "" {
+		return fmt.Errorf("TLS can't be used with UDP-multicast: unset MulticastIPRange, " +
+			"MulticastRTPPort and MulticastRTCPPort")
+	}
+
+	if transportOptions.UDPRTPAddress != "" && transportOptions.UDPRTCPAddress == "" {
+		return fmt.Errorf("UDPRTPAddress requires UDPRTCPAddress")
+	}
+
+	if transportOptions.UDPRTPAddress == "" && transportOptions.UDPRTCPAddress != "" {
+		return fmt.Errorf("UDPRTCPAddress requires UDPRTPAddress")
+	}
+
+	if transportOptions.UDPRTPAddress != "" {
+		rtpPort, err := extractPort(transportOptions.UDPRTPAddress)
+		if err != nil {
+			return fmt.Errorf("invalid UDPRTPAddress: %w", err)
+		}
+
+		rtcpPort, err := extractPort(transportOptions.UDPRTCPAddress)
+		if err != nil {
+			return fmt.Errorf("invalid UDPRTCPAddress: %w", err)
+		}
+
+		if (rtpPort % 2) != 0 {
+			return fmt.Errorf("RTP port must be even")
+		}
+
+		if rtcpPort != (rtpPort + 1) {
+			return fmt.Errorf("RTP and RTCP ports must be consecutive")
+		}
+	}
+
+	if transportOptions.MulticastIPRange == "" &&
+		(transportOptions.MulticastRTPPort != 0 || transportOptions.MulticastRTCPPort != 0) {
+		return fmt.Errorf("MulticastRTPPort and MulticastRTCPPort require MulticastIPRange")
+	}
+
+	if transportOptions.MulticastIPRange != "" {
+		if transportOptions.MulticastRTPPort == 0 || transportOptions.MulticastRTCPPort == 0 {
+			return fmt.Errorf("MulticastIPRange requires MulticastRTPPort and MulticastRTCPPort")
+		}
+
+		if (transportOptions.MulticastRTPPort % 2) != 0 {
+			return fmt.Errorf("RTP port must be even")
+		}
+
+		if transportOptions.MulticastRTCPPort != (transportOptions.MulticastRTPPort + 1) {
+			return fmt.Errorf("RTP and RTCP ports must be consecutive")
+		}
+
+		_, _, err := net.ParseCIDR(transportOptions.MulticastIPRange)
+		if err != nil {
+			return fmt.Errorf("invalid MulticastIPRange: %w", err)
+		}
+	}
+
+	return nil
+}
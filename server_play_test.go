@@ -21,6 +21,7 @@ import (
 	"github.com/bluenviron/gortsplib/v4/pkg/description"
 	"github.com/bluenviron/gortsplib/v4/pkg/format"
 	"github.com/bluenviron/gortsplib/v4/pkg/headers"
+	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
 	"github.com/bluenviron/gortsplib/v4/pkg/sdp"
 )
 
@@ -585,6 +586,82 @@ func TestServerPlaySetupErrorSameUDPPortsAndIP(t *testing.T) {
 	<-errorRecv
 }
 
+func TestServerPlaySetupDenyTransport(t *testing.T) {
+	var stream *ServerStream
+	var transports []Transport
+	var clientPorts *[2]int
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				transports = append(transports, ctx.Transport)
+
+				if ctx.Transport == TransportUDPMulticast {
+					return &base.Response{
+						StatusCode: base.StatusUnsupportedTransport,
+					}, nil, nil
+				}
+
+				clientPorts = ctx.TransportHeader.ClientPorts
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		UDPRTPAddress:     "127.0.0.1:8000",
+		UDPRTCPAddress:    "127.0.0.1:8001",
+		MulticastIPRange:  "224.1.0.0/16",
+		MulticastRTPPort:  8002,
+		MulticastRTCPPort: 8003,
+		RTSPAddress:       "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Setup,
+		URL:    mediaURL(t, desc.BaseURL, desc.Medias[0]),
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"2"},
+			"Transport": headers.Transport{
+				Delivery: deliveryPtr(headers.TransportDeliveryMulticast),
+				Mode:     transportModePtr(headers.TransportModePlay),
+				Protocol: headers.TransportProtocolUDP,
+			}.Marshal(),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusUnsupportedTransport, res.StatusCode)
+
+	doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), &headers.Transport{
+		Delivery:    deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:        transportModePtr(headers.TransportModePlay),
+		Protocol:    headers.TransportProtocolUDP,
+		ClientPorts: &[2]int{35466, 35467},
+	}, "")
+
+	require.Equal(t, []Transport{TransportUDPMulticast, TransportUDP}, transports)
+	require.Equal(t, &[2]int{35466, 35467}, clientPorts)
+}
+
 func TestServerPlay(t *testing.T) {
 	for _, transport := range []string{
 		"udp",
@@ -1445,6 +1522,173 @@ func TestServerPlayVLCMulticast(t *testing.T) {
 	require.Equal(t, "224.1.0.0", desc.ConnectionInformation.Address.Address)
 }
 
+func TestServerPlayRewriteDescription(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				if ctx.Query == "nobackchannel" {
+					ctx.RewriteDescription = func(desc *description.Session) {
+						desc.Medias = desc.Medias[:1]
+					}
+				}
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{
+		testH264Media,
+		{
+			Type:          description.MediaTypeAudio,
+			IsBackChannel: true,
+			Formats: []format.Format{&format.G711{
+				PayloadTyp:   8,
+				MULaw:        false,
+				SampleRate:   8000,
+				ChannelCount: 1,
+			}},
+		},
+	}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	conn := conn.NewConn(nconn)
+	defer nconn.Close()
+
+	for i, ca := range []struct {
+		query  string
+		medias int
+	}{
+		{"", 2},
+		{"nobackchannel", 1},
+	} {
+		res, err := writeReqReadRes(conn, base.Request{
+			Method: base.Describe,
+			URL:    mustParseURL("rtsp://localhost:8554/teststream?" + ca.query),
+			Header: base.Header{
+				"CSeq": base.HeaderValue{strconv.Itoa(i + 1)},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, base.StatusOK, res.StatusCode)
+
+		var desc sdp.SessionDescription
+		err = desc.Unmarshal(res.Body)
+		require.NoError(t, err)
+		require.Len(t, desc.MediaDescriptions, ca.medias)
+	}
+
+	// the description of the stream is not affected
+	require.Len(t, stream.Description().Medias, 2)
+}
+
+func TestServerPlayCompletePlayback(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"tcp",
+	} {
+		t.Run(transport, func(t *testing.T) {
+			var stream *ServerStream
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			if transport == "udp" {
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{
+				testH264Media,
+				{
+					Type:    description.MediaTypeAudio,
+					Formats: []format.Format{&format.G711{PayloadTyp: 8, SampleRate: 8000, ChannelCount: 1}},
+				},
+			}})
+			defer stream.Close()
+
+			err = stream.SetRTPMode(ServerStreamRTPModeNormalize)
+			require.NoError(t, err)
+
+			stream.SetDuration(10 * time.Second)
+
+			endOfStream := make(chan error, 1)
+
+			c := Client{
+				OnEndOfStream: func(err error) {
+					endOfStream <- err
+				},
+				Transport: func() *Transport {
+					if transport == "udp" {
+						return transportPtr(TransportUDP)
+					}
+					return transportPtr(TransportTCP)
+				}(),
+			}
+
+			u, err := base.ParseURL("rtsp://localhost:8554/teststream")
+			require.NoError(t, err)
+
+			err = c.Start(u.Scheme, u.Host)
+			require.NoError(t, err)
+			defer c.Close()
+
+			desc, _, err := c.Describe(u)
+			require.NoError(t, err)
+
+			err = c.SetupAll(desc.BaseURL, desc.Medias)
+			require.NoError(t, err)
+
+			res, err := c.Play(&headers.Range{
+				Value: &headers.RangeNPT{
+					Start: 2 * time.Second,
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, base.HeaderValue{"npt=2-10"}, res.Header["Range"])
+
+			err = stream.CompletePlayback()
+			require.NoError(t, err)
+
+			err = <-endOfStream
+			require.Equal(t, liberrors.ErrClientEndOfStream{}, err)
+		})
+	}
+}
+
 func TestServerPlayTCPResponseBeforeFrames(t *testing.T) {
 	var stream *ServerStream
 	writerDone := make(chan struct{})
@@ -1528,18 +1772,20 @@ func TestServerPlayTCPResponseBeforeFrames(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestServerPlayPause(t *testing.T) {
+func TestServerPlayTCPPacing(t *testing.T) {
 	var stream *ServerStream
-	writerStarted := false
-	writerDone := make(chan struct{})
-	writerTerminate := make(chan struct{})
+
+	buf, err := testRTPPacket.Marshal()
+	require.NoError(t, err)
 
 	s := &Server{
+		RTSPAddress: "localhost:8554",
+		// a packet every 20ms
+		LimitOptions: ServerLimitOptions{
+			MaxReaderBitrate: len(buf) * 8 * 50,
+			MaxReaderBurst:   len(buf),
+		},
 		Handler: &testServerHandler{
-			onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
-				close(writerTerminate)
-				<-writerDone
-			},
 			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
 				return &base.Response{
 					StatusCode: base.StatusOK,
@@ -1551,37 +1797,93 @@ func TestServerPlayPause(t *testing.T) {
 				}, stream, nil
 			},
 			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
-				if !writerStarted {
-					writerStarted = true
-					go func() {
-						defer close(writerDone)
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+	}
 
-						ti := time.NewTicker(50 * time.Millisecond)
-						defer ti.Stop()
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
 
-						for {
-							select {
-							case <-ti.C:
-								err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
-								require.NoError(t, err)
-							case <-writerTerminate:
-								return
-							}
-						}
-					}()
-				}
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+	start := time.Now()
+
+	for i := 0; i < 6; i++ {
+		err = stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < 6; {
+		var f *base.InterleavedFrame
+		f, err = conn.ReadInterleavedFrame()
+		require.NoError(t, err)
+
+		if f.Channel == 0 {
+			require.Equal(t, buf, f.Payload)
+			i++
+		}
+	}
 
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestServerPlayWriteQueueSize(t *testing.T) {
+	var stream *ServerStream
+	var session *ServerSession
+
+	s := &Server{
+		RTSPAddress: "localhost:8554",
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
 				return &base.Response{
 					StatusCode: base.StatusOK,
-				}, nil
+				}, stream, nil
 			},
-			onPause: func(_ *ServerHandlerOnPauseCtx) (*base.Response, error) {
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				err := ctx.Session.SetWriteQueueSize(3)
+				require.EqualError(t, err, "write queue size must be a power of two")
+
+				err = ctx.Session.SetWriteQueueSize(32)
+				require.NoError(t, err)
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				require.Equal(t, 32, ctx.Session.WriteQueueSize())
+				session = ctx.Session
+
 				return &base.Response{
 					StatusCode: base.StatusOK,
 				}, nil
 			},
 		},
-		RTSPAddress: "localhost:8554",
 	}
 
 	err := s.Start()
@@ -1591,6 +1893,11 @@ func TestServerPlayPause(t *testing.T) {
 	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
 	defer stream.Close()
 
+	require.Equal(t, 0, stream.WriteQueueSize())
+	err = stream.SetWriteQueueSize(64)
+	require.NoError(t, err)
+	require.Equal(t, 64, stream.WriteQueueSize())
+
 	nconn, err := net.Dial("tcp", "localhost:8554")
 	require.NoError(t, err)
 	defer nconn.Close()
@@ -1607,290 +1914,1181 @@ func TestServerPlayPause(t *testing.T) {
 
 	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
 
-	session := readSession(t, res)
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", readSession(t, res))
 
-	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-	doPause(t, conn, "rtsp://localhost:8554/teststream", session)
+	session.writerMutex.RLock()
+	bufferSize := session.writer.bufferSize
+	session.writerMutex.RUnlock()
+	require.Equal(t, 32, bufferSize)
 }
 
-func TestServerPlayPlayPausePausePlay(t *testing.T) {
-	for _, ca := range []string{"stream", "direct"} {
-		t.Run(ca, func(t *testing.T) {
-			var stream *ServerStream
-			writerStarted := false
-			writerDone := make(chan struct{})
-			writerTerminate := make(chan struct{})
+func TestServerPlayWritePacketsRTP(t *testing.T) {
+	var stream *ServerStream
+	writeError := make(chan error, 1)
 
-			s := &Server{
-				Handler: &testServerHandler{
-					onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
-						close(writerTerminate)
-						<-writerDone
-					},
-					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, stream, nil
-					},
-					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, stream, nil
-					},
-					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
-						if !writerStarted {
-							writerStarted = true
-							go func() {
-								defer close(writerDone)
+	s := &Server{
+		RTSPAddress: "localhost:8554",
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onStreamWriteError: func(ctx *ServerHandlerOnStreamWriteErrorCtx) {
+				writeError <- ctx.Error
+			},
+		},
+	}
 
-								ti := time.NewTicker(50 * time.Millisecond)
-								defer ti.Stop()
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
 
-								for {
-									select {
-									case <-ti.C:
-										if ca == "stream" {
-											err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
-											require.NoError(t, err)
-										} else {
-											err := ctx.Session.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
-											require.NoError(t, err)
-										}
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
 
-									case <-writerTerminate:
-										return
-									}
-								}
-							}()
-						}
+	err = stream.SetWriteQueueSize(2)
+	require.NoError(t, err)
 
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, nil
-					},
-					onPause: func(_ *ServerHandlerOnPauseCtx) (*base.Response, error) {
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, nil
-					},
-				},
-				RTSPAddress: "localhost:8554",
-			}
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
 
-			err := s.Start()
-			require.NoError(t, err)
-			defer s.Close()
+	desc := doDescribe(t, conn)
 
-			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
-			defer stream.Close()
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
 
-			nconn, err := net.Dial("tcp", "localhost:8554")
-			require.NoError(t, err)
-			defer nconn.Close()
-			conn := conn.NewConn(nconn)
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
 
-			desc := doDescribe(t, conn)
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", readSession(t, res))
+
+	pkts := make([]*rtp.Packet, 3)
+	for i := range pkts {
+		pkts[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: uint16(i),
+				Marker:         i == 2,
+				CSRC:           []uint32{},
+			},
+			Payload: []byte{1, 2, 3, 4},
+		}
+	}
 
-			inTH := &headers.Transport{
-				Protocol:       headers.TransportProtocolTCP,
-				Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
-				Mode:           transportModePtr(headers.TransportModePlay),
-				InterleavedIDs: &[2]int{0, 1},
-			}
+	// access unit is bigger than the queue
+	err = stream.WritePacketsRTP(stream.Description().Medias[0], pkts)
+	require.NoError(t, err)
+	require.Equal(t, liberrors.ErrServerWriteQueueFull{}, <-writeError)
 
-			res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+	err = stream.WritePacketsRTP(stream.Description().Medias[0], pkts[1:])
+	require.NoError(t, err)
 
-			session := readSession(t, res)
+	for _, pkt := range pkts[1:] {
+		f, err := conn.ReadInterleavedFrame()
+		require.NoError(t, err)
+		require.Equal(t, 0, f.Channel)
 
-			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-			doPause(t, conn, "rtsp://localhost:8554/teststream", session)
-			doPause(t, conn, "rtsp://localhost:8554/teststream", session)
-			time.Sleep(500 * time.Millisecond)
-			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-		})
+		var dec rtp.Packet
+		err = dec.Unmarshal(f.Payload)
+		require.NoError(t, err)
+		require.Equal(t, pkt, &dec)
 	}
 }
 
-func TestServerPlayTimeout(t *testing.T) {
-	for _, transport := range []string{
-		"udp",
-		"multicast",
-		// there's no timeout when reading with TCP
-	} {
-		t.Run(transport, func(t *testing.T) {
-			var stream *ServerStream
-			sessionClosed := make(chan struct{})
+func TestServerPlayPacketFilter(t *testing.T) {
+	var stream *ServerStream
 
-			s := &Server{
-				Handler: &testServerHandler{
-					onSessionClose: func(_ *ServerHandlerOnSessionCloseCtx) {
-						close(sessionClosed)
-					},
-					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, stream, nil
-					},
-					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, stream, nil
-					},
-					onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, nil
-					},
-				},
-				ReadTimeout:       1 * time.Second,
-				sessionTimeout:    1 * time.Second,
-				RTSPAddress:       "localhost:8554",
-				checkStreamPeriod: 500 * time.Millisecond,
-			}
+	s := &Server{
+		RTSPAddress: "localhost:8554",
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+	}
 
-			switch transport {
-			case "udp":
-				s.UDPRTPAddress = "127.0.0.1:8000"
-				s.UDPRTCPAddress = "127.0.0.1:8001"
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
 
-			case "multicast":
-				s.MulticastIPRange = "224.1.0.0/16"
-				s.MulticastRTPPort = 8000
-				s.MulticastRTCPPort = 8001
-			}
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
 
-			err := s.Start()
-			require.NoError(t, err)
-			defer s.Close()
+	stream.SetPacketFilter(func(medi *description.Media, pkt *rtp.Packet) FilterAction {
+		require.Equal(t, stream.Description().Medias[0], medi)
 
-			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
-			defer stream.Close()
+		switch pkt.SequenceNumber {
+		case 0:
+			return FilterAction{Drop: true}
 
-			nconn, err := net.Dial("tcp", "localhost:8554")
-			require.NoError(t, err)
-			defer nconn.Close()
-			conn := conn.NewConn(nconn)
+		case 1:
+			return FilterAction{Delay: 100 * time.Millisecond}
 
-			desc := doDescribe(t, conn)
+		default:
+			pkt.Payload = []byte{5, 6, 7, 8}
+			return FilterAction{}
+		}
+	})
 
-			inTH := &headers.Transport{
-				Mode: transportModePtr(headers.TransportModePlay),
-			}
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
 
-			switch transport {
-			case "udp":
-				v := headers.TransportDeliveryUnicast
-				inTH.Delivery = &v
-				inTH.Protocol = headers.TransportProtocolUDP
-				inTH.ClientPorts = &[2]int{35466, 35467}
+	desc := doDescribe(t, conn)
 
-			case "multicast":
-				v := headers.TransportDeliveryMulticast
-				inTH.Delivery = &v
-				inTH.Protocol = headers.TransportProtocolUDP
-			}
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
 
-			res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
 
-			session := readSession(t, res)
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", readSession(t, res))
+
+	pkts := make([]*rtp.Packet, 3)
+	for i := range pkts {
+		pkts[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: uint16(i),
+				CSRC:           []uint32{},
+			},
+			Payload: []byte{1, 2, 3, 4},
+		}
+	}
+
+	for _, pkt := range pkts {
+		err = stream.WritePacketRTP(stream.Description().Medias[0], pkt)
+		require.NoError(t, err)
+	}
+
+	// packets of the caller are not edited
+	require.Equal(t, []byte{1, 2, 3, 4}, pkts[2].Payload)
+
+	for _, ca := range []struct {
+		seqNum  uint16
+		payload []byte
+	}{
+		{2, []byte{5, 6, 7, 8}},
+		{1, []byte{1, 2, 3, 4}},
+	} {
+		f, err := conn.ReadInterleavedFrame()
+		require.NoError(t, err)
+		require.Equal(t, 0, f.Channel)
+
+		var dec rtp.Packet
+		err = dec.Unmarshal(f.Payload)
+		require.NoError(t, err)
+		require.Equal(t, ca.seqNum, dec.SequenceNumber)
+		require.Equal(t, ca.payload, dec.Payload)
+	}
+}
+
+func TestServerPlayRTPModeNormalize(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		RTSPAddress: "localhost:8554",
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	require.Equal(t, ServerStreamRTPModePassthrough, stream.RTPMode())
+
+	err = stream.SetRTPMode(ServerStreamRTPModeNormalize)
+	require.NoError(t, err)
+	require.Equal(t, ServerStreamRTPModeNormalize, stream.RTPMode())
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, th := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	// SSRC is announced before packets are written
+	require.NotNil(t, th.SSRC)
+
+	err = stream.SetRTPMode(ServerStreamRTPModePassthrough)
+	require.EqualError(t, err, "RTP mode can't be changed when the stream has readers")
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", readSession(t, res))
+
+	for i, ssrc := range []uint32{0x11223344, 0x55667788} {
+		err = stream.WritePacketRTP(stream.Description().Medias[0], &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: uint16(1000 * (i + 1)),
+				SSRC:           ssrc,
+				Marker:         true,
+			},
+			Payload: []byte{5, 1, 2, 3},
+		})
+		require.NoError(t, err)
+	}
+
+	var firstSeq uint16
+
+	for i := 0; i < 2; i++ {
+		f, err := conn.ReadInterleavedFrame()
+		require.NoError(t, err)
+		require.Equal(t, 0, f.Channel)
+
+		var dec rtp.Packet
+		err = dec.Unmarshal(f.Payload)
+		require.NoError(t, err)
+		require.Equal(t, *th.SSRC, dec.SSRC)
+		require.Equal(t, []byte{5, 1, 2, 3}, dec.Payload)
+
+		if i == 0 {
+			firstSeq = dec.SequenceNumber
+		} else {
+			require.Equal(t, firstSeq+1, dec.SequenceNumber)
+		}
+	}
+}
+
+func TestServerPlayPause(t *testing.T) {
+	var stream *ServerStream
+	writerStarted := false
+	writerDone := make(chan struct{})
+	writerTerminate := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
+				close(writerTerminate)
+				<-writerDone
+			},
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				if !writerStarted {
+					writerStarted = true
+					go func() {
+						defer close(writerDone)
+
+						ti := time.NewTicker(50 * time.Millisecond)
+						defer ti.Stop()
+
+						for {
+							select {
+							case <-ti.C:
+								err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+								require.NoError(t, err)
+							case <-writerTerminate:
+								return
+							}
+						}
+					}()
+				}
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onPause: func(_ *ServerHandlerOnPauseCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+	doPause(t, conn, "rtsp://localhost:8554/teststream", session)
+}
+
+func TestServerPlayPlayPausePausePlay(t *testing.T) {
+	for _, ca := range []string{"stream", "direct"} {
+		t.Run(ca, func(t *testing.T) {
+			var stream *ServerStream
+			writerStarted := false
+			writerDone := make(chan struct{})
+			writerTerminate := make(chan struct{})
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
+						close(writerTerminate)
+						<-writerDone
+					},
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						if !writerStarted {
+							writerStarted = true
+							go func() {
+								defer close(writerDone)
+
+								ti := time.NewTicker(50 * time.Millisecond)
+								defer ti.Stop()
+
+								for {
+									select {
+									case <-ti.C:
+										if ca == "stream" {
+											err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+											require.NoError(t, err)
+										} else {
+											err := ctx.Session.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+											require.NoError(t, err)
+										}
+
+									case <-writerTerminate:
+										return
+									}
+								}
+							}()
+						}
+
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onPause: func(_ *ServerHandlerOnPauseCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+			defer stream.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			desc := doDescribe(t, conn)
+
+			inTH := &headers.Transport{
+				Protocol:       headers.TransportProtocolTCP,
+				Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+				Mode:           transportModePtr(headers.TransportModePlay),
+				InterleavedIDs: &[2]int{0, 1},
+			}
+
+			res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+			session := readSession(t, res)
+
+			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+			doPause(t, conn, "rtsp://localhost:8554/teststream", session)
+			doPause(t, conn, "rtsp://localhost:8554/teststream", session)
+			time.Sleep(500 * time.Millisecond)
+			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+		})
+	}
+}
+
+func TestServerPlayTimeout(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"multicast",
+		// there's no timeout when reading with TCP
+	} {
+		t.Run(transport, func(t *testing.T) {
+			var stream *ServerStream
+			sessionClosed := make(chan struct{})
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onSessionClose: func(_ *ServerHandlerOnSessionCloseCtx) {
+						close(sessionClosed)
+					},
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				ReadTimeout:       1 * time.Second,
+				SessionOptions:    ServerSessionOptions{Timeout: 1 * time.Second},
+				RTSPAddress:       "localhost:8554",
+				checkStreamPeriod: 500 * time.Millisecond,
+			}
+
+			switch transport {
+			case "udp":
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+
+			case "multicast":
+				s.MulticastIPRange = "224.1.0.0/16"
+				s.MulticastRTPPort = 8000
+				s.MulticastRTCPPort = 8001
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+			defer stream.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			desc := doDescribe(t, conn)
+
+			inTH := &headers.Transport{
+				Mode: transportModePtr(headers.TransportModePlay),
+			}
+
+			switch transport {
+			case "udp":
+				v := headers.TransportDeliveryUnicast
+				inTH.Delivery = &v
+				inTH.Protocol = headers.TransportProtocolUDP
+				inTH.ClientPorts = &[2]int{35466, 35467}
+
+			case "multicast":
+				v := headers.TransportDeliveryMulticast
+				inTH.Delivery = &v
+				inTH.Protocol = headers.TransportProtocolUDP
+			}
+
+			res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+			session := readSession(t, res)
+
+			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+			<-sessionClosed
+		})
+	}
+}
+
+func TestServerPlaySessionTimeoutPolicy(t *testing.T) {
+	for _, ca := range []string{
+		"per-session timeout",
+		"requests are not activity",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			var stream *ServerStream
+			sessionClosed := make(chan struct{})
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onSessionClose: func(_ *ServerHandlerOnSessionCloseCtx) {
+						close(sessionClosed)
+					},
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						if ca == "per-session timeout" {
+							err := ctx.Session.SetTimeout(1 * time.Second)
+							require.NoError(t, err)
+						}
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onGetParameter: func(_ *ServerHandlerOnGetParameterCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress:       "localhost:8554",
+				UDPRTPAddress:     "127.0.0.1:8000",
+				UDPRTCPAddress:    "127.0.0.1:8001",
+				checkStreamPeriod: 500 * time.Millisecond,
+			}
+
+			if ca == "requests are not activity" {
+				s.SessionOptions.Timeout = 1 * time.Second
+				s.SessionOptions.Activity = ServerSessionActivityPackets
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+			defer stream.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			desc := doDescribe(t, conn)
+
+			inTH := &headers.Transport{
+				Delivery:    deliveryPtr(headers.TransportDeliveryUnicast),
+				Mode:        transportModePtr(headers.TransportModePlay),
+				Protocol:    headers.TransportProtocolUDP,
+				ClientPorts: &[2]int{35466, 35467},
+			}
+
+			res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+			var sx headers.Session
+			err = sx.Unmarshal(res.Header["Session"])
+			require.NoError(t, err)
+			require.Equal(t, uint(1), *sx.Timeout)
+
+			doPlay(t, conn, "rtsp://localhost:8554/teststream", sx.Session)
+
+			if ca == "requests are not activity" {
+				for i := 0; ; i++ {
+					select {
+					case <-sessionClosed:
+						return
+					case <-time.After(300 * time.Millisecond):
+					}
+
+					res, err = writeReqReadRes(conn, base.Request{
+						Method: base.GetParameter,
+						URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+						Header: base.Header{
+							"CSeq":    base.HeaderValue{strconv.FormatInt(int64(i+10), 10)},
+							"Session": base.HeaderValue{sx.Session},
+						},
+					})
+					if err != nil || res.StatusCode != base.StatusOK {
+						<-sessionClosed
+						return
+					}
+				}
+			}
+
+			<-sessionClosed
+		})
+	}
+}
+
+func TestServerPlayWithoutTeardown(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"tcp",
+	} {
+		t.Run(transport, func(t *testing.T) {
+			var stream *ServerStream
+			nconnClosed := make(chan struct{})
+			sessionClosed := make(chan struct{})
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
+						close(nconnClosed)
+					},
+					onSessionClose: func(_ *ServerHandlerOnSessionCloseCtx) {
+						close(sessionClosed)
+					},
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				ReadTimeout:    1 * time.Second,
+				SessionOptions: ServerSessionOptions{Timeout: 1 * time.Second},
+				RTSPAddress:    "localhost:8554",
+			}
+
+			if transport == "udp" {
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+			defer stream.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			desc := doDescribe(t, conn)
+
+			inTH := &headers.Transport{
+				Delivery: deliveryPtr(headers.TransportDeliveryUnicast),
+				Mode:     transportModePtr(headers.TransportModePlay),
+			}
+
+			if transport == "udp" {
+				inTH.Protocol = headers.TransportProtocolUDP
+				inTH.ClientPorts = &[2]int{35466, 35467}
+			} else {
+				inTH.Protocol = headers.TransportProtocolTCP
+				inTH.InterleavedIDs = &[2]int{0, 1}
+			}
+
+			res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+			session := readSession(t, res)
+
+			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+			nconn.Close()
+
+			<-sessionClosed
+			<-nconnClosed
+		})
+	}
+}
+
+func TestServerPlayUDPChangeConn(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onGetParameter: func(_ *ServerHandlerOnGetParameterCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+		RTSPAddress:    "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	sxID := ""
+
+	func() {
+		nconn, err := net.Dial("tcp", "localhost:8554")
+		require.NoError(t, err)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		desc := doDescribe(t, conn)
+
+		inTH := &headers.Transport{
+			Delivery:    deliveryPtr(headers.TransportDeliveryUnicast),
+			Mode:        transportModePtr(headers.TransportModePlay),
+			Protocol:    headers.TransportProtocolUDP,
+			ClientPorts: &[2]int{35466, 35467},
+		}
+
+		res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+		session := readSession(t, res)
+
+		doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+		sxID = session
+	}()
+
+	func() {
+		nconn, err := net.Dial("tcp", "localhost:8554")
+		require.NoError(t, err)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		res, err := writeReqReadRes(conn, base.Request{
+			Method: base.GetParameter,
+			URL:    mustParseURL("rtsp://localhost:8554/teststream/"),
+			Header: base.Header{
+				"CSeq":    base.HeaderValue{"4"},
+				"Session": base.HeaderValue{sxID},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, base.StatusOK, res.StatusCode)
+	}()
+}
+
+func TestServerPlayPartialMedias(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				go func() {
+					time.Sleep(500 * time.Millisecond)
+					err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+					require.NoError(t, err)
+					err = stream.WritePacketRTP(stream.Description().Medias[1], &testRTPPacket)
+					require.NoError(t, err)
+				}()
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media, testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		Protocol:       headers.TransportProtocolTCP,
+		InterleavedIDs: &[2]int{4, 5},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+	f, err := conn.ReadInterleavedFrame()
+	require.NoError(t, err)
+	require.Equal(t, 4, f.Channel)
+	require.Equal(t, testRTPPacketMarshaled, f.Payload)
+}
+
+func TestServerPlayAdditionalInfos(t *testing.T) {
+	getInfos := func() (*headers.RTPInfo, []*uint32) {
+		nconn, err := net.Dial("tcp", "localhost:8554")
+		require.NoError(t, err)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		desc := doDescribe(t, conn)
+
+		inTH := &headers.Transport{
+			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+			Mode:           transportModePtr(headers.TransportModePlay),
+			Protocol:       headers.TransportProtocolTCP,
+			InterleavedIDs: &[2]int{0, 1},
+		}
+
+		res, th := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+		ssrcs := make([]*uint32, 2)
+		ssrcs[0] = th.SSRC
+
+		inTH = &headers.Transport{
+			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+			Mode:           transportModePtr(headers.TransportModePlay),
+			Protocol:       headers.TransportProtocolTCP,
+			InterleavedIDs: &[2]int{2, 3},
+		}
+
+		session := readSession(t, res)
+
+		_, th = doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[1]).String(), inTH, session)
+
+		ssrcs[1] = th.SSRC
+
+		res = doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+		var ri headers.RTPInfo
+		err = ri.Unmarshal(res.Header["RTP-Info"])
+		require.NoError(t, err)
+
+		return &ri, ssrcs
+	}
+
+	forma := &format.Generic{
+		PayloadTyp: 96,
+		RTPMa:      "private/90000",
+	}
+	err := forma.Init()
+	require.NoError(t, err)
+
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{
+		Medias: []*description.Media{
+			{
+				Type:    "application",
+				Formats: []format.Format{forma},
+			},
+			{
+				Type:    "application",
+				Formats: []format.Format{forma},
+			},
+		},
+	})
+	defer stream.Close()
+
+	err = stream.WritePacketRTP(stream.Description().Medias[0], &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 556,
+			Timestamp:      984512368,
+			SSRC:           96342362,
+		},
+		Payload: []byte{0x01, 0x02, 0x03, 0x04},
+	})
+	require.NoError(t, err)
+
+	rtpInfo, ssrcs := getInfos()
+	require.True(t, strings.HasPrefix(mustParseURL((*rtpInfo)[0].URL).Path, "/teststream/trackID="))
+	require.Equal(t, &headers.RTPInfo{
+		&headers.RTPInfoEntry{
+			URL: (&base.URL{
+				Scheme: "rtsp",
+				Host:   "localhost:8554",
+				Path:   mustParseURL((*rtpInfo)[0].URL).Path,
+			}).String(),
+			SequenceNumber: uint16Ptr(557),
+			Timestamp:      (*rtpInfo)[0].Timestamp,
+		},
+		&headers.RTPInfoEntry{
+			URL: (&base.URL{
+				Scheme: "rtsp",
+				Host:   "localhost:8554",
+				Path:   mustParseURL((*rtpInfo)[1].URL).Path,
+			}).String(),
+		},
+	}, rtpInfo)
+	require.Equal(t, []*uint32{
+		uint32Ptr(96342362),
+		nil,
+	}, ssrcs)
+
+	err = stream.WritePacketRTP(stream.Description().Medias[1], &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 87,
+			Timestamp:      756436454,
+			SSRC:           536474323,
+		},
+		Payload: []byte{0x01, 0x02, 0x03, 0x04},
+	})
+	require.NoError(t, err)
+
+	rtpInfo, ssrcs = getInfos()
+	require.True(t, strings.HasPrefix(mustParseURL((*rtpInfo)[0].URL).Path, "/teststream/trackID="))
+	require.Equal(t, &headers.RTPInfo{
+		&headers.RTPInfoEntry{
+			URL: (&base.URL{
+				Scheme: "rtsp",
+				Host:   "localhost:8554",
+				Path:   mustParseURL((*rtpInfo)[0].URL).Path,
+			}).String(),
+			SequenceNumber: uint16Ptr(557),
+			Timestamp:      (*rtpInfo)[0].Timestamp,
+		},
+		&headers.RTPInfoEntry{
+			URL: (&base.URL{
+				Scheme: "rtsp",
+				Host:   "localhost:8554",
+				Path:   mustParseURL((*rtpInfo)[1].URL).Path,
+			}).String(),
+			SequenceNumber: uint16Ptr(88),
+			Timestamp:      (*rtpInfo)[1].Timestamp,
+		},
+	}, rtpInfo)
+	require.Equal(t, []*uint32{
+		uint32Ptr(96342362),
+		uint32Ptr(536474323),
+	}, ssrcs)
+}
+
+func TestServerPlayNoInterleavedIDs(t *testing.T) {
+	forma := &format.Generic{
+		PayloadTyp: 96,
+		RTPMa:      "private/90000",
+	}
+	err := forma.Init()
+	require.NoError(t, err)
 
-			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+	var stream *ServerStream
 
-			<-sessionClosed
-		})
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
 	}
-}
-
-func TestServerPlayWithoutTeardown(t *testing.T) {
-	for _, transport := range []string{
-		"udp",
-		"tcp",
-	} {
-		t.Run(transport, func(t *testing.T) {
-			var stream *ServerStream
-			nconnClosed := make(chan struct{})
-			sessionClosed := make(chan struct{})
 
-			s := &Server{
-				Handler: &testServerHandler{
-					onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
-						close(nconnClosed)
-					},
-					onSessionClose: func(_ *ServerHandlerOnSessionCloseCtx) {
-						close(sessionClosed)
-					},
-					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, stream, nil
-					},
-					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, stream, nil
-					},
-					onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
-						return &base.Response{
-							StatusCode: base.StatusOK,
-						}, nil
-					},
-				},
-				ReadTimeout:    1 * time.Second,
-				sessionTimeout: 1 * time.Second,
-				RTSPAddress:    "localhost:8554",
-			}
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
 
-			if transport == "udp" {
-				s.UDPRTPAddress = "127.0.0.1:8000"
-				s.UDPRTCPAddress = "127.0.0.1:8001"
-			}
+	stream = NewServerStream(s, &description.Session{
+		Medias: []*description.Media{
+			{
+				Type:    "application",
+				Formats: []format.Format{forma},
+			},
+			{
+				Type:    "application",
+				Formats: []format.Format{forma},
+			},
+		},
+	})
+	defer stream.Close()
 
-			err := s.Start()
-			require.NoError(t, err)
-			defer s.Close()
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
 
-			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
-			defer stream.Close()
+	desc := doDescribe(t, conn)
 
-			nconn, err := net.Dial("tcp", "localhost:8554")
-			require.NoError(t, err)
-			defer nconn.Close()
-			conn := conn.NewConn(nconn)
+	inTH := &headers.Transport{
+		Delivery: deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:     transportModePtr(headers.TransportModePlay),
+		Protocol: headers.TransportProtocolTCP,
+	}
 
-			desc := doDescribe(t, conn)
+	res, th := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
 
-			inTH := &headers.Transport{
-				Delivery: deliveryPtr(headers.TransportDeliveryUnicast),
-				Mode:     transportModePtr(headers.TransportModePlay),
-			}
+	require.Equal(t, &[2]int{0, 1}, th.InterleavedIDs)
 
-			if transport == "udp" {
-				inTH.Protocol = headers.TransportProtocolUDP
-				inTH.ClientPorts = &[2]int{35466, 35467}
-			} else {
-				inTH.Protocol = headers.TransportProtocolTCP
-				inTH.InterleavedIDs = &[2]int{0, 1}
-			}
+	session := readSession(t, res)
 
-			res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+	_, th = doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[1]).String(), inTH, session)
 
-			session := readSession(t, res)
+	require.Equal(t, &[2]int{2, 3}, th.InterleavedIDs)
 
-			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
 
-			nconn.Close()
+	for i := 0; i < 2; i++ {
+		err := stream.WritePacketRTP(stream.Description().Medias[i], &testRTPPacket)
+		require.NoError(t, err)
 
-			<-sessionClosed
-			<-nconnClosed
-		})
+		f, err := conn.ReadInterleavedFrame()
+		require.NoError(t, err)
+		require.Equal(t, i*2, f.Channel)
+		require.Equal(t, testRTPPacketMarshaled, f.Payload)
 	}
 }
 
-func TestServerPlayUDPChangeConn(t *testing.T) {
+func TestServerPlayStreamStats(t *testing.T) {
 	var stream *ServerStream
 
 	s := &Server{
+		RTSPAddress:       "localhost:8554",
+		MulticastIPRange:  "224.1.0.0/16",
+		MulticastRTPPort:  8000,
+		MulticastRTCPPort: 8001,
 		Handler: &testServerHandler{
 			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
 				return &base.Response{
@@ -1907,28 +3105,18 @@ func TestServerPlayUDPChangeConn(t *testing.T) {
 					StatusCode: base.StatusOK,
 				}, nil
 			},
-			onGetParameter: func(_ *ServerHandlerOnGetParameterCtx) (*base.Response, error) {
-				return &base.Response{
-					StatusCode: base.StatusOK,
-				}, nil
-			},
 		},
-		UDPRTPAddress:  "127.0.0.1:8000",
-		UDPRTCPAddress: "127.0.0.1:8001",
-		RTSPAddress:    "localhost:8554",
 	}
 
 	err := s.Start()
 	require.NoError(t, err)
 	defer s.Close()
-
 	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
 	defer stream.Close()
 
-	sxID := ""
-
-	func() {
-		nconn, err := net.Dial("tcp", "localhost:8554")
+	for _, transport := range []string{"tcp", "multicast"} {
+		var nconn net.Conn
+		nconn, err = net.Dial("tcp", "localhost:8554")
 		require.NoError(t, err)
 		defer nconn.Close()
 		conn := conn.NewConn(nconn)
@@ -1936,10 +3124,18 @@ func TestServerPlayUDPChangeConn(t *testing.T) {
 		desc := doDescribe(t, conn)
 
 		inTH := &headers.Transport{
-			Delivery:    deliveryPtr(headers.TransportDeliveryUnicast),
-			Mode:        transportModePtr(headers.TransportModePlay),
-			Protocol:    headers.TransportProtocolUDP,
-			ClientPorts: &[2]int{35466, 35467},
+			Mode: transportModePtr(headers.TransportModePlay),
+		}
+
+		if transport == "multicast" {
+			v := headers.TransportDeliveryMulticast
+			inTH.Delivery = &v
+			inTH.Protocol = headers.TransportProtocolUDP
+		} else {
+			v := headers.TransportDeliveryUnicast
+			inTH.Delivery = &v
+			inTH.Protocol = headers.TransportProtocolTCP
+			inTH.InterleavedIDs = &[2]int{0, 1}
 		}
 
 		res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
@@ -1947,31 +3143,19 @@ func TestServerPlayUDPChangeConn(t *testing.T) {
 		session := readSession(t, res)
 
 		doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+	}
 
-		sxID = session
-	}()
-
-	func() {
-		nconn, err := net.Dial("tcp", "localhost:8554")
-		require.NoError(t, err)
-		defer nconn.Close()
-		conn := conn.NewConn(nconn)
+	err = stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+	require.NoError(t, err)
 
-		res, err := writeReqReadRes(conn, base.Request{
-			Method: base.GetParameter,
-			URL:    mustParseURL("rtsp://localhost:8554/teststream/"),
-			Header: base.Header{
-				"CSeq":    base.HeaderValue{"4"},
-				"Session": base.HeaderValue{sxID},
-			},
-		})
-		require.NoError(t, err)
-		require.Equal(t, base.StatusOK, res.StatusCode)
-	}()
+	st := stream.Stats()
+	require.Equal(t, uint64(16*2), st.BytesSent)
+	require.Equal(t, st, stream.StatsSnapshot())
 }
 
-func TestServerPlayPartialMedias(t *testing.T) {
+func TestServerPlayRedirect(t *testing.T) {
 	var stream *ServerStream
+	sessionCreated := make(chan *ServerSession, 1)
 
 	s := &Server{
 		Handler: &testServerHandler{
@@ -1985,15 +3169,13 @@ func TestServerPlayPartialMedias(t *testing.T) {
 					StatusCode: base.StatusOK,
 				}, stream, nil
 			},
-			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
-				go func() {
-					time.Sleep(500 * time.Millisecond)
-					err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
-					require.NoError(t, err)
-					err = stream.WritePacketRTP(stream.Description().Medias[1], &testRTPPacket)
-					require.NoError(t, err)
-				}()
-
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				sessionCreated <- ctx.Session
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onGetParameter: func(_ *ServerHandlerOnGetParameterCtx) (*base.Response, error) {
 				return &base.Response{
 					StatusCode: base.StatusOK,
 				}, nil
@@ -2006,7 +3188,7 @@ func TestServerPlayPartialMedias(t *testing.T) {
 	require.NoError(t, err)
 	defer s.Close()
 
-	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media, testH264Media}})
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
 	defer stream.Close()
 
 	nconn, err := net.Dial("tcp", "localhost:8554")
@@ -2017,75 +3199,59 @@ func TestServerPlayPartialMedias(t *testing.T) {
 	desc := doDescribe(t, conn)
 
 	inTH := &headers.Transport{
-		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
-		Mode:           transportModePtr(headers.TransportModePlay),
-		Protocol:       headers.TransportProtocolTCP,
-		InterleavedIDs: &[2]int{4, 5},
-	}
-
-	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
-
-	session := readSession(t, res)
-
-	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-
-	f, err := conn.ReadInterleavedFrame()
-	require.NoError(t, err)
-	require.Equal(t, 4, f.Channel)
-	require.Equal(t, testRTPPacketMarshaled, f.Payload)
-}
-
-func TestServerPlayAdditionalInfos(t *testing.T) {
-	getInfos := func() (*headers.RTPInfo, []*uint32) {
-		nconn, err := net.Dial("tcp", "localhost:8554")
-		require.NoError(t, err)
-		defer nconn.Close()
-		conn := conn.NewConn(nconn)
-
-		desc := doDescribe(t, conn)
-
-		inTH := &headers.Transport{
-			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
-			Mode:           transportModePtr(headers.TransportModePlay),
-			Protocol:       headers.TransportProtocolTCP,
-			InterleavedIDs: &[2]int{0, 1},
-		}
-
-		res, th := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
-
-		ssrcs := make([]*uint32, 2)
-		ssrcs[0] = th.SSRC
-
-		inTH = &headers.Transport{
-			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
-			Mode:           transportModePtr(headers.TransportModePlay),
-			Protocol:       headers.TransportProtocolTCP,
-			InterleavedIDs: &[2]int{2, 3},
-		}
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
 
-		session := readSession(t, res)
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
 
-		_, th = doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[1]).String(), inTH, session)
+	session := readSession(t, res)
 
-		ssrcs[1] = th.SSRC
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
 
-		res = doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+	ss := <-sessionCreated
 
-		var ri headers.RTPInfo
-		err = ri.Unmarshal(res.Header["RTP-Info"])
-		require.NoError(t, err)
+	err = ss.Redirect(mustParseURL("rtsp://otherhost:8554/teststream"), &headers.Range{
+		Value: &headers.RangeNPT{
+			Start: 10 * time.Second,
+		},
+	})
+	require.NoError(t, err)
 
-		return &ri, ssrcs
-	}
+	req, err := conn.ReadRequest()
+	require.NoError(t, err)
+	require.Equal(t, base.Redirect, req.Method)
+	require.Equal(t, base.HeaderValue{"1"}, req.Header["CSeq"])
+	require.Equal(t, base.HeaderValue{session}, req.Header["Session"])
+	require.Equal(t, base.HeaderValue{"rtsp://otherhost:8554/teststream"}, req.Header["Location"])
+	require.Equal(t, base.HeaderValue{"npt=10-"}, req.Header["Range"])
+
+	err = conn.WriteResponse(&base.Response{
+		StatusCode: base.StatusOK,
+		Header: base.Header{
+			"CSeq": req.Header["CSeq"],
+		},
+	})
+	require.NoError(t, err)
 
-	forma := &format.Generic{
-		PayloadTyp: 96,
-		RTPMa:      "private/90000",
-	}
-	err := forma.Init()
+	// the connection must still be usable after the response
+	res, err = writeReqReadRes(conn, base.Request{
+		Method: base.GetParameter,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq":    base.HeaderValue{"5"},
+			"Session": base.HeaderValue{session},
+		},
+	})
 	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+}
 
+func TestServerPlayRedirectUnexpectedResponse(t *testing.T) {
 	var stream *ServerStream
+	sessionCreated := make(chan *ServerSession, 1)
 
 	s := &Server{
 		Handler: &testServerHandler{
@@ -2099,7 +3265,13 @@ func TestServerPlayAdditionalInfos(t *testing.T) {
 					StatusCode: base.StatusOK,
 				}, stream, nil
 			},
-			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				sessionCreated <- ctx.Session
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onGetParameter: func(_ *ServerHandlerOnGetParameterCtx) (*base.Response, error) {
 				return &base.Response{
 					StatusCode: base.StatusOK,
 				}, nil
@@ -2108,111 +3280,77 @@ func TestServerPlayAdditionalInfos(t *testing.T) {
 		RTSPAddress: "localhost:8554",
 	}
 
-	err = s.Start()
+	err := s.Start()
 	require.NoError(t, err)
 	defer s.Close()
 
-	stream = NewServerStream(s, &description.Session{
-		Medias: []*description.Media{
-			{
-				Type:    "application",
-				Formats: []format.Format{forma},
-			},
-			{
-				Type:    "application",
-				Formats: []format.Format{forma},
-			},
-		},
-	})
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
 	defer stream.Close()
 
-	err = stream.WritePacketRTP(stream.Description().Medias[0], &rtp.Packet{
-		Header: rtp.Header{
-			Version:        2,
-			PayloadType:    96,
-			SequenceNumber: 556,
-			Timestamp:      984512368,
-			SSRC:           96342362,
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+	ss := <-sessionCreated
+
+	err = ss.Redirect(mustParseURL("rtsp://otherhost:8554/teststream"), &headers.Range{
+		Value: &headers.RangeNPT{
+			Start: 10 * time.Second,
 		},
-		Payload: []byte{0x01, 0x02, 0x03, 0x04},
 	})
 	require.NoError(t, err)
 
-	rtpInfo, ssrcs := getInfos()
-	require.True(t, strings.HasPrefix(mustParseURL((*rtpInfo)[0].URL).Path, "/teststream/trackID="))
-	require.Equal(t, &headers.RTPInfo{
-		&headers.RTPInfoEntry{
-			URL: (&base.URL{
-				Scheme: "rtsp",
-				Host:   "localhost:8554",
-				Path:   mustParseURL((*rtpInfo)[0].URL).Path,
-			}).String(),
-			SequenceNumber: uint16Ptr(557),
-			Timestamp:      (*rtpInfo)[0].Timestamp,
-		},
-		&headers.RTPInfoEntry{
-			URL: (&base.URL{
-				Scheme: "rtsp",
-				Host:   "localhost:8554",
-				Path:   mustParseURL((*rtpInfo)[1].URL).Path,
-			}).String(),
-		},
-	}, rtpInfo)
-	require.Equal(t, []*uint32{
-		uint32Ptr(96342362),
-		nil,
-	}, ssrcs)
+	req, err := conn.ReadRequest()
+	require.NoError(t, err)
+	require.Equal(t, base.HeaderValue{"1"}, req.Header["CSeq"])
 
-	err = stream.WritePacketRTP(stream.Description().Medias[1], &rtp.Packet{
-		Header: rtp.Header{
-			Version:        2,
-			PayloadType:    96,
-			SequenceNumber: 87,
-			Timestamp:      756436454,
-			SSRC:           536474323,
+	err = conn.WriteResponse(&base.Response{
+		StatusCode: base.StatusOK,
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"2"},
 		},
-		Payload: []byte{0x01, 0x02, 0x03, 0x04},
 	})
 	require.NoError(t, err)
 
-	rtpInfo, ssrcs = getInfos()
-	require.True(t, strings.HasPrefix(mustParseURL((*rtpInfo)[0].URL).Path, "/teststream/trackID="))
-	require.Equal(t, &headers.RTPInfo{
-		&headers.RTPInfoEntry{
-			URL: (&base.URL{
-				Scheme: "rtsp",
-				Host:   "localhost:8554",
-				Path:   mustParseURL((*rtpInfo)[0].URL).Path,
-			}).String(),
-			SequenceNumber: uint16Ptr(557),
-			Timestamp:      (*rtpInfo)[0].Timestamp,
-		},
-		&headers.RTPInfoEntry{
-			URL: (&base.URL{
-				Scheme: "rtsp",
-				Host:   "localhost:8554",
-				Path:   mustParseURL((*rtpInfo)[1].URL).Path,
-			}).String(),
-			SequenceNumber: uint16Ptr(88),
-			Timestamp:      (*rtpInfo)[1].Timestamp,
-		},
-	}, rtpInfo)
-	require.Equal(t, []*uint32{
-		uint32Ptr(96342362),
-		uint32Ptr(536474323),
-	}, ssrcs)
+	// a response that doesn't match any pending request closes the connection
+	_, err = conn.Read()
+	require.Error(t, err)
 }
 
-func TestServerPlayNoInterleavedIDs(t *testing.T) {
-	forma := &format.Generic{
-		PayloadTyp: 96,
+func TestServerPlayUnencryptedMedia(t *testing.T) {
+	metaFormat := &format.Generic{
+		PayloadTyp: 97,
 		RTPMa:      "private/90000",
 	}
-	err := forma.Init()
+	err := metaFormat.Init()
 	require.NoError(t, err)
 
+	metaMedia := &description.Media{
+		Type:    description.MediaTypeApplication,
+		Formats: []format.Format{metaFormat},
+	}
+
 	var stream *ServerStream
 
+	cert, err := tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+
 	s := &Server{
 		Handler: &testServerHandler{
 			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
@@ -2225,34 +3363,26 @@ func TestServerPlayNoInterleavedIDs(t *testing.T) {
 					StatusCode: base.StatusOK,
 				}, stream, nil
 			},
-			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
-				return &base.Response{
-					StatusCode: base.StatusOK,
-				}, nil
+		},
+		RTSPAddress:    "localhost:8554",
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+		SecurityOptions: SecurityOptions{
+			TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+			UnencryptedMedia: func(medi *description.Media) bool {
+				return medi.Type == description.MediaTypeApplication
 			},
 		},
-		RTSPAddress: "localhost:8554",
 	}
 
 	err = s.Start()
 	require.NoError(t, err)
 	defer s.Close()
 
-	stream = NewServerStream(s, &description.Session{
-		Medias: []*description.Media{
-			{
-				Type:    "application",
-				Formats: []format.Format{forma},
-			},
-			{
-				Type:    "application",
-				Formats: []format.Format{forma},
-			},
-		},
-	})
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media, metaMedia}})
 	defer stream.Close()
 
-	nconn, err := net.Dial("tcp", "localhost:8554")
+	nconn, err := tls.Dial("tcp", "localhost:8554", &tls.Config{InsecureSkipVerify: true})
 	require.NoError(t, err)
 	defer nconn.Close()
 	conn := conn.NewConn(nconn)
@@ -2260,42 +3390,56 @@ func TestServerPlayNoInterleavedIDs(t *testing.T) {
 	desc := doDescribe(t, conn)
 
 	inTH := &headers.Transport{
-		Delivery: deliveryPtr(headers.TransportDeliveryUnicast),
-		Mode:     transportModePtr(headers.TransportModePlay),
-		Protocol: headers.TransportProtocolTCP,
+		Mode:        transportModePtr(headers.TransportModePlay),
+		Delivery:    deliveryPtr(headers.TransportDeliveryUnicast),
+		Protocol:    headers.TransportProtocolUDP,
+		ClientPorts: &[2]int{35466, 35467},
 	}
 
-	res, th := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Setup,
+		URL:    mediaURL(t, desc.BaseURL, desc.Medias[0]),
+		Header: base.Header{
+			"CSeq":      base.HeaderValue{"1"},
+			"Transport": inTH.Marshal(),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusUnsupportedTransport, res.StatusCode)
 
-	require.Equal(t, &[2]int{0, 1}, th.InterleavedIDs)
+	res, th := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[1]).String(), inTH, "")
+	require.Equal(t, headers.TransportProtocolUDP, th.Protocol)
 
 	session := readSession(t, res)
 
-	_, th = doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[1]).String(), inTH, session)
-
-	require.Equal(t, &[2]int{2, 3}, th.InterleavedIDs)
-
-	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-
-	for i := 0; i < 2; i++ {
-		err := stream.WritePacketRTP(stream.Description().Medias[i], &testRTPPacket)
-		require.NoError(t, err)
-
-		f, err := conn.ReadInterleavedFrame()
-		require.NoError(t, err)
-		require.Equal(t, i*2, f.Channel)
-		require.Equal(t, testRTPPacketMarshaled, f.Payload)
+	inTH = &headers.Transport{
+		Mode:           transportModePtr(headers.TransportModePlay),
+		Protocol:       headers.TransportProtocolTCP,
+		InterleavedIDs: &[2]int{0, 1},
 	}
+
+	res, err = writeReqReadRes(conn, base.Request{
+		Method: base.Setup,
+		URL:    mediaURL(t, desc.BaseURL, desc.Medias[0]),
+		Header: base.Header{
+			"CSeq":      base.HeaderValue{"2"},
+			"Transport": inTH.Marshal(),
+			"Session":   base.HeaderValue{session},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusBadRequest, res.StatusCode)
 }
 
-func TestServerPlayStreamStats(t *testing.T) {
+func TestServerPlayConcurrentWrites(t *testing.T) {
+	const (
+		producers = 4
+		packets   = 100
+	)
+
 	var stream *ServerStream
 
 	s := &Server{
-		RTSPAddress:       "localhost:8554",
-		MulticastIPRange:  "224.1.0.0/16",
-		MulticastRTPPort:  8000,
-		MulticastRTCPPort: 8001,
 		Handler: &testServerHandler{
 			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
 				return &base.Response{
@@ -2313,48 +3457,69 @@ func TestServerPlayStreamStats(t *testing.T) {
 				}, nil
 			},
 		},
+		RTSPAddress:    "localhost:8554",
+		WriteQueueSize: 2048,
 	}
 
 	err := s.Start()
 	require.NoError(t, err)
 	defer s.Close()
+
 	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
 	defer stream.Close()
 
-	for _, transport := range []string{"tcp", "multicast"} {
-		var nconn net.Conn
-		nconn, err = net.Dial("tcp", "localhost:8554")
-		require.NoError(t, err)
-		defer nconn.Close()
-		conn := conn.NewConn(nconn)
+	var lastCounters [producers]int
+	received := 0
+	done := make(chan struct{})
 
-		desc := doDescribe(t, conn)
+	c := Client{
+		TransportOptions: ClientTransportOptions{
+			Transport: transportPtr(TransportTCP),
+		},
+	}
 
-		inTH := &headers.Transport{
-			Mode: transportModePtr(headers.TransportModePlay),
-		}
+	err = readAll(&c, "rtsp://localhost:8554/teststream",
+		func(_ *description.Media, _ format.Format, pkt *rtp.Packet) {
+			// packets of each producer must be received in order
+			producer := int(pkt.Payload[0])
+			counter := int(pkt.Payload[1])
+			require.Equal(t, lastCounters[producer], counter)
+			lastCounters[producer]++
+
+			received++
+			if received == producers*packets {
+				close(done)
+			}
+		})
+	require.NoError(t, err)
+	defer c.Close()
 
-		if transport == "multicast" {
-			v := headers.TransportDeliveryMulticast
-			inTH.Delivery = &v
-			inTH.Protocol = headers.TransportProtocolUDP
-		} else {
-			v := headers.TransportDeliveryUnicast
-			inTH.Delivery = &v
-			inTH.Protocol = headers.TransportProtocolTCP
-			inTH.InterleavedIDs = &[2]int{0, 1}
-		}
+	// the session is added to the stream only after onPlay returns
+	time.Sleep(500 * time.Millisecond)
 
-		res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+	var wg sync.WaitGroup
 
-		session := readSession(t, res)
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
 
-		doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-	}
+		go func(p int) {
+			defer wg.Done()
 
-	err = stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
-	require.NoError(t, err)
+			for i := 0; i < packets; i++ {
+				err2 := stream.WritePacketRTP(stream.Description().Medias[0], &rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						PayloadType:    96,
+						SequenceNumber: uint16(p*packets + i),
+						SSRC:           0x38F27A2F,
+					},
+					Payload: []byte{byte(p), byte(i)},
+				})
+				require.NoError(t, err2)
+			}
+		}(p)
+	}
 
-	st := stream.Stats()
-	require.Equal(t, uint64(16*2), st.BytesSent)
+	wg.Wait()
+	<-done
 }
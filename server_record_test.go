@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"net"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/bluenviron/gortsplib/v4/pkg/description"
 	"github.com/bluenviron/gortsplib/v4/pkg/format"
 	"github.com/bluenviron/gortsplib/v4/pkg/headers"
+	"github.com/bluenviron/gortsplib/v4/pkg/rtpextension"
 	"github.com/bluenviron/gortsplib/v4/pkg/sdp"
 )
 
@@ -149,6 +151,61 @@ func TestServerRecordErrorAnnounce(t *testing.T) {
 	}
 }
 
+type testServerAnnounceBodyHandler struct {
+	testServerHandler
+	onAnnounceBody func(*ServerHandlerOnAnnounceBodyCtx) (*description.Session, error)
+}
+
+func (sh *testServerAnnounceBodyHandler) OnAnnounceBody(
+	ctx *ServerHandlerOnAnnounceBodyCtx,
+) (*description.Session, error) {
+	return sh.onAnnounceBody(ctx)
+}
+
+func TestServerRecordAnnounceBody(t *testing.T) {
+	s := &Server{
+		Handler: &testServerAnnounceBodyHandler{
+			testServerHandler: testServerHandler{
+				onAnnounce: func(ctx *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+					require.Equal(t, []*description.Media{testH264Media}, ctx.Description.Medias)
+					return &base.Response{
+						StatusCode: base.StatusOK,
+					}, nil
+				},
+			},
+			onAnnounceBody: func(ctx *ServerHandlerOnAnnounceBodyCtx) (*description.Session, error) {
+				require.Equal(t, "text/xml", ctx.ContentType)
+				require.Equal(t, []byte("<stream/>"), ctx.Request.Body)
+				return &description.Session{
+					Medias: []*description.Media{testH264Media},
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Announce,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq":         base.HeaderValue{"1"},
+			"Content-Type": base.HeaderValue{"text/xml; charset=utf-8"},
+		},
+		Body: []byte("<stream/>"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+}
+
 func TestServerRecordErrorSetup(t *testing.T) {
 	for _, ca := range []struct {
 		name string
@@ -972,11 +1029,15 @@ func TestServerRecordRTCPReport(t *testing.T) {
 }
 
 func TestServerRecordTimeout(t *testing.T) {
-	for _, transport := range []string{
+	for _, ca := range []string{
 		"udp",
 		"tcp",
+		"udp sparse",
+		"tcp sparse",
 	} {
-		t.Run(transport, func(t *testing.T) {
+		t.Run(ca, func(t *testing.T) {
+			transport := strings.Split(ca, " ")[0]
+
 			nconnClosed := make(chan struct{})
 			sessionClosed := make(chan struct{})
 
@@ -1004,9 +1065,10 @@ func TestServerRecordTimeout(t *testing.T) {
 						}, nil
 					},
 				},
-				ReadTimeout:       1 * time.Second,
-				RTSPAddress:       "localhost:8554",
-				checkStreamPeriod: 500 * time.Millisecond,
+				ReadTimeout:          1 * time.Second,
+				MediaActivityOptions: MediaActivityOptions{SparseReadTimeout: 3 * time.Second},
+				RTSPAddress:          "localhost:8554",
+				checkStreamPeriod:    500 * time.Millisecond,
 			}
 
 			if transport == "udp" {
@@ -1025,6 +1087,16 @@ func TestServerRecordTimeout(t *testing.T) {
 
 			medias := []*description.Media{testH264Media}
 
+			if strings.HasSuffix(ca, "sparse") {
+				medias = []*description.Media{{
+					Type: description.MediaTypeApplication,
+					Formats: []format.Format{&format.Generic{
+						PayloadTyp: 97,
+						RTPMa:      "private/90000",
+					}},
+				}}
+			}
+
 			doAnnounce(t, conn, "rtsp://localhost:8554/teststream", medias)
 
 			inTH := &headers.Transport{
@@ -1046,8 +1118,16 @@ func TestServerRecordTimeout(t *testing.T) {
 
 			doRecord(t, conn, "rtsp://localhost:8554/teststream", session)
 
+			start := time.Now()
+
 			<-sessionClosed
 
+			if strings.HasSuffix(ca, "sparse") {
+				require.GreaterOrEqual(t, time.Since(start), 2*time.Second)
+			} else {
+				require.Less(t, time.Since(start), 2*time.Second)
+			}
+
 			if transport == "tcp" {
 				<-nconnClosed
 			}
@@ -1566,6 +1646,89 @@ func TestServerRecordPacketNTP(t *testing.T) {
 	<-recv
 }
 
+func TestServerRecordPacketNTPONVIFReplay(t *testing.T) {
+	recv := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				ctx.Session.OnPacketRTPAny(func(medi *description.Media, _ format.Format, pkt *rtp.Packet) {
+					ntp, ok := ctx.Session.PacketNTP(medi, pkt)
+					require.Equal(t, true, ok)
+					require.Equal(t, time.Date(2018, 2, 20, 19, 0, 0, 500000000, time.UTC), ntp.UTC())
+					close(recv)
+				})
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	medias := []*description.Media{testH264Media}
+
+	doAnnounce(t, conn, "rtsp://localhost:8554/teststream", medias)
+
+	inTH := &headers.Transport{
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModeRecord),
+		Protocol:       headers.TransportProtocolTCP,
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, "rtsp://localhost:8554/teststream/"+medias[0].Control, inTH, "")
+
+	session := readSession(t, res)
+
+	doRecord(t, conn, "rtsp://localhost:8554/teststream", session)
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 534,
+			Timestamp:      54352,
+			SSRC:           753621,
+		},
+		Payload: []byte{1, 2, 3, 4},
+	}
+
+	rtpextension.ONVIFReplay{
+		NTPTime:    time.Date(2018, 2, 20, 19, 0, 0, 500000000, time.UTC),
+		CleanPoint: true,
+	}.Write(&pkt.Header)
+
+	err = conn.WriteInterleavedFrame(&base.InterleavedFrame{
+		Channel: 0,
+		Payload: mustMarshalPacketRTP(pkt),
+	}, make([]byte, 1024))
+	require.NoError(t, err)
+
+	<-recv
+}
+
 func TestServerRecordPausePause(t *testing.T) {
 	s := &Server{
 		Handler: &testServerHandler{
@@ -1631,3 +1794,372 @@ func TestServerRecordPausePause(t *testing.T) {
 
 	doPause(t, conn, "rtsp://localhost:8554/teststream", session)
 }
+
+func TestServerRecordStatsRates(t *testing.T) {
+	serverRates := make(chan StatsSessionMedia, 1)
+	clientRates := make(chan StatsSessionMedia, 1)
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(_ *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSessionStats: func(ctx *ServerHandlerOnSessionStatsCtx) {
+				for _, sm := range ctx.Stats.Medias {
+					if sm.FrameRateReceived != 0 {
+						select {
+						case serverRates <- sm:
+						default:
+						}
+					}
+				}
+			},
+		},
+		RTSPAddress:          "localhost:8554",
+		ObservabilityOptions: ObservabilityOptions{StatsPeriod: 100 * time.Millisecond},
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	c := Client{
+		TransportOptions: ClientTransportOptions{
+			Transport: transportPtr(TransportTCP),
+		},
+		ObservabilityOptions: ObservabilityOptions{StatsPeriod: 100 * time.Millisecond},
+		OnStats: func(stats *ClientStats) {
+			for _, sm := range stats.Session.Medias {
+				if sm.FrameRateSent != 0 {
+					select {
+					case clientRates <- sm:
+					default:
+					}
+				}
+			}
+		},
+	}
+
+	desc := &description.Session{Medias: []*description.Media{testH264Media}}
+
+	err = c.StartRecording("rtsp://localhost:8554/teststream", desc)
+	require.NoError(t, err)
+	defer c.Close()
+
+	done := make(chan struct{})
+	writerDone := make(chan struct{})
+
+	defer func() {
+		close(done)
+		<-writerDone
+	}()
+
+	go func() {
+		defer close(writerDone)
+
+		for i := 0; i < 100; i++ {
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			err2 := c.WritePacketRTP(desc.Medias[0], &rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         (i % 2) == 1,
+					PayloadType:    96,
+					SequenceNumber: uint16(i),
+					Timestamp:      uint32(i / 2 * 3600),
+					SSRC:           0x38F27A2F,
+				},
+				Payload: []byte{5},
+			})
+			require.NoError(t, err2)
+		}
+	}()
+
+	sm := <-serverRates
+	require.NotZero(t, sm.RTPBitrateReceived)
+	require.NotZero(t, sm.RTPPacketRateReceived)
+	require.Less(t, sm.FrameRateReceived, sm.RTPPacketRateReceived)
+	require.Zero(t, sm.RTPPacketRateSent)
+
+	sm = <-clientRates
+	require.NotZero(t, sm.RTPBitrateSent)
+	require.NotZero(t, sm.RTPPacketRateSent)
+	require.Less(t, sm.FrameRateSent, sm.RTPPacketRateSent)
+	require.Zero(t, sm.RTPPacketRateReceived)
+}
+
+func TestServerRecordSenderIdentity(t *testing.T) {
+	announced := make(chan *description.Session, 1)
+	serverStats := make(chan StatsSessionFormat, 1)
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(ctx *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				announced <- ctx.Description
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(_ *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSessionStats: func(ctx *ServerHandlerOnSessionStatsCtx) {
+				for _, sm := range ctx.Stats.Medias {
+					for _, sf := range sm.Formats {
+						if sf.RemoteCNAME != "" {
+							select {
+							case serverStats <- sf:
+							default:
+							}
+						}
+					}
+				}
+			},
+		},
+		RTSPAddress:          "localhost:8554",
+		ObservabilityOptions: ObservabilityOptions{StatsPeriod: 100 * time.Millisecond},
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	c := Client{
+		TransportOptions: ClientTransportOptions{
+			Transport: transportPtr(TransportTCP),
+		},
+		RecordOptions: ClientRecordOptions{
+			SenderIdentity: &SenderIdentity{
+				CNAME:       "camera1",
+				Name:        "Camera 1",
+				LabelMedias: true,
+			},
+		},
+		senderReportPeriod: 100 * time.Millisecond,
+	}
+
+	desc := &description.Session{Medias: []*description.Media{{
+		Type:    description.MediaTypeVideo,
+		Formats: []format.Format{&format.H264{PayloadTyp: 96, PacketizationMode: 1}},
+	}}}
+
+	err = c.StartRecording("rtsp://localhost:8554/teststream", desc)
+	require.NoError(t, err)
+	defer c.Close()
+
+	announcedDesc := <-announced
+	require.Equal(t, "camera1-0", announcedDesc.Medias[0].Label)
+
+	err = c.WritePacketRTP(desc.Medias[0], &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 1,
+			Timestamp:      3600,
+			SSRC:           0x38F27A2F,
+		},
+		Payload: []byte{5},
+	})
+	require.NoError(t, err)
+
+	sf := <-serverStats
+	require.Equal(t, uint32(0x38F27A2F), sf.RemoteSSRC)
+	require.Equal(t, "camera1", sf.RemoteCNAME)
+	require.Equal(t, "Camera 1", sf.RemoteName)
+}
+
+func TestServerRecordSendTimeExtensions(t *testing.T) {
+	recv := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				ctx.Session.OnPacketRTPAny(func(medi *description.Media, _ format.Format, pkt *rtp.Packet) {
+					require.Len(t, medi.Extensions, 2)
+
+					sendTime, ok := ctx.Session.PacketAbsSendTime(medi, pkt)
+					require.True(t, ok)
+					require.Less(t, time.Since(sendTime), 10*time.Second)
+
+					offset, ok := ctx.Session.PacketTransmissionOffset(medi, pkt)
+					require.True(t, ok)
+					require.InDelta(t, 2*90000, offset, 90000)
+
+					close(recv)
+				})
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	c := Client{
+		TransportOptions: ClientTransportOptions{
+			Transport: transportPtr(TransportTCP),
+		},
+	}
+
+	desc := &description.Session{Medias: []*description.Media{{
+		Type: description.MediaTypeVideo,
+		Extensions: []description.MediaExtension{
+			{ID: 2, URI: rtpextension.AbsSendTimeURI},
+			{ID: 3, URI: rtpextension.TransmissionOffsetURI},
+		},
+		Formats: []format.Format{&format.H264{PayloadTyp: 96, PacketizationMode: 1}},
+	}}}
+
+	err = c.StartRecording("rtsp://localhost:8554/teststream", desc)
+	require.NoError(t, err)
+	defer c.Close()
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 1,
+			Timestamp:      3600,
+			SSRC:           0x38F27A2F,
+		},
+		Payload: []byte{5},
+	}
+
+	err = c.WritePacketRTPWithNTP(desc.Medias[0], pkt, time.Now().Add(-2*time.Second))
+	require.NoError(t, err)
+
+	// packets of the caller are not edited
+	require.False(t, pkt.Extension)
+
+	<-recv
+}
+
+func TestServerRecordDetectCorruption(t *testing.T) {
+	decodeErr := make(chan error, 1)
+	serverStats := make(chan StatsSessionFormat, 1)
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(_ *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onDecodeError: func(ctx *ServerHandlerOnDecodeErrorCtx) {
+				decodeErr <- ctx.Error
+			},
+			onSessionStats: func(ctx *ServerHandlerOnSessionStatsCtx) {
+				for _, sm := range ctx.Stats.Medias {
+					for _, sf := range sm.Formats {
+						if sf.AccessUnitsReceived == 3 {
+							select {
+							case serverStats <- sf:
+							default:
+							}
+						}
+					}
+				}
+			},
+		},
+		RTSPAddress: "localhost:8554",
+		SessionOptions: ServerSessionOptions{
+			DetectCorruption: true,
+		},
+		ObservabilityOptions: ObservabilityOptions{StatsPeriod: 100 * time.Millisecond},
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	c := Client{
+		TransportOptions: ClientTransportOptions{
+			Transport: transportPtr(TransportTCP),
+		},
+	}
+
+	desc := &description.Session{Medias: []*description.Media{{
+		Type:    description.MediaTypeVideo,
+		Formats: []format.Format{&format.H264{PayloadTyp: 96, PacketizationMode: 1}},
+	}}}
+
+	err = c.StartRecording("rtsp://localhost:8554/teststream", desc)
+	require.NoError(t, err)
+	defer c.Close()
+
+	for i, payload := range [][]byte{
+		{0x65, 0x01},       // IDR
+		{0x1c, 0x81, 0x02}, // FU-A start without end
+		{0x65, 0x03},       // IDR
+	} {
+		err = c.WritePacketRTP(desc.Medias[0], &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: uint16(1000 + i),
+				Timestamp:      uint32(i * 3000),
+				SSRC:           0x38F27A2F,
+			},
+			Payload: payload,
+		})
+		require.NoError(t, err)
+	}
+
+	err = <-decodeErr
+	require.EqualError(t, err, "1 of 2 access units of a group of pictures are corrupted")
+
+	sf := <-serverStats
+	require.Equal(t, uint64(1), sf.AccessUnitsCorrupted)
+	require.Equal(t, uint64(0), sf.RTPPacketsInError)
+}
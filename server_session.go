@@ -3,7 +3,7 @@ package gortsplib
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
 	"net"
 	"strconv"
 	"strings"
@@ -15,6 +15,7 @@ import (
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 
+	"github.com/bluenviron/gortsplib/v4/internal/pacer"
 	"github.com/bluenviron/gortsplib/v4/internal/rtcpreceiver"
 	"github.com/bluenviron/gortsplib/v4/internal/rtcpsender"
 	"github.com/bluenviron/gortsplib/v4/pkg/base"
@@ -163,7 +164,7 @@ func findFirstSupportedTransportHeader(s *Server, tsh headers.Transports) *heade
 		isMulticast := tr.Delivery != nil && *tr.Delivery == headers.TransportDeliveryMulticast
 		if tr.Protocol == headers.TransportProtocolUDP &&
 			((!isMulticast && s.udpRTPListener == nil) ||
-				(isMulticast && s.MulticastIPRange == "")) {
+				(isMulticast && s.TransportOptions.MulticastIPRange == "")) {
 			continue
 		}
 		return &tr
@@ -230,17 +231,25 @@ func (s ServerSessionState) String() string {
 	return "unknown"
 }
 
+type sessionRedirectReq struct {
+	u   *base.URL
+	ra  *headers.Range
+	res chan error
+}
+
 // ServerSession is a server-side RTSP session.
 type ServerSession struct {
 	s      *Server
 	author *ServerConn
 
+	id                    uuid.UUID
 	secretID              string // must not be shared, allows to take ownership of the session
 	ctx                   context.Context
 	ctxCancel             func()
 	userData              interface{}
 	conns                 map[*ServerConn]struct{}
 	state                 ServerSessionState
+	atomicState           *int32 // copy of state that can be read by other routines
 	setuppedMedias        map[*description.Media]*serverSessionMedia
 	setuppedMediasOrdered []*serverSessionMedia
 	tcpCallbackByChannel  map[int]readFunc
@@ -249,20 +258,27 @@ type ServerSession struct {
 	setuppedPath          string
 	setuppedQuery         string
 	lastRequestTime       time.Time
+	timeout               time.Duration
+	writeQueueSize        int
 	tcpConn               *ServerConn
 	announcedDesc         *description.Session // publish
 	udpLastPacketTime     *int64               // publish
 	udpCheckStreamTimer   *time.Timer
+	statsTimer            *time.Timer
 	writer                *asyncProcessor
 	writerMutex           sync.RWMutex
+	writerPacer           *pacer.Pacer
 	timeDecoder           *rtptime.GlobalDecoder2
 	tcpFrame              *base.InterleavedFrame
 	tcpBuffer             []byte
+	packetDump            atomic.Pointer[packetDump]
 
 	// in
 	chHandleRequest    chan sessionRequestReq
 	chRemoveConn       chan *ServerConn
 	chAsyncStartWriter chan struct{}
+	chRedirect         chan sessionRedirectReq
+	chKick             chan string
 }
 
 func (ss *ServerSession) initialize() {
@@ -271,16 +287,27 @@ func (ss *ServerSession) initialize() {
 	// use an UUID without dashes, since dashes confuse some clients.
 	secretID := strings.ReplaceAll(uuid.New().String(), "-", "")
 
+	ss.id = uuid.New()
 	ss.secretID = secretID
 	ss.ctx = ctx
 	ss.ctxCancel = ctxCancel
 	ss.conns = make(map[*ServerConn]struct{})
 	ss.lastRequestTime = ss.s.timeNow()
+	ss.timeout = ss.s.SessionOptions.Timeout
 	ss.udpCheckStreamTimer = emptyTimer()
+	ss.atomicState = new(int32)
+
+	if _, ok := ss.s.Handler.(ServerHandlerOnSessionStats); ok {
+		ss.statsTimer = time.NewTimer(ss.s.ObservabilityOptions.StatsPeriod)
+	} else {
+		ss.statsTimer = emptyTimer()
+	}
 
 	ss.chHandleRequest = make(chan sessionRequestReq)
 	ss.chRemoveConn = make(chan *ServerConn)
 	ss.chAsyncStartWriter = make(chan struct{})
+	ss.chRedirect = make(chan sessionRedirectReq)
+	ss.chKick = make(chan string, 1)
 
 	ss.s.wg.Add(1)
 	go ss.run()
@@ -291,6 +318,32 @@ func (ss *ServerSession) Close() {
 	ss.ctxCancel()
 }
 
+// Kick closes the session, and all its connections, forcibly.
+// reason is reported to ServerHandlerOnSessionClose through a liberrors.ErrServerSessionKicked error.
+// It can be called inside a ServerHandler callback.
+func (ss *ServerSession) Kick(reason string) {
+	select {
+	case ss.chKick <- reason:
+	default: // session is already being kicked
+	}
+}
+
+// Redirect sends a REDIRECT request to the client,
+// asking it to connect to another server.
+// ra is the time at which the redirect takes effect (optional).
+// It must not be called inside a ServerHandler callback.
+func (ss *ServerSession) Redirect(u *base.URL, ra *headers.Range) error {
+	cres := make(chan error)
+
+	select {
+	case ss.chRedirect <- sessionRedirectReq{u: u, ra: ra, res: cres}:
+		return <-cres
+
+	case <-ss.ctx.Done():
+		return liberrors.ErrServerTerminated{}
+	}
+}
+
 // BytesReceived returns the number of read bytes.
 //
 // Deprecated: replaced by Stats()
@@ -323,6 +376,11 @@ func (ss *ServerSession) SetuppedTransport() *Transport {
 	return ss.setuppedTransport
 }
 
+// RemoteAddr returns the address of the client that created the session.
+func (ss *ServerSession) RemoteAddr() net.Addr {
+	return ss.author.nconn.RemoteAddr()
+}
+
 // SetuppedStream returns the stream associated with the session.
 func (ss *ServerSession) SetuppedStream() *ServerStream {
 	return ss.setuppedStream
@@ -352,18 +410,161 @@ func (ss *ServerSession) SetuppedMedias() []*description.Media {
 	return ret
 }
 
+// StartPacketDump starts writing received and sent RTP and RTCP packets into w,
+// in the pcapng format, in order to debug interoperability issues.
+// Packets are written as UDP datagrams, that can be decoded by Wireshark as RTP or RTCP.
+// With the TCP transport, ports are set to the interleaved channels.
+// Packets sent through multicast are not written.
+// It can be called at any time, including while reading or publishing.
+func (ss *ServerSession) StartPacketDump(w io.Writer) error {
+	if ss.packetDump.Load() != nil {
+		return fmt.Errorf("packet dump already started")
+	}
+
+	d, err := newPacketDump(w)
+	if err != nil {
+		return err
+	}
+
+	if !ss.packetDump.CompareAndSwap(nil, d) {
+		return fmt.Errorf("packet dump already started")
+	}
+
+	return nil
+}
+
+// StopPacketDump stops writing packets into the writer passed to StartPacketDump().
+// It returns the first error that occurred while writing packets.
+func (ss *ServerSession) StopPacketDump() error {
+	d := ss.packetDump.Swap(nil)
+	if d == nil {
+		return fmt.Errorf("packet dump not started")
+	}
+
+	return d.close()
+}
+
+// MediaTransports returns transport parameters of setupped medias.
+// It is meant to be called inside handler callbacks.
+func (ss *ServerSession) MediaTransports() map[*description.Media]MediaTransportInfo {
+	stats := ss.Stats()
+	ret := make(map[*description.Media]MediaTransportInfo, len(ss.setuppedMedias))
+
+	for medi, sm := range ss.setuppedMedias {
+		info := MediaTransportInfo{
+			Transport: *ss.setuppedTransport,
+		}
+
+		switch *ss.setuppedTransport {
+		case TransportUDP:
+			info.LocalRTPPort = ss.s.udpRTPListener.port()
+			info.LocalRTCPPort = ss.s.udpRTCPListener.port()
+			info.RemoteRTPPort = sm.udpRTPReadPort
+			info.RemoteRTCPPort = sm.udpRTCPReadPort
+			info.ReadBufferSize = ss.s.udpRTPListener.actualReadBufferSize
+			info.WriteBufferSize = ss.s.udpRTPListener.actualWriteBufferSize
+
+		case TransportUDPMulticast:
+			info.LocalRTPPort = ss.s.TransportOptions.MulticastRTPPort
+			info.LocalRTCPPort = ss.s.TransportOptions.MulticastRTCPPort
+			info.RemoteRTPPort = ss.s.TransportOptions.MulticastRTPPort
+			info.RemoteRTCPPort = ss.s.TransportOptions.MulticastRTCPPort
+			mw := ss.setuppedStream.medias[medi].multicastWriter
+			info.MulticastGroup = mw.ip()
+			info.ReadBufferSize = mw.rtpl.actualReadBufferSize
+			info.WriteBufferSize = mw.rtpl.actualWriteBufferSize
+
+		default: // TCP
+			info.InterleavedIDs = &[2]int{sm.tcpChannel, sm.tcpChannel + 1}
+		}
+
+		info.fillSSRCs(stats.Medias[medi])
+
+		// when reading, SSRCs of outgoing packets are the ones of the stream,
+		// that may be known before any packet is written.
+		if ss.setuppedStream != nil {
+			info.LocalSSRCs = ss.setuppedStream.localSSRCs(medi)
+		}
+
+		ret[medi] = info
+	}
+
+	return ret
+}
+
+// ID returns a unique identifier of the session.
+// Unlike the session ID sent to clients, it can be shared safely,
+// for instance in logs.
+func (ss *ServerSession) ID() uuid.UUID {
+	return ss.id
+}
+
 // SetUserData sets some user data associated with the session.
 func (ss *ServerSession) SetUserData(v interface{}) {
 	ss.userData = v
 }
 
+// SetTimeout sets the timeout of the session, overriding Server.SessionOptions.Timeout.
+// It must be at least one second.
+// It is meant to be called inside handler callbacks, before the response to PLAY,
+// in order to advertise the timeout to the client.
+func (ss *ServerSession) SetTimeout(v time.Duration) error {
+	if v < time.Second {
+		return fmt.Errorf("timeout must be at least one second")
+	}
+	ss.timeout = v
+	return nil
+}
+
+// Timeout returns the timeout of the session.
+func (ss *ServerSession) Timeout() time.Duration {
+	return ss.timeout
+}
+
+// SetWriteQueueSize sets the size of the queue of outgoing packets of the session,
+// overriding ServerStream.WriteQueueSize() and Server.WriteQueueSize.
+// It must be a power of two.
+// It is meant to be called inside OnSetup, since the queue is allocated before OnPlay is called.
+func (ss *ServerSession) SetWriteQueueSize(v int) error {
+	if v <= 0 || (v&(v-1)) != 0 {
+		return fmt.Errorf("write queue size must be a power of two")
+	}
+	ss.writeQueueSize = v
+	return nil
+}
+
+// WriteQueueSize returns the size of the queue of outgoing packets used when reading.
+// It is meant to be called inside handler callbacks.
+func (ss *ServerSession) WriteQueueSize() int {
+	if ss.writeQueueSize != 0 {
+		return ss.writeQueueSize
+	}
+
+	if ss.setuppedStream != nil {
+		if v := ss.setuppedStream.WriteQueueSize(); v != 0 {
+			return v
+		}
+	}
+
+	return ss.s.limits.Load().WriteQueueSize
+}
+
 // UserData returns some user data associated with the session.
 func (ss *ServerSession) UserData() interface{} {
 	return ss.userData
 }
 
 // Stats returns server session statistics.
+// It is equivalent to StatsSnapshot().
 func (ss *ServerSession) Stats() *StatsSession {
+	return ss.StatsSnapshot()
+}
+
+// StatsSnapshot returns a snapshot of server session statistics.
+// Counters are updated atomically by packet paths, that never wait for readers,
+// therefore it can be called periodically without slowing down packet processing.
+// See StatsSession for the consistency model.
+func (ss *ServerSession) StatsSnapshot() *StatsSession {
 	return &StatsSession{
 		BytesReceived: func() uint64 {
 			v := uint64(0)
@@ -456,14 +657,26 @@ func (ss *ServerSession) Stats() *StatsSession {
 		Medias: func() map[*description.Media]StatsSessionMedia { //nolint:dupl
 			ret := make(map[*description.Media]StatsSessionMedia, len(ss.setuppedMedias))
 
+			now := ss.s.timeNow()
+
 			for med, sm := range ss.setuppedMedias {
+				received := sm.rates.received.Rates(now)
+				sent := sm.rates.sent.Rates(now)
+
 				ret[med] = StatsSessionMedia{
-					BytesReceived:       atomic.LoadUint64(sm.bytesReceived),
-					BytesSent:           atomic.LoadUint64(sm.bytesSent),
-					RTPPacketsInError:   atomic.LoadUint64(sm.rtpPacketsInError),
-					RTCPPacketsReceived: atomic.LoadUint64(sm.rtcpPacketsReceived),
-					RTCPPacketsSent:     atomic.LoadUint64(sm.rtcpPacketsSent),
-					RTCPPacketsInError:  atomic.LoadUint64(sm.rtcpPacketsInError),
+					BytesReceived:         atomic.LoadUint64(sm.bytesReceived),
+					BytesSent:             atomic.LoadUint64(sm.bytesSent),
+					RTPPacketsInError:     atomic.LoadUint64(sm.rtpPacketsInError),
+					RTCPPacketsReceived:   atomic.LoadUint64(sm.rtcpPacketsReceived),
+					RTCPPacketsSent:       atomic.LoadUint64(sm.rtcpPacketsSent),
+					RTCPPacketsInError:    atomic.LoadUint64(sm.rtcpPacketsInError),
+					UDPPacketsTruncated:   atomic.LoadUint64(sm.udpPacketsTruncated),
+					RTPBitrateReceived:    received.Bitrate,
+					RTPBitrateSent:        sent.Bitrate,
+					RTPPacketRateReceived: received.PacketRate,
+					RTPPacketRateSent:     sent.PacketRate,
+					FrameRateReceived:     received.FrameRate,
+					FrameRateSent:         sent.FrameRate,
 					Formats: func() map[format.Format]StatsSessionFormat {
 						ret := make(map[format.Format]StatsSessionFormat, len(sm.formats))
 
@@ -487,10 +700,15 @@ func (ss *ServerSession) Stats() *StatsSession {
 								return nil
 							}()
 
+							remoteCNAME, remoteName := fo.remoteSDES.stats(med, recvStats)
+
 							ret[fo.format] = StatsSessionFormat{ //nolint:dupl
-								RTPPacketsReceived: atomic.LoadUint64(fo.rtpPacketsReceived),
-								RTPPacketsSent:     atomic.LoadUint64(fo.rtpPacketsSent),
-								RTPPacketsLost:     atomic.LoadUint64(fo.rtpPacketsLost),
+								RTPPacketsReceived:   atomic.LoadUint64(fo.rtpPacketsReceived),
+								RTPPacketsSent:       atomic.LoadUint64(fo.rtpPacketsSent),
+								RTPPacketsLost:       atomic.LoadUint64(fo.rtpPacketsLost),
+								RTPPacketsInError:    atomic.LoadUint64(fo.rtpPacketsInError),
+								AccessUnitsReceived:  atomic.LoadUint64(fo.accessUnitsReceived),
+								AccessUnitsCorrupted: atomic.LoadUint64(fo.accessUnitsCorrupted),
 								LocalSSRC: func() uint32 {
 									if fo.rtcpReceiver != nil {
 										return *fo.rtcpReceiver.LocalSSRC
@@ -506,6 +724,8 @@ func (ss *ServerSession) Stats() *StatsSession {
 									}
 									return 0
 								}(),
+								RemoteCNAME: remoteCNAME,
+								RemoteName:  remoteName,
 								RTPPacketsLastSequenceNumber: func() uint16 {
 									if recvStats != nil {
 										return recvStats.LastSequenceNumber
@@ -559,10 +779,19 @@ func (ss *ServerSession) onStreamWriteError(err error) {
 			Error:   err,
 		})
 	} else {
-		log.Println(err.Error())
+		ss.s.ObservabilityOptions.Logger.Warn(err.Error(), "session", ss.id)
 	}
 }
 
+func (ss *ServerSession) setState(state ServerSessionState) {
+	ss.state = state
+	atomic.StoreInt32(ss.atomicState, int32(state))
+}
+
+func (ss *ServerSession) loadState() ServerSessionState {
+	return ServerSessionState(atomic.LoadInt32(ss.atomicState))
+}
+
 func (ss *ServerSession) checkState(allowed map[ServerSessionState]struct{}) error {
 	if _, ok := allowed[ss.state]; ok {
 		return nil
@@ -583,7 +812,7 @@ func (ss *ServerSession) createWriter() {
 	ss.writer = &asyncProcessor{
 		bufferSize: func() int {
 			if ss.state == ServerSessionStatePrePlay {
-				return ss.s.WriteQueueSize
+				return ss.WriteQueueSize()
 			}
 
 			// when recording, writeBuffer is only used to send RTCP receiver reports,
@@ -595,9 +824,51 @@ func (ss *ServerSession) createWriter() {
 
 	ss.writer.initialize()
 
+	ss.writerPacer = nil
+
+	if ss.state == ServerSessionStatePrePlay && *ss.setuppedTransport == TransportTCP {
+		l := ss.s.limits.Load()
+
+		if l.MaxReaderBitrate != 0 {
+			burst := l.MaxReaderBurst
+			if burst == 0 {
+				burst = l.MaxReaderBitrate / 8 / 10
+			}
+
+			ss.writerPacer = &pacer.Pacer{
+				Bitrate: l.MaxReaderBitrate,
+				Burst:   burst,
+			}
+			ss.writerPacer.Initialize()
+		}
+	}
+
 	ss.writerMutex.Unlock()
 }
 
+// waitPacer waits until n bytes can be sent to the reader.
+// It is called by the writer routine.
+func (ss *ServerSession) waitPacer(n int) error {
+	if ss.writerPacer == nil {
+		return nil
+	}
+
+	d := ss.writerPacer.Consume(n)
+	if d == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ss.ctx.Done():
+		return liberrors.ErrServerTerminated{}
+	}
+}
+
 func (ss *ServerSession) startWriter() {
 	ss.writer.start()
 }
@@ -696,7 +967,7 @@ func (ss *ServerSession) runInner() error {
 								ss.state == ServerSessionStatePlay) &&
 								(*ss.setuppedTransport == TransportUDP ||
 									*ss.setuppedTransport == TransportUDPMulticast) {
-								v := uint(ss.s.sessionTimeout / time.Second)
+								v := uint(ss.timeout / time.Second)
 								return &v
 							}
 							return nil
@@ -736,6 +1007,12 @@ func (ss *ServerSession) runInner() error {
 				return liberrors.ErrServerSessionNotInUse{}
 			}
 
+		case reason := <-ss.chKick:
+			return liberrors.ErrServerSessionKicked{Reason: reason}
+
+		case req := <-ss.chRedirect:
+			req.res <- ss.doRedirect(req.u, req.ra)
+
 		case <-ss.chAsyncStartWriter:
 			if (ss.state == ServerSessionStateRecord ||
 				ss.state == ServerSessionStatePlay) &&
@@ -743,6 +1020,13 @@ func (ss *ServerSession) runInner() error {
 				ss.startWriter()
 			}
 
+		case <-ss.statsTimer.C:
+			ss.s.Handler.(ServerHandlerOnSessionStats).OnSessionStats(&ServerHandlerOnSessionStatsCtx{
+				Session: ss,
+				Stats:   ss.StatsSnapshot(),
+			})
+			ss.statsTimer = time.NewTimer(ss.s.ObservabilityOptions.StatsPeriod)
+
 		case <-ss.udpCheckStreamTimer.C:
 			now := ss.s.timeNow()
 
@@ -750,13 +1034,16 @@ func (ss *ServerSession) runInner() error {
 
 			// in case of RECORD, timeout happens when no RTP or RTCP packets are being received
 			if ss.state == ServerSessionStateRecord {
-				if now.Sub(time.Unix(lft, 0)) >= ss.s.ReadTimeout {
+				if now.Sub(time.Unix(lft, 0)) >= ss.readTimeout() {
 					return liberrors.ErrServerSessionTimedOut{}
 				}
 
-				// in case of PLAY, timeout happens when no RTSP keepalives and no RTCP packets are being received
-			} else if now.Sub(ss.lastRequestTime) >= ss.s.sessionTimeout &&
-				now.Sub(time.Unix(lft, 0)) >= ss.s.sessionTimeout {
+				// in case of PLAY, timeout happens when there's no activity, that is, by default,
+				// no RTSP keepalives and no RTCP packets are being received
+			} else if ((ss.s.SessionOptions.Activity&ServerSessionActivityRequests) == 0 ||
+				now.Sub(ss.lastRequestTime) >= ss.timeout) &&
+				((ss.s.SessionOptions.Activity&ServerSessionActivityPackets) == 0 ||
+					now.Sub(time.Unix(lft, 0)) >= ss.timeout) {
 				return liberrors.ErrServerSessionTimedOut{}
 			}
 
@@ -810,7 +1097,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			methods = append(methods, string(base.Pause))
 		}
 		methods = append(methods, string(base.GetParameter))
-		if _, ok := sc.s.Handler.(ServerHandlerOnSetParameter); ok {
+		if serverHandlerSupportsSetParameter(sc.s.Handler) {
 			methods = append(methods, string(base.SetParameter))
 		}
 		methods = append(methods, string(base.Teardown))
@@ -832,33 +1119,52 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			}, err
 		}
 
-		ct, ok := req.Header["Content-Type"]
-		if !ok || len(ct) != 1 {
+		ct, ok := req.Header.ContentType()
+		if !ok {
 			return &base.Response{
 				StatusCode: base.StatusBadRequest,
 			}, liberrors.ErrServerContentTypeMissing{}
 		}
 
-		if ct[0] != "application/sdp" {
-			return &base.Response{
-				StatusCode: base.StatusBadRequest,
-			}, liberrors.ErrServerContentTypeUnsupported{CT: ct}
-		}
+		var desc *description.Session
 
-		var ssd sdp.SessionDescription
-		err = ssd.Unmarshal(req.Body)
-		if err != nil {
-			return &base.Response{
-				StatusCode: base.StatusBadRequest,
-			}, liberrors.ErrServerSDPInvalid{Err: err}
-		}
+		if ct == "application/sdp" {
+			var ssd sdp.SessionDescription
+			err = ssd.Unmarshal(req.Body)
+			if err != nil {
+				return &base.Response{
+					StatusCode: base.StatusBadRequest,
+				}, liberrors.ErrServerSDPInvalid{Err: err}
+			}
 
-		var desc description.Session
-		err = desc.Unmarshal(&ssd)
-		if err != nil {
-			return &base.Response{
-				StatusCode: base.StatusBadRequest,
-			}, liberrors.ErrServerSDPInvalid{Err: err}
+			desc = &description.Session{}
+			err = desc.Unmarshal(&ssd)
+			if err != nil {
+				return &base.Response{
+					StatusCode: base.StatusBadRequest,
+				}, liberrors.ErrServerSDPInvalid{Err: err}
+			}
+		} else {
+			h, ok := ss.s.Handler.(ServerHandlerOnAnnounceBody)
+			if !ok {
+				return &base.Response{
+					StatusCode: base.StatusUnsupportedMediaType,
+				}, liberrors.ErrServerContentTypeUnsupported{CT: req.Header["Content-Type"]}
+			}
+
+			desc, err = h.OnAnnounceBody(&ServerHandlerOnAnnounceBodyCtx{
+				Session:     ss,
+				Conn:        sc,
+				Request:     req,
+				Path:        path,
+				Query:       query,
+				ContentType: ct,
+			})
+			if err != nil {
+				return &base.Response{
+					StatusCode: base.StatusBadRequest,
+				}, err
+			}
 		}
 
 		res, err := ss.s.Handler.(ServerHandlerOnAnnounce).OnAnnounce(&ServerHandlerOnAnnounceCtx{
@@ -867,17 +1173,17 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			Request:     req,
 			Path:        path,
 			Query:       query,
-			Description: &desc,
+			Description: desc,
 		})
 
 		if res.StatusCode != base.StatusOK {
 			return res, err
 		}
 
-		ss.state = ServerSessionStatePreRecord
+		ss.setState(ServerSessionStatePreRecord)
 		ss.setuppedPath = path
 		ss.setuppedQuery = query
-		ss.announcedDesc = &desc
+		ss.announcedDesc = desc
 
 		return res, err
 
@@ -995,12 +1301,13 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 		}
 
 		res, stream, err := ss.s.Handler.(ServerHandlerOnSetup).OnSetup(&ServerHandlerOnSetupCtx{
-			Session:   ss,
-			Conn:      sc,
-			Request:   req,
-			Path:      path,
-			Query:     query,
-			Transport: transport,
+			Session:         ss,
+			Conn:            sc,
+			Request:         req,
+			Path:            path,
+			Query:           query,
+			Transport:       transport,
+			TransportHeader: inTH,
 		})
 
 		// workaround to prevent a bug in rtspclientsink
@@ -1039,6 +1346,13 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			}, liberrors.ErrServerMediaAlreadySetup{}
 		}
 
+		if transport == TransportUDP && ss.s.SecurityOptions.TLSConfig != nil &&
+			!ss.s.SecurityOptions.UnencryptedMedia(medi) {
+			return &base.Response{
+				StatusCode: base.StatusUnsupportedTransport,
+			}, nil
+		}
+
 		ss.setuppedTransport = &transport
 
 		if ss.state == ServerSessionStateInitial {
@@ -1051,7 +1365,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 				}, err
 			}
 
-			ss.state = ServerSessionStatePrePlay
+			ss.setState(ServerSessionStatePrePlay)
 			ss.setuppedPath = path
 			ss.setuppedQuery = query
 			ss.setuppedStream = stream
@@ -1073,6 +1387,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 		sm := &serverSessionMedia{
 			ss:           ss,
 			media:        medi,
+			activity:     ss.s.MediaActivityOptions.MediaActivity(medi),
 			onPacketRTCP: func(_ rtcp.Packet) {},
 		}
 		sm.initialize()
@@ -1108,7 +1423,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			th.TTL = &v
 			d := stream.medias[medi].multicastWriter.ip()
 			th.Destination = &d
-			th.Ports = &[2]int{ss.s.MulticastRTPPort, ss.s.MulticastRTCPPort}
+			th.Ports = &[2]int{ss.s.TransportOptions.MulticastRTPPort, ss.s.TransportOptions.MulticastRTCPPort}
 
 		default: // TCP
 			if inTH.InterleavedIDs != nil {
@@ -1176,7 +1491,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			return res, err
 		}
 
-		ss.state = ServerSessionStatePlay
+		ss.setState(ServerSessionStatePlay)
 
 		v := ss.s.timeNow().Unix()
 		ss.udpLastPacketTime = &v
@@ -1223,6 +1538,15 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			res.Header["RTP-Info"] = rtpInfo.Marshal()
 		}
 
+		if _, ok := res.Header["Range"]; !ok {
+			if ra, ok2 := ss.setuppedStream.playRange(req); ok2 {
+				if res.Header == nil {
+					res.Header = make(base.Header)
+				}
+				res.Header["Range"] = ra.Marshal()
+			}
+		}
+
 		return res, err
 
 	case base.Record:
@@ -1262,7 +1586,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			return res, err
 		}
 
-		ss.state = ServerSessionStateRecord
+		ss.setState(ServerSessionStateRecord)
 
 		v := ss.s.timeNow().Unix()
 		ss.udpLastPacketTime = &v
@@ -1270,6 +1594,11 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 		ss.timeDecoder = rtptime.NewGlobalDecoder2()
 
 		for _, sm := range ss.setuppedMedias {
+			if sm.activity == MediaActivitySparse {
+				for _, sf := range sm.formats {
+					ss.timeDecoder.SetSparse(sf.format)
+				}
+			}
 			sm.start()
 		}
 
@@ -1332,7 +1661,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 
 			switch ss.state {
 			case ServerSessionStatePlay:
-				ss.state = ServerSessionStatePrePlay
+				ss.setState(ServerSessionStatePrePlay)
 
 				switch *ss.setuppedTransport {
 				case TransportUDP:
@@ -1356,7 +1685,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 					ss.tcpConn = nil
 				}
 
-				ss.state = ServerSessionStatePreRecord
+				ss.setState(ServerSessionStatePreRecord)
 			}
 		}
 
@@ -1374,6 +1703,26 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 		}, err
 
 	case base.GetParameter:
+		if h, ok := sc.s.Handler.(ServerHandlerOnGetParameters); ok && isParametersBody(req) {
+			params := unmarshalParameters(req.Body)
+			if len(params) == 0 {
+				return keepaliveResponse(), nil
+			}
+
+			res, values, err := h.OnGetParameters(&ServerHandlerOnGetParametersCtx{
+				Session:    ss,
+				Conn:       sc,
+				Request:    req,
+				Path:       path,
+				Query:      query,
+				Parameters: params,
+			})
+			if err != nil {
+				return res, err
+			}
+			return parametersResponse(res, values), nil
+		}
+
 		if h, ok := sc.s.Handler.(ServerHandlerOnGetParameter); ok {
 			return h.OnGetParameter(&ServerHandlerOnGetParameterCtx{
 				Session: ss,
@@ -1386,15 +1735,20 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 
 		// GET_PARAMETER is used like a ping when reading, and sometimes
 		// also when publishing; reply with 200
-		return &base.Response{
-			StatusCode: base.StatusOK,
-			Header: base.Header{
-				"Content-Type": base.HeaderValue{"text/parameters"},
-			},
-			Body: []byte{},
-		}, nil
+		return keepaliveResponse(), nil
 
 	case base.SetParameter:
+		if h, ok := sc.s.Handler.(ServerHandlerOnSetParameters); ok && isParametersBody(req) {
+			return h.OnSetParameters(&ServerHandlerOnSetParametersCtx{
+				Session:    ss,
+				Conn:       sc,
+				Request:    req,
+				Path:       path,
+				Query:      query,
+				Parameters: unmarshalParameters(req.Body),
+			})
+		}
+
 		if h, ok := sc.s.Handler.(ServerHandlerOnSetParameter); ok {
 			return h.OnSetParameter(&ServerHandlerOnSetParameterCtx{
 				Session: ss,
@@ -1420,6 +1774,17 @@ func (ss *ServerSession) isChannelPairInUse(channel int) bool {
 	return false
 }
 
+// timeout of read operations of sessions that are publishing.
+// sessions that are publishing sparse medias only use a longer timeout.
+func (ss *ServerSession) readTimeout() time.Duration {
+	for _, sm := range ss.setuppedMedias {
+		if sm.activity == MediaActivityContinuous {
+			return ss.s.limits.Load().ReadTimeout
+		}
+	}
+	return ss.s.MediaActivityOptions.SparseReadTimeout
+}
+
 func (ss *ServerSession) findFreeChannelPair() int {
 	for i := 0; ; i += 2 { // prefer even channels
 		if !ss.isChannelPairInUse(i) {
@@ -1457,15 +1822,31 @@ func (ss *ServerSession) OnPacketRTP(medi *description.Media, forma format.Forma
 	st.onPacketRTP = cb
 }
 
+// RetainPacketRTP prevents a RTP packet from being reused after the OnPacketRTP callback returns.
+// It is needed only when Server.MemoryOptions.PoolPackets is true, and must be called inside the callback.
+func (ss *ServerSession) RetainPacketRTP(medi *description.Media, pkt *rtp.Packet) {
+	sm := ss.setuppedMedias[medi]
+	sm.packets.retain(pkt)
+}
+
 // OnPacketRTCP sets a callback that is called when a RTCP packet is read.
 func (ss *ServerSession) OnPacketRTCP(medi *description.Media, cb OnPacketRTCPFunc) {
 	sm := ss.setuppedMedias[medi]
 	sm.onPacketRTCP = cb
 }
 
-func (ss *ServerSession) writePacketRTP(medi *description.Media, payloadType uint8, byts []byte) error {
+func (ss *ServerSession) writePacketRTP(medi *description.Media, pkt *rtp.Packet, byts []byte) error {
+	return ss.writePacketsRTP(medi, []*rtp.Packet{pkt}, [][]byte{byts}, nil)
+}
+
+func (ss *ServerSession) writePacketsRTP(
+	medi *description.Media,
+	pkts []*rtp.Packet,
+	byts [][]byte,
+	shared *sharedPacketBuffers,
+) error {
 	sm := ss.setuppedMedias[medi]
-	sf := sm.formats[payloadType]
+	sf := sm.formats[pkts[0].PayloadType]
 
 	ss.writerMutex.RLock()
 	defer ss.writerMutex.RUnlock()
@@ -1474,17 +1855,15 @@ func (ss *ServerSession) writePacketRTP(medi *description.Media, payloadType uin
 		return nil
 	}
 
-	ok := ss.writer.push(func() error {
-		return sf.writePacketRTPInQueue(byts)
-	})
-	if !ok {
-		return liberrors.ErrServerWriteQueueFull{}
-	}
+	sm.writeMutex.Lock()
+	defer sm.writeMutex.Unlock()
 
-	return nil
+	return sf.writePacketsRTP(pkts, byts, shared)
 }
 
 // WritePacketRTP writes a RTP packet to the session.
+// It can be called concurrently with other writes,
+// and writes to the same media are sent in the same order in which calls are made.
 func (ss *ServerSession) WritePacketRTP(medi *description.Media, pkt *rtp.Packet) error {
 	byts := make([]byte, ss.s.MaxPacketSize)
 	n, err := pkt.MarshalTo(byts)
@@ -1493,7 +1872,7 @@ func (ss *ServerSession) WritePacketRTP(medi *description.Media, pkt *rtp.Packet
 	}
 	byts = byts[:n]
 
-	return ss.writePacketRTP(medi, pkt.PayloadType, byts)
+	return ss.writePacketRTP(medi, pkt, byts)
 }
 
 func (ss *ServerSession) writePacketRTCP(medi *description.Media, byts []byte) error {
@@ -1506,10 +1885,28 @@ func (ss *ServerSession) writePacketRTCP(medi *description.Media, byts []byte) e
 		return nil
 	}
 
+	sm.writeMutex.Lock()
+	defer sm.writeMutex.Unlock()
+
 	ok := ss.writer.push(func() error {
 		return sm.writePacketRTCPInQueue(byts)
 	})
 	if !ok {
+		dropped := atomic.AddUint64(sm.rtcpPacketsDropped, 1)
+		ss.s.ObservabilityOptions.Metrics.AddPacketsDropped(1)
+
+		if h, ok2 := ss.s.Handler.(ServerHandlerOnReaderCongestion); ok2 {
+			h.OnReaderCongestion(&ServerHandlerOnReaderCongestionCtx{
+				Session:        ss,
+				Media:          medi,
+				QueueSize:      ss.writer.bufferSize,
+				PacketsDropped: dropped,
+			})
+		} else {
+			ss.s.ObservabilityOptions.Logger.Debug(liberrors.ErrServerWriteQueueFull{}.Error(),
+				"session", ss.id, "dropped", dropped)
+		}
+
 		return liberrors.ErrServerWriteQueueFull{}
 	}
 
@@ -1534,7 +1931,7 @@ func (ss *ServerSession) PacketPTS(medi *description.Media, pkt *rtp.Packet) (ti
 	sm := ss.setuppedMedias[medi]
 	sf := sm.formats[pkt.PayloadType]
 
-	v, ok := ss.timeDecoder.Decode(sf.format, pkt)
+	v, ok := sf.decodePTS(pkt)
 	if !ok {
 		return 0, false
 	}
@@ -1547,17 +1944,79 @@ func (ss *ServerSession) PacketPTS(medi *description.Media, pkt *rtp.Packet) (ti
 func (ss *ServerSession) PacketPTS2(medi *description.Media, pkt *rtp.Packet) (int64, bool) {
 	sm := ss.setuppedMedias[medi]
 	sf := sm.formats[pkt.PayloadType]
-	return ss.timeDecoder.Decode(sf.format, pkt)
+	return sf.decodePTS(pkt)
+}
+
+// PacketDTS returns the DTS of an incoming RTP packet.
+// With H264 and H265, DTS is estimated from the picture order count, in order to support B-frames;
+// this must be called with every packet of the format, in order,
+// and DTS is returned with the last packet of each access unit only.
+// With other formats, DTS is equal to PTS.
+func (ss *ServerSession) PacketDTS(medi *description.Media, pkt *rtp.Packet) (int64, bool) {
+	sm := ss.setuppedMedias[medi]
+	sf := sm.formats[pkt.PayloadType]
+
+	if sf.dtsEstimator == nil {
+		return 0, false
+	}
+
+	pts, ok := sf.decodePTS(pkt)
+	if !ok {
+		return 0, false
+	}
+
+	return sf.dtsEstimator.Estimate(pkt, pts)
+}
+
+// AccessUnitDTS returns the DTS of an access unit that has been decoded from incoming RTP packets,
+// where pkt is the last packet of the access unit.
+// It allows to avoid decoding packets twice when the caller already decodes them.
+// With H264 and H265, this must be called with every access unit of the format, in order,
+// and must not be used together with PacketDTS.
+// With other formats, DTS is equal to PTS.
+func (ss *ServerSession) AccessUnitDTS(medi *description.Media, pkt *rtp.Packet, au [][]byte) (int64, bool) {
+	sm := ss.setuppedMedias[medi]
+	sf := sm.formats[pkt.PayloadType]
+
+	if sf.dtsEstimator == nil {
+		return 0, false
+	}
+
+	pts, ok := sf.decodePTS(pkt)
+	if !ok {
+		return 0, false
+	}
+
+	return sf.dtsEstimator.EstimateAccessUnit(au, pts)
 }
 
 // PacketNTP returns the NTP timestamp of an incoming RTP packet.
-// The NTP timestamp is computed from RTCP sender reports.
+// The NTP timestamp is read from the ONVIF replay extension, if present,
+// otherwise it is computed from RTCP sender reports.
 func (ss *ServerSession) PacketNTP(medi *description.Media, pkt *rtp.Packet) (time.Time, bool) {
+	if ntp, ok := packetNTPFromExtension(pkt); ok {
+		return ntp, true
+	}
+
 	sm := ss.setuppedMedias[medi]
 	sf := sm.formats[pkt.PayloadType]
 	return sf.rtcpReceiver.PacketNTP(pkt.Timestamp)
 }
 
+// PacketAbsSendTime returns the send time of an incoming RTP packet,
+// read from the abs-send-time extension.
+// The extension must be declared in the media with an extmap attribute.
+func (ss *ServerSession) PacketAbsSendTime(medi *description.Media, pkt *rtp.Packet) (time.Time, bool) {
+	return packetAbsSendTime(medi, pkt, ss.s.timeNow())
+}
+
+// PacketTransmissionOffset returns the transmission time offset of an incoming RTP packet,
+// in clock rate units, read from the toffset extension.
+// The extension must be declared in the media with an extmap attribute.
+func (ss *ServerSession) PacketTransmissionOffset(medi *description.Media, pkt *rtp.Packet) (int32, bool) {
+	return packetTransmissionOffset(medi, pkt)
+}
+
 func (ss *ServerSession) handleRequest(req sessionRequestReq) (*base.Response, *ServerSession, error) {
 	select {
 	case ss.chHandleRequest <- req:
@@ -1578,6 +2037,35 @@ func (ss *ServerSession) removeConn(sc *ServerConn) {
 	}
 }
 
+func (ss *ServerSession) doRedirect(u *base.URL, ra *headers.Range) error {
+	sc := ss.tcpConn
+	if sc == nil {
+		for c := range ss.conns {
+			sc = c
+			break
+		}
+	}
+
+	if sc == nil {
+		return liberrors.ErrServerSessionNotConnected{}
+	}
+
+	req := &base.Request{
+		Method: base.Redirect,
+		URL:    u,
+		Header: base.Header{
+			"Session":  base.HeaderValue{ss.secretID},
+			"Location": base.HeaderValue{u.String()},
+		},
+	}
+
+	if ra != nil {
+		req.Header["Range"] = ra.Marshal()
+	}
+
+	return sc.writeServerRequest(req)
+}
+
 func (ss *ServerSession) asyncStartWriter() {
 	select {
 	case ss.chAsyncStartWriter <- struct{}{}:
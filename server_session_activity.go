@@ -0,0 +1,13 @@
+package gortsplib
+
+// ServerSessionActivity is a set of events that keep alive sessions that are reading.
+type ServerSessionActivity int
+
+// activities.
+const (
+	// incoming RTSP requests, including keepalives.
+	ServerSessionActivityRequests ServerSessionActivity = 1 << iota
+
+	// incoming RTP and RTCP packets, including RTCP receiver reports.
+	ServerSessionActivityPackets
+)
@@ -1,16 +1,20 @@
 package gortsplib
 
 import (
-	"log"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/bluenviron/mediacommon/pkg/codecs/h264"
+	"github.com/bluenviron/mediacommon/pkg/codecs/h265"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 
+	"github.com/bluenviron/gortsplib/v4/internal/dtsestimator"
 	"github.com/bluenviron/gortsplib/v4/internal/rtcpreceiver"
 	"github.com/bluenviron/gortsplib/v4/internal/rtplossdetector"
 	"github.com/bluenviron/gortsplib/v4/internal/rtpreorderer"
+	"github.com/bluenviron/gortsplib/v4/pkg/corruptiondetector"
 	"github.com/bluenviron/gortsplib/v4/pkg/format"
 	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
 )
@@ -23,19 +27,55 @@ type serverSessionFormat struct {
 	udpReorderer          *rtpreorderer.Reorderer
 	tcpLossDetector       *rtplossdetector.LossDetector
 	rtcpReceiver          *rtcpreceiver.RTCPReceiver
-	writePacketRTPInQueue func([]byte) error
+	dtsEstimator          *dtsestimator.Estimator
+	ptsPacket             *rtp.Packet
+	ptsSequenceNumber     uint16
+	pts                   int64
+	ptsOK                 bool
+	writePacketRTPInQueue func([]byte, bool) error
+	udpPending            [][]byte
 	rtpPacketsReceived    *uint64
 	rtpPacketsSent        *uint64
+	rtpPacketsInError     *uint64
 	rtpPacketsLost        *uint64
+	rtpPacketsDropped     *uint64
+	accessUnitsReceived   *uint64
+	accessUnitsCorrupted  *uint64
+	corruptionDetector    *corruptiondetector.Detector
+	remoteSDES            sourceDescription
+	congested             bool
+	accessUnitStart       bool
+	randomAccessPkts      []*rtp.Packet
+	randomAccessByts      [][]byte
 }
 
 func (sf *serverSessionFormat) initialize() {
 	sf.rtpPacketsReceived = new(uint64)
 	sf.rtpPacketsSent = new(uint64)
+	sf.rtpPacketsInError = new(uint64)
 	sf.rtpPacketsLost = new(uint64)
+	sf.rtpPacketsDropped = new(uint64)
+	sf.accessUnitsReceived = new(uint64)
+	sf.accessUnitsCorrupted = new(uint64)
+}
+
+// decodePTS decodes the PTS of an incoming RTP packet.
+// The result of the last packet is cached, in order to decode packets once
+// when both PacketPTS2 and PacketDTS are called.
+func (sf *serverSessionFormat) decodePTS(pkt *rtp.Packet) (int64, bool) {
+	if pkt != sf.ptsPacket || pkt.SequenceNumber != sf.ptsSequenceNumber {
+		sf.pts, sf.ptsOK = sf.sm.ss.timeDecoder.Decode(sf.format, pkt)
+		sf.ptsPacket = pkt
+		sf.ptsSequenceNumber = pkt.SequenceNumber
+	}
+	return sf.pts, sf.ptsOK
 }
 
 func (sf *serverSessionFormat) start() {
+	sf.congested = false
+	sf.accessUnitStart = true
+	sf.udpPending = nil
+
 	switch *sf.sm.ss.setuppedTransport {
 	case TransportUDP, TransportUDPMulticast:
 		sf.writePacketRTPInQueue = sf.writePacketRTPInQueueUDP
@@ -46,7 +86,9 @@ func (sf *serverSessionFormat) start() {
 
 	if sf.sm.ss.state != ServerSessionStatePlay {
 		if *sf.sm.ss.setuppedTransport == TransportUDP || *sf.sm.ss.setuppedTransport == TransportUDPMulticast {
-			sf.udpReorderer = &rtpreorderer.Reorderer{}
+			sf.udpReorderer = &rtpreorderer.Reorderer{
+				OnDiscard: sf.sm.packets.release,
+			}
 			sf.udpReorderer.Initialize()
 		} else {
 			sf.tcpLossDetector = &rtplossdetector.LossDetector{}
@@ -66,6 +108,39 @@ func (sf *serverSessionFormat) start() {
 		if err != nil {
 			panic(err)
 		}
+
+		sf.dtsEstimator = &dtsestimator.Estimator{
+			Format:       sf.format,
+			CopyPayloads: sf.sm.ss.s.MemoryOptions.PoolPackets,
+		}
+		err = sf.dtsEstimator.Initialize()
+		if err != nil {
+			sf.dtsEstimator = nil
+		}
+
+		if sf.sm.ss.s.SessionOptions.DetectCorruption {
+			sf.corruptionDetector = &corruptiondetector.Detector{
+				Format: sf.format,
+				OnAccessUnit: func(_ uint32, corrupted bool) {
+					atomic.AddUint64(sf.accessUnitsReceived, 1)
+					if corrupted {
+						atomic.AddUint64(sf.accessUnitsCorrupted, 1)
+					}
+				},
+				OnGOP: func(st corruptiondetector.GOPStats) {
+					if st.CorruptedAccessUnits != 0 {
+						sf.onCorruptedGOP(liberrors.ErrServerCorruptedGOP{
+							AccessUnits:          st.AccessUnits,
+							CorruptedAccessUnits: st.CorruptedAccessUnits,
+						})
+					}
+				},
+			}
+			err = sf.corruptionDetector.Initialize()
+			if err != nil {
+				sf.corruptionDetector = nil
+			}
+		}
 	}
 }
 
@@ -101,19 +176,29 @@ func (sf *serverSessionFormat) readPacketRTPTCP(pkt *rtp.Packet) {
 }
 
 func (sf *serverSessionFormat) handlePacketRTP(pkt *rtp.Packet, now time.Time) {
+	defer sf.sm.packets.release(pkt)
+
 	err := sf.rtcpReceiver.ProcessPacketRTP(pkt, now, sf.format.PTSEqualsDTS(pkt))
 	if err != nil {
+		atomic.AddUint64(sf.rtpPacketsInError, 1)
 		sf.sm.onPacketRTPDecodeError(err)
 		return
 	}
 
 	atomic.AddUint64(sf.rtpPacketsReceived, 1)
+	atomic.AddUint64(&sf.sm.transportStats.rtpPacketsReceived, 1)
+	sf.sm.rates.addReceived(now, pkt)
+
+	if sf.corruptionDetector != nil {
+		sf.corruptionDetector.Process(pkt)
+	}
 
 	sf.onPacketRTP(pkt)
 }
 
 func (sf *serverSessionFormat) onPacketRTPLost(lost uint) {
 	atomic.AddUint64(sf.rtpPacketsLost, uint64(lost))
+	sf.sm.ss.s.ObservabilityOptions.Metrics.AddErrors(MetricsErrorTypeRTPPacketsLost, uint64(lost))
 
 	if h, ok := sf.sm.ss.s.Handler.(ServerHandlerOnPacketLost); ok {
 		h.OnPacketLost(&ServerHandlerOnPacketLostCtx{
@@ -121,31 +206,275 @@ func (sf *serverSessionFormat) onPacketRTPLost(lost uint) {
 			Error:   liberrors.ErrServerRTPPacketsLost{Lost: lost},
 		})
 	} else {
-		log.Println(liberrors.ErrServerRTPPacketsLost{Lost: lost}.Error())
+		sf.sm.ss.s.ObservabilityOptions.Logger.Warn(liberrors.ErrServerRTPPacketsLost{Lost: lost}.Error(), "session", sf.sm.ss.id)
 	}
 }
 
-func (sf *serverSessionFormat) writePacketRTPInQueueUDP(payload []byte) error {
-	err := sf.sm.ss.s.udpRTPListener.write(payload, sf.sm.udpRTPWriteAddr)
+// isRandomAccess checks whether packets of an access unit contain a random access point.
+// Formats that don't provide random access points are always considered random access.
+func isRandomAccess(forma format.Format, pkts []*rtp.Packet) bool {
+	switch forma := forma.(type) {
+	case *format.H264:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return false
+		}
+
+		for _, pkt := range pkts {
+			var au [][]byte
+			au, err = dec.Decode(pkt)
+			if err == nil {
+				return h264.IDRPresent(au)
+			}
+		}
+		return false
+
+	case *format.H265:
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return false
+		}
+
+		for _, pkt := range pkts {
+			var au [][]byte
+			au, err = dec.Decode(pkt)
+			if err == nil {
+				return h265.IsRandomAccess(au)
+			}
+		}
+		return false
+
+	default:
+		return true
+	}
+}
+
+// writePacketsRTP writes packets of a single access unit.
+// Either all packets are enqueued or none of them is.
+// shared buffers, if present, are released once all packets have been written.
+func (sf *serverSessionFormat) writePacketsRTP(pkts []*rtp.Packet, byts [][]byte, shared *sharedPacketBuffers) error {
+	accessUnitStart := sf.accessUnitStart
+	sf.accessUnitStart = pkts[len(pkts)-1].Marker
+
+	if sf.congested {
+		switch sf.sm.ss.s.SessionOptions.CongestionPolicy {
+		case ServerCongestionPolicyDropAccessUnits:
+			sf.congested = !accessUnitStart
+
+		case ServerCongestionPolicyDropUntilRandomAccess:
+			return sf.writePacketsRTPUntilRandomAccess(accessUnitStart, pkts, byts)
+		}
+
+		if sf.congested {
+			sf.dropPacketsRTP(len(pkts))
+			return nil
+		}
+	}
+
+	return sf.enqueuePacketsRTP(pkts, byts, shared)
+}
+
+func (sf *serverSessionFormat) dropPacketsRTP(n int) {
+	atomic.AddUint64(sf.rtpPacketsDropped, uint64(n))
+	sf.sm.ss.s.ObservabilityOptions.Metrics.AddPacketsDropped(uint64(n))
+}
+
+// writePacketsRTPUntilRandomAccess is called when the reader is congested.
+// Packets of each access unit are copied and buffered until the access unit is complete,
+// then they are written if the access unit is a random access point, otherwise they are dropped.
+func (sf *serverSessionFormat) writePacketsRTPUntilRandomAccess(
+	accessUnitStart bool,
+	pkts []*rtp.Packet,
+	byts [][]byte,
+) error {
+	if accessUnitStart {
+		sf.dropPacketsRTP(len(sf.randomAccessPkts))
+		sf.randomAccessPkts = nil
+		sf.randomAccessByts = nil
+	} else if sf.randomAccessPkts == nil {
+		// access unit started while the reader was congested
+		sf.dropPacketsRTP(len(pkts))
+		return nil
+	}
+
+	for _, b := range byts {
+		buf := append([]byte(nil), b...)
+
+		var pkt rtp.Packet
+		err := pkt.Unmarshal(buf)
+		if err != nil {
+			return err
+		}
+
+		sf.randomAccessPkts = append(sf.randomAccessPkts, &pkt)
+		sf.randomAccessByts = append(sf.randomAccessByts, buf)
+	}
+
+	if !pkts[len(pkts)-1].Marker {
+		return nil
+	}
+
+	pendingPkts := sf.randomAccessPkts
+	pendingByts := sf.randomAccessByts
+	sf.randomAccessPkts = nil
+	sf.randomAccessByts = nil
+
+	if !isRandomAccess(sf.format, pendingPkts) {
+		sf.dropPacketsRTP(len(pendingPkts))
+		return nil
+	}
+
+	sf.congested = false
+
+	return sf.enqueuePacketsRTP(pendingPkts, pendingByts, nil)
+}
+
+// serverSessionPacket is a RTP packet in the write queue of a session.
+// It is taken from a pool, in order to avoid allocating a closure for each packet.
+type serverSessionPacket struct {
+	sf     *serverSessionFormat
+	byts   []byte
+	last   bool
+	shared *sharedPacketBuffers
+}
+
+var serverSessionPacketPool = sync.Pool{
+	New: func() interface{} {
+		return &serverSessionPacket{}
+	},
+}
+
+func (p *serverSessionPacket) put() {
+	*p = serverSessionPacket{}
+	serverSessionPacketPool.Put(p)
+}
+
+func (p *serverSessionPacket) run() error {
+	sf, byts, last, shared := p.sf, p.byts, p.last, p.shared
+	p.put()
+
+	if last {
+		defer shared.release()
+	}
+	return sf.writePacketRTPInQueue(byts, last)
+}
+
+func (sf *serverSessionFormat) enqueuePacketsRTP(pkts []*rtp.Packet, byts [][]byte, shared *sharedPacketBuffers) error {
+	// access units usually fit into this array, that is allocated on the stack.
+	var entriesBuf [16]interface{}
+	entries := entriesBuf[:0]
+
+	for i, b := range byts {
+		p := serverSessionPacketPool.Get().(*serverSessionPacket)
+		p.sf = sf
+		p.byts = b
+		p.last = (i == len(byts)-1)
+		p.shared = shared
+		entries = append(entries, p)
+	}
+
+	shared.retain()
+
+	ok := sf.sm.ss.writer.pushMultiple(entries)
+	if !ok {
+		shared.release()
+
+		for _, entry := range entries {
+			entry.(*serverSessionPacket).put()
+		}
+
+		dropped := atomic.AddUint64(sf.rtpPacketsDropped, uint64(len(pkts)))
+		sf.sm.ss.s.ObservabilityOptions.Metrics.AddPacketsDropped(uint64(len(pkts)))
+
+		if h, ok2 := sf.sm.ss.s.Handler.(ServerHandlerOnReaderCongestion); ok2 {
+			h.OnReaderCongestion(&ServerHandlerOnReaderCongestionCtx{
+				Session:        sf.sm.ss,
+				Media:          sf.sm.media,
+				Format:         sf.format,
+				QueueSize:      sf.sm.ss.writer.bufferSize,
+				PacketsDropped: dropped,
+			})
+		} else {
+			sf.sm.ss.s.ObservabilityOptions.Logger.Debug(liberrors.ErrServerWriteQueueFull{}.Error(),
+				"session", sf.sm.ss.id, "dropped", dropped)
+		}
+
+		if sf.sm.ss.s.SessionOptions.CongestionPolicy != ServerCongestionPolicyDropPackets {
+			sf.congested = true
+		}
+
+		return liberrors.ErrServerWriteQueueFull{}
+	}
+
+	return nil
+}
+
+// writePacketRTPInQueueUDP collects packets of an access unit
+// and writes them together once the last one is received.
+func (sf *serverSessionFormat) writePacketRTPInQueueUDP(payload []byte, last bool) error {
+	sf.udpPending = append(sf.udpPending, payload)
+	if !last {
+		return nil
+	}
+
+	pending := sf.udpPending
+	defer func() {
+		for i := range pending {
+			pending[i] = nil
+		}
+		sf.udpPending = pending[:0]
+	}()
+
+	err := sf.sm.ss.s.udpRTPListener.writeMultiple(pending, sf.sm.udpRTPWriteAddr)
 	if err != nil {
 		return err
 	}
 
-	atomic.AddUint64(sf.sm.bytesSent, uint64(len(payload)))
-	atomic.AddUint64(sf.rtpPacketsSent, 1)
+	now := sf.sm.ss.s.timeNow()
+
+	for _, b := range pending {
+		sf.sm.rates.addSent(now, b)
+		atomic.AddUint64(sf.sm.bytesSent, uint64(len(b)))
+		sf.sm.ss.s.ObservabilityOptions.Metrics.AddBytesSent(uint64(len(b)))
+		atomic.AddUint64(&sf.sm.transportStats.bytesSent, uint64(len(b)))
+		sf.sm.dumpSent(false, b)
+	}
+	atomic.AddUint64(sf.rtpPacketsSent, uint64(len(pending)))
+	atomic.AddUint64(&sf.sm.transportStats.rtpPacketsSent, uint64(len(pending)))
 	return nil
 }
 
-func (sf *serverSessionFormat) writePacketRTPInQueueTCP(payload []byte) error {
+func (sf *serverSessionFormat) writePacketRTPInQueueTCP(payload []byte, _ bool) error {
+	err := sf.sm.ss.waitPacer(len(payload))
+	if err != nil {
+		return err
+	}
+
 	sf.sm.ss.tcpFrame.Channel = sf.sm.tcpChannel
 	sf.sm.ss.tcpFrame.Payload = payload
-	sf.sm.ss.tcpConn.nconn.SetWriteDeadline(time.Now().Add(sf.sm.ss.s.WriteTimeout))
-	err := sf.sm.ss.tcpConn.conn.WriteInterleavedFrame(sf.sm.ss.tcpFrame, sf.sm.ss.tcpBuffer)
+	sf.sm.ss.tcpConn.nconn.SetWriteDeadline(time.Now().Add(sf.sm.ss.s.limits.Load().WriteTimeout))
+	err = sf.sm.ss.tcpConn.conn.WriteInterleavedFrame(sf.sm.ss.tcpFrame, sf.sm.ss.tcpBuffer)
 	if err != nil {
 		return err
 	}
 
 	atomic.AddUint64(sf.sm.bytesSent, uint64(len(payload)))
+	sf.sm.ss.s.ObservabilityOptions.Metrics.AddBytesSent(uint64(len(payload)))
 	atomic.AddUint64(sf.rtpPacketsSent, 1)
+	atomic.AddUint64(&sf.sm.transportStats.bytesSent, uint64(len(payload)))
+	atomic.AddUint64(&sf.sm.transportStats.rtpPacketsSent, 1)
+	sf.sm.rates.addSent(sf.sm.ss.s.timeNow(), payload)
+	sf.sm.dumpSent(false, payload)
 	return nil
 }
+
+func (sf *serverSessionFormat) onCorruptedGOP(err error) {
+	if h, ok := sf.sm.ss.s.Handler.(ServerHandlerOnDecodeError); ok {
+		h.OnDecodeError(&ServerHandlerOnDecodeErrorCtx{
+			Session: sf.sm.ss,
+			Error:   err,
+		})
+	} else {
+		sf.sm.ss.s.ObservabilityOptions.Logger.Warn(err.Error(), "session", sf.sm.ss.id)
+	}
+}
@@ -1,8 +1,8 @@
 package gortsplib
 
 import (
-	"log"
 	"net"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -16,6 +16,7 @@ import (
 type serverSessionMedia struct {
 	ss           *ServerSession
 	media        *description.Media
+	activity     MediaActivity
 	onPacketRTCP OnPacketRTCPFunc
 
 	tcpChannel             int
@@ -31,6 +32,15 @@ type serverSessionMedia struct {
 	rtcpPacketsReceived    *uint64
 	rtcpPacketsSent        *uint64
 	rtcpPacketsInError     *uint64
+	rtcpPacketsDropped     *uint64
+	udpPacketsTruncated    *uint64
+	transportStats         *serverTransportStats
+	packets                receivedPackets // record only
+	dumpAddrs              *packetDumpAddrs
+	rates                  *mediaRates
+
+	// serializes writes of concurrent producers.
+	writeMutex sync.Mutex
 }
 
 func (sm *serverSessionMedia) initialize() {
@@ -40,6 +50,16 @@ func (sm *serverSessionMedia) initialize() {
 	sm.rtcpPacketsReceived = new(uint64)
 	sm.rtcpPacketsSent = new(uint64)
 	sm.rtcpPacketsInError = new(uint64)
+	sm.rtcpPacketsDropped = new(uint64)
+	sm.udpPacketsTruncated = new(uint64)
+
+	sm.rates = &mediaRates{media: sm.media}
+	sm.rates.initialize()
+
+	sm.packets = receivedPackets{
+		pool: sm.ss.s.MemoryOptions.PoolPackets,
+	}
+	sm.packets.initialize()
 
 	sm.formats = make(map[uint8]*serverSessionFormat)
 
@@ -55,6 +75,8 @@ func (sm *serverSessionMedia) initialize() {
 }
 
 func (sm *serverSessionMedia) start() {
+	sm.transportStats = sm.ss.s.transportStats[*sm.ss.setuppedTransport]
+
 	// allocate udpRTCPReceiver before udpRTCPListener
 	// otherwise udpRTCPReceiver.LastSSRC() can't be called.
 	for _, sf := range sm.formats {
@@ -66,11 +88,14 @@ func (sm *serverSessionMedia) start() {
 		sm.writePacketRTCPInQueue = sm.writePacketRTCPInQueueUDP
 
 		if *sm.ss.setuppedTransport == TransportUDP {
+			sm.dumpAddrs = sm.packetDumpAddrsUDP()
+
 			if sm.ss.state == ServerSessionStatePlay {
 				// firewall opening is performed with RTCP sender reports generated by ServerStream
 
 				// readers can send RTCP packets only
-				sm.ss.s.udpRTCPListener.addClient(sm.ss.author.ip(), sm.udpRTCPReadPort, sm.readPacketRTCPUDPPlay)
+				sm.ss.s.udpRTCPListener.addClient(sm.ss.author.ip(), sm.udpRTCPReadPort,
+					sm.dumpReadFunc(true, sm.readPacketRTCPUDPPlay), sm.onPacketRTCPTruncated)
 			} else {
 				// open the firewall by sending empty packets to the counterpart.
 				byts, _ := (&rtp.Packet{Header: rtp.Header{Version: 2}}).Marshal()
@@ -79,8 +104,10 @@ func (sm *serverSessionMedia) start() {
 				byts, _ = (&rtcp.ReceiverReport{}).Marshal()
 				sm.ss.s.udpRTCPListener.write(byts, sm.udpRTCPWriteAddr) //nolint:errcheck
 
-				sm.ss.s.udpRTPListener.addClient(sm.ss.author.ip(), sm.udpRTPReadPort, sm.readPacketRTPUDPRecord)
-				sm.ss.s.udpRTCPListener.addClient(sm.ss.author.ip(), sm.udpRTCPReadPort, sm.readPacketRTCPUDPRecord)
+				sm.ss.s.udpRTPListener.addClient(sm.ss.author.ip(), sm.udpRTPReadPort,
+					sm.dumpReadFunc(false, sm.readPacketRTPUDPRecord), sm.onPacketRTPTruncated)
+				sm.ss.s.udpRTCPListener.addClient(sm.ss.author.ip(), sm.udpRTCPReadPort,
+					sm.dumpReadFunc(true, sm.readPacketRTCPUDPRecord), sm.onPacketRTCPTruncated)
 			}
 		}
 
@@ -91,12 +118,15 @@ func (sm *serverSessionMedia) start() {
 			sm.ss.tcpCallbackByChannel = make(map[int]readFunc)
 		}
 
+		sm.dumpAddrs = newPacketDumpAddrsTCP(sm.ss.author.nconn.LocalAddr(), sm.ss.author.nconn.RemoteAddr(),
+			sm.tcpChannel)
+
 		if sm.ss.state == ServerSessionStatePlay {
-			sm.ss.tcpCallbackByChannel[sm.tcpChannel] = sm.readPacketRTPTCPPlay
-			sm.ss.tcpCallbackByChannel[sm.tcpChannel+1] = sm.readPacketRTCPTCPPlay
+			sm.ss.tcpCallbackByChannel[sm.tcpChannel] = sm.dumpReadFunc(false, sm.readPacketRTPTCPPlay)
+			sm.ss.tcpCallbackByChannel[sm.tcpChannel+1] = sm.dumpReadFunc(true, sm.readPacketRTCPTCPPlay)
 		} else {
-			sm.ss.tcpCallbackByChannel[sm.tcpChannel] = sm.readPacketRTPTCPRecord
-			sm.ss.tcpCallbackByChannel[sm.tcpChannel+1] = sm.readPacketRTCPTCPRecord
+			sm.ss.tcpCallbackByChannel[sm.tcpChannel] = sm.dumpReadFunc(false, sm.readPacketRTPTCPRecord)
+			sm.ss.tcpCallbackByChannel[sm.tcpChannel+1] = sm.dumpReadFunc(true, sm.readPacketRTCPTCPRecord)
 		}
 	}
 }
@@ -122,6 +152,38 @@ func (sm *serverSessionMedia) findFormatWithSSRC(ssrc uint32) *serverSessionForm
 	return nil
 }
 
+func (sm *serverSessionMedia) packetDumpAddrsUDP() *packetDumpAddrs {
+	localIP := addrIP(sm.ss.author.nconn.LocalAddr())
+
+	return &packetDumpAddrs{
+		rtpLocal:   &net.UDPAddr{IP: localIP, Port: sm.ss.s.udpRTPListener.port()},
+		rtpRemote:  sm.udpRTPWriteAddr,
+		rtcpLocal:  &net.UDPAddr{IP: localIP, Port: sm.ss.s.udpRTCPListener.port()},
+		rtcpRemote: sm.udpRTCPWriteAddr,
+	}
+}
+
+func (sm *serverSessionMedia) dumpReadFunc(isRTCP bool, cb readFunc) readFunc {
+	return func(payload []byte) bool {
+		if d := sm.ss.packetDump.Load(); d != nil {
+			d.write(sm.ss.s.timeNow(), true, sm.dumpAddrs, isRTCP, payload)
+		}
+		return cb(payload)
+	}
+}
+
+// dumpSent writes a sent packet into the packet dump.
+// Packets sent through multicast are not written, since they are shared between sessions.
+func (sm *serverSessionMedia) dumpSent(isRTCP bool, payload []byte) {
+	if sm.dumpAddrs == nil {
+		return
+	}
+
+	if d := sm.ss.packetDump.Load(); d != nil {
+		d.write(sm.ss.s.timeNow(), false, sm.dumpAddrs, isRTCP, payload)
+	}
+}
+
 func (sm *serverSessionMedia) writePacketRTCPInQueueUDP(payload []byte) error {
 	err := sm.ss.s.udpRTCPListener.write(payload, sm.udpRTCPWriteAddr)
 	if err != nil {
@@ -129,31 +191,41 @@ func (sm *serverSessionMedia) writePacketRTCPInQueueUDP(payload []byte) error {
 	}
 
 	atomic.AddUint64(sm.bytesSent, uint64(len(payload)))
+	sm.ss.s.ObservabilityOptions.Metrics.AddBytesSent(uint64(len(payload)))
 	atomic.AddUint64(sm.rtcpPacketsSent, 1)
+	atomic.AddUint64(&sm.transportStats.bytesSent, uint64(len(payload)))
+	atomic.AddUint64(&sm.transportStats.rtcpPacketsSent, 1)
+	sm.dumpSent(true, payload)
 	return nil
 }
 
 func (sm *serverSessionMedia) writePacketRTCPInQueueTCP(payload []byte) error {
+	err := sm.ss.waitPacer(len(payload))
+	if err != nil {
+		return err
+	}
+
 	sm.ss.tcpFrame.Channel = sm.tcpChannel + 1
 	sm.ss.tcpFrame.Payload = payload
-	sm.ss.tcpConn.nconn.SetWriteDeadline(time.Now().Add(sm.ss.s.WriteTimeout))
-	err := sm.ss.tcpConn.conn.WriteInterleavedFrame(sm.ss.tcpFrame, sm.ss.tcpBuffer)
+	sm.ss.tcpConn.nconn.SetWriteDeadline(time.Now().Add(sm.ss.s.limits.Load().WriteTimeout))
+	err = sm.ss.tcpConn.conn.WriteInterleavedFrame(sm.ss.tcpFrame, sm.ss.tcpBuffer)
 	if err != nil {
 		return err
 	}
 
 	atomic.AddUint64(sm.bytesSent, uint64(len(payload)))
+	sm.ss.s.ObservabilityOptions.Metrics.AddBytesSent(uint64(len(payload)))
 	atomic.AddUint64(sm.rtcpPacketsSent, 1)
+	atomic.AddUint64(&sm.transportStats.bytesSent, uint64(len(payload)))
+	atomic.AddUint64(&sm.transportStats.rtcpPacketsSent, 1)
+	sm.dumpSent(true, payload)
 	return nil
 }
 
 func (sm *serverSessionMedia) readPacketRTCPUDPPlay(payload []byte) bool {
 	atomic.AddUint64(sm.bytesReceived, uint64(len(payload)))
-
-	if len(payload) == (udpMaxPayloadSize + 1) {
-		sm.onPacketRTCPDecodeError(liberrors.ErrServerRTCPPacketTooBigUDP{})
-		return false
-	}
+	sm.ss.s.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
+	atomic.AddUint64(&sm.transportStats.bytesReceived, uint64(len(payload)))
 
 	packets, err := rtcp.Unmarshal(payload)
 	if err != nil {
@@ -165,6 +237,7 @@ func (sm *serverSessionMedia) readPacketRTCPUDPPlay(payload []byte) bool {
 	atomic.StoreInt64(sm.ss.udpLastPacketTime, now.Unix())
 
 	atomic.AddUint64(sm.rtcpPacketsReceived, uint64(len(packets)))
+	atomic.AddUint64(&sm.transportStats.rtcpPacketsReceived, uint64(len(packets)))
 
 	for _, pkt := range packets {
 		sm.onPacketRTCP(pkt)
@@ -175,14 +248,10 @@ func (sm *serverSessionMedia) readPacketRTCPUDPPlay(payload []byte) bool {
 
 func (sm *serverSessionMedia) readPacketRTPUDPRecord(payload []byte) bool {
 	atomic.AddUint64(sm.bytesReceived, uint64(len(payload)))
+	sm.ss.s.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
+	atomic.AddUint64(&sm.transportStats.bytesReceived, uint64(len(payload)))
 
-	if len(payload) == (udpMaxPayloadSize + 1) {
-		sm.onPacketRTPDecodeError(liberrors.ErrServerRTPPacketTooBigUDP{})
-		return false
-	}
-
-	pkt := &rtp.Packet{}
-	err := pkt.Unmarshal(payload)
+	pkt, err := sm.packets.decode(payload)
 	if err != nil {
 		sm.onPacketRTPDecodeError(err)
 		return false
@@ -190,6 +259,7 @@ func (sm *serverSessionMedia) readPacketRTPUDPRecord(payload []byte) bool {
 
 	forma, ok := sm.formats[pkt.PayloadType]
 	if !ok {
+		sm.packets.discard(pkt)
 		sm.onPacketRTPDecodeError(liberrors.ErrServerRTPPacketUnknownPayloadType{PayloadType: pkt.PayloadType})
 		return false
 	}
@@ -204,11 +274,8 @@ func (sm *serverSessionMedia) readPacketRTPUDPRecord(payload []byte) bool {
 
 func (sm *serverSessionMedia) readPacketRTCPUDPRecord(payload []byte) bool {
 	atomic.AddUint64(sm.bytesReceived, uint64(len(payload)))
-
-	if len(payload) == (udpMaxPayloadSize + 1) {
-		sm.onPacketRTCPDecodeError(liberrors.ErrServerRTCPPacketTooBigUDP{})
-		return false
-	}
+	sm.ss.s.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
+	atomic.AddUint64(&sm.transportStats.bytesReceived, uint64(len(payload)))
 
 	packets, err := rtcp.Unmarshal(payload)
 	if err != nil {
@@ -220,12 +287,22 @@ func (sm *serverSessionMedia) readPacketRTCPUDPRecord(payload []byte) bool {
 	atomic.StoreInt64(sm.ss.udpLastPacketTime, now.Unix())
 
 	atomic.AddUint64(sm.rtcpPacketsReceived, uint64(len(packets)))
+	atomic.AddUint64(&sm.transportStats.rtcpPacketsReceived, uint64(len(packets)))
 
 	for _, pkt := range packets {
-		if sr, ok := pkt.(*rtcp.SenderReport); ok {
-			format := sm.findFormatWithSSRC(sr.SSRC)
+		switch tpkt := pkt.(type) {
+		case *rtcp.SenderReport:
+			format := sm.findFormatWithSSRC(tpkt.SSRC)
 			if format != nil {
-				format.rtcpReceiver.ProcessSenderReport(sr, now)
+				format.rtcpReceiver.ProcessSenderReport(tpkt, now)
+			}
+
+		case *rtcp.SourceDescription:
+			for i, chunk := range tpkt.Chunks {
+				format := sm.findFormatWithSSRC(chunk.Source)
+				if format != nil {
+					format.remoteSDES.process(&tpkt.Chunks[i])
+				}
 			}
 		}
 
@@ -241,6 +318,8 @@ func (sm *serverSessionMedia) readPacketRTPTCPPlay(_ []byte) bool {
 
 func (sm *serverSessionMedia) readPacketRTCPTCPPlay(payload []byte) bool {
 	atomic.AddUint64(sm.bytesReceived, uint64(len(payload)))
+	sm.ss.s.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
+	atomic.AddUint64(&sm.transportStats.bytesReceived, uint64(len(payload)))
 
 	if len(payload) > udpMaxPayloadSize {
 		sm.onPacketRTCPDecodeError(liberrors.ErrServerRTCPPacketTooBig{L: len(payload), Max: udpMaxPayloadSize})
@@ -254,6 +333,7 @@ func (sm *serverSessionMedia) readPacketRTCPTCPPlay(payload []byte) bool {
 	}
 
 	atomic.AddUint64(sm.rtcpPacketsReceived, uint64(len(packets)))
+	atomic.AddUint64(&sm.transportStats.rtcpPacketsReceived, uint64(len(packets)))
 
 	for _, pkt := range packets {
 		sm.onPacketRTCP(pkt)
@@ -264,9 +344,10 @@ func (sm *serverSessionMedia) readPacketRTCPTCPPlay(payload []byte) bool {
 
 func (sm *serverSessionMedia) readPacketRTPTCPRecord(payload []byte) bool {
 	atomic.AddUint64(sm.bytesReceived, uint64(len(payload)))
+	sm.ss.s.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
+	atomic.AddUint64(&sm.transportStats.bytesReceived, uint64(len(payload)))
 
-	pkt := &rtp.Packet{}
-	err := pkt.Unmarshal(payload)
+	pkt, err := sm.packets.decode(payload)
 	if err != nil {
 		sm.onPacketRTPDecodeError(err)
 		return false
@@ -274,6 +355,7 @@ func (sm *serverSessionMedia) readPacketRTPTCPRecord(payload []byte) bool {
 
 	forma, ok := sm.formats[pkt.PayloadType]
 	if !ok {
+		sm.packets.discard(pkt)
 		sm.onPacketRTPDecodeError(liberrors.ErrServerRTPPacketUnknownPayloadType{PayloadType: pkt.PayloadType})
 		return false
 	}
@@ -285,6 +367,8 @@ func (sm *serverSessionMedia) readPacketRTPTCPRecord(payload []byte) bool {
 
 func (sm *serverSessionMedia) readPacketRTCPTCPRecord(payload []byte) bool {
 	atomic.AddUint64(sm.bytesReceived, uint64(len(payload)))
+	sm.ss.s.ObservabilityOptions.Metrics.AddBytesReceived(uint64(len(payload)))
+	atomic.AddUint64(&sm.transportStats.bytesReceived, uint64(len(payload)))
 
 	if len(payload) > udpMaxPayloadSize {
 		sm.onPacketRTCPDecodeError(liberrors.ErrServerRTCPPacketTooBig{L: len(payload), Max: udpMaxPayloadSize})
@@ -300,12 +384,22 @@ func (sm *serverSessionMedia) readPacketRTCPTCPRecord(payload []byte) bool {
 	now := sm.ss.s.timeNow()
 
 	atomic.AddUint64(sm.rtcpPacketsReceived, uint64(len(packets)))
+	atomic.AddUint64(&sm.transportStats.rtcpPacketsReceived, uint64(len(packets)))
 
 	for _, pkt := range packets {
-		if sr, ok := pkt.(*rtcp.SenderReport); ok {
-			format := sm.findFormatWithSSRC(sr.SSRC)
+		switch tpkt := pkt.(type) {
+		case *rtcp.SenderReport:
+			format := sm.findFormatWithSSRC(tpkt.SSRC)
 			if format != nil {
-				format.rtcpReceiver.ProcessSenderReport(sr, now)
+				format.rtcpReceiver.ProcessSenderReport(tpkt, now)
+			}
+
+		case *rtcp.SourceDescription:
+			for i, chunk := range tpkt.Chunks {
+				format := sm.findFormatWithSSRC(chunk.Source)
+				if format != nil {
+					format.remoteSDES.process(&tpkt.Chunks[i])
+				}
 			}
 		}
 
@@ -317,6 +411,7 @@ func (sm *serverSessionMedia) readPacketRTCPTCPRecord(payload []byte) bool {
 
 func (sm *serverSessionMedia) onPacketRTPDecodeError(err error) {
 	atomic.AddUint64(sm.rtpPacketsInError, 1)
+	sm.ss.s.ObservabilityOptions.Metrics.AddErrors(MetricsErrorTypeRTPDecode, 1)
 
 	if h, ok := sm.ss.s.Handler.(ServerHandlerOnDecodeError); ok {
 		h.OnDecodeError(&ServerHandlerOnDecodeErrorCtx{
@@ -324,12 +419,13 @@ func (sm *serverSessionMedia) onPacketRTPDecodeError(err error) {
 			Error:   err,
 		})
 	} else {
-		log.Println(err.Error())
+		sm.ss.s.ObservabilityOptions.Logger.Warn(err.Error(), "session", sm.ss.id)
 	}
 }
 
 func (sm *serverSessionMedia) onPacketRTCPDecodeError(err error) {
 	atomic.AddUint64(sm.rtcpPacketsInError, 1)
+	sm.ss.s.ObservabilityOptions.Metrics.AddErrors(MetricsErrorTypeRTCPDecode, 1)
 
 	if h, ok := sm.ss.s.Handler.(ServerHandlerOnDecodeError); ok {
 		h.OnDecodeError(&ServerHandlerOnDecodeErrorCtx{
@@ -337,6 +433,16 @@ func (sm *serverSessionMedia) onPacketRTCPDecodeError(err error) {
 			Error:   err,
 		})
 	} else {
-		log.Println(err.Error())
+		sm.ss.s.ObservabilityOptions.Logger.Warn(err.Error(), "session", sm.ss.id)
 	}
 }
+
+func (sm *serverSessionMedia) onPacketRTPTruncated() {
+	atomic.AddUint64(sm.udpPacketsTruncated, 1)
+	sm.onPacketRTPDecodeError(liberrors.ErrServerRTPPacketTooBigUDP{})
+}
+
+func (sm *serverSessionMedia) onPacketRTCPTruncated() {
+	atomic.AddUint64(sm.udpPacketsTruncated, 1)
+	sm.onPacketRTCPDecodeError(liberrors.ErrServerRTCPPacketTooBigUDP{})
+}
@@ -0,0 +1,57 @@
+package gortsplib
+
+import (
+	"sync/atomic"
+)
+
+// StatsServerTransport are server statistics of a transport.
+type StatsServerTransport struct {
+	// received bytes
+	BytesReceived uint64
+	// sent bytes
+	BytesSent uint64
+	// number of RTP packets correctly received and processed
+	RTPPacketsReceived uint64
+	// number of sent RTP packets
+	RTPPacketsSent uint64
+	// number of RTCP packets correctly received and processed
+	RTCPPacketsReceived uint64
+	// number of sent RTCP packets
+	RTCPPacketsSent uint64
+}
+
+// StatsServer are server statistics.
+type StatsServer struct {
+	// number of connections accepted since the server was started
+	ConnsAccepted uint64
+	// number of open connections
+	ConnsOpen int
+	// number of TLS handshakes that failed
+	TLSHandshakeFailures uint64
+	// number of open sessions, by state
+	Sessions map[ServerSessionState]int
+	// statistics of open and closed sessions, by transport.
+	// Packets sent with UDP-multicast are counted once, regardless of the number of readers.
+	Transports map[Transport]StatsServerTransport
+}
+
+// serverTransportStats are counters of a transport, shared by all sessions.
+type serverTransportStats struct {
+	bytesReceived       uint64
+	bytesSent           uint64
+	rtpPacketsReceived  uint64
+	rtpPacketsSent      uint64
+	rtcpPacketsReceived uint64
+	rtcpPacketsSent     uint64
+}
+
+func (ts *serverTransportStats) stats() StatsServerTransport {
+	return StatsServerTransport{
+		BytesReceived:       atomic.LoadUint64(&ts.bytesReceived),
+		BytesSent:           atomic.LoadUint64(&ts.bytesSent),
+		RTPPacketsReceived:  atomic.LoadUint64(&ts.rtpPacketsReceived),
+		RTPPacketsSent:      atomic.LoadUint64(&ts.rtpPacketsSent),
+		RTCPPacketsReceived: atomic.LoadUint64(&ts.rtcpPacketsReceived),
+		RTCPPacketsSent:     atomic.LoadUint64(&ts.rtcpPacketsSent),
+	}
+}
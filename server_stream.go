@@ -1,6 +1,7 @@
 package gortsplib
 
 import (
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -8,6 +9,8 @@ import (
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 
+	"github.com/bluenviron/gortsplib/v4/internal/rtpnormalizer"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
 	"github.com/bluenviron/gortsplib/v4/pkg/description"
 	"github.com/bluenviron/gortsplib/v4/pkg/format"
 	"github.com/bluenviron/gortsplib/v4/pkg/headers"
@@ -38,6 +41,10 @@ type ServerStream struct {
 	multicastReaderCount int
 	activeUnicastReaders map[*ServerSession]struct{}
 	medias               map[*description.Media]*serverStreamMedia
+	writeQueueSize       int
+	rtpMode              ServerStreamRTPMode
+	packetFilter         ServerStreamPacketFilter
+	duration             time.Duration
 	closed               bool
 }
 
@@ -90,13 +97,178 @@ func (st *ServerStream) BytesSent() uint64 {
 	return v
 }
 
+// SetWriteQueueSize sets the size of the queue of outgoing packets of readers
+// of the stream, overriding Server.WriteQueueSize.
+// It must be a power of two.
+// It is applied to readers that start reading afterwards.
+func (st *ServerStream) SetWriteQueueSize(v int) error {
+	if v <= 0 || (v&(v-1)) != 0 {
+		return fmt.Errorf("write queue size must be a power of two")
+	}
+
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	st.writeQueueSize = v
+	return nil
+}
+
+// WriteQueueSize returns the size of the queue of outgoing packets of readers of the stream.
+// It returns zero when the size is not set and Server.WriteQueueSize is used.
+func (st *ServerStream) WriteQueueSize() int {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	return st.writeQueueSize
+}
+
+// SetRTPMode sets the mode used to forward RTP packets to readers.
+// It defaults to ServerStreamRTPModePassthrough.
+// It must be called before the stream has readers and before packets are written,
+// since readers expect SSRC, sequence numbers and timestamps not to change mode
+// while they are reading.
+func (st *ServerStream) SetRTPMode(mode ServerStreamRTPMode) error {
+	if mode < ServerStreamRTPModePassthrough || mode > ServerStreamRTPModeNormalize {
+		return fmt.Errorf("invalid RTP mode")
+	}
+
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	if st.closed {
+		return liberrors.ErrServerStreamClosed{}
+	}
+
+	if len(st.readers) != 0 {
+		return fmt.Errorf("RTP mode can't be changed when the stream has readers")
+	}
+
+	for _, sm := range st.medias {
+		for _, sf := range sm.formats {
+			if sf.rtcpSender.Stats() != nil {
+				return fmt.Errorf("RTP mode can't be changed after packets have been written")
+			}
+		}
+	}
+
+	for _, sm := range st.medias {
+		for _, sf := range sm.formats {
+			if mode == ServerStreamRTPModeNormalize {
+				n := &rtpnormalizer.Normalizer{
+					ClockRate: sf.format.ClockRate(),
+				}
+				err := n.Initialize()
+				if err != nil {
+					return err
+				}
+				sf.normalizer = n
+			} else {
+				sf.normalizer = nil
+			}
+		}
+	}
+
+	st.rtpMode = mode
+	return nil
+}
+
+// RTPMode returns the mode used to forward RTP packets to readers.
+func (st *ServerStream) RTPMode() ServerStreamRTPMode {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	return st.rtpMode
+}
+
+// SetPacketFilter sets a filter that is applied to RTP packets before they are sent to readers.
+// The filter is called once for each written packet, regardless of the number of readers,
+// and can drop, modify or delay packets.
+// Delayed packets are written asynchronously, without blocking the writer.
+// A nil filter removes the current one.
+func (st *ServerStream) SetPacketFilter(filter ServerStreamPacketFilter) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	st.packetFilter = filter
+}
+
+// SetDuration sets the duration of the stream, in case of on-demand streams.
+// When set, responses to PLAY requests contain a Range header with the end time,
+// unless the handler provides one.
+func (st *ServerStream) SetDuration(d time.Duration) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	st.duration = d
+}
+
+// CompletePlayback signals the end of the stream to readers,
+// by sending a RTCP BYE packet for each media.
+// Readers are not closed and can still seek or tear down the session.
+func (st *ServerStream) CompletePlayback() error {
+	for _, medi := range st.desc.Medias {
+		ssrcs := st.localSSRCs(medi)
+
+		bye := &rtcp.Goodbye{}
+		for _, forma := range medi.Formats {
+			if ssrc, ok := ssrcs[forma]; ok {
+				bye.Sources = append(bye.Sources, ssrc)
+			}
+		}
+
+		err := st.WritePacketRTCP(medi, bye)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// playRange returns the Range header of responses to PLAY requests.
+func (st *ServerStream) playRange(req *base.Request) (*headers.Range, bool) {
+	st.mutex.RLock()
+	duration := st.duration
+	st.mutex.RUnlock()
+
+	if duration == 0 {
+		return nil, false
+	}
+
+	start := time.Duration(0)
+
+	var ra headers.Range
+	err := ra.Unmarshal(req.Header["Range"])
+	if err == nil {
+		if npt, ok := ra.Value.(*headers.RangeNPT); ok {
+			start = npt.Start
+		}
+	}
+
+	return &headers.Range{
+		Value: &headers.RangeNPT{
+			Start: start,
+			End:   &duration,
+		},
+	}, true
+}
+
 // Description returns the description of the stream.
 func (st *ServerStream) Description() *description.Session {
 	return st.desc
 }
 
 // Stats returns stream statistics.
+// It is equivalent to StatsSnapshot().
 func (st *ServerStream) Stats() *ServerStreamStats {
+	return st.StatsSnapshot()
+}
+
+// StatsSnapshot returns a snapshot of stream statistics.
+// Counters are updated atomically by packet paths, that never wait for readers,
+// therefore it can be called periodically without slowing down writes.
+// See StatsSession for the consistency model.
+func (st *ServerStream) StatsSnapshot() *ServerStreamStats {
 	return &ServerStreamStats{
 		BytesSent: func() uint64 {
 			v := uint64(0)
@@ -148,8 +320,8 @@ func (st *ServerStream) Stats() *ServerStreamStats {
 }
 
 func (st *ServerStream) localSSRC(medi *description.Media) (uint32, bool) {
-	st.mutex.Lock()
-	defer st.mutex.Unlock()
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
 
 	sm := st.medias[medi]
 
@@ -160,17 +332,27 @@ func (st *ServerStream) localSSRC(medi *description.Media) (uint32, bool) {
 		return 0, false
 	}
 
-	stats := firstFormat(sm.formats).rtcpSender.Stats()
-	if stats == nil {
-		return 0, false
+	return firstFormat(sm.formats).localSSRC()
+}
+
+func (st *ServerStream) localSSRCs(medi *description.Media) map[format.Format]uint32 {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	ret := make(map[format.Format]uint32)
+
+	for _, sf := range st.medias[medi].formats {
+		if ssrc, ok := sf.localSSRC(); ok {
+			ret[sf.format] = ssrc
+		}
 	}
 
-	return stats.LocalSSRC, true
+	return ret
 }
 
 func (st *ServerStream) rtpInfoEntry(medi *description.Media, now time.Time) *headers.RTPInfoEntry {
-	st.mutex.Lock()
-	defer st.mutex.Unlock()
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
 
 	sm := st.medias[medi]
 
@@ -239,7 +421,8 @@ func (st *ServerStream) readerAdd(
 		if st.multicastReaderCount == 0 {
 			for _, media := range st.medias {
 				mw := &serverMulticastWriter{
-					s: st.s,
+					s:              st.s,
+					writeQueueSize: st.writeQueueSize,
 				}
 				err := mw.initialize()
 				if err != nil {
@@ -289,7 +472,8 @@ func (st *ServerStream) readerSetActive(ss *ServerSession) {
 		for medi, sm := range ss.setuppedMedias {
 			streamMedia := st.medias[medi]
 			streamMedia.multicastWriter.rtcpl.addClient(
-				ss.author.ip(), streamMedia.multicastWriter.rtcpl.port(), sm.readPacketRTCPUDPPlay)
+				ss.author.ip(), streamMedia.multicastWriter.rtcpl.port(), sm.readPacketRTCPUDPPlay,
+				sm.onPacketRTCPTruncated)
 		}
 	} else {
 		st.activeUnicastReaders[ss] = struct{}{}
@@ -315,6 +499,11 @@ func (st *ServerStream) readerSetInactive(ss *ServerSession) {
 }
 
 // WritePacketRTP writes a RTP packet to all the readers of the stream.
+//
+// Write methods can be called concurrently from multiple goroutines.
+// Writes to the same media are serialized, therefore readers receive packets
+// in the same order in which calls are made, while writes to different medias
+// don't block each other.
 func (st *ServerStream) WritePacketRTP(medi *description.Media, pkt *rtp.Packet) error {
 	return st.WritePacketRTPWithNTP(medi, pkt, st.s.timeNow())
 }
@@ -322,12 +511,24 @@ func (st *ServerStream) WritePacketRTP(medi *description.Media, pkt *rtp.Packet)
 // WritePacketRTPWithNTP writes a RTP packet to all the readers of the stream.
 // ntp is the absolute time of the packet, and is sent with periodic RTCP sender reports.
 func (st *ServerStream) WritePacketRTPWithNTP(medi *description.Media, pkt *rtp.Packet, ntp time.Time) error {
-	byts := make([]byte, st.s.MaxPacketSize)
-	n, err := pkt.MarshalTo(byts)
-	if err != nil {
-		return err
+	return st.WritePacketsRTPWithNTP(medi, []*rtp.Packet{pkt}, ntp)
+}
+
+// WritePacketsRTP writes the RTP packets of an access unit to all the readers of the stream.
+// Packets are enqueued atomically: each reader receives either all packets or none of them.
+// Packets must belong to the same format.
+func (st *ServerStream) WritePacketsRTP(medi *description.Media, pkts []*rtp.Packet) error {
+	return st.WritePacketsRTPWithNTP(medi, pkts, st.s.timeNow())
+}
+
+// WritePacketsRTPWithNTP writes the RTP packets of an access unit to all the readers of the stream.
+// Packets are enqueued atomically: each reader receives either all packets or none of them.
+// Packets must belong to the same format.
+// ntp is the absolute time of the access unit, and is sent with periodic RTCP sender reports.
+func (st *ServerStream) WritePacketsRTPWithNTP(medi *description.Media, pkts []*rtp.Packet, ntp time.Time) error {
+	if len(pkts) == 0 {
+		return nil
 	}
-	byts = byts[:n]
 
 	st.mutex.RLock()
 	defer st.mutex.RUnlock()
@@ -337,8 +538,109 @@ func (st *ServerStream) WritePacketRTPWithNTP(medi *description.Media, pkt *rtp.
 	}
 
 	sm := st.medias[medi]
-	sf := sm.formats[pkt.PayloadType]
-	return sf.writePacketRTP(byts, pkt, ntp)
+	sf := sm.formats[pkts[0].PayloadType]
+
+	// packets must be normalized, processed by the RTCP sender
+	// and enqueued into readers in the same order.
+	sm.writeMutex.Lock()
+	defer sm.writeMutex.Unlock()
+
+	if sf.normalizer != nil {
+		normalized := make([]*rtp.Packet, len(pkts))
+		for i, pkt := range pkts {
+			normalized[i] = sf.normalizer.Process(pkt, ntp)
+		}
+		pkts = normalized
+	}
+
+	if st.packetFilter != nil {
+		var delay time.Duration
+		pkts, delay = filterPackets(st.packetFilter, medi, pkts)
+
+		if len(pkts) == 0 {
+			return nil
+		}
+
+		if delay > 0 {
+			time.AfterFunc(delay, func() {
+				st.writeDelayedPacketsRTP(sm, sf, pkts, ntp)
+			})
+			return nil
+		}
+	}
+
+	return st.writePacketsRTP(sm, sf, pkts, ntp)
+}
+
+func (st *ServerStream) writeDelayedPacketsRTP(
+	sm *serverStreamMedia,
+	sf *serverStreamFormat,
+	pkts []*rtp.Packet,
+	ntp time.Time,
+) {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	if st.closed {
+		return
+	}
+
+	sm.writeMutex.Lock()
+	defer sm.writeMutex.Unlock()
+
+	st.writePacketsRTP(sm, sf, pkts, ntp) //nolint:errcheck
+}
+
+func (st *ServerStream) writePacketsRTP(
+	sm *serverStreamMedia,
+	sf *serverStreamFormat,
+	pkts []*rtp.Packet,
+	ntp time.Time,
+) error {
+	now := st.s.timeNow()
+
+	byts := make([][]byte, len(pkts))
+	for i, pkt := range pkts {
+		if sm.extensions.enabled() {
+			var err error
+			pkt, err = sm.extensions.apply(pkt, now, ntp)
+			if err != nil {
+				if st.s.MemoryOptions.PoolPackets {
+					for _, b := range byts[:i] {
+						putPacketBuffer(b)
+					}
+				}
+				return err
+			}
+		}
+
+		var buf []byte
+		if st.s.MemoryOptions.PoolPackets {
+			buf = getPacketBuffer(st.s.MaxPacketSize)
+		} else {
+			buf = make([]byte, st.s.MaxPacketSize)
+		}
+
+		n, err := pkt.MarshalTo(buf)
+		if err != nil {
+			if st.s.MemoryOptions.PoolPackets {
+				putPacketBuffer(buf)
+				for _, b := range byts[:i] {
+					putPacketBuffer(b)
+				}
+			}
+			return err
+		}
+		byts[i] = buf[:n]
+	}
+
+	var shared *sharedPacketBuffers
+	if st.s.MemoryOptions.PoolPackets {
+		shared = newSharedPacketBuffers(byts)
+		defer shared.release()
+	}
+
+	return sf.writePacketsRTP(byts, pkts, ntp, shared)
 }
 
 // WritePacketRTCP writes a RTCP packet to all the readers of the stream.
@@ -356,5 +658,9 @@ func (st *ServerStream) WritePacketRTCP(medi *description.Media, pkt rtcp.Packet
 	}
 
 	sm := st.medias[medi]
+
+	sm.writeMutex.Lock()
+	defer sm.writeMutex.Unlock()
+
 	return sm.writePacketRTCP(byts)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/pion/rtp"
 
 	"github.com/bluenviron/gortsplib/v4/internal/rtcpsender"
+	"github.com/bluenviron/gortsplib/v4/internal/rtpnormalizer"
 	"github.com/bluenviron/gortsplib/v4/pkg/format"
 )
 
@@ -16,9 +17,24 @@ type serverStreamFormat struct {
 	format format.Format
 
 	rtcpSender     *rtcpsender.RTCPSender
+	normalizer     *rtpnormalizer.Normalizer
 	rtpPacketsSent *uint64
 }
 
+func (sf *serverStreamFormat) localSSRC() (uint32, bool) {
+	// in normalize mode, the SSRC is known before the first packet is written.
+	if sf.normalizer != nil {
+		return sf.normalizer.SSRC(), true
+	}
+
+	stats := sf.rtcpSender.Stats()
+	if stats == nil {
+		return 0, false
+	}
+
+	return stats.LocalSSRC, true
+}
+
 func (sf *serverStreamFormat) initialize() {
 	sf.rtpPacketsSent = new(uint64)
 
@@ -35,34 +51,49 @@ func (sf *serverStreamFormat) initialize() {
 	sf.rtcpSender.Initialize()
 }
 
-func (sf *serverStreamFormat) writePacketRTP(byts []byte, pkt *rtp.Packet, ntp time.Time) error {
-	sf.rtcpSender.ProcessPacketRTP(pkt, ntp, sf.format.PTSEqualsDTS(pkt))
+func (sf *serverStreamFormat) writePacketsRTP(
+	byts [][]byte,
+	pkts []*rtp.Packet,
+	ntp time.Time,
+	shared *sharedPacketBuffers,
+) error {
+	for _, pkt := range pkts {
+		sf.rtcpSender.ProcessPacketRTP(pkt, ntp, sf.format.PTSEqualsDTS(pkt))
+	}
 
-	le := uint64(len(byts))
+	le := uint64(0)
+	for _, b := range byts {
+		le += uint64(len(b))
+	}
 
 	// send unicast
 	for r := range sf.sm.st.activeUnicastReaders {
 		if _, ok := r.setuppedMedias[sf.sm.media]; ok {
-			err := r.writePacketRTP(sf.sm.media, pkt.PayloadType, byts)
+			err := r.writePacketsRTP(sf.sm.media, pkts, byts, shared)
 			if err != nil {
 				r.onStreamWriteError(err)
 				continue
 			}
 
 			atomic.AddUint64(sf.sm.bytesSent, le)
-			atomic.AddUint64(sf.rtpPacketsSent, 1)
+			atomic.AddUint64(sf.rtpPacketsSent, uint64(len(pkts)))
 		}
 	}
 
 	// send multicast
 	if sf.sm.multicastWriter != nil {
-		err := sf.sm.multicastWriter.writePacketRTP(byts)
+		err := sf.sm.multicastWriter.writePacketsRTP(byts, shared)
 		if err != nil {
 			return err
 		}
 
 		atomic.AddUint64(sf.sm.bytesSent, le)
-		atomic.AddUint64(sf.rtpPacketsSent, 1)
+		atomic.AddUint64(sf.rtpPacketsSent, uint64(len(pkts)))
+		sf.sm.st.s.ObservabilityOptions.Metrics.AddBytesSent(le)
+
+		ts := sf.sm.st.s.transportStats[TransportUDPMulticast]
+		atomic.AddUint64(&ts.bytesSent, le)
+		atomic.AddUint64(&ts.rtpPacketsSent, uint64(len(pkts)))
 	}
 
 	return nil
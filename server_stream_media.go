@@ -1,6 +1,7 @@
 package gortsplib
 
 import (
+	"sync"
 	"sync/atomic"
 
 	"github.com/bluenviron/gortsplib/v4/pkg/description"
@@ -15,11 +16,16 @@ type serverStreamMedia struct {
 	multicastWriter *serverMulticastWriter
 	bytesSent       *uint64
 	rtcpPacketsSent *uint64
+	extensions      mediaExtensions
+
+	// serializes writes of concurrent producers.
+	writeMutex sync.Mutex
 }
 
 func (sm *serverStreamMedia) initialize() {
 	sm.bytesSent = new(uint64)
 	sm.rtcpPacketsSent = new(uint64)
+	sm.extensions = newMediaExtensions(sm.media)
 
 	sm.formats = make(map[uint8]*serverStreamFormat)
 	for _, forma := range sm.media.Formats {
@@ -70,6 +76,11 @@ func (sm *serverStreamMedia) writePacketRTCP(byts []byte) error {
 
 		atomic.AddUint64(sm.bytesSent, uint64(le))
 		atomic.AddUint64(sm.rtcpPacketsSent, 1)
+		sm.st.s.ObservabilityOptions.Metrics.AddBytesSent(uint64(le))
+
+		ts := sm.st.s.transportStats[TransportUDPMulticast]
+		atomic.AddUint64(&ts.bytesSent, uint64(le))
+		atomic.AddUint64(&ts.rtcpPacketsSent, 1)
 	}
 
 	return nil
@@ -0,0 +1,47 @@
+package gortsplib
+
+import (
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+)
+
+// FilterAction is the action that a packet filter applies to a packet.
+// The zero value forwards the packet unchanged.
+type FilterAction struct {
+	// drop the packet.
+	Drop bool
+	// delay the packet.
+	// Packets written together are delayed by the highest delay among them.
+	Delay time.Duration
+}
+
+// ServerStreamPacketFilter is the prototype of the filter set with ServerStream.SetPacketFilter.
+// The packet is a copy and can be modified.
+type ServerStreamPacketFilter func(medi *description.Media, pkt *rtp.Packet) FilterAction
+
+// filterPackets applies a filter to packets and returns forwarded packets and their delay.
+func filterPackets(
+	filter ServerStreamPacketFilter,
+	medi *description.Media,
+	pkts []*rtp.Packet,
+) ([]*rtp.Packet, time.Duration) {
+	ret := make([]*rtp.Packet, 0, len(pkts))
+	var delay time.Duration
+
+	for _, pkt := range pkts {
+		pkt = pkt.Clone()
+
+		action := filter(medi, pkt)
+		if action.Drop {
+			continue
+		}
+
+		delay = max(delay, action.Delay)
+		ret = append(ret, pkt)
+	}
+
+	return ret, delay
+}
@@ -0,0 +1,21 @@
+package gortsplib
+
+// ServerStreamRTPMode is the mode used by a ServerStream to forward RTP packets to readers.
+type ServerStreamRTPMode int
+
+// modes.
+const (
+	// forward packets untouched (transparent relay).
+	// SSRC, sequence numbers, timestamps and header extensions are the ones
+	// of written packets, therefore readers see any discontinuity of the source
+	// (i.e. a publisher that restarts or changes SSRC).
+	// This has the lowest overhead.
+	ServerStreamRTPModePassthrough ServerStreamRTPMode = iota
+
+	// rewrite packets before forwarding them.
+	// Each format gets a fixed random SSRC, that is announced to readers
+	// before the first packet is written; sequence numbers and timestamps
+	// are shifted in order to remain continuous when the source changes SSRC.
+	// Header extensions and payloads are left untouched.
+	ServerStreamRTPModeNormalize
+)
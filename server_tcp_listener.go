@@ -5,14 +5,15 @@ import (
 )
 
 type serverTCPListener struct {
-	s *Server
+	s       *Server
+	address string
 
 	ln net.Listener
 }
 
 func (sl *serverTCPListener) initialize() error {
 	var err error
-	sl.ln, err = sl.s.Listen(restrictNetwork("tcp", sl.s.RTSPAddress))
+	sl.ln, err = sl.s.Listen(restrictNetwork("tcp", sl.address))
 	if err != nil {
 		return err
 	}
@@ -37,6 +38,17 @@ func (sl *serverTCPListener) run() {
 			return
 		}
 
+		if sl.s.OnConnFilter != nil && !sl.s.OnConnFilter(nconn.RemoteAddr()) {
+			nconn.Close()
+			continue
+		}
+
+		err = setTCPOptions(nconn, sl.s.SocketOptions.DisableTCPNoDelay, sl.s.SocketOptions.TCPKeepAlivePeriod)
+		if err != nil {
+			nconn.Close()
+			continue
+		}
+
 		sl.s.newConn(nconn)
 	}
 }
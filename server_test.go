@@ -1,11 +1,17 @@
 package gortsplib
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/bluenviron/gortsplib/v4/pkg/auth"
@@ -82,20 +88,25 @@ func writeReqReadRes(
 }
 
 type testServerHandler struct {
-	onConnOpen     func(*ServerHandlerOnConnOpenCtx)
-	onConnClose    func(*ServerHandlerOnConnCloseCtx)
-	onSessionOpen  func(*ServerHandlerOnSessionOpenCtx)
-	onSessionClose func(*ServerHandlerOnSessionCloseCtx)
-	onDescribe     func(*ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error)
-	onAnnounce     func(*ServerHandlerOnAnnounceCtx) (*base.Response, error)
-	onSetup        func(*ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error)
-	onPlay         func(*ServerHandlerOnPlayCtx) (*base.Response, error)
-	onRecord       func(*ServerHandlerOnRecordCtx) (*base.Response, error)
-	onPause        func(*ServerHandlerOnPauseCtx) (*base.Response, error)
-	onSetParameter func(*ServerHandlerOnSetParameterCtx) (*base.Response, error)
-	onGetParameter func(*ServerHandlerOnGetParameterCtx) (*base.Response, error)
-	onPacketLost   func(*ServerHandlerOnPacketLostCtx)
-	onDecodeError  func(*ServerHandlerOnDecodeErrorCtx)
+	onConnOpen         func(*ServerHandlerOnConnOpenCtx)
+	onConnClose        func(*ServerHandlerOnConnCloseCtx)
+	onSessionOpen      func(*ServerHandlerOnSessionOpenCtx)
+	onSessionClose     func(*ServerHandlerOnSessionCloseCtx)
+	onDescribe         func(*ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error)
+	onAnnounce         func(*ServerHandlerOnAnnounceCtx) (*base.Response, error)
+	onSetup            func(*ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error)
+	onPlay             func(*ServerHandlerOnPlayCtx) (*base.Response, error)
+	onRecord           func(*ServerHandlerOnRecordCtx) (*base.Response, error)
+	onPause            func(*ServerHandlerOnPauseCtx) (*base.Response, error)
+	onSetParameter     func(*ServerHandlerOnSetParameterCtx) (*base.Response, error)
+	onGetParameter     func(*ServerHandlerOnGetParameterCtx) (*base.Response, error)
+	onPacketLost       func(*ServerHandlerOnPacketLostCtx)
+	onDecodeError      func(*ServerHandlerOnDecodeErrorCtx)
+	onReaderCongestion func(*ServerHandlerOnReaderCongestionCtx)
+	onStreamWriteError func(*ServerHandlerOnStreamWriteErrorCtx)
+	onRequestDone      func(*ServerHandlerOnRequestDoneCtx)
+	onSessionStats     func(*ServerHandlerOnSessionStatsCtx)
+	onViolation        func(*ServerHandlerOnViolationCtx)
 }
 
 func (sh *testServerHandler) OnConnOpen(ctx *ServerHandlerOnConnOpenCtx) {
@@ -184,12 +195,42 @@ func (sh *testServerHandler) OnPacketLost(ctx *ServerHandlerOnPacketLostCtx) {
 	}
 }
 
+func (sh *testServerHandler) OnSessionStats(ctx *ServerHandlerOnSessionStatsCtx) {
+	if sh.onSessionStats != nil {
+		sh.onSessionStats(ctx)
+	}
+}
+
+func (sh *testServerHandler) OnViolation(ctx *ServerHandlerOnViolationCtx) {
+	if sh.onViolation != nil {
+		sh.onViolation(ctx)
+	}
+}
+
 func (sh *testServerHandler) OnDecodeError(ctx *ServerHandlerOnDecodeErrorCtx) {
 	if sh.onDecodeError != nil {
 		sh.onDecodeError(ctx)
 	}
 }
 
+func (sh *testServerHandler) OnReaderCongestion(ctx *ServerHandlerOnReaderCongestionCtx) {
+	if sh.onReaderCongestion != nil {
+		sh.onReaderCongestion(ctx)
+	}
+}
+
+func (sh *testServerHandler) OnStreamWriteError(ctx *ServerHandlerOnStreamWriteErrorCtx) {
+	if sh.onStreamWriteError != nil {
+		sh.onStreamWriteError(ctx)
+	}
+}
+
+func (sh *testServerHandler) OnRequestDone(ctx *ServerHandlerOnRequestDoneCtx) {
+	if sh.onRequestDone != nil {
+		sh.onRequestDone(ctx)
+	}
+}
+
 func TestServerClose(t *testing.T) {
 	s := &Server{
 		Handler:     &testServerHandler{},
@@ -198,48 +239,776 @@ func TestServerClose(t *testing.T) {
 
 	err := s.Start()
 	require.NoError(t, err)
-	s.Close()
-	s.Close()
-}
+	s.Close()
+	s.Close()
+}
+
+func TestServerErrorInvalidUDPPorts(t *testing.T) {
+	t.Run("non consecutive", func(t *testing.T) {
+		s := &Server{
+			UDPRTPAddress:  "127.0.0.1:8006",
+			UDPRTCPAddress: "127.0.0.1:8009",
+			RTSPAddress:    "localhost:8554",
+		}
+		err := s.Start()
+		require.Error(t, err)
+	})
+
+	t.Run("non even", func(t *testing.T) {
+		s := &Server{
+			UDPRTPAddress:  "127.0.0.1:8003",
+			UDPRTCPAddress: "127.0.0.1:8004",
+			RTSPAddress:    "localhost:8554",
+		}
+		err := s.Start()
+		require.Error(t, err)
+	})
+}
+
+func TestServerValidate(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		s    *Server
+		err  string
+	}{
+		{
+			"missing rtsp address",
+			&Server{},
+			"RTSPAddress not provided",
+		},
+		{
+			"udp rtp without rtcp",
+			&Server{
+				RTSPAddress:   "localhost:8554",
+				UDPRTPAddress: "127.0.0.1:8000",
+			},
+			"UDPRTPAddress requires UDPRTCPAddress",
+		},
+		{
+			"multicast ports without ip range",
+			&Server{
+				RTSPAddress:       "localhost:8554",
+				MulticastRTPPort:  8002,
+				MulticastRTCPPort: 8003,
+			},
+			"MulticastRTPPort and MulticastRTCPPort require MulticastIPRange",
+		},
+		{
+			"multicast ip range without ports",
+			&Server{
+				RTSPAddress:      "localhost:8554",
+				MulticastIPRange: "224.1.0.0/16",
+			},
+			"MulticastIPRange requires MulticastRTPPort and MulticastRTCPPort",
+		},
+		{
+			"invalid multicast ip range",
+			&Server{
+				RTSPAddress:       "localhost:8554",
+				MulticastIPRange:  "224.1.0.0",
+				MulticastRTPPort:  8002,
+				MulticastRTCPPort: 8003,
+			},
+			"invalid MulticastIPRange: invalid CIDR address: 224.1.0.0",
+		},
+		{
+			"tls with udp",
+			NewServer("localhost:8554", nil,
+				WithServerSecurity(SecurityOptions{TLSConfig: &tls.Config{}}),
+				WithServerTransport(ServerTransportOptions{
+					UDPRTPAddress:  "127.0.0.1:8000",
+					UDPRTCPAddress: "127.0.0.1:8001",
+				})),
+			"TLS can't be used with UDP: unset UDPRTPAddress and UDPRTCPAddress, or set UnencryptedMedia",
+		},
+		{
+			"write queue size",
+			&Server{
+				RTSPAddress:    "localhost:8554",
+				WriteQueueSize: 100,
+			},
+			"WriteQueueSize must be a power of two",
+		},
+		{
+			"session timeout",
+			&Server{
+				RTSPAddress:    "localhost:8554",
+				SessionOptions: ServerSessionOptions{Timeout: 500 * time.Millisecond},
+			},
+			"SessionTimeout must be at least one second",
+		},
+		{
+			"negative timeout",
+			NewServer("localhost:8554", nil,
+				WithServerTimeouts(TimeoutOptions{Read: -1})),
+			"ReadTimeout must not be negative",
+		},
+		{
+			"invalid dscp",
+			NewServer("localhost:8554", nil,
+				WithServerSocket(SocketOptions{DSCP: 64})),
+			"DSCP must be between 0 and 63",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			require.EqualError(t, ca.s.Validate(), ca.err)
+			require.EqualError(t, ca.s.Start(), ca.err)
+		})
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	s := NewServer("localhost:8554", &testServerHandler{},
+		WithServerTransport(ServerTransportOptions{
+			UDPRTPAddress:  "127.0.0.1:8000",
+			UDPRTCPAddress: "127.0.0.1:8001",
+		}),
+		WithServerTimeouts(TimeoutOptions{
+			Read:  5 * time.Second,
+			Write: 6 * time.Second,
+		}),
+		WithServerSocket(SocketOptions{
+			DSCP:               46,
+			TCPKeepAlivePeriod: 30 * time.Second,
+		}),
+		WithServerStrict(StrictOptions{
+			Enabled: true,
+		}),
+		WithServerLimits(ServerLimitOptions{
+			MaxConnections: 10,
+		}),
+		WithServerAuth(ServerAuthOptions{
+			Realm: "myrealm",
+		}),
+		WithServerSession(ServerSessionOptions{
+			Timeout: 30 * time.Second,
+		}),
+		WithServerObservability(ObservabilityOptions{
+			StatsPeriod: 2 * time.Second,
+		}),
+		WithServerMemory(MemoryOptions{
+			PoolPackets: true,
+		}))
+
+	require.Equal(t, "127.0.0.1:8000", s.TransportOptions.UDPRTPAddress)
+	require.Equal(t, "127.0.0.1:8001", s.TransportOptions.UDPRTCPAddress)
+	require.Equal(t, 5*time.Second, s.TimeoutOptions.Read)
+	require.Equal(t, 6*time.Second, s.TimeoutOptions.Write)
+	require.Equal(t, 46, s.SocketOptions.DSCP)
+	require.Equal(t, 30*time.Second, s.SocketOptions.TCPKeepAlivePeriod)
+	require.Equal(t, true, s.StrictOptions.Enabled)
+	require.Equal(t, 10, s.LimitOptions.MaxConnections)
+	require.Equal(t, "myrealm", s.AuthOptions.Realm)
+	require.Equal(t, 30*time.Second, s.SessionOptions.Timeout)
+	require.Equal(t, 2*time.Second, s.ObservabilityOptions.StatsPeriod)
+	require.Equal(t, true, s.MemoryOptions.PoolPackets)
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.Equal(t, 10, s.Limits().MaxConnections)
+	require.Equal(t, "myrealm", s.Limits().AuthRealm)
+}
+
+func TestServerDeprecatedFields(t *testing.T) {
+	s := &Server{
+		Handler:        &testServerHandler{},
+		RTSPAddress:    "localhost:8554",
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+		ReadTimeout:    5 * time.Second,
+		TimeoutOptions: TimeoutOptions{
+			Write: 6 * time.Second,
+		},
+	}
+
+	// Validate() must not move deprecated fields
+	err := s.Validate()
+	require.NoError(t, err)
+	require.Equal(t, ServerTransportOptions{}, s.TransportOptions)
+	require.Equal(t, TimeoutOptions{Write: 6 * time.Second}, s.TimeoutOptions)
+
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.Equal(t, ServerTransportOptions{
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+	}, s.TransportOptions)
+	require.Equal(t, TimeoutOptions{
+		Read:  5 * time.Second,
+		Write: 6 * time.Second,
+	}, s.TimeoutOptions)
+}
+
+func TestServerSetLimits(t *testing.T) {
+	s := &Server{
+		Handler:      &testServerHandler{},
+		RTSPAddress:  "localhost:8554",
+		LimitOptions: ServerLimitOptions{MaxConnections: 1},
+	}
+
+	err := s.SetLimits(ServerLimits{})
+	require.EqualError(t, err, "server is not running")
+
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.Equal(t, ServerLimits{
+		ReadTimeout:         10 * time.Second,
+		WriteTimeout:        10 * time.Second,
+		WriteQueueSize:      256,
+		MaxConnections:      1,
+		AuthRequiredMethods: defaultAuthRequiredMethods,
+		AuthValidateMethods: defaultAuthValidateMethods,
+		AuthRealm:           "IPCAM",
+	}, s.Limits())
+
+	options := func(nconn net.Conn) base.StatusCode {
+		res, err2 := writeReqReadRes(conn.NewConn(nconn), base.Request{
+			Method: base.Options,
+			URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+			Header: base.Header{
+				"CSeq": base.HeaderValue{"1"},
+			},
+		})
+		require.NoError(t, err2)
+		return res.StatusCode
+	}
+
+	nconn1, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn1.Close()
+	require.Equal(t, base.StatusOK, options(nconn1))
+
+	// connections above limits are closed after writing a response
+	nconn2, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn2.Close()
+	conn2 := conn.NewConn(nconn2)
+	res, err := conn2.ReadResponse()
+	require.NoError(t, err)
+	require.Equal(t, base.StatusServiceUnavailable, res.StatusCode)
+	_, err = conn2.ReadResponse()
+	require.Error(t, err)
+
+	err = s.SetLimits(ServerLimits{WriteQueueSize: 100})
+	require.EqualError(t, err, "WriteQueueSize must be a power of two")
+
+	err = s.SetLimits(ServerLimits{
+		ReadTimeout:    5 * time.Second,
+		MaxConnections: 2,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, ServerLimits{
+		ReadTimeout:         5 * time.Second,
+		WriteTimeout:        10 * time.Second,
+		WriteQueueSize:      256,
+		MaxConnections:      2,
+		AuthRequiredMethods: defaultAuthRequiredMethods,
+		AuthValidateMethods: defaultAuthValidateMethods,
+		AuthRealm:           "IPCAM",
+	}, s.Limits())
+
+	nconn3, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn3.Close()
+	require.Equal(t, base.StatusOK, options(nconn3))
+
+	// existing connections are not closed
+	require.Equal(t, base.StatusOK, options(nconn1))
+}
+
+func TestServerConnSessionLimits(t *testing.T) {
+	for _, ca := range []string{
+		"connections",
+		"connections per ip",
+		"sessions",
+		"sessions per ip",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			s := &Server{
+				Handler: &testServerHandler{
+					onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			switch ca {
+			case "connections":
+				s.LimitOptions.MaxConnections = 1
+			case "connections per ip":
+				s.LimitOptions.MaxConnectionsPerIP = 1
+			case "sessions":
+				s.LimitOptions.MaxSessions = 1
+			case "sessions per ip":
+				s.LimitOptions.MaxSessionsPerIP = 1
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			announce := func(nconn net.Conn) base.StatusCode {
+				res, err2 := writeReqReadRes(conn.NewConn(nconn), base.Request{
+					Method: base.Announce,
+					URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+					Header: base.Header{
+						"CSeq":         base.HeaderValue{"1"},
+						"Content-Type": base.HeaderValue{"application/sdp"},
+					},
+					Body: mediasToSDP([]*description.Media{testH264Media}),
+				})
+				require.NoError(t, err2)
+				return res.StatusCode
+			}
+
+			nconn1, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn1.Close()
+			require.Equal(t, base.StatusOK, announce(nconn1))
+
+			nconn2, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn2.Close()
+
+			if ca == "connections" || ca == "connections per ip" {
+				// connections above limits are closed after writing a response
+				conn2 := conn.NewConn(nconn2)
+				var res *base.Response
+				res, err = conn2.ReadResponse()
+				require.NoError(t, err)
+				require.Equal(t, base.StatusServiceUnavailable, res.StatusCode)
+				_, err = conn2.ReadResponse()
+				require.Error(t, err)
+			} else {
+				require.Equal(t, base.StatusServiceUnavailable, announce(nconn2))
+			}
+
+			// limits are released when connections and sessions are closed
+			nconn1.Close()
+
+			require.Eventually(t, func() bool {
+				nconn3, err2 := net.Dial("tcp", "localhost:8554")
+				require.NoError(t, err2)
+				defer nconn3.Close()
+
+				// wait for the response written to rejected connections, if any
+				nconn3.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+				_, err2 = conn.NewConn(nconn3).ReadResponse()
+				if err2 == nil {
+					return false
+				}
+				nconn3.SetReadDeadline(time.Time{})
+
+				return announce(nconn3) == base.StatusOK
+			}, 2*time.Second, 50*time.Millisecond)
+		})
+	}
+}
+
+func TestServerRequestLimits(t *testing.T) {
+	for _, ca := range []string{
+		"requests per second",
+		"bad requests",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusNotFound,
+						}, nil, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			method := base.Options
+			if ca == "requests per second" {
+				s.LimitOptions.MaxRequestsPerSecond = 2
+			} else {
+				s.LimitOptions.MaxBadRequests = 2
+				method = base.Describe
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			for i := 0; i < 3; i++ {
+				var res *base.Response
+				res, err = writeReqReadRes(conn, base.Request{
+					Method: method,
+					URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+					Header: base.Header{
+						"CSeq": base.HeaderValue{strconv.FormatInt(int64(i+1), 10)},
+					},
+				})
+				require.NoError(t, err)
+
+				switch {
+				case ca == "bad requests":
+					require.Equal(t, base.StatusNotFound, res.StatusCode)
+
+				case i < 2:
+					require.Equal(t, base.StatusOK, res.StatusCode)
+
+				default:
+					require.Equal(t, base.StatusServiceUnavailable, res.StatusCode)
+				}
+			}
+
+			// connection is closed
+			_, err = conn.ReadResponse()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestServerConnFilter(t *testing.T) {
+	connOpened := make(chan struct{}, 1)
+	filtered := make(chan net.Addr, 1)
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onConnOpen: func(_ *ServerHandlerOnConnOpenCtx) {
+				connOpened <- struct{}{}
+			},
+		},
+		RTSPAddress: "localhost:8554",
+		OnConnFilter: func(addr net.Addr) bool {
+			filtered <- addr
+			return false
+		},
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+
+	_, err = writeReqReadRes(conn.NewConn(nconn), base.Request{
+		Method: base.Options,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"1"},
+		},
+	})
+	require.Error(t, err)
+
+	require.Equal(t, nconn.LocalAddr().String(), (<-filtered).String())
+
+	select {
+	case <-connOpened:
+		t.Errorf("should not happen")
+	default:
+	}
+}
+
+func TestServerConnClose(t *testing.T) {
+	nconnClosed := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onConnOpen: func(ctx *ServerHandlerOnConnOpenCtx) {
+				ctx.Conn.Close()
+				ctx.Conn.Close()
+			},
+			onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
+				close(nconnClosed)
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	<-nconnClosed
+
+	_, err = writeReqReadRes(conn, base.Request{
+		Method: base.Options,
+		URL:    mustParseURL("rtsp://localhost:8554/"),
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"1"},
+		},
+	})
+	require.Error(t, err)
+}
+
+func TestServerCSeq(t *testing.T) {
+	s := &Server{
+		RTSPAddress: "localhost:8554",
+	}
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Options,
+		URL:    mustParseURL("rtsp://localhost:8554/"),
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"5"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+
+	require.Equal(t, base.HeaderValue{"5"}, res.Header["CSeq"])
+}
+
+func TestServerStrictMode(t *testing.T) {
+	for _, ca := range []string{"report", "fail"} {
+		t.Run(ca, func(t *testing.T) {
+			var violations []*Violation
+			nconnClosed := make(chan struct{})
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onViolation: func(ctx *ServerHandlerOnViolationCtx) {
+						violations = append(violations, ctx.Violation)
+					},
+					onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
+						close(nconnClosed)
+					},
+				},
+				RTSPAddress: "localhost:8554",
+				StrictOptions: StrictOptions{
+					Enabled:         true,
+					FailOnViolation: ca == "fail",
+				},
+			}
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			res, err := writeReqReadRes(conn, base.Request{
+				Method: base.Options,
+				URL:    mustParseURL("rtsp://localhost:8554/"),
+				Header: base.Header{
+					"CSeq": base.HeaderValue{"2"},
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, base.StatusOK, res.StatusCode)
+			require.Empty(t, violations)
+
+			res, err = writeReqReadRes(conn, base.Request{
+				Method: base.Options,
+				URL:    mustParseURL("rtsp://localhost:8554/"),
+				Header: base.Header{
+					"CSeq": base.HeaderValue{"2"},
+				},
+			})
+			require.NoError(t, err)
+
+			require.Len(t, violations, 1)
+			require.Equal(t, ViolationTypeCSeqInvalid, violations[0].Type)
+			require.Equal(t, "12.17", violations[0].Section)
+			require.Equal(t, "CSeq 2 is not greater than previous CSeq 2", violations[0].Description)
+
+			if ca == "fail" {
+				require.Equal(t, base.StatusBadRequest, res.StatusCode)
+				<-nconnClosed
+				return
+			}
+
+			require.Equal(t, base.StatusOK, res.StatusCode)
+
+			_, err = writeReqReadRes(conn, base.Request{
+				Method: base.Play,
+				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+				Header: base.Header{
+					"CSeq": base.HeaderValue{"3"},
+				},
+			})
+			require.NoError(t, err)
+
+			require.Len(t, violations, 2)
+			require.Equal(t, ViolationTypeHeaderMissing, violations[1].Type)
+			require.Equal(t, "12.37", violations[1].Section)
+			require.Equal(t, "Session header is missing from a PLAY request", violations[1].Description)
+		})
+	}
+}
+
+type testLogEntry struct {
+	msg  string
+	args []interface{}
+}
+
+type testLogger struct {
+	mutex   sync.Mutex
+	entries []testLogEntry
+}
+
+func (l *testLogger) log(msg string, args []interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries = append(l.entries, testLogEntry{msg: msg, args: args})
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.log(msg, args) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.log(msg, args) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.log(msg, args) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.log(msg, args) }
+
+func (l *testLogger) last() testLogEntry {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.entries[len(l.entries)-1]
+}
+
+// testServerHandlerNoViolation hides OnViolation, in order to route violations to the logger.
+type testServerHandlerNoViolation struct {
+	h testServerHandler
+}
+
+func (sh *testServerHandlerNoViolation) OnSessionOpen(ctx *ServerHandlerOnSessionOpenCtx) {
+	sh.h.OnSessionOpen(ctx)
+}
+
+func (sh *testServerHandlerNoViolation) OnDescribe(
+	ctx *ServerHandlerOnDescribeCtx,
+) (*base.Response, *ServerStream, error) {
+	return sh.h.OnDescribe(ctx)
+}
+
+func (sh *testServerHandlerNoViolation) OnSetup(
+	ctx *ServerHandlerOnSetupCtx,
+) (*base.Response, *ServerStream, error) {
+	return sh.h.OnSetup(ctx)
+}
+
+func TestServerConnLogIDs(t *testing.T) {
+	var stream *ServerStream
+	var sc *ServerConn
+	var ss *ServerSession
+	logger := &testLogger{}
+
+	s := &Server{
+		Handler: &testServerHandlerNoViolation{
+			testServerHandler{
+				onSessionOpen: func(ctx *ServerHandlerOnSessionOpenCtx) {
+					sc = ctx.Conn
+					ss = ctx.Session
+				},
+				onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+					return &base.Response{
+						StatusCode: base.StatusOK,
+					}, stream, nil
+				},
+				onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+					return &base.Response{
+						StatusCode: base.StatusOK,
+					}, stream, nil
+				},
+			},
+		},
+		RTSPAddress: "localhost:8554",
+		StrictOptions: StrictOptions{
+			Enabled: true,
+		},
+		ObservabilityOptions: ObservabilityOptions{
+			Logger: logger,
+		},
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
 
-func TestServerErrorInvalidUDPPorts(t *testing.T) {
-	t.Run("non consecutive", func(t *testing.T) {
-		s := &Server{
-			UDPRTPAddress:  "127.0.0.1:8006",
-			UDPRTCPAddress: "127.0.0.1:8009",
-			RTSPAddress:    "localhost:8554",
-		}
-		err := s.Start()
-		require.Error(t, err)
-	})
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
 
-	t.Run("non even", func(t *testing.T) {
-		s := &Server{
-			UDPRTPAddress:  "127.0.0.1:8003",
-			UDPRTCPAddress: "127.0.0.1:8004",
-			RTSPAddress:    "localhost:8554",
-		}
-		err := s.Start()
-		require.Error(t, err)
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	var sx headers.Session
+	err = sx.Unmarshal(res.Header["Session"])
+	require.NoError(t, err)
+
+	// repeat the CSeq of the SETUP request to trigger a violation,
+	// that is logged since OnViolation is not implemented.
+	res, err = writeReqReadRes(conn, base.Request{
+		Method: base.Options,
+		URL:    mustParseURL("rtsp://localhost:8554/"),
+		Header: base.Header{
+			"CSeq":    res.Header["CSeq"],
+			"Session": base.HeaderValue{sx.Session},
+		},
 	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+
+	logger.mutex.Lock()
+	require.Len(t, logger.entries, 2)
+	// the first violation is caused by SETUP, before the session is created.
+	require.Equal(t, []interface{}{"conn", sc.ID()}, logger.entries[0].args)
+	logger.mutex.Unlock()
+
+	e := logger.last()
+	require.Equal(t, "RFC 2326 violation (section 12.17): CSeq 1 is not greater than previous CSeq 1", e.msg)
+	require.Equal(t, []interface{}{"conn", sc.ID(), "session", ss.ID()}, e.args)
 }
 
-func TestServerConnClose(t *testing.T) {
-	nconnClosed := make(chan struct{})
+func TestServerRequestDone(t *testing.T) {
+	done := make(chan *ServerHandlerOnRequestDoneCtx, 1)
 
 	s := &Server{
 		Handler: &testServerHandler{
-			onConnOpen: func(ctx *ServerHandlerOnConnOpenCtx) {
-				ctx.Conn.Close()
-				ctx.Conn.Close()
-			},
-			onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
-				close(nconnClosed)
+			onRequestDone: func(ctx *ServerHandlerOnRequestDoneCtx) {
+				done <- ctx
 			},
 		},
 		RTSPAddress: "localhost:8554",
 	}
-
 	err := s.Start()
 	require.NoError(t, err)
 	defer s.Close()
@@ -249,25 +1018,34 @@ func TestServerConnClose(t *testing.T) {
 	defer nconn.Close()
 	conn := conn.NewConn(nconn)
 
-	<-nconnClosed
-
-	_, err = writeReqReadRes(conn, base.Request{
+	res, err := writeReqReadRes(conn, base.Request{
 		Method: base.Options,
 		URL:    mustParseURL("rtsp://localhost:8554/"),
 		Header: base.Header{
-			"CSeq": base.HeaderValue{"1"},
+			"CSeq": base.HeaderValue{"5"},
 		},
 	})
-	require.Error(t, err)
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+
+	ctx := <-done
+	require.Equal(t, base.Options, ctx.Request.Method)
+	require.Equal(t, base.StatusOK, ctx.Response.StatusCode)
+	require.False(t, ctx.Start.IsZero())
+	require.GreaterOrEqual(t, ctx.Duration, time.Duration(0))
+	require.NoError(t, ctx.Error)
 }
 
-func TestServerCSeq(t *testing.T) {
+func TestServerMetrics(t *testing.T) {
+	m := &testMetrics{}
+
 	s := &Server{
-		RTSPAddress: "localhost:8554",
+		Handler:              &testServerHandler{},
+		RTSPAddress:          "localhost:8554",
+		ObservabilityOptions: ObservabilityOptions{Metrics: m},
 	}
 	err := s.Start()
 	require.NoError(t, err)
-	defer s.Close()
 
 	nconn, err := net.Dial("tcp", "localhost:8554")
 	require.NoError(t, err)
@@ -278,13 +1056,114 @@ func TestServerCSeq(t *testing.T) {
 		Method: base.Options,
 		URL:    mustParseURL("rtsp://localhost:8554/"),
 		Header: base.Header{
-			"CSeq": base.HeaderValue{"5"},
+			"CSeq": base.HeaderValue{"1"},
 		},
 	})
 	require.NoError(t, err)
 	require.Equal(t, base.StatusOK, res.StatusCode)
 
-	require.Equal(t, base.HeaderValue{"5"}, res.Header["CSeq"])
+	require.Equal(t, int64(1), atomic.LoadInt64(&m.conns))
+
+	s.Close()
+
+	require.Equal(t, int64(0), atomic.LoadInt64(&m.conns))
+	require.Equal(t, int64(0), atomic.LoadInt64(&m.sessions))
+}
+
+func TestServerStats(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", readSession(t, res))
+
+	err = stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+	require.NoError(t, err)
+
+	f, err := conn.ReadInterleavedFrame()
+	require.NoError(t, err)
+	require.Equal(t, 0, f.Channel)
+
+	st := s.Stats()
+	require.Equal(t, uint64(1), st.ConnsAccepted)
+	require.Equal(t, 1, st.ConnsOpen)
+	require.Equal(t, uint64(0), st.TLSHandshakeFailures)
+	require.Equal(t, map[ServerSessionState]int{ServerSessionStatePlay: 1}, st.Sessions)
+	require.Equal(t, StatsServerTransport{
+		BytesSent:      uint64(len(testRTPPacketMarshaled)),
+		RTPPacketsSent: 1,
+	}, st.Transports[TransportTCP])
+	require.Equal(t, StatsServerTransport{}, st.Transports[TransportUDP])
+}
+
+func TestServerStatsTLSHandshakeFailures(t *testing.T) {
+	cert, err := tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+
+	s := &Server{
+		Handler:     &testServerHandler{},
+		RTSPAddress: "localhost:8554",
+		SecurityOptions: SecurityOptions{
+			TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		},
+	}
+
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+
+	_, err = nconn.Write([]byte("OPTIONS rtsp://localhost:8554/ RTSP/1.0\r\nCSeq: 1\r\n\r\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return s.Stats().TLSHandshakeFailures == 1
+	}, 2*time.Second, 10*time.Millisecond)
 }
 
 func TestServerErrorCSeqMissing(t *testing.T) {
@@ -688,28 +1567,161 @@ func TestServerGetSetParameter(t *testing.T) {
 							StatusCode: base.StatusOK,
 						}, stream, nil
 					},
-					onSetParameter: func(ctx *ServerHandlerOnSetParameterCtx) (*base.Response, error) {
-						if ca == "inside session" {
-							require.NotNil(t, ctx.Session)
-							require.Equal(t, 123, ctx.Session.UserData())
-						} else {
-							ctx.Conn.SetUserData(456)
+					onSetParameter: func(ctx *ServerHandlerOnSetParameterCtx) (*base.Response, error) {
+						if ca == "inside session" {
+							require.NotNil(t, ctx.Session)
+							require.Equal(t, 123, ctx.Session.UserData())
+						} else {
+							ctx.Conn.SetUserData(456)
+						}
+						params = ctx.Request.Body
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onGetParameter: func(ctx *ServerHandlerOnGetParameterCtx) (*base.Response, error) {
+						if ca == "inside session" {
+							require.NotNil(t, ctx.Session)
+						} else {
+							require.Equal(t, 456, ctx.Conn.UserData())
+						}
+						return &base.Response{
+							StatusCode: base.StatusOK,
+							Body:       params,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+			defer stream.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			desc := doDescribe(t, conn)
+
+			var session string
+
+			if ca == "inside session" {
+				inTH := &headers.Transport{
+					Protocol:       headers.TransportProtocolTCP,
+					Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+					Mode:           transportModePtr(headers.TransportModePlay),
+					InterleavedIDs: &[2]int{0, 1},
+				}
+
+				res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+				session = readSession(t, res)
+			}
+
+			headers := base.Header{
+				"CSeq": base.HeaderValue{"3"},
+			}
+			if ca == "inside session" {
+				headers["Session"] = base.HeaderValue{session}
+			}
+
+			res, err := writeReqReadRes(conn, base.Request{
+				Method: base.SetParameter,
+				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+				Header: headers,
+				Body:   []byte("param1: 123456\r\n"),
+			})
+			require.NoError(t, err)
+			require.Equal(t, base.StatusOK, res.StatusCode)
+
+			headers = base.Header{
+				"CSeq": base.HeaderValue{"4"},
+			}
+			if ca == "inside session" {
+				headers["Session"] = base.HeaderValue{session}
+			}
+
+			res, err = writeReqReadRes(conn, base.Request{
+				Method: base.GetParameter,
+				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+				Header: headers,
+				Body:   []byte("param1\r\n"),
+			})
+			require.NoError(t, err)
+			require.Equal(t, base.StatusOK, res.StatusCode)
+			require.Equal(t, []byte("param1: 123456\r\n"), res.Body)
+		})
+	}
+}
+
+type testServerParametersHandler struct {
+	testServerHandler
+	onGetParameters func(*ServerHandlerOnGetParametersCtx) (*base.Response, []Parameter, error)
+	onSetParameters func(*ServerHandlerOnSetParametersCtx) (*base.Response, error)
+}
+
+func (sh *testServerParametersHandler) OnGetParameters(
+	ctx *ServerHandlerOnGetParametersCtx,
+) (*base.Response, []Parameter, error) {
+	return sh.onGetParameters(ctx)
+}
+
+func (sh *testServerParametersHandler) OnSetParameters(ctx *ServerHandlerOnSetParametersCtx) (*base.Response, error) {
+	return sh.onSetParameters(ctx)
+}
+
+func TestServerGetSetParameters(t *testing.T) {
+	for _, ca := range []string{"inside session", "outside session"} {
+		t.Run(ca, func(t *testing.T) {
+			var stream *ServerStream
+			values := make(map[string]string)
+			getCalled := 0
+			var rawBody []byte
+
+			s := &Server{
+				Handler: &testServerParametersHandler{
+					testServerHandler: testServerHandler{
+						onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+							return &base.Response{
+								StatusCode: base.StatusOK,
+							}, stream, nil
+						},
+						onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+							return &base.Response{
+								StatusCode: base.StatusOK,
+							}, stream, nil
+						},
+						onSetParameter: func(ctx *ServerHandlerOnSetParameterCtx) (*base.Response, error) {
+							rawBody = ctx.Request.Body
+							return &base.Response{
+								StatusCode: base.StatusOK,
+							}, nil
+						},
+					},
+					onSetParameters: func(ctx *ServerHandlerOnSetParametersCtx) (*base.Response, error) {
+						require.Equal(t, ca == "inside session", ctx.Session != nil)
+						for _, p := range ctx.Parameters {
+							values[p.Name] = p.Value
 						}
-						params = ctx.Request.Body
 						return &base.Response{
 							StatusCode: base.StatusOK,
 						}, nil
 					},
-					onGetParameter: func(ctx *ServerHandlerOnGetParameterCtx) (*base.Response, error) {
-						if ca == "inside session" {
-							require.NotNil(t, ctx.Session)
-						} else {
-							require.Equal(t, 456, ctx.Conn.UserData())
+					onGetParameters: func(ctx *ServerHandlerOnGetParametersCtx) (*base.Response, []Parameter, error) {
+						getCalled++
+						require.Equal(t, ca == "inside session", ctx.Session != nil)
+						ret := make([]Parameter, len(ctx.Parameters))
+						for i, p := range ctx.Parameters {
+							ret[i] = Parameter{Name: p.Name, Value: values[p.Name]}
 						}
 						return &base.Response{
 							StatusCode: base.StatusOK,
-							Body:       params,
-						}, nil
+						}, ret, nil
 					},
 				},
 				RTSPAddress: "localhost:8554",
@@ -744,42 +1756,47 @@ func TestServerGetSetParameter(t *testing.T) {
 				session = readSession(t, res)
 			}
 
-			headers := base.Header{
-				"CSeq": base.HeaderValue{"3"},
-			}
-			if ca == "inside session" {
-				headers["Session"] = base.HeaderValue{session}
+			cseq := 3
+
+			do := func(method base.Method, body []byte, contentType string) *base.Response {
+				h := base.Header{
+					"CSeq": base.HeaderValue{strconv.FormatInt(int64(cseq), 10)},
+				}
+				if contentType != "" {
+					h["Content-Type"] = base.HeaderValue{contentType}
+				}
+				if ca == "inside session" {
+					h["Session"] = base.HeaderValue{session}
+				}
+				cseq++
+
+				res, err2 := writeReqReadRes(conn, base.Request{
+					Method: method,
+					URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+					Header: h,
+					Body:   body,
+				})
+				require.NoError(t, err2)
+				require.Equal(t, base.StatusOK, res.StatusCode)
+				return res
 			}
 
-			res, err := writeReqReadRes(conn, base.Request{
-				Method: base.SetParameter,
-				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
-				Header: headers,
-				Body:   []byte("param1: 123456\r\n"),
-			})
-			require.NoError(t, err)
-			require.Equal(t, base.StatusOK, res.StatusCode)
+			do(base.SetParameter, []byte("param1: 123456\r\nparam2: abc\r\n"), "text/parameters")
 
-			headers = base.Header{
-				"CSeq": base.HeaderValue{"4"},
-			}
-			if ca == "inside session" {
-				headers["Session"] = base.HeaderValue{session}
-			}
+			do(base.SetParameter, []byte("<param1>7</param1>"), "text/xml")
+			require.Equal(t, []byte("<param1>7</param1>"), rawBody)
 
-			res, err = writeReqReadRes(conn, base.Request{
-				Method: base.GetParameter,
-				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
-				Header: headers,
-				Body:   []byte("param1\r\n"),
-			})
-			require.NoError(t, err)
-			require.Equal(t, base.StatusOK, res.StatusCode)
-			require.Equal(t, []byte("param1: 123456\r\n"), res.Body)
+			res := do(base.GetParameter, []byte("param2\r\nparam1\r\n"), "")
+			require.Equal(t, base.HeaderValue{"text/parameters"}, res.Header["Content-Type"])
+			require.Equal(t, []byte("param2: abc\r\nparam1: 123456\r\n"), res.Body)
+			require.Equal(t, 1, getCalled)
+
+			res = do(base.GetParameter, nil, "")
+			require.Empty(t, res.Body)
+			require.Equal(t, 1, getCalled)
 		})
 	}
 }
-
 func TestServerErrorInvalidSession(t *testing.T) {
 	for _, method := range []base.Method{
 		base.Play,
@@ -901,6 +1918,136 @@ func TestServerSessionClose(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestServerSessionKick(t *testing.T) {
+	var stream *ServerStream
+	sessionClosed := make(chan error)
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onSessionClose: func(ctx *ServerHandlerOnSessionCloseCtx) {
+				sessionClosed <- ctx.Error
+			},
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	require.Empty(t, s.Sessions())
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	sessions := s.Sessions()
+	require.Len(t, sessions, 1)
+	require.Equal(t, ServerSessionStatePrePlay, sessions[0].State())
+	require.Equal(t, TransportTCP, *sessions[0].SetuppedTransport())
+	require.Equal(t, nconn.LocalAddr().String(), sessions[0].RemoteAddr().String())
+	require.Len(t, sessions[0].Stats().Medias, 1)
+
+	sessions[0].Kick("banned")
+	sessions[0].Kick("banned")
+
+	select {
+	case err = <-sessionClosed:
+		require.EqualError(t, err, "session kicked: banned")
+	case <-time.After(2 * time.Second):
+		t.Errorf("should not happen")
+	}
+
+	_, err = writeReqReadRes(conn, base.Request{
+		Method: base.Options,
+		URL:    mustParseURL("rtsp://localhost:8554/"),
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"2"},
+		},
+	})
+	require.Error(t, err)
+
+	require.Empty(t, s.Sessions())
+}
+
+func TestServerIDs(t *testing.T) {
+	var stream *ServerStream
+	var sc *ServerConn
+	var ss *ServerSession
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onSessionOpen: func(ctx *ServerHandlerOnSessionOpenCtx) {
+				sc = ctx.Conn
+				ss = ctx.Session
+			},
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	require.NotEqual(t, uuid.Nil, sc.ID())
+	require.NotEqual(t, uuid.Nil, ss.ID())
+	require.NotEqual(t, sc.ID(), ss.ID())
+}
+
 func TestServerSessionAutoClose(t *testing.T) {
 	for _, ca := range []string{
 		"200", "400",
@@ -1092,3 +2239,145 @@ func TestServerAuth(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, base.StatusOK, res.StatusCode)
 }
+
+func TestServerAuthLookup(t *testing.T) {
+	for _, ca := range []string{"valid", "wrong pass", "unknown user"} {
+		t.Run(ca, func(t *testing.T) {
+			announced := false
+			var lookupPath string
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+						announced = true
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+				OnAuthLookup: func(ctx *ServerAuthLookupCtx) (string, bool) {
+					lookupPath = ctx.Path
+					if ctx.User != "myuser" {
+						return "", false
+					}
+					return "mypass", true
+				},
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			res, err := writeReqReadRes(conn, base.Request{
+				Method: base.Options,
+				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+				Header: base.Header{
+					"CSeq": base.HeaderValue{"1"},
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, base.StatusOK, res.StatusCode)
+
+			req := base.Request{
+				Method: base.Announce,
+				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+				Header: base.Header{
+					"CSeq":         base.HeaderValue{"2"},
+					"Content-Type": base.HeaderValue{"application/sdp"},
+				},
+				Body: mediasToSDP([]*description.Media{testH264Media}),
+			}
+
+			res, err = writeReqReadRes(conn, req)
+			require.NoError(t, err)
+			require.Equal(t, base.StatusUnauthorized, res.StatusCode)
+			require.False(t, announced)
+
+			// basic authentication is not offered by default
+			for _, v := range res.Header["WWW-Authenticate"] {
+				require.False(t, strings.HasPrefix(v, "Basic "))
+			}
+
+			user, pass := "myuser", "mypass"
+			switch ca {
+			case "wrong pass":
+				pass = "otherpass"
+
+			case "unknown user":
+				user = "otheruser"
+			}
+
+			sender, err := auth.NewSender(res.Header["WWW-Authenticate"], user, pass)
+			require.NoError(t, err)
+
+			sender.AddAuthorization(&req)
+			res, err = writeReqReadRes(conn, req)
+			require.NoError(t, err)
+			require.Equal(t, "/teststream", lookupPath)
+
+			if ca == "valid" {
+				require.Equal(t, base.StatusOK, res.StatusCode)
+				require.True(t, announced)
+			} else {
+				require.Equal(t, base.StatusUnauthorized, res.StatusCode)
+				require.False(t, announced)
+			}
+		})
+	}
+}
+
+func TestServerSetLimitsAuth(t *testing.T) {
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusNotFound,
+				}, nil, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	describe := func(cseq string) base.StatusCode {
+		res, err2 := writeReqReadRes(conn, base.Request{
+			Method: base.Describe,
+			URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+			Header: base.Header{
+				"CSeq": base.HeaderValue{cseq},
+			},
+		})
+		require.NoError(t, err2)
+		return res.StatusCode
+	}
+
+	require.Equal(t, base.StatusNotFound, describe("1"))
+
+	err = s.SetLimits(ServerLimits{
+		OnAuthLookup: func(_ *ServerAuthLookupCtx) (string, bool) {
+			return "mypass", true
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, base.StatusUnauthorized, describe("2"))
+
+	err = s.SetLimits(ServerLimits{})
+	require.NoError(t, err)
+
+	require.Equal(t, base.StatusNotFound, describe("3"))
+}
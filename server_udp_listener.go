@@ -6,6 +6,8 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/net/ipv4"
+
 	"github.com/bluenviron/gortsplib/v4/pkg/multicast"
 )
 
@@ -25,18 +27,33 @@ func (p *clientAddr) fill(ip net.IP, port int) {
 	}
 }
 
+type serverUDPListenerClient struct {
+	readFunc      readFunc
+	truncatedFunc func()
+}
+
 func createUDPListenerMulticastPair(
 	listenPacket func(network, address string) (net.PacketConn, error),
 	writeTimeout time.Duration,
 	multicastRTPPort int,
 	multicastRTCPPort int,
 	ip net.IP,
+	gso bool,
+	readBufferSize int,
+	writeBufferSize int,
+	maxPayloadSize int,
+	dscp int,
 ) (*serverUDPListener, *serverUDPListener, error) {
 	rtpl := &serverUDPListener{
 		listenPacket:    listenPacket,
 		writeTimeout:    writeTimeout,
 		multicastEnable: true,
 		address:         net.JoinHostPort(ip.String(), strconv.FormatInt(int64(multicastRTPPort), 10)),
+		gso:             gso,
+		readBufferSize:  readBufferSize,
+		writeBufferSize: writeBufferSize,
+		maxPayloadSize:  maxPayloadSize,
+		dscp:            dscp,
 	}
 	err := rtpl.initialize()
 	if err != nil {
@@ -48,6 +65,10 @@ func createUDPListenerMulticastPair(
 		writeTimeout:    writeTimeout,
 		multicastEnable: true,
 		address:         net.JoinHostPort(ip.String(), strconv.FormatInt(int64(multicastRTCPPort), 10)),
+		readBufferSize:  readBufferSize,
+		writeBufferSize: writeBufferSize,
+		maxPayloadSize:  maxPayloadSize,
+		dscp:            dscp,
 	}
 	err = rtcpl.initialize()
 	if err != nil {
@@ -63,11 +84,22 @@ type serverUDPListener struct {
 	writeTimeout    time.Duration
 	multicastEnable bool
 	address         string
-
-	pc           packetConn
-	listenIP     net.IP
-	clientsMutex sync.RWMutex
-	clients      map[clientAddr]readFunc
+	poolBuffers     bool
+	gso             bool
+	readBufferSize  int
+	writeBufferSize int
+	maxPayloadSize  int
+	dscp            int
+
+	pc                    packetConn
+	batch                 *udpBatchConn
+	gsoBuf                []byte
+	actualReadBufferSize  int
+	actualWriteBufferSize int
+	listenIP              net.IP
+	payloadSize           int
+	clientsMutex          sync.RWMutex
+	clients               map[clientAddr]serverUDPListenerClient
 
 	done chan struct{}
 }
@@ -94,13 +126,24 @@ func (u *serverUDPListener) initialize() error {
 		u.listenIP = tmp.LocalAddr().(*net.UDPAddr).IP
 	}
 
-	err := u.pc.SetReadBuffer(udpKernelReadBufferSize)
+	var err error
+	u.actualReadBufferSize, u.actualWriteBufferSize, err = setBufferSizes(u.pc, u.readBufferSize, u.writeBufferSize)
 	if err != nil {
 		u.pc.Close()
 		return err
 	}
 
-	u.clients = make(map[clientAddr]readFunc)
+	if u.dscp != 0 {
+		err = setDSCP(u.pc, u.dscp)
+		if err != nil {
+			u.pc.Close()
+			return err
+		}
+	}
+
+	u.payloadSize = min(udpMaxPayloadSize, u.maxPayloadSize)
+	u.batch = newUDPBatchConn(u.pc)
+	u.clients = make(map[clientAddr]serverUDPListenerClient)
 	u.done = make(chan struct{})
 
 	go u.run()
@@ -124,39 +167,99 @@ func (u *serverUDPListener) port() int {
 func (u *serverUDPListener) run() {
 	defer close(u.done)
 
-	var buf []byte
+	if u.batch != nil {
+		u.runBatch()
+	} else {
+		u.runSingle()
+	}
+}
+
+// newBuffer allocates a buffer that is one byte bigger than the payload size,
+// in order to detect truncated packets.
+func (u *serverUDPListener) newBuffer() []byte {
+	if u.poolBuffers {
+		return getPacketBuffer(u.payloadSize + 1)
+	}
+	return make([]byte, u.payloadSize+1)
+}
 
-	createNewBuffer := func() {
-		buf = make([]byte, udpMaxPayloadSize+1)
+// nextBuffer returns the buffer to use for the next read.
+func (u *serverUDPListener) nextBuffer(buf []byte, retained bool) []byte {
+	if retained {
+		return u.newBuffer()
 	}
 
-	createNewBuffer()
+	// the payload size has been enlarged
+	if len(buf) != (u.payloadSize + 1) {
+		if u.poolBuffers {
+			putPacketBuffer(buf)
+		}
+		return u.newBuffer()
+	}
+
+	return buf
+}
+
+func (u *serverUDPListener) runSingle() {
+	buf := u.newBuffer()
 
 	for {
-		n, addr2, err := u.pc.ReadFrom(buf)
+		n, addr, err := u.pc.ReadFrom(buf)
 		if err != nil {
-			break
+			return
 		}
-		addr := addr2.(*net.UDPAddr)
 
-		func() {
-			u.clientsMutex.RLock()
-			defer u.clientsMutex.RUnlock()
+		// the packet filled the whole buffer, therefore it may have been truncated.
+		retained := u.processPacket(buf[:n], n == len(buf), addr.(*net.UDPAddr))
+		buf = u.nextBuffer(buf, retained)
+	}
+}
 
-			var ca clientAddr
-			ca.fill(addr.IP, addr.Port)
-			cb, ok := u.clients[ca]
-			if !ok {
-				return
-			}
+func (u *serverUDPListener) runBatch() {
+	ms := make([]ipv4.Message, udpReadBatchSize)
+	for i := range ms {
+		ms[i].Buffers = [][]byte{u.newBuffer()}
+	}
 
-			if cb(buf[:n]) {
-				createNewBuffer()
-			}
-		}()
+	for {
+		n, err := u.batch.read(ms)
+		if err != nil {
+			return
+		}
+
+		for i := 0; i < n; i++ {
+			buf := ms[i].Buffers[0]
+
+			retained := u.processPacket(buf[:ms[i].N], ms[i].N == len(buf), ms[i].Addr.(*net.UDPAddr))
+			ms[i].Buffers[0] = u.nextBuffer(buf, retained)
+		}
 	}
 }
 
+// processPacket processes a packet and returns whether its buffer has been retained.
+func (u *serverUDPListener) processPacket(buf []byte, truncated bool, addr *net.UDPAddr) bool {
+	u.clientsMutex.RLock()
+	defer u.clientsMutex.RUnlock()
+
+	var ca clientAddr
+	ca.fill(addr.IP, addr.Port)
+	cl, ok := u.clients[ca]
+	if !ok {
+		return false
+	}
+
+	// discard truncated packets and enlarge buffers, in order to read next packets entirely.
+	if truncated {
+		if len(buf) > u.payloadSize {
+			u.payloadSize = min(u.payloadSize*2, u.maxPayloadSize)
+		}
+		cl.truncatedFunc()
+		return false
+	}
+
+	return cl.readFunc(buf)
+}
+
 func (u *serverUDPListener) write(buf []byte, addr *net.UDPAddr) error {
 	// no mutex is needed here since Write() has an internal lock.
 	// https://github.com/golang/go/issues/27203#issuecomment-534386117
@@ -165,14 +268,70 @@ func (u *serverUDPListener) write(buf []byte, addr *net.UDPAddr) error {
 	return err
 }
 
-func (u *serverUDPListener) addClient(ip net.IP, port int, cb readFunc) {
+// writeMultiple writes multiple packets to the same address,
+// with a single syscall when possible.
+func (u *serverUDPListener) writeMultiple(byts [][]byte, addr *net.UDPAddr) error {
+	if u.gso && len(byts) > 1 {
+		if gc, ok := u.pc.(gsoConn); ok {
+			return u.writeGSO(gc, byts, addr)
+		}
+	}
+
+	if len(byts) == 1 || !u.batch.canWriteTo(addr) {
+		for _, buf := range byts {
+			err := u.write(buf, addr)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	u.pc.SetWriteDeadline(time.Now().Add(u.writeTimeout))
+	return u.batch.write(byts, addr)
+}
+
+// writeGSO writes multiple packets with UDP Generic Segmentation Offload.
+// It is used by a single routine only, the one of the multicast writer.
+func (u *serverUDPListener) writeGSO(gc gsoConn, byts [][]byte, addr *net.UDPAddr) error {
+	u.pc.SetWriteDeadline(time.Now().Add(u.writeTimeout))
+
+	for _, batch := range gsoBatches(byts) {
+		if len(batch) == 1 {
+			_, err := u.pc.WriteTo(batch[0], addr)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		u.gsoBuf = u.gsoBuf[:0]
+		for _, buf := range batch {
+			u.gsoBuf = append(u.gsoBuf, buf...)
+		}
+
+		// devices that don't support GSO are detected by the connection,
+		// that writes packets one by one on them.
+		_, err := gc.WriteGSO(u.gsoBuf, len(batch[0]), addr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *serverUDPListener) addClient(ip net.IP, port int, cb readFunc, truncatedCb func()) {
 	var addr clientAddr
 	addr.fill(ip, port)
 
 	u.clientsMutex.Lock()
 	defer u.clientsMutex.Unlock()
 
-	u.clients[addr] = cb
+	u.clients[addr] = serverUDPListenerClient{
+		readFunc:      cb,
+		truncatedFunc: truncatedCb,
+	}
 }
 
 func (u *serverUDPListener) removeClient(ip net.IP, port int) {
@@ -0,0 +1,71 @@
+package gortsplib
+
+import (
+	"net"
+	"time"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// tosConn is implemented by connections that can set the type-of-service field
+// of outgoing packets by themselves (i.e. multicast connections).
+type tosConn interface {
+	SetTOS(int) error
+}
+
+// setDSCP sets the DSCP of outgoing packets of a UDP socket.
+func setDSCP(pc packetConn, dscp int) error {
+	tos := dscp << 2
+
+	switch tpc := pc.(type) {
+	case tosConn:
+		return tpc.SetTOS(tos)
+
+	case *net.UDPConn:
+		if tpc.LocalAddr().(*net.UDPAddr).IP.To4() != nil {
+			return ipv4.NewConn(tpc).SetTOS(tos)
+		}
+
+		err := ipv6.NewConn(tpc).SetTrafficClass(tos)
+		if err != nil {
+			return err
+		}
+
+		// dual-stack sockets use the IPv4 field with IPv4 destinations.
+		// This is not supported by all platforms, ignore errors.
+		ipv4.NewConn(tpc).SetTOS(tos) //nolint:errcheck
+	}
+
+	return nil
+}
+
+// setTCPOptions sets options of a TCP connection.
+// Connections that are not TCP (i.e. returned by custom dialers or listeners) are left untouched.
+func setTCPOptions(nconn net.Conn, disableNoDelay bool, keepAlivePeriod time.Duration) error {
+	tc, ok := nconn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+
+	if disableNoDelay {
+		err := tc.SetNoDelay(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case keepAlivePeriod < 0:
+		return tc.SetKeepAlive(false)
+
+	case keepAlivePeriod > 0:
+		err := tc.SetKeepAlive(true)
+		if err != nil {
+			return err
+		}
+		return tc.SetKeepAlivePeriod(keepAlivePeriod)
+	}
+
+	return nil
+}
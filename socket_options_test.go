@@ -0,0 +1,54 @@
+package gortsplib
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
+)
+
+func TestSetDSCP(t *testing.T) {
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	err = setDSCP(pc.(*net.UDPConn), 46)
+	require.NoError(t, err)
+
+	tos, err := ipv4.NewConn(pc.(*net.UDPConn)).TOS()
+	require.NoError(t, err)
+	require.Equal(t, 46<<2, tos)
+}
+
+func TestSetTCPOptions(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	go func() {
+		nconn, err2 := l.Accept()
+		if err2 == nil {
+			nconn.Close()
+		}
+	}()
+
+	nconn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer nconn.Close()
+
+	err = setTCPOptions(nconn, true, 30*time.Second)
+	require.NoError(t, err)
+
+	err = setTCPOptions(nconn, false, -1)
+	require.NoError(t, err)
+
+	// non-TCP connections are left untouched
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	err = setTCPOptions(c1, true, 30*time.Second)
+	require.NoError(t, err)
+}
@@ -0,0 +1,55 @@
+package gortsplib
+
+import (
+	"sync/atomic"
+
+	"github.com/pion/rtcp"
+
+	"github.com/bluenviron/gortsplib/v4/internal/rtcpreceiver"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+)
+
+// sourceDescription stores items of RTCP SDES packets sent by a remote source.
+type sourceDescription struct {
+	cname atomic.Pointer[string]
+	name  atomic.Pointer[string]
+}
+
+func (d *sourceDescription) process(chunk *rtcp.SourceDescriptionChunk) {
+	for _, item := range chunk.Items {
+		text := item.Text
+
+		switch item.Type {
+		case rtcp.SDESCNAME:
+			d.cname.Store(&text)
+
+		case rtcp.SDESName:
+			d.name.Store(&text)
+		}
+	}
+}
+
+func (d *sourceDescription) load() (string, string) {
+	var cname, name string
+
+	if v := d.cname.Load(); v != nil {
+		cname = *v
+	}
+	if v := d.name.Load(); v != nil {
+		name = *v
+	}
+
+	return cname, name
+}
+
+// stats returns the CNAME and NAME of the remote source.
+// When SDES packets have not been received yet, the CNAME is read from the stream description.
+func (d *sourceDescription) stats(medi *description.Media, recvStats *rtcpreceiver.Stats) (string, string) {
+	cname, name := d.load()
+
+	if cname == "" && recvStats != nil {
+		cname, _ = medi.CNAME(recvStats.RemoteSSRC)
+	}
+
+	return cname, name
+}
@@ -15,12 +15,31 @@ type StatsSessionFormat struct {
 	RTPPacketsSent uint64
 	// number of lost RTP packets
 	RTPPacketsLost uint64
+	// number of RTP packets that could not be processed
+	RTPPacketsInError uint64
+	// number of RTP packets that have been decoded successfully
+	// by the decoder of Client.OnFrame() (client only).
+	RTPPacketsDecoded uint64
+	// number of RTP packets that could not be decoded
+	// by the decoder of Client.OnFrame() (client only).
+	RTPPacketsDecodeErrors uint64
+	// number of received access units,
+	// when corruption detection is enabled (H264 and H265 only).
+	AccessUnitsReceived uint64
+	// number of received access units with missing packets or incomplete fragmented NALUs,
+	// when corruption detection is enabled (H264 and H265 only).
+	AccessUnitsCorrupted uint64
 	// mean jitter of received RTP packets
 	RTPPacketsJitter float64
 	// local SSRC
 	LocalSSRC uint32
 	// remote SSRC
 	RemoteSSRC uint32
+	// canonical name of the remote source, read from RTCP SDES packets
+	// or from ssrc attributes of the stream description.
+	RemoteCNAME string
+	// user name of the remote source, read from RTCP SDES packets.
+	RemoteName string
 	// last sequence number of incoming/outgoing RTP packets
 	RTPPacketsLastSequenceNumber uint16
 	// last RTP time of incoming/outgoing RTP packets
@@ -30,6 +49,7 @@ type StatsSessionFormat struct {
 }
 
 // StatsSessionMedia are session media statistics.
+// Rates are computed in a rolling window of 5 seconds.
 type StatsSessionMedia struct {
 	// received bytes
 	BytesReceived uint64
@@ -43,12 +63,38 @@ type StatsSessionMedia struct {
 	RTCPPacketsSent uint64
 	// number of RTCP packets that could not be processed
 	RTCPPacketsInError uint64
+	// whether RTCP packets have been received on the RTP port,
+	// as done by servers that use a single port for both RTP and RTCP (client only).
+	RTCPOnRTPPort bool
+	// number of UDP packets that have been discarded since they were bigger than read buffers.
+	// Read buffers are enlarged after each truncation, up to SocketOptions.UDPMaxPayloadSize.
+	UDPPacketsTruncated uint64
+	// bitrate of received RTP packets, in bits per second.
+	RTPBitrateReceived float64
+	// bitrate of sent RTP packets, in bits per second.
+	RTPBitrateSent float64
+	// number of received RTP packets per second.
+	RTPPacketRateReceived float64
+	// number of sent RTP packets per second.
+	RTPPacketRateSent float64
+	// number of received frames per second (video medias only).
+	// Frame boundaries are detected with the RTP marker bit.
+	FrameRateReceived float64
+	// number of sent frames per second (video medias only).
+	FrameRateSent float64
 
 	// format statistics
 	Formats map[format.Format]StatsSessionFormat
 }
 
 // StatsSession are session statistics.
+//
+// Statistics are updated atomically by packet paths and are read without locking.
+// Each counter is always valid, but counters are read one after the other,
+// therefore two counters may refer to slightly different instants
+// (for instance, BytesReceived may include a packet that is not yet counted in RTPPacketsReceived).
+// Fields of a single StatsSessionFormat that come from RTCP receivers and senders
+// (SSRCs, last sequence number, last RTP and NTP timestamps, jitter) are always consistent with each other.
 type StatsSession struct {
 	// received bytes
 	BytesReceived uint64
@@ -0,0 +1,252 @@
+package gortsplib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+)
+
+// ViolationType is the type of a Violation.
+type ViolationType int
+
+// violation types.
+const (
+	ViolationTypeHeaderMissing ViolationType = iota
+	ViolationTypeCSeqInvalid
+	ViolationTypeSessionMismatch
+	ViolationTypeMethodInvalid
+	ViolationTypeStateInvalid
+)
+
+var violationTypeLabels = map[ViolationType]string{
+	ViolationTypeHeaderMissing:   "header_missing",
+	ViolationTypeCSeqInvalid:     "cseq_invalid",
+	ViolationTypeSessionMismatch: "session_mismatch",
+	ViolationTypeMethodInvalid:   "method_invalid",
+	ViolationTypeStateInvalid:    "state_invalid",
+}
+
+// String implements fmt.Stringer.
+func (t ViolationType) String() string {
+	if l, ok := violationTypeLabels[t]; ok {
+		return l
+	}
+	return "unknown"
+}
+
+// Violation is a violation of RFC 2326 committed by the peer,
+// detected when StrictOptions.Enabled is true.
+type Violation struct {
+	Type ViolationType
+	// section of RFC 2326 that has been violated.
+	Section string
+	// description of the violation.
+	Description string
+	// request that contains the violation, or that caused the response that contains it.
+	Request *base.Request
+	// response that contains the violation, or nil if the violation is in the request.
+	Response *base.Response
+}
+
+// String implements fmt.Stringer.
+func (v Violation) String() string {
+	return fmt.Sprintf("RFC 2326 violation (section %s): %s", v.Section, v.Description)
+}
+
+// strictCSeqChecker checks that CSeq values sent by the peer are present and monotonic.
+type strictCSeqChecker struct {
+	last    uint64
+	hasLast bool
+}
+
+func (c *strictCSeqChecker) check(req *base.Request) *Violation {
+	v, ok := req.Header["CSeq"]
+	if !ok || len(v) != 1 {
+		return &Violation{
+			Type:        ViolationTypeHeaderMissing,
+			Section:     "12.17",
+			Description: "CSeq header is missing",
+			Request:     req,
+		}
+	}
+
+	cseq, err := strconv.ParseUint(strings.TrimSpace(v[0]), 10, 64)
+	if err != nil {
+		return &Violation{
+			Type:        ViolationTypeCSeqInvalid,
+			Section:     "12.17",
+			Description: fmt.Sprintf("CSeq '%s' is not a number", v[0]),
+			Request:     req,
+		}
+	}
+
+	if c.hasLast && cseq <= c.last {
+		return &Violation{
+			Type:        ViolationTypeCSeqInvalid,
+			Section:     "12.17",
+			Description: fmt.Sprintf("CSeq %d is not greater than previous CSeq %d", cseq, c.last),
+			Request:     req,
+		}
+	}
+
+	c.last = cseq
+	c.hasLast = true
+	return nil
+}
+
+func strictCheckContentType(req *base.Request, res *base.Response) *Violation {
+	var h base.Header
+	var body []byte
+
+	if res != nil {
+		h, body = res.Header, res.Body
+	} else {
+		h, body = req.Header, req.Body
+	}
+
+	if len(body) != 0 {
+		if _, ok := h["Content-Type"]; !ok {
+			return &Violation{
+				Type:        ViolationTypeHeaderMissing,
+				Section:     "12.16",
+				Description: "Content-Type header is missing from a message with a body",
+				Request:     req,
+				Response:    res,
+			}
+		}
+	}
+
+	return nil
+}
+
+// strictCheckRequest validates a request received by a server.
+func strictCheckRequest(cseqChecker *strictCSeqChecker, req *base.Request) []*Violation {
+	var ret []*Violation
+
+	if v := cseqChecker.check(req); v != nil {
+		ret = append(ret, v)
+	}
+
+	switch req.Method {
+	case base.Setup:
+		if _, ok := req.Header["Transport"]; !ok {
+			ret = append(ret, &Violation{
+				Type:        ViolationTypeHeaderMissing,
+				Section:     "12.39",
+				Description: "Transport header is missing from a SETUP request",
+				Request:     req,
+			})
+		}
+
+	case base.Play, base.Pause, base.Record, base.Teardown:
+		if _, ok := req.Header["Session"]; !ok {
+			ret = append(ret, &Violation{
+				Type:        ViolationTypeHeaderMissing,
+				Section:     "12.37",
+				Description: fmt.Sprintf("Session header is missing from a %s request", req.Method),
+				Request:     req,
+			})
+		}
+
+	case base.Redirect:
+		ret = append(ret, &Violation{
+			Type:        ViolationTypeMethodInvalid,
+			Section:     "10",
+			Description: "REDIRECT requests can't be sent by clients",
+			Request:     req,
+		})
+	}
+
+	if v := strictCheckContentType(req, nil); v != nil {
+		ret = append(ret, v)
+	}
+
+	return ret
+}
+
+// strictCheckServerRequest validates a request received by a client.
+func strictCheckServerRequest(cseqChecker *strictCSeqChecker, req *base.Request) []*Violation {
+	var ret []*Violation
+
+	if v := cseqChecker.check(req); v != nil {
+		ret = append(ret, v)
+	}
+
+	switch req.Method {
+	case base.Announce, base.GetParameter, base.SetParameter, base.Options, base.Redirect:
+
+	default:
+		ret = append(ret, &Violation{
+			Type:        ViolationTypeMethodInvalid,
+			Section:     "10",
+			Description: fmt.Sprintf("%s requests can't be sent by servers", req.Method),
+			Request:     req,
+		})
+	}
+
+	if v := strictCheckContentType(req, nil); v != nil {
+		ret = append(ret, v)
+	}
+
+	return ret
+}
+
+// strictCheckResponse validates a response received by a client.
+func strictCheckResponse(session string, req *base.Request, res *base.Response) []*Violation {
+	var ret []*Violation
+
+	if _, ok := res.Header["CSeq"]; !ok {
+		ret = append(ret, &Violation{
+			Type:        ViolationTypeHeaderMissing,
+			Section:     "12.17",
+			Description: "CSeq header is missing",
+			Request:     req,
+			Response:    res,
+		})
+	}
+
+	if req.Method == base.Setup && res.StatusCode == base.StatusOK {
+		if _, ok := res.Header["Session"]; !ok {
+			ret = append(ret, &Violation{
+				Type:        ViolationTypeHeaderMissing,
+				Section:     "12.37",
+				Description: "Session header is missing from a SETUP response",
+				Request:     req,
+				Response:    res,
+			})
+		}
+
+		if _, ok := res.Header["Transport"]; !ok {
+			ret = append(ret, &Violation{
+				Type:        ViolationTypeHeaderMissing,
+				Section:     "12.39",
+				Description: "Transport header is missing from a SETUP response",
+				Request:     req,
+				Response:    res,
+			})
+		}
+	}
+
+	if session != "" {
+		if v, ok := res.Header["Session"]; ok && len(v) == 1 {
+			id, _, _ := strings.Cut(v[0], ";")
+			if id = strings.TrimSpace(id); id != session {
+				ret = append(ret, &Violation{
+					Type:        ViolationTypeSessionMismatch,
+					Section:     "12.37",
+					Description: fmt.Sprintf("session ID changed from '%s' to '%s'", session, id),
+					Request:     req,
+					Response:    res,
+				})
+			}
+		}
+	}
+
+	if v := strictCheckContentType(req, res); v != nil {
+		ret = append(ret, v)
+	}
+
+	return ret
+}
@@ -0,0 +1,80 @@
+package gortsplib
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// maximum number of UDP packets read with a single syscall.
+const udpReadBatchSize = 16
+
+// udpBatchConn allows to read and write multiple UDP packets with a single syscall
+// (recvmmsg / sendmmsg) on platforms that support it.
+// On other platforms, packets are read and written one by one.
+type udpBatchConn struct {
+	bc   batchConn
+	ipv6 bool
+}
+
+type batchConn interface {
+	ReadBatch(ms []ipv4.Message, flags int) (int, error)
+	WriteBatch(ms []ipv4.Message, flags int) (int, error)
+}
+
+// newUDPBatchConn returns a udpBatchConn, or nil when the connection
+// doesn't support batches.
+func newUDPBatchConn(pc net.PacketConn) *udpBatchConn {
+	// multicast connections
+	if bc, ok := pc.(batchConn); ok {
+		return &udpBatchConn{
+			bc: bc,
+		}
+	}
+
+	uc, ok := pc.(*net.UDPConn)
+	if !ok {
+		return nil
+	}
+
+	if uc.LocalAddr().(*net.UDPAddr).IP.To4() != nil {
+		return &udpBatchConn{
+			bc: ipv4.NewPacketConn(uc),
+		}
+	}
+
+	return &udpBatchConn{
+		bc:   ipv6.NewPacketConn(uc),
+		ipv6: true,
+	}
+}
+
+// canWriteTo checks whether packets can be written in batches to the given address.
+// IPv6 sockets can't write in batches to IPv4 addresses, since
+// addresses are not converted into IPv4-mapped ones.
+func (c *udpBatchConn) canWriteTo(addr *net.UDPAddr) bool {
+	return c != nil && (!c.ipv6 || addr.IP.To4() == nil)
+}
+
+func (c *udpBatchConn) read(ms []ipv4.Message) (int, error) {
+	return c.bc.ReadBatch(ms, 0)
+}
+
+func (c *udpBatchConn) write(byts [][]byte, addr *net.UDPAddr) error {
+	ms := make([]ipv4.Message, len(byts))
+	for i, b := range byts {
+		ms[i].Buffers = [][]byte{b}
+		ms[i].Addr = addr
+	}
+
+	for len(ms) != 0 {
+		n, err := c.bc.WriteBatch(ms, 0)
+		if err != nil {
+			return err
+		}
+		ms = ms[n:]
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package gortsplib
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
+)
+
+func TestUDPBatchConn(t *testing.T) {
+	pc1, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc1.Close()
+
+	pc2, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc2.Close()
+
+	bc1 := newUDPBatchConn(pc1)
+	require.NotNil(t, bc1)
+
+	bc2 := newUDPBatchConn(pc2)
+	require.NotNil(t, bc2)
+
+	addr := pc2.LocalAddr().(*net.UDPAddr)
+	require.True(t, bc1.canWriteTo(addr))
+
+	err = bc1.write([][]byte{{1, 2}, {3, 4, 5}, {6}}, addr)
+	require.NoError(t, err)
+
+	ms := make([]ipv4.Message, udpReadBatchSize)
+	for i := range ms {
+		ms[i].Buffers = [][]byte{make([]byte, 10)}
+	}
+
+	var received [][]byte
+
+	for len(received) != 3 {
+		var n int
+		n, err = bc2.read(ms)
+		require.NoError(t, err)
+
+		for i := 0; i < n; i++ {
+			require.Equal(t, pc1.LocalAddr().(*net.UDPAddr).Port, ms[i].Addr.(*net.UDPAddr).Port)
+			received = append(received, append([]byte(nil), ms[i].Buffers[0][:ms[i].N]...))
+		}
+	}
+
+	require.Equal(t, [][]byte{{1, 2}, {3, 4, 5}, {6}}, received)
+}
+
+func TestUDPBatchConnCanWriteTo(t *testing.T) {
+	var nb *udpBatchConn
+	require.False(t, nb.canWriteTo(&net.UDPAddr{IP: net.ParseIP("127.0.0.1")}))
+
+	bc := &udpBatchConn{ipv6: true}
+	require.False(t, bc.canWriteTo(&net.UDPAddr{IP: net.ParseIP("127.0.0.1")}))
+	require.True(t, bc.canWriteTo(&net.UDPAddr{IP: net.ParseIP("::1")}))
+}
@@ -0,0 +1,52 @@
+package gortsplib
+
+import (
+	"syscall"
+
+	"github.com/bluenviron/gortsplib/v4/internal/sockbuf"
+)
+
+// writeBufferConn is implemented by connections whose write buffer can be set.
+type writeBufferConn interface {
+	SetWriteBuffer(int) error
+}
+
+// bufferSizeConn is implemented by connections that can report
+// the size of their kernel buffers (i.e. multicast connections).
+type bufferSizeConn interface {
+	ReadBufferSize() (int, error)
+	WriteBufferSize() (int, error)
+}
+
+// setBufferSizes sets the size of kernel buffers of a UDP socket
+// and returns the sizes actually obtained.
+func setBufferSizes(pc packetConn, readBufferSize int, writeBufferSize int) (int, int, error) {
+	err := pc.SetReadBuffer(readBufferSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if writeBufferSize != 0 {
+		if wc, ok := pc.(writeBufferConn); ok {
+			err = wc.SetWriteBuffer(writeBufferSize)
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	// sizes are informative only, do not return errors
+	switch tpc := pc.(type) {
+	case bufferSizeConn:
+		r, _ := tpc.ReadBufferSize()
+		w, _ := tpc.WriteBufferSize()
+		return r, w, nil
+
+	case syscall.Conn:
+		r, _ := sockbuf.ReadBufferSize(tpc)
+		w, _ := sockbuf.WriteBufferSize(tpc)
+		return r, w, nil
+	}
+
+	return 0, 0, nil
+}
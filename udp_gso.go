@@ -0,0 +1,49 @@
+package gortsplib
+
+import (
+	"net"
+)
+
+const (
+	// maximum number of segments of a single GSO write (UDP_MAX_SEGMENTS).
+	gsoMaxSegments = 64
+
+	// maximum size of a single GSO write.
+	gsoMaxSize = 65507
+)
+
+type gsoConn interface {
+	WriteGSO(b []byte, segmentSize int, addr net.Addr) (int, error)
+}
+
+// gsoBatches splits packets into batches that can be written with a single GSO write.
+// All packets of a batch have the same size, except the last one, that can be smaller.
+func gsoBatches(byts [][]byte) [][][]byte {
+	var ret [][][]byte
+	start := 0
+
+	for start < len(byts) {
+		segmentSize := len(byts[start])
+		size := segmentSize
+		end := start + 1
+
+		for end < len(byts) && (end-start) < gsoMaxSegments {
+			l := len(byts[end])
+			if l > segmentSize || (size+l) > gsoMaxSize {
+				break
+			}
+
+			size += l
+			end++
+
+			if l < segmentSize {
+				break
+			}
+		}
+
+		ret = append(ret, byts[start:end])
+		start = end
+	}
+
+	return ret
+}
@@ -0,0 +1,77 @@
+package gortsplib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func repeatSize(size int, count int) []int {
+	ret := make([]int, count)
+	for i := range ret {
+		ret[i] = size
+	}
+	return ret
+}
+
+func TestGSOBatches(t *testing.T) {
+	for _, ca := range []struct {
+		name  string
+		sizes []int
+		out   [][]int
+	}{
+		{
+			"single",
+			[]int{100},
+			[][]int{{100}},
+		},
+		{
+			"same size",
+			[]int{100, 100, 100},
+			[][]int{{100, 100, 100}},
+		},
+		{
+			"smaller last",
+			[]int{100, 100, 50},
+			[][]int{{100, 100, 50}},
+		},
+		{
+			"smaller in the middle",
+			[]int{100, 50, 100, 100},
+			[][]int{{100, 50}, {100, 100}},
+		},
+		{
+			"bigger",
+			[]int{50, 100, 100},
+			[][]int{{50}, {100, 100}},
+		},
+		{
+			"max segments",
+			repeatSize(10, gsoMaxSegments+1),
+			[][]int{repeatSize(10, gsoMaxSegments), {10}},
+		},
+		{
+			"max size",
+			repeatSize(1400, 50),
+			[][]int{repeatSize(1400, 46), repeatSize(1400, 4)},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			byts := make([][]byte, len(ca.sizes))
+			for i, s := range ca.sizes {
+				byts[i] = make([]byte, s)
+			}
+
+			batches := gsoBatches(byts)
+
+			sizes := make([][]int, len(batches))
+			for i, batch := range batches {
+				for _, b := range batch {
+					sizes[i] = append(sizes[i], len(b))
+				}
+			}
+
+			require.Equal(t, ca.out, sizes)
+		})
+	}
+}